@@ -28,6 +28,9 @@ const (
 
 	defaultMetricNameLogs = "log.record.count"
 	defaultMetricDescLogs = "The number of log records observed."
+
+	defaultMetricNameProfiles = "profile.sample.count"
+	defaultMetricDescProfiles = "The number of profile samples observed."
 )
 
 // Config for the connector
@@ -37,6 +40,7 @@ type Config struct {
 	Metrics    map[string]MetricInfo `mapstructure:"metrics"`
 	DataPoints map[string]MetricInfo `mapstructure:"datapoints"`
 	Logs       map[string]MetricInfo `mapstructure:"logs"`
+	Profiles   map[string]MetricInfo `mapstructure:"profiles"`
 }
 
 // MetricInfo for a data type
@@ -108,6 +112,17 @@ func (c *Config) Validate() error {
 			return fmt.Errorf("logs attributes: metric %q: %w", name, err)
 		}
 	}
+	for name, info := range c.Profiles {
+		if name == "" {
+			return fmt.Errorf("profiles: metric name missing")
+		}
+		if _, err := filterottl.NewBoolExprForProfile(info.Conditions, filterottl.StandardProfileFuncs(), ottl.PropagateError, component.TelemetrySettings{Logger: zap.NewNop()}); err != nil {
+			return fmt.Errorf("profiles condition: metric %q: %w", name, err)
+		}
+		if err := info.validateAttributes(); err != nil {
+			return fmt.Errorf("profiles attributes: metric %q: %w", name, err)
+		}
+	}
 	return nil
 }
 
@@ -148,6 +163,9 @@ func (c *Config) Unmarshal(componentParser *confmap.Conf) error {
 	if !componentParser.IsSet("logs") {
 		c.Logs = defaultLogsConfig()
 	}
+	if !componentParser.IsSet("profiles") {
+		c.Profiles = defaultProfilesConfig()
+	}
 	return nil
 }
 
@@ -190,3 +208,11 @@ func defaultLogsConfig() map[string]MetricInfo {
 		},
 	}
 }
+
+func defaultProfilesConfig() map[string]MetricInfo {
+	return map[string]MetricInfo{
+		defaultMetricNameProfiles: {
+			Description: defaultMetricDescProfiles,
+		},
+	}
+}
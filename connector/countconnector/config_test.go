@@ -49,6 +49,11 @@ func TestLoadConfig(t *testing.T) {
 						Description: defaultMetricDescLogs,
 					},
 				},
+				Profiles: map[string]MetricInfo{
+					defaultMetricNameProfiles: {
+						Description: defaultMetricDescProfiles,
+					},
+				},
 			},
 		},
 		{
@@ -79,6 +84,11 @@ func TestLoadConfig(t *testing.T) {
 						Description: "My description for default log count metric.",
 					},
 				},
+				Profiles: map[string]MetricInfo{
+					defaultMetricNameProfiles: {
+						Description: "My description for default profile sample count metric.",
+					},
+				},
 			},
 		},
 		{
@@ -109,6 +119,11 @@ func TestLoadConfig(t *testing.T) {
 						Description: "My log record count.",
 					},
 				},
+				Profiles: map[string]MetricInfo{
+					"my.profile.sample.count": {
+						Description: "My profile sample count.",
+					},
+				},
 			},
 		},
 		{
@@ -144,6 +159,12 @@ func TestLoadConfig(t *testing.T) {
 						Conditions:  []string{`IsMatch(resource.attributes["host.name"], "pod-l")`},
 					},
 				},
+				Profiles: map[string]MetricInfo{
+					"my.profile.sample.count": {
+						Description: "My profile sample count.",
+						Conditions:  []string{`IsMatch(resource.attributes["host.name"], "pod-p")`},
+					},
+				},
 			},
 		},
 		{
@@ -194,6 +215,15 @@ func TestLoadConfig(t *testing.T) {
 						},
 					},
 				},
+				Profiles: map[string]MetricInfo{
+					"my.profile.sample.count": {
+						Description: "My profile sample count.",
+						Conditions: []string{
+							`IsMatch(resource.attributes["host.name"], "pod-p")`,
+							`IsMatch(resource.attributes["foo"], "bar-p")`,
+						},
+					},
+				},
 			},
 		},
 		{
@@ -236,6 +266,14 @@ func TestLoadConfig(t *testing.T) {
 						},
 					},
 				},
+				Profiles: map[string]MetricInfo{
+					"my.profile.sample.count": {
+						Description: "My profile sample count by environment.",
+						Attributes: []AttributeConfig{
+							{Key: "env"},
+						},
+					},
+				},
 			},
 		},
 		{
@@ -322,6 +360,24 @@ func TestLoadConfig(t *testing.T) {
 						},
 					},
 				},
+				Profiles: map[string]MetricInfo{
+					"my.profile.sample.count": {
+						Description: "My profile sample count.",
+					},
+					"limited.profile.sample.count": {
+						Description: "Limited profile sample count.",
+						Conditions:  []string{`IsMatch(resource.attributes["host.name"], "pod-p")`},
+						Attributes: []AttributeConfig{
+							{
+								Key: "env",
+							},
+							{
+								Key:          "component",
+								DefaultValue: "other",
+							},
+						},
+					},
+				},
 			},
 		},
 		{
@@ -369,6 +425,28 @@ func TestLoadConfig(t *testing.T) {
 						},
 					},
 				},
+				Profiles: map[string]MetricInfo{
+					"my.profile.sample.count": {
+						Description: "My profile sample count with default values.",
+					},
+					"limited.profile.sample.count": {
+						Description: "Limited profile sample count.",
+						Attributes: []AttributeConfig{
+							{
+								Key:          "env",
+								DefaultValue: "local",
+							},
+							{
+								Key:          "http_code",
+								DefaultValue: int(200),
+							},
+							{
+								Key:          "request_success",
+								DefaultValue: float64(0.85),
+							},
+						},
+					},
+				},
 			},
 		},
 	}
@@ -451,6 +529,17 @@ func TestConfigErrors(t *testing.T) {
 			},
 			expect: "logs: metric name missing",
 		},
+		{
+			name: "missing_metric_name_profile",
+			input: &Config{
+				Profiles: map[string]MetricInfo{
+					"": {
+						Description: defaultMetricDescProfiles,
+					},
+				},
+			},
+			expect: "profiles: metric name missing",
+		},
 		{
 			name: "invalid_condition_span",
 			input: &Config{
@@ -511,6 +600,18 @@ func TestConfigErrors(t *testing.T) {
 			},
 			expect: fmt.Sprintf("logs condition: metric %q: unable to parse OTTL condition", defaultMetricNameLogs),
 		},
+		{
+			name: "invalid_condition_profile",
+			input: &Config{
+				Profiles: map[string]MetricInfo{
+					defaultMetricNameProfiles: {
+						Description: defaultMetricDescProfiles,
+						Conditions:  []string{"invalid condition"},
+					},
+				},
+			},
+			expect: fmt.Sprintf("profiles condition: metric %q: unable to parse OTTL condition", defaultMetricNameProfiles),
+		},
 	}
 
 	for _, tc := range testCases {
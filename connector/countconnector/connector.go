@@ -13,18 +13,20 @@ import (
 	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/plog"
 	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/pprofile"
 	"go.opentelemetry.io/collector/pdata/ptrace"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/connector/countconnector/internal/metadata"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottldatapoint"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottllog"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottlmetric"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottlprofile"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottlspan"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottlspanevent"
 )
 
-// count can count spans, span event, metrics, data points, or log records
-// and emit the counts onto a metrics pipeline.
+// count can count spans, span event, metrics, data points, log records,
+// or profile samples and emit the counts onto a metrics pipeline.
 type count struct {
 	metricsConsumer consumer.Metrics
 	component.StartFunc
@@ -35,6 +37,7 @@ type count struct {
 	metricsMetricDefs    map[string]metricDef[ottlmetric.TransformContext]
 	dataPointsMetricDefs map[string]metricDef[ottldatapoint.TransformContext]
 	logsMetricDefs       map[string]metricDef[ottllog.TransformContext]
+	profilesMetricDefs   map[string]metricDef[ottlprofile.TransformContext]
 }
 
 func (c *count) Capabilities() consumer.Capabilities {
@@ -198,3 +201,44 @@ func (c *count) ConsumeLogs(ctx context.Context, ld plog.Logs) error {
 	}
 	return c.metricsConsumer.ConsumeMetrics(ctx, countMetrics)
 }
+
+func (c *count) ConsumeProfiles(ctx context.Context, pd pprofile.Profiles) error {
+	var multiError error
+	countMetrics := pmetric.NewMetrics()
+	countMetrics.ResourceMetrics().EnsureCapacity(pd.ResourceProfiles().Len())
+	for i := 0; i < pd.ResourceProfiles().Len(); i++ {
+		resourceProfile := pd.ResourceProfiles().At(i)
+		counter := newCounter[ottlprofile.TransformContext](c.profilesMetricDefs)
+
+		for j := 0; j < resourceProfile.ScopeProfiles().Len(); j++ {
+			scopeProfiles := resourceProfile.ScopeProfiles().At(j)
+
+			for k := 0; k < scopeProfiles.Profiles().Len(); k++ {
+				profile := scopeProfiles.Profiles().At(k)
+
+				for l := 0; l < profile.Sample().Len(); l++ {
+					sample := profile.Sample().At(l)
+					pCtx := ottlprofile.NewTransformContext(sample, profile, scopeProfiles.Scope(), resourceProfile.Resource(), scopeProfiles, resourceProfile)
+					multiError = errors.Join(multiError, counter.update(ctx, ottlprofile.SampleAttributes(sample, profile), pCtx))
+				}
+			}
+		}
+
+		if len(counter.counts) == 0 {
+			continue // don't add an empty resource
+		}
+
+		countResource := countMetrics.ResourceMetrics().AppendEmpty()
+		resourceProfile.Resource().Attributes().CopyTo(countResource.Resource().Attributes())
+
+		countResource.ScopeMetrics().EnsureCapacity(resourceProfile.ScopeProfiles().Len())
+		countScope := countResource.ScopeMetrics().AppendEmpty()
+		countScope.Scope().SetName(metadata.ScopeName)
+
+		counter.appendMetricsTo(countScope.Metrics())
+	}
+	if multiError != nil {
+		return multiError
+	}
+	return c.metricsConsumer.ConsumeMetrics(ctx, countMetrics)
+}
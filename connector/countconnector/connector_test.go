@@ -12,7 +12,9 @@ import (
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/connector/connectortest"
+	"go.opentelemetry.io/collector/connector/xconnector"
 	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/pdata/pprofile"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/golden"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/pdatatest/pmetrictest"
@@ -692,3 +694,159 @@ func TestLogsToMetrics(t *testing.T) {
 		})
 	}
 }
+
+// The test input is generated by testProfiles and has a repetitive structure:
+// - There are two resources, each with one profile with four samples.
+// - The two resources have the following sets of attributes:
+//   - resource.required: foo, resource.optional: bar
+//   - resource.required: notfoo
+//
+// - The four samples on each profile have the following sets of attributes:
+//   - profile.required: foo, profile.optional: bar
+//   - profile.required: foo, profile.optional: notbar
+//   - profile.required: notfoo
+//   - (no attributes)
+func TestProfilesToMetrics(t *testing.T) {
+	testCases := []struct {
+		name string
+		cfg  *Config
+	}{
+		{
+			name: "zero_conditions",
+			cfg:  &Config{Profiles: defaultProfilesConfig()},
+		},
+		{
+			name: "one_condition",
+			cfg: &Config{
+				Profiles: map[string]MetricInfo{
+					"count.if": {
+						Description: "Count if ...",
+						Conditions: []string{
+							`resource.attributes["resource.optional"] != nil`,
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "multiple_conditions",
+			cfg: &Config{
+				Profiles: map[string]MetricInfo{
+					"count.if": {
+						Description: "Count if ...",
+						Conditions: []string{
+							`resource.attributes["resource.optional"] != nil`,
+							`attributes["profile.optional"] != nil`,
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "one_attribute",
+			cfg: &Config{
+				Profiles: map[string]MetricInfo{
+					"profile.sample.count.by_attr": {
+						Description: "Profile sample count by attribute",
+						Attributes: []AttributeConfig{
+							{
+								Key: "profile.required",
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "condition_and_attribute",
+			cfg: &Config{
+				Profiles: map[string]MetricInfo{
+					"profile.sample.count.if.by_attr": {
+						Description: "Profile sample count by attribute if ...",
+						Conditions: []string{
+							`sample_type == "cpu"`,
+							`function_name == "leaf"`,
+						},
+						Attributes: []AttributeConfig{
+							{
+								Key:          "profile.optional",
+								DefaultValue: "other",
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.NoError(t, tc.cfg.Validate())
+			factory := NewFactory().(xconnector.Factory)
+			sink := &consumertest.MetricsSink{}
+			conn, err := factory.CreateProfilesToMetrics(context.Background(),
+				connectortest.NewNopSettings(), tc.cfg, sink)
+			require.NoError(t, err)
+			require.NotNil(t, conn)
+			assert.False(t, conn.Capabilities().MutatesData)
+
+			require.NoError(t, conn.Start(context.Background(), componenttest.NewNopHost()))
+			defer func() {
+				assert.NoError(t, conn.Shutdown(context.Background()))
+			}()
+
+			assert.NoError(t, conn.ConsumeProfiles(context.Background(), testProfiles()))
+
+			allMetrics := sink.AllMetrics()
+			assert.Len(t, allMetrics, 1)
+
+			// golden.WriteMetrics(t, filepath.Join("testdata", "profiles", tc.name+".yaml"), allMetrics[0])
+			expected, err := golden.ReadMetrics(filepath.Join("testdata", "profiles", tc.name+".yaml"))
+			assert.NoError(t, err)
+			assert.NoError(t, pmetrictest.CompareMetrics(expected, allMetrics[0],
+				pmetrictest.IgnoreTimestamp(),
+				pmetrictest.IgnoreResourceMetricsOrder(),
+				pmetrictest.IgnoreMetricsOrder(),
+				pmetrictest.IgnoreMetricDataPointsOrder()))
+		})
+	}
+}
+
+func testProfiles() pprofile.Profiles {
+	pd := pprofile.NewProfiles()
+	for _, resAttrs := range []map[string]string{
+		{"resource.required": "foo", "resource.optional": "bar"},
+		{"resource.required": "notfoo"},
+	} {
+		rp := pd.ResourceProfiles().AppendEmpty()
+		for k, v := range resAttrs {
+			rp.Resource().Attributes().PutStr(k, v)
+		}
+		profile := rp.ScopeProfiles().AppendEmpty().Profiles().AppendEmpty()
+		profile.StringTable().Append("", "cpu", "nanoseconds", "leaf")
+		sampleType := profile.SampleType().AppendEmpty()
+		sampleType.SetTypeStrindex(1)
+		sampleType.SetUnitStrindex(2)
+		profile.FunctionTable().AppendEmpty().SetNameStrindex(3)
+		profile.LocationTable().AppendEmpty().Line().AppendEmpty().SetFunctionIndex(0)
+		profile.LocationIndices().Append(0)
+
+		for _, kv := range [][2]string{
+			{"profile.required", "foo"},
+			{"profile.optional", "bar"},
+			{"profile.required", "notfoo"},
+			{"profile.optional", "notbar"},
+		} {
+			attr := profile.AttributeTable().AppendEmpty()
+			attr.SetKey(kv[0])
+			attr.Value().SetStr(kv[1])
+		}
+		for _, indices := range [][]int32{{0, 1}, {0, 3}, {2}, {}} {
+			sample := profile.Sample().AppendEmpty()
+			sample.SetLocationsLength(1)
+			sample.Value().Append(10_000_000)
+			sample.AttributeIndices().Append(indices...)
+		}
+	}
+	return pd
+}
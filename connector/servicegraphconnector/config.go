@@ -5,6 +5,8 @@ package servicegraphconnector // import "github.com/open-telemetry/opentelemetry
 
 import (
 	"time"
+
+	"go.opentelemetry.io/collector/component"
 )
 
 // Config defines the configuration options for servicegraphprocessor.
@@ -27,7 +29,7 @@ type Config struct {
 	// https://github.com/open-telemetry/opentelemetry-collector/blob/main/model/semconv/opentelemetry.go.
 	Dimensions []string `mapstructure:"dimensions"`
 
-	// Store contains the config for the in-memory store used to find requests between services by pairing spans.
+	// Store contains the config for the store used to find requests between services by pairing spans.
 	Store StoreConfig `mapstructure:"store"`
 
 	// CacheLoop is the time to cleans the cache periodically.
@@ -49,6 +51,22 @@ type Config struct {
 	// DatabaseNameAttribute is the attribute name used to identify the database name from span attributes.
 	// The default value is db.name.
 	DatabaseNameAttribute string `mapstructure:"database_name_attribute"`
+
+	// Messaging contains the config for building edges between messaging producers and consumers.
+	Messaging MessagingConfig `mapstructure:"messaging"`
+}
+
+type MessagingConfig struct {
+	// DestinationAttributes the list of attributes used to identify the messaging destination of producer and
+	// consumer spans, the higher the front, the higher the priority.
+	// The default value is [messaging.destination.name].
+	DestinationAttributes []string `mapstructure:"destination_attributes"`
+	// MatchDestination only pairs the producer and consumer spans having the same messaging destination, the spans
+	// are paired by their trace and span IDs alone otherwise.
+	MatchDestination bool `mapstructure:"match_destination"`
+	// DestinationNode enables completing producer and consumer spans that could not be paired with an edge
+	// to or from a node named after the messaging destination.
+	DestinationNode bool `mapstructure:"destination_node"`
 }
 
 type StoreConfig struct {
@@ -56,4 +74,12 @@ type StoreConfig struct {
 	MaxItems int `mapstructure:"max_items"`
 	// TTL is the time to live for items in the store.
 	TTL time.Duration `mapstructure:"ttl"`
+	// StorageID is the ID of the storage extension used to keep the edges. When set, the edges are kept in the
+	// storage instead of in memory, which allows pairing spans across collectors sharing the storage and
+	// across restarts. MaxItems then limits the number of pending edges created by this collector.
+	StorageID *component.ID `mapstructure:"storage"`
+	// InstanceID identifies this collector among the ones sharing the storage. It must be unique to each of
+	// them and stable across restarts, the edges of the collectors which stopped recording their heartbeat
+	// are expired by the other ones. Defaults to the hostname.
+	InstanceID string `mapstructure:"instance_id"`
 }
@@ -7,6 +7,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"os"
 	"sort"
 	"strconv"
 	"strings"
@@ -15,6 +16,7 @@ import (
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/extension/xextension/storage"
 	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/pmetric"
 	"go.opentelemetry.io/collector/pdata/ptrace"
@@ -49,6 +51,10 @@ var (
 	}
 
 	defaultDatabaseNameAttribute = semconv.AttributeDBName
+
+	defaultMessagingDestinationAttributes = []string{
+		semconv.AttributeMessagingDestinationName,
+	}
 )
 
 type metricSeries struct {
@@ -59,11 +65,13 @@ type metricSeries struct {
 var _ processor.Traces = (*serviceGraphConnector)(nil)
 
 type serviceGraphConnector struct {
+	id              component.ID
 	config          *Config
 	logger          *zap.Logger
 	metricsConsumer consumer.Metrics
 
-	store *store.Store
+	store           store.EdgeStore
+	persistentStore *store.PersistentStore
 
 	startTime time.Time
 
@@ -76,6 +84,9 @@ type serviceGraphConnector struct {
 	reqServerDurationSecondsCount        map[string]uint64
 	reqServerDurationSecondsSum          map[string]float64
 	reqServerDurationSecondsBucketCounts map[string][]uint64
+	reqMessagingSecondsCount             map[string]uint64
+	reqMessagingSecondsSum               map[string]float64
+	reqMessagingSecondsBucketCounts      map[string][]uint64
 	reqDurationBounds                    []float64
 
 	metricMutex sync.RWMutex
@@ -117,6 +128,10 @@ func newConnector(set component.TelemetrySettings, config component.Config, next
 		pConfig.DatabaseNameAttribute = defaultDatabaseNameAttribute
 	}
 
+	if pConfig.Messaging.DestinationAttributes == nil {
+		pConfig.Messaging.DestinationAttributes = defaultMessagingDestinationAttributes
+	}
+
 	telemetryBuilder, err := metadata.NewTelemetryBuilder(set)
 	if err != nil {
 		return nil, err
@@ -136,6 +151,9 @@ func newConnector(set component.TelemetrySettings, config component.Config, next
 		reqServerDurationSecondsCount:        make(map[string]uint64),
 		reqServerDurationSecondsSum:          make(map[string]float64),
 		reqServerDurationSecondsBucketCounts: make(map[string][]uint64),
+		reqMessagingSecondsCount:             make(map[string]uint64),
+		reqMessagingSecondsSum:               make(map[string]float64),
+		reqMessagingSecondsBucketCounts:      make(map[string][]uint64),
 		reqDurationBounds:                    bounds,
 		keyToMetric:                          make(map[string]metricSeries),
 		shutdownCh:                           make(chan any),
@@ -143,8 +161,36 @@ func newConnector(set component.TelemetrySettings, config component.Config, next
 	}, nil
 }
 
-func (p *serviceGraphConnector) Start(_ context.Context, _ component.Host) error {
-	p.store = store.NewStore(p.config.Store.TTL, p.config.Store.MaxItems, p.onComplete, p.onExpire)
+func (p *serviceGraphConnector) Start(ctx context.Context, host component.Host) error {
+	if p.config.Store.StorageID != nil {
+		client, err := getStorageClient(ctx, host, *p.config.Store.StorageID, p.id)
+		if err != nil {
+			return err
+		}
+		// The instance ID must be unique to each collector sharing the storage and stable across
+		// restarts, use the hostname to identify the collector by default.
+		instanceID := p.config.Store.InstanceID
+		if instanceID == "" {
+			if instanceID, err = os.Hostname(); err != nil {
+				return errors.Join(fmt.Errorf("failed to get hostname for the store instance ID: %w", err), client.Close(ctx))
+			}
+		}
+		p.persistentStore = store.NewPersistentStore(client, store.PersistentStoreSettings{
+			InstanceID:        instanceID,
+			TTL:               p.config.Store.TTL,
+			MaxItems:          p.config.Store.MaxItems,
+			OnComplete:        p.onPaired,
+			OnStorageComplete: p.onStoragePaired,
+			OnExpire:          p.onExpire,
+			OnStorageError:    p.onStorageError,
+		})
+		if err := p.persistentStore.Start(ctx); err != nil {
+			return errors.Join(err, client.Close(ctx))
+		}
+		p.store = p.persistentStore
+	} else {
+		p.store = store.NewStore(p.config.Store.TTL, p.config.Store.MaxItems, p.onPaired, p.onExpire)
+	}
 
 	go p.metricFlushLoop(p.config.MetricsFlushInterval)
 
@@ -191,12 +237,27 @@ func (p *serviceGraphConnector) flushMetrics(ctx context.Context) error {
 	return p.metricsConsumer.ConsumeMetrics(ctx, md)
 }
 
-func (p *serviceGraphConnector) Shutdown(_ context.Context) error {
+func (p *serviceGraphConnector) Shutdown(ctx context.Context) error {
 	p.logger.Info("Shutting down servicegraphconnector")
 	close(p.shutdownCh)
+	if p.persistentStore != nil {
+		return p.persistentStore.Shutdown(ctx)
+	}
 	return nil
 }
 
+func getStorageClient(ctx context.Context, host component.Host, storageID component.ID, componentID component.ID) (storage.Client, error) {
+	ext, ok := host.GetExtensions()[storageID]
+	if !ok {
+		return nil, fmt.Errorf("storage extension '%s' not found", storageID)
+	}
+	storageExt, ok := ext.(storage.Extension)
+	if !ok {
+		return nil, fmt.Errorf("non-storage extension '%s' found", storageID)
+	}
+	return storageExt.GetClient(ctx, component.KindConnector, componentID, "")
+}
+
 func (p *serviceGraphConnector) Capabilities() consumer.Capabilities {
 	return consumer.Capabilities{MutatesData: false}
 }
@@ -251,6 +312,11 @@ func (p *serviceGraphConnector) aggregateMetrics(ctx context.Context, td ptrace.
 				case ptrace.SpanKindClient:
 					traceID := span.TraceID()
 					key := store.NewKey(traceID, span.SpanID())
+					var destination string
+					if connectionType == store.MessagingSystem {
+						destination = p.messagingDestination(span.Attributes())
+						key = p.messagingKey(traceID, span.SpanID(), destination)
+					}
 					isNew, err = p.store.UpsertEdge(key, func(e *store.Edge) {
 						e.TraceID = traceID
 						e.ConnectionType = connectionType
@@ -259,6 +325,11 @@ func (p *serviceGraphConnector) aggregateMetrics(ctx context.Context, td ptrace.
 						e.Failed = e.Failed || span.Status().Code() == ptrace.StatusCodeError
 						p.upsertDimensions(clientKind, e.Dimensions, rAttributes, span.Attributes())
 
+						if connectionType == store.MessagingSystem {
+							e.ClientEndTimestamp = span.EndTimestamp()
+							upsertMessagingDestination(e, destination)
+						}
+
 						if virtualNodeFeatureGate.IsEnabled() {
 							p.upsertPeerAttributes(p.config.VirtualNodePeerAttributes, e.Peer, span.Attributes())
 						}
@@ -272,9 +343,21 @@ func (p *serviceGraphConnector) aggregateMetrics(ctx context.Context, td ptrace.
 						}
 					})
 				case ptrace.SpanKindConsumer:
-					// override connection type and continue processing as span kind server
-					connectionType = store.MessagingSystem
-					fallthrough
+					// Consumers which receive messages in batches or start a new trace reference the
+					// producer spans with links instead of being their child. Pair with every linked
+					// span if there are any, otherwise fall back to pairing with the parent span.
+					links := span.Links()
+					if links.Len() == 0 {
+						isNew, err = p.upsertConsumerEdge(span.TraceID(), span.ParentSpanID(), serviceName, span, rAttributes)
+						break
+					}
+					for l := 0; l < links.Len(); l++ {
+						link := links.At(l)
+						isNew, err = p.upsertConsumerEdge(link.TraceID(), link.SpanID(), serviceName, span, rAttributes)
+						if l < links.Len()-1 {
+							totalDroppedSpans += p.recordUpsert(ctx, isNew, err)
+						}
+					}
 				case ptrace.SpanKindServer:
 					traceID := span.TraceID()
 					key := store.NewKey(traceID, span.ParentSpanID())
@@ -311,6 +394,59 @@ func (p *serviceGraphConnector) aggregateMetrics(ctx context.Context, td ptrace.
 	return nil
 }
 
+// upsertConsumerEdge upserts the server side of a messaging system edge for a consumer span
+// which is paired with the producer span identified by the given trace and span IDs.
+func (p *serviceGraphConnector) upsertConsumerEdge(
+	traceID pcommon.TraceID,
+	spanID pcommon.SpanID,
+	serviceName string,
+	span ptrace.Span,
+	rAttributes pcommon.Map,
+) (bool, error) {
+	destination := p.messagingDestination(span.Attributes())
+	return p.store.UpsertEdge(p.messagingKey(traceID, spanID, destination), func(e *store.Edge) {
+		e.TraceID = traceID
+		e.ConnectionType = store.MessagingSystem
+		e.ServerService = serviceName
+		e.ServerLatencySec = spanDuration(span)
+		e.ServerStartTimestamp = span.StartTimestamp()
+		e.Failed = e.Failed || span.Status().Code() == ptrace.StatusCodeError
+		p.upsertDimensions(serverKind, e.Dimensions, rAttributes, span.Attributes())
+		upsertMessagingDestination(e, destination)
+	})
+}
+
+// messagingKey returns the key pairing a producer span with a consumer span, which includes the messaging
+// destination when the spans are only paired with the same destination.
+func (p *serviceGraphConnector) messagingKey(traceID pcommon.TraceID, spanID pcommon.SpanID, destination string) store.Key {
+	if p.config.Messaging.MatchDestination {
+		return store.NewMessagingKey(traceID, spanID, destination)
+	}
+	return store.NewKey(traceID, spanID)
+}
+
+// upsertMessagingDestination sets the messaging destination of the edge, the first span seen with a destination
+// sets it.
+func upsertMessagingDestination(e *store.Edge, destination string) {
+	if e.MessagingDestination == "" {
+		e.MessagingDestination = destination
+	}
+}
+
+// recordUpsert records the telemetry for the result of an edge upsert which is not the last
+// one of a span, and returns the number of dropped spans.
+func (p *serviceGraphConnector) recordUpsert(ctx context.Context, isNew bool, err error) int {
+	// UpsertEdge will only return ErrTooManyItems
+	if err != nil {
+		p.telemetryBuilder.ConnectorServicegraphDroppedSpans.Add(ctx, 1)
+		return 1
+	}
+	if isNew {
+		p.telemetryBuilder.ConnectorServicegraphTotalEdges.Add(ctx, 1)
+	}
+	return 0
+}
+
 func (p *serviceGraphConnector) upsertDimensions(kind string, m map[string]string, resourceAttr pcommon.Map, spanAttr pcommon.Map) {
 	for _, dim := range p.config.Dimensions {
 		if v, ok := pdatautil.GetAttributeValue(dim, resourceAttr, spanAttr); ok {
@@ -319,6 +455,17 @@ func (p *serviceGraphConnector) upsertDimensions(kind string, m map[string]strin
 	}
 }
 
+// messagingDestination returns the destination a producer span sends to, or a consumer span
+// receives from, empty if it has none of the destination attributes.
+func (p *serviceGraphConnector) messagingDestination(spanAttr pcommon.Map) string {
+	for _, attr := range p.config.Messaging.DestinationAttributes {
+		if v, ok := pdatautil.GetAttributeValue(attr, spanAttr); ok {
+			return v
+		}
+	}
+	return ""
+}
+
 func (p *serviceGraphConnector) upsertPeerAttributes(m []string, peers map[string]string, spanAttr pcommon.Map) {
 	for _, s := range m {
 		if v, ok := pdatautil.GetAttributeValue(s, spanAttr); ok {
@@ -328,6 +475,25 @@ func (p *serviceGraphConnector) upsertPeerAttributes(m []string, peers map[strin
 	}
 }
 
+// onPaired is called by the store when both spans of an edge have been processed.
+func (p *serviceGraphConnector) onPaired(e *store.Edge) {
+	p.telemetryBuilder.ConnectorServicegraphPairedEdges.Add(context.Background(), 1)
+	p.onComplete(e)
+}
+
+// onStoragePaired is called by the persistent store when an edge is completed with the spans
+// processed by another collector, read from the storage.
+func (p *serviceGraphConnector) onStoragePaired(e *store.Edge) {
+	p.telemetryBuilder.ConnectorServicegraphStoragePairedEdges.Add(context.Background(), 1)
+	p.onComplete(e)
+}
+
+// onStorageError is called by the persistent store for each failed batch of storage operations.
+func (p *serviceGraphConnector) onStorageError(err error) {
+	p.telemetryBuilder.ConnectorServicegraphStorageErrors.Add(context.Background(), 1)
+	p.logger.Warn("failed to synchronize edges with storage", zap.Error(err))
+}
+
 func (p *serviceGraphConnector) onComplete(e *store.Edge) {
 	p.logger.Debug(
 		"edge completed",
@@ -350,6 +516,19 @@ func (p *serviceGraphConnector) onExpire(e *store.Edge) {
 
 	p.telemetryBuilder.ConnectorServicegraphExpiredEdges.Add(context.Background(), 1)
 
+	// Complete unpaired messaging edges with a node for the destination the message was
+	// sent to or received from, connecting producers and consumers through the destination.
+	if p.config.Messaging.DestinationNode && e.ConnectionType == store.MessagingSystem && e.MessagingDestination != "" {
+		if len(e.ClientService) == 0 {
+			e.ClientService = e.MessagingDestination
+		}
+		if len(e.ServerService) == 0 {
+			e.ServerService = e.MessagingDestination
+		}
+		p.onComplete(e)
+		return
+	}
+
 	if virtualNodeFeatureGate.IsEnabled() && len(p.config.VirtualNodePeerAttributes) > 0 {
 		e.ConnectionType = store.VirtualNode
 		if len(e.ClientService) == 0 && e.Key.SpanIDIsEmpty() {
@@ -386,6 +565,9 @@ func (p *serviceGraphConnector) aggregateMetricsForEdge(e *store.Edge) {
 		p.updateErrorMetrics(metricKey)
 	}
 	p.updateDurationMetrics(metricKey, e.ServerLatencySec, e.ClientLatencySec)
+	if queueTime, ok := e.MessagingQueueTime(); ok {
+		p.updateMessagingDurationMetrics(metricKey, durationToFloat(queueTime))
+	}
 }
 
 func (p *serviceGraphConnector) updateSeries(key string, dimensions pcommon.Map) {
@@ -437,6 +619,16 @@ func (p *serviceGraphConnector) updateClientDurationMetrics(key string, duration
 	p.reqClientDurationSecondsBucketCounts[key][index]++
 }
 
+func (p *serviceGraphConnector) updateMessagingDurationMetrics(key string, duration float64) {
+	index := sort.SearchFloat64s(p.reqDurationBounds, duration) // Search bucket index
+	if _, ok := p.reqMessagingSecondsBucketCounts[key]; !ok {
+		p.reqMessagingSecondsBucketCounts[key] = make([]uint64, len(p.reqDurationBounds)+1)
+	}
+	p.reqMessagingSecondsSum[key] += duration
+	p.reqMessagingSecondsCount[key]++
+	p.reqMessagingSecondsBucketCounts[key][index]++
+}
+
 func buildDimensions(e *store.Edge) pcommon.Map {
 	dims := pcommon.NewMap()
 	dims.PutStr("client", e.ClientService)
@@ -532,7 +724,43 @@ func (p *serviceGraphConnector) collectLatencyMetrics(ilm pmetric.ScopeMetrics)
 		return err
 	}
 
-	return p.collectClientLatencyMetrics(ilm)
+	if err := p.collectClientLatencyMetrics(ilm); err != nil {
+		return err
+	}
+
+	return p.collectMessagingLatencyMetrics(ilm)
+}
+
+func (p *serviceGraphConnector) collectMessagingLatencyMetrics(ilm pmetric.ScopeMetrics) error {
+	if len(p.reqMessagingSecondsCount) > 0 {
+		mDuration := ilm.Metrics().AppendEmpty()
+		mDuration.SetName("traces_service_graph_request_messaging_system")
+		mDuration.SetUnit(secondsUnit)
+		if legacyLatencyUnitMsFeatureGate.IsEnabled() {
+			mDuration.SetUnit(millisecondsUnit)
+		}
+		// TODO: Support other aggregation temporalities
+		mDuration.SetEmptyHistogram().SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+		timestamp := pcommon.NewTimestampFromTime(time.Now())
+
+		for key := range p.reqMessagingSecondsCount {
+			dpDuration := mDuration.Histogram().DataPoints().AppendEmpty()
+			dpDuration.SetStartTimestamp(pcommon.NewTimestampFromTime(p.startTime))
+			dpDuration.SetTimestamp(timestamp)
+			dpDuration.ExplicitBounds().FromRaw(p.reqDurationBounds)
+			dpDuration.BucketCounts().FromRaw(p.reqMessagingSecondsBucketCounts[key])
+			dpDuration.SetCount(p.reqMessagingSecondsCount[key])
+			dpDuration.SetSum(p.reqMessagingSecondsSum[key])
+
+			dimensions, ok := p.dimensionsForSeries(key)
+			if !ok {
+				return fmt.Errorf("failed to find dimensions for key %s", key)
+			}
+
+			dimensions.CopyTo(dpDuration.Attributes())
+		}
+	}
+	return nil
 }
 
 func (p *serviceGraphConnector) collectClientLatencyMetrics(ilm pmetric.ScopeMetrics) error {
@@ -674,6 +902,9 @@ func (p *serviceGraphConnector) cleanCache() {
 		delete(p.reqServerDurationSecondsCount, key)
 		delete(p.reqServerDurationSecondsSum, key)
 		delete(p.reqServerDurationSecondsBucketCounts, key)
+		delete(p.reqMessagingSecondsCount, key)
+		delete(p.reqMessagingSecondsSum, key)
+		delete(p.reqMessagingSecondsBucketCounts, key)
 	}
 	p.seriesMutex.Unlock()
 
@@ -154,6 +154,117 @@ func TestConnectorConsume(t *testing.T) {
 				assert.Equal(t, 0, md.MetricCount())
 			},
 		},
+		{
+			name: "messaging traces with consumer span linked to producer span",
+			cfg: &Config{
+				Store: StoreConfig{
+					MaxItems: 10,
+					TTL:      time.Nanosecond,
+				},
+			},
+			sampleTraces: buildMessagingTraces(true),
+			verifyMetrics: func(t *testing.T, md pmetric.Metrics) {
+				ms := md.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics()
+				require.Equal(t, 4, ms.Len())
+				dp := ms.At(0).Sum().DataPoints().At(0)
+				assert.Equal(t, int64(1), dp.IntValue())
+				verifyAttr(t, dp.Attributes(), "client", "producer-service")
+				verifyAttr(t, dp.Attributes(), "server", "consumer-service")
+				verifyAttr(t, dp.Attributes(), "connection_type", "messaging_system")
+
+				mQueue := ms.At(3)
+				assert.Equal(t, "traces_service_graph_request_messaging_system", mQueue.Name())
+				verifyUnit(t, secondsUnit, mQueue.Unit())
+				queueDp := mQueue.Histogram().DataPoints().At(0)
+				assert.Equal(t, uint64(1), queueDp.Count())
+				assert.InDelta(t, 0.5, queueDp.Sum(), 0.0001)
+				assert.Equal(t, []uint64{0, 0, 0, 0, 0, 0, 0, 0, 0, 1, 0, 0, 0, 0, 0, 0, 0}, queueDp.BucketCounts().AsRaw())
+				verifyAttr(t, queueDp.Attributes(), "connection_type", "messaging_system")
+			},
+		},
+		{
+			name: "messaging traces with consumer spans linked to several producer spans",
+			cfg: &Config{
+				Store: StoreConfig{
+					MaxItems: 10,
+					TTL:      time.Nanosecond,
+				},
+			},
+			sampleTraces: buildMessagingLinksTraces(),
+			verifyMetrics: func(t *testing.T, md pmetric.Metrics) {
+				dps := md.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0).Sum().DataPoints()
+				edges := map[string]int64{}
+				for i := 0; i < dps.Len(); i++ {
+					attrs := dps.At(i).Attributes()
+					verifyAttr(t, attrs, "connection_type", "messaging_system")
+					client, _ := attrs.Get("client")
+					server, _ := attrs.Get("server")
+					edges[client.Str()+" -> "+server.Str()] = dps.At(i).IntValue()
+				}
+				// The spans are paired by their IDs whatever their destination, the producer span sending to
+				// payments is paired with the consumer of orders linking to it first.
+				assert.Equal(t, map[string]int64{
+					"orders-service -> billing-service":   2,
+					"payments-service -> billing-service": 1,
+				}, edges)
+			},
+		},
+		{
+			name: "messaging traces with consumer spans linked to several producer spans matching destinations",
+			cfg: &Config{
+				Store: StoreConfig{
+					MaxItems: 10,
+					TTL:      time.Nanosecond,
+				},
+				Messaging: MessagingConfig{
+					MatchDestination: true,
+				},
+			},
+			sampleTraces: buildMessagingLinksTraces(),
+			verifyMetrics: func(t *testing.T, md pmetric.Metrics) {
+				dps := md.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0).Sum().DataPoints()
+				edges := map[string]int64{}
+				for i := 0; i < dps.Len(); i++ {
+					attrs := dps.At(i).Attributes()
+					verifyAttr(t, attrs, "connection_type", "messaging_system")
+					client, _ := attrs.Get("client")
+					server, _ := attrs.Get("server")
+					edges[client.Str()+" -> "+server.Str()] = dps.At(i).IntValue()
+				}
+				// The link from the consumer of orders to the producer span sending to payments is not paired.
+				assert.Equal(t, map[string]int64{
+					"orders-service -> billing-service": 2,
+					"payments-service -> audit-service": 1,
+				}, edges)
+			},
+		},
+		{
+			name: "incomplete messaging traces with destination node",
+			cfg: &Config{
+				Store: StoreConfig{
+					MaxItems: 10,
+					TTL:      time.Nanosecond,
+				},
+				Messaging: MessagingConfig{DestinationNode: true},
+			},
+			sampleTraces: buildMessagingTraces(false),
+			verifyMetrics: func(t *testing.T, md pmetric.Metrics) {
+				dps := md.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0).Sum().DataPoints()
+				require.Equal(t, 2, dps.Len())
+				edges := map[string]string{}
+				for i := 0; i < dps.Len(); i++ {
+					attrs := dps.At(i).Attributes()
+					verifyAttr(t, attrs, "connection_type", "messaging_system")
+					client, _ := attrs.Get("client")
+					server, _ := attrs.Get("server")
+					edges[client.Str()] = server.Str()
+				}
+				assert.Equal(t, map[string]string{
+					"producer-service": "orders",
+					"orders":           "consumer-service",
+				}, edges)
+			},
+		},
 		{
 			name: "complete traces with legacy latency metrics",
 			cfg: &Config{
@@ -385,6 +496,93 @@ func incompleteServerTraces(withParentSpan bool) ptrace.Traces {
 	return traces
 }
 
+// buildMessagingTraces builds a producer and a consumer span, in separate traces, which send to and
+// receive from the `orders` destination. If linked is true the consumer span links to the producer span.
+func buildMessagingTraces(linked bool) ptrace.Traces {
+	tStart := time.Date(2022, 1, 2, 3, 4, 5, 6, time.UTC)
+	// producer: 1s, message is consumed 500ms after it was produced
+	pEnd := time.Date(2022, 1, 2, 3, 4, 6, 6, time.UTC)
+	cStart := pEnd.Add(500 * time.Millisecond)
+	cEnd := cStart.Add(time.Second)
+
+	producerTraceID := pcommon.TraceID([16]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16})
+	producerSpanID := pcommon.SpanID([8]byte{1, 2, 3, 4, 5, 6, 7, 8})
+	consumerTraceID := pcommon.TraceID([16]byte{16, 15, 14, 13, 12, 11, 10, 9, 8, 7, 6, 5, 4, 3, 2, 1})
+
+	traces := ptrace.NewTraces()
+
+	producerResourceSpans := traces.ResourceSpans().AppendEmpty()
+	producerResourceSpans.Resource().Attributes().PutStr(semconv.AttributeServiceName, "producer-service")
+	producerSpan := producerResourceSpans.ScopeSpans().AppendEmpty().Spans().AppendEmpty()
+	producerSpan.SetName("orders publish")
+	producerSpan.SetTraceID(producerTraceID)
+	producerSpan.SetSpanID(producerSpanID)
+	producerSpan.SetKind(ptrace.SpanKindProducer)
+	producerSpan.SetStartTimestamp(pcommon.NewTimestampFromTime(tStart))
+	producerSpan.SetEndTimestamp(pcommon.NewTimestampFromTime(pEnd))
+	producerSpan.Attributes().PutStr(semconv.AttributeMessagingDestinationName, "orders")
+
+	consumerResourceSpans := traces.ResourceSpans().AppendEmpty()
+	consumerResourceSpans.Resource().Attributes().PutStr(semconv.AttributeServiceName, "consumer-service")
+	consumerSpan := consumerResourceSpans.ScopeSpans().AppendEmpty().Spans().AppendEmpty()
+	consumerSpan.SetName("orders process")
+	consumerSpan.SetTraceID(consumerTraceID)
+	consumerSpan.SetSpanID([8]byte{8, 7, 6, 5, 4, 3, 2, 1})
+	consumerSpan.SetKind(ptrace.SpanKindConsumer)
+	consumerSpan.SetStartTimestamp(pcommon.NewTimestampFromTime(cStart))
+	consumerSpan.SetEndTimestamp(pcommon.NewTimestampFromTime(cEnd))
+	consumerSpan.Attributes().PutStr(semconv.AttributeMessagingDestinationName, "orders")
+	if linked {
+		link := consumerSpan.Links().AppendEmpty()
+		link.SetTraceID(producerTraceID)
+		link.SetSpanID(producerSpanID)
+	}
+
+	return traces
+}
+
+// buildMessagingLinksTraces builds producer spans sending to the `orders` and `payments` destinations,
+// and consumer spans receiving batches of messages from each destination, linked to their producer spans.
+// The consumer of `orders` also links to a producer span sending to `payments`.
+func buildMessagingLinksTraces() ptrace.Traces {
+	tStart := time.Date(2022, 1, 2, 3, 4, 5, 6, time.UTC)
+	tEnd := tStart.Add(time.Second)
+	traces := ptrace.NewTraces()
+
+	addSpan := func(service, destination string, kind ptrace.SpanKind, traceID pcommon.TraceID, spanID pcommon.SpanID) ptrace.Span {
+		rs := traces.ResourceSpans().AppendEmpty()
+		rs.Resource().Attributes().PutStr(semconv.AttributeServiceName, service)
+		span := rs.ScopeSpans().AppendEmpty().Spans().AppendEmpty()
+		span.SetName(destination)
+		span.SetTraceID(traceID)
+		span.SetSpanID(spanID)
+		span.SetKind(kind)
+		span.SetStartTimestamp(pcommon.NewTimestampFromTime(tStart))
+		span.SetEndTimestamp(pcommon.NewTimestampFromTime(tEnd))
+		span.Attributes().PutStr(semconv.AttributeMessagingDestinationName, destination)
+		return span
+	}
+	link := func(consumer, producer ptrace.Span) {
+		l := consumer.Links().AppendEmpty()
+		l.SetTraceID(producer.TraceID())
+		l.SetSpanID(producer.SpanID())
+	}
+
+	order1 := addSpan("orders-service", "orders", ptrace.SpanKindProducer, [16]byte{1}, [8]byte{1})
+	order2 := addSpan("orders-service", "orders", ptrace.SpanKindProducer, [16]byte{2}, [8]byte{2})
+	payment := addSpan("payments-service", "payments", ptrace.SpanKindProducer, [16]byte{3}, [8]byte{3})
+
+	billing := addSpan("billing-service", "orders", ptrace.SpanKindConsumer, [16]byte{4}, [8]byte{4})
+	link(billing, order1)
+	link(billing, order2)
+	link(billing, payment)
+
+	audit := addSpan("audit-service", "payments", ptrace.SpanKindConsumer, [16]byte{5}, [8]byte{5})
+	link(audit, payment)
+
+	return traces
+}
+
 var _ exporter.Metrics = (*mockMetricsExporter)(nil)
 
 func newMockMetricsExporter() *mockMetricsExporter {
@@ -595,6 +793,18 @@ func TestValidateOwnTelemetry(t *testing.T) {
 	// Shutdown the connector
 	assert.NoError(t, p.Shutdown(context.Background()))
 	set.AssertMetrics(t, []metricdata.Metrics{
+		{
+			Name:        "otelcol_connector_servicegraph_paired_edges",
+			Description: "Number of edges completed by pairing the spans of both of their sides",
+			Unit:        "1",
+			Data: metricdata.Sum[int64]{
+				Temporality: metricdata.CumulativeTemporality,
+				IsMonotonic: true,
+				DataPoints: []metricdata.DataPoint[int64]{
+					{Value: 2},
+				},
+			},
+		},
 		{
 			Name:        "otelcol_connector_servicegraph_total_edges",
 			Description: "Total number of unique edges",
@@ -16,6 +16,7 @@ require (
 	go.opentelemetry.io/collector/consumer v1.24.0
 	go.opentelemetry.io/collector/consumer/consumertest v0.118.0
 	go.opentelemetry.io/collector/exporter v0.118.0
+	go.opentelemetry.io/collector/extension/xextension v0.118.0
 	go.opentelemetry.io/collector/featuregate v1.24.0
 	go.opentelemetry.io/collector/otelcol/otelcoltest v0.118.0
 	go.opentelemetry.io/collector/pdata v1.24.0
@@ -24,10 +24,13 @@ func Tracer(settings component.TelemetrySettings) trace.Tracer {
 // TelemetryBuilder provides an interface for components to report telemetry
 // as defined in metadata and user config.
 type TelemetryBuilder struct {
-	meter                             metric.Meter
-	ConnectorServicegraphDroppedSpans metric.Int64Counter
-	ConnectorServicegraphExpiredEdges metric.Int64Counter
-	ConnectorServicegraphTotalEdges   metric.Int64Counter
+	meter                                   metric.Meter
+	ConnectorServicegraphDroppedSpans       metric.Int64Counter
+	ConnectorServicegraphExpiredEdges       metric.Int64Counter
+	ConnectorServicegraphPairedEdges        metric.Int64Counter
+	ConnectorServicegraphStorageErrors      metric.Int64Counter
+	ConnectorServicegraphStoragePairedEdges metric.Int64Counter
+	ConnectorServicegraphTotalEdges         metric.Int64Counter
 }
 
 // TelemetryBuilderOption applies changes to default builder.
@@ -62,6 +65,24 @@ func NewTelemetryBuilder(settings component.TelemetrySettings, options ...Teleme
 		metric.WithUnit("1"),
 	)
 	errs = errors.Join(errs, err)
+	builder.ConnectorServicegraphPairedEdges, err = getLeveledMeter(builder.meter, configtelemetry.LevelBasic, settings.MetricsLevel).Int64Counter(
+		"otelcol_connector_servicegraph_paired_edges",
+		metric.WithDescription("Number of edges completed by pairing the spans of both of their sides"),
+		metric.WithUnit("1"),
+	)
+	errs = errors.Join(errs, err)
+	builder.ConnectorServicegraphStorageErrors, err = getLeveledMeter(builder.meter, configtelemetry.LevelBasic, settings.MetricsLevel).Int64Counter(
+		"otelcol_connector_servicegraph_storage_errors",
+		metric.WithDescription("Number of failed batches of operations on the storage of the edges"),
+		metric.WithUnit("1"),
+	)
+	errs = errors.Join(errs, err)
+	builder.ConnectorServicegraphStoragePairedEdges, err = getLeveledMeter(builder.meter, configtelemetry.LevelBasic, settings.MetricsLevel).Int64Counter(
+		"otelcol_connector_servicegraph_storage_paired_edges",
+		metric.WithDescription("Number of edges completed by pairing their spans with the pending edges of other collectors read from the storage"),
+		metric.WithUnit("1"),
+	)
+	errs = errors.Join(errs, err)
 	builder.ConnectorServicegraphTotalEdges, err = getLeveledMeter(builder.meter, configtelemetry.LevelBasic, settings.MetricsLevel).Int64Counter(
 		"otelcol_connector_servicegraph_total_edges",
 		metric.WithDescription("Total number of unique edges"),
@@ -22,6 +22,9 @@ func TestSetupTelemetry(t *testing.T) {
 	require.NotNil(t, tb)
 	tb.ConnectorServicegraphDroppedSpans.Add(context.Background(), 1)
 	tb.ConnectorServicegraphExpiredEdges.Add(context.Background(), 1)
+	tb.ConnectorServicegraphPairedEdges.Add(context.Background(), 1)
+	tb.ConnectorServicegraphStorageErrors.Add(context.Background(), 1)
+	tb.ConnectorServicegraphStoragePairedEdges.Add(context.Background(), 1)
 	tb.ConnectorServicegraphTotalEdges.Add(context.Background(), 1)
 
 	testTel.AssertMetrics(t, []metricdata.Metrics{
@@ -49,6 +52,42 @@ func TestSetupTelemetry(t *testing.T) {
 				},
 			},
 		},
+		{
+			Name:        "otelcol_connector_servicegraph_paired_edges",
+			Description: "Number of edges completed by pairing the spans of both of their sides",
+			Unit:        "1",
+			Data: metricdata.Sum[int64]{
+				Temporality: metricdata.CumulativeTemporality,
+				IsMonotonic: true,
+				DataPoints: []metricdata.DataPoint[int64]{
+					{},
+				},
+			},
+		},
+		{
+			Name:        "otelcol_connector_servicegraph_storage_errors",
+			Description: "Number of failed batches of operations on the storage of the edges",
+			Unit:        "1",
+			Data: metricdata.Sum[int64]{
+				Temporality: metricdata.CumulativeTemporality,
+				IsMonotonic: true,
+				DataPoints: []metricdata.DataPoint[int64]{
+					{},
+				},
+			},
+		},
+		{
+			Name:        "otelcol_connector_servicegraph_storage_paired_edges",
+			Description: "Number of edges completed by pairing their spans with the pending edges of other collectors read from the storage",
+			Unit:        "1",
+			Data: metricdata.Sum[int64]{
+				Temporality: metricdata.CumulativeTemporality,
+				IsMonotonic: true,
+				DataPoints: []metricdata.DataPoint[int64]{
+					{},
+				},
+			},
+		},
 		{
 			Name:        "otelcol_connector_servicegraph_total_edges",
 			Description: "Total number of unique edges",
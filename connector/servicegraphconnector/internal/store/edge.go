@@ -50,6 +50,13 @@ type Edge struct {
 
 	// VirtualNodeLabel is an optional label to be added to the spans
 	VirtualNodeLabel VirtualNodeLabel
+
+	// MessagingDestination is the destination, e.g. queue or topic, of a messaging system Edge
+	MessagingDestination string
+
+	// ClientEndTimestamp and ServerStartTimestamp are used to calculate the time
+	// a message spent in a messaging system between the producer and consumer
+	ClientEndTimestamp, ServerStartTimestamp pcommon.Timestamp
 }
 
 func newEdge(key Key, ttl time.Duration) *Edge {
@@ -67,6 +74,72 @@ func (e *Edge) isComplete() bool {
 	return len(e.ClientService) != 0 && len(e.ServerService) != 0
 }
 
+// MessagingQueueTime returns the time between the end of the producer span
+// and the start of the consumer span. It returns false if the Edge is not a
+// messaging system Edge or if either side has not been processed.
+func (e *Edge) MessagingQueueTime() (time.Duration, bool) {
+	if e.ConnectionType != MessagingSystem || e.ClientEndTimestamp == 0 || e.ServerStartTimestamp == 0 {
+		return 0, false
+	}
+	// Clock skew between the producer and consumer can result in negative values.
+	if e.ServerStartTimestamp < e.ClientEndTimestamp {
+		return 0, true
+	}
+	return e.ServerStartTimestamp.AsTime().Sub(e.ClientEndTimestamp.AsTime()), true
+}
+
 func (e *Edge) isExpired() bool {
 	return time.Now().After(e.expiration)
 }
+
+// merge completes the Edge with the sides of the other Edge it lacks, the other Edge holding the
+// spans of the same request processed by another collector.
+func (e *Edge) merge(other *Edge) {
+	if e.TraceID.IsEmpty() {
+		e.TraceID = other.TraceID
+	}
+	if e.ConnectionType == Unknown {
+		e.ConnectionType = other.ConnectionType
+	}
+	if len(e.ClientService) == 0 {
+		e.ClientService = other.ClientService
+		e.ClientLatencySec = other.ClientLatencySec
+		e.ClientEndTimestamp = other.ClientEndTimestamp
+	}
+	if len(e.ServerService) == 0 {
+		e.ServerService = other.ServerService
+		e.ServerLatencySec = other.ServerLatencySec
+		e.ServerStartTimestamp = other.ServerStartTimestamp
+	}
+	e.Failed = e.Failed || other.Failed
+	for k, v := range other.Dimensions {
+		if _, ok := e.Dimensions[k]; !ok {
+			e.Dimensions[k] = v
+		}
+	}
+	for k, v := range other.Peer {
+		if _, ok := e.Peer[k]; !ok {
+			e.Peer[k] = v
+		}
+	}
+	if e.VirtualNodeLabel == UnknownVirtualNode {
+		e.VirtualNodeLabel = other.VirtualNodeLabel
+	}
+	if e.MessagingDestination == "" {
+		e.MessagingDestination = other.MessagingDestination
+	}
+}
+
+// clone returns a copy of the Edge which doesn't share its maps.
+func (e *Edge) clone() *Edge {
+	c := *e
+	c.Dimensions = make(map[string]string, len(e.Dimensions))
+	for k, v := range e.Dimensions {
+		c.Dimensions[k] = v
+	}
+	c.Peer = make(map[string]string, len(e.Peer))
+	for k, v := range e.Peer {
+		c.Peer[k] = v
+	}
+	return &c
+}
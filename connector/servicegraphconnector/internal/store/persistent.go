@@ -0,0 +1,664 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package store // import "github.com/open-telemetry/opentelemetry-collector-contrib/connector/servicegraphconnector/internal/store"
+
+import (
+	"container/list"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/extension/xextension/storage"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+)
+
+const (
+	edgeKeyPrefix  = "edge/"
+	indexKeyPrefix = "index/"
+	// heartbeatsKey is the key of the last heartbeats of the stores sharing the storage, by instance ID.
+	heartbeatsKey = "heartbeats"
+
+	// heartbeatInterval is the interval at which a store records its heartbeat and garbage collects
+	// the indexes of the stores which stopped.
+	heartbeatInterval = time.Minute
+	// staleIndexTimeout is the time without heartbeat after which the index of a store is garbage
+	// collected by the other stores, and its edges expired.
+	staleIndexTimeout = 10 * time.Minute
+)
+
+// PersistentStoreSettings configures a PersistentStore.
+type PersistentStoreSettings struct {
+	// InstanceID identifies the collector among the ones sharing the storage, it must be unique to
+	// each of them and stable across restarts.
+	InstanceID string
+	// TTL is the time to live of the edges.
+	TTL time.Duration
+	// MaxItems is the maximum number of edges kept by the store.
+	MaxItems int
+
+	// OnComplete is called for the edges completed in memory, all of their spans having been
+	// processed by this collector.
+	OnComplete Callback
+	// OnStorageComplete is called for the edges completed by pairing them with the pending edges of
+	// other collectors, read from the storage.
+	OnStorageComplete Callback
+	// OnExpire is called for the edges which expired before being completed.
+	OnExpire Callback
+	// OnStorageError is called for each failed batch of storage operations.
+	OnStorageError func(err error)
+}
+
+// PersistentStore is an EdgeStore which shares the edges through a storage extension. When the
+// storage is shared, e.g. a Redis instance, the spans of an edge can be paired by any of the
+// collectors using it, and pending edges survive restarts.
+//
+// The edges are kept in memory, so that spans are processed without accessing the storage, and
+// are synchronized with the storage in batches by Expire: the new and updated edges are merged
+// with the ones written by the other collectors, then either completed or written back. The
+// storage client does not provide atomic read-modify-write operations, pairing is therefore
+// best-effort: when both sides of an edge are synchronized at the same time by different
+// collectors, the edge may expire instead of being completed.
+//
+// Storage clients cannot list their keys, so each store keeps an index of the edges it has written
+// in order to load them again on restart. Each store also records a heartbeat, and the indexes of
+// the stores without heartbeat for staleIndexTimeout are garbage collected by the other ones.
+type PersistentStore struct {
+	client storage.Client
+	set    PersistentStoreSettings
+	now    func() time.Time
+
+	mtx sync.Mutex
+	l   *list.List
+	m   map[Key]*list.Element
+	// deletes are the storage keys of the edges completed in memory after being written.
+	deletes    []string
+	indexDirty bool
+
+	// syncMtx serializes the synchronizations with the storage.
+	syncMtx       sync.Mutex
+	lastHeartbeat time.Time
+}
+
+var _ EdgeStore = (*PersistentStore)(nil)
+
+// persistentEdge is an edge kept in memory by a PersistentStore.
+type persistentEdge struct {
+	*Edge
+	// stored is set once the edge has been written to the storage.
+	stored bool
+	// version is incremented by each update of the edge, written is the last version written to
+	// the storage.
+	version, written uint64
+}
+
+// syncOutcome is the result of the synchronization of an edge with the storage.
+type syncOutcome int
+
+const (
+	// outcomeWritten means that the edge, merged with the stored one if any, was written.
+	outcomeWritten syncOutcome = iota
+	// outcomeCompleted means that the edge was completed by merging it with the stored one.
+	outcomeCompleted
+	// outcomeExpired means that the edge expired.
+	outcomeExpired
+	// outcomeGone means that the edge written before was completed by another collector.
+	outcomeGone
+)
+
+// syncedEdge is an edge being synchronized with the storage.
+type syncedEdge struct {
+	// edge is a copy of the edge, or the edge itself once expired.
+	edge    *Edge
+	stored  bool
+	expired bool
+	version uint64
+	outcome syncOutcome
+}
+
+// indexEntry is an edge written by this store which has not been completed nor expired yet.
+type indexEntry struct {
+	TraceID     pcommon.TraceID `json:"trace_id"`
+	SpanID      pcommon.SpanID  `json:"span_id"`
+	Destination string          `json:"destination,omitempty"`
+	Expiration  time.Time       `json:"expiration"`
+}
+
+func (e indexEntry) key() Key {
+	return NewMessagingKey(e.TraceID, e.SpanID, e.Destination)
+}
+
+// storedEdge is the representation of an Edge in the storage.
+type storedEdge struct {
+	TraceID              pcommon.TraceID   `json:"trace_id"`
+	ConnectionType       ConnectionType    `json:"connection_type"`
+	ServerService        string            `json:"server_service"`
+	ClientService        string            `json:"client_service"`
+	ServerLatencySec     float64           `json:"server_latency_sec"`
+	ClientLatencySec     float64           `json:"client_latency_sec"`
+	Failed               bool              `json:"failed"`
+	Dimensions           map[string]string `json:"dimensions"`
+	Expiration           time.Time         `json:"expiration"`
+	Peer                 map[string]string `json:"peer"`
+	VirtualNodeLabel     VirtualNodeLabel  `json:"virtual_node_label"`
+	MessagingDestination string            `json:"messaging_destination"`
+	ClientEndTimestamp   uint64            `json:"client_end_timestamp"`
+	ServerStartTimestamp uint64            `json:"server_start_timestamp"`
+}
+
+// NewPersistentStore creates a PersistentStore using the given storage client.
+func NewPersistentStore(client storage.Client, set PersistentStoreSettings) *PersistentStore {
+	return &PersistentStore{
+		client: client,
+		set:    set,
+		now:    time.Now,
+
+		l: list.New(),
+		m: make(map[Key]*list.Element),
+	}
+}
+
+// Start loads the edges written by this store before it was last shut down, and records its
+// heartbeat.
+func (s *PersistentStore) Start(ctx context.Context) error {
+	entries, err := s.loadIndex(ctx, s.indexKey())
+	if err != nil {
+		return fmt.Errorf("failed to load edge index: %w", err)
+	}
+	gets := make([]*storage.Operation, len(entries))
+	for i, entry := range entries {
+		gets[i] = storage.GetOperation(edgeStorageKey(entry.key()))
+	}
+	if len(gets) > 0 {
+		if err := s.client.Batch(ctx, gets...); err != nil {
+			return fmt.Errorf("failed to load edges: %w", err)
+		}
+	}
+
+	var edges []*persistentEdge
+	for i, entry := range entries {
+		edge, err := decodeEdge(entry.key(), gets[i].Value)
+		if err != nil {
+			return err
+		}
+		if edge == nil {
+			// completed by another collector
+			continue
+		}
+		edges = append(edges, &persistentEdge{Edge: edge, stored: true})
+	}
+	sort.SliceStable(edges, func(i, j int) bool {
+		return edges[i].expiration.Before(edges[j].expiration)
+	})
+
+	s.mtx.Lock()
+	for _, edge := range edges {
+		if _, ok := s.m[edge.Key]; !ok {
+			s.m[edge.Key] = s.l.PushBack(edge)
+		}
+	}
+	s.indexDirty = len(edges) != len(entries)
+	s.mtx.Unlock()
+
+	s.syncMtx.Lock()
+	defer s.syncMtx.Unlock()
+	s.heartbeat(ctx)
+	return nil
+}
+
+// Shutdown writes the pending edges to the storage and closes the storage client.
+func (s *PersistentStore) Shutdown(ctx context.Context) error {
+	s.syncMtx.Lock()
+	// No edge expires at the zero time.
+	s.sync(ctx, time.Time{})
+	s.syncMtx.Unlock()
+
+	return s.client.Close(ctx)
+}
+
+// Len returns the number of edges kept by this store.
+func (s *PersistentStore) Len() int {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	return s.l.Len()
+}
+
+// UpsertEdge fetches an Edge from the store and updates it using the given callback. If the Edge
+// doesn't exist yet, it creates a new one with the default TTL.
+// If the Edge is complete after applying the callback, it's completed and removed.
+// The storage isn't accessed, the changes are written by the next synchronization.
+func (s *PersistentStore) UpsertEdge(key Key, update Callback) (isNew bool, err error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if ele, ok := s.m[key]; ok {
+		pe := ele.Value.(*persistentEdge)
+		update(pe.Edge)
+
+		if pe.isComplete() {
+			s.set.OnComplete(pe.Edge)
+			s.remove(ele)
+			return false, nil
+		}
+
+		pe.version++
+		return false, nil
+	}
+
+	edge := newEdge(key, s.set.TTL)
+	update(edge)
+
+	if edge.isComplete() {
+		s.set.OnComplete(edge)
+		return true, nil
+	}
+
+	// Check we can add new edges
+	if s.l.Len() >= s.set.MaxItems {
+		return false, ErrTooManyItems
+	}
+
+	s.m[key] = s.l.PushBack(&persistentEdge{Edge: edge, version: 1})
+	return true, nil
+}
+
+// Expire synchronizes the new and updated edges with the storage and evicts the expired ones.
+// Expired edges which have been completed by another collector sharing the storage are dropped.
+func (s *PersistentStore) Expire() {
+	ctx := context.Background()
+
+	s.syncMtx.Lock()
+	defer s.syncMtx.Unlock()
+
+	s.sync(ctx, s.now())
+	s.heartbeat(ctx)
+}
+
+// sync synchronizes the new and updated edges with the storage, as well as the edges expired at the
+// given time, which are evicted. The storage is accessed without holding the lock, so that spans
+// keep being processed. sync must be called holding syncMtx.
+func (s *PersistentStore) sync(ctx context.Context, now time.Time) {
+	edges, deletes := s.pendingSync(now)
+
+	gets := make([]*storage.Operation, len(edges))
+	for i, e := range edges {
+		gets[i] = storage.GetOperation(edgeStorageKey(e.edge.Key))
+	}
+	if len(gets) > 0 {
+		if err := s.client.Batch(ctx, gets...); err != nil {
+			s.set.OnStorageError(fmt.Errorf("failed to get edges from storage: %w", err))
+			// The expired edges can't be paired anymore, the other ones are synchronized again
+			// by the next call.
+			for _, e := range edges {
+				if e.expired {
+					s.set.OnExpire(e.edge)
+					if e.stored {
+						deletes = append(deletes, edgeStorageKey(e.edge.Key))
+					}
+				}
+			}
+			s.requeueDeletes(deletes)
+			return
+		}
+	}
+
+	writes := make([]*storage.Operation, 0, len(edges)+len(deletes))
+	for _, key := range deletes {
+		writes = append(writes, storage.DeleteOperation(key))
+	}
+	for i, e := range edges {
+		stored, err := decodeEdge(e.edge.Key, gets[i].Value)
+		if err != nil {
+			// The edge is overwritten below.
+			s.set.OnStorageError(err)
+		}
+		if stored == nil && e.stored {
+			e.outcome = outcomeGone
+			continue
+		}
+		if stored != nil {
+			e.edge.merge(stored)
+		}
+		switch {
+		case e.edge.isComplete():
+			e.outcome = outcomeCompleted
+		case e.expired:
+			e.outcome = outcomeExpired
+		default:
+			e.outcome = outcomeWritten
+			writes = append(writes, storage.SetOperation(gets[i].Key, encodeEdge(e.edge)))
+			continue
+		}
+		if stored != nil {
+			writes = append(writes, storage.DeleteOperation(gets[i].Key))
+		}
+	}
+	if len(writes) > 0 {
+		if err := s.client.Batch(ctx, writes...); err != nil {
+			s.set.OnStorageError(fmt.Errorf("failed to write edges to storage: %w", err))
+			for _, e := range edges {
+				if !e.expired {
+					continue
+				}
+				if e.outcome == outcomeCompleted {
+					s.set.OnStorageComplete(e.edge)
+				} else if e.outcome == outcomeExpired {
+					s.set.OnExpire(e.edge)
+				}
+				if e.stored {
+					deletes = append(deletes, edgeStorageKey(e.edge.Key))
+				}
+			}
+			s.requeueDeletes(deletes)
+			return
+		}
+	}
+
+	completed, expired, index := s.applySync(edges)
+	for _, e := range completed {
+		s.set.OnStorageComplete(e)
+	}
+	for _, e := range expired {
+		s.set.OnExpire(e)
+	}
+	if index != nil {
+		if err := s.client.Set(ctx, s.indexKey(), index); err != nil {
+			s.set.OnStorageError(fmt.Errorf("failed to persist edge index: %w", err))
+			s.mtx.Lock()
+			s.indexDirty = true
+			s.mtx.Unlock()
+		}
+	}
+}
+
+// pendingSync evicts the edges expired at the given time, and returns them along with copies of
+// the new and updated edges, as well as the storage keys of the edges to delete.
+func (s *PersistentStore) pendingSync(now time.Time) ([]*syncedEdge, []string) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	var edges []*syncedEdge
+	for ele := s.l.Front(); ele != nil; {
+		next := ele.Next()
+		pe := ele.Value.(*persistentEdge)
+		switch {
+		case !now.Before(pe.expiration):
+			s.l.Remove(ele)
+			delete(s.m, pe.Key)
+			if pe.stored {
+				s.indexDirty = true
+			}
+			edges = append(edges, &syncedEdge{edge: pe.Edge, stored: pe.stored, expired: true})
+		case pe.version != pe.written:
+			edges = append(edges, &syncedEdge{edge: pe.Edge.clone(), stored: pe.stored, version: pe.version})
+		}
+		ele = next
+	}
+
+	deletes := s.deletes
+	s.deletes = nil
+	return edges, deletes
+}
+
+// applySync applies the outcome of a synchronization to the edges kept in memory, and returns the
+// completed and expired edges, as well as the index to persist if it changed.
+func (s *PersistentStore) applySync(edges []*syncedEdge) (completed, expired []*Edge, index []byte) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	for _, e := range edges {
+		if e.expired {
+			switch e.outcome {
+			case outcomeCompleted:
+				completed = append(completed, e.edge)
+			case outcomeExpired:
+				expired = append(expired, e.edge)
+			}
+			continue
+		}
+
+		ele, ok := s.m[e.edge.Key]
+		if !ok {
+			// Completed in memory during the synchronization.
+			if e.outcome == outcomeWritten {
+				s.deletes = append(s.deletes, edgeStorageKey(e.edge.Key))
+			}
+			continue
+		}
+		pe := ele.Value.(*persistentEdge)
+		switch e.outcome {
+		case outcomeCompleted:
+			completed = append(completed, e.edge)
+			fallthrough
+		case outcomeGone:
+			s.l.Remove(ele)
+			delete(s.m, pe.Key)
+			if pe.stored {
+				s.indexDirty = true
+			}
+		case outcomeWritten:
+			if !pe.stored {
+				pe.stored = true
+				s.indexDirty = true
+			}
+			// An edge updated during the synchronization is written again by the next one.
+			if pe.version == e.version {
+				pe.Edge = e.edge
+				pe.written = e.version
+			}
+		}
+	}
+
+	if !s.indexDirty {
+		return completed, expired, nil
+	}
+	entries := make([]indexEntry, 0, s.l.Len())
+	for ele := s.l.Front(); ele != nil; ele = ele.Next() {
+		if pe := ele.Value.(*persistentEdge); pe.stored {
+			entries = append(entries, indexEntry{
+				TraceID:     pe.Key.tid,
+				SpanID:      pe.Key.sid,
+				Destination: pe.Key.destination,
+				Expiration:  pe.expiration,
+			})
+		}
+	}
+	// The entries only contain IDs and times, they can't fail to be marshaled.
+	index, _ = json.Marshal(entries)
+	s.indexDirty = false
+	return completed, expired, index
+}
+
+// heartbeat records the heartbeat of the store every heartbeatInterval, and garbage collects the
+// indexes of the stores without heartbeat for staleIndexTimeout. heartbeat must be called holding
+// syncMtx.
+func (s *PersistentStore) heartbeat(ctx context.Context) {
+	now := s.now()
+	if now.Sub(s.lastHeartbeat) < heartbeatInterval {
+		return
+	}
+
+	heartbeats := make(map[string]time.Time)
+	data, err := s.client.Get(ctx, heartbeatsKey)
+	if err != nil {
+		s.set.OnStorageError(fmt.Errorf("failed to get heartbeats: %w", err))
+		return
+	}
+	if data != nil {
+		if err := json.Unmarshal(data, &heartbeats); err != nil {
+			s.set.OnStorageError(fmt.Errorf("failed to decode heartbeats: %w", err))
+		}
+	}
+
+	heartbeats[s.set.InstanceID] = now
+	for instanceID, last := range heartbeats {
+		if instanceID == s.set.InstanceID || now.Sub(last) < staleIndexTimeout {
+			continue
+		}
+		if err := s.collectIndex(ctx, instanceID); err != nil {
+			s.set.OnStorageError(err)
+			continue
+		}
+		delete(heartbeats, instanceID)
+	}
+
+	// The heartbeats only contain times, they can't fail to be marshaled.
+	data, _ = json.Marshal(heartbeats)
+	if err := s.client.Set(ctx, heartbeatsKey, data); err != nil {
+		s.set.OnStorageError(fmt.Errorf("failed to set heartbeats: %w", err))
+		return
+	}
+	s.lastHeartbeat = now
+}
+
+// collectIndex expires the edges written by a stopped store, and deletes its index.
+//
+// The heartbeats are updated without atomic read-modify-write, so several stores may collect the
+// same index. The index, then its edges, are therefore read and deleted in the same batches, and
+// only the edges read are expired: provided the batches of the storage client are atomic, as with
+// the Redis and file storages, a single store reads the index and expires each edge.
+func (s *PersistentStore) collectIndex(ctx context.Context, instanceID string) error {
+	indexKey := indexKeyPrefix + instanceID
+	getIndex := storage.GetOperation(indexKey)
+	if err := s.client.Batch(ctx, getIndex, storage.DeleteOperation(indexKey)); err != nil {
+		return fmt.Errorf("failed to collect stale edge index: %w", err)
+	}
+	if getIndex.Value == nil {
+		// Collected by another store.
+		return nil
+	}
+	var entries []indexEntry
+	if err := json.Unmarshal(getIndex.Value, &entries); err != nil {
+		return fmt.Errorf("failed to decode stale edge index: %w", err)
+	}
+
+	ops := make([]*storage.Operation, 0, 2*len(entries))
+	gets := make([]*storage.Operation, len(entries))
+	for i, entry := range entries {
+		key := edgeStorageKey(entry.key())
+		gets[i] = storage.GetOperation(key)
+		ops = append(ops, gets[i], storage.DeleteOperation(key))
+	}
+	if len(ops) > 0 {
+		if err := s.client.Batch(ctx, ops...); err != nil {
+			// The index is restored so that its edges are collected again.
+			if setErr := s.client.Set(ctx, indexKey, getIndex.Value); setErr != nil {
+				err = errors.Join(err, setErr)
+			}
+			return fmt.Errorf("failed to collect stale edges: %w", err)
+		}
+	}
+	for i, entry := range entries {
+		if edge, _ := decodeEdge(entry.key(), gets[i].Value); edge != nil {
+			s.set.OnExpire(edge)
+		}
+	}
+	return nil
+}
+
+func (s *PersistentStore) requeueDeletes(deletes []string) {
+	if len(deletes) == 0 {
+		return
+	}
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	s.deletes = append(s.deletes, deletes...)
+}
+
+// remove must be called holding lock.
+func (s *PersistentStore) remove(ele *list.Element) {
+	pe := ele.Value.(*persistentEdge)
+	s.l.Remove(ele)
+	delete(s.m, pe.Key)
+	if pe.stored {
+		s.deletes = append(s.deletes, edgeStorageKey(pe.Key))
+		s.indexDirty = true
+	}
+}
+
+func (s *PersistentStore) indexKey() string {
+	return indexKeyPrefix + s.set.InstanceID
+}
+
+func (s *PersistentStore) loadIndex(ctx context.Context, indexKey string) ([]indexEntry, error) {
+	data, err := s.client.Get(ctx, indexKey)
+	if err != nil || data == nil {
+		return nil, err
+	}
+	var entries []indexEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("failed to decode edge index: %w", err)
+	}
+	return entries, nil
+}
+
+// decodeEdge decodes an edge read from the storage, nil if there is none.
+func decodeEdge(key Key, data []byte) (*Edge, error) {
+	if data == nil {
+		return nil, nil
+	}
+
+	var se storedEdge
+	if err := json.Unmarshal(data, &se); err != nil {
+		return nil, fmt.Errorf("failed to decode edge: %w", err)
+	}
+	edge := &Edge{
+		Key:                  key,
+		TraceID:              se.TraceID,
+		ConnectionType:       se.ConnectionType,
+		ServerService:        se.ServerService,
+		ClientService:        se.ClientService,
+		ServerLatencySec:     se.ServerLatencySec,
+		ClientLatencySec:     se.ClientLatencySec,
+		Failed:               se.Failed,
+		Dimensions:           se.Dimensions,
+		expiration:           se.Expiration,
+		Peer:                 se.Peer,
+		VirtualNodeLabel:     se.VirtualNodeLabel,
+		MessagingDestination: se.MessagingDestination,
+		ClientEndTimestamp:   pcommon.Timestamp(se.ClientEndTimestamp),
+		ServerStartTimestamp: pcommon.Timestamp(se.ServerStartTimestamp),
+	}
+	if edge.Dimensions == nil {
+		edge.Dimensions = make(map[string]string)
+	}
+	if edge.Peer == nil {
+		edge.Peer = make(map[string]string)
+	}
+	return edge, nil
+}
+
+func encodeEdge(e *Edge) []byte {
+	// The edge only contains strings, numbers and times, it can't fail to be marshaled.
+	data, _ := json.Marshal(storedEdge{
+		TraceID:              e.TraceID,
+		ConnectionType:       e.ConnectionType,
+		ServerService:        e.ServerService,
+		ClientService:        e.ClientService,
+		ServerLatencySec:     e.ServerLatencySec,
+		ClientLatencySec:     e.ClientLatencySec,
+		Failed:               e.Failed,
+		Dimensions:           e.Dimensions,
+		Expiration:           e.expiration,
+		Peer:                 e.Peer,
+		VirtualNodeLabel:     e.VirtualNodeLabel,
+		MessagingDestination: e.MessagingDestination,
+		ClientEndTimestamp:   uint64(e.ClientEndTimestamp),
+		ServerStartTimestamp: uint64(e.ServerStartTimestamp),
+	})
+	return data
+}
+
+func edgeStorageKey(key Key) string {
+	storageKey := edgeKeyPrefix + hex.EncodeToString(key.tid[:]) + hex.EncodeToString(key.sid[:])
+	if key.destination != "" {
+		storageKey += "/" + key.destination
+	}
+	return storageKey
+}
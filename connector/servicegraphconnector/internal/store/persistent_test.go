@@ -0,0 +1,407 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package store
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/extension/xextension/storage"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+)
+
+// mapClient is a storage.Client backed by a map which can be shared by multiple stores.
+type mapClient struct {
+	mtx    sync.Mutex
+	data   map[string][]byte
+	err    error
+	closed bool
+}
+
+func newMapClient() *mapClient {
+	return &mapClient{data: make(map[string][]byte)}
+}
+
+func (c *mapClient) Get(_ context.Context, key string) ([]byte, error) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	return c.data[key], nil
+}
+
+func (c *mapClient) Set(_ context.Context, key string, value []byte) error {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	c.data[key] = value
+	return nil
+}
+
+func (c *mapClient) Delete(_ context.Context, key string) error {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	delete(c.data, key)
+	return nil
+}
+
+// Batch runs the operations atomically, like the Redis and file storages.
+func (c *mapClient) Batch(_ context.Context, ops ...*storage.Operation) error {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	if c.err != nil {
+		return c.err
+	}
+	for _, op := range ops {
+		switch op.Type {
+		case storage.Get:
+			op.Value = c.data[op.Key]
+		case storage.Set:
+			c.data[op.Key] = op.Value
+		case storage.Delete:
+			delete(c.data, op.Key)
+		}
+	}
+	return nil
+}
+
+func (c *mapClient) Close(context.Context) error {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	c.closed = true
+	return nil
+}
+
+func (c *mapClient) setErr(err error) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	c.err = err
+}
+
+func (c *mapClient) has(key string) bool {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	_, ok := c.data[key]
+	return ok
+}
+
+func (c *mapClient) edgeCount() int {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	var n int
+	for k := range c.data {
+		if len(k) > len(edgeKeyPrefix) && k[:len(edgeKeyPrefix)] == edgeKeyPrefix {
+			n++
+		}
+	}
+	return n
+}
+
+// storeRecorder records the edges and errors reported by a PersistentStore.
+type storeRecorder struct {
+	completed, storageCompleted, expired []*Edge
+	errs                                 []error
+}
+
+func newTestPersistentStore(client storage.Client, instanceID string, ttl time.Duration, maxItems int, r *storeRecorder) *PersistentStore {
+	return NewPersistentStore(client, PersistentStoreSettings{
+		InstanceID:        instanceID,
+		TTL:               ttl,
+		MaxItems:          maxItems,
+		OnComplete:        func(e *Edge) { r.completed = append(r.completed, e) },
+		OnStorageComplete: func(e *Edge) { r.storageCompleted = append(r.storageCompleted, e) },
+		OnExpire:          func(e *Edge) { r.expired = append(r.expired, e) },
+		OnStorageError:    func(err error) { r.errs = append(r.errs, err) },
+	})
+}
+
+func TestPersistentStorePairingAcrossStores(t *testing.T) {
+	client := newMapClient()
+	key := NewKey(pcommon.TraceID([16]byte{1, 2, 3}), pcommon.SpanID([8]byte{1, 2, 3}))
+
+	var r storeRecorder
+	first := newTestPersistentStore(client, "first", time.Hour, 10, &r)
+	second := newTestPersistentStore(client, "second", time.Hour, 10, &r)
+
+	// Client span processed by the first collector
+	isNew, err := first.UpsertEdge(key, func(e *Edge) {
+		e.ClientService = clientService
+		e.ClientLatencySec = 1
+		e.Dimensions["client_foo"] = "bar"
+	})
+	require.NoError(t, err)
+	assert.True(t, isNew)
+	assert.Equal(t, 1, first.Len())
+
+	// The storage is only accessed by the synchronization
+	assert.Equal(t, 0, client.edgeCount())
+	first.Expire()
+	assert.Equal(t, 1, first.Len())
+	assert.Equal(t, 1, client.edgeCount())
+
+	// Server span processed by the second collector completes the edge once synchronized
+	isNew, err = second.UpsertEdge(key, func(e *Edge) {
+		e.ServerService = "server"
+		e.ServerLatencySec = 2
+	})
+	require.NoError(t, err)
+	assert.True(t, isNew)
+	second.Expire()
+	assert.Equal(t, 0, second.Len())
+	assert.Equal(t, 0, client.edgeCount())
+
+	assert.Empty(t, r.completed)
+	require.Len(t, r.storageCompleted, 1)
+	assert.Equal(t, key, r.storageCompleted[0].Key)
+	assert.Equal(t, clientService, r.storageCompleted[0].ClientService)
+	assert.Equal(t, "server", r.storageCompleted[0].ServerService)
+	assert.Equal(t, 1.0, r.storageCompleted[0].ClientLatencySec)
+	assert.Equal(t, 2.0, r.storageCompleted[0].ServerLatencySec)
+	assert.Equal(t, map[string]string{"client_foo": "bar"}, r.storageCompleted[0].Dimensions)
+
+	// The edge was completed by the second collector, it is dropped by the first collector
+	// without expiring.
+	first.now = func() time.Time { return time.Now().Add(2 * time.Hour) }
+	first.Expire()
+	assert.Equal(t, 0, first.Len())
+	assert.Empty(t, r.expired)
+	assert.Empty(t, r.errs)
+}
+
+func TestPersistentStoreCompleteInMemory(t *testing.T) {
+	client := newMapClient()
+	key := NewKey(pcommon.TraceID([16]byte{1, 2, 3}), pcommon.SpanID([8]byte{1, 2, 3}))
+
+	var r storeRecorder
+	s := newTestPersistentStore(client, "test", time.Hour, 10, &r)
+
+	_, err := s.UpsertEdge(key, func(e *Edge) {
+		e.ClientService = clientService
+	})
+	require.NoError(t, err)
+	s.Expire()
+	assert.Equal(t, 1, client.edgeCount())
+
+	// The edge is completed without accessing the storage, and deleted by the next synchronization
+	_, err = s.UpsertEdge(key, func(e *Edge) {
+		e.ServerService = "server"
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 0, s.Len())
+	require.Len(t, r.completed, 1)
+	assert.Empty(t, r.storageCompleted)
+	assert.Equal(t, 1, client.edgeCount())
+
+	s.Expire()
+	assert.Equal(t, 0, client.edgeCount())
+	assert.Empty(t, r.expired)
+}
+
+func TestPersistentStoreExpire(t *testing.T) {
+	client := newMapClient()
+	key := NewKey(pcommon.TraceID([16]byte{1, 2, 3}), pcommon.SpanID([8]byte{1, 2, 3}))
+
+	var r storeRecorder
+	s := newTestPersistentStore(client, "test", time.Hour, 10, &r)
+
+	_, err := s.UpsertEdge(key, func(e *Edge) {
+		e.ClientService = clientService
+	})
+	require.NoError(t, err)
+	s.Expire()
+	assert.Equal(t, 1, client.edgeCount())
+	assert.Empty(t, r.expired)
+
+	s.now = func() time.Time { return time.Now().Add(2 * time.Hour) }
+	s.Expire()
+	assert.Equal(t, 0, s.Len())
+	assert.Equal(t, 0, client.edgeCount())
+	assert.Empty(t, r.completed)
+	assert.Empty(t, r.storageCompleted)
+	require.Len(t, r.expired, 1)
+	assert.Equal(t, key, r.expired[0].Key)
+	assert.Equal(t, clientService, r.expired[0].ClientService)
+}
+
+func TestPersistentStoreRestart(t *testing.T) {
+	client := newMapClient()
+	key := NewMessagingKey(pcommon.TraceID([16]byte{1, 2, 3}), pcommon.SpanID([8]byte{1, 2, 3}), "orders")
+
+	var r storeRecorder
+	s := newTestPersistentStore(client, "test", time.Hour, 10, &r)
+	require.NoError(t, s.Start(context.Background()))
+	_, err := s.UpsertEdge(key, func(e *Edge) {
+		e.ClientService = clientService
+	})
+	require.NoError(t, err)
+
+	// The pending edge is written on shutdown
+	require.NoError(t, s.Shutdown(context.Background()))
+	assert.True(t, client.closed)
+	assert.Equal(t, 1, client.edgeCount())
+
+	// The pending edge is loaded and expired by the restarted store
+	s = newTestPersistentStore(client, "test", time.Hour, 10, &r)
+	require.NoError(t, s.Start(context.Background()))
+	assert.Equal(t, 1, s.Len())
+	s.now = func() time.Time { return time.Now().Add(2 * time.Hour) }
+	s.Expire()
+	assert.Equal(t, 0, s.Len())
+	assert.Equal(t, 0, client.edgeCount())
+	require.Len(t, r.expired, 1)
+	assert.Equal(t, key, r.expired[0].Key)
+	assert.Equal(t, clientService, r.expired[0].ClientService)
+	assert.Empty(t, r.completed)
+	assert.Empty(t, r.errs)
+}
+
+func TestPersistentStoreMaxItems(t *testing.T) {
+	s := newTestPersistentStore(newMapClient(), "test", time.Hour, 1, &storeRecorder{})
+
+	_, err := s.UpsertEdge(NewKey(pcommon.TraceID([16]byte{1}), pcommon.SpanID([8]byte{1})), func(e *Edge) {
+		e.ClientService = clientService
+	})
+	require.NoError(t, err)
+
+	_, err = s.UpsertEdge(NewKey(pcommon.TraceID([16]byte{2}), pcommon.SpanID([8]byte{2})), func(e *Edge) {
+		e.ClientService = clientService
+	})
+	assert.ErrorIs(t, err, ErrTooManyItems)
+	assert.Equal(t, 1, s.Len())
+}
+
+func TestPersistentStoreStorageError(t *testing.T) {
+	client := newMapClient()
+	key := NewKey(pcommon.TraceID([16]byte{1, 2, 3}), pcommon.SpanID([8]byte{1, 2, 3}))
+
+	var r storeRecorder
+	s := newTestPersistentStore(client, "test", time.Hour, 10, &r)
+
+	_, err := s.UpsertEdge(key, func(e *Edge) {
+		e.ClientService = clientService
+	})
+	require.NoError(t, err)
+
+	// The edge is kept and written by the next synchronization
+	client.setErr(errors.New("unavailable"))
+	s.Expire()
+	assert.Len(t, r.errs, 1)
+	assert.Equal(t, 1, s.Len())
+	assert.Equal(t, 0, client.edgeCount())
+
+	client.setErr(nil)
+	s.Expire()
+	assert.Len(t, r.errs, 1)
+	assert.Equal(t, 1, s.Len())
+	assert.Equal(t, 1, client.edgeCount())
+}
+
+func TestPersistentStoreCollectStaleIndex(t *testing.T) {
+	client := newMapClient()
+	key := NewKey(pcommon.TraceID([16]byte{1, 2, 3}), pcommon.SpanID([8]byte{1, 2, 3}))
+	now := time.Now()
+
+	// The first collector writes an edge and stops without shutting down
+	var stopped storeRecorder
+	first := newTestPersistentStore(client, "first", time.Hour, 10, &stopped)
+	first.now = func() time.Time { return now }
+	require.NoError(t, first.Start(context.Background()))
+	_, err := first.UpsertEdge(key, func(e *Edge) {
+		e.ClientService = clientService
+	})
+	require.NoError(t, err)
+	first.Expire()
+	assert.Equal(t, 1, client.edgeCount())
+	assert.True(t, client.has(indexKeyPrefix+"first"))
+
+	// The second collector keeps the index of the first one while it is recent
+	var r storeRecorder
+	second := newTestPersistentStore(client, "second", time.Hour, 10, &r)
+	second.now = func() time.Time { return now.Add(staleIndexTimeout / 2) }
+	require.NoError(t, second.Start(context.Background()))
+	assert.Equal(t, 1, client.edgeCount())
+
+	// and expires its edges once stale
+	second.now = func() time.Time { return now.Add(staleIndexTimeout + heartbeatInterval) }
+	second.Expire()
+	assert.Equal(t, 0, client.edgeCount())
+	assert.False(t, client.has(indexKeyPrefix+"first"))
+	require.Len(t, r.expired, 1)
+	assert.Equal(t, clientService, r.expired[0].ClientService)
+	assert.Empty(t, stopped.expired)
+	assert.Empty(t, r.errs)
+}
+
+func TestPersistentStoreCollectStaleIndexOnce(t *testing.T) {
+	client := newMapClient()
+	now := time.Now()
+
+	var stopped storeRecorder
+	first := newTestPersistentStore(client, "first", time.Hour, 10, &stopped)
+	first.now = func() time.Time { return now }
+	require.NoError(t, first.Start(context.Background()))
+	for i := byte(1); i <= 2; i++ {
+		_, err := first.UpsertEdge(NewKey(pcommon.TraceID([16]byte{i}), pcommon.SpanID([8]byte{i})), func(e *Edge) {
+			e.ClientService = clientService
+		})
+		require.NoError(t, err)
+	}
+	first.Expire()
+	assert.Equal(t, 2, client.edgeCount())
+
+	// Both collectors saw the first one as stale, only one of them expires its edges
+	var r1, r2 storeRecorder
+	second := newTestPersistentStore(client, "second", time.Hour, 10, &r1)
+	third := newTestPersistentStore(client, "third", time.Hour, 10, &r2)
+	require.NoError(t, second.collectIndex(context.Background(), "first"))
+	require.NoError(t, third.collectIndex(context.Background(), "first"))
+	assert.Len(t, r1.expired, 2)
+	assert.Empty(t, r2.expired)
+	assert.Equal(t, 0, client.edgeCount())
+	assert.False(t, client.has(indexKeyPrefix+"first"))
+}
+
+func TestPersistentStoreCollectStaleIndexError(t *testing.T) {
+	client := newMapClient()
+	now := time.Now()
+
+	var stopped storeRecorder
+	first := newTestPersistentStore(client, "first", time.Hour, 10, &stopped)
+	first.now = func() time.Time { return now }
+	require.NoError(t, first.Start(context.Background()))
+	_, err := first.UpsertEdge(NewKey(pcommon.TraceID([16]byte{1}), pcommon.SpanID([8]byte{1})), func(e *Edge) {
+		e.ClientService = clientService
+	})
+	require.NoError(t, err)
+	first.Expire()
+
+	// The index is restored when its edges fail to be collected
+	var r storeRecorder
+	second := newTestPersistentStore(client, "second", time.Hour, 10, &r)
+	second.client = &failingEdgesClient{mapClient: client}
+	require.Error(t, second.collectIndex(context.Background(), "first"))
+	assert.Empty(t, r.expired)
+	assert.True(t, client.has(indexKeyPrefix+"first"))
+
+	second.client = client
+	require.NoError(t, second.collectIndex(context.Background(), "first"))
+	assert.Len(t, r.expired, 1)
+}
+
+// failingEdgesClient fails the batches accessing edges.
+type failingEdgesClient struct {
+	*mapClient
+}
+
+func (c *failingEdgesClient) Batch(ctx context.Context, ops ...*storage.Operation) error {
+	for _, op := range ops {
+		if strings.HasPrefix(op.Key, edgeKeyPrefix) {
+			return errors.New("storage unavailable")
+		}
+	}
+	return c.mapClient.Batch(ctx, ops...)
+}
@@ -16,9 +16,25 @@ var ErrTooManyItems = errors.New("too many items")
 
 type Callback func(e *Edge)
 
+// EdgeStore holds edges until both of their spans have been processed, or until they expire.
+type EdgeStore interface {
+	// UpsertEdge fetches an Edge from the store and updates it using the given callback. If the Edge
+	// doesn't exist yet, it creates a new one with the default TTL.
+	// If the Edge is complete after applying the callback, it's completed and removed.
+	UpsertEdge(key Key, update Callback) (isNew bool, err error)
+	// Expire evicts all expired items in the store.
+	Expire()
+	// Len returns the number of items in the store.
+	Len() int
+}
+
+var _ EdgeStore = (*Store)(nil)
+
 type Key struct {
 	tid pcommon.TraceID
 	sid pcommon.SpanID
+	// destination is the messaging destination of the spans of messaging system edges.
+	destination string
 }
 
 func (k *Key) SpanIDIsEmpty() bool {
@@ -29,6 +45,12 @@ func NewKey(tid pcommon.TraceID, sid pcommon.SpanID) Key {
 	return Key{tid: tid, sid: sid}
 }
 
+// NewMessagingKey creates the key of a messaging system edge including the destination, so that the
+// producer and consumer spans are only paired when they send to and receive from the same destination.
+func NewMessagingKey(tid pcommon.TraceID, sid pcommon.SpanID, destination string) Key {
+	return Key{tid: tid, sid: sid, destination: destination}
+}
+
 type Store struct {
 	l   *list.List
 	mtx sync.Mutex
@@ -169,3 +169,28 @@ func countingCallback(counter *int) func(*Edge) {
 		*counter++
 	}
 }
+
+func TestEdgeMessagingQueueTime(t *testing.T) {
+	producerEnd := pcommon.NewTimestampFromTime(time.Unix(10, 0))
+
+	e := newEdge(NewKey(pcommon.TraceID([16]byte{1, 2, 3}), pcommon.SpanID([8]byte{1, 2, 3})), time.Hour)
+	e.ClientEndTimestamp = producerEnd
+	e.ServerStartTimestamp = pcommon.NewTimestampFromTime(time.Unix(12, 0))
+	_, ok := e.MessagingQueueTime()
+	assert.False(t, ok, "queue time is only available for messaging system edges")
+
+	e.ConnectionType = MessagingSystem
+	queueTime, ok := e.MessagingQueueTime()
+	assert.True(t, ok)
+	assert.Equal(t, 2*time.Second, queueTime)
+
+	// Consumer span starting before the producer span ended due to clock skew
+	e.ServerStartTimestamp = pcommon.NewTimestampFromTime(time.Unix(9, 0))
+	queueTime, ok = e.MessagingQueueTime()
+	assert.True(t, ok)
+	assert.Equal(t, time.Duration(0), queueTime)
+
+	e.ServerStartTimestamp = 0
+	_, ok = e.MessagingQueueTime()
+	assert.False(t, ok)
+}
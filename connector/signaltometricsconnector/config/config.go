@@ -17,6 +17,7 @@ import (
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottldatapoint"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottllog"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottlprofile"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottlspan"
 )
 
@@ -42,10 +43,11 @@ type Config struct {
 	Spans      []MetricInfo `mapstructure:"spans"`
 	Datapoints []MetricInfo `mapstructure:"datapoints"`
 	Logs       []MetricInfo `mapstructure:"logs"`
+	Profiles   []MetricInfo `mapstructure:"profiles"`
 }
 
 func (c *Config) Validate() error {
-	if len(c.Spans) == 0 && len(c.Datapoints) == 0 && len(c.Logs) == 0 {
+	if len(c.Spans) == 0 && len(c.Datapoints) == 0 && len(c.Logs) == 0 && len(c.Profiles) == 0 {
 		return fmt.Errorf("no configuration provided, at least one should be specified")
 	}
 	var multiError error // collect all errors at once
@@ -91,6 +93,20 @@ func (c *Config) Validate() error {
 			}
 		}
 	}
+	if len(c.Profiles) > 0 {
+		parser, err := ottlprofile.NewParser(
+			customottl.ProfileFuncs(),
+			component.TelemetrySettings{Logger: zap.NewNop()},
+		)
+		if err != nil {
+			return fmt.Errorf("failed to create parser for OTTL profiles: %w", err)
+		}
+		for _, profile := range c.Profiles {
+			if err := validateMetricInfo(profile, parser); err != nil {
+				multiError = errors.Join(multiError, fmt.Errorf("failed to validate profiles configuration: %w", err))
+			}
+		}
+	}
 	return multiError
 }
 
@@ -116,6 +132,10 @@ func (c *Config) Unmarshal(collectorCfg *confmap.Conf) error {
 		info.ensureDefaults()
 		c.Logs[i] = info
 	}
+	for i, info := range c.Profiles {
+		info.ensureDefaults()
+		c.Profiles[i] = info
+	}
 	return nil
 }
 
@@ -140,6 +160,28 @@ type Sum struct {
 	Value string `mapstructure:"value"`
 }
 
+// GaugeAggregation defines how multiple values recorded for the same gauge
+// datapoint are reduced to a single value.
+type GaugeAggregation string
+
+const (
+	// GaugeAggregationLast keeps the last observed value.
+	GaugeAggregationLast GaugeAggregation = "last"
+	// GaugeAggregationMin keeps the minimum observed value.
+	GaugeAggregationMin GaugeAggregation = "min"
+	// GaugeAggregationMax keeps the maximum observed value.
+	GaugeAggregationMax GaugeAggregation = "max"
+)
+
+// Gauge configures a gauge metric. Value is the OTTL expression whose result
+// is recorded by the gauge.
+type Gauge struct {
+	Value string `mapstructure:"value"`
+	// Aggregation determines the value reported by the gauge when more than
+	// one value is observed for the same datapoint. Defaults to `last`.
+	Aggregation GaugeAggregation `mapstructure:"aggregation"`
+}
+
 // MetricInfo defines the structure of the metric produced by the connector.
 type MetricInfo struct {
 	Name        string `mapstructure:"name"`
@@ -158,6 +200,7 @@ type MetricInfo struct {
 	Histogram            *Histogram            `mapstructure:"histogram"`
 	ExponentialHistogram *ExponentialHistogram `mapstructure:"exponential_histogram"`
 	Sum                  *Sum                  `mapstructure:"sum"`
+	Gauge                *Gauge                `mapstructure:"gauge"`
 }
 
 func (mi *MetricInfo) ensureDefaults() {
@@ -172,6 +215,11 @@ func (mi *MetricInfo) ensureDefaults() {
 			mi.ExponentialHistogram.MaxSize = defaultExponentialHistogramMaxSize
 		}
 	}
+	if mi.Gauge != nil {
+		if mi.Gauge.Aggregation == "" {
+			mi.Gauge.Aggregation = GaugeAggregationLast
+		}
+	}
 }
 
 func (mi *MetricInfo) validateAttributes() error {
@@ -223,6 +271,20 @@ func (mi *MetricInfo) validateSum() error {
 	return nil
 }
 
+func (mi *MetricInfo) validateGauge() error {
+	if mi.Gauge != nil {
+		if mi.Gauge.Value == "" {
+			return errors.New("value must be defined for gauge metrics")
+		}
+		switch mi.Gauge.Aggregation {
+		case GaugeAggregationLast, GaugeAggregationMin, GaugeAggregationMax:
+		default:
+			return fmt.Errorf("invalid gauge aggregation %q, must be one of last, min or max", mi.Gauge.Aggregation)
+		}
+	}
+	return nil
+}
+
 // validateMetricInfo is an utility method validate all supported metric
 // types defined for the metric info including any ottl expressions.
 func validateMetricInfo[K any](mi MetricInfo, parser ottl.Parser[K]) error {
@@ -238,6 +300,9 @@ func validateMetricInfo[K any](mi MetricInfo, parser ottl.Parser[K]) error {
 	if err := mi.validateSum(); err != nil {
 		return fmt.Errorf("sum validation failed: %w", err)
 	}
+	if err := mi.validateGauge(); err != nil {
+		return fmt.Errorf("gauge validation failed: %w", err)
+	}
 
 	// Exactly one metric should be defined
 	var (
@@ -262,6 +327,10 @@ func validateMetricInfo[K any](mi MetricInfo, parser ottl.Parser[K]) error {
 		metricsDefinedCount++
 		statements = append(statements, customottl.ConvertToStatement(mi.Sum.Value))
 	}
+	if mi.Gauge != nil {
+		metricsDefinedCount++
+		statements = append(statements, customottl.ConvertToStatement(mi.Gauge.Value))
+	}
 	if metricsDefinedCount != 1 {
 		return fmt.Errorf("exactly one of the metrics must be defined, %d found", metricsDefinedCount)
 	}
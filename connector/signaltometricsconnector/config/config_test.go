@@ -74,6 +74,14 @@ func TestConfig(t *testing.T) {
 				fullErrorForSignal(t, "logs", "sum validation failed"),
 			},
 		},
+		{
+			path: "invalid_gauge",
+			errorMsgs: []string{
+				fullErrorForSignal(t, "spans", "gauge validation failed"),
+				fullErrorForSignal(t, "datapoints", "gauge validation failed"),
+				fullErrorForSignal(t, "logs", "gauge validation failed"),
+			},
+		},
 		{
 			path: "multiple_metric",
 			errorMsgs: []string{
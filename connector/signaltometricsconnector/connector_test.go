@@ -15,10 +15,12 @@ import (
 	"go.opentelemetry.io/collector/confmap/confmaptest"
 	"go.opentelemetry.io/collector/connector"
 	"go.opentelemetry.io/collector/connector/connectortest"
+	"go.opentelemetry.io/collector/connector/xconnector"
 	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/consumer/consumertest"
 	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/pprofile"
 	semconv "go.opentelemetry.io/collector/semconv/v1.26.0"
 	"go.uber.org/zap/zapcore"
 	"go.uber.org/zap/zaptest"
@@ -100,6 +102,7 @@ func TestConnectorWithLogs(t *testing.T) {
 		"sum",
 		"histograms",
 		"exponential_histograms",
+		"gauge",
 	}
 
 	ctx, cancel := context.WithCancel(context.Background())
@@ -127,6 +130,32 @@ func TestConnectorWithLogs(t *testing.T) {
 	}
 }
 
+func TestConnectorWithProfiles(t *testing.T) {
+	testCases := []string{
+		"sum",
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	for _, tc := range testCases {
+		t.Run(tc, func(t *testing.T) {
+			next := &consumertest.MetricsSink{}
+			tcTestDataDir := filepath.Join(testDataDir, "profiles", tc)
+			factory, settings, cfg := setupConnector(t, tcTestDataDir)
+			connector, err := factory.(xconnector.Factory).CreateProfilesToMetrics(ctx, settings, cfg, next)
+			require.NoError(t, err)
+			require.IsType(t, &signalToMetrics{}, connector)
+			expectedMetrics, err := golden.ReadMetrics(filepath.Join(tcTestDataDir, "output.yaml"))
+			require.NoError(t, err)
+
+			require.NoError(t, connector.ConsumeProfiles(ctx, testProfiles()))
+			require.Len(t, next.AllMetrics(), 1)
+			assertAggregatedMetrics(t, expectedMetrics, next.AllMetrics()[0])
+		})
+	}
+}
+
 func BenchmarkConnectorWithTraces(b *testing.B) {
 	factory := NewFactory()
 	settings := connectortest.NewNopSettings()
@@ -321,3 +350,46 @@ func assertAggregatedMetrics(t *testing.T, expected, actual pmetric.Metrics) {
 		pmetrictest.IgnoreTimestamp(),
 	))
 }
+
+// testProfiles creates a single CPU profile with four samples. Samples are
+// attributed to the `main` and `leaf` functions and to two threads, with the
+// last sample missing the thread attribute.
+func testProfiles() pprofile.Profiles {
+	profiles := pprofile.NewProfiles()
+	rp := profiles.ResourceProfiles().AppendEmpty()
+	rp.Resource().Attributes().PutStr("resource.foo", "foo")
+	profile := rp.ScopeProfiles().AppendEmpty().Profiles().AppendEmpty()
+	profile.StringTable().Append("", "cpu", "nanoseconds", "main", "leaf")
+	sampleType := profile.SampleType().AppendEmpty()
+	sampleType.SetTypeStrindex(1)
+	sampleType.SetUnitStrindex(2)
+	profile.FunctionTable().AppendEmpty().SetNameStrindex(3)
+	profile.FunctionTable().AppendEmpty().SetNameStrindex(4)
+	profile.LocationTable().AppendEmpty().Line().AppendEmpty().SetFunctionIndex(0)
+	profile.LocationTable().AppendEmpty().Line().AppendEmpty().SetFunctionIndex(1)
+	// Stacks: [main] at index 0 and [leaf, main] at index 1.
+	profile.LocationIndices().Append(0, 1, 0)
+	for _, thread := range []string{"worker-1", "worker-2"} {
+		attr := profile.AttributeTable().AppendEmpty()
+		attr.SetKey("thread.name")
+		attr.Value().SetStr(thread)
+	}
+
+	for _, s := range []struct {
+		start, length int32
+		value         int64
+		attrs         []int32
+	}{
+		{start: 0, length: 1, value: 100, attrs: []int32{0}},
+		{start: 1, length: 2, value: 200, attrs: []int32{0}},
+		{start: 1, length: 2, value: 400, attrs: []int32{1}},
+		{start: 0, length: 1, value: 800},
+	} {
+		sample := profile.Sample().AppendEmpty()
+		sample.SetLocationsStartIndex(s.start)
+		sample.SetLocationsLength(s.length)
+		sample.Value().Append(s.value)
+		sample.AttributeIndices().Append(s.attrs...)
+	}
+	return profiles
+}
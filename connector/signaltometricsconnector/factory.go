@@ -9,6 +9,7 @@ import (
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/connector"
+	"go.opentelemetry.io/collector/connector/xconnector"
 	"go.opentelemetry.io/collector/consumer"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/connector/signaltometricsconnector/config"
@@ -17,17 +18,19 @@ import (
 	"github.com/open-telemetry/opentelemetry-collector-contrib/connector/signaltometricsconnector/internal/model"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottldatapoint"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottllog"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottlprofile"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottlspan"
 )
 
 // NewFactory returns a ConnectorFactory.
 func NewFactory() connector.Factory {
-	return connector.NewFactory(
+	return xconnector.NewFactory(
 		metadata.Type,
 		createDefaultConfig,
-		connector.WithTracesToMetrics(createTracesToMetrics, metadata.TracesToMetricsStability),
-		connector.WithMetricsToMetrics(createMetricsToMetrics, metadata.MetricsToMetricsStability),
-		connector.WithLogsToMetrics(createLogsToMetrics, metadata.LogsToMetricsStability),
+		xconnector.WithTracesToMetrics(createTracesToMetrics, metadata.TracesToMetricsStability),
+		xconnector.WithMetricsToMetrics(createMetricsToMetrics, metadata.MetricsToMetricsStability),
+		xconnector.WithLogsToMetrics(createLogsToMetrics, metadata.LogsToMetricsStability),
+		xconnector.WithProfilesToMetrics(createProfilesToMetrics, metadata.ProfilesToMetricsStability),
 	)
 }
 
@@ -127,3 +130,34 @@ func createLogsToMetrics(
 		logMetricDefs: metricDefs,
 	}, nil
 }
+
+func createProfilesToMetrics(
+	_ context.Context,
+	set connector.Settings,
+	cfg component.Config,
+	nextConsumer consumer.Metrics,
+) (xconnector.Profiles, error) {
+	c := cfg.(*config.Config)
+	parser, err := ottlprofile.NewParser(customottl.ProfileFuncs(), set.TelemetrySettings)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTTL statement parser for profiles: %w", err)
+	}
+
+	metricDefs := make([]model.MetricDef[ottlprofile.TransformContext], 0, len(c.Profiles))
+	for _, info := range c.Profiles {
+		var md model.MetricDef[ottlprofile.TransformContext]
+		if err := md.FromMetricInfo(info, parser, set.TelemetrySettings); err != nil {
+			return nil, fmt.Errorf("failed to parse provided metric information; %w", err)
+		}
+		metricDefs = append(metricDefs, md)
+	}
+
+	return &signalToMetrics{
+		logger: set.Logger,
+		collectorInstanceInfo: model.NewCollectorInstanceInfo(
+			set.TelemetrySettings,
+		),
+		next:              nextConsumer,
+		profileMetricDefs: metricDefs,
+	}, nil
+}
@@ -15,9 +15,11 @@ require (
 	go.opentelemetry.io/collector/confmap v1.24.0
 	go.opentelemetry.io/collector/connector v0.118.0
 	go.opentelemetry.io/collector/connector/connectortest v0.118.0
+	go.opentelemetry.io/collector/connector/xconnector v0.118.0
 	go.opentelemetry.io/collector/consumer v1.24.0
 	go.opentelemetry.io/collector/consumer/consumertest v0.118.0
 	go.opentelemetry.io/collector/pdata v1.24.0
+	go.opentelemetry.io/collector/pdata/pprofile v0.118.0
 	go.opentelemetry.io/collector/pipeline v0.118.0
 	go.opentelemetry.io/collector/semconv v0.118.0
 	go.uber.org/goleak v1.3.0
@@ -55,10 +57,8 @@ require (
 	github.com/pmezard/go-difflib v1.0.0 // indirect
 	github.com/ua-parser/uap-go v0.0.0-20240611065828-3a4781585db6 // indirect
 	go.opentelemetry.io/collector/config/configtelemetry v0.118.0 // indirect
-	go.opentelemetry.io/collector/connector/xconnector v0.118.0 // indirect
 	go.opentelemetry.io/collector/consumer/xconsumer v0.118.0 // indirect
 	go.opentelemetry.io/collector/internal/fanoutconsumer v0.118.0 // indirect
-	go.opentelemetry.io/collector/pdata/pprofile v0.118.0 // indirect
 	go.opentelemetry.io/collector/pipeline/xpipeline v0.118.0 // indirect
 	go.opentelemetry.io/otel v1.32.0 // indirect
 	go.opentelemetry.io/otel/metric v1.32.0 // indirect
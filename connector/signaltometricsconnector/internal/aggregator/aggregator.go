@@ -27,6 +27,7 @@ type Aggregator[K any] struct {
 	smLookup    map[[16]byte]pmetric.ScopeMetrics
 	valueCounts map[model.MetricKey]map[[16]byte]map[[16]byte]*valueCountDP
 	sums        map[model.MetricKey]map[[16]byte]map[[16]byte]*sumDP
+	gauges      map[model.MetricKey]map[[16]byte]map[[16]byte]*gaugeDP
 	timestamp   time.Time
 }
 
@@ -37,6 +38,7 @@ func NewAggregator[K any](metrics pmetric.Metrics) *Aggregator[K] {
 		smLookup:    make(map[[16]byte]pmetric.ScopeMetrics),
 		valueCounts: make(map[model.MetricKey]map[[16]byte]map[[16]byte]*valueCountDP),
 		sums:        make(map[model.MetricKey]map[[16]byte]map[[16]byte]*sumDP),
+		gauges:      make(map[model.MetricKey]map[[16]byte]map[[16]byte]*gaugeDP),
 		timestamp:   time.Now(),
 	}
 }
@@ -87,6 +89,22 @@ func (a *Aggregator[K]) Aggregate(
 				v, v,
 			)
 		}
+	case md.Gauge != nil:
+		raw, _, err := md.Gauge.Value.Execute(ctx, tCtx)
+		if err != nil {
+			return fmt.Errorf("failed to execute OTTL value for gauge: %w", err)
+		}
+		switch v := raw.(type) {
+		case int64:
+			a.getGaugeDP(md, resAttrs, srcAttrs, false).AggregateInt(v)
+		case float64:
+			a.getGaugeDP(md, resAttrs, srcAttrs, true).AggregateDouble(v)
+		default:
+			return fmt.Errorf(
+				"failed to parse gauge OTTL value of type %T into int64 or float64: %v",
+				v, v,
+			)
+		}
 	}
 	return nil
 }
@@ -145,11 +163,27 @@ func (a *Aggregator[K]) Finalize(mds []model.MetricDef[K]) {
 				dp.Copy(a.timestamp, destCounter.DataPoints().AppendEmpty())
 			}
 		}
+		for resID, dpMap := range a.gauges[md.Key] {
+			if md.Gauge == nil {
+				continue
+			}
+			metrics := a.smLookup[resID].Metrics()
+			destMetric := metrics.AppendEmpty()
+			destMetric.SetName(md.Key.Name)
+			destMetric.SetDescription(md.Key.Description)
+			destMetric.SetUnit(md.Unit)
+			destGauge := destMetric.SetEmptyGauge()
+			destGauge.DataPoints().EnsureCapacity(len(dpMap))
+			for _, dp := range dpMap {
+				dp.Copy(a.timestamp, destGauge.DataPoints().AppendEmpty())
+			}
+		}
 		// If there are two metric defined with the same key required by metricKey
 		// then they will be aggregated within the same metric and produced
 		// together. Deleting the key ensures this while preventing duplicates.
 		delete(a.valueCounts, md.Key)
 		delete(a.sums, md.Key)
+		delete(a.gauges, md.Key)
 	}
 }
 
@@ -193,6 +227,28 @@ func (a *Aggregator[K]) aggregateDouble(
 	return nil
 }
 
+// getGaugeDP returns the gauge datapoint for the given metric definition and
+// attributes, creating it if it does not exist. The value type of a gauge
+// datapoint is decided by the first value recorded for it.
+func (a *Aggregator[K]) getGaugeDP(
+	md model.MetricDef[K],
+	resAttrs, srcAttrs pcommon.Map,
+	isDbl bool,
+) *gaugeDP {
+	resID := a.getResourceID(resAttrs)
+	attrID := pdatautil.MapHash(srcAttrs)
+	if _, ok := a.gauges[md.Key]; !ok {
+		a.gauges[md.Key] = make(map[[16]byte]map[[16]byte]*gaugeDP)
+	}
+	if _, ok := a.gauges[md.Key][resID]; !ok {
+		a.gauges[md.Key][resID] = make(map[[16]byte]*gaugeDP)
+	}
+	if _, ok := a.gauges[md.Key][resID][attrID]; !ok {
+		a.gauges[md.Key][resID][attrID] = newGaugeDP(srcAttrs, md.Gauge.Aggregation, isDbl)
+	}
+	return a.gauges[md.Key][resID][attrID]
+}
+
 func (a *Aggregator[K]) aggregateValueCount(
 	md model.MetricDef[K],
 	resAttrs, srcAttrs pcommon.Map,
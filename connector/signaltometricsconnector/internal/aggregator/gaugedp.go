@@ -0,0 +1,84 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package aggregator // import "github.com/open-telemetry/opentelemetry-collector-contrib/connector/signaltometricsconnector/internal/aggregator"
+
+import (
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/connector/signaltometricsconnector/config"
+)
+
+// gaugeDP records the last, minimum or maximum value observed for a
+// datapoint depending on the configured aggregation.
+type gaugeDP struct {
+	attrs       pcommon.Map
+	aggregation config.GaugeAggregation
+
+	isDbl  bool
+	seen   bool
+	intVal int64
+	dblVal float64
+}
+
+func newGaugeDP(attrs pcommon.Map, aggregation config.GaugeAggregation, isDbl bool) *gaugeDP {
+	return &gaugeDP{
+		attrs:       attrs,
+		aggregation: aggregation,
+		isDbl:       isDbl,
+	}
+}
+
+func (dp *gaugeDP) AggregateInt(v int64) {
+	if dp.isDbl {
+		dp.AggregateDouble(float64(v))
+		return
+	}
+	if !dp.seen || shouldReplace(dp.aggregation, v, dp.intVal) {
+		dp.intVal = v
+	}
+	dp.seen = true
+}
+
+// AggregateDouble records a double value. If the datapoint was created for
+// integer values then it is promoted to a double datapoint since, unlike
+// sums, a gauge computed from logs or spans may see mixed value types.
+func (dp *gaugeDP) AggregateDouble(v float64) {
+	if !dp.isDbl {
+		dp.isDbl = true
+		dp.dblVal = float64(dp.intVal)
+	}
+	if !dp.seen || shouldReplace(dp.aggregation, v, dp.dblVal) {
+		dp.dblVal = v
+	}
+	dp.seen = true
+}
+
+// shouldReplace reports whether the value replaces the current one. Integers
+// are compared as such, converting them to doubles would lose precision.
+func shouldReplace[T int64 | float64](aggregation config.GaugeAggregation, v, current T) bool {
+	switch aggregation {
+	case config.GaugeAggregationMin:
+		return v < current
+	case config.GaugeAggregationMax:
+		return v > current
+	default:
+		return true
+	}
+}
+
+func (dp *gaugeDP) Copy(
+	timestamp time.Time,
+	dest pmetric.NumberDataPoint,
+) {
+	dp.attrs.CopyTo(dest.Attributes())
+	if dp.isDbl {
+		dest.SetDoubleValue(dp.dblVal)
+	} else {
+		dest.SetIntValue(dp.intVal)
+	}
+	dest.SetTimestamp(pcommon.NewTimestampFromTime(timestamp))
+}
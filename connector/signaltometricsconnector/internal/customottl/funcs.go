@@ -7,6 +7,7 @@ import (
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottldatapoint"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottllog"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottlprofile"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottlspan"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/ottlfuncs"
 )
@@ -26,6 +27,10 @@ func LogFuncs() map[string]ottl.Factory[ottllog.TransformContext] {
 	return commonFuncs[ottllog.TransformContext]()
 }
 
+func ProfileFuncs() map[string]ottl.Factory[ottlprofile.TransformContext] {
+	return commonFuncs[ottlprofile.TransformContext]()
+}
+
 func commonFuncs[K any]() map[string]ottl.Factory[K] {
 	getFactory := NewGetFactory[K]()
 	standard := ottlfuncs.StandardFuncs[K]()
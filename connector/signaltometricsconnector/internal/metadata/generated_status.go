@@ -12,7 +12,8 @@ var (
 )
 
 const (
-	TracesToMetricsStability  = component.StabilityLevelDevelopment
-	LogsToMetricsStability    = component.StabilityLevelDevelopment
-	MetricsToMetricsStability = component.StabilityLevelDevelopment
+	TracesToMetricsStability   = component.StabilityLevelDevelopment
+	LogsToMetricsStability     = component.StabilityLevelDevelopment
+	MetricsToMetricsStability  = component.StabilityLevelDevelopment
+	ProfilesToMetricsStability = component.StabilityLevelDevelopment
 )
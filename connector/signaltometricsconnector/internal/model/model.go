@@ -103,6 +103,28 @@ func (s *Sum[K]) fromConfig(
 	return nil
 }
 
+type Gauge[K any] struct {
+	Value       *ottl.Statement[K]
+	Aggregation config.GaugeAggregation
+}
+
+func (g *Gauge[K]) fromConfig(
+	mi *config.Gauge,
+	parser ottl.Parser[K],
+) error {
+	if mi == nil {
+		return nil
+	}
+
+	var err error
+	g.Aggregation = mi.Aggregation
+	g.Value, err = parser.ParseStatement(customottl.ConvertToStatement(mi.Value))
+	if err != nil {
+		return fmt.Errorf("failed to parse value statement for gauge: %w", err)
+	}
+	return nil
+}
+
 type MetricDef[K any] struct {
 	Key                       MetricKey
 	Unit                      string
@@ -112,6 +134,7 @@ type MetricDef[K any] struct {
 	ExponentialHistogram      *ExponentialHistogram[K]
 	ExplicitHistogram         *ExplicitHistogram[K]
 	Sum                       *Sum[K]
+	Gauge                     *Gauge[K]
 }
 
 func (md *MetricDef[K]) FromMetricInfo(
@@ -162,6 +185,12 @@ func (md *MetricDef[K]) FromMetricInfo(
 			return fmt.Errorf("failed to parse sum config: %w", err)
 		}
 	}
+	if mi.Gauge != nil {
+		md.Gauge = new(Gauge[K])
+		if err := md.Gauge.fromConfig(mi.Gauge, parser); err != nil {
+			return fmt.Errorf("failed to parse gauge config: %w", err)
+		}
+	}
 	return nil
 }
 
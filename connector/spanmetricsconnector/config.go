@@ -84,6 +84,9 @@ type Config struct {
 
 	// Events defines the configuration for events section of spans.
 	Events EventsConfig `mapstructure:"events"`
+
+	// Extrapolation defines the configuration for weighting spans by their sampling adjusted count.
+	Extrapolation ExtrapolationConfig `mapstructure:"extrapolation"`
 }
 
 type HistogramConfig struct {
@@ -114,6 +117,15 @@ type EventsConfig struct {
 	Dimensions []Dimension `mapstructure:"dimensions"`
 }
 
+type ExtrapolationConfig struct {
+	// Enabled is a flag to weight the calls, duration and events metrics by the adjusted count of each span,
+	// i.e. the inverse of the probability with which the span was sampled.
+	Enabled bool `mapstructure:"enabled"`
+	// AdjustedCountAttributes defines the list of span attributes that are checked, in order, for the adjusted
+	// count of spans which do not carry an OpenTelemetry sampling threshold in their tracestate.
+	AdjustedCountAttributes []string `mapstructure:"adjusted_count_attributes"`
+}
+
 var _ component.ConfigValidator = (*Config)(nil)
 
 // Validate checks if the processor configuration is valid
@@ -144,6 +156,12 @@ func (c Config) Validate() error {
 		return fmt.Errorf("invalid metrics_expiration: %v, the duration should be positive", c.MetricsExpiration)
 	}
 
+	for _, attr := range c.Extrapolation.AdjustedCountAttributes {
+		if attr == "" {
+			return errors.New("invalid extrapolation adjusted_count_attributes: attribute name must not be empty")
+		}
+	}
+
 	if c.GetAggregationTemporality() == pmetric.AggregationTemporalityDelta && c.GetDeltaTimestampCacheSize() <= 0 {
 		return fmt.Errorf(
 			"invalid delta timestamp cache size: %v, the maximum number of the items in the cache should be positive",
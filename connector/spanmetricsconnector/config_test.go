@@ -164,6 +164,25 @@ func TestLoadConfig(t *testing.T) {
 			id:           component.NewIDWithName(metadata.Type, "invalid_delta_timestamp_cache_size"),
 			errorMessage: "invalid delta timestamp cache size: 0, the maximum number of the items in the cache should be positive",
 		},
+		{
+			id: component.NewIDWithName(metadata.Type, "extrapolation_enabled"),
+			expected: &Config{
+				AggregationTemporality:   "AGGREGATION_TEMPORALITY_CUMULATIVE",
+				DimensionsCacheSize:      defaultDimensionsCacheSize,
+				ResourceMetricsCacheSize: defaultResourceMetricsCacheSize,
+				MetricsFlushInterval:     60 * time.Second,
+				Histogram:                HistogramConfig{Disable: false, Unit: defaultUnit},
+				Namespace:                DefaultNamespace,
+				Extrapolation: ExtrapolationConfig{
+					Enabled:                 true,
+					AdjustedCountAttributes: []string{"sampling.adjusted_count"},
+				},
+			},
+		},
+		{
+			id:           component.NewIDWithName(metadata.Type, "invalid_extrapolation_attribute"),
+			errorMessage: "invalid extrapolation adjusted_count_attributes: attribute name must not be empty",
+		},
 	}
 
 	for _, tt := range tests {
@@ -404,18 +404,30 @@ func (p *connectorImp) aggregateMetrics(traces ptrace.Traces) {
 					attributes = p.buildAttributes(serviceName, span, resourceAttr, p.dimensions)
 					p.metricKeyToDimensions.Add(key, attributes)
 				}
+				adjustedCount := float64(1)
+				if p.config.Extrapolation.Enabled {
+					adjustedCount = p.adjustedCount(span)
+				}
 				if !p.config.Histogram.Disable {
 					// aggregate histogram metrics
 					h := histograms.GetOrCreate(key, attributes)
 					p.addExemplar(span, duration, h)
-					h.Observe(duration)
+					if p.config.Extrapolation.Enabled {
+						h.ObserveAdjusted(duration, adjustedCount)
+					} else {
+						h.Observe(duration)
+					}
 				}
 				// aggregate sums metrics
 				s := sums.GetOrCreate(key, attributes)
 				if p.config.Exemplars.Enabled && !span.TraceID().IsEmpty() {
 					s.AddExemplar(span.TraceID(), span.SpanID(), duration)
 				}
-				s.Add(1)
+				if p.config.Extrapolation.Enabled {
+					s.AddAdjusted(adjustedCount)
+				} else {
+					s.Add(1)
+				}
 
 				// aggregate events metrics
 				if p.events.Enabled {
@@ -439,7 +451,11 @@ func (p *connectorImp) aggregateMetrics(traces ptrace.Traces) {
 						if p.config.Exemplars.Enabled && !span.TraceID().IsEmpty() {
 							e.AddExemplar(span.TraceID(), span.SpanID(), duration)
 						}
-						e.Add(1)
+						if p.config.Extrapolation.Enabled {
+							e.AddAdjusted(adjustedCount)
+						} else {
+							e.Add(1)
+						}
 					}
 				}
 			}
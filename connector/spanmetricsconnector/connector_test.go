@@ -1875,3 +1875,109 @@ func (c alwaysIncreasingClock) Now() time.Time {
 	c.Clock.(clockwork.FakeClock).Advance(time.Millisecond)
 	return c.Clock.Now()
 }
+
+func TestSpanMetrics_Extrapolation(t *testing.T) {
+	buildTraces := func() ptrace.Traces {
+		traces := ptrace.NewTraces()
+		rs := traces.ResourceSpans().AppendEmpty()
+		rs.Resource().Attributes().PutStr(serviceNameKey, "service-a")
+		spans := rs.ScopeSpans().AppendEmpty().Spans()
+		for _, s := range []struct {
+			traceState    string
+			adjustedCount any
+			priority      any
+		}{
+			{traceState: "ot=th:8"},                       // 50% probability
+			{traceState: "ot=th:c"},                       // 25% probability
+			{adjustedCount: int64(10)},                    // no threshold, adjusted count attribute
+			{traceState: "ot=th:c", priority: int64(1)},   // sampled by priority
+			{traceState: "invalid", adjustedCount: "str"}, // invalid sampling information
+			{}, // no sampling information
+		} {
+			span := spans.AppendEmpty()
+			span.SetName("operation")
+			span.SetStartTimestamp(pcommon.NewTimestampFromTime(time.Unix(0, 0)))
+			span.SetEndTimestamp(pcommon.NewTimestampFromTime(time.Unix(0, int64(time.Millisecond))))
+			span.TraceState().FromRaw(s.traceState)
+			if s.adjustedCount != nil {
+				require.NoError(t, span.Attributes().PutEmpty("sampling.adjusted_count").FromRaw(s.adjustedCount))
+			}
+			if s.priority != nil {
+				require.NoError(t, span.Attributes().PutEmpty(samplingPriorityKey).FromRaw(s.priority))
+			}
+			event := span.Events().AppendEmpty()
+			event.SetName("event")
+		}
+		return traces
+	}
+
+	tests := []struct {
+		name          string
+		extrapolation ExtrapolationConfig
+		histogram     func() HistogramConfig
+		expectedCount int64
+	}{
+		{
+			name:          "disabled",
+			histogram:     explicitHistogramsConfig,
+			expectedCount: 6,
+		},
+		{
+			name: "enabled explicit histogram",
+			extrapolation: ExtrapolationConfig{
+				Enabled:                 true,
+				AdjustedCountAttributes: []string{"sampling.adjusted_count"},
+			},
+			histogram:     explicitHistogramsConfig,
+			expectedCount: 19,
+		},
+		{
+			name: "enabled exponential histogram",
+			extrapolation: ExtrapolationConfig{
+				Enabled:                 true,
+				AdjustedCountAttributes: []string{"sampling.adjusted_count"},
+			},
+			histogram:     exponentialHistogramsConfig,
+			expectedCount: 19,
+		},
+		{
+			name:          "enabled without adjusted count attributes",
+			extrapolation: ExtrapolationConfig{Enabled: true},
+			histogram:     explicitHistogramsConfig,
+			expectedCount: 10,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			factory := NewFactory()
+			cfg := factory.CreateDefaultConfig().(*Config)
+			cfg.Histogram = tt.histogram()
+			cfg.Events = EventsConfig{Enabled: true, Dimensions: []Dimension{{Name: "event.name", Default: stringp("unknown")}}}
+			cfg.Extrapolation = tt.extrapolation
+			c, err := newConnector(zaptest.NewLogger(t), cfg, clockwork.NewFakeClock())
+			require.NoError(t, err)
+			require.NoError(t, c.ConsumeTraces(context.Background(), buildTraces()))
+
+			metrics := c.buildMetrics()
+			require.Equal(t, 1, metrics.ResourceMetrics().Len())
+			ms := metrics.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics()
+			require.Equal(t, 3, ms.Len())
+			for i := 0; i < ms.Len(); i++ {
+				metric := ms.At(i)
+				switch metric.Type() {
+				case pmetric.MetricTypeSum:
+					require.Equal(t, 1, metric.Sum().DataPoints().Len())
+					assert.Equal(t, tt.expectedCount, metric.Sum().DataPoints().At(0).IntValue(), metric.Name())
+				case pmetric.MetricTypeHistogram:
+					require.Equal(t, 1, metric.Histogram().DataPoints().Len())
+					assert.Equal(t, uint64(tt.expectedCount), metric.Histogram().DataPoints().At(0).Count())
+				case pmetric.MetricTypeExponentialHistogram:
+					require.Equal(t, 1, metric.ExponentialHistogram().DataPoints().Len())
+					assert.Equal(t, uint64(tt.expectedCount), metric.ExponentialHistogram().DataPoints().At(0).Count())
+				default:
+					t.Fatalf("unexpected metric type %s", metric.Type())
+				}
+			}
+		})
+	}
+}
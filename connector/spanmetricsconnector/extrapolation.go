@@ -0,0 +1,60 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package spanmetricsconnector // import "github.com/open-telemetry/opentelemetry-collector-contrib/connector/spanmetricsconnector"
+
+import (
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/sampling"
+)
+
+// samplingPriorityKey is the OpenTracing convention used by samplers to mark
+// spans which were sampled irrespective of the configured probability.
+const samplingPriorityKey = "sampling.priority"
+
+// adjustedCount returns the number of spans in the population the span is
+// representative of. It is resolved, in order of precedence, from:
+//   - the `sampling.priority` attribute, a positive priority means the span
+//     was sampled unconditionally and represents only itself.
+//   - the OpenTelemetry sampling threshold (`ot=th:...`) of the W3C tracestate.
+//   - the first of the configured adjusted count attributes with a positive
+//     numeric value.
+//
+// Spans without any sampling information have an adjusted count of 1.
+func (p *connectorImp) adjustedCount(span ptrace.Span) float64 {
+	if v, ok := span.Attributes().Get(samplingPriorityKey); ok {
+		if priority, ok := numericValue(v); ok && priority > 0 {
+			return 1
+		}
+	}
+
+	if raw := span.TraceState().AsRaw(); raw != "" {
+		if w3c, err := sampling.NewW3CTraceState(raw); err == nil {
+			if count := w3c.OTelValue().AdjustedCount(); count > 0 {
+				return count
+			}
+		}
+	}
+
+	for _, attr := range p.config.Extrapolation.AdjustedCountAttributes {
+		if v, ok := span.Attributes().Get(attr); ok {
+			if count, ok := numericValue(v); ok && count > 0 {
+				return count
+			}
+		}
+	}
+	return 1
+}
+
+func numericValue(v pcommon.Value) (float64, bool) {
+	switch v.Type() {
+	case pcommon.ValueTypeInt:
+		return float64(v.Int()), true
+	case pcommon.ValueTypeDouble:
+		return v.Double(), true
+	default:
+		return 0, false
+	}
+}
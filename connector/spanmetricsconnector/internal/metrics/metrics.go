@@ -4,6 +4,7 @@
 package metrics // import "github.com/open-telemetry/opentelemetry-collector-contrib/connector/spanmetricsconnector/internal/metrics"
 
 import (
+	"math"
 	"sort"
 
 	"github.com/lightstep/go-expohisto/structure"
@@ -21,6 +22,9 @@ type HistogramMetrics interface {
 
 type Histogram interface {
 	Observe(value float64)
+	// ObserveAdjusted records the value as representative of adjustedCount
+	// observations, e.g. the inverse of the probability a span was sampled with.
+	ObserveAdjusted(value float64, adjustedCount float64)
 	AddExemplar(traceID pcommon.TraceID, spanID pcommon.SpanID, value float64)
 }
 
@@ -46,6 +50,8 @@ type explicitHistogram struct {
 
 	bounds []float64
 
+	adjusted adjustedCounter
+
 	maxExemplarCount *int
 }
 
@@ -55,9 +61,26 @@ type exponentialHistogram struct {
 
 	histogram *structure.Histogram[float64]
 
+	adjusted adjustedCounter
+
 	maxExemplarCount *int
 }
 
+// adjustedCounter converts fractional adjusted counts into the integer counts
+// required by the data model. The fractional remainder is carried over to
+// the next observation so that the reported counts converge on the
+// extrapolated total instead of accumulating rounding errors.
+type adjustedCounter struct {
+	remainder float64
+}
+
+func (c *adjustedCounter) next(adjustedCount float64) uint64 {
+	c.remainder += adjustedCount
+	n := math.Floor(c.remainder)
+	c.remainder -= n
+	return uint64(n)
+}
+
 type generateStartTimestamp = func(Key) pcommon.Timestamp
 
 func NewExponentialHistogramMetrics(maxSize int32, maxExemplarCount *int) HistogramMetrics {
@@ -212,6 +235,18 @@ func (h *explicitHistogram) Observe(value float64) {
 	h.bucketCounts[index]++
 }
 
+func (h *explicitHistogram) ObserveAdjusted(value float64, adjustedCount float64) {
+	n := h.adjusted.next(adjustedCount)
+	if n == 0 {
+		return
+	}
+	h.sum += value * float64(n)
+	h.count += n
+
+	index := sort.SearchFloat64s(h.bounds, value)
+	h.bucketCounts[index] += n
+}
+
 func (h *explicitHistogram) AddExemplar(traceID pcommon.TraceID, spanID pcommon.SpanID, value float64) {
 	if h.maxExemplarCount != nil && h.exemplars.Len() >= *h.maxExemplarCount {
 		return
@@ -226,6 +261,12 @@ func (h *exponentialHistogram) Observe(value float64) {
 	h.histogram.Update(value)
 }
 
+func (h *exponentialHistogram) ObserveAdjusted(value float64, adjustedCount float64) {
+	if n := h.adjusted.next(adjustedCount); n > 0 {
+		h.histogram.UpdateByIncr(value, n)
+	}
+}
+
 func (h *exponentialHistogram) AddExemplar(traceID pcommon.TraceID, spanID pcommon.SpanID, value float64) {
 	if h.maxExemplarCount != nil && h.exemplars.Len() >= *h.maxExemplarCount {
 		return
@@ -239,6 +280,7 @@ func (h *exponentialHistogram) AddExemplar(traceID pcommon.TraceID, spanID pcomm
 type Sum struct {
 	attributes       pcommon.Map
 	count            uint64
+	adjusted         adjustedCounter
 	exemplars        pmetric.ExemplarSlice
 	maxExemplarCount *int
 }
@@ -247,6 +289,12 @@ func (s *Sum) Add(value uint64) {
 	s.count += value
 }
 
+// AddAdjusted increments the sum by the adjusted count, carrying over any
+// fractional remainder to the next call.
+func (s *Sum) AddAdjusted(adjustedCount float64) {
+	s.count += s.adjusted.next(adjustedCount)
+}
+
 func NewSumMetrics(maxExemplarCount *int) SumMetrics {
 	return SumMetrics{
 		metrics:          make(map[Key]*Sum),
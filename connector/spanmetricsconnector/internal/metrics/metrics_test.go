@@ -287,3 +287,54 @@ func TestExponentialHistogram_AddExemplar(t *testing.T) {
 		})
 	}
 }
+
+func TestAdjustedCounter(t *testing.T) {
+	var c adjustedCounter
+	var total uint64
+	for i := 0; i < 3; i++ {
+		total += c.next(10.0 / 3)
+	}
+	assert.Equal(t, uint64(10), total)
+	assert.Equal(t, uint64(0), c.next(0.5))
+	assert.Equal(t, uint64(1), c.next(0.5))
+}
+
+func TestExplicitHistogram_ObserveAdjusted(t *testing.T) {
+	hm := NewExplicitHistogramMetrics([]float64{1, 10}, nil)
+	h := hm.GetOrCreate("key", pcommon.NewMap())
+	h.Observe(0.5)
+	h.ObserveAdjusted(5, 4)
+	h.ObserveAdjusted(20, 2.5)
+
+	metric := pmetric.NewMetric()
+	hm.BuildMetrics(metric, func(Key) pcommon.Timestamp { return 0 }, 0, pmetric.AggregationTemporalityCumulative)
+	dp := metric.Histogram().DataPoints().At(0)
+	assert.Equal(t, uint64(7), dp.Count())
+	assert.Equal(t, 60.5, dp.Sum())
+	assert.Equal(t, []uint64{1, 4, 2}, dp.BucketCounts().AsRaw())
+}
+
+func TestExponentialHistogram_ObserveAdjusted(t *testing.T) {
+	hm := NewExponentialHistogramMetrics(160, nil)
+	h := hm.GetOrCreate("key", pcommon.NewMap())
+	h.Observe(2)
+	h.ObserveAdjusted(4, 4)
+
+	metric := pmetric.NewMetric()
+	hm.BuildMetrics(metric, func(Key) pcommon.Timestamp { return 0 }, 0, pmetric.AggregationTemporalityCumulative)
+	dp := metric.ExponentialHistogram().DataPoints().At(0)
+	assert.Equal(t, uint64(5), dp.Count())
+	assert.Equal(t, 18.0, dp.Sum())
+}
+
+func TestSum_AddAdjusted(t *testing.T) {
+	sm := NewSumMetrics(nil)
+	s := sm.GetOrCreate("key", pcommon.NewMap())
+	s.Add(1)
+	s.AddAdjusted(1.5)
+	s.AddAdjusted(1.5)
+
+	metric := pmetric.NewMetric()
+	sm.BuildMetrics(metric, func(Key) pcommon.Timestamp { return 0 }, 0, pmetric.AggregationTemporalityCumulative)
+	assert.Equal(t, int64(4), metric.Sum().DataPoints().At(0).IntValue())
+}
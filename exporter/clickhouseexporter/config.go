@@ -14,6 +14,7 @@ import (
 	"go.opentelemetry.io/collector/config/configopaque"
 	"go.opentelemetry.io/collector/config/configretry"
 	"go.opentelemetry.io/collector/exporter/exporterhelper"
+	"go.opentelemetry.io/collector/pdata/pcommon"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/clickhouseexporter/internal"
 )
@@ -36,8 +37,12 @@ type Config struct {
 	ConnectionParams map[string]string `mapstructure:"connection_params"`
 	// LogsTableName is the table name for logs. default is `otel_logs`.
 	LogsTableName string `mapstructure:"logs_table_name"`
+	// LogsColumns defines the columns and indexes added to the logs table.
+	LogsColumns internal.ColumnsConfig `mapstructure:"logs_columns"`
 	// TracesTableName is the table name for traces. default is `otel_traces`.
 	TracesTableName string `mapstructure:"traces_table_name"`
+	// TracesColumns defines the columns and indexes added to the traces table.
+	TracesColumns internal.ColumnsConfig `mapstructure:"traces_columns"`
 	// MetricsTableName is the table name for metrics. default is `otel_metrics`.
 	//
 	// Deprecated: MetricsTableName exists for historical compatibility
@@ -60,6 +65,9 @@ type Config struct {
 	AsyncInsert bool `mapstructure:"async_insert"`
 	// MetricsTables defines the table names for metric types.
 	MetricsTables MetricTablesConfig `mapstructure:"metrics_tables"`
+	// AttributesType is the type of the attributes columns of the logs and traces tables, `map` or `json`. default is `map`.
+	// The `json` type keeps the types and the nested structure of the attribute values.
+	AttributesType string `mapstructure:"attributes_type"`
 }
 
 type MetricTablesConfig struct {
@@ -90,11 +98,15 @@ const (
 	defaultSummarySuffix      = "_summary"
 	defaultHistogramSuffix    = "_histogram"
 	defaultExpHistogramSuffix = "_exponential_histogram"
+
+	attributesTypeMap  = "map"
+	attributesTypeJSON = "json"
 )
 
 var (
 	errConfigNoEndpoint      = errors.New("endpoint must be specified")
 	errConfigInvalidEndpoint = errors.New("endpoint must be url format")
+	errConfigAttributesType  = errors.New("attributes_type must be either map or json")
 )
 
 // Validate the ClickHouse server configuration.
@@ -107,8 +119,24 @@ func (cfg *Config) Validate() (err error) {
 		err = errors.Join(err, e)
 	}
 
+	switch cfg.AttributesType {
+	case "", attributesTypeMap, attributesTypeJSON:
+	default:
+		err = errors.Join(err, errConfigAttributesType)
+	}
+
 	cfg.buildMetricTableNames()
 
+	if e := cfg.LogsColumns.ValidateBuiltinColumns(createLogsTableSQL); e != nil {
+		err = errors.Join(err, fmt.Errorf("logs_columns: %w", e))
+	}
+	if e := cfg.TracesColumns.ValidateBuiltinColumns(createTracesTableSQL); e != nil {
+		err = errors.Join(err, fmt.Errorf("traces_columns: %w", e))
+	}
+	if e := internal.ValidateMetricsBuiltinColumns(generateMetricTablesConfigMapper(cfg)); e != nil {
+		err = errors.Join(err, fmt.Errorf("metrics_tables: %w", e))
+	}
+
 	// Validate DSN with clickhouse driver.
 	// Last chance to catch invalid config.
 	if _, e := clickhouse.ParseDSN(dsn); e != nil {
@@ -216,6 +244,22 @@ func (cfg *Config) areMetricTableNamesSet() bool {
 		len(cfg.MetricsTables.ExponentialHistogram.Name) != 0
 }
 
+// attributesToValue converts attributes to the value of an attributes column of the logs and traces tables.
+func (cfg *Config) attributesToValue(attributes pcommon.Map) any {
+	if cfg.AttributesType == attributesTypeJSON {
+		return internal.AttributesToJSON(attributes)
+	}
+	return internal.AttributesToMap(attributes)
+}
+
+// attributesType returns the type of the attributes columns of the logs and traces tables, `map` by default.
+func (cfg *Config) attributesType() string {
+	if cfg.AttributesType == "" {
+		return attributesTypeMap
+	}
+	return cfg.AttributesType
+}
+
 // tableEngineString generates the ENGINE string.
 func (cfg *Config) tableEngineString() string {
 	engine := cfg.TableEngine.Name
@@ -83,6 +83,31 @@ func TestLoadConfig(t *testing.T) {
 				AsyncInsert: true,
 			},
 		},
+		{
+			id: component.NewIDWithName(metadata.Type, "columns"),
+			expected: withDefaultConfig(func(cfg *Config) {
+				cfg.Endpoint = defaultEndpoint
+				cfg.LogsColumns = internal.ColumnsConfig{
+					Attributes: []internal.AttributeColumnConfig{
+						{Name: "TenantId", Key: "tenant_id", Source: internal.AttributeSourceResource, Type: "LowCardinality(String)"},
+						{Name: "HttpRoute", Key: "http.route", Codec: "LZ4"},
+					},
+					Indexes: []internal.IndexConfig{
+						{Name: "idx_http_route", Expression: "HttpRoute", Type: "bloom_filter(0.01)"},
+					},
+				}
+				cfg.TracesColumns = internal.ColumnsConfig{
+					Attributes: []internal.AttributeColumnConfig{
+						{Name: "HttpStatusCode", Key: "http.response.status_code", Type: "Nullable(Int64)"},
+					},
+				}
+				cfg.MetricsTables.Gauge.Columns = internal.ColumnsConfig{
+					Attributes: []internal.AttributeColumnConfig{
+						{Name: "TenantId", Key: "tenant_id", Source: internal.AttributeSourceResource},
+					},
+				}
+			}),
+		},
 	}
 
 	for _, tt := range tests {
@@ -234,6 +259,7 @@ func TestConfig_buildDSN(t *testing.T) {
 		Compress         string
 		ConnectionParams map[string]string
 		AsyncInsert      *bool
+		AttributesType   string
 	}
 	mergeConfigWithFields := func(cfg *Config, fields fields) {
 		if fields.Endpoint != "" {
@@ -257,6 +283,9 @@ func TestConfig_buildDSN(t *testing.T) {
 		if fields.AsyncInsert != nil {
 			cfg.AsyncInsert = *fields.AsyncInsert
 		}
+		if fields.AttributesType != "" {
+			cfg.AttributesType = fields.AttributesType
+		}
 	}
 
 	type ChOptions struct {
@@ -485,6 +514,23 @@ func TestConfig_buildDSN(t *testing.T) {
 			},
 			want: "tcp://127.0.0.1:9000/default?async_insert=true&compress=br",
 		},
+		{
+			name: "json attributes leave the JSON type setting to connection_params",
+			fields: fields{
+				Endpoint:       "tcp://127.0.0.1:9000",
+				AttributesType: "json",
+			},
+			want: "tcp://127.0.0.1:9000/default?async_insert=true&compress=lz4",
+		},
+		{
+			name: "connection_params enables the JSON type",
+			fields: fields{
+				Endpoint:         "tcp://127.0.0.1:9000",
+				ConnectionParams: map[string]string{"allow_experimental_json_type": "1"},
+				AttributesType:   "json",
+			},
+			want: "tcp://127.0.0.1:9000/default?allow_experimental_json_type=1&async_insert=true&compress=lz4",
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -509,6 +555,38 @@ func TestConfig_buildDSN(t *testing.T) {
 	}
 }
 
+func TestConfig_ValidateAttributesType(t *testing.T) {
+	for _, attributesType := range []string{"", "map", "json"} {
+		cfg := withDefaultConfig(func(cfg *Config) {
+			cfg.Endpoint = defaultEndpoint
+			cfg.AttributesType = attributesType
+		})
+		require.NoError(t, cfg.Validate())
+	}
+
+	cfg := withDefaultConfig(func(cfg *Config) {
+		cfg.Endpoint = defaultEndpoint
+		cfg.AttributesType = "variant"
+	})
+	require.ErrorIs(t, cfg.Validate(), errConfigAttributesType)
+}
+
+func TestConfig_ValidateBuiltinColumns(t *testing.T) {
+	cfg := withDefaultConfig(func(cfg *Config) {
+		cfg.Endpoint = defaultEndpoint
+		cfg.LogsColumns.Attributes = []internal.AttributeColumnConfig{{Name: "TimestampTime", Key: "time"}}
+		cfg.TracesColumns.Attributes = []internal.AttributeColumnConfig{{Name: "ServiceName", Key: "service.name"}}
+		cfg.MetricsTables.Gauge.Columns.Attributes = []internal.AttributeColumnConfig{{Name: "MetricName", Key: "name"}}
+		cfg.MetricsTables.Sum.Columns.Attributes = []internal.AttributeColumnConfig{{Name: "TenantId", Key: "tenant_id"}}
+	})
+
+	err := cfg.Validate()
+	require.ErrorContains(t, err, `logs_columns: column "TimestampTime" collides with a built-in column`)
+	require.ErrorContains(t, err, `traces_columns: column "ServiceName" collides with a built-in column`)
+	require.ErrorContains(t, err, `metrics_tables: table otel_metrics_gauge: column "MetricName" collides with a built-in column`)
+	require.NotContains(t, err.Error(), "TenantId")
+}
+
 func TestShouldCreateSchema(t *testing.T) {
 	t.Parallel()
 
@@ -21,12 +21,18 @@ import (
 type logsExporter struct {
 	client    *sql.DB
 	insertSQL string
+	columns   internal.ColumnValues
 
 	logger *zap.Logger
 	cfg    *Config
 }
 
 func newLogsExporter(logger *zap.Logger, cfg *Config) (*logsExporter, error) {
+	columns, err := cfg.LogsColumns.NewColumnValues()
+	if err != nil {
+		return nil, err
+	}
+
 	client, err := newClickhouseClient(cfg)
 	if err != nil {
 		return nil, err
@@ -35,6 +41,7 @@ func newLogsExporter(logger *zap.Logger, cfg *Config) (*logsExporter, error) {
 	return &logsExporter{
 		client:    client,
 		insertSQL: renderInsertLogsSQL(cfg),
+		columns:   columns,
 		logger:    logger,
 		cfg:       cfg,
 	}, nil
@@ -49,7 +56,7 @@ func (e *logsExporter) start(ctx context.Context, _ component.Host) error {
 		return err
 	}
 
-	return createLogsTable(ctx, e.cfg, e.client)
+	return createLogsTable(ctx, e.logger, e.cfg, e.client)
 }
 
 // shutdown will shut down the exporter.
@@ -75,7 +82,7 @@ func (e *logsExporter) pushLogsData(ctx context.Context, ld plog.Logs) error {
 			logs := ld.ResourceLogs().At(i)
 			res := logs.Resource()
 			resURL := logs.SchemaUrl()
-			resAttr := internal.AttributesToMap(res.Attributes())
+			resAttr := e.cfg.attributesToValue(res.Attributes())
 			serviceName := internal.GetServiceName(res.Attributes())
 
 			for j := 0; j < logs.ScopeLogs().Len(); j++ {
@@ -83,7 +90,7 @@ func (e *logsExporter) pushLogsData(ctx context.Context, ld plog.Logs) error {
 				scopeURL := logs.ScopeLogs().At(j).SchemaUrl()
 				scopeName := logs.ScopeLogs().At(j).Scope().Name()
 				scopeVersion := logs.ScopeLogs().At(j).Scope().Version()
-				scopeAttr := internal.AttributesToMap(logs.ScopeLogs().At(j).Scope().Attributes())
+				scopeAttr := e.cfg.attributesToValue(logs.ScopeLogs().At(j).Scope().Attributes())
 
 				for k := 0; k < rs.Len(); k++ {
 					r := rs.At(k)
@@ -93,8 +100,8 @@ func (e *logsExporter) pushLogsData(ctx context.Context, ld plog.Logs) error {
 						timestamp = r.ObservedTimestamp()
 					}
 
-					logAttr := internal.AttributesToMap(r.Attributes())
-					_, err = statement.ExecContext(ctx,
+					logAttr := e.cfg.attributesToValue(r.Attributes())
+					args := []any{
 						timestamp.AsTime(),
 						traceutil.TraceIDToHexOrEmptyString(r.TraceID()),
 						traceutil.SpanIDToHexOrEmptyString(r.SpanID()),
@@ -110,7 +117,9 @@ func (e *logsExporter) pushLogsData(ctx context.Context, ld plog.Logs) error {
 						scopeVersion,
 						scopeAttr,
 						logAttr,
-					)
+					}
+					args = e.columns.AppendValues(args, res.Attributes(), logs.ScopeLogs().At(j).Scope().Attributes(), r.Attributes())
+					_, err = statement.ExecContext(ctx, args...)
 					if err != nil {
 						return fmt.Errorf("ExecContext:%w", err)
 					}
@@ -144,7 +153,7 @@ CREATE TABLE IF NOT EXISTS %s %s (
 	ScopeName String CODEC(ZSTD(1)),
 	ScopeVersion LowCardinality(String) CODEC(ZSTD(1)),
 	ScopeAttributes Map(LowCardinality(String), String) CODEC(ZSTD(1)),
-	LogAttributes Map(LowCardinality(String), String) CODEC(ZSTD(1)),
+	LogAttributes Map(LowCardinality(String), String) CODEC(ZSTD(1)),%s
 
 	INDEX idx_trace_id TraceId TYPE bloom_filter(0.001) GRANULARITY 1,
 	INDEX idx_res_attr_key mapKeys(ResourceAttributes) TYPE bloom_filter(0.01) GRANULARITY 1,
@@ -153,7 +162,39 @@ CREATE TABLE IF NOT EXISTS %s %s (
 	INDEX idx_scope_attr_value mapValues(ScopeAttributes) TYPE bloom_filter(0.01) GRANULARITY 1,
 	INDEX idx_log_attr_key mapKeys(LogAttributes) TYPE bloom_filter(0.01) GRANULARITY 1,
 	INDEX idx_log_attr_value mapValues(LogAttributes) TYPE bloom_filter(0.01) GRANULARITY 1,
-	INDEX idx_body Body TYPE tokenbf_v1(32768, 3, 0) GRANULARITY 8
+	INDEX idx_body Body TYPE tokenbf_v1(32768, 3, 0) GRANULARITY 8%s
+) ENGINE = %s
+PARTITION BY toDate(TimestampTime)
+PRIMARY KEY (ServiceName, TimestampTime)
+ORDER BY (ServiceName, TimestampTime, Timestamp)
+%s
+SETTINGS index_granularity = 8192, ttl_only_drop_parts = 1;
+`
+	// language=ClickHouse SQL
+	createLogsJSONTableSQL = `
+CREATE TABLE IF NOT EXISTS %s %s (
+	Timestamp DateTime64(9) CODEC(Delta(8), ZSTD(1)),
+	TimestampTime DateTime DEFAULT toDateTime(Timestamp),
+	TraceId String CODEC(ZSTD(1)),
+	SpanId String CODEC(ZSTD(1)),
+	TraceFlags UInt8,
+	SeverityText LowCardinality(String) CODEC(ZSTD(1)),
+	SeverityNumber UInt8,
+	ServiceName LowCardinality(String) CODEC(ZSTD(1)),
+	Body String CODEC(ZSTD(1)),
+	ResourceSchemaUrl LowCardinality(String) CODEC(ZSTD(1)),
+	ResourceAttributes JSON CODEC(ZSTD(1)),
+	ScopeSchemaUrl LowCardinality(String) CODEC(ZSTD(1)),
+	ScopeName String CODEC(ZSTD(1)),
+	ScopeVersion LowCardinality(String) CODEC(ZSTD(1)),
+	ScopeAttributes JSON CODEC(ZSTD(1)),
+	LogAttributes JSON CODEC(ZSTD(1)),%s
+
+	INDEX idx_trace_id TraceId TYPE bloom_filter(0.001) GRANULARITY 1,
+	INDEX idx_res_attr_paths JSONAllPaths(ResourceAttributes) TYPE bloom_filter(0.01) GRANULARITY 1,
+	INDEX idx_scope_attr_paths JSONAllPaths(ScopeAttributes) TYPE bloom_filter(0.01) GRANULARITY 1,
+	INDEX idx_log_attr_paths JSONAllPaths(LogAttributes) TYPE bloom_filter(0.01) GRANULARITY 1,
+	INDEX idx_body Body TYPE tokenbf_v1(32768, 3, 0) GRANULARITY 8%s
 ) ENGINE = %s
 PARTITION BY toDate(TimestampTime)
 PRIMARY KEY (ServiceName, TimestampTime)
@@ -177,7 +218,7 @@ SETTINGS index_granularity = 8192, ttl_only_drop_parts = 1;
                         ScopeName,
                         ScopeVersion,
                         ScopeAttributes,
-                        LogAttributes
+                        LogAttributes%s
                         ) VALUES (
                                   ?,
                                   ?,
@@ -193,7 +234,7 @@ SETTINGS index_granularity = 8192, ttl_only_drop_parts = 1;
                                   ?,
                                   ?,
                                   ?,
-                                  ?
+                                  ?%s
                                   )`
 )
 
@@ -229,20 +270,45 @@ func createDatabase(ctx context.Context, cfg *Config) error {
 	return nil
 }
 
-func createLogsTable(ctx context.Context, cfg *Config, db *sql.DB) error {
-	if _, err := db.ExecContext(ctx, renderCreateLogsTableSQL(cfg)); err != nil {
-		return fmt.Errorf("exec create logs table sql: %w", err)
+func createLogsTable(ctx context.Context, logger *zap.Logger, cfg *Config, db *sql.DB) error {
+	if err := checkAttributesType(ctx, cfg, db, cfg.LogsTableName, "LogAttributes"); err != nil {
+		return err
+	}
+	if err := migrateTable(ctx, logger, cfg, db, cfg.LogsTableName, logsMigrations(cfg)); err != nil {
+		return err
+	}
+	if query := cfg.LogsColumns.AlterSQL(cfg.LogsTableName, cfg.clusterString()); query != "" {
+		if _, err := db.ExecContext(ctx, query); err != nil {
+			return fmt.Errorf("exec alter logs table columns sql: %w", err)
+		}
 	}
 	return nil
 }
 
+// logsMigrations are the versions of the schema of the logs table.
+func logsMigrations(cfg *Config) []migration {
+	return []migration{
+		{
+			version:     1,
+			description: "create logs table",
+			statements:  []string{renderCreateLogsTableSQL(cfg)},
+			columns:     internal.TableColumnNames(createLogsTableSQL),
+		},
+	}
+}
+
 func renderCreateLogsTableSQL(cfg *Config) string {
 	ttlExpr := generateTTLExpr(cfg.TTL, "TimestampTime")
-	return fmt.Sprintf(createLogsTableSQL, cfg.LogsTableName, cfg.clusterString(), cfg.tableEngineString(), ttlExpr)
+	query := createLogsTableSQL
+	if cfg.AttributesType == attributesTypeJSON {
+		query = createLogsJSONTableSQL
+	}
+	return fmt.Sprintf(query, cfg.LogsTableName, cfg.clusterString(),
+		cfg.LogsColumns.ColumnsSQL(), cfg.LogsColumns.IndexesSQL(), cfg.tableEngineString(), ttlExpr)
 }
 
 func renderInsertLogsSQL(cfg *Config) string {
-	return fmt.Sprintf(insertLogsSQLTemplate, cfg.LogsTableName)
+	return fmt.Sprintf(insertLogsSQLTemplate, cfg.LogsTableName, cfg.LogsColumns.InsertColumnsSQL(), cfg.LogsColumns.InsertPlaceholdersSQL())
 }
 
 func doWithTx(_ context.Context, db *sql.DB, fn func(tx *sql.Tx) error) error {
@@ -8,6 +8,7 @@ import (
 	"database/sql"
 	"database/sql/driver"
 	"errors"
+	"io"
 	"strings"
 	"testing"
 	"time"
@@ -19,6 +20,8 @@ import (
 	conventions "go.opentelemetry.io/collector/semconv/v1.27.0"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zaptest"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/clickhouseexporter/internal"
 )
 
 func TestLogsExporter_New(t *testing.T) {
@@ -48,7 +51,7 @@ func TestLogsExporter_New(t *testing.T) {
 	}{
 		"no dsn": {
 			config: withDefaultConfig(),
-			want:   failWithMsg("exec create logs table sql: parse dsn address failed"),
+			want:   failWithMsg("read type of column LogAttributes of table otel_logs: parse dsn address failed"),
 		},
 	}
 
@@ -142,6 +145,70 @@ func TestExporter_pushLogsData(t *testing.T) {
 		exporter := newTestLogsExporter(t, defaultEndpoint)
 		mustPushLogsData(t, exporter, multipleLogsWithDifferentServiceName(1))
 	})
+	t.Run("test attribute columns", func(t *testing.T) {
+		var created, inserted bool
+		initClickhouseTestServer(t, func(query string, values []driver.Value) error {
+			if strings.HasPrefix(strings.TrimSpace(query), "CREATE TABLE") {
+				require.Contains(t, query, "\tServiceNamespace LowCardinality(String) CODEC(ZSTD(1)),\n\tLib String CODEC(LZ4),\n")
+				require.Contains(t, query, "INDEX idx_service_namespace ServiceNamespace TYPE set(100) GRANULARITY 1\n) ENGINE")
+				created = true
+			}
+			if strings.HasPrefix(query, "INSERT") {
+				require.Contains(t, query, "LogAttributes,\n\tServiceNamespace,\n\tLib")
+				require.Len(t, values, 17)
+				require.Equal(t, "default", values[15])
+				require.Equal(t, "clickhouse", values[16])
+				inserted = true
+			}
+			return nil
+		})
+
+		exporter := newTestLogsExporter(t, defaultEndpoint, func(cfg *Config) {
+			cfg.LogsColumns = internal.ColumnsConfig{
+				Attributes: []internal.AttributeColumnConfig{
+					{Name: "ServiceNamespace", Key: conventions.AttributeServiceNamespace, Type: "LowCardinality(String)"},
+					{Name: "Lib", Key: "lib", Source: internal.AttributeSourceScope, Codec: "LZ4"},
+				},
+				Indexes: []internal.IndexConfig{
+					{Name: "idx_service_namespace", Expression: "ServiceNamespace", Type: "set(100)"},
+				},
+			}
+		})
+		mustPushLogsData(t, exporter, simpleLogs(1))
+		require.True(t, created)
+		require.True(t, inserted)
+	})
+	t.Run("test json attributes", func(t *testing.T) {
+		var created, inserted bool
+		initClickhouseTestServer(t, func(query string, values []driver.Value) error {
+			if strings.HasPrefix(strings.TrimSpace(query), "CREATE TABLE") {
+				require.Contains(t, query, "\tResourceAttributes JSON CODEC(ZSTD(1)),\n")
+				require.Contains(t, query, "\tLogAttributes JSON CODEC(ZSTD(1)),\n")
+				require.Contains(t, query, "INDEX idx_log_attr_paths JSONAllPaths(LogAttributes)")
+				created = true
+			}
+			if strings.HasPrefix(query, "INSERT") {
+				require.Equal(t, `{"service.name":"test-service"}`, values[9])
+				require.Equal(t, `{"lib":"clickhouse"}`, values[13])
+				require.Equal(t, `{"http.response.status_code":200,"retry":{"attempt":2,"backoff":1.5},"service.namespace":"default"}`, values[14])
+				inserted = true
+			}
+			return nil
+		})
+
+		exporter := newTestLogsExporter(t, defaultEndpoint, func(cfg *Config) {
+			cfg.AttributesType = attributesTypeJSON
+		})
+		logs := simpleLogs(1)
+		attrs := logs.ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(0).Attributes()
+		attrs.PutInt("http.response.status_code", 200)
+		retry := attrs.PutEmptyMap("retry")
+		retry.PutInt("attempt", 2)
+		retry.PutDouble("backoff", 1.5)
+		mustPushLogsData(t, exporter, logs)
+		require.True(t, created)
+		require.True(t, inserted)
+	})
 }
 
 func TestLogsClusterConfig(t *testing.T) {
@@ -259,32 +326,44 @@ func mustPushLogsData(t *testing.T, exporter *logsExporter, ld plog.Logs) {
 }
 
 func initClickhouseTestServer(t *testing.T, recorder recorder) {
+	initClickhouseTestServerWithQuerier(t, recorder, nil)
+}
+
+func initClickhouseTestServerWithQuerier(t *testing.T, recorder recorder, querier querier) {
 	driverName = t.Name()
 	sql.Register(t.Name(), &testClickhouseDriver{
 		recorder: recorder,
+		querier:  querier,
 	})
 }
 
 type recorder func(query string, values []driver.Value) error
 
+// querier returns the rows of a query.
+type querier func(query string, values []driver.Value) [][]driver.Value
+
 type testClickhouseDriver struct {
 	recorder recorder
+	querier  querier
 }
 
 func (t *testClickhouseDriver) Open(_ string) (driver.Conn, error) {
 	return &testClickhouseDriverConn{
 		recorder: t.recorder,
+		querier:  t.querier,
 	}, nil
 }
 
 type testClickhouseDriverConn struct {
 	recorder recorder
+	querier  querier
 }
 
 func (t *testClickhouseDriverConn) Prepare(query string) (driver.Stmt, error) {
 	return &testClickhouseDriverStmt{
 		query:    query,
 		recorder: t.recorder,
+		querier:  t.querier,
 	}, nil
 }
 
@@ -303,6 +382,7 @@ func (*testClickhouseDriverConn) CheckNamedValue(_ *driver.NamedValue) error {
 type testClickhouseDriverStmt struct {
 	query    string
 	recorder recorder
+	querier  querier
 }
 
 func (*testClickhouseDriverStmt) Close() error {
@@ -317,8 +397,36 @@ func (t *testClickhouseDriverStmt) Exec(args []driver.Value) (driver.Result, err
 	return nil, t.recorder(t.query, args)
 }
 
-func (t *testClickhouseDriverStmt) Query(_ []driver.Value) (driver.Rows, error) {
-	return nil, nil
+func (t *testClickhouseDriverStmt) Query(args []driver.Value) (driver.Rows, error) {
+	rows := &testClickhouseDriverRows{}
+	if t.querier != nil {
+		rows.rows = t.querier(t.query, args)
+	}
+	return rows, nil
+}
+
+type testClickhouseDriverRows struct {
+	rows [][]driver.Value
+}
+
+func (r *testClickhouseDriverRows) Columns() []string {
+	if len(r.rows) == 0 {
+		return nil
+	}
+	return make([]string, len(r.rows[0]))
+}
+
+func (*testClickhouseDriverRows) Close() error {
+	return nil
+}
+
+func (r *testClickhouseDriverRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+	return nil
 }
 
 type testClickhouseDriverTx struct{}
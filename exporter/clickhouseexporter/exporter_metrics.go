@@ -22,21 +22,27 @@ type metricsExporter struct {
 	logger       *zap.Logger
 	cfg          *Config
 	tablesConfig internal.MetricTablesConfigMapper
+	columnValues map[pmetric.MetricType]internal.ColumnValues
 }
 
 func newMetricsExporter(logger *zap.Logger, cfg *Config) (*metricsExporter, error) {
-	client, err := newClickhouseClient(cfg)
+	tablesConfig := generateMetricTablesConfigMapper(cfg)
+	columnValues, err := internal.NewMetricsColumnValues(tablesConfig)
 	if err != nil {
 		return nil, err
 	}
 
-	tablesConfig := generateMetricTablesConfigMapper(cfg)
+	client, err := newClickhouseClient(cfg)
+	if err != nil {
+		return nil, err
+	}
 
 	return &metricsExporter{
 		client:       client,
 		logger:       logger,
 		cfg:          cfg,
 		tablesConfig: tablesConfig,
+		columnValues: columnValues,
 	}, nil
 }
 
@@ -51,8 +57,36 @@ func (e *metricsExporter) start(ctx context.Context, _ component.Host) error {
 		return err
 	}
 
-	ttlExpr := generateTTLExpr(e.cfg.TTL, "toDateTime(TimeUnix)")
-	return internal.NewMetricsTable(ctx, e.tablesConfig, e.cfg.clusterString(), e.cfg.tableEngineString(), ttlExpr, e.client)
+	return createMetricsTables(ctx, e.logger, e.cfg, e.tablesConfig, e.client)
+}
+
+func createMetricsTables(ctx context.Context, logger *zap.Logger, cfg *Config, tablesConfig internal.MetricTablesConfigMapper, db *sql.DB) error {
+	for metricType, tableConfig := range tablesConfig {
+		if err := migrateTable(ctx, logger, cfg, db, tableConfig.Name, metricsMigrations(cfg, metricType, tableConfig)); err != nil {
+			return err
+		}
+		if query := tableConfig.Columns.AlterSQL(tableConfig.Name, cfg.clusterString()); query != "" {
+			if _, err := db.ExecContext(ctx, query); err != nil {
+				return fmt.Errorf("exec alter metrics table columns sql: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+// metricsMigrations are the versions of the schema of the table of a metric type.
+func metricsMigrations(cfg *Config, metricType pmetric.MetricType, tableConfig internal.MetricTypeConfig) []migration {
+	ttlExpr := generateTTLExpr(cfg.TTL, "toDateTime(TimeUnix)")
+	return []migration{
+		{
+			version:     1,
+			description: "create metrics table",
+			statements: []string{
+				internal.RenderCreateMetricsTableSQL(metricType, tableConfig, cfg.clusterString(), cfg.tableEngineString(), ttlExpr),
+			},
+			columns: internal.MetricsTableColumnNames(metricType),
+		},
+	}
 }
 
 func generateMetricTablesConfigMapper(cfg *Config) internal.MetricTablesConfigMapper {
@@ -74,7 +108,7 @@ func (e *metricsExporter) shutdown(_ context.Context) error {
 }
 
 func (e *metricsExporter) pushMetricsData(ctx context.Context, md pmetric.Metrics) error {
-	metricsMap := internal.NewMetricsModel(e.tablesConfig)
+	metricsMap := internal.NewMetricsModel(e.tablesConfig, e.columnValues)
 	for i := 0; i < md.ResourceMetrics().Len(); i++ {
 		metrics := md.ResourceMetrics().At(i)
 		resAttr := metrics.Resource().Attributes()
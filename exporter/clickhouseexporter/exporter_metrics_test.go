@@ -157,6 +157,31 @@ func TestExporter_pushMetricsData(t *testing.T) {
 		exporter := newTestMetricsExporter(t, defaultEndpoint)
 		mustPushMetricsData(t, exporter, simpleMetrics(1))
 	})
+	t.Run("check attribute columns", func(t *testing.T) {
+		var gaugeLabels []driver.Value
+		initClickhouseTestServer(t, func(query string, values []driver.Value) error {
+			if strings.HasPrefix(strings.TrimSpace(query), "CREATE TABLE IF NOT EXISTS otel_metrics_gauge ") {
+				require.Contains(t, query, "\tGaugeLabel Int64 CODEC(ZSTD(1)),\n")
+			}
+			if strings.HasPrefix(query, "INSERT INTO otel_metrics_gauge") {
+				require.Len(t, values, 22)
+				gaugeLabels = append(gaugeLabels, values[21])
+			}
+			if strings.HasPrefix(query, "INSERT INTO otel_metrics_sum ") {
+				require.Len(t, values, 23)
+			}
+			return nil
+		})
+		exporter := newTestMetricsExporter(t, defaultEndpoint, func(cfg *Config) {
+			cfg.MetricsTables.Gauge.Columns = internal.ColumnsConfig{
+				Attributes: []internal.AttributeColumnConfig{
+					{Name: "GaugeLabel", Key: "gauge_label_1", Type: "Int64"},
+				},
+			}
+		})
+		mustPushMetricsData(t, exporter, simpleMetrics(1))
+		require.Equal(t, []driver.Value{int64(1), int64(0), int64(0)}, gaugeLabels)
+	})
 }
 
 func Benchmark_pushMetricsData(b *testing.B) {
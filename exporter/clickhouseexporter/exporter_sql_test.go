@@ -40,7 +40,7 @@ func checkClusterQueryDefinition(query string, clusterName string) error {
 	line := getQueryFirstLine(query)
 	lowercasedLine := strings.ToLower(line)
 	suffix := fmt.Sprintf("ON CLUSTER %s", clusterName)
-	prefixes := []string{"create database", "create table", "create materialized view"}
+	prefixes := []string{"create database", "create table", "create materialized view", "alter table"}
 	for _, prefix := range prefixes {
 		if strings.HasPrefix(lowercasedLine, prefix) {
 			if strings.HasSuffix(line, suffix) {
@@ -23,12 +23,18 @@ import (
 type tracesExporter struct {
 	client    *sql.DB
 	insertSQL string
+	columns   internal.ColumnValues
 
 	logger *zap.Logger
 	cfg    *Config
 }
 
 func newTracesExporter(logger *zap.Logger, cfg *Config) (*tracesExporter, error) {
+	columns, err := cfg.TracesColumns.NewColumnValues()
+	if err != nil {
+		return nil, err
+	}
+
 	client, err := newClickhouseClient(cfg)
 	if err != nil {
 		return nil, err
@@ -37,6 +43,7 @@ func newTracesExporter(logger *zap.Logger, cfg *Config) (*tracesExporter, error)
 	return &tracesExporter{
 		client:    client,
 		insertSQL: renderInsertTracesSQL(cfg),
+		columns:   columns,
 		logger:    logger,
 		cfg:       cfg,
 	}, nil
@@ -51,7 +58,7 @@ func (e *tracesExporter) start(ctx context.Context, _ component.Host) error {
 		return err
 	}
 
-	return createTracesTable(ctx, e.cfg, e.client)
+	return createTracesTable(ctx, e.logger, e.cfg, e.client)
 }
 
 // shutdown will shut down the exporter.
@@ -75,7 +82,7 @@ func (e *tracesExporter) pushTraceData(ctx context.Context, td ptrace.Traces) er
 		for i := 0; i < td.ResourceSpans().Len(); i++ {
 			spans := td.ResourceSpans().At(i)
 			res := spans.Resource()
-			resAttr := internal.AttributesToMap(res.Attributes())
+			resAttr := e.cfg.attributesToValue(res.Attributes())
 			serviceName := internal.GetServiceName(res.Attributes())
 
 			for j := 0; j < spans.ScopeSpans().Len(); j++ {
@@ -84,11 +91,11 @@ func (e *tracesExporter) pushTraceData(ctx context.Context, td ptrace.Traces) er
 				scopeVersion := spans.ScopeSpans().At(j).Scope().Version()
 				for k := 0; k < rs.Len(); k++ {
 					r := rs.At(k)
-					spanAttr := internal.AttributesToMap(r.Attributes())
+					spanAttr := e.cfg.attributesToValue(r.Attributes())
 					status := r.Status()
 					eventTimes, eventNames, eventAttrs := convertEvents(r.Events())
 					linksTraceIDs, linksSpanIDs, linksTraceStates, linksAttrs := convertLinks(r.Links())
-					_, err = statement.ExecContext(ctx,
+					args := []any{
 						r.StartTimestamp().AsTime(),
 						traceutil.TraceIDToHexOrEmptyString(r.TraceID()),
 						traceutil.SpanIDToHexOrEmptyString(r.SpanID()),
@@ -111,7 +118,9 @@ func (e *tracesExporter) pushTraceData(ctx context.Context, td ptrace.Traces) er
 						linksSpanIDs,
 						linksTraceStates,
 						linksAttrs,
-					)
+					}
+					args = e.columns.AppendValues(args, res.Attributes(), spans.ScopeSpans().At(j).Scope().Attributes(), r.Attributes())
+					_, err = statement.ExecContext(ctx, args...)
 					if err != nil {
 						return fmt.Errorf("ExecContext:%w", err)
 					}
@@ -176,13 +185,52 @@ CREATE TABLE IF NOT EXISTS %s %s (
 		SpanId String,
 		TraceState String,
 		Attributes Map(LowCardinality(String), String)
-	) CODEC(ZSTD(1)),
+	) CODEC(ZSTD(1)),%s
 	INDEX idx_trace_id TraceId TYPE bloom_filter(0.001) GRANULARITY 1,
 	INDEX idx_res_attr_key mapKeys(ResourceAttributes) TYPE bloom_filter(0.01) GRANULARITY 1,
 	INDEX idx_res_attr_value mapValues(ResourceAttributes) TYPE bloom_filter(0.01) GRANULARITY 1,
 	INDEX idx_span_attr_key mapKeys(SpanAttributes) TYPE bloom_filter(0.01) GRANULARITY 1,
 	INDEX idx_span_attr_value mapValues(SpanAttributes) TYPE bloom_filter(0.01) GRANULARITY 1,
-	INDEX idx_duration Duration TYPE minmax GRANULARITY 1
+	INDEX idx_duration Duration TYPE minmax GRANULARITY 1%s
+) ENGINE = %s
+PARTITION BY toDate(Timestamp)
+ORDER BY (ServiceName, SpanName, toDateTime(Timestamp))
+%s
+SETTINGS index_granularity=8192, ttl_only_drop_parts = 1;
+`
+	// language=ClickHouse SQL
+	createTracesJSONTableSQL = `
+CREATE TABLE IF NOT EXISTS %s %s (
+	Timestamp DateTime64(9) CODEC(Delta, ZSTD(1)),
+	TraceId String CODEC(ZSTD(1)),
+	SpanId String CODEC(ZSTD(1)),
+	ParentSpanId String CODEC(ZSTD(1)),
+	TraceState String CODEC(ZSTD(1)),
+	SpanName LowCardinality(String) CODEC(ZSTD(1)),
+	SpanKind LowCardinality(String) CODEC(ZSTD(1)),
+	ServiceName LowCardinality(String) CODEC(ZSTD(1)),
+	ResourceAttributes JSON CODEC(ZSTD(1)),
+	ScopeName String CODEC(ZSTD(1)),
+	ScopeVersion String CODEC(ZSTD(1)),
+	SpanAttributes JSON CODEC(ZSTD(1)),
+	Duration UInt64 CODEC(ZSTD(1)),
+	StatusCode LowCardinality(String) CODEC(ZSTD(1)),
+	StatusMessage String CODEC(ZSTD(1)),
+	Events Nested (
+		Timestamp DateTime64(9),
+		Name LowCardinality(String),
+		Attributes Map(LowCardinality(String), String)
+	) CODEC(ZSTD(1)),
+	Links Nested (
+		TraceId String,
+		SpanId String,
+		TraceState String,
+		Attributes Map(LowCardinality(String), String)
+	) CODEC(ZSTD(1)),%s
+	INDEX idx_trace_id TraceId TYPE bloom_filter(0.001) GRANULARITY 1,
+	INDEX idx_res_attr_paths JSONAllPaths(ResourceAttributes) TYPE bloom_filter(0.01) GRANULARITY 1,
+	INDEX idx_span_attr_paths JSONAllPaths(SpanAttributes) TYPE bloom_filter(0.01) GRANULARITY 1,
+	INDEX idx_duration Duration TYPE minmax GRANULARITY 1%s
 ) ENGINE = %s
 PARTITION BY toDate(Timestamp)
 ORDER BY (ServiceName, SpanName, toDateTime(Timestamp))
@@ -212,7 +260,7 @@ SETTINGS index_granularity=8192, ttl_only_drop_parts = 1;
                         Links.TraceId,
                         Links.SpanId,
                         Links.TraceState,
-                        Links.Attributes
+                        Links.Attributes%s
                         ) VALUES (
                                   ?,
                                   ?,
@@ -235,7 +283,7 @@ SETTINGS index_granularity=8192, ttl_only_drop_parts = 1;
                                   ?,
                                   ?,
                                   ?,
-                                  ?
+                                  ?%s
                                   )`
 )
 
@@ -266,26 +314,50 @@ GROUP BY TraceId;
 `
 )
 
-func createTracesTable(ctx context.Context, cfg *Config, db *sql.DB) error {
-	if _, err := db.ExecContext(ctx, renderCreateTracesTableSQL(cfg)); err != nil {
-		return fmt.Errorf("exec create traces table sql: %w", err)
+func createTracesTable(ctx context.Context, logger *zap.Logger, cfg *Config, db *sql.DB) error {
+	if err := checkAttributesType(ctx, cfg, db, cfg.TracesTableName, "SpanAttributes"); err != nil {
+		return err
 	}
-	if _, err := db.ExecContext(ctx, renderCreateTraceIDTsTableSQL(cfg)); err != nil {
-		return fmt.Errorf("exec create traceID timestamp table sql: %w", err)
+	if err := migrateTable(ctx, logger, cfg, db, cfg.TracesTableName, tracesMigrations(cfg)); err != nil {
+		return err
 	}
-	if _, err := db.ExecContext(ctx, renderTraceIDTsMaterializedViewSQL(cfg)); err != nil {
-		return fmt.Errorf("exec create traceID timestamp view sql: %w", err)
+	if query := cfg.TracesColumns.AlterSQL(cfg.TracesTableName, cfg.clusterString()); query != "" {
+		if _, err := db.ExecContext(ctx, query); err != nil {
+			return fmt.Errorf("exec alter traces table columns sql: %w", err)
+		}
 	}
 	return nil
 }
 
+// tracesMigrations are the versions of the schema of the traces table and of the traceID timestamp table and view.
+func tracesMigrations(cfg *Config) []migration {
+	return []migration{
+		{
+			version:     1,
+			description: "create traces table",
+			statements: []string{
+				renderCreateTracesTableSQL(cfg),
+				renderCreateTraceIDTsTableSQL(cfg),
+				renderTraceIDTsMaterializedViewSQL(cfg),
+			},
+			columns: internal.TableColumnNames(createTracesTableSQL),
+		},
+	}
+}
+
 func renderInsertTracesSQL(cfg *Config) string {
-	return fmt.Sprintf(strings.ReplaceAll(insertTracesSQLTemplate, "'", "`"), cfg.TracesTableName)
+	return fmt.Sprintf(strings.ReplaceAll(insertTracesSQLTemplate, "'", "`"), cfg.TracesTableName,
+		cfg.TracesColumns.InsertColumnsSQL(), cfg.TracesColumns.InsertPlaceholdersSQL())
 }
 
 func renderCreateTracesTableSQL(cfg *Config) string {
 	ttlExpr := generateTTLExpr(cfg.TTL, "toDate(Timestamp)")
-	return fmt.Sprintf(createTracesTableSQL, cfg.TracesTableName, cfg.clusterString(), cfg.tableEngineString(), ttlExpr)
+	query := createTracesTableSQL
+	if cfg.AttributesType == attributesTypeJSON {
+		query = createTracesJSONTableSQL
+	}
+	return fmt.Sprintf(query, cfg.TracesTableName, cfg.clusterString(),
+		cfg.TracesColumns.ColumnsSQL(), cfg.TracesColumns.IndexesSQL(), cfg.tableEngineString(), ttlExpr)
 }
 
 func renderCreateTraceIDTsTableSQL(cfg *Config) string {
@@ -15,6 +15,8 @@ import (
 	"go.opentelemetry.io/collector/pdata/ptrace"
 	conventions "go.opentelemetry.io/collector/semconv/v1.27.0"
 	"go.uber.org/zap/zaptest"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/clickhouseexporter/internal"
 )
 
 func TestExporter_pushTracesData(t *testing.T) {
@@ -46,6 +48,51 @@ func TestExporter_pushTracesData(t *testing.T) {
 		exporter := newTestTracesExporter(t, defaultEndpoint)
 		mustPushTracesData(t, exporter, simpleTraces(1))
 	})
+	t.Run("check attribute columns", func(t *testing.T) {
+		var inserted bool
+		initClickhouseTestServer(t, func(query string, values []driver.Value) error {
+			if strings.HasPrefix(strings.TrimSpace(query), "CREATE TABLE IF NOT EXISTS otel_traces ") {
+				require.Contains(t, query, "\tServiceVersion Nullable(String) CODEC(ZSTD(1)),\n")
+			}
+			if strings.HasPrefix(query, "INSERT") {
+				require.Contains(t, query, "Links.Attributes,\n\tServiceVersion")
+				require.Len(t, values, 23)
+				require.Nil(t, values[22])
+				inserted = true
+			}
+			return nil
+		})
+
+		exporter := newTestTracesExporter(t, defaultEndpoint, func(cfg *Config) {
+			cfg.TracesColumns = internal.ColumnsConfig{
+				Attributes: []internal.AttributeColumnConfig{
+					{Name: "ServiceVersion", Key: conventions.AttributeServiceVersion, Source: internal.AttributeSourceResource, Type: "Nullable(String)"},
+				},
+			}
+		})
+		mustPushTracesData(t, exporter, simpleTraces(1))
+		require.True(t, inserted)
+	})
+	t.Run("check json attributes", func(t *testing.T) {
+		var inserted bool
+		initClickhouseTestServer(t, func(query string, values []driver.Value) error {
+			if strings.HasPrefix(strings.TrimSpace(query), "CREATE TABLE IF NOT EXISTS otel_traces ") {
+				require.Contains(t, query, "\tSpanAttributes JSON CODEC(ZSTD(1)),\n")
+			}
+			if strings.HasPrefix(query, "INSERT") {
+				require.Equal(t, `{"service.name":"test-service"}`, values[8])
+				require.Equal(t, `{"service.name":"v"}`, values[11])
+				inserted = true
+			}
+			return nil
+		})
+
+		exporter := newTestTracesExporter(t, defaultEndpoint, func(cfg *Config) {
+			cfg.AttributesType = attributesTypeJSON
+		})
+		mustPushTracesData(t, exporter, simpleTraces(1))
+		require.True(t, inserted)
+	})
 }
 
 func newTestTracesExporter(t *testing.T, dsn string, fns ...func(*Config)) *tracesExporter {
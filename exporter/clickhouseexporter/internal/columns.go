@@ -0,0 +1,368 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package internal // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/clickhouseexporter/internal"
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+)
+
+const defaultColumnCodec = "ZSTD(1)"
+
+// AttributeSource is the part of the telemetry an attribute is read from.
+type AttributeSource string
+
+const (
+	// AttributeSourceResource reads the attribute from the resource.
+	AttributeSourceResource AttributeSource = "resource"
+	// AttributeSourceScope reads the attribute from the instrumentation scope.
+	AttributeSourceScope AttributeSource = "scope"
+	// AttributeSourceRecord reads the attribute from the log record, span or data point.
+	AttributeSourceRecord AttributeSource = "record"
+)
+
+var identifierRegexp = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// ColumnsConfig defines the columns and indexes added to a table on top of the ones of the exporter schema.
+type ColumnsConfig struct {
+	// Attributes promotes attributes to dedicated columns.
+	Attributes []AttributeColumnConfig `mapstructure:"attributes"`
+	// Indexes are the data skipping indexes added to the table.
+	Indexes []IndexConfig `mapstructure:"indexes"`
+}
+
+// AttributeColumnConfig maps an attribute to a column.
+type AttributeColumnConfig struct {
+	// Name is the name of the column.
+	Name string `mapstructure:"name"`
+	// Key is the key of the attribute stored in the column.
+	Key string `mapstructure:"key"`
+	// Source is where the attribute is read from, one of `resource`, `scope` or `record`. default is `record`.
+	Source AttributeSource `mapstructure:"source"`
+	// Type is the ClickHouse type of the column. default is `String`.
+	Type string `mapstructure:"type"`
+	// Codec is the compression codec of the column. default is `ZSTD(1)`.
+	Codec string `mapstructure:"codec"`
+}
+
+// IndexConfig defines a data skipping index.
+type IndexConfig struct {
+	// Name is the name of the index.
+	Name string `mapstructure:"name"`
+	// Expression is the indexed expression, usually a column name.
+	Expression string `mapstructure:"expression"`
+	// Type is the type of the index, for example `bloom_filter(0.01)` or `minmax`.
+	Type string `mapstructure:"type"`
+	// Granularity is the granularity of the index. default is 1.
+	Granularity int `mapstructure:"granularity"`
+}
+
+// Validate the columns configuration.
+func (cfg ColumnsConfig) Validate() (err error) {
+	names := map[string]struct{}{}
+	for _, c := range cfg.Attributes {
+		if !identifierRegexp.MatchString(c.Name) {
+			err = errors.Join(err, fmt.Errorf("invalid column name %q", c.Name))
+		}
+		if _, ok := names[c.Name]; ok {
+			err = errors.Join(err, fmt.Errorf("duplicate column %q", c.Name))
+		}
+		names[c.Name] = struct{}{}
+		if c.Key == "" {
+			err = errors.Join(err, fmt.Errorf("column %q: the attribute key must be specified", c.Name))
+		}
+		switch c.Source {
+		case "", AttributeSourceResource, AttributeSourceScope, AttributeSourceRecord:
+		default:
+			err = errors.Join(err, fmt.Errorf("column %q: unknown attribute source %q", c.Name, c.Source))
+		}
+		if _, e := newColumnValueFunc(c.columnType()); e != nil {
+			err = errors.Join(err, fmt.Errorf("column %q: %w", c.Name, e))
+		}
+	}
+	for _, idx := range cfg.Indexes {
+		if !identifierRegexp.MatchString(idx.Name) {
+			err = errors.Join(err, fmt.Errorf("invalid index name %q", idx.Name))
+		}
+		if idx.Expression == "" || idx.Type == "" {
+			err = errors.Join(err, fmt.Errorf("index %q: the expression and type must be specified", idx.Name))
+		}
+		if idx.Granularity < 0 {
+			err = errors.Join(err, fmt.Errorf("index %q: the granularity must be positive", idx.Name))
+		}
+	}
+	return err
+}
+
+// ColumnsSQL renders the definitions of the attribute columns, each one followed by a comma.
+func (cfg ColumnsConfig) ColumnsSQL() string {
+	var b strings.Builder
+	for _, c := range cfg.Attributes {
+		fmt.Fprintf(&b, "\n\t%s,", c.definition())
+	}
+	return b.String()
+}
+
+// IndexesSQL renders the definitions of the indexes, each one preceded by a comma.
+func (cfg ColumnsConfig) IndexesSQL() string {
+	var b strings.Builder
+	for _, idx := range cfg.Indexes {
+		fmt.Fprintf(&b, ",\n\tINDEX %s", idx.definition())
+	}
+	return b.String()
+}
+
+// AlterSQL renders the statement adding the attribute columns and indexes to an existing table, empty if there are
+// none. The columns and indexes already present are left untouched.
+func (cfg ColumnsConfig) AlterSQL(table, cluster string) string {
+	var actions []string
+	for _, c := range cfg.Attributes {
+		actions = append(actions, "ADD COLUMN IF NOT EXISTS "+c.definition())
+	}
+	for _, idx := range cfg.Indexes {
+		actions = append(actions, "ADD INDEX IF NOT EXISTS "+idx.definition())
+	}
+	if len(actions) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("\nALTER TABLE %s %s\n%s;\n", table, cluster, strings.Join(actions, ",\n"))
+}
+
+// InsertColumnsSQL renders the names of the attribute columns, each one preceded by a comma.
+func (cfg ColumnsConfig) InsertColumnsSQL() string {
+	var b strings.Builder
+	for _, c := range cfg.Attributes {
+		fmt.Fprintf(&b, ",\n\t%s", c.Name)
+	}
+	return b.String()
+}
+
+// InsertPlaceholdersSQL renders the placeholders of the attribute columns, each one preceded by a comma.
+func (cfg ColumnsConfig) InsertPlaceholdersSQL() string {
+	return strings.Repeat(",?", len(cfg.Attributes))
+}
+
+// ValidateBuiltinColumns checks that the attribute columns don't collide with the columns created by the exporter,
+// as defined by the statement creating the table.
+func (cfg ColumnsConfig) ValidateBuiltinColumns(createTableSQL string) (err error) {
+	builtin := tableColumnNames(createTableSQL)
+	for _, c := range cfg.Attributes {
+		if _, ok := builtin[c.Name]; ok {
+			err = errors.Join(err, fmt.Errorf("column %q collides with a built-in column", c.Name))
+		}
+	}
+	return err
+}
+
+// TableColumnNames returns the sorted names of the top level columns of a CREATE TABLE statement, the fields of
+// the nested columns excluded.
+func TableColumnNames(createTableSQL string) []string {
+	names := make([]string, 0)
+	for name := range tableColumnNames(createTableSQL) {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// tableColumnNames returns the names of the top level columns of a CREATE TABLE statement, the fields of the
+// nested columns excluded.
+func tableColumnNames(createTableSQL string) map[string]struct{} {
+	names := map[string]struct{}{}
+	depth := 0
+	for _, line := range strings.Split(createTableSQL, "\n") {
+		if fields := strings.Fields(line); depth == 1 && len(fields) > 1 && fields[0] != "INDEX" && identifierRegexp.MatchString(fields[0]) {
+			names[fields[0]] = struct{}{}
+		}
+		depth += strings.Count(line, "(") - strings.Count(line, ")")
+	}
+	return names
+}
+
+// ColumnValues converts the attributes of the rows to the values of the attribute columns of a table.
+type ColumnValues []attributeColumnValue
+
+type attributeColumnValue struct {
+	key       string
+	source    AttributeSource
+	valueFunc columnValueFunc
+}
+
+// NewColumnValues resolves the conversion of the attributes to the values of the attribute columns,
+// once for all the rows.
+func (cfg ColumnsConfig) NewColumnValues() (ColumnValues, error) {
+	values := make(ColumnValues, 0, len(cfg.Attributes))
+	for _, c := range cfg.Attributes {
+		valueFunc, err := newColumnValueFunc(c.columnType())
+		if err != nil {
+			return nil, fmt.Errorf("column %q: %w", c.Name, err)
+		}
+		values = append(values, attributeColumnValue{key: c.Key, source: c.Source, valueFunc: valueFunc})
+	}
+	return values, nil
+}
+
+// AppendValues appends the values of the attribute columns to the values of a row.
+func (cv ColumnValues) AppendValues(values []any, resAttr, scopeAttr, attr pcommon.Map) []any {
+	for _, c := range cv {
+		attrs := attr
+		switch c.source {
+		case AttributeSourceResource:
+			attrs = resAttr
+		case AttributeSourceScope:
+			attrs = scopeAttr
+		}
+		v, ok := attrs.Get(c.key)
+		values = append(values, c.valueFunc(v, ok))
+	}
+	return values
+}
+
+func (c AttributeColumnConfig) definition() string {
+	codec := c.Codec
+	if codec == "" {
+		codec = defaultColumnCodec
+	}
+	return fmt.Sprintf("%s %s CODEC(%s)", c.Name, c.columnType(), codec)
+}
+
+func (idx IndexConfig) definition() string {
+	granularity := idx.Granularity
+	if granularity == 0 {
+		granularity = 1
+	}
+	return fmt.Sprintf("%s %s TYPE %s GRANULARITY %d", idx.Name, idx.Expression, idx.Type, granularity)
+}
+
+func (c AttributeColumnConfig) columnType() string {
+	if c.Type == "" {
+		return "String"
+	}
+	return c.Type
+}
+
+type columnValueFunc func(v pcommon.Value, ok bool) any
+
+// newColumnValueFunc returns the function converting attribute values to the values of a column of the given type.
+func newColumnValueFunc(columnType string) (columnValueFunc, error) {
+	baseType, nullable := unwrapColumnType(columnType)
+	var convert func(pcommon.Value) any
+	switch baseType {
+	case "String":
+		convert = func(v pcommon.Value) any { return v.AsString() }
+	case "Int64":
+		convert = int64Value
+	case "Float64":
+		convert = float64Value
+	case "Bool":
+		convert = boolValue
+	case "Map(String, String)", "Map(LowCardinality(String), String)":
+		convert = func(v pcommon.Value) any { return AttributesToMap(mapValue(v)) }
+	case "JSON":
+		convert = func(v pcommon.Value) any { return AttributesToJSON(mapValue(v)) }
+	default:
+		return nil, fmt.Errorf("unsupported column type %q", columnType)
+	}
+	if nullable && (strings.HasPrefix(baseType, "Map") || baseType == "JSON") {
+		return nil, fmt.Errorf("unsupported column type %q", columnType)
+	}
+
+	return func(v pcommon.Value, ok bool) any {
+		if !ok {
+			if nullable {
+				return nil
+			}
+			v = pcommon.NewValueEmpty()
+		}
+		return convert(v)
+	}, nil
+}
+
+// unwrapColumnType strips the LowCardinality and Nullable modifiers from a column type.
+func unwrapColumnType(columnType string) (baseType string, nullable bool) {
+	baseType = strings.TrimSpace(columnType)
+	for {
+		switch {
+		case strings.HasPrefix(baseType, "LowCardinality(") && strings.HasSuffix(baseType, ")"):
+			baseType = baseType[len("LowCardinality(") : len(baseType)-1]
+		case strings.HasPrefix(baseType, "Nullable(") && strings.HasSuffix(baseType, ")"):
+			baseType = baseType[len("Nullable(") : len(baseType)-1]
+			nullable = true
+		default:
+			return baseType, nullable
+		}
+	}
+}
+
+func int64Value(v pcommon.Value) any {
+	switch v.Type() {
+	case pcommon.ValueTypeInt:
+		return v.Int()
+	case pcommon.ValueTypeDouble:
+		return int64(v.Double())
+	case pcommon.ValueTypeBool:
+		if v.Bool() {
+			return int64(1)
+		}
+	case pcommon.ValueTypeStr:
+		i, _ := strconv.ParseInt(v.Str(), 10, 64)
+		return i
+	}
+	return int64(0)
+}
+
+func float64Value(v pcommon.Value) any {
+	switch v.Type() {
+	case pcommon.ValueTypeInt:
+		return float64(v.Int())
+	case pcommon.ValueTypeDouble:
+		return v.Double()
+	case pcommon.ValueTypeBool:
+		if v.Bool() {
+			return float64(1)
+		}
+	case pcommon.ValueTypeStr:
+		f, _ := strconv.ParseFloat(v.Str(), 64)
+		return f
+	}
+	return float64(0)
+}
+
+func boolValue(v pcommon.Value) any {
+	switch v.Type() {
+	case pcommon.ValueTypeBool:
+		return v.Bool()
+	case pcommon.ValueTypeInt:
+		return v.Int() != 0
+	case pcommon.ValueTypeDouble:
+		return v.Double() != 0
+	case pcommon.ValueTypeStr:
+		b, _ := strconv.ParseBool(v.Str())
+		return b
+	}
+	return false
+}
+
+func mapValue(v pcommon.Value) pcommon.Map {
+	if v.Type() == pcommon.ValueTypeMap {
+		return v.Map()
+	}
+	return pcommon.NewMap()
+}
+
+// AttributesToJSON renders the attributes as a JSON object, keeping the types of the values.
+func AttributesToJSON(attributes pcommon.Map) string {
+	b, err := json.Marshal(attributes.AsRaw())
+	if err != nil {
+		return "{}"
+	}
+	return string(b)
+}
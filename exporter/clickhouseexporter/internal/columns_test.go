@@ -0,0 +1,170 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package internal
+
+import (
+	"testing"
+
+	"github.com/ClickHouse/clickhouse-go/v2/lib/column/orderedmap"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+)
+
+func Test_ColumnsConfigValidate(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		cfg := ColumnsConfig{
+			Attributes: []AttributeColumnConfig{
+				{Name: "TenantId", Key: "tenant_id", Source: AttributeSourceResource, Type: "LowCardinality(String)"},
+				{Name: "HttpStatusCode", Key: "http.response.status_code", Type: "Nullable(Int64)"},
+				{Name: "Labels", Key: "labels", Type: "Map(LowCardinality(String), String)"},
+				{Name: "Payload", Key: "payload", Type: "JSON"},
+			},
+			Indexes: []IndexConfig{{Name: "idx_tenant_id", Expression: "TenantId", Type: "set(100)"}},
+		}
+		require.NoError(t, cfg.Validate())
+	})
+	t.Run("invalid columns", func(t *testing.T) {
+		cfg := ColumnsConfig{
+			Attributes: []AttributeColumnConfig{
+				{Name: "Http Route", Key: "http.route"},
+				{Name: "TenantId"},
+				{Name: "TenantId", Key: "tenant_id", Source: "span"},
+				{Name: "Count", Key: "count", Type: "UInt8"},
+				{Name: "Labels", Key: "labels", Type: "Nullable(JSON)"},
+			},
+		}
+		err := cfg.Validate()
+		require.ErrorContains(t, err, `invalid column name "Http Route"`)
+		require.ErrorContains(t, err, `column "TenantId": the attribute key must be specified`)
+		require.ErrorContains(t, err, `duplicate column "TenantId"`)
+		require.ErrorContains(t, err, `column "TenantId": unknown attribute source "span"`)
+		require.ErrorContains(t, err, `column "Count": unsupported column type "UInt8"`)
+		require.ErrorContains(t, err, `column "Labels": unsupported column type "Nullable(JSON)"`)
+	})
+	t.Run("invalid indexes", func(t *testing.T) {
+		cfg := ColumnsConfig{
+			Indexes: []IndexConfig{
+				{Name: "idx_route", Type: "bloom_filter"},
+				{Name: "idx_tenant", Expression: "TenantId", Type: "set(100)", Granularity: -1},
+			},
+		}
+		err := cfg.Validate()
+		require.ErrorContains(t, err, `index "idx_route": the expression and type must be specified`)
+		require.ErrorContains(t, err, `index "idx_tenant": the granularity must be positive`)
+	})
+}
+
+func Test_ColumnsConfigSQL(t *testing.T) {
+	cfg := ColumnsConfig{
+		Attributes: []AttributeColumnConfig{
+			{Name: "TenantId", Key: "tenant_id", Type: "LowCardinality(String)"},
+			{Name: "HttpRoute", Key: "http.route", Codec: "LZ4"},
+		},
+		Indexes: []IndexConfig{
+			{Name: "idx_http_route", Expression: "HttpRoute", Type: "bloom_filter(0.01)"},
+			{Name: "idx_tenant_id", Expression: "TenantId", Type: "set(100)", Granularity: 4},
+		},
+	}
+	require.Equal(t, "\n\tTenantId LowCardinality(String) CODEC(ZSTD(1)),\n\tHttpRoute String CODEC(LZ4),", cfg.ColumnsSQL())
+	require.Equal(t, ",\n\tINDEX idx_http_route HttpRoute TYPE bloom_filter(0.01) GRANULARITY 1,\n\tINDEX idx_tenant_id TenantId TYPE set(100) GRANULARITY 4", cfg.IndexesSQL())
+	require.Equal(t, ",\n\tTenantId,\n\tHttpRoute", cfg.InsertColumnsSQL())
+	require.Equal(t, ",?,?", cfg.InsertPlaceholdersSQL())
+	require.Equal(t, "\nALTER TABLE otel_logs ON CLUSTER c\n"+
+		"ADD COLUMN IF NOT EXISTS TenantId LowCardinality(String) CODEC(ZSTD(1)),\n"+
+		"ADD COLUMN IF NOT EXISTS HttpRoute String CODEC(LZ4),\n"+
+		"ADD INDEX IF NOT EXISTS idx_http_route HttpRoute TYPE bloom_filter(0.01) GRANULARITY 1,\n"+
+		"ADD INDEX IF NOT EXISTS idx_tenant_id TenantId TYPE set(100) GRANULARITY 4;\n", cfg.AlterSQL("otel_logs", "ON CLUSTER c"))
+
+	require.Empty(t, ColumnsConfig{}.ColumnsSQL())
+	require.Empty(t, ColumnsConfig{}.IndexesSQL())
+	require.Empty(t, ColumnsConfig{}.InsertColumnsSQL())
+	require.Empty(t, ColumnsConfig{}.InsertPlaceholdersSQL())
+	require.Empty(t, ColumnsConfig{}.AlterSQL("otel_logs", ""))
+}
+
+func Test_ColumnValuesAppendValues(t *testing.T) {
+	resAttr := pcommon.NewMap()
+	resAttr.PutStr("tenant_id", "tenant-1")
+	scopeAttr := pcommon.NewMap()
+	scopeAttr.PutStr("lib", "clickhouse")
+	attr := pcommon.NewMap()
+	attr.PutStr("http.route", "/users/{id}")
+	attr.PutInt("http.response.status_code", 200)
+	attr.PutStr("duration", "1.5")
+	attr.PutBool("error", true)
+	labels := attr.PutEmptyMap("labels")
+	labels.PutStr("team", "a")
+	labels.PutInt("replicas", 3)
+
+	cfg := ColumnsConfig{
+		Attributes: []AttributeColumnConfig{
+			{Name: "TenantId", Key: "tenant_id", Source: AttributeSourceResource},
+			{Name: "Lib", Key: "lib", Source: AttributeSourceScope},
+			{Name: "HttpRoute", Key: "http.route", Source: AttributeSourceRecord},
+			{Name: "HttpStatusCode", Key: "http.response.status_code", Type: "Int64"},
+			{Name: "Duration", Key: "duration", Type: "Float64"},
+			{Name: "Error", Key: "error", Type: "Bool"},
+			{Name: "Labels", Key: "labels", Type: "Map(String, String)"},
+			{Name: "LabelsJSON", Key: "labels", Type: "JSON"},
+			{Name: "Missing", Key: "missing", Type: "Int64"},
+			{Name: "MissingNullable", Key: "missing", Type: "LowCardinality(Nullable(String))"},
+		},
+	}
+
+	columnValues, err := cfg.NewColumnValues()
+	require.NoError(t, err)
+	values := columnValues.AppendValues([]any{"existing"}, resAttr, scopeAttr, attr)
+
+	require.Equal(t, []any{
+		"existing",
+		"tenant-1",
+		"clickhouse",
+		"/users/{id}",
+		int64(200),
+		1.5,
+		true,
+		orderedmap.FromMap(map[string]string{"team": "a", "replicas": "3"}),
+		`{"replicas":3,"team":"a"}`,
+		int64(0),
+		nil,
+	}, values)
+}
+
+func Test_NewColumnValuesInvalidType(t *testing.T) {
+	cfg := ColumnsConfig{Attributes: []AttributeColumnConfig{{Name: "Count", Key: "count", Type: "UInt8"}}}
+	_, err := cfg.NewColumnValues()
+	require.EqualError(t, err, `column "Count": unsupported column type "UInt8"`)
+}
+
+func Test_ColumnsConfigValidateBuiltinColumns(t *testing.T) {
+	createTableSQL := `
+CREATE TABLE IF NOT EXISTS %s %s (
+	Timestamp DateTime64(9) CODEC(Delta, ZSTD(1)),
+	ServiceName LowCardinality(String) CODEC(ZSTD(1)),
+	Events Nested (
+		Name LowCardinality(String),
+		Attributes Map(LowCardinality(String), String)
+	) CODEC(ZSTD(1)),%s
+	INDEX idx_service_name ServiceName TYPE bloom_filter(0.001) GRANULARITY 1%s
+) ENGINE = %s
+ORDER BY (ServiceName, toDateTime(Timestamp))
+`
+	require.Equal(t, map[string]struct{}{"Timestamp": {}, "ServiceName": {}, "Events": {}}, tableColumnNames(createTableSQL))
+	require.Equal(t, []string{"Events", "ServiceName", "Timestamp"}, TableColumnNames(createTableSQL))
+
+	cfg := ColumnsConfig{
+		Attributes: []AttributeColumnConfig{
+			{Name: "ServiceName", Key: "service.name"},
+			{Name: "Timestamp", Key: "timestamp"},
+			// the fields of the nested columns are prefixed with the name of the column
+			{Name: "Name", Key: "name"},
+			{Name: "INDEX", Key: "index"},
+		},
+	}
+	err := cfg.ValidateBuiltinColumns(createTableSQL)
+	require.ErrorContains(t, err, `column "ServiceName" collides with a built-in column`)
+	require.ErrorContains(t, err, `column "Timestamp" collides with a built-in column`)
+	require.NotContains(t, err.Error(), `"Name"`)
+	require.NotContains(t, err.Error(), `"INDEX"`)
+}
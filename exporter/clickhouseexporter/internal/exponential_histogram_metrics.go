@@ -50,13 +50,13 @@ CREATE TABLE IF NOT EXISTS %s %s (
     Flags UInt32  CODEC(ZSTD(1)),
     Min Float64 CODEC(ZSTD(1)),
     Max Float64 CODEC(ZSTD(1)),
-		AggregationTemporality Int32 CODEC(ZSTD(1)),
+		AggregationTemporality Int32 CODEC(ZSTD(1)),%s
 	INDEX idx_res_attr_key mapKeys(ResourceAttributes) TYPE bloom_filter(0.01) GRANULARITY 1,
 	INDEX idx_res_attr_value mapValues(ResourceAttributes) TYPE bloom_filter(0.01) GRANULARITY 1,
 	INDEX idx_scope_attr_key mapKeys(ScopeAttributes) TYPE bloom_filter(0.01) GRANULARITY 1,
 	INDEX idx_scope_attr_value mapValues(ScopeAttributes) TYPE bloom_filter(0.01) GRANULARITY 1,
 	INDEX idx_attr_key mapKeys(Attributes) TYPE bloom_filter(0.01) GRANULARITY 1,
-	INDEX idx_attr_value mapValues(Attributes) TYPE bloom_filter(0.01) GRANULARITY 1
+	INDEX idx_attr_value mapValues(Attributes) TYPE bloom_filter(0.01) GRANULARITY 1%s
 ) ENGINE = %s
 %s
 PARTITION BY toDate(TimeUnix)
@@ -95,7 +95,7 @@ SETTINGS index_granularity=8192, ttl_only_drop_parts = 1;
 	Flags,
 	Min,
 	Max,
-	AggregationTemporality) VALUES (?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?)`
+	AggregationTemporality%s) VALUES (?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?%s)`
 )
 
 type expHistogramModel struct {
@@ -109,6 +109,7 @@ type expHistogramModel struct {
 type expHistogramMetrics struct {
 	expHistogramModels []*expHistogramModel
 	insertSQL          string
+	columns            ColumnValues
 	count              int
 }
 
@@ -136,7 +137,7 @@ func (e *expHistogramMetrics) insert(ctx context.Context, db *sql.DB) error {
 			for i := 0; i < model.expHistogram.DataPoints().Len(); i++ {
 				dp := model.expHistogram.DataPoints().At(i)
 				attrs, times, values, traceIDs, spanIDs := convertExemplars(dp.Exemplars())
-				_, err = statement.ExecContext(ctx,
+				args := []any{
 					resAttr,
 					model.metadata.ResURL,
 					model.metadata.ScopeInstr.Name(),
@@ -168,7 +169,9 @@ func (e *expHistogramMetrics) insert(ctx context.Context, db *sql.DB) error {
 					dp.Min(),
 					dp.Max(),
 					int32(model.expHistogram.AggregationTemporality()),
-				)
+				}
+				args = e.columns.AppendValues(args, model.metadata.ResAttr, model.metadata.ScopeInstr.Attributes(), dp.Attributes())
+				_, err = statement.ExecContext(ctx, args...)
 				if err != nil {
 					return fmt.Errorf("ExecContext:%w", err)
 				}
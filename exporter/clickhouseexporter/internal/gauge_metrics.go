@@ -40,13 +40,13 @@ CREATE TABLE IF NOT EXISTS %s %s (
 		Value Float64,
 		SpanId String,
 		TraceId String
-    ) CODEC(ZSTD(1)),
+    ) CODEC(ZSTD(1)),%s
 	INDEX idx_res_attr_key mapKeys(ResourceAttributes) TYPE bloom_filter(0.01) GRANULARITY 1,
 	INDEX idx_res_attr_value mapValues(ResourceAttributes) TYPE bloom_filter(0.01) GRANULARITY 1,
 	INDEX idx_scope_attr_key mapKeys(ScopeAttributes) TYPE bloom_filter(0.01) GRANULARITY 1,
 	INDEX idx_scope_attr_value mapValues(ScopeAttributes) TYPE bloom_filter(0.01) GRANULARITY 1,
 	INDEX idx_attr_key mapKeys(Attributes) TYPE bloom_filter(0.01) GRANULARITY 1,
-	INDEX idx_attr_value mapValues(Attributes) TYPE bloom_filter(0.01) GRANULARITY 1
+	INDEX idx_attr_value mapValues(Attributes) TYPE bloom_filter(0.01) GRANULARITY 1%s
 ) ENGINE = %s
 %s
 PARTITION BY toDate(TimeUnix)
@@ -75,7 +75,7 @@ SETTINGS index_granularity=8192, ttl_only_drop_parts = 1;
 	Exemplars.TimeUnix,
     Exemplars.Value,
     Exemplars.SpanId,
-    Exemplars.TraceId) VALUES (?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?)`
+    Exemplars.TraceId%s) VALUES (?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?%s)`
 )
 
 type gaugeModel struct {
@@ -89,6 +89,7 @@ type gaugeModel struct {
 type gaugeMetrics struct {
 	gaugeModels []*gaugeModel
 	insertSQL   string
+	columns     ColumnValues
 	count       int
 }
 
@@ -115,7 +116,7 @@ func (g *gaugeMetrics) insert(ctx context.Context, db *sql.DB) error {
 			for i := 0; i < model.gauge.DataPoints().Len(); i++ {
 				dp := model.gauge.DataPoints().At(i)
 				attrs, times, values, traceIDs, spanIDs := convertExemplars(dp.Exemplars())
-				_, err = statement.ExecContext(ctx,
+				args := []any{
 					resAttr,
 					model.metadata.ResURL,
 					model.metadata.ScopeInstr.Name(),
@@ -137,7 +138,9 @@ func (g *gaugeMetrics) insert(ctx context.Context, db *sql.DB) error {
 					values,
 					spanIDs,
 					traceIDs,
-				)
+				}
+				args = g.columns.AppendValues(args, model.metadata.ResAttr, model.metadata.ScopeInstr.Attributes(), dp.Attributes())
+				_, err = statement.ExecContext(ctx, args...)
 				if err != nil {
 					return fmt.Errorf("ExecContext:%w", err)
 				}
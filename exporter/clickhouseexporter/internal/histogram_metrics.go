@@ -46,13 +46,13 @@ CREATE TABLE IF NOT EXISTS %s %s (
     Flags UInt32 CODEC(ZSTD(1)),
     Min Float64 CODEC(ZSTD(1)),
     Max Float64 CODEC(ZSTD(1)),
-		AggregationTemporality Int32 CODEC(ZSTD(1)),
+		AggregationTemporality Int32 CODEC(ZSTD(1)),%s
 	INDEX idx_res_attr_key mapKeys(ResourceAttributes) TYPE bloom_filter(0.01) GRANULARITY 1,
 	INDEX idx_res_attr_value mapValues(ResourceAttributes) TYPE bloom_filter(0.01) GRANULARITY 1,
 	INDEX idx_scope_attr_key mapKeys(ScopeAttributes) TYPE bloom_filter(0.01) GRANULARITY 1,
 	INDEX idx_scope_attr_value mapValues(ScopeAttributes) TYPE bloom_filter(0.01) GRANULARITY 1,
 	INDEX idx_attr_key mapKeys(Attributes) TYPE bloom_filter(0.01) GRANULARITY 1,
-	INDEX idx_attr_value mapValues(Attributes) TYPE bloom_filter(0.01) GRANULARITY 1
+	INDEX idx_attr_value mapValues(Attributes) TYPE bloom_filter(0.01) GRANULARITY 1%s
 ) ENGINE = %s
 %s
 PARTITION BY toDate(TimeUnix)
@@ -87,7 +87,7 @@ SETTINGS index_granularity=8192, ttl_only_drop_parts = 1;
 	Flags,
 	Min,
 	Max,
-	AggregationTemporality) VALUES (?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?)`
+	AggregationTemporality%s) VALUES (?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?%s)`
 )
 
 type histogramModel struct {
@@ -101,6 +101,7 @@ type histogramModel struct {
 type histogramMetrics struct {
 	histogramModel []*histogramModel
 	insertSQL      string
+	columns        ColumnValues
 	count          int
 }
 
@@ -127,7 +128,7 @@ func (h *histogramMetrics) insert(ctx context.Context, db *sql.DB) error {
 			for i := 0; i < model.histogram.DataPoints().Len(); i++ {
 				dp := model.histogram.DataPoints().At(i)
 				attrs, times, values, traceIDs, spanIDs := convertExemplars(dp.Exemplars())
-				_, err = statement.ExecContext(ctx,
+				args := []any{
 					resAttr,
 					model.metadata.ResURL,
 					model.metadata.ScopeInstr.Name(),
@@ -155,7 +156,9 @@ func (h *histogramMetrics) insert(ctx context.Context, db *sql.DB) error {
 					dp.Min(),
 					dp.Max(),
 					int32(model.histogram.AggregationTemporality()),
-				)
+				}
+				args = h.columns.AppendValues(args, model.metadata.ResAttr, model.metadata.ScopeInstr.Attributes(), dp.Attributes())
+				_, err = statement.ExecContext(ctx, args...)
 				if err != nil {
 					return fmt.Errorf("ExecContext:%w", err)
 				}
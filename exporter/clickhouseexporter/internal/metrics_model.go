@@ -35,6 +35,8 @@ type MetricTablesConfigMapper map[pmetric.MetricType]MetricTypeConfig
 
 type MetricTypeConfig struct {
 	Name string `mapstructure:"name"`
+	// Columns are the columns and indexes added to the table.
+	Columns ColumnsConfig `mapstructure:"columns"`
 }
 
 // MetricsModel is used to group metric data and insert into clickhouse
@@ -59,38 +61,71 @@ func SetLogger(l *zap.Logger) {
 	logger = l
 }
 
-// NewMetricsTable create metric tables with an expiry time to storage metric telemetry data
-func NewMetricsTable(ctx context.Context, tablesConfig MetricTablesConfigMapper, cluster, engine, ttlExpr string, db *sql.DB) error {
-	for key, queryTemplate := range supportedMetricTypes {
-		query := fmt.Sprintf(queryTemplate, tablesConfig[key].Name, cluster, engine, ttlExpr)
-		if _, err := db.ExecContext(ctx, query); err != nil {
-			return fmt.Errorf("exec create metrics table sql: %w", err)
+// RenderCreateMetricsTableSQL renders the statement creating the table of a metric type with an expiry time to storage metric telemetry data
+func RenderCreateMetricsTableSQL(metricType pmetric.MetricType, tableConfig MetricTypeConfig, cluster, engine, ttlExpr string) string {
+	return fmt.Sprintf(supportedMetricTypes[metricType], tableConfig.Name, cluster, tableConfig.Columns.ColumnsSQL(), tableConfig.Columns.IndexesSQL(), engine, ttlExpr)
+}
+
+// MetricsTableColumnNames returns the names of the columns created by the exporter in the table of a metric type.
+func MetricsTableColumnNames(metricType pmetric.MetricType) []string {
+	return TableColumnNames(supportedMetricTypes[metricType])
+}
+
+// NewMetricsColumnValues resolves the conversion of the attributes to the values of the attribute columns of the
+// table of each metric type.
+func NewMetricsColumnValues(tablesConfig MetricTablesConfigMapper) (map[pmetric.MetricType]ColumnValues, error) {
+	columnValues := make(map[pmetric.MetricType]ColumnValues, len(tablesConfig))
+	for metricType, tableConfig := range tablesConfig {
+		values, err := tableConfig.Columns.NewColumnValues()
+		if err != nil {
+			return nil, fmt.Errorf("table %s: %w", tableConfig.Name, err)
+		}
+		columnValues[metricType] = values
+	}
+	return columnValues, nil
+}
+
+// ValidateMetricsBuiltinColumns checks that the attribute columns of the table of each metric type don't collide
+// with the columns created by the exporter.
+func ValidateMetricsBuiltinColumns(tablesConfig MetricTablesConfigMapper) (err error) {
+	for metricType, tableConfig := range tablesConfig {
+		if e := tableConfig.Columns.ValidateBuiltinColumns(supportedMetricTypes[metricType]); e != nil {
+			err = errors.Join(err, fmt.Errorf("table %s: %w", tableConfig.Name, e))
 		}
 	}
-	return nil
+	return err
 }
 
 // NewMetricsModel create a model for contain different metric data
-func NewMetricsModel(tablesConfig MetricTablesConfigMapper) map[pmetric.MetricType]MetricsModel {
+func NewMetricsModel(tablesConfig MetricTablesConfigMapper, columnValues map[pmetric.MetricType]ColumnValues) map[pmetric.MetricType]MetricsModel {
 	return map[pmetric.MetricType]MetricsModel{
 		pmetric.MetricTypeGauge: &gaugeMetrics{
-			insertSQL: fmt.Sprintf(insertGaugeTableSQL, tablesConfig[pmetric.MetricTypeGauge].Name),
+			insertSQL: renderInsertMetricsSQL(insertGaugeTableSQL, tablesConfig[pmetric.MetricTypeGauge]),
+			columns:   columnValues[pmetric.MetricTypeGauge],
 		},
 		pmetric.MetricTypeSum: &sumMetrics{
-			insertSQL: fmt.Sprintf(insertSumTableSQL, tablesConfig[pmetric.MetricTypeSum].Name),
+			insertSQL: renderInsertMetricsSQL(insertSumTableSQL, tablesConfig[pmetric.MetricTypeSum]),
+			columns:   columnValues[pmetric.MetricTypeSum],
 		},
 		pmetric.MetricTypeHistogram: &histogramMetrics{
-			insertSQL: fmt.Sprintf(insertHistogramTableSQL, tablesConfig[pmetric.MetricTypeHistogram].Name),
+			insertSQL: renderInsertMetricsSQL(insertHistogramTableSQL, tablesConfig[pmetric.MetricTypeHistogram]),
+			columns:   columnValues[pmetric.MetricTypeHistogram],
 		},
 		pmetric.MetricTypeExponentialHistogram: &expHistogramMetrics{
-			insertSQL: fmt.Sprintf(insertExpHistogramTableSQL, tablesConfig[pmetric.MetricTypeExponentialHistogram].Name),
+			insertSQL: renderInsertMetricsSQL(insertExpHistogramTableSQL, tablesConfig[pmetric.MetricTypeExponentialHistogram]),
+			columns:   columnValues[pmetric.MetricTypeExponentialHistogram],
 		},
 		pmetric.MetricTypeSummary: &summaryMetrics{
-			insertSQL: fmt.Sprintf(insertSummaryTableSQL, tablesConfig[pmetric.MetricTypeSummary].Name),
+			insertSQL: renderInsertMetricsSQL(insertSummaryTableSQL, tablesConfig[pmetric.MetricTypeSummary]),
+			columns:   columnValues[pmetric.MetricTypeSummary],
 		},
 	}
 }
 
+func renderInsertMetricsSQL(queryTemplate string, tableConfig MetricTypeConfig) string {
+	return fmt.Sprintf(queryTemplate, tableConfig.Name, tableConfig.Columns.InsertColumnsSQL(), tableConfig.Columns.InsertPlaceholdersSQL())
+}
+
 // InsertMetrics insert metric data into clickhouse concurrently
 func InsertMetrics(ctx context.Context, db *sql.DB, metricsMap map[pmetric.MetricType]MetricsModel) error {
 	errsChan := make(chan error, len(supportedMetricTypes))
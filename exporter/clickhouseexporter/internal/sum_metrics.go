@@ -42,13 +42,13 @@ CREATE TABLE IF NOT EXISTS %s %s (
 		TraceId String
     ) CODEC(ZSTD(1)),
     AggregationTemporality Int32 CODEC(ZSTD(1)),
-	IsMonotonic Boolean CODEC(Delta, ZSTD(1)),
+	IsMonotonic Boolean CODEC(Delta, ZSTD(1)),%s
 	INDEX idx_res_attr_key mapKeys(ResourceAttributes) TYPE bloom_filter(0.01) GRANULARITY 1,
 	INDEX idx_res_attr_value mapValues(ResourceAttributes) TYPE bloom_filter(0.01) GRANULARITY 1,
 	INDEX idx_scope_attr_key mapKeys(ScopeAttributes) TYPE bloom_filter(0.01) GRANULARITY 1,
 	INDEX idx_scope_attr_value mapValues(ScopeAttributes) TYPE bloom_filter(0.01) GRANULARITY 1,
 	INDEX idx_attr_key mapKeys(Attributes) TYPE bloom_filter(0.01) GRANULARITY 1,
-	INDEX idx_attr_value mapValues(Attributes) TYPE bloom_filter(0.01) GRANULARITY 1
+	INDEX idx_attr_value mapValues(Attributes) TYPE bloom_filter(0.01) GRANULARITY 1%s
 ) ENGINE = %s
 %s
 PARTITION BY toDate(TimeUnix)
@@ -79,7 +79,7 @@ SETTINGS index_granularity=8192, ttl_only_drop_parts = 1;
     Exemplars.SpanId,
     Exemplars.TraceId,
 	AggregationTemporality,
-	IsMonotonic) VALUES (?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?)`
+	IsMonotonic%s) VALUES (?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?%s)`
 )
 
 type sumModel struct {
@@ -93,6 +93,7 @@ type sumModel struct {
 type sumMetrics struct {
 	sumModel  []*sumModel
 	insertSQL string
+	columns   ColumnValues
 	count     int
 }
 
@@ -119,7 +120,7 @@ func (s *sumMetrics) insert(ctx context.Context, db *sql.DB) error {
 			for i := 0; i < model.sum.DataPoints().Len(); i++ {
 				dp := model.sum.DataPoints().At(i)
 				attrs, times, values, traceIDs, spanIDs := convertExemplars(dp.Exemplars())
-				_, err = statement.ExecContext(ctx,
+				args := []any{
 					resAttr,
 					model.metadata.ResURL,
 					model.metadata.ScopeInstr.Name(),
@@ -143,7 +144,9 @@ func (s *sumMetrics) insert(ctx context.Context, db *sql.DB) error {
 					traceIDs,
 					int32(model.sum.AggregationTemporality()),
 					model.sum.IsMonotonic(),
-				)
+				}
+				args = s.columns.AppendValues(args, model.metadata.ResAttr, model.metadata.ScopeInstr.Attributes(), dp.Attributes())
+				_, err = statement.ExecContext(ctx, args...)
 				if err != nil {
 					return fmt.Errorf("ExecContext:%w", err)
 				}
@@ -38,13 +38,13 @@ CREATE TABLE IF NOT EXISTS %s %s (
 		Quantile Float64,
 		Value Float64
 	) CODEC(ZSTD(1)),
-    Flags UInt32  CODEC(ZSTD(1)),
+    Flags UInt32  CODEC(ZSTD(1)),%s
 	INDEX idx_res_attr_key mapKeys(ResourceAttributes) TYPE bloom_filter(0.01) GRANULARITY 1,
 	INDEX idx_res_attr_value mapValues(ResourceAttributes) TYPE bloom_filter(0.01) GRANULARITY 1,
 	INDEX idx_scope_attr_key mapKeys(ScopeAttributes) TYPE bloom_filter(0.01) GRANULARITY 1,
 	INDEX idx_scope_attr_value mapValues(ScopeAttributes) TYPE bloom_filter(0.01) GRANULARITY 1,
 	INDEX idx_attr_key mapKeys(Attributes) TYPE bloom_filter(0.01) GRANULARITY 1,
-	INDEX idx_attr_value mapValues(Attributes) TYPE bloom_filter(0.01) GRANULARITY 1
+	INDEX idx_attr_value mapValues(Attributes) TYPE bloom_filter(0.01) GRANULARITY 1%s
 ) ENGINE = %s
 %s
 PARTITION BY toDate(TimeUnix)
@@ -71,7 +71,7 @@ SETTINGS index_granularity=8192, ttl_only_drop_parts = 1;
     Sum,
     ValueAtQuantiles.Quantile,
 	ValueAtQuantiles.Value,
-    Flags) VALUES (?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?)`
+    Flags%s) VALUES (?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?%s)`
 )
 
 type summaryModel struct {
@@ -85,6 +85,7 @@ type summaryModel struct {
 type summaryMetrics struct {
 	summaryModel []*summaryModel
 	insertSQL    string
+	columns      ColumnValues
 	count        int
 }
 
@@ -110,7 +111,7 @@ func (s *summaryMetrics) insert(ctx context.Context, db *sql.DB) error {
 				dp := model.summary.DataPoints().At(i)
 				quantiles, values := convertValueAtQuantile(dp.QuantileValues())
 
-				_, err = statement.ExecContext(ctx,
+				args := []any{
 					resAttr,
 					model.metadata.ResURL,
 					model.metadata.ScopeInstr.Name(),
@@ -130,7 +131,9 @@ func (s *summaryMetrics) insert(ctx context.Context, db *sql.DB) error {
 					quantiles,
 					values,
 					uint32(dp.Flags()),
-				)
+				}
+				args = s.columns.AppendValues(args, model.metadata.ResAttr, model.metadata.ScopeInstr.Attributes(), dp.Attributes())
+				_, err = statement.ExecContext(ctx, args...)
 				if err != nil {
 					return fmt.Errorf("ExecContext:%w", err)
 				}
@@ -0,0 +1,192 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package clickhouseexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/clickhouseexporter"
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// schemaVersionCommentPrefix prefixes the schema version stored in the comment of the tables.
+// The version isn't stored in the tables with another comment, so that the comment is kept.
+const schemaVersionCommentPrefix = "otel_schema_version="
+
+// migration is a versioned change of the schema of a table.
+// The statements must be idempotent, since several exporters may apply them concurrently,
+// and they are applied again at each start to the tables whose comment doesn't store the version.
+type migration struct {
+	version     int
+	description string
+	statements  []string
+	// columns are the columns of the table once the migration is applied. The columns of the tables created
+	// before the schema was versioned are checked against them, since the statements don't alter these tables.
+	columns []string
+}
+
+// tableSchema is the schema version of a table, read from its comment.
+type tableSchema struct {
+	exists  bool
+	version int
+	// commented is true if the comment of the table doesn't store a schema version.
+	commented bool
+}
+
+// migrateTable applies the migrations of a table newer than the schema version stored in its comment,
+// then stores the version of the last applied migration.
+func migrateTable(ctx context.Context, logger *zap.Logger, cfg *Config, db *sql.DB, table string, migrations []migration) error {
+	schema, err := readTableSchema(ctx, db, table)
+	if err != nil {
+		return fmt.Errorf("read schema version of table %s: %w", table, err)
+	}
+
+	latest := migrations[len(migrations)-1].version
+	if schema.version > latest {
+		logger.Warn("The schema of the table is newer than the one of the exporter, skipping migrations",
+			zap.String("table", table), zap.Int("version", schema.version), zap.Int("exporter_version", latest))
+		return nil
+	}
+	if schema.commented {
+		logger.Warn("The table has a comment, the schema version isn't stored and the migrations are applied at each start",
+			zap.String("table", table))
+	}
+
+	unversioned := schema.exists && schema.version == 0
+	for _, m := range migrations {
+		if m.version <= schema.version {
+			continue
+		}
+		for _, statement := range m.statements {
+			if _, err := db.ExecContext(ctx, statement); err != nil {
+				return fmt.Errorf("exec %s sql: %w", m.description, err)
+			}
+		}
+		if unversioned {
+			if err := checkColumns(ctx, db, table, m); err != nil {
+				return err
+			}
+		}
+		if schema.commented {
+			continue
+		}
+		if _, err := db.ExecContext(ctx, renderSchemaVersionSQL(cfg, table, m.version)); err != nil {
+			return fmt.Errorf("write schema version of table %s: %w", table, err)
+		}
+		logger.Info("Applied schema migration", zap.String("table", table), zap.Int("version", m.version),
+			zap.String("description", m.description))
+	}
+	return nil
+}
+
+// readTableSchema returns the schema version of a table, 0 if the table doesn't exist or was created
+// before the schema was versioned.
+func readTableSchema(ctx context.Context, db *sql.DB, table string) (tableSchema, error) {
+	var comment string
+	err := db.QueryRowContext(ctx, selectTableCommentSQL, table).Scan(&comment)
+	if errors.Is(err, sql.ErrNoRows) {
+		return tableSchema{}, nil
+	}
+	if err != nil {
+		return tableSchema{}, err
+	}
+	return tableSchema{
+		exists:    true,
+		version:   parseSchemaVersion(comment),
+		commented: comment != "" && !strings.HasPrefix(comment, schemaVersionCommentPrefix),
+	}, nil
+}
+
+// checkColumns checks that an existing table has the columns of a migration: the tables created by older versions
+// of the exporter may lack some of them, and the inserts would fail otherwise.
+func checkColumns(ctx context.Context, db *sql.DB, table string, m migration) error {
+	rows, err := db.QueryContext(ctx, selectColumnNamesSQL, table)
+	if err != nil {
+		return fmt.Errorf("read columns of table %s: %w", table, err)
+	}
+	defer rows.Close()
+
+	existing := map[string]struct{}{}
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return fmt.Errorf("read columns of table %s: %w", table, err)
+		}
+		// the fields of the nested columns are flattened, e.g. Events.Name
+		name, _, _ = strings.Cut(name, ".")
+		existing[name] = struct{}{}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("read columns of table %s: %w", table, err)
+	}
+
+	var missing []string
+	for _, column := range m.columns {
+		if _, ok := existing[column]; !ok {
+			missing = append(missing, column)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("the table %s lacks the columns %s of schema version %d, it was likely created by an older "+
+			"version of the exporter: add the columns or create a new table", table, strings.Join(missing, ", "), m.version)
+	}
+	return nil
+}
+
+// checkAttributesType checks that the attributes column of an existing table has the type configured by
+// attributes_type: the migrations don't convert the attributes columns, and the inserts would fail otherwise.
+func checkAttributesType(ctx context.Context, cfg *Config, db *sql.DB, table, column string) error {
+	var columnType string
+	err := db.QueryRowContext(ctx, selectColumnTypeSQL, table, column).Scan(&columnType)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("read type of column %s of table %s: %w", column, table, err)
+	}
+
+	attributesType := attributesTypeMap
+	if strings.HasPrefix(columnType, "JSON") {
+		attributesType = attributesTypeJSON
+	}
+	if configured := cfg.attributesType(); attributesType != configured {
+		return fmt.Errorf("the column %s of table %s has type %s, which doesn't match attributes_type %q: "+
+			"set attributes_type to %q or create a new table", column, table, columnType, configured, attributesType)
+	}
+	return nil
+}
+
+func parseSchemaVersion(comment string) int {
+	v, ok := strings.CutPrefix(comment, schemaVersionCommentPrefix)
+	if !ok {
+		return 0
+	}
+	version, err := strconv.Atoi(v)
+	if err != nil {
+		return 0
+	}
+	return version
+}
+
+const (
+	// language=ClickHouse SQL
+	selectTableCommentSQL = `SELECT comment FROM system.tables WHERE database = currentDatabase() AND name = ?`
+	// language=ClickHouse SQL
+	selectColumnTypeSQL = `SELECT type FROM system.columns WHERE database = currentDatabase() AND table = ? AND name = ?`
+	// language=ClickHouse SQL
+	selectColumnNamesSQL = `SELECT name FROM system.columns WHERE database = currentDatabase() AND table = ?`
+	// language=ClickHouse SQL
+	alterTableCommentSQL = `
+ALTER TABLE %s %s
+MODIFY COMMENT '%s%d';
+`
+)
+
+func renderSchemaVersionSQL(cfg *Config, table string, version int) string {
+	return fmt.Sprintf(alterTableCommentSQL, table, cfg.clusterString(), schemaVersionCommentPrefix, version)
+}
@@ -0,0 +1,276 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package clickhouseexporter
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/clickhouseexporter/internal"
+)
+
+var testMigrations = []migration{
+	{version: 1, description: "create test table", statements: []string{"CREATE TABLE IF NOT EXISTS test"}, columns: []string{"Timestamp", "Events"}},
+	{version: 2, description: "add test column", statements: []string{"ALTER TABLE test ADD COLUMN IF NOT EXISTS a String", "ALTER TABLE test ADD COLUMN IF NOT EXISTS b String"}, columns: []string{"Timestamp", "Events", "a", "b"}},
+	{version: 3, description: "add test index", statements: []string{"ALTER TABLE test ADD INDEX IF NOT EXISTS idx_a a TYPE set(100)"}, columns: []string{"Timestamp", "Events", "a", "b"}},
+}
+
+// testTable is the comment and the columns of the test table, the table doesn't exist if comment is nil.
+type testTable struct {
+	comment *string
+	columns []string
+}
+
+func migrateTestTable(t *testing.T, table testTable) ([]string, error) {
+	var queries []string
+	initClickhouseTestServerWithQuerier(t, func(query string, _ []driver.Value) error {
+		queries = append(queries, strings.TrimSpace(query))
+		return nil
+	}, func(query string, values []driver.Value) [][]driver.Value {
+		require.Equal(t, []driver.Value{"test"}, values)
+		switch query {
+		case selectTableCommentSQL:
+			if table.comment == nil {
+				return nil
+			}
+			return [][]driver.Value{{*table.comment}}
+		case selectColumnNamesSQL:
+			var rows [][]driver.Value
+			for _, column := range table.columns {
+				rows = append(rows, []driver.Value{column})
+			}
+			return rows
+		}
+		require.Fail(t, "unexpected query", query)
+		return nil
+	})
+
+	cfg := withTestExporterConfig()(defaultEndpoint)
+	db, err := newClickhouseClient(cfg)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	err = migrateTable(context.Background(), zaptest.NewLogger(t), cfg, db, "test", testMigrations)
+	return queries, err
+}
+
+func comment(c string) *string {
+	return &c
+}
+
+func TestMigrateTable(t *testing.T) {
+	t.Run("new table", func(t *testing.T) {
+		queries, err := migrateTestTable(t, testTable{})
+		require.NoError(t, err)
+		require.Equal(t, []string{
+			"CREATE TABLE IF NOT EXISTS test",
+			"ALTER TABLE test \nMODIFY COMMENT 'otel_schema_version=1';",
+			"ALTER TABLE test ADD COLUMN IF NOT EXISTS a String",
+			"ALTER TABLE test ADD COLUMN IF NOT EXISTS b String",
+			"ALTER TABLE test \nMODIFY COMMENT 'otel_schema_version=2';",
+			"ALTER TABLE test ADD INDEX IF NOT EXISTS idx_a a TYPE set(100)",
+			"ALTER TABLE test \nMODIFY COMMENT 'otel_schema_version=3';",
+		}, queries)
+	})
+	t.Run("table created before the schema was versioned", func(t *testing.T) {
+		queries, err := migrateTestTable(t, testTable{
+			comment: comment(""),
+			columns: []string{"Timestamp", "Events.Timestamp", "Events.Name", "a", "b"},
+		})
+		require.NoError(t, err)
+		require.Len(t, queries, 7)
+	})
+	t.Run("table created before the schema was versioned with missing columns", func(t *testing.T) {
+		queries, err := migrateTestTable(t, testTable{
+			comment: comment(""),
+			columns: []string{"Timestamp"},
+		})
+		require.EqualError(t, err, "the table test lacks the columns Events of schema version 1, it was likely created "+
+			"by an older version of the exporter: add the columns or create a new table")
+		require.Equal(t, []string{"CREATE TABLE IF NOT EXISTS test"}, queries, "the version isn't stored")
+	})
+	t.Run("table with a comment", func(t *testing.T) {
+		queries, err := migrateTestTable(t, testTable{
+			comment: comment("a table comment"),
+			columns: []string{"Timestamp", "Events", "a", "b"},
+		})
+		require.NoError(t, err)
+		require.Equal(t, []string{
+			"CREATE TABLE IF NOT EXISTS test",
+			"ALTER TABLE test ADD COLUMN IF NOT EXISTS a String",
+			"ALTER TABLE test ADD COLUMN IF NOT EXISTS b String",
+			"ALTER TABLE test ADD INDEX IF NOT EXISTS idx_a a TYPE set(100)",
+		}, queries, "the comment isn't replaced by the version")
+	})
+	t.Run("outdated table", func(t *testing.T) {
+		queries, err := migrateTestTable(t, testTable{comment: comment("otel_schema_version=2")})
+		require.NoError(t, err)
+		require.Equal(t, []string{
+			"ALTER TABLE test ADD INDEX IF NOT EXISTS idx_a a TYPE set(100)",
+			"ALTER TABLE test \nMODIFY COMMENT 'otel_schema_version=3';",
+		}, queries)
+	})
+	t.Run("up to date table", func(t *testing.T) {
+		queries, err := migrateTestTable(t, testTable{comment: comment("otel_schema_version=3")})
+		require.NoError(t, err)
+		require.Empty(t, queries)
+	})
+	t.Run("table newer than the exporter", func(t *testing.T) {
+		queries, err := migrateTestTable(t, testTable{comment: comment("otel_schema_version=4")})
+		require.NoError(t, err)
+		require.Empty(t, queries)
+	})
+}
+
+func TestMigrateTableFailure(t *testing.T) {
+	var queries []string
+	initClickhouseTestServer(t, func(query string, _ []driver.Value) error {
+		queries = append(queries, strings.TrimSpace(query))
+		if strings.Contains(query, "ADD COLUMN") {
+			return errors.New("mock alter error")
+		}
+		return nil
+	})
+	cfg := withTestExporterConfig()(defaultEndpoint)
+	db, err := newClickhouseClient(cfg)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	err = migrateTable(context.Background(), zaptest.NewLogger(t), cfg, db, "test", testMigrations)
+
+	require.ErrorContains(t, err, "exec add test column sql: mock alter error")
+	require.Equal(t, []string{
+		"CREATE TABLE IF NOT EXISTS test",
+		"ALTER TABLE test \nMODIFY COMMENT 'otel_schema_version=1';",
+		"ALTER TABLE test ADD COLUMN IF NOT EXISTS a String",
+	}, queries, "the version of a failed migration isn't written, so that it's applied again on the next start")
+}
+
+func TestParseSchemaVersion(t *testing.T) {
+	require.Equal(t, 0, parseSchemaVersion(""))
+	require.Equal(t, 0, parseSchemaVersion("the logs table"))
+	require.Equal(t, 0, parseSchemaVersion("otel_schema_version=x"))
+	require.Equal(t, 12, parseSchemaVersion("otel_schema_version=12"))
+}
+
+func TestLogsTableMigrationsAlterColumns(t *testing.T) {
+	var queries []string
+	initClickhouseTestServerWithQuerier(t, func(query string, _ []driver.Value) error {
+		queries = append(queries, strings.TrimSpace(query))
+		return nil
+	}, func(_ string, _ []driver.Value) [][]driver.Value {
+		return [][]driver.Value{{"otel_schema_version=1"}}
+	})
+
+	newTestLogsExporter(t, defaultEndpoint, func(cfg *Config) {
+		cfg.ClusterName = "cluster_a_b"
+		cfg.LogsColumns.Attributes = []internal.AttributeColumnConfig{{Name: "TenantId", Key: "tenant_id"}}
+		cfg.LogsColumns.Indexes = []internal.IndexConfig{{Name: "idx_tenant_id", Expression: "TenantId", Type: "set(100)"}}
+	})
+
+	require.Equal(t, []string{
+		"ALTER TABLE otel_logs ON CLUSTER cluster_a_b\n" +
+			"ADD COLUMN IF NOT EXISTS TenantId String CODEC(ZSTD(1)),\n" +
+			"ADD INDEX IF NOT EXISTS idx_tenant_id TenantId TYPE set(100) GRANULARITY 1;",
+	}, queries, "the configured columns are added to the existing table")
+}
+
+func TestMigrateUnversionedTableAttributesType(t *testing.T) {
+	// a table created before the schema was versioned, with the Map attributes columns
+	v0Querier := func(query string, values []driver.Value) [][]driver.Value {
+		switch query {
+		case selectTableCommentSQL:
+			return [][]driver.Value{{""}}
+		case selectColumnTypeSQL:
+			require.Equal(t, []driver.Value{"otel_logs", "LogAttributes"}, values)
+			return [][]driver.Value{{"Map(LowCardinality(String), String)"}}
+		case selectColumnNamesSQL:
+			var rows [][]driver.Value
+			for _, column := range internal.TableColumnNames(createLogsTableSQL) {
+				rows = append(rows, []driver.Value{column})
+			}
+			return rows
+		}
+		return nil
+	}
+
+	t.Run("same attributes type", func(t *testing.T) {
+		var queries []string
+		initClickhouseTestServerWithQuerier(t, func(query string, _ []driver.Value) error {
+			queries = append(queries, strings.TrimSpace(query))
+			return nil
+		}, v0Querier)
+
+		newTestLogsExporter(t, defaultEndpoint)
+
+		require.Len(t, queries, 2)
+		require.Contains(t, queries[0], "CREATE TABLE IF NOT EXISTS otel_logs")
+		require.Equal(t, "ALTER TABLE otel_logs \nMODIFY COMMENT 'otel_schema_version=1';", queries[1])
+	})
+	t.Run("different attributes type", func(t *testing.T) {
+		var queries []string
+		initClickhouseTestServerWithQuerier(t, func(query string, _ []driver.Value) error {
+			queries = append(queries, strings.TrimSpace(query))
+			return nil
+		}, v0Querier)
+
+		exporter, err := newLogsExporter(zaptest.NewLogger(t), withTestExporterConfig(func(cfg *Config) {
+			cfg.AttributesType = attributesTypeJSON
+		})(defaultEndpoint))
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = exporter.shutdown(context.Background()) })
+
+		err = exporter.start(context.Background(), nil)
+
+		require.EqualError(t, err, "the column LogAttributes of table otel_logs has type Map(LowCardinality(String), String), "+
+			`which doesn't match attributes_type "json": set attributes_type to "map" or create a new table`)
+		require.Empty(t, queries, "the table isn't migrated")
+	})
+}
+
+func TestCheckAttributesType(t *testing.T) {
+	tests := []struct {
+		name           string
+		columnType     string
+		attributesType string
+		wantErr        bool
+	}{
+		{name: "new table", attributesType: attributesTypeJSON},
+		{name: "map", columnType: "Map(LowCardinality(String), String)", attributesType: ""},
+		{name: "json", columnType: "JSON", attributesType: attributesTypeJSON},
+		{name: "json with parameters", columnType: "JSON(max_dynamic_paths = 1024)", attributesType: attributesTypeJSON},
+		{name: "json table with map attributes", columnType: "JSON", attributesType: attributesTypeMap, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			initClickhouseTestServerWithQuerier(t, func(string, []driver.Value) error {
+				return nil
+			}, func(string, []driver.Value) [][]driver.Value {
+				if tt.columnType == "" {
+					return nil
+				}
+				return [][]driver.Value{{tt.columnType}}
+			})
+			cfg := withTestExporterConfig(func(cfg *Config) {
+				cfg.AttributesType = tt.attributesType
+			})(defaultEndpoint)
+			db, err := newClickhouseClient(cfg)
+			require.NoError(t, err)
+			t.Cleanup(func() { _ = db.Close() })
+
+			err = checkAttributesType(context.Background(), cfg, db, "otel_traces", "SpanAttributes")
+			if tt.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
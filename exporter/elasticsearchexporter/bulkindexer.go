@@ -17,7 +17,11 @@ import (
 	"github.com/elastic/go-docappender/v2"
 	"github.com/elastic/go-elasticsearch/v7"
 	"go.opentelemetry.io/collector/config/configcompression"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
 	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/elasticsearchexporter/internal/metadata"
 )
 
 type bulkIndexer interface {
@@ -55,14 +59,14 @@ type bulkIndexerSession interface {
 
 const defaultMaxRetries = 2
 
-func newBulkIndexer(logger *zap.Logger, client *elasticsearch.Client, config *Config) (bulkIndexer, error) {
+func newBulkIndexer(logger *zap.Logger, client *elasticsearch.Client, config *Config, telemetryBuilder *metadata.TelemetryBuilder) (bulkIndexer, error) {
 	if config.Batcher.Enabled != nil {
-		return newSyncBulkIndexer(logger, client, config), nil
+		return newSyncBulkIndexer(logger, client, config, telemetryBuilder), nil
 	}
-	return newAsyncBulkIndexer(logger, client, config)
+	return newAsyncBulkIndexer(logger, client, config, telemetryBuilder)
 }
 
-func bulkIndexerConfig(client *elasticsearch.Client, config *Config) docappender.BulkIndexerConfig {
+func maxDocumentRetries(config *Config) int {
 	var maxDocRetries int
 	if config.Retry.Enabled {
 		maxDocRetries = defaultMaxRetries
@@ -70,6 +74,15 @@ func bulkIndexerConfig(client *elasticsearch.Client, config *Config) docappender
 			maxDocRetries = config.Retry.MaxRetries
 		}
 	}
+	return maxDocRetries
+}
+
+func bulkIndexerConfig(client *elasticsearch.Client, config *Config) docappender.BulkIndexerConfig {
+	maxDocRetries := maxDocumentRetries(config)
+	if config.DeadLetter.Enabled {
+		// document level retries are done by the dead letter queue
+		maxDocRetries = 0
+	}
 	var compressionLevel int
 	if config.Compression == configcompression.TypeGzip {
 		compressionLevel = gzip.BestSpeed
@@ -84,22 +97,26 @@ func bulkIndexerConfig(client *elasticsearch.Client, config *Config) docappender
 	}
 }
 
-func newSyncBulkIndexer(logger *zap.Logger, client *elasticsearch.Client, config *Config) *syncBulkIndexer {
+func newSyncBulkIndexer(logger *zap.Logger, client *elasticsearch.Client, config *Config, telemetryBuilder *metadata.TelemetryBuilder) *syncBulkIndexer {
 	return &syncBulkIndexer{
-		config:       bulkIndexerConfig(client, config),
-		flushTimeout: config.Timeout,
-		flushBytes:   config.Flush.Bytes,
-		retryConfig:  config.Retry,
-		logger:       logger,
+		config:           bulkIndexerConfig(client, config),
+		exporterConfig:   config,
+		flushTimeout:     config.Timeout,
+		flushBytes:       config.Flush.Bytes,
+		retryConfig:      config.Retry,
+		telemetryBuilder: telemetryBuilder,
+		logger:           logger,
 	}
 }
 
 type syncBulkIndexer struct {
-	config       docappender.BulkIndexerConfig
-	flushTimeout time.Duration
-	flushBytes   int
-	retryConfig  RetrySettings
-	logger       *zap.Logger
+	config           docappender.BulkIndexerConfig
+	exporterConfig   *Config
+	flushTimeout     time.Duration
+	flushBytes       int
+	retryConfig      RetrySettings
+	telemetryBuilder *metadata.TelemetryBuilder
+	logger           *zap.Logger
 }
 
 // StartSession creates a new docappender.BulkIndexer, and wraps
@@ -110,8 +127,9 @@ func (s *syncBulkIndexer) StartSession(context.Context) (bulkIndexerSession, err
 		return nil, err
 	}
 	return &syncBulkIndexerSession{
-		s:  s,
-		bi: bi,
+		s:   s,
+		bi:  bi,
+		dlq: newDeadLetterQueue(s.exporterConfig),
 	}, nil
 }
 
@@ -121,13 +139,14 @@ func (s *syncBulkIndexer) Close(context.Context) error {
 }
 
 type syncBulkIndexerSession struct {
-	s  *syncBulkIndexer
-	bi *docappender.BulkIndexer
+	s   *syncBulkIndexer
+	bi  *docappender.BulkIndexer
+	dlq *deadLetterQueue
 }
 
 // Add adds an item to the sync bulk indexer session.
 func (s *syncBulkIndexerSession) Add(ctx context.Context, index string, document io.WriterTo, dynamicTemplates map[string]string) error {
-	err := s.bi.Add(docappender.BulkIndexerItem{Index: index, Body: document, DynamicTemplates: dynamicTemplates})
+	err := addBulkIndexerItem(s.bi, s.dlq, docappender.BulkIndexerItem{Index: index, Body: document, DynamicTemplates: dynamicTemplates})
 	if err != nil {
 		return err
 	}
@@ -148,7 +167,7 @@ func (s *syncBulkIndexerSession) End() {
 func (s *syncBulkIndexerSession) Flush(ctx context.Context) error {
 	var retryBackoff func(int) time.Duration
 	for attempts := 0; ; attempts++ {
-		if _, err := flushBulkIndexer(ctx, s.bi, s.s.flushTimeout, s.s.logger); err != nil {
+		if _, err := flushBulkIndexer(ctx, s.bi, s.dlq, s.s.flushTimeout, s.s.telemetryBuilder, s.s.logger); err != nil {
 			return err
 		}
 		if s.bi.Items() == 0 {
@@ -175,7 +194,7 @@ func (s *syncBulkIndexerSession) Flush(ctx context.Context) error {
 	}
 }
 
-func newAsyncBulkIndexer(logger *zap.Logger, client *elasticsearch.Client, config *Config) (*asyncBulkIndexer, error) {
+func newAsyncBulkIndexer(logger *zap.Logger, client *elasticsearch.Client, config *Config, telemetryBuilder *metadata.TelemetryBuilder) (*asyncBulkIndexer, error) {
 	numWorkers := config.NumWorkers
 	if numWorkers == 0 {
 		numWorkers = runtime.NumCPU()
@@ -194,13 +213,15 @@ func newAsyncBulkIndexer(logger *zap.Logger, client *elasticsearch.Client, confi
 			return nil, err
 		}
 		w := asyncBulkIndexerWorker{
-			indexer:       bi,
-			items:         pool.items,
-			flushInterval: config.Flush.Interval,
-			flushTimeout:  config.Timeout,
-			flushBytes:    config.Flush.Bytes,
-			logger:        logger,
-			stats:         &pool.stats,
+			indexer:          bi,
+			deadLetterQueue:  newDeadLetterQueue(config),
+			items:            pool.items,
+			flushInterval:    config.Flush.Interval,
+			flushTimeout:     config.Timeout,
+			flushBytes:       config.Flush.Bytes,
+			telemetryBuilder: telemetryBuilder,
+			logger:           logger,
+			stats:            &pool.stats,
 		}
 		go func() {
 			defer pool.wg.Done()
@@ -272,15 +293,17 @@ func (s asyncBulkIndexerSession) Flush(context.Context) error {
 }
 
 type asyncBulkIndexerWorker struct {
-	indexer       *docappender.BulkIndexer
-	items         <-chan docappender.BulkIndexerItem
-	flushInterval time.Duration
-	flushTimeout  time.Duration
-	flushBytes    int
+	indexer         *docappender.BulkIndexer
+	deadLetterQueue *deadLetterQueue
+	items           <-chan docappender.BulkIndexerItem
+	flushInterval   time.Duration
+	flushTimeout    time.Duration
+	flushBytes      int
 
 	stats *bulkIndexerStats
 
-	logger *zap.Logger
+	telemetryBuilder *metadata.TelemetryBuilder
+	logger           *zap.Logger
 }
 
 func (w *asyncBulkIndexerWorker) run() {
@@ -295,7 +318,7 @@ func (w *asyncBulkIndexerWorker) run() {
 				return
 			}
 
-			if err := w.indexer.Add(item); err != nil {
+			if err := addBulkIndexerItem(w.indexer, w.deadLetterQueue, item); err != nil {
 				w.logger.Error("error adding item to bulk indexer", zap.Error(err))
 			}
 
@@ -315,14 +338,23 @@ func (w *asyncBulkIndexerWorker) run() {
 
 func (w *asyncBulkIndexerWorker) flush() {
 	ctx := context.Background()
-	stat, _ := flushBulkIndexer(ctx, w.indexer, w.flushTimeout, w.logger)
+	stat, _ := flushBulkIndexer(ctx, w.indexer, w.deadLetterQueue, w.flushTimeout, w.telemetryBuilder, w.logger)
 	w.stats.docsIndexed.Add(stat.Indexed)
 }
 
+func addBulkIndexerItem(bi *docappender.BulkIndexer, dlq *deadLetterQueue, item docappender.BulkIndexerItem) error {
+	if dlq != nil {
+		return dlq.add(bi, item)
+	}
+	return bi.Add(item)
+}
+
 func flushBulkIndexer(
 	ctx context.Context,
 	bi *docappender.BulkIndexer,
+	dlq *deadLetterQueue,
 	timeout time.Duration,
+	telemetryBuilder *metadata.TelemetryBuilder,
 	logger *zap.Logger,
 ) (docappender.BulkIndexerResponseStat, error) {
 	if timeout > 0 {
@@ -334,16 +366,41 @@ func flushBulkIndexer(
 	if err != nil {
 		logger.Error("bulk indexer flush error", zap.Error(err))
 	}
-	for _, resp := range stat.FailedDocs {
+	var rejected []rejectedDoc
+	if dlq != nil {
+		rejected = dlq.handleFailedDocs(bi, stat.FailedDocs)
+	} else {
+		for _, resp := range stat.FailedDocs {
+			rejected = append(rejected, rejectedDoc{BulkIndexerResponseItem: resp})
+		}
+	}
+	for _, doc := range rejected {
 		fields := []zap.Field{
-			zap.String("index", resp.Index),
-			zap.String("error.type", resp.Error.Type),
-			zap.String("error.reason", resp.Error.Reason),
+			zap.String("index", doc.Index),
+			zap.String("error.type", doc.Error.Type),
+			zap.String("error.reason", doc.Error.Reason),
 		}
-		if hint := getErrorHint(resp.Index, resp.Error.Type); hint != "" {
+		if hint := getErrorHint(doc.Index, doc.Error.Type); hint != "" {
 			fields = append(fields, zap.String("hint", hint))
 		}
+		if doc.deadLettered {
+			fields = append(fields, zap.String("dead_letter_index", dlq.index))
+		}
 		logger.Error("failed to index document", fields...)
+
+		errorType := attribute.String("error.type", doc.Error.Type)
+		telemetryBuilder.ElasticsearchDocsRejected.Add(ctx, 1, metric.WithAttributes(
+			errorType, attribute.Int("http.response.status_code", doc.Status),
+		))
+		if doc.deadLettered {
+			telemetryBuilder.ElasticsearchDocsDeadLettered.Add(ctx, 1, metric.WithAttributes(errorType))
+		}
+	}
+	if dlq != nil && bi.Items() > 0 && !dlq.pendingRetries() {
+		// Write the rejected documents to the dead letter index right away rather than with the next flush,
+		// which may never happen. Failures are logged by the nested flush, the original documents were
+		// already handled.
+		_, _ = flushBulkIndexer(ctx, bi, dlq, 0, telemetryBuilder, logger)
 	}
 	return stat, err
 }
@@ -16,10 +16,18 @@ import (
 	"github.com/elastic/go-elasticsearch/v7"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/config/confighttp"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata/metricdatatest"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/elasticsearchexporter/internal/metadata"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/elasticsearchexporter/internal/metadatatest"
 )
 
 var defaultRoundTripFunc = func(*http.Request) (*http.Response, error) {
@@ -97,7 +105,7 @@ func TestAsyncBulkIndexer_flush(t *testing.T) {
 			}})
 			require.NoError(t, err)
 
-			bulkIndexer, err := newAsyncBulkIndexer(zap.NewNop(), client, &tt.config)
+			bulkIndexer, err := newAsyncBulkIndexer(zap.NewNop(), client, &tt.config, newTestTelemetryBuilder(t))
 			require.NoError(t, err)
 			session, err := bulkIndexer.StartSession(context.Background())
 			require.NoError(t, err)
@@ -222,7 +230,7 @@ func TestAsyncBulkIndexer_flush_error(t *testing.T) {
 			require.NoError(t, err)
 			core, observed := observer.New(zap.NewAtomicLevelAt(zapcore.DebugLevel))
 
-			bulkIndexer, err := newAsyncBulkIndexer(zap.New(core), client, &cfg)
+			bulkIndexer, err := newAsyncBulkIndexer(zap.New(core), client, &cfg, newTestTelemetryBuilder(t))
 			require.NoError(t, err)
 			defer bulkIndexer.Close(context.Background())
 
@@ -306,8 +314,14 @@ func TestAsyncBulkIndexer_logRoundTrip(t *testing.T) {
 	}
 }
 
+func newTestTelemetryBuilder(t *testing.T) *metadata.TelemetryBuilder {
+	telemetryBuilder, err := metadata.NewTelemetryBuilder(componenttest.NewNopTelemetrySettings())
+	require.NoError(t, err)
+	return telemetryBuilder
+}
+
 func runBulkIndexerOnce(t *testing.T, config *Config, client *elasticsearch.Client) *asyncBulkIndexer {
-	bulkIndexer, err := newAsyncBulkIndexer(zap.NewNop(), client, config)
+	bulkIndexer, err := newAsyncBulkIndexer(zap.NewNop(), client, config, newTestTelemetryBuilder(t))
 	require.NoError(t, err)
 	session, err := bulkIndexer.StartSession(context.Background())
 	require.NoError(t, err)
@@ -334,7 +348,7 @@ func TestSyncBulkIndexer_flushBytes(t *testing.T) {
 	}})
 	require.NoError(t, err)
 
-	bi := newSyncBulkIndexer(zap.NewNop(), client, &cfg)
+	bi := newSyncBulkIndexer(zap.NewNop(), client, &cfg, newTestTelemetryBuilder(t))
 	session, err := bi.StartSession(context.Background())
 	require.NoError(t, err)
 
@@ -342,3 +356,186 @@ func TestSyncBulkIndexer_flushBytes(t *testing.T) {
 	assert.Equal(t, int64(1), reqCnt.Load()) // flush due to flush::bytes
 	assert.NoError(t, bi.Close(context.Background()))
 }
+
+// newBulkResponsesClient returns a client answering the bulk requests with the given responses, in order,
+// and the bodies of the bulk requests.
+func newBulkResponsesClient(t *testing.T, responses ...string) (*elasticsearch.Client, *[]string) {
+	var requests []string
+	client, err := elasticsearch.NewClient(elasticsearch.Config{Transport: &mockTransport{
+		RoundTripFunc: func(r *http.Request) (*http.Response, error) {
+			resp := successResp
+			if r.URL.Path == "/_bulk" {
+				body, err := io.ReadAll(r.Body)
+				require.NoError(t, err)
+				requests = append(requests, string(body))
+				require.LessOrEqual(t, len(requests), len(responses), "unexpected bulk request %q", body)
+				resp = responses[len(requests)-1]
+			}
+			return &http.Response{
+				Header: http.Header{"X-Elastic-Product": []string{"Elasticsearch"}},
+				Body:   io.NopCloser(strings.NewReader(resp)),
+			}, nil
+		},
+	}})
+	require.NoError(t, err)
+	return client, &requests
+}
+
+func TestSyncBulkIndexer_deadLetter(t *testing.T) {
+	const (
+		mappingConflictResp = `{"items":[
+			{"create":{"_index":"foo","status":400,"error":{"type":"mapper_parsing_exception","reason":"failed to parse field [foo]"}}},
+			{"create":{"_index":"foo","status":201}}]}`
+		deadLetterResp         = `{"items":[{"create":{"_index":"logs-elasticsearch.dead_letter-default","status":201}}]}`
+		rejectedDeadLetterResp = `{"items":[{"create":{"_index":"logs-elasticsearch.dead_letter-default","status":400,"error":{"type":"illegal_argument_exception","reason":"no write index"}}}]}`
+		tooManyRequestsResp    = `{"items":[
+			{"create":{"_index":"foo","status":429,"error":{"type":"es_rejected_execution_exception","reason":"rejected"}}},
+			{"create":{"_index":"foo","status":429,"error":{"type":"es_rejected_execution_exception","reason":"rejected"}}}]}`
+		retriedResp = `{"items":[
+			{"create":{"_index":"foo","status":201}},
+			{"create":{"_index":"foo","status":429,"error":{"type":"es_rejected_execution_exception","reason":"rejected"}}}]}`
+	)
+
+	rejectedMetric := func(dataPoints ...metricdata.DataPoint[int64]) metricdata.Metrics {
+		return metricdata.Metrics{
+			Name:        "otelcol_elasticsearch_docs_rejected",
+			Description: "Number of documents rejected by Elasticsearch and not retried, by error type.",
+			Unit:        "{documents}",
+			Data: metricdata.Sum[int64]{
+				Temporality: metricdata.CumulativeTemporality,
+				IsMonotonic: true,
+				DataPoints:  dataPoints,
+			},
+		}
+	}
+	rejectedDataPoint := func(errorType string, status int) metricdata.DataPoint[int64] {
+		return metricdata.DataPoint[int64]{
+			Attributes: attribute.NewSet(attribute.String("error.type", errorType), attribute.Int("http.response.status_code", status)),
+			Value:      1,
+		}
+	}
+	deadLetteredMetric := func(errorType string) metricdata.Metrics {
+		return metricdata.Metrics{
+			Name:        "otelcol_elasticsearch_docs_dead_lettered",
+			Description: "Number of rejected documents written to the dead letter index, by error type.",
+			Unit:        "{documents}",
+			Data: metricdata.Sum[int64]{
+				Temporality: metricdata.CumulativeTemporality,
+				IsMonotonic: true,
+				DataPoints: []metricdata.DataPoint[int64]{{
+					Attributes: attribute.NewSet(attribute.String("error.type", errorType)),
+					Value:      1,
+				}},
+			},
+		}
+	}
+
+	tests := []struct {
+		name      string
+		retry     RetrySettings
+		responses []string
+		// wantDeadLetter is the request writing the dead letter document, and the document it contains.
+		wantDeadLetter       int
+		wantDeadLetterSource string
+		wantDeadLetterError  string
+		wantMetrics          []metricdata.Metrics
+	}{
+		{
+			name:                 "mapping conflict",
+			responses:            []string{mappingConflictResp, deadLetterResp},
+			wantDeadLetter:       1,
+			wantDeadLetterSource: `{"foo":"bar"}`,
+			wantDeadLetterError:  `{"type":"mapper_parsing_exception","reason":"failed to parse field [foo]","status":400}`,
+			wantMetrics: []metricdata.Metrics{
+				rejectedMetric(rejectedDataPoint("mapper_parsing_exception", 400)),
+				deadLetteredMetric("mapper_parsing_exception"),
+			},
+		},
+		{
+			name: "retry limit reached",
+			retry: RetrySettings{
+				Enabled:         true,
+				MaxRetries:      1,
+				InitialInterval: time.Millisecond,
+				MaxInterval:     time.Millisecond,
+				RetryOnStatus:   []int{http.StatusTooManyRequests},
+			},
+			responses:            []string{tooManyRequestsResp, retriedResp, deadLetterResp},
+			wantDeadLetter:       2,
+			wantDeadLetterSource: `{"foo":{"bar":1}}`,
+			wantDeadLetterError:  `{"type":"es_rejected_execution_exception","reason":"rejected","status":429}`,
+			wantMetrics: []metricdata.Metrics{
+				rejectedMetric(rejectedDataPoint("es_rejected_execution_exception", 429)),
+				deadLetteredMetric("es_rejected_execution_exception"),
+			},
+		},
+		{
+			name:                 "dead letter rejected",
+			responses:            []string{mappingConflictResp, rejectedDeadLetterResp},
+			wantDeadLetter:       1,
+			wantDeadLetterSource: `{"foo":"bar"}`,
+			wantDeadLetterError:  `{"type":"mapper_parsing_exception","reason":"failed to parse field [foo]","status":400}`,
+			wantMetrics: []metricdata.Metrics{
+				rejectedMetric(
+					rejectedDataPoint("mapper_parsing_exception", 400),
+					rejectedDataPoint("illegal_argument_exception", 400),
+				),
+				deadLetteredMetric("mapper_parsing_exception"),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, requests := newBulkResponsesClient(t, tt.responses...)
+			testTel := metadatatest.SetupTelemetry()
+			telemetryBuilder, err := metadata.NewTelemetryBuilder(testTel.NewTelemetrySettings())
+			require.NoError(t, err)
+
+			cfg := Config{
+				Flush:      FlushSettings{Interval: time.Hour, Bytes: 2 << 30},
+				Retry:      tt.retry,
+				DeadLetter: DeadLetterSettings{Enabled: true, Index: defaultDeadLetterIndex},
+			}
+			bi := newSyncBulkIndexer(zap.NewNop(), client, &cfg, telemetryBuilder)
+			session, err := bi.StartSession(context.Background())
+			require.NoError(t, err)
+			require.NoError(t, session.Add(context.Background(), "foo", strings.NewReader(`{"foo":"bar"}`), nil))
+			require.NoError(t, session.Add(context.Background(), "foo", strings.NewReader(`{"foo":{"bar":1}}`), nil))
+			require.NoError(t, session.Flush(context.Background()))
+			session.End()
+
+			require.Len(t, *requests, len(tt.responses))
+			docs := "{\"create\":{\"_index\":\"foo\"}}\n{\"foo\":\"bar\"}\n{\"create\":{\"_index\":\"foo\"}}\n{\"foo\":{\"bar\":1}}\n"
+			assert.Equal(t, docs, (*requests)[0])
+			if tt.wantDeadLetter == 2 {
+				assert.Equal(t, docs, (*requests)[1], "the documents are retried in order")
+			}
+
+			action, doc, ok := strings.Cut((*requests)[tt.wantDeadLetter], "\n")
+			require.True(t, ok)
+			assert.Equal(t, `{"create":{"_index":"logs-elasticsearch.dead_letter-default"}}`, action)
+			assert.Equal(t, "foo", gjson.Get(doc, "document.index").String())
+			assert.Equal(t, tt.wantDeadLetterSource, gjson.Get(doc, "document.source").String())
+			assert.JSONEq(t, tt.wantDeadLetterError, gjson.Get(doc, "error").Raw)
+			assert.True(t, gjson.Get(doc, "@timestamp").Exists())
+
+			testTel.AssertMetrics(t, tt.wantMetrics, metricdatatest.IgnoreTimestamp())
+			require.NoError(t, testTel.Shutdown(context.Background()))
+		})
+	}
+}
+
+func TestAsyncBulkIndexer_deadLetterOnClose(t *testing.T) {
+	client, requests := newBulkResponsesClient(t, `{"items":[{"create":{"_index":"foo","status":400,"error":{"type":"mapper_parsing_exception","reason":"failed to parse field [foo]"}}}]}`, successResp)
+	cfg := Config{
+		NumWorkers: 1,
+		Flush:      FlushSettings{Interval: time.Hour, Bytes: 2 << 30},
+		DeadLetter: DeadLetterSettings{Enabled: true, Index: defaultDeadLetterIndex},
+	}
+
+	runBulkIndexerOnce(t, &cfg, client)
+
+	require.Len(t, *requests, 2, "the dead letter document is written before closing")
+	assert.True(t, strings.HasPrefix((*requests)[1], `{"create":{"_index":"logs-elasticsearch.dead_letter-default"}}`))
+}
@@ -66,6 +66,7 @@ type Config struct {
 	Flush                   FlushSettings          `mapstructure:"flush"`
 	Mapping                 MappingsSettings       `mapstructure:"mapping"`
 	LogstashFormat          LogstashFormatSettings `mapstructure:"logstash_format"`
+	DeadLetter              DeadLetterSettings     `mapstructure:"dead_letter"`
 
 	// TelemetrySettings contains settings useful for testing/debugging purposes
 	// This is experimental and may change at any time.
@@ -108,6 +109,19 @@ type LogstashFormatSettings struct {
 	DateFormat      string `mapstructure:"date_format"`
 }
 
+// DeadLetterSettings defines where the documents rejected by Elasticsearch are written.
+//
+// Rejected documents are the ones failing with a status not configured in retry::retry_on_status,
+// e.g. because of a mapping conflict, or reaching the retry limit.
+type DeadLetterSettings struct {
+	// Enabled writes the rejected documents, along with the reason of the rejection,
+	// to the dead letter index instead of dropping them.
+	Enabled bool `mapstructure:"enabled"`
+
+	// Index is the index or data stream the rejected documents are written to.
+	Index string `mapstructure:"index"`
+}
+
 type DynamicIndexSetting struct {
 	Enabled bool `mapstructure:"enabled"`
 }
@@ -273,6 +287,10 @@ func (cfg *Config) Validate() error {
 		return errors.New("retry::max_retries should be non-negative")
 	}
 
+	if cfg.DeadLetter.Enabled && cfg.DeadLetter.Index == "" {
+		return errors.New("dead_letter::index must be specified when dead_letter::enabled is true")
+	}
+
 	return nil
 }
 
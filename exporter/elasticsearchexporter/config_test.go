@@ -111,6 +111,10 @@ func TestConfig(t *testing.T) {
 					PrefixSeparator: "-",
 					DateFormat:      "%Y.%m.%d",
 				},
+				DeadLetter: DeadLetterSettings{
+					Enabled: false,
+					Index:   "logs-elasticsearch.dead_letter-default",
+				},
 				Batcher: BatcherConfig{
 					FlushTimeout: 30 * time.Second,
 					MinSizeConfig: exporterbatcher.MinSizeConfig{
@@ -182,6 +186,10 @@ func TestConfig(t *testing.T) {
 					PrefixSeparator: "-",
 					DateFormat:      "%Y.%m.%d",
 				},
+				DeadLetter: DeadLetterSettings{
+					Enabled: false,
+					Index:   "logs-elasticsearch.dead_letter-default",
+				},
 				Batcher: BatcherConfig{
 					FlushTimeout: 30 * time.Second,
 					MinSizeConfig: exporterbatcher.MinSizeConfig{
@@ -253,6 +261,10 @@ func TestConfig(t *testing.T) {
 					PrefixSeparator: "-",
 					DateFormat:      "%Y.%m.%d",
 				},
+				DeadLetter: DeadLetterSettings{
+					Enabled: false,
+					Index:   "logs-elasticsearch.dead_letter-default",
+				},
 				Batcher: BatcherConfig{
 					FlushTimeout: 30 * time.Second,
 					MinSizeConfig: exporterbatcher.MinSizeConfig{
@@ -316,6 +328,16 @@ func TestConfig(t *testing.T) {
 				cfg.Compression = "gzip"
 			}),
 		},
+		{
+			id:         component.NewIDWithName(metadata.Type, "dead_letter"),
+			configFile: "config.yaml",
+			expected: withDefaultConfig(func(cfg *Config) {
+				cfg.Endpoint = "https://elastic.example.com:9200"
+
+				cfg.DeadLetter.Enabled = true
+				cfg.DeadLetter.Index = "logs-otel.rejected-default"
+			}),
+		},
 	}
 
 	for _, tt := range tests {
@@ -414,6 +436,14 @@ func TestConfig_Validate(t *testing.T) {
 			}),
 			err: `must not specify both retry::max_requests and retry::max_retries`,
 		},
+		"dead letter index not specified": {
+			config: withDefaultConfig(func(cfg *Config) {
+				cfg.Endpoints = []string{"http://test:9200"}
+				cfg.DeadLetter.Enabled = true
+				cfg.DeadLetter.Index = ""
+			}),
+			err: `dead_letter::index must be specified when dead_letter::enabled is true`,
+		},
 	}
 
 	for name, tt := range tests {
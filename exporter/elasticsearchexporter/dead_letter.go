@@ -0,0 +1,166 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package elasticsearchexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/elasticsearchexporter"
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/elastic/go-docappender/v2"
+)
+
+// deadLetterQueue keeps a copy of the documents added to a bulk indexer, so that the ones rejected
+// by Elasticsearch can be written to the dead letter index along with the reason of the rejection.
+//
+// The bulk indexer only reports the position of the rejected documents in the flushed request.
+// Document level retries are therefore done by the queue instead of the bulk indexer,
+// which would otherwise reorder its buffer.
+//
+// A deadLetterQueue must only be used with the bulk indexer it was created for,
+// and is not safe for concurrent use.
+type deadLetterQueue struct {
+	index         string
+	maxRetries    int
+	retryOnStatus []int
+
+	// docs are the documents in the buffer of the bulk indexer, by position.
+	docs []deadLetterDoc
+}
+
+type deadLetterDoc struct {
+	index            string
+	dynamicTemplates map[string]string
+	body             []byte
+	retries          int
+
+	// deadLetter is set for the documents written to the dead letter index,
+	// which are dropped if rejected.
+	deadLetter bool
+}
+
+// rejectedDoc is a document rejected by Elasticsearch.
+type rejectedDoc struct {
+	docappender.BulkIndexerResponseItem
+
+	// deadLettered is set if the document was added to the bulk indexer for the dead letter index.
+	deadLettered bool
+}
+
+// deadLetterEvent is the document written to the dead letter index.
+// The error fields are named after the ones of the bulk API response.
+type deadLetterEvent struct {
+	Timestamp time.Time `json:"@timestamp"`
+	Document  struct {
+		Index  string `json:"index"`
+		Source string `json:"source"`
+	} `json:"document"`
+	Error struct {
+		Type   string `json:"type"`
+		Reason string `json:"reason"`
+		Status int    `json:"status"`
+	} `json:"error"`
+}
+
+// newDeadLetterQueue returns the dead letter queue of a bulk indexer, nil if disabled.
+func newDeadLetterQueue(config *Config) *deadLetterQueue {
+	if !config.DeadLetter.Enabled {
+		return nil
+	}
+	return &deadLetterQueue{
+		index:         config.DeadLetter.Index,
+		maxRetries:    maxDocumentRetries(config),
+		retryOnStatus: config.Retry.RetryOnStatus,
+	}
+}
+
+// add adds an item to the bulk indexer, keeping a copy of its document.
+func (q *deadLetterQueue) add(bi *docappender.BulkIndexer, item docappender.BulkIndexerItem) error {
+	var body bytes.Buffer
+	if _, err := item.Body.WriteTo(&body); err != nil {
+		return fmt.Errorf("failed to read document: %w", err)
+	}
+	return q.addDoc(bi, deadLetterDoc{
+		index:            item.Index,
+		dynamicTemplates: item.DynamicTemplates,
+		body:             body.Bytes(),
+	})
+}
+
+func (q *deadLetterQueue) addDoc(bi *docappender.BulkIndexer, doc deadLetterDoc) error {
+	err := bi.Add(docappender.BulkIndexerItem{
+		Index:            doc.index,
+		Body:             bytes.NewReader(doc.body),
+		DynamicTemplates: doc.dynamicTemplates,
+	})
+	if err != nil {
+		return err
+	}
+	q.docs = append(q.docs, doc)
+	return nil
+}
+
+// handleFailedDocs must be called after each flush of the bulk indexer with the documents it failed to index.
+// Documents failing with a status of retry::retry_on_status are added back to the bulk indexer until they
+// reach the retry limit. The other ones are rejected: they are added to the bulk indexer for the dead letter
+// index, unless they already were dead letters.
+func (q *deadLetterQueue) handleFailedDocs(bi *docappender.BulkIndexer, failedDocs []docappender.BulkIndexerResponseItem) []rejectedDoc {
+	if bi.Items() != 0 {
+		// The request wasn't sent, the documents are still in the buffer.
+		return nil
+	}
+	docs := q.docs
+	q.docs = nil
+
+	var rejected []rejectedDoc
+	for _, resp := range failedDocs {
+		if resp.Position < 0 || resp.Position >= len(docs) {
+			rejected = append(rejected, rejectedDoc{BulkIndexerResponseItem: resp})
+			continue
+		}
+		doc := docs[resp.Position]
+		if doc.retries < q.maxRetries && slices.Contains(q.retryOnStatus, resp.Status) {
+			doc.retries++
+			if err := q.addDoc(bi, doc); err == nil {
+				continue
+			}
+		}
+		r := rejectedDoc{BulkIndexerResponseItem: resp}
+		if !doc.deadLetter {
+			r.deadLettered = q.addDoc(bi, q.newDeadLetterDoc(doc, resp)) == nil
+		}
+		rejected = append(rejected, r)
+	}
+	return rejected
+}
+
+// pendingRetries reports whether the buffer of the bulk indexer contains documents to retry,
+// as opposed to only documents for the dead letter index.
+func (q *deadLetterQueue) pendingRetries() bool {
+	for _, doc := range q.docs {
+		if !doc.deadLetter {
+			return true
+		}
+	}
+	return false
+}
+
+func (q *deadLetterQueue) newDeadLetterDoc(doc deadLetterDoc, resp docappender.BulkIndexerResponseItem) deadLetterDoc {
+	var event deadLetterEvent
+	event.Timestamp = time.Now().UTC()
+	event.Document.Index = doc.index
+	event.Document.Source = string(doc.body)
+	event.Error.Type = resp.Error.Type
+	event.Error.Reason = resp.Error.Reason
+	event.Error.Status = resp.Status
+	// The event only contains strings and numbers, it can't fail to be marshaled.
+	body, _ := json.Marshal(event)
+	return deadLetterDoc{
+		index:      q.index,
+		body:       body,
+		deadLetter: true,
+	}
+}
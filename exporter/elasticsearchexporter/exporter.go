@@ -19,6 +19,7 @@ import (
 	"go.opentelemetry.io/collector/pdata/ptrace"
 	"go.uber.org/zap"
 
+	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/elasticsearchexporter/internal/metadata"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/elasticsearchexporter/internal/pool"
 )
 
@@ -79,7 +80,11 @@ func (e *elasticsearchExporter) Start(ctx context.Context, host component.Host)
 	if err != nil {
 		return err
 	}
-	bulkIndexer, err := newBulkIndexer(e.Logger, client, e.config)
+	telemetryBuilder, err := metadata.NewTelemetryBuilder(e.TelemetrySettings)
+	if err != nil {
+		return err
+	}
+	bulkIndexer, err := newBulkIndexer(e.Logger, client, e.config, telemetryBuilder)
 	if err != nil {
 		return err
 	}
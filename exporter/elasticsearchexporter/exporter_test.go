@@ -630,6 +630,30 @@ func TestExporterLogs(t *testing.T) {
 		assert.Equal(t, int64(1), attempts.Load())
 	})
 
+	t.Run("write bad item to dead letter index", func(t *testing.T) {
+		rec := newBulkRecorder()
+		server := newESTestServer(t, func(docs []itemRequest) ([]itemResponse, error) {
+			rec.Record(docs)
+			if gjson.GetBytes(docs[0].Action, "create._index").String() == "logs-elasticsearch.dead_letter-default" {
+				return itemsAllOK(docs)
+			}
+			return itemsReportStatus(docs, http.StatusBadRequest)
+		})
+
+		exporter := newTestLogsExporter(t, server.URL, func(cfg *Config) {
+			cfg.DeadLetter.Enabled = true
+		})
+		logRecord := plog.NewLogRecord()
+		logRecord.Body().SetStr("hello world")
+		mustSendLogRecords(t, exporter, logRecord)
+
+		rec.WaitItems(2)
+		items := rec.Items()
+		assert.Equal(t, string(items[0].Document), gjson.GetBytes(items[1].Document, "document.source").String())
+		assert.Equal(t, "logs-generic-default", gjson.GetBytes(items[1].Document, "document.index").String())
+		assert.Equal(t, int64(http.StatusBadRequest), gjson.GetBytes(items[1].Document, "error.status").Int())
+	})
+
 	t.Run("only retry failed items", func(t *testing.T) {
 		var attempts [3]int
 		var wg sync.WaitGroup
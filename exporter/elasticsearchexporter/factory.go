@@ -26,6 +26,8 @@ const (
 	defaultLogsIndex    = "logs-generic-default"
 	defaultMetricsIndex = "metrics-generic-default"
 	defaultTracesIndex  = "traces-generic-default"
+
+	defaultDeadLetterIndex = "logs-elasticsearch.dead_letter-default"
 )
 
 // NewFactory creates a factory for Elastic exporter.
@@ -80,6 +82,10 @@ func createDefaultConfig() component.Config {
 			PrefixSeparator: "-",
 			DateFormat:      "%Y.%m.%d",
 		},
+		DeadLetter: DeadLetterSettings{
+			Enabled: false,
+			Index:   defaultDeadLetterIndex,
+		},
 		TelemetrySettings: TelemetrySettings{
 			LogRequestBody:  false,
 			LogResponseBody: false,
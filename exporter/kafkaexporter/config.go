@@ -5,7 +5,6 @@ package kafkaexporter // import "github.com/open-telemetry/opentelemetry-collect
 
 import (
 	"fmt"
-	"time"
 
 	"github.com/IBM/sarama"
 	"go.opentelemetry.io/collector/component"
@@ -55,6 +54,10 @@ type Config struct {
 
 	PartitionLogsByResourceAttributes bool `mapstructure:"partition_logs_by_resource_attributes"`
 
+	// PartitionBy computes the topic and the key of the messages with OTTL
+	// value expressions.
+	PartitionBy PartitionBy `mapstructure:"partition_by"`
+
 	// Metadata is the namespace for metadata management properties used by the
 	// Client, and shared by the Producer/Consumer.
 	Metadata Metadata `mapstructure:"metadata"`
@@ -66,20 +69,21 @@ type Config struct {
 	Authentication kafka.Authentication `mapstructure:"auth"`
 }
 
-// Metadata defines configuration for retrieving metadata from the broker.
-type Metadata struct {
-	// Whether to maintain a full set of metadata for all topics, or just
-	// the minimal set that has been necessary so far. The full set is simpler
-	// and usually more convenient, but can take up a substantial amount of
-	// memory if you have many topics and partitions. Defaults to true.
-	Full bool `mapstructure:"full"`
-
-	// Retry configuration for metadata.
-	// This configuration is useful to avoid race conditions when broker
-	// is starting at the same time as collector.
-	Retry MetadataRetry `mapstructure:"retry"`
+// PartitionBy defines the OTTL value expressions evaluated for every span, data
+// point or log record to split the exported batches by topic and message key.
+type PartitionBy struct {
+	// Key is the expression of the message key, it takes precedence over the
+	// keys set by the partition_* options. Nil values leave the key unset.
+	Key string `mapstructure:"key"`
+
+	// Topic is the expression of the topic, it falls back to the topic of
+	// the exporter when it evaluates to nil or to an empty string.
+	Topic string `mapstructure:"topic"`
 }
 
+// Metadata defines configuration for retrieving metadata from the broker.
+type Metadata = kafka.Metadata
+
 // Producer defines configuration for producer
 type Producer struct {
 	// Maximum message bytes the producer will accept to produce.
@@ -102,17 +106,21 @@ type Producer struct {
 	// broker request. Defaults to 0 for unlimited. Similar to
 	// `queue.buffering.max.messages` in the JVM producer.
 	FlushMaxMessages int `mapstructure:"flush_max_messages"`
+
+	// Idempotent enables the idempotent producer, the brokers discard the
+	// duplicates of the messages retried by the producer. It requires
+	// required_acks -1 and a protocol_version of at least 0.11.0.
+	Idempotent bool `mapstructure:"idempotent"`
+
+	// TransactionalID enables the transactional producer, which implies the
+	// idempotent producer: each exported batch is written atomically within a
+	// transaction. The ID is suffixed with the host name and the signal type,
+	// and must be unique across the exporters of a collector.
+	TransactionalID string `mapstructure:"transactional_id"`
 }
 
 // MetadataRetry defines retry configuration for Metadata.
-type MetadataRetry struct {
-	// The total number of times to retry a metadata request when the
-	// cluster is in the middle of a leader election or at startup (default 3).
-	Max int `mapstructure:"max"`
-	// How long to wait for leader election to occur before retrying
-	// (default 250ms). Similar to the JVM's `retry.backoff.ms`.
-	Backoff time.Duration `mapstructure:"backoff"`
-}
+type MetadataRetry = kafka.MetadataRetry
 
 var _ component.Config = (*Config)(nil)
 
@@ -127,6 +135,18 @@ func (cfg *Config) Validate() error {
 		return err
 	}
 
+	if cfg.Producer.Idempotent || cfg.Producer.TransactionalID != "" {
+		if cfg.Producer.RequiredAcks != sarama.WaitForAll {
+			return fmt.Errorf("producer.required_acks has to be -1 for the idempotent or transactional producer. configured value %v", cfg.Producer.RequiredAcks)
+		}
+		if cfg.ProtocolVersion != "" {
+			version, err := sarama.ParseKafkaVersion(cfg.ProtocolVersion)
+			if err == nil && !version.IsAtLeast(sarama.V0_11_0_0) {
+				return fmt.Errorf("protocol_version has to be at least 0.11.0 for the idempotent or transactional producer. configured value %v", cfg.ProtocolVersion)
+			}
+		}
+	}
+
 	return validateSASLConfig(cfg.Authentication.SASL)
 }
 
@@ -60,8 +60,11 @@ func TestLoadConfig(t *testing.T) {
 				PartitionTracesByID:                  true,
 				PartitionMetricsByResourceAttributes: true,
 				PartitionLogsByResourceAttributes:    true,
-				Brokers:                              []string{"foo:123", "bar:456"},
-				ClientID:                             "test_client_id",
+				PartitionBy: PartitionBy{
+					Key: `resource.attributes["tenant"]`,
+				},
+				Brokers:  []string{"foo:123", "bar:456"},
+				ClientID: "test_client_id",
 				Authentication: kafka.Authentication{
 					PlainText: &kafka.PlainTextConfig{
 						Username: "jdoe",
@@ -79,6 +82,7 @@ func TestLoadConfig(t *testing.T) {
 					MaxMessageBytes: 10000000,
 					RequiredAcks:    sarama.WaitForAll,
 					Compression:     "none",
+					TransactionalID: "otelcol",
 				},
 			},
 		},
@@ -116,8 +120,11 @@ func TestLoadConfig(t *testing.T) {
 				PartitionTracesByID:                  true,
 				PartitionMetricsByResourceAttributes: true,
 				PartitionLogsByResourceAttributes:    true,
-				Brokers:                              []string{"foo:123", "bar:456"},
-				ClientID:                             "test_client_id",
+				PartitionBy: PartitionBy{
+					Key: `resource.attributes["tenant"]`,
+				},
+				Brokers:  []string{"foo:123", "bar:456"},
+				ClientID: "test_client_id",
 				Authentication: kafka.Authentication{
 					PlainText: &kafka.PlainTextConfig{
 						Username: "jdoe",
@@ -141,6 +148,7 @@ func TestLoadConfig(t *testing.T) {
 					MaxMessageBytes: 10000000,
 					RequiredAcks:    sarama.WaitForAll,
 					Compression:     "none",
+					TransactionalID: "otelcol",
 				},
 			},
 		},
@@ -171,6 +179,9 @@ func TestLoadConfig(t *testing.T) {
 				PartitionTracesByID:                  true,
 				PartitionMetricsByResourceAttributes: true,
 				PartitionLogsByResourceAttributes:    true,
+				PartitionBy: PartitionBy{
+					Key: `resource.attributes["tenant"]`,
+				},
 				Brokers:                              []string{"foo:123", "bar:456"},
 				ClientID:                             "test_client_id",
 				ResolveCanonicalBootstrapServersOnly: true,
@@ -191,6 +202,7 @@ func TestLoadConfig(t *testing.T) {
 					MaxMessageBytes: 10000000,
 					RequiredAcks:    sarama.WaitForAll,
 					Compression:     "none",
+					TransactionalID: "otelcol",
 				},
 			},
 		},
@@ -294,6 +306,36 @@ func TestValidate_sasl_version(t *testing.T) {
 	assert.EqualError(t, err, "auth.sasl.version has to be either 0 or 1. configured value 42")
 }
 
+func TestValidate_idempotent_required_acks(t *testing.T) {
+	config := &Config{
+		Producer: Producer{
+			Compression:  "none",
+			RequiredAcks: sarama.WaitForLocal,
+			Idempotent:   true,
+		},
+	}
+
+	err := config.Validate()
+	assert.EqualError(t, err, "producer.required_acks has to be -1 for the idempotent or transactional producer. configured value 1")
+}
+
+func TestValidate_transactional_protocol_version(t *testing.T) {
+	config := &Config{
+		ProtocolVersion: "0.10.2.0",
+		Producer: Producer{
+			Compression:     "none",
+			RequiredAcks:    sarama.WaitForAll,
+			TransactionalID: "otelcol",
+		},
+	}
+
+	err := config.Validate()
+	assert.EqualError(t, err, "protocol_version has to be at least 0.11.0 for the idempotent or transactional producer. configured value 0.10.2.0")
+
+	config.ProtocolVersion = "2.0.0"
+	assert.NoError(t, config.Validate())
+}
+
 func Test_saramaProducerCompressionCodec(t *testing.T) {
 	tests := map[string]struct {
 		compression         string
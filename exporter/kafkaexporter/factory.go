@@ -105,6 +105,11 @@ func (f *kafkaExporterFactory) createTracesExporter(
 		set.Logger.Info("otlp_json is considered experimental and should not be used in a production environment")
 	}
 	exp := newTracesExporter(oCfg, set)
+	partitioner, err := newTracesPartitioner(oCfg.PartitionBy, set.TelemetrySettings)
+	if err != nil {
+		return nil, err
+	}
+	exp.partitioner = partitioner
 	return exporterhelper.NewTraces(
 		ctx,
 		set,
@@ -133,6 +138,11 @@ func (f *kafkaExporterFactory) createMetricsExporter(
 		set.Logger.Info("otlp_json is considered experimental and should not be used in a production environment")
 	}
 	exp := newMetricsExporter(oCfg, set)
+	partitioner, err := newMetricsPartitioner(oCfg.PartitionBy, set.TelemetrySettings)
+	if err != nil {
+		return nil, err
+	}
+	exp.partitioner = partitioner
 	return exporterhelper.NewMetrics(
 		ctx,
 		set,
@@ -161,6 +171,11 @@ func (f *kafkaExporterFactory) createLogsExporter(
 		set.Logger.Info("otlp_json is considered experimental and should not be used in a production environment")
 	}
 	exp := newLogsExporter(oCfg, set)
+	partitioner, err := newLogsPartitioner(oCfg.PartitionBy, set.TelemetrySettings)
+	if err != nil {
+		return nil, err
+	}
+	exp.partitioner = partitioner
 	return exporterhelper.NewLogs(
 		ctx,
 		set,
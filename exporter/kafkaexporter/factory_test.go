@@ -223,3 +223,25 @@ func TestCreateTraceExporter(t *testing.T) {
 		})
 	}
 }
+
+func TestCreateExporter_invalid_partition_by(t *testing.T) {
+	f := NewFactory()
+	conf := applyConfigOption(func(conf *Config) {
+		conf.PartitionBy.Key = `attributes["tenant"`
+	})
+	_, err := f.CreateTraces(context.Background(), exportertest.NewNopSettings(), conf)
+	assert.ErrorContains(t, err, "failed to parse partition_by.key")
+	_, err = f.CreateMetrics(context.Background(), exportertest.NewNopSettings(), conf)
+	assert.ErrorContains(t, err, "failed to parse partition_by.key")
+	_, err = f.CreateLogs(context.Background(), exportertest.NewNopSettings(), conf)
+	assert.ErrorContains(t, err, "failed to parse partition_by.key")
+
+	// Paths are specific to the context of each signal.
+	conf = applyConfigOption(func(conf *Config) {
+		conf.PartitionBy.Topic = `name`
+	})
+	_, err = f.CreateTraces(context.Background(), exportertest.NewNopSettings(), conf)
+	assert.NoError(t, err)
+	_, err = f.CreateLogs(context.Background(), exportertest.NewNopSettings(), conf)
+	assert.ErrorContains(t, err, "failed to parse partition_by.topic")
+}
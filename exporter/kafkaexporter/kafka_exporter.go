@@ -7,6 +7,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"os"
+	"sync"
 
 	"github.com/IBM/sarama"
 	"go.opentelemetry.io/collector/component"
@@ -20,16 +22,24 @@ import (
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/kafka"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/kafka/topic"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottldatapoint"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottllog"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottlspan"
 )
 
-var errUnrecognizedEncoding = fmt.Errorf("unrecognized encoding")
+var (
+	errUnrecognizedEncoding = fmt.Errorf("unrecognized encoding")
+	errFatalTxn             = errors.New("transactional producer failed with a fatal error, the exporter must be restarted")
+)
 
 // kafkaTracesProducer uses sarama to produce trace messages to Kafka.
 type kafkaTracesProducer struct {
-	cfg       Config
-	producer  sarama.SyncProducer
-	marshaler TracesMarshaler
-	logger    *zap.Logger
+	cfg         Config
+	producer    sarama.SyncProducer
+	txnMu       sync.Mutex
+	marshaler   TracesMarshaler
+	partitioner *partitioner[ottlspan.TransformContext]
+	logger      *zap.Logger
 }
 
 type kafkaErrors struct {
@@ -41,24 +51,89 @@ func (ke kafkaErrors) Error() string {
 	return fmt.Sprintf("Failed to deliver %d messages due to %s", ke.count, ke.err)
 }
 
-func (e *kafkaTracesProducer) tracesPusher(ctx context.Context, td ptrace.Traces) error {
-	messages, err := e.marshaler.Marshal(td, getTopic(ctx, &e.cfg, td.ResourceSpans()))
-	if err != nil {
+// sendMessages sends the messages of an exported batch, within a transaction
+// when the producer is transactional so the batch is written atomically.
+// The transaction is aborted on failure, and the error is retryable unless the
+// producer failed with a fatal error.
+//
+// A producer has a single ongoing transaction, while the consumers of the
+// sending queue export concurrently: txnMu serializes the transactions, so that
+// an export neither writes to nor aborts the transaction of another one.
+func sendMessages(txnMu *sync.Mutex, producer sarama.SyncProducer, messages []*sarama.ProducerMessage) error {
+	if !producer.IsTransactional() {
+		return producerError(producer.SendMessages(messages))
+	}
+
+	txnMu.Lock()
+	defer txnMu.Unlock()
+	if err := producer.BeginTxn(); err != nil {
+		return txnError(fmt.Errorf("failed to begin transaction: %w", err), producer)
+	}
+	if err := producer.SendMessages(messages); err != nil {
+		return txnError(producerError(err), producer)
+	}
+	if err := producer.CommitTxn(); err != nil {
+		return txnError(fmt.Errorf("failed to commit transaction: %w", err), producer)
+	}
+	return nil
+}
+
+// txnError aborts the failed transaction and returns its error. The error is
+// permanent when the producer failed with a fatal error, since every retry
+// would fail again with the same producer.
+func txnError(err error, producer sarama.SyncProducer) error {
+	abortErr := abortTxn(producer)
+	err = errors.Join(err, abortErr)
+	if errors.Is(abortErr, errFatalTxn) {
 		return consumererror.NewPermanent(err)
 	}
-	err = e.producer.SendMessages(messages)
-	if err != nil {
-		var prodErr sarama.ProducerErrors
-		if errors.As(err, &prodErr) {
-			if len(prodErr) > 0 {
-				return kafkaErrors{len(prodErr), prodErr[0].Err.Error()}
-			}
-		}
-		return err
+	return err
+}
+
+// abortTxn aborts the ongoing transaction, unless it already ended or the
+// producer failed with a fatal error.
+func abortTxn(producer sarama.SyncProducer) error {
+	status := producer.TxnStatus()
+	if status&sarama.ProducerTxnFlagFatalError != 0 {
+		return errFatalTxn
+	}
+	if status&(sarama.ProducerTxnFlagInTransaction|sarama.ProducerTxnFlagAbortableError) == 0 {
+		return nil
+	}
+	if err := producer.AbortTxn(); err != nil {
+		return fmt.Errorf("failed to abort transaction: %w", err)
 	}
 	return nil
 }
 
+func producerError(err error) error {
+	var prodErr sarama.ProducerErrors
+	if errors.As(err, &prodErr) {
+		if len(prodErr) > 0 {
+			return kafkaErrors{len(prodErr), prodErr[0].Err.Error()}
+		}
+	}
+	return err
+}
+
+func (e *kafkaTracesProducer) tracesPusher(ctx context.Context, td ptrace.Traces) error {
+	topic := getTopic(ctx, &e.cfg, td.ResourceSpans())
+	if e.partitioner == nil {
+		messages, err := e.marshaler.Marshal(td, topic)
+		if err != nil {
+			return consumererror.NewPermanent(err)
+		}
+		return sendMessages(&e.txnMu, e.producer, messages)
+	}
+	partitions, failures := partitionTraces(ctx, e.partitioner, td, topic)
+	failures.log(e.logger, "traces")
+	messages, err := marshalPartitions(partitions, e.marshaler.Marshal)
+	if err != nil {
+		return consumererror.NewPermanent(err)
+	}
+	return sendMessages(&e.txnMu, e.producer, messages)
+}
+
 func (e *kafkaTracesProducer) Close(context.Context) error {
 	if e.producer == nil {
 		return nil
@@ -83,7 +158,7 @@ func (e *kafkaTracesProducer) start(ctx context.Context, host component.Host) er
 	if e.marshaler == nil {
 		return errUnrecognizedEncoding
 	}
-	producer, err := newSaramaProducer(ctx, e.cfg)
+	producer, err := newSaramaProducer(ctx, e.cfg, "traces")
 	if err != nil {
 		return err
 	}
@@ -93,28 +168,30 @@ func (e *kafkaTracesProducer) start(ctx context.Context, host component.Host) er
 
 // kafkaMetricsProducer uses sarama to produce metrics messages to kafka
 type kafkaMetricsProducer struct {
-	cfg       Config
-	producer  sarama.SyncProducer
-	marshaler MetricsMarshaler
-	logger    *zap.Logger
+	cfg         Config
+	producer    sarama.SyncProducer
+	txnMu       sync.Mutex
+	marshaler   MetricsMarshaler
+	partitioner *partitioner[ottldatapoint.TransformContext]
+	logger      *zap.Logger
 }
 
 func (e *kafkaMetricsProducer) metricsDataPusher(ctx context.Context, md pmetric.Metrics) error {
-	messages, err := e.marshaler.Marshal(md, getTopic(ctx, &e.cfg, md.ResourceMetrics()))
-	if err != nil {
-		return consumererror.NewPermanent(err)
+	topic := getTopic(ctx, &e.cfg, md.ResourceMetrics())
+	if e.partitioner == nil {
+		messages, err := e.marshaler.Marshal(md, topic)
+		if err != nil {
+			return consumererror.NewPermanent(err)
+		}
+		return sendMessages(&e.txnMu, e.producer, messages)
 	}
-	err = e.producer.SendMessages(messages)
+	partitions, failures := partitionMetrics(ctx, e.partitioner, md, topic)
+	failures.log(e.logger, "metrics")
+	messages, err := marshalPartitions(partitions, e.marshaler.Marshal)
 	if err != nil {
-		var prodErr sarama.ProducerErrors
-		if errors.As(err, &prodErr) {
-			if len(prodErr) > 0 {
-				return kafkaErrors{len(prodErr), prodErr[0].Err.Error()}
-			}
-		}
-		return err
+		return consumererror.NewPermanent(err)
 	}
-	return nil
+	return sendMessages(&e.txnMu, e.producer, messages)
 }
 
 func (e *kafkaMetricsProducer) Close(context.Context) error {
@@ -141,7 +218,7 @@ func (e *kafkaMetricsProducer) start(ctx context.Context, host component.Host) e
 	if e.marshaler == nil {
 		return errUnrecognizedEncoding
 	}
-	producer, err := newSaramaProducer(ctx, e.cfg)
+	producer, err := newSaramaProducer(ctx, e.cfg, "metrics")
 	if err != nil {
 		return err
 	}
@@ -151,28 +228,30 @@ func (e *kafkaMetricsProducer) start(ctx context.Context, host component.Host) e
 
 // kafkaLogsProducer uses sarama to produce logs messages to kafka
 type kafkaLogsProducer struct {
-	cfg       Config
-	producer  sarama.SyncProducer
-	marshaler LogsMarshaler
-	logger    *zap.Logger
+	cfg         Config
+	producer    sarama.SyncProducer
+	txnMu       sync.Mutex
+	marshaler   LogsMarshaler
+	partitioner *partitioner[ottllog.TransformContext]
+	logger      *zap.Logger
 }
 
 func (e *kafkaLogsProducer) logsDataPusher(ctx context.Context, ld plog.Logs) error {
-	messages, err := e.marshaler.Marshal(ld, getTopic(ctx, &e.cfg, ld.ResourceLogs()))
-	if err != nil {
-		return consumererror.NewPermanent(err)
+	topic := getTopic(ctx, &e.cfg, ld.ResourceLogs())
+	if e.partitioner == nil {
+		messages, err := e.marshaler.Marshal(ld, topic)
+		if err != nil {
+			return consumererror.NewPermanent(err)
+		}
+		return sendMessages(&e.txnMu, e.producer, messages)
 	}
-	err = e.producer.SendMessages(messages)
+	partitions, failures := partitionLogs(ctx, e.partitioner, ld, topic)
+	failures.log(e.logger, "logs")
+	messages, err := marshalPartitions(partitions, e.marshaler.Marshal)
 	if err != nil {
-		var prodErr sarama.ProducerErrors
-		if errors.As(err, &prodErr) {
-			if len(prodErr) > 0 {
-				return kafkaErrors{len(prodErr), prodErr[0].Err.Error()}
-			}
-		}
-		return err
+		return consumererror.NewPermanent(err)
 	}
-	return nil
+	return sendMessages(&e.txnMu, e.producer, messages)
 }
 
 func (e *kafkaLogsProducer) Close(context.Context) error {
@@ -199,7 +278,7 @@ func (e *kafkaLogsProducer) start(ctx context.Context, host component.Host) erro
 	if e.marshaler == nil {
 		return errUnrecognizedEncoding
 	}
-	producer, err := newSaramaProducer(ctx, e.cfg)
+	producer, err := newSaramaProducer(ctx, e.cfg, "logs")
 	if err != nil {
 		return err
 	}
@@ -207,7 +286,7 @@ func (e *kafkaLogsProducer) start(ctx context.Context, host component.Host) erro
 	return nil
 }
 
-func newSaramaProducer(ctx context.Context, config Config) (sarama.SyncProducer, error) {
+func newSaramaProducer(ctx context.Context, config Config, signal string) (sarama.SyncProducer, error) {
 	c := sarama.NewConfig()
 
 	c.ClientID = config.ClientID
@@ -224,6 +303,20 @@ func newSaramaProducer(ctx context.Context, config Config) (sarama.SyncProducer,
 	c.Producer.MaxMessageBytes = config.Producer.MaxMessageBytes
 	c.Producer.Flush.MaxMessages = config.Producer.FlushMaxMessages
 
+	if config.Producer.Idempotent || config.Producer.TransactionalID != "" {
+		// The idempotent producer requires a single in flight request per
+		// broker to keep the ordering of the retries.
+		c.Producer.Idempotent = true
+		c.Net.MaxOpenRequests = 1
+	}
+	if config.Producer.TransactionalID != "" {
+		id, err := transactionalID(config.Producer.TransactionalID, signal)
+		if err != nil {
+			return nil, err
+		}
+		c.Producer.Transaction.ID = id
+	}
+
 	if config.ResolveCanonicalBootstrapServersOnly {
 		c.Net.ResolveCanonicalBootstrapServers = true
 	}
@@ -253,6 +346,18 @@ func newSaramaProducer(ctx context.Context, config Config) (sarama.SyncProducer,
 	return producer, nil
 }
 
+// transactionalID suffixes the configured transactional ID with the host name,
+// so that the replicas of a collector sharing the same configuration don't
+// fence each other, and with the signal since the exporters of each signal
+// have their own producer.
+func transactionalID(prefix, signal string) (string, error) {
+	hostname, err := os.Hostname()
+	if err != nil {
+		return "", fmt.Errorf("failed to get the host name of the transactional ID: %w", err)
+	}
+	return prefix + "-" + hostname + "-" + signal, nil
+}
+
 func newMetricsExporter(config Config, set exporter.Settings) *kafkaMetricsProducer {
 	return &kafkaMetricsProducer{
 		cfg:    config,
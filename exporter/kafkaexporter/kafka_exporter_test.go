@@ -5,8 +5,13 @@ package kafkaexporter
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/IBM/sarama"
 	"github.com/IBM/sarama/mocks"
@@ -15,6 +20,7 @@ import (
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/config/configtls"
+	"go.opentelemetry.io/collector/consumer/consumererror"
 	"go.opentelemetry.io/collector/exporter/exportertest"
 	"go.opentelemetry.io/collector/pdata/plog"
 	"go.opentelemetry.io/collector/pdata/pmetric"
@@ -237,6 +243,217 @@ func TestTracesPusher_err(t *testing.T) {
 	assert.EqualError(t, err, expErr.Error())
 }
 
+func TestTracesPusher_transactional(t *testing.T) {
+	c := newTransactionalConfig()
+	producer := mocks.NewSyncProducer(t, c)
+	producer.ExpectSendMessageAndSucceed()
+
+	p := kafkaTracesProducer{
+		producer:  producer,
+		marshaler: newPdataTracesMarshaler(&ptrace.ProtoMarshaler{}, defaultEncoding, false),
+	}
+	t.Cleanup(func() {
+		require.NoError(t, p.Close(context.Background()))
+	})
+	err := p.tracesPusher(context.Background(), testdata.GenerateTraces(2))
+	require.NoError(t, err)
+	assert.Equal(t, sarama.ProducerTxnFlagReady, producer.TxnStatus())
+}
+
+func TestTracesPusher_transactional_err(t *testing.T) {
+	c := newTransactionalConfig()
+	producer := mocks.NewSyncProducer(t, c)
+	expErr := fmt.Errorf("failed to send")
+	producer.ExpectSendMessageAndFail(expErr)
+
+	p := kafkaTracesProducer{
+		producer:  producer,
+		marshaler: newPdataTracesMarshaler(&ptrace.ProtoMarshaler{}, defaultEncoding, false),
+		logger:    zap.NewNop(),
+	}
+	t.Cleanup(func() {
+		require.NoError(t, p.Close(context.Background()))
+	})
+	err := p.tracesPusher(context.Background(), testdata.GenerateTraces(2))
+	assert.EqualError(t, err, expErr.Error())
+	assert.False(t, consumererror.IsPermanent(err))
+	// The transaction is aborted.
+	assert.Equal(t, sarama.ProducerTxnFlagReady, producer.TxnStatus())
+}
+
+func TestTracesPusher_transactional_concurrent(t *testing.T) {
+	const exports = 10
+	c := newTransactionalConfig()
+	producer := &serialTxnProducer{SyncProducer: mocks.NewSyncProducer(t, c)}
+	for i := 0; i < exports; i++ {
+		producer.ExpectSendMessageAndSucceed()
+	}
+
+	p := kafkaTracesProducer{
+		producer:  producer,
+		marshaler: newPdataTracesMarshaler(&ptrace.ProtoMarshaler{}, defaultEncoding, false),
+	}
+	t.Cleanup(func() {
+		require.NoError(t, p.Close(context.Background()))
+	})
+
+	// The consumers of the sending queue export concurrently with the same producer.
+	var wg sync.WaitGroup
+	errs := make(chan error, exports)
+	for i := 0; i < exports; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- p.tracesPusher(context.Background(), testdata.GenerateTraces(2))
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, int64(exports), producer.committed.Load())
+}
+
+func TestTransactionalID(t *testing.T) {
+	hostname, err := os.Hostname()
+	require.NoError(t, err)
+
+	id, err := transactionalID("otelcol", "traces")
+	require.NoError(t, err)
+	assert.Equal(t, "otelcol-"+hostname+"-traces", id)
+}
+
+func TestSendMessages_commit_err(t *testing.T) {
+	c := newTransactionalConfig()
+	producer := &txnProducer{
+		SyncProducer: mocks.NewSyncProducer(t, c),
+		commitErr:    errors.New("coordinator not available"),
+		status:       sarama.ProducerTxnFlagInError | sarama.ProducerTxnFlagAbortableError,
+	}
+	producer.ExpectSendMessageAndSucceed()
+	t.Cleanup(func() {
+		require.NoError(t, producer.Close())
+	})
+
+	err := sendMessages(&sync.Mutex{}, producer, []*sarama.ProducerMessage{{Topic: "spans"}})
+	assert.EqualError(t, err, "failed to commit transaction: coordinator not available")
+	assert.False(t, consumererror.IsPermanent(err))
+	assert.True(t, producer.aborted)
+}
+
+func TestSendMessages_fatal_err(t *testing.T) {
+	c := newTransactionalConfig()
+	producer := &txnProducer{
+		SyncProducer: mocks.NewSyncProducer(t, c),
+		commitErr:    sarama.ErrProducerFenced,
+		status:       sarama.ProducerTxnFlagInError | sarama.ProducerTxnFlagFatalError,
+	}
+	producer.ExpectSendMessageAndSucceed()
+	t.Cleanup(func() {
+		require.NoError(t, producer.Close())
+	})
+
+	err := sendMessages(&sync.Mutex{}, producer, []*sarama.ProducerMessage{{Topic: "spans"}})
+	assert.ErrorIs(t, err, sarama.ErrProducerFenced)
+	assert.ErrorIs(t, err, errFatalTxn)
+	assert.True(t, consumererror.IsPermanent(err))
+	assert.False(t, producer.aborted)
+}
+
+func TestSendMessages_begin_fatal_err(t *testing.T) {
+	c := newTransactionalConfig()
+	producer := &txnProducer{
+		SyncProducer: mocks.NewSyncProducer(t, c),
+		beginErr:     sarama.ErrTxnUnableToParseResponse,
+		status:       sarama.ProducerTxnFlagInError | sarama.ProducerTxnFlagFatalError,
+	}
+	t.Cleanup(func() {
+		require.NoError(t, producer.Close())
+	})
+
+	// The producer stays in error once fenced, the next exports fail without
+	// being retried.
+	err := sendMessages(&sync.Mutex{}, producer, []*sarama.ProducerMessage{{Topic: "spans"}})
+	assert.ErrorIs(t, err, errFatalTxn)
+	assert.True(t, consumererror.IsPermanent(err))
+}
+
+func newTransactionalConfig() *sarama.Config {
+	c := sarama.NewConfig()
+	c.Version = sarama.V2_0_0_0
+	c.Producer.RequiredAcks = sarama.WaitForAll
+	c.Producer.Idempotent = true
+	c.Producer.Transaction.ID = "otelcol-traces"
+	c.Net.MaxOpenRequests = 1
+	return c
+}
+
+// txnProducer fails to begin or to commit the transactions, leaving them in the given
+// status.
+type txnProducer struct {
+	*mocks.SyncProducer
+	beginErr  error
+	commitErr error
+	status    sarama.ProducerTxnStatusFlag
+	failed    bool
+	aborted   bool
+}
+
+func (p *txnProducer) BeginTxn() error {
+	if p.beginErr != nil {
+		p.failed = true
+		return p.beginErr
+	}
+	return p.SyncProducer.BeginTxn()
+}
+
+func (p *txnProducer) CommitTxn() error {
+	p.failed = true
+	return p.commitErr
+}
+
+func (p *txnProducer) AbortTxn() error {
+	p.aborted = true
+	return p.SyncProducer.AbortTxn()
+}
+
+func (p *txnProducer) TxnStatus() sarama.ProducerTxnStatusFlag {
+	if p.failed {
+		return p.status
+	}
+	return p.SyncProducer.TxnStatus()
+}
+
+// serialTxnProducer fails the transactions begun while another one is ongoing.
+type serialTxnProducer struct {
+	*mocks.SyncProducer
+	inTxn     atomic.Bool
+	committed atomic.Int64
+}
+
+func (p *serialTxnProducer) BeginTxn() error {
+	if !p.inTxn.CompareAndSwap(false, true) {
+		return errors.New("a transaction is already ongoing")
+	}
+	return p.SyncProducer.BeginTxn()
+}
+
+func (p *serialTxnProducer) SendMessages(msgs []*sarama.ProducerMessage) error {
+	// Leave time for the other exports to begin a transaction.
+	time.Sleep(time.Millisecond)
+	return p.SyncProducer.SendMessages(msgs)
+}
+
+func (p *serialTxnProducer) CommitTxn() error {
+	defer p.inTxn.Store(false)
+	if err := p.SyncProducer.CommitTxn(); err != nil {
+		return err
+	}
+	p.committed.Add(1)
+	return nil
+}
+
 func TestTracesPusher_marshal_error(t *testing.T) {
 	expErr := fmt.Errorf("failed to marshal")
 	p := kafkaTracesProducer{
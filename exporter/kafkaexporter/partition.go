@@ -0,0 +1,432 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package kafkaexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/kafkaexporter"
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/IBM/sarama"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottldatapoint"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottllog"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottlspan"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/ottlfuncs"
+)
+
+// partitioner evaluates the OTTL value expressions of the message key and
+// topic for every span, data point or log record.
+type partitioner[K any] struct {
+	key   *ottl.ValueExpression[K]
+	topic *ottl.ValueExpression[K]
+}
+
+// partitionID identifies the messages sharing the same topic and key.
+type partitionID struct {
+	topic  string
+	key    string
+	hasKey bool
+}
+
+// partition holds the data written to a topic with a message key, the key is
+// nil when the key expression evaluated to nil.
+type partition[T any] struct {
+	topic string
+	key   []byte
+	data  T
+}
+
+// newPartitioner parses the expressions of the configuration, it returns nil
+// when none is set.
+func newPartitioner[K any](config PartitionBy, parser ottl.Parser[K]) (*partitioner[K], error) {
+	if config.Key == "" && config.Topic == "" {
+		return nil, nil
+	}
+	p := &partitioner[K]{}
+	var err error
+	if config.Key != "" {
+		if p.key, err = parser.ParseValueExpression(config.Key); err != nil {
+			return nil, fmt.Errorf("failed to parse partition_by.key: %w", err)
+		}
+	}
+	if config.Topic != "" {
+		if p.topic, err = parser.ParseValueExpression(config.Topic); err != nil {
+			return nil, fmt.Errorf("failed to parse partition_by.topic: %w", err)
+		}
+	}
+	return p, nil
+}
+
+func newTracesPartitioner(config PartitionBy, set component.TelemetrySettings) (*partitioner[ottlspan.TransformContext], error) {
+	parser, err := ottlspan.NewParser(ottlfuncs.StandardConverters[ottlspan.TransformContext](), set)
+	if err != nil {
+		return nil, err
+	}
+	return newPartitioner(config, parser)
+}
+
+func newMetricsPartitioner(config PartitionBy, set component.TelemetrySettings) (*partitioner[ottldatapoint.TransformContext], error) {
+	parser, err := ottldatapoint.NewParser(ottlfuncs.StandardConverters[ottldatapoint.TransformContext](), set)
+	if err != nil {
+		return nil, err
+	}
+	return newPartitioner(config, parser)
+}
+
+func newLogsPartitioner(config PartitionBy, set component.TelemetrySettings) (*partitioner[ottllog.TransformContext], error) {
+	parser, err := ottllog.NewParser(ottlfuncs.StandardConverters[ottllog.TransformContext](), set)
+	if err != nil {
+		return nil, err
+	}
+	return newPartitioner(config, parser)
+}
+
+// evaluate returns the partition of the telemetry of tCtx, the topic falls
+// back to defaultTopic when its expression evaluates to nil or to an empty
+// string. When an expression fails to evaluate, the returned partition falls
+// back to the default key or topic along with the error.
+func (p *partitioner[K]) evaluate(ctx context.Context, tCtx K, defaultTopic string) (partitionID, error) {
+	id := partitionID{topic: defaultTopic}
+	var errs []error
+	if p.key != nil {
+		key, hasKey, err := evaluateString(ctx, p.key, tCtx)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("partition_by.key: %w", err))
+		} else {
+			id.key, id.hasKey = key, hasKey
+		}
+	}
+	if p.topic != nil {
+		topic, _, err := evaluateString(ctx, p.topic, tCtx)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("partition_by.topic: %w", err))
+		} else if topic != "" {
+			id.topic = topic
+		}
+	}
+	return id, errors.Join(errs...)
+}
+
+func evaluateString[K any](ctx context.Context, expr *ottl.ValueExpression[K], tCtx K) (string, bool, error) {
+	value, err := expr.Eval(ctx, tCtx)
+	if err != nil {
+		return "", false, fmt.Errorf("failed to evaluate: %w", err)
+	}
+	return valueToString(value)
+}
+
+// evaluationFailures counts the telemetry that fell back to the default key
+// or topic because an expression failed to evaluate.
+type evaluationFailures struct {
+	count int
+	// err is the first failure of the batch.
+	err error
+}
+
+func (f *evaluationFailures) add(err error) {
+	if err == nil {
+		return
+	}
+	if f.count == 0 {
+		f.err = err
+	}
+	f.count++
+}
+
+// log reports the failures of a batch with a single entry.
+func (f evaluationFailures) log(logger *zap.Logger, signal string) {
+	if f.count == 0 {
+		return
+	}
+	logger.Warn("Failed to evaluate partition_by, falling back to the default key and topic",
+		zap.String("signal", signal),
+		zap.Int("failures", f.count),
+		zap.Error(f.err))
+}
+
+// valueToString converts the value of an expression to a string, the bytes
+// are kept as is. It returns false for nil and empty values.
+func valueToString(value any) (string, bool, error) {
+	switch v := value.(type) {
+	case nil:
+		return "", false, nil
+	case string:
+		return v, true, nil
+	case []byte:
+		return string(v), true, nil
+	case pcommon.Value:
+		switch v.Type() {
+		case pcommon.ValueTypeEmpty:
+			return "", false, nil
+		case pcommon.ValueTypeBytes:
+			return string(v.Bytes().AsRaw()), true, nil
+		default:
+			return v.AsString(), true, nil
+		}
+	case pcommon.Map:
+		value = v.AsRaw()
+	case pcommon.Slice:
+		value = v.AsRaw()
+	}
+	pv := pcommon.NewValueEmpty()
+	if err := pv.FromRaw(value); err != nil {
+		return "", false, fmt.Errorf("unsupported value of type %T", value)
+	}
+	return pv.AsString(), true, nil
+}
+
+// partitions groups the telemetry by partition, keeping the order of the
+// partitions and of the telemetry within them.
+type partitions[T any, C any] struct {
+	index map[partitionID]int
+	list  []partition[T]
+	// cursors are the containers of the last telemetry appended to each
+	// partition, to append the telemetry sharing them.
+	cursors []C
+	newData func() T
+}
+
+func newPartitions[T any, C any](newData func() T) *partitions[T, C] {
+	return &partitions[T, C]{
+		index:   map[partitionID]int{},
+		newData: newData,
+	}
+}
+
+// get returns the data and the cursor of the partition.
+func (p *partitions[T, C]) get(id partitionID) (T, *C) {
+	i, ok := p.index[id]
+	if !ok {
+		i = len(p.list)
+		p.index[id] = i
+		part := partition[T]{topic: id.topic, data: p.newData()}
+		if id.hasKey {
+			part.key = []byte(id.key)
+		}
+		p.list = append(p.list, part)
+		p.cursors = append(p.cursors, *new(C))
+	}
+	return p.list[i].data, &p.cursors[i]
+}
+
+// cursor holds the last resource and scope appended to a partition, and the
+// indexes of their source, to append the telemetry sharing them.
+type cursor[R any, S any] struct {
+	resource      R
+	scope         S
+	resourceIndex int
+	scopeIndex    int
+	// metricIndex is the index of the last metric, -1 when the scope has no
+	// metric yet.
+	metricIndex int
+	started     bool
+}
+
+// move reports whether the resource and the scope of the telemetry at the
+// given indexes have to be appended to the partition.
+func (c *cursor[R, S]) move(resourceIndex, scopeIndex int) (newResource, newScope bool) {
+	newResource = !c.started || c.resourceIndex != resourceIndex
+	newScope = newResource || c.scopeIndex != scopeIndex
+	if newScope {
+		c.metricIndex = -1
+	}
+	c.started = true
+	c.resourceIndex, c.scopeIndex = resourceIndex, scopeIndex
+	return newResource, newScope
+}
+
+func partitionTraces(ctx context.Context, p *partitioner[ottlspan.TransformContext], td ptrace.Traces, defaultTopic string) ([]partition[ptrace.Traces], evaluationFailures) {
+	var failures evaluationFailures
+	parts := newPartitions[ptrace.Traces, cursor[ptrace.ResourceSpans, ptrace.ScopeSpans]](ptrace.NewTraces)
+	for i := 0; i < td.ResourceSpans().Len(); i++ {
+		rs := td.ResourceSpans().At(i)
+		for j := 0; j < rs.ScopeSpans().Len(); j++ {
+			ss := rs.ScopeSpans().At(j)
+			for k := 0; k < ss.Spans().Len(); k++ {
+				span := ss.Spans().At(k)
+				id, err := p.evaluate(ctx, ottlspan.NewTransformContext(span, ss.Scope(), rs.Resource(), ss, rs), defaultTopic)
+				failures.add(err)
+				data, c := parts.get(id)
+				newResource, newScope := c.move(i, j)
+				if newResource {
+					dst := data.ResourceSpans().AppendEmpty()
+					rs.Resource().CopyTo(dst.Resource())
+					dst.SetSchemaUrl(rs.SchemaUrl())
+					c.resource = dst
+				}
+				if newScope {
+					dst := c.resource.ScopeSpans().AppendEmpty()
+					ss.Scope().CopyTo(dst.Scope())
+					dst.SetSchemaUrl(ss.SchemaUrl())
+					c.scope = dst
+				}
+				span.CopyTo(c.scope.Spans().AppendEmpty())
+			}
+		}
+	}
+	return parts.list, failures
+}
+
+type metricsCursor struct {
+	cursor[pmetric.ResourceMetrics, pmetric.ScopeMetrics]
+	metric pmetric.Metric
+}
+
+func partitionMetrics(ctx context.Context, p *partitioner[ottldatapoint.TransformContext], md pmetric.Metrics, defaultTopic string) ([]partition[pmetric.Metrics], evaluationFailures) {
+	var failures evaluationFailures
+	parts := newPartitions[pmetric.Metrics, metricsCursor](pmetric.NewMetrics)
+	for i := 0; i < md.ResourceMetrics().Len(); i++ {
+		rm := md.ResourceMetrics().At(i)
+		for j := 0; j < rm.ScopeMetrics().Len(); j++ {
+			sm := rm.ScopeMetrics().At(j)
+			for k := 0; k < sm.Metrics().Len(); k++ {
+				metric := sm.Metrics().At(k)
+				forEachDataPoint(metric, func(dp any, appendTo func(pmetric.Metric)) {
+					tCtx := ottldatapoint.NewTransformContext(dp, metric, sm.Metrics(), sm.Scope(), rm.Resource(), sm, rm)
+					id, err := p.evaluate(ctx, tCtx, defaultTopic)
+					failures.add(err)
+					data, c := parts.get(id)
+					newResource, newScope := c.move(i, j)
+					if newResource {
+						dst := data.ResourceMetrics().AppendEmpty()
+						rm.Resource().CopyTo(dst.Resource())
+						dst.SetSchemaUrl(rm.SchemaUrl())
+						c.resource = dst
+					}
+					if newScope {
+						dst := c.resource.ScopeMetrics().AppendEmpty()
+						sm.Scope().CopyTo(dst.Scope())
+						dst.SetSchemaUrl(sm.SchemaUrl())
+						c.scope = dst
+					}
+					if c.metricIndex != k {
+						c.metric = c.scope.Metrics().AppendEmpty()
+						copyMetricDescription(metric, c.metric)
+						c.metricIndex = k
+					}
+					appendTo(c.metric)
+				})
+			}
+		}
+	}
+	return parts.list, failures
+}
+
+// forEachDataPoint calls fn with every data point of the metric and a
+// function appending a copy of the data point to a metric of the same type.
+func forEachDataPoint(metric pmetric.Metric, fn func(dp any, appendTo func(pmetric.Metric))) {
+	switch metric.Type() {
+	case pmetric.MetricTypeGauge:
+		dps := metric.Gauge().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			dp := dps.At(i)
+			fn(dp, func(m pmetric.Metric) { dp.CopyTo(m.Gauge().DataPoints().AppendEmpty()) })
+		}
+	case pmetric.MetricTypeSum:
+		dps := metric.Sum().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			dp := dps.At(i)
+			fn(dp, func(m pmetric.Metric) { dp.CopyTo(m.Sum().DataPoints().AppendEmpty()) })
+		}
+	case pmetric.MetricTypeHistogram:
+		dps := metric.Histogram().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			dp := dps.At(i)
+			fn(dp, func(m pmetric.Metric) { dp.CopyTo(m.Histogram().DataPoints().AppendEmpty()) })
+		}
+	case pmetric.MetricTypeExponentialHistogram:
+		dps := metric.ExponentialHistogram().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			dp := dps.At(i)
+			fn(dp, func(m pmetric.Metric) { dp.CopyTo(m.ExponentialHistogram().DataPoints().AppendEmpty()) })
+		}
+	case pmetric.MetricTypeSummary:
+		dps := metric.Summary().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			dp := dps.At(i)
+			fn(dp, func(m pmetric.Metric) { dp.CopyTo(m.Summary().DataPoints().AppendEmpty()) })
+		}
+	}
+}
+
+// copyMetricDescription copies the metric without its data points.
+func copyMetricDescription(src, dst pmetric.Metric) {
+	dst.SetName(src.Name())
+	dst.SetDescription(src.Description())
+	dst.SetUnit(src.Unit())
+	src.Metadata().CopyTo(dst.Metadata())
+	switch src.Type() {
+	case pmetric.MetricTypeGauge:
+		dst.SetEmptyGauge()
+	case pmetric.MetricTypeSum:
+		sum := dst.SetEmptySum()
+		sum.SetAggregationTemporality(src.Sum().AggregationTemporality())
+		sum.SetIsMonotonic(src.Sum().IsMonotonic())
+	case pmetric.MetricTypeHistogram:
+		dst.SetEmptyHistogram().SetAggregationTemporality(src.Histogram().AggregationTemporality())
+	case pmetric.MetricTypeExponentialHistogram:
+		dst.SetEmptyExponentialHistogram().SetAggregationTemporality(src.ExponentialHistogram().AggregationTemporality())
+	case pmetric.MetricTypeSummary:
+		dst.SetEmptySummary()
+	}
+}
+
+func partitionLogs(ctx context.Context, p *partitioner[ottllog.TransformContext], ld plog.Logs, defaultTopic string) ([]partition[plog.Logs], evaluationFailures) {
+	var failures evaluationFailures
+	parts := newPartitions[plog.Logs, cursor[plog.ResourceLogs, plog.ScopeLogs]](plog.NewLogs)
+	for i := 0; i < ld.ResourceLogs().Len(); i++ {
+		rl := ld.ResourceLogs().At(i)
+		for j := 0; j < rl.ScopeLogs().Len(); j++ {
+			sl := rl.ScopeLogs().At(j)
+			for k := 0; k < sl.LogRecords().Len(); k++ {
+				logRecord := sl.LogRecords().At(k)
+				id, err := p.evaluate(ctx, ottllog.NewTransformContext(logRecord, sl.Scope(), rl.Resource(), sl, rl), defaultTopic)
+				failures.add(err)
+				data, c := parts.get(id)
+				newResource, newScope := c.move(i, j)
+				if newResource {
+					dst := data.ResourceLogs().AppendEmpty()
+					rl.Resource().CopyTo(dst.Resource())
+					dst.SetSchemaUrl(rl.SchemaUrl())
+					c.resource = dst
+				}
+				if newScope {
+					dst := c.resource.ScopeLogs().AppendEmpty()
+					sl.Scope().CopyTo(dst.Scope())
+					dst.SetSchemaUrl(sl.SchemaUrl())
+					c.scope = dst
+				}
+				logRecord.CopyTo(c.scope.LogRecords().AppendEmpty())
+			}
+		}
+	}
+	return parts.list, failures
+}
+
+// marshalPartitions marshals every partition to its topic, the key of the
+// partition, when set, replaces the key of the messages.
+func marshalPartitions[T any](parts []partition[T], marshal func(T, string) ([]*sarama.ProducerMessage, error)) ([]*sarama.ProducerMessage, error) {
+	var messages []*sarama.ProducerMessage
+	for _, part := range parts {
+		msgs, err := marshal(part.data, part.topic)
+		if err != nil {
+			return nil, err
+		}
+		if part.key != nil {
+			for _, msg := range msgs {
+				msg.Key = sarama.ByteEncoder(part.key)
+			}
+		}
+		messages = append(messages, msgs...)
+	}
+	return messages, nil
+}
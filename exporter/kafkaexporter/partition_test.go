@@ -0,0 +1,274 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package kafkaexporter
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/IBM/sarama"
+	"github.com/IBM/sarama/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+func TestPartitionLogs(t *testing.T) {
+	p, err := newLogsPartitioner(PartitionBy{
+		Key:   `attributes["tenant"]`,
+		Topic: `resource.attributes["topic"]`,
+	}, componenttest.NewNopTelemetrySettings())
+	require.NoError(t, err)
+
+	ld := plog.NewLogs()
+	rl := ld.ResourceLogs().AppendEmpty()
+	rl.SetSchemaUrl("https://opentelemetry.io/schemas/1.27.0")
+	rl.Resource().Attributes().PutStr("service.name", "checkout")
+	sl := rl.ScopeLogs().AppendEmpty()
+	sl.Scope().SetName("scope_a")
+	for _, tenant := range []string{"a", "b", "a"} {
+		lr := sl.LogRecords().AppendEmpty()
+		lr.Attributes().PutStr("tenant", tenant)
+		lr.Body().SetStr("checkout " + tenant)
+	}
+	rl = ld.ResourceLogs().AppendEmpty()
+	rl.Resource().Attributes().PutStr("service.name", "billing")
+	rl.Resource().Attributes().PutStr("topic", "billing_logs")
+	sl = rl.ScopeLogs().AppendEmpty()
+	sl.Scope().SetName("scope_b")
+	lr := sl.LogRecords().AppendEmpty()
+	lr.Attributes().PutStr("tenant", "a")
+	lr.Body().SetStr("billing a")
+	sl.LogRecords().AppendEmpty().Body().SetStr("billing")
+
+	parts, failures := partitionLogs(context.Background(), p, ld, "otlp_logs")
+	assert.Zero(t, failures.count)
+	require.Len(t, parts, 4)
+
+	assert.Equal(t, "otlp_logs", parts[0].topic)
+	assert.Equal(t, []byte("a"), parts[0].key)
+	require.Equal(t, 1, parts[0].data.ResourceLogs().Len())
+	rl = parts[0].data.ResourceLogs().At(0)
+	assert.Equal(t, "https://opentelemetry.io/schemas/1.27.0", rl.SchemaUrl())
+	assert.Equal(t, map[string]any{"service.name": "checkout"}, rl.Resource().Attributes().AsRaw())
+	require.Equal(t, 1, rl.ScopeLogs().Len())
+	assert.Equal(t, "scope_a", rl.ScopeLogs().At(0).Scope().Name())
+	assert.Equal(t, 2, rl.ScopeLogs().At(0).LogRecords().Len())
+	assert.Equal(t, "checkout a", rl.ScopeLogs().At(0).LogRecords().At(1).Body().Str())
+
+	assert.Equal(t, "otlp_logs", parts[1].topic)
+	assert.Equal(t, []byte("b"), parts[1].key)
+	assert.Equal(t, 1, parts[1].data.LogRecordCount())
+
+	assert.Equal(t, "billing_logs", parts[2].topic)
+	assert.Equal(t, []byte("a"), parts[2].key)
+	require.Equal(t, 1, parts[2].data.LogRecordCount())
+	assert.Equal(t, "billing a", parts[2].data.ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(0).Body().Str())
+
+	// The key is unset when its expression evaluates to nil.
+	assert.Equal(t, "billing_logs", parts[3].topic)
+	assert.Nil(t, parts[3].key)
+	assert.Equal(t, 1, parts[3].data.LogRecordCount())
+}
+
+func TestPartitionTraces(t *testing.T) {
+	p, err := newTracesPartitioner(PartitionBy{
+		Key: `Concat([resource.attributes["service.name"], resource.attributes["k8s.namespace.name"]], "/")`,
+	}, componenttest.NewNopTelemetrySettings())
+	require.NoError(t, err)
+
+	td := ptrace.NewTraces()
+	for _, namespace := range []string{"prod", "dev", "prod"} {
+		rs := td.ResourceSpans().AppendEmpty()
+		rs.Resource().Attributes().PutStr("service.name", "checkout")
+		rs.Resource().Attributes().PutStr("k8s.namespace.name", namespace)
+		ss := rs.ScopeSpans().AppendEmpty()
+		ss.Spans().AppendEmpty().SetName("span_1")
+		ss.Spans().AppendEmpty().SetName("span_2")
+	}
+
+	parts, failures := partitionTraces(context.Background(), p, td, "otlp_spans")
+	assert.Zero(t, failures.count)
+	require.Len(t, parts, 2)
+	assert.Equal(t, []byte("checkout/prod"), parts[0].key)
+	// The spans of different resources aren't merged.
+	assert.Equal(t, 2, parts[0].data.ResourceSpans().Len())
+	assert.Equal(t, 4, parts[0].data.SpanCount())
+	assert.Equal(t, []byte("checkout/dev"), parts[1].key)
+	assert.Equal(t, 2, parts[1].data.SpanCount())
+}
+
+func TestPartitionMetrics(t *testing.T) {
+	p, err := newMetricsPartitioner(PartitionBy{
+		Key: `attributes["tenant"]`,
+	}, componenttest.NewNopTelemetrySettings())
+	require.NoError(t, err)
+
+	md := pmetric.NewMetrics()
+	sm := md.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty()
+	sum := sm.Metrics().AppendEmpty()
+	sum.SetName("requests")
+	sum.SetUnit("1")
+	sum.SetEmptySum().SetIsMonotonic(true)
+	sum.Sum().SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+	for _, tenant := range []string{"a", "b", "a"} {
+		dp := sum.Sum().DataPoints().AppendEmpty()
+		dp.Attributes().PutStr("tenant", tenant)
+		dp.SetIntValue(1)
+	}
+	histogram := sm.Metrics().AppendEmpty()
+	histogram.SetName("latency")
+	histogram.SetEmptyHistogram().SetAggregationTemporality(pmetric.AggregationTemporalityDelta)
+	histogram.Histogram().DataPoints().AppendEmpty().Attributes().PutStr("tenant", "b")
+	gauge := sm.Metrics().AppendEmpty()
+	gauge.SetName("memory")
+	gauge.SetEmptyGauge().DataPoints().AppendEmpty().Attributes().PutStr("tenant", "a")
+
+	parts, failures := partitionMetrics(context.Background(), p, md, "otlp_metrics")
+	assert.Zero(t, failures.count)
+	require.Len(t, parts, 2)
+
+	assert.Equal(t, []byte("a"), parts[0].key)
+	metrics := parts[0].data.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics()
+	require.Equal(t, 2, metrics.Len())
+	assert.Equal(t, "requests", metrics.At(0).Name())
+	assert.Equal(t, "1", metrics.At(0).Unit())
+	assert.True(t, metrics.At(0).Sum().IsMonotonic())
+	assert.Equal(t, pmetric.AggregationTemporalityCumulative, metrics.At(0).Sum().AggregationTemporality())
+	assert.Equal(t, 2, metrics.At(0).Sum().DataPoints().Len())
+	assert.Equal(t, "memory", metrics.At(1).Name())
+	assert.Equal(t, 1, metrics.At(1).Gauge().DataPoints().Len())
+
+	assert.Equal(t, []byte("b"), parts[1].key)
+	metrics = parts[1].data.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics()
+	require.Equal(t, 2, metrics.Len())
+	assert.Equal(t, 1, metrics.At(0).Sum().DataPoints().Len())
+	assert.Equal(t, "latency", metrics.At(1).Name())
+	assert.Equal(t, pmetric.AggregationTemporalityDelta, metrics.At(1).Histogram().AggregationTemporality())
+	assert.Equal(t, 1, metrics.At(1).Histogram().DataPoints().Len())
+}
+
+func TestPartitionLogs_map_value(t *testing.T) {
+	p, err := newLogsPartitioner(PartitionBy{
+		Topic: `attributes`,
+	}, componenttest.NewNopTelemetrySettings())
+	require.NoError(t, err)
+
+	ld := plog.NewLogs()
+	ld.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords().AppendEmpty().Attributes().PutStr("tenant", "a")
+	parts, failures := partitionLogs(context.Background(), p, ld, "otlp_logs")
+	assert.Zero(t, failures.count)
+	// Maps are converted to their JSON representation.
+	assert.Equal(t, `{"tenant":"a"}`, parts[0].topic)
+}
+
+func TestPartitionLogs_evaluation_failure(t *testing.T) {
+	p, err := newLogsPartitioner(PartitionBy{
+		Key:   `Substring(attributes["tenant"], 0, 4)`,
+		Topic: `Substring(attributes["topic"], 0, 4)`,
+	}, componenttest.NewNopTelemetrySettings())
+	require.NoError(t, err)
+
+	ld := plog.NewLogs()
+	sl := ld.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty()
+	lr := sl.LogRecords().AppendEmpty()
+	lr.Attributes().PutStr("tenant", "acme")
+	lr.Attributes().PutStr("topic", "a")
+	lr = sl.LogRecords().AppendEmpty()
+	lr.Attributes().PutStr("tenant", "a")
+	lr.Attributes().PutStr("topic", "logs")
+	lr = sl.LogRecords().AppendEmpty()
+	lr.Attributes().PutStr("tenant", "acme")
+	lr.Attributes().PutStr("topic", "logs")
+
+	parts, failures := partitionLogs(context.Background(), p, ld, "otlp_logs")
+	assert.Equal(t, 2, failures.count)
+	assert.ErrorContains(t, failures.err, "partition_by.topic")
+	require.Len(t, parts, 3)
+	// Each record falls back to the default of the expression that failed.
+	assert.Equal(t, "otlp_logs", parts[0].topic)
+	assert.Equal(t, []byte("acme"), parts[0].key)
+	assert.Equal(t, "logs", parts[1].topic)
+	assert.Nil(t, parts[1].key)
+	assert.Equal(t, "logs", parts[2].topic)
+	assert.Equal(t, []byte("acme"), parts[2].key)
+}
+
+func TestValueToString(t *testing.T) {
+	bytesValue := pcommon.NewValueBytes()
+	bytesValue.Bytes().FromRaw([]byte{0xff, 0x00})
+	m := pcommon.NewMap()
+	m.PutInt("count", 1)
+
+	tests := []struct {
+		name    string
+		value   any
+		want    string
+		wantSet bool
+		wantErr bool
+	}{
+		{name: "nil", value: nil},
+		{name: "empty", value: pcommon.NewValueEmpty()},
+		{name: "string", value: "tenant_a", want: "tenant_a", wantSet: true},
+		{name: "bytes", value: []byte{0xff, 0x00}, want: "\xff\x00", wantSet: true},
+		{name: "bytes_value", value: bytesValue, want: "\xff\x00", wantSet: true},
+		{name: "int", value: int64(42), want: "42", wantSet: true},
+		{name: "value", value: pcommon.NewValueBool(true), want: "true", wantSet: true},
+		{name: "map", value: m, want: `{"count":1}`, wantSet: true},
+		{name: "unsupported", value: struct{}{}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, set, err := valueToString(tt.value)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantSet, set)
+		})
+	}
+}
+
+func TestLogsDataPusher_partition_by(t *testing.T) {
+	c := sarama.NewConfig()
+	producer := mocks.NewSyncProducer(t, c)
+	for _, want := range []struct{ topic, key string }{{"otlp_logs", "a"}, {"otlp_logs", "b"}} {
+		producer.ExpectSendMessageWithMessageCheckerFunctionAndSucceed(func(msg *sarama.ProducerMessage) error {
+			key, err := msg.Key.Encode()
+			if err != nil {
+				return err
+			}
+			if msg.Topic != want.topic || string(key) != want.key {
+				return errors.New("unexpected topic or key")
+			}
+			return nil
+		})
+	}
+
+	partitioner, err := newLogsPartitioner(PartitionBy{Key: `attributes["tenant"]`}, componenttest.NewNopTelemetrySettings())
+	require.NoError(t, err)
+	p := kafkaLogsProducer{
+		cfg:      Config{Topic: "otlp_logs"},
+		producer: producer,
+		// The key of the partition replaces the key of the marshaler.
+		marshaler:   newPdataLogsMarshaler(&plog.ProtoMarshaler{}, defaultEncoding, true),
+		partitioner: partitioner,
+	}
+	t.Cleanup(func() {
+		require.NoError(t, p.Close(context.Background()))
+	})
+
+	ld := plog.NewLogs()
+	sl := ld.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty()
+	sl.LogRecords().AppendEmpty().Attributes().PutStr("tenant", "a")
+	sl.LogRecords().AppendEmpty().Attributes().PutStr("tenant", "b")
+	require.NoError(t, p.logsDataPusher(context.Background(), ld))
+}
@@ -0,0 +1,154 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package loadbalancingexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/loadbalancingexporter"
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+const (
+	// loadHalfLife is the time after which an item counts for half of its load: the load of an endpoint reflects
+	// the items routed to it in the last few seconds.
+	loadHalfLife = time.Second
+	// loadDecayInterval is the minimum interval between two decays of the loads.
+	loadDecayInterval = 100 * time.Millisecond
+	// assignmentTTL is the time after which a routing key not seen anymore is forgotten, and can be assigned to
+	// another endpoint when seen again. It outlives the traces being routed, so that all their spans reach the same
+	// endpoint, e.g. for the tail sampling.
+	assignmentTTL = time.Minute
+)
+
+// boundedLoads implements consistent hashing with bounded loads, following Mirrokni et al.: the load of each endpoint
+// is capped to balanceFactor times its weighted share of the total load, and the new routing keys of an endpoint at
+// its cap go to the next endpoints of the ring. The load of an endpoint is the number of items (spans, data points or
+// log records) recently routed to it.
+//
+// A routing key keeps the endpoint it was assigned to until it isn't seen for assignmentTTL, or the endpoint leaves
+// the ring: only the new routing keys are balanced.
+type boundedLoads struct {
+	balanceFactor float64
+	now           func() time.Time
+
+	mu        sync.Mutex
+	loads     map[string]float64
+	total     float64
+	lastDecay time.Time
+	// assignments are the endpoints of the routing keys seen in the last assignmentTTL
+	assignments map[string]*assignment
+	lastExpiry  time.Time
+}
+
+type assignment struct {
+	endpoint string
+	lastSeen time.Time
+}
+
+func newBoundedLoads(balanceFactor float64) *boundedLoads {
+	return &boundedLoads{
+		balanceFactor: balanceFactor,
+		now:           time.Now,
+		loads:         map[string]float64{},
+		assignments:   map[string]*assignment{},
+	}
+}
+
+// endpointFor returns the endpoint for the given identifier, routing the given number of items, and whether the
+// identifier was newly assigned away from the endpoint owning it in the ring because it was at its cap.
+func (b *boundedLoads) endpointFor(ring *hashRing, identifier []byte, items int) (string, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	now := b.now()
+	b.decay(now)
+	b.expire(now)
+
+	key := string(identifier)
+	if a, ok := b.assignments[key]; ok {
+		if _, inRing := ring.weights[a.endpoint]; inRing {
+			a.lastSeen = now
+			b.add(a.endpoint, items)
+			return a.endpoint, false
+		}
+		delete(b.assignments, key)
+	}
+
+	load := float64(items)
+	var owner, endpoint string
+	skipped := false
+	ring.endpointsFrom(identifier, func(candidate string) bool {
+		if owner == "" {
+			owner = candidate
+		}
+		// the capacity accounts for the items being routed
+		capacity := math.Ceil(b.balanceFactor * (b.total + load) * float64(ring.weights[candidate]) / float64(ring.totalWeight))
+		if b.loads[candidate]+load <= capacity {
+			endpoint = candidate
+			return true
+		}
+		skipped = true
+		return false
+	})
+	if owner == "" {
+		return "", false
+	}
+	if endpoint == "" {
+		// the items are too many to fit under any cap, they stay with the owner
+		endpoint, skipped = owner, false
+	}
+	b.assignments[key] = &assignment{endpoint: endpoint, lastSeen: now}
+	b.add(endpoint, items)
+	return endpoint, skipped
+}
+
+func (b *boundedLoads) add(endpoint string, items int) {
+	b.loads[endpoint] += float64(items)
+	b.total += float64(items)
+}
+
+// decay reduces the loads according to the time elapsed since the last decay.
+func (b *boundedLoads) decay(now time.Time) {
+	elapsed := now.Sub(b.lastDecay)
+	if elapsed < loadDecayInterval {
+		return
+	}
+	b.lastDecay = now
+	factor := math.Exp2(-elapsed.Seconds() / loadHalfLife.Seconds())
+	b.total = 0
+	for endpoint, load := range b.loads {
+		b.loads[endpoint] = load * factor
+		b.total += b.loads[endpoint]
+	}
+}
+
+// expire forgets the routing keys not seen for assignmentTTL. The assignments are scanned once per assignmentTTL,
+// so a routing key is forgotten between one and two assignmentTTL after it was last seen.
+func (b *boundedLoads) expire(now time.Time) {
+	if now.Sub(b.lastExpiry) < assignmentTTL {
+		return
+	}
+	b.lastExpiry = now
+	for key, a := range b.assignments {
+		if now.Sub(a.lastSeen) >= assignmentTTL {
+			delete(b.assignments, key)
+		}
+	}
+}
+
+// retain forgets the loads and the routing keys of the endpoints not in the ring anymore.
+func (b *boundedLoads) retain(ring *hashRing) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for endpoint, load := range b.loads {
+		if _, ok := ring.weights[endpoint]; !ok {
+			delete(b.loads, endpoint)
+			b.total = max(b.total-load, 0)
+		}
+	}
+	for key, a := range b.assignments {
+		if _, ok := ring.weights[a.endpoint]; !ok {
+			delete(b.assignments, key)
+		}
+	}
+}
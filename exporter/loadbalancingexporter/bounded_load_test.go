@@ -0,0 +1,228 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package loadbalancingexporter
+
+import (
+	"fmt"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBoundedLoadsCap(t *testing.T) {
+	// prepare
+	endpoints := []string{"endpoint-1", "endpoint-2", "endpoint-3", "endpoint-4"}
+	ring := newHashRing(endpoints)
+	loads := newBoundedLoads(1.25)
+	now := time.Now()
+	loads.now = func() time.Time { return now }
+
+	// test
+	counts := map[string]int{}
+	overflows := 0
+	for i := 0; i < 1000; i++ {
+		id := []byte(fmt.Sprintf("service-%d", i))
+		endpoint, overflow := loads.endpointFor(ring, id, 1)
+		counts[endpoint]++
+		if overflow {
+			overflows++
+			assert.NotEqual(t, ring.endpointFor(id), endpoint)
+		} else {
+			assert.Equal(t, ring.endpointFor(id), endpoint)
+		}
+	}
+
+	// verify
+	capacity := int(math.Ceil(1.25 * 1000 / 4))
+	for _, endpoint := range endpoints {
+		assert.LessOrEqual(t, counts[endpoint], capacity, endpoint)
+	}
+	assert.Positive(t, overflows)
+}
+
+func TestBoundedLoadsWeights(t *testing.T) {
+	// prepare
+	ring := newWeightedHashRing([]string{"small", "large"}, map[string]int{"large": 3})
+	loads := newBoundedLoads(1)
+	now := time.Now()
+	loads.now = func() time.Time { return now }
+
+	// test
+	counts := map[string]int{}
+	for i := 0; i < 1000; i++ {
+		endpoint, _ := loads.endpointFor(ring, []byte(fmt.Sprintf("service-%d", i)), 1)
+		counts[endpoint]++
+	}
+
+	// verify
+	assert.InDelta(t, 250, counts["small"], 2)
+	assert.InDelta(t, 750, counts["large"], 2)
+}
+
+func TestBoundedLoadsKeepsOwnerUnderCap(t *testing.T) {
+	// prepare
+	ring := newHashRing([]string{"endpoint-1", "endpoint-2", "endpoint-3"})
+	// a high factor leaves enough room for the natural imbalance of the ring
+	loads := newBoundedLoads(100)
+	now := time.Now()
+	loads.now = func() time.Time { return now }
+
+	for i := 0; i < 300; i++ {
+		id := []byte(fmt.Sprintf("service-%d", i))
+
+		// test
+		endpoint, overflow := loads.endpointFor(ring, id, 1)
+
+		// verify
+		assert.Equal(t, ring.endpointFor(id), endpoint)
+		assert.False(t, overflow)
+	}
+}
+
+func TestBoundedLoadsSticky(t *testing.T) {
+	// prepare
+	ring := newHashRing([]string{"endpoint-1", "endpoint-2"})
+	loads := newBoundedLoads(1.25)
+	now := time.Now()
+	loads.now = func() time.Time { return now }
+	owner := ring.endpointFor([]byte("hot-trace"))
+
+	// test
+	for i := 0; i < 100; i++ {
+		// the routing key stays with its endpoint, even beyond the cap
+		endpoint, overflow := loads.endpointFor(ring, []byte("hot-trace"), 10)
+
+		// verify
+		require.Equal(t, owner, endpoint)
+		require.False(t, overflow)
+	}
+	assert.InDelta(t, 1000, loads.loads[owner], 1e-9)
+
+	// test
+	// the owner of a new routing key is at its cap
+	var other []byte
+	for i := 0; other == nil; i++ {
+		if id := []byte(fmt.Sprintf("trace-%d", i)); ring.endpointFor(id) == owner {
+			other = id
+		}
+	}
+	endpoint, overflow := loads.endpointFor(ring, other, 1)
+
+	// verify
+	assert.NotEqual(t, owner, endpoint)
+	assert.True(t, overflow)
+
+	// test
+	// the routing keys seen within the TTL keep their endpoint
+	for i := 0; i < 3; i++ {
+		now = now.Add(assignmentTTL / 2)
+		endpoint, _ = loads.endpointFor(ring, other, 1)
+		assert.NotEqual(t, owner, endpoint)
+	}
+
+	// verify
+	// the hot routing key wasn't seen for more than the TTL, it's forgotten
+	assert.NotContains(t, loads.assignments, "hot-trace")
+	assert.Contains(t, loads.assignments, string(other))
+
+	// test
+	// the loads decayed, the routing key goes back to its owner once forgotten
+	now = now.Add(2 * assignmentTTL)
+	endpoint, overflow = loads.endpointFor(ring, other, 1)
+
+	// verify
+	assert.Equal(t, owner, endpoint)
+	assert.False(t, overflow)
+}
+
+func TestBoundedLoadsItems(t *testing.T) {
+	// prepare
+	ring := newHashRing([]string{"endpoint-1", "endpoint-2"})
+	loads := newBoundedLoads(1.25)
+	now := time.Now()
+	loads.now = func() time.Time { return now }
+
+	// test
+	// the items are too many to fit under any cap
+	endpoint, overflow := loads.endpointFor(ring, []byte("trace-1"), 100)
+
+	// verify
+	assert.Equal(t, ring.endpointFor([]byte("trace-1")), endpoint)
+	assert.False(t, overflow)
+	assert.InDelta(t, 100, loads.loads[endpoint], 1e-9)
+	assert.InDelta(t, 100, loads.total, 1e-9)
+}
+
+func TestBoundedLoadsDecay(t *testing.T) {
+	// prepare
+	ring := newHashRing([]string{"endpoint-1", "endpoint-2"})
+	loads := newBoundedLoads(1.25)
+	now := time.Now()
+	loads.now = func() time.Time { return now }
+	for i := 0; i < 100; i++ {
+		loads.endpointFor(ring, []byte("hot-service"), 1)
+	}
+	assert.InDelta(t, 100, loads.total, 1e-9)
+
+	// test
+	now = now.Add(2 * loadHalfLife)
+	loads.endpointFor(ring, []byte("hot-service"), 1)
+
+	// verify
+	assert.InDelta(t, 26, loads.total, 1e-9)
+
+	// the loads don't decay within the decay interval
+	now = now.Add(loadDecayInterval / 2)
+	loads.endpointFor(ring, []byte("hot-service"), 1)
+	assert.InDelta(t, 27, loads.total, 1e-9)
+}
+
+func TestBoundedLoadsRetain(t *testing.T) {
+	// prepare
+	loads := newBoundedLoads(1.25)
+	ring := newHashRing([]string{"endpoint-1", "endpoint-2"})
+	for i := 0; i < 10; i++ {
+		loads.endpointFor(ring, []byte(fmt.Sprintf("service-%d", i)), 1)
+	}
+
+	// test
+	loads.retain(newHashRing([]string{"endpoint-1"}))
+
+	// verify
+	assert.NotContains(t, loads.loads, "endpoint-2")
+	assert.InDelta(t, loads.loads["endpoint-1"], loads.total, 1e-9)
+	for _, a := range loads.assignments {
+		assert.Equal(t, "endpoint-1", a.endpoint)
+	}
+}
+
+func TestBoundedLoadsEndpointLeftRing(t *testing.T) {
+	// prepare
+	loads := newBoundedLoads(1.25)
+	ring := newHashRing([]string{"endpoint-1", "endpoint-2"})
+	endpoint, _ := loads.endpointFor(ring, []byte("trace-1"), 1)
+	var remaining string
+	for _, e := range []string{"endpoint-1", "endpoint-2"} {
+		if e != endpoint {
+			remaining = e
+		}
+	}
+
+	// test
+	endpoint, _ = loads.endpointFor(newHashRing([]string{remaining}), []byte("trace-1"), 1)
+
+	// verify
+	assert.Equal(t, remaining, endpoint)
+	assert.Equal(t, remaining, loads.assignments["trace-1"].endpoint)
+}
+
+func TestBoundedLoadsEmptyRing(t *testing.T) {
+	loads := newBoundedLoads(1.25)
+	endpoint, overflow := loads.endpointFor(newHashRing(nil), []byte("hot-service"), 1)
+	assert.Empty(t, endpoint)
+	assert.False(t, overflow)
+}
@@ -7,9 +7,14 @@ import (
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/service/servicediscovery/types"
+	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config/configretry"
+	"go.opentelemetry.io/collector/confmap"
 	"go.opentelemetry.io/collector/exporter/exporterhelper"
 	"go.opentelemetry.io/collector/exporter/otlpexporter"
+	"go.opentelemetry.io/collector/exporter/otlphttpexporter"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/otelarrowexporter"
 )
 
 type routingKey int
@@ -39,11 +44,82 @@ type Config struct {
 	Protocol   Protocol         `mapstructure:"protocol"`
 	Resolver   ResolverSettings `mapstructure:"resolver"`
 	RoutingKey string           `mapstructure:"routing_key"`
+
+	// BalanceFactor enables the consistent hashing with bounded loads when set: the load of each backend is capped to
+	// BalanceFactor times its weighted share of the total load, and the new routing keys of the backends at their cap
+	// go to the next backends of the ring, where they stay as long as they are seen. It has to be at least 1, the lower
+	// the more even the loads, at the cost of more routing keys being moved away from their backend.
+	BalanceFactor float64 `mapstructure:"balance_factor"`
+
+	// HealthCheck configures the ejection of the unhealthy backends from the ring.
+	HealthCheck HealthCheck `mapstructure:"health_check"`
 }
 
-// Protocol holds the individual protocol-specific settings. Only OTLP is supported at the moment.
+var _ component.ConfigValidator = (*Config)(nil)
+
+// Validate checks that at most one protocol is configured, including in the configurations built in code.
+func (cfg *Config) Validate() error {
+	if cfg.Protocol.OTLPHTTP != nil && cfg.Protocol.OTelArrow != nil {
+		return errMultipleProtocolsProvided
+	}
+	return nil
+}
+
+// HealthCheck defines the health checking of the backends: a backend failing ConsecutiveFailures exports or probes in
+// a row is ejected from the ring, and reinstated after an ejection time doubling with each consecutive ejection.
+type HealthCheck struct {
+	// ConsecutiveFailures is the number of consecutive failures after which a backend is ejected, 0 disables the
+	// health checking.
+	ConsecutiveFailures int `mapstructure:"consecutive_failures"`
+	// BaseEjectionTime is the ejection time of a backend ejected for the first time.
+	BaseEjectionTime time.Duration `mapstructure:"base_ejection_time"`
+	// MaxEjectionTime caps the ejection time of the backends ejected repeatedly.
+	MaxEjectionTime time.Duration `mapstructure:"max_ejection_time"`
+	// MaxEjectionPercent is the maximum percentage of the backends ejected at the same time. One backend can always
+	// be ejected, as long as another one remains in the ring.
+	MaxEjectionPercent int `mapstructure:"max_ejection_percent"`
+	// GRPCProbe enables the active health checking of the backends with the gRPC health checking protocol.
+	GRPCProbe *GRPCProbe `mapstructure:"grpc_probe"`
+}
+
+// GRPCProbe defines the active health checking of the backends with the gRPC health checking protocol
+type GRPCProbe struct {
+	Interval time.Duration `mapstructure:"interval"`
+	Timeout  time.Duration `mapstructure:"timeout"`
+	// Port is the port of the health service, the port of the backend is used when not set.
+	Port uint16 `mapstructure:"port"`
+	// Service is the name of the service whose health is checked, the overall health of the server is checked when empty.
+	Service string `mapstructure:"service"`
+}
+
+// Protocol holds the individual protocol-specific settings. Only one protocol can be configured, OTLP over gRPC is
+// used when no other protocol is configured.
 type Protocol struct {
-	OTLP otlpexporter.Config `mapstructure:"otlp"`
+	OTLP      otlpexporter.Config       `mapstructure:"otlp"`
+	OTLPHTTP  *otlphttpexporter.Config  `mapstructure:"otlphttp"`
+	OTelArrow *otelarrowexporter.Config `mapstructure:"otelarrow"`
+}
+
+var _ confmap.Unmarshaler = (*Protocol)(nil)
+
+// Unmarshal starts from the default configuration of the protocols present in the configuration.
+func (p *Protocol) Unmarshal(conf *confmap.Conf) error {
+	count := 0
+	for _, protocol := range []string{"otlp", "otlphttp", "otelarrow"} {
+		if conf.IsSet(protocol) {
+			count++
+		}
+	}
+	if count > 1 {
+		return errMultipleProtocolsProvided
+	}
+	if conf.IsSet("otlphttp") {
+		p.OTLPHTTP = otlphttpexporter.NewFactory().CreateDefaultConfig().(*otlphttpexporter.Config)
+	}
+	if conf.IsSet("otelarrow") {
+		p.OTelArrow = otelarrowexporter.NewFactory().CreateDefaultConfig().(*otelarrowexporter.Config)
+	}
+	return conf.Unmarshal(p)
 }
 
 // ResolverSettings defines the configurations for the backend resolver
@@ -56,23 +132,26 @@ type ResolverSettings struct {
 
 // StaticResolver defines the configuration for the resolver providing a fixed list of backends
 type StaticResolver struct {
-	Hostnames []string `mapstructure:"hostnames"`
+	Hostnames []string       `mapstructure:"hostnames"`
+	Weights   map[string]int `mapstructure:"weights"`
 }
 
 // DNSResolver defines the configuration for the DNS resolver
 type DNSResolver struct {
-	Hostname string        `mapstructure:"hostname"`
-	Port     string        `mapstructure:"port"`
-	Interval time.Duration `mapstructure:"interval"`
-	Timeout  time.Duration `mapstructure:"timeout"`
+	Hostname string         `mapstructure:"hostname"`
+	Port     string         `mapstructure:"port"`
+	Interval time.Duration  `mapstructure:"interval"`
+	Timeout  time.Duration  `mapstructure:"timeout"`
+	Weights  map[string]int `mapstructure:"weights"`
 }
 
 // K8sSvcResolver defines the configuration for the DNS resolver
 type K8sSvcResolver struct {
-	Service         string        `mapstructure:"service"`
-	Ports           []int32       `mapstructure:"ports"`
-	Timeout         time.Duration `mapstructure:"timeout"`
-	ReturnHostnames bool          `mapstructure:"return_hostnames"`
+	Service         string         `mapstructure:"service"`
+	Ports           []int32        `mapstructure:"ports"`
+	Timeout         time.Duration  `mapstructure:"timeout"`
+	ReturnHostnames bool           `mapstructure:"return_hostnames"`
+	Weights         map[string]int `mapstructure:"weights"`
 }
 
 type AWSCloudMapResolver struct {
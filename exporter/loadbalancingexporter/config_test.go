@@ -6,12 +6,17 @@ package loadbalancingexporter
 import (
 	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/confmap"
 	"go.opentelemetry.io/collector/confmap/confmaptest"
+	"go.opentelemetry.io/collector/exporter/otlphttpexporter"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/loadbalancingexporter/internal/metadata"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/otelarrowexporter"
 )
 
 func TestLoadConfig(t *testing.T) {
@@ -24,4 +29,75 @@ func TestLoadConfig(t *testing.T) {
 	require.NoError(t, err)
 	require.NoError(t, sub.Unmarshal(cfg))
 	require.NotNil(t, cfg)
+
+	cfg = factory.CreateDefaultConfig()
+	sub, err = cm.Sub(component.NewIDWithName(metadata.Type, "6").String())
+	require.NoError(t, err)
+	require.NoError(t, sub.Unmarshal(cfg))
+	assert.Equal(t, 1.25, cfg.(*Config).BalanceFactor)
+	assert.Equal(t, map[string]int{"endpoint-2:55678": 3}, cfg.(*Config).Resolver.Static.Weights)
+
+	cfg = factory.CreateDefaultConfig()
+	sub, err = cm.Sub(component.NewIDWithName(metadata.Type, "7").String())
+	require.NoError(t, err)
+	require.NoError(t, sub.Unmarshal(cfg))
+	assert.Equal(t, HealthCheck{
+		ConsecutiveFailures: 5,
+		BaseEjectionTime:    10 * time.Second,
+		MaxEjectionTime:     defaultMaxEjectionTime,
+		MaxEjectionPercent:  defaultMaxEjectionPercent,
+		GRPCProbe: &GRPCProbe{
+			Interval: 5 * time.Second,
+			Port:     13133,
+		},
+	}, cfg.(*Config).HealthCheck)
+
+	cfg = factory.CreateDefaultConfig()
+	sub, err = cm.Sub(component.NewIDWithName(metadata.Type, "8").String())
+	require.NoError(t, err)
+	require.NoError(t, sub.Unmarshal(cfg))
+	require.NotNil(t, cfg.(*Config).Protocol.OTelArrow)
+	assert.Nil(t, cfg.(*Config).Protocol.OTLPHTTP)
+	assert.Equal(t, 2, cfg.(*Config).Protocol.OTelArrow.Arrow.NumStreams)
+	// the unset settings keep the defaults of the otelarrow exporter
+	defaultArrowCfg := otelarrowexporter.NewFactory().CreateDefaultConfig().(*otelarrowexporter.Config)
+	assert.Equal(t, defaultArrowCfg.Arrow.MaxStreamLifetime, cfg.(*Config).Protocol.OTelArrow.Arrow.MaxStreamLifetime)
+
+	cfg = factory.CreateDefaultConfig()
+	sub, err = cm.Sub(component.NewIDWithName(metadata.Type, "9").String())
+	require.NoError(t, err)
+	require.NoError(t, sub.Unmarshal(cfg))
+	require.NotNil(t, cfg.(*Config).Protocol.OTLPHTTP)
+	assert.Nil(t, cfg.(*Config).Protocol.OTelArrow)
+	assert.True(t, cfg.(*Config).Protocol.OTLPHTTP.ClientConfig.TLSSetting.Insecure)
+	defaultHTTPCfg := otlphttpexporter.NewFactory().CreateDefaultConfig().(*otlphttpexporter.Config)
+	assert.Equal(t, defaultHTTPCfg.RetryConfig, cfg.(*Config).Protocol.OTLPHTTP.RetryConfig)
+}
+
+func TestValidateMultipleProtocols(t *testing.T) {
+	// prepare
+	cfg := simpleConfig()
+	cfg.Protocol.OTLPHTTP = &otlphttpexporter.Config{}
+	cfg.Protocol.OTelArrow = &otelarrowexporter.Config{}
+
+	// test and verify
+	assert.Equal(t, errMultipleProtocolsProvided, cfg.Validate())
+
+	// test and verify
+	cfg.Protocol.OTelArrow = nil
+	assert.NoError(t, cfg.Validate())
+}
+
+func TestLoadConfigMultipleProtocols(t *testing.T) {
+	cfg := NewFactory().CreateDefaultConfig()
+	conf := confmap.NewFromStringMap(map[string]any{
+		"protocol": map[string]any{
+			"otlp":      map[string]any{},
+			"otelarrow": map[string]any{},
+		},
+	})
+
+	err := conf.Unmarshal(cfg)
+
+	assert.ErrorIs(t, err, errMultipleProtocolsProvided)
 }
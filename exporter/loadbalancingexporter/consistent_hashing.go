@@ -4,6 +4,7 @@
 package loadbalancingexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/loadbalancingexporter"
 
 import (
+	"encoding/binary"
 	"hash/crc32"
 	"sort"
 )
@@ -13,6 +14,9 @@ const (
 	defaultWeight int    = 100   // the number of points in the ring for each entry. For better results, it should be greater than 100.
 )
 
+// defaultEndpointWeight is the weight of the endpoints without a configured weight.
+const defaultEndpointWeight = 1
+
 // position represents a specific angle in the ring.
 // Each entry in the ring is positioned at an angle in a hypothetical circle, meaning that it ranges from 0 to 360.
 type position uint32
@@ -27,14 +31,35 @@ type ringItem struct {
 type hashRing struct {
 	// ringItems holds all the positions, used for the lookup the position for the closest next ring item
 	items []ringItem
+
+	// weights holds the weight of each endpoint, and totalWeight their sum.
+	weights     map[string]int
+	totalWeight int
 }
 
 // newHashRing builds a new immutable consistent hash ring based on the given endpoints.
 func newHashRing(endpoints []string) *hashRing {
-	items := positionsForEndpoints(endpoints, defaultWeight)
-	return &hashRing{
-		items: items,
+	return newWeightedHashRing(endpoints, nil)
+}
+
+// newWeightedHashRing builds a new immutable consistent hash ring based on the given endpoints, each endpoint
+// having a number of points in the ring proportional to its weight. Endpoints missing from weights have the default weight.
+func newWeightedHashRing(endpoints []string, weights map[string]int) *hashRing {
+	ring := &hashRing{
+		weights: make(map[string]int, len(endpoints)),
+	}
+	for _, endpoint := range endpoints {
+		weight, ok := weights[endpoint]
+		if !ok {
+			weight = defaultEndpointWeight
+		}
+		ring.weights[endpoint] = weight
+		ring.totalWeight += weight
 	}
+	ring.items = weightedPositionsForEndpoints(endpoints, func(endpoint string) int {
+		return defaultWeight * ring.weights[endpoint]
+	})
+	return ring
 }
 
 // endpointFor calculates which backend is responsible for the given traceID
@@ -106,7 +131,12 @@ func positionsFor(endpoint string, numPoints int) []position {
 	for i := 0; i < numPoints; i++ {
 		h := crc32.NewIEEE()
 		h.Write([]byte(endpoint))
-		h.Write([]byte{byte(i)})
+		if i < 256 {
+			h.Write([]byte{byte(i)})
+		} else {
+			// the points beyond the first 256 ones, for weighted endpoints, need the whole index to get distinct positions
+			h.Write(binary.BigEndian.AppendUint32(nil, uint32(i)))
+		}
 		hash := h.Sum32()
 		pos := hash % maxPositions
 		res = append(res, position(pos))
@@ -117,11 +147,18 @@ func positionsFor(endpoint string, numPoints int) []position {
 
 // positionsForEndpoints calculates all the positions for all the given endpoints
 func positionsForEndpoints(endpoints []string, weight int) []ringItem {
+	return weightedPositionsForEndpoints(endpoints, func(string) int {
+		return weight
+	})
+}
+
+// weightedPositionsForEndpoints calculates all the positions for all the given endpoints, numPoints returning the
+// number of positions of each endpoint
+func weightedPositionsForEndpoints(endpoints []string, numPoints func(endpoint string) int) []ringItem {
 	var items []ringItem
 	positions := map[position]bool{} // tracking the used positions
 	for _, endpoint := range endpoints {
-		// for this initial implementation, we don't allow endpoints to have custom weights
-		for _, pos := range positionsFor(endpoint, weight) {
+		for _, pos := range positionsFor(endpoint, numPoints(endpoint)) {
 			// if this position is occupied already, skip this item
 			if _, found := positions[pos]; found {
 				continue
@@ -143,6 +180,49 @@ func positionsForEndpoints(endpoints []string, weight int) []ringItem {
 	return items
 }
 
+// endpointsFrom calls fn with the distinct endpoints of the ring, in the order they are found walking the ring from
+// the position of the given identifier, until fn returns true.
+func (h *hashRing) endpointsFrom(identifier []byte, fn func(endpoint string) bool) {
+	if h == nil || len(h.items) == 0 {
+		return
+	}
+	pos := position(crc32.ChecksumIEEE(identifier) % maxPositions)
+	start := sort.Search(len(h.items), func(i int) bool {
+		return h.items[i].pos >= pos
+	})
+	visited := make(map[string]bool, len(h.weights))
+	for i := 0; i < len(h.items) && len(visited) < len(h.weights); i++ {
+		endpoint := h.items[(start+i)%len(h.items)].endpoint
+		if visited[endpoint] {
+			continue
+		}
+		visited[endpoint] = true
+		if fn(endpoint) {
+			return
+		}
+	}
+}
+
+// shares returns the fraction of the ring owned by each endpoint, that is the fraction of the positions for which
+// it is the "next" endpoint.
+func (h *hashRing) shares() map[string]float64 {
+	arcs := make(map[string]uint32, len(h.weights))
+	for i, item := range h.items {
+		// the item owns the positions after the previous item, up to and including its own
+		prev := h.items[(i+len(h.items)-1)%len(h.items)].pos
+		arc := (uint32(item.pos) + maxPositions - uint32(prev)) % maxPositions
+		if len(h.items) == 1 {
+			arc = maxPositions
+		}
+		arcs[item.endpoint] += arc
+	}
+	shares := make(map[string]float64, len(h.weights))
+	for endpoint := range h.weights {
+		shares[endpoint] = float64(arcs[endpoint]) / float64(maxPositions)
+	}
+	return shares
+}
+
 func (h *hashRing) equal(candidate *hashRing) bool {
 	if candidate == nil {
 		return false
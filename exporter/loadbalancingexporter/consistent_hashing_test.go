@@ -155,7 +155,7 @@ func TestPositionsForEndpoints(t *testing.T) {
 
 func TestEqual(t *testing.T) {
 	original := &hashRing{
-		[]ringItem{
+		items: []ringItem{
 			{pos: position(123), endpoint: "endpoint-1"},
 		},
 	}
@@ -167,7 +167,7 @@ func TestEqual(t *testing.T) {
 	}{
 		{
 			"empty",
-			&hashRing{[]ringItem{}},
+			&hashRing{items: []ringItem{}},
 			false,
 		},
 		{
@@ -178,7 +178,7 @@ func TestEqual(t *testing.T) {
 		{
 			"equal",
 			&hashRing{
-				[]ringItem{
+				items: []ringItem{
 					{pos: position(123), endpoint: "endpoint-1"},
 				},
 			},
@@ -187,7 +187,7 @@ func TestEqual(t *testing.T) {
 		{
 			"different length",
 			&hashRing{
-				[]ringItem{
+				items: []ringItem{
 					{pos: position(123), endpoint: "endpoint-1"},
 					{pos: position(124), endpoint: "endpoint-2"},
 				},
@@ -197,7 +197,7 @@ func TestEqual(t *testing.T) {
 		{
 			"different position",
 			&hashRing{
-				[]ringItem{
+				items: []ringItem{
 					{pos: position(124), endpoint: "endpoint-1"},
 				},
 			},
@@ -206,7 +206,7 @@ func TestEqual(t *testing.T) {
 		{
 			"different endpoint",
 			&hashRing{
-				[]ringItem{
+				items: []ringItem{
 					{pos: position(123), endpoint: "endpoint-2"},
 				},
 			},
@@ -218,3 +218,83 @@ func TestEqual(t *testing.T) {
 		})
 	}
 }
+
+func TestNewWeightedHashRing(t *testing.T) {
+	// prepare
+	endpoints := []string{"endpoint-1", "endpoint-2", "endpoint-3"}
+
+	// test
+	ring := newWeightedHashRing(endpoints, map[string]int{"endpoint-2": 3})
+
+	// verify
+	assert.Equal(t, map[string]int{"endpoint-1": 1, "endpoint-2": 3, "endpoint-3": 1}, ring.weights)
+	assert.Equal(t, 5, ring.totalWeight)
+	count := map[string]int{}
+	for _, item := range ring.items {
+		count[item.endpoint]++
+	}
+	// a few positions might be taken by the other endpoints already
+	assert.InDelta(t, 3*defaultWeight, count["endpoint-2"], 10)
+	assert.InDelta(t, defaultWeight, count["endpoint-1"], 10)
+
+	// the ring without weights is the same as the unweighted one
+	assert.True(t, newWeightedHashRing(endpoints, nil).equal(newHashRing(endpoints)))
+}
+
+func TestPositionsForMoreThan256Points(t *testing.T) {
+	// test
+	positions := positionsFor("host1", 1000)
+
+	// verify
+	distinct := map[position]bool{}
+	for _, pos := range positions {
+		distinct[pos] = true
+	}
+	// crc32 modulo the number of positions might collide a few times
+	assert.Greater(t, len(distinct), 950)
+}
+
+func TestEndpointsFrom(t *testing.T) {
+	// prepare
+	endpoints := []string{"endpoint-1", "endpoint-2", "endpoint-3"}
+	ring := newHashRing(endpoints)
+
+	for _, id := range [][]byte{{1, 2, 0, 0}, {128, 128, 0, 0}, []byte("ad-service-7")} {
+		// test
+		var visited []string
+		ring.endpointsFrom(id, func(endpoint string) bool {
+			visited = append(visited, endpoint)
+			return false
+		})
+
+		// verify
+		assert.ElementsMatch(t, endpoints, visited)
+		assert.Equal(t, ring.endpointFor(id), visited[0])
+	}
+
+	// the walk stops when the function returns true
+	calls := 0
+	ring.endpointsFrom([]byte("ad-service-7"), func(string) bool {
+		calls++
+		return true
+	})
+	assert.Equal(t, 1, calls)
+}
+
+func TestShares(t *testing.T) {
+	// prepare
+	ring := newWeightedHashRing([]string{"endpoint-1", "endpoint-2"}, map[string]int{"endpoint-2": 3})
+
+	// test
+	shares := ring.shares()
+
+	// verify
+	assert.InDelta(t, 1, shares["endpoint-1"]+shares["endpoint-2"], 1e-9)
+	assert.InDelta(t, 0.75, shares["endpoint-2"], 0.1)
+
+	single := &hashRing{
+		items:   []ringItem{{pos: 10, endpoint: "endpoint-1"}},
+		weights: map[string]int{"endpoint-1": 1},
+	}
+	assert.Equal(t, map[string]float64{"endpoint-1": 1}, single.shares())
+}
@@ -10,12 +10,16 @@ import (
 	"fmt"
 
 	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configgrpc"
+	"go.opentelemetry.io/collector/config/configretry"
 	"go.opentelemetry.io/collector/exporter"
 	"go.opentelemetry.io/collector/exporter/exporterhelper"
 	"go.opentelemetry.io/collector/exporter/otlpexporter"
+	"go.opentelemetry.io/collector/exporter/otlphttpexporter"
 	"go.uber.org/zap"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/loadbalancingexporter/internal/metadata"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/otelarrowexporter"
 )
 
 const (
@@ -44,16 +48,102 @@ func createDefaultConfig() component.Config {
 		Protocol: Protocol{
 			OTLP: *otlpDefaultCfg,
 		},
+		HealthCheck: HealthCheck{
+			BaseEjectionTime:   defaultBaseEjectionTime,
+			MaxEjectionTime:    defaultMaxEjectionTime,
+			MaxEjectionPercent: defaultMaxEjectionPercent,
+		},
 	}
 }
 
 func buildExporterConfig(cfg *Config, endpoint string) otlpexporter.Config {
 	oCfg := cfg.Protocol.OTLP
 	oCfg.Endpoint = endpoint
+	disableResilienceWhenHealthChecked(cfg, &oCfg.QueueConfig, &oCfg.RetryConfig)
+
+	return oCfg
+}
+
+// buildHTTPExporterConfig builds the configuration of the OTLP/HTTP exporter to the endpoint. The endpoint is reached
+// over HTTPS unless the TLS settings are insecure, and the signal-specific endpoints are ignored.
+func buildHTTPExporterConfig(cfg *Config, endpoint string) otlphttpexporter.Config {
+	oCfg := *cfg.Protocol.OTLPHTTP
+	scheme := "https"
+	if oCfg.ClientConfig.TLSSetting.Insecure {
+		scheme = "http"
+	}
+	oCfg.ClientConfig.Endpoint = fmt.Sprintf("%s://%s", scheme, endpoint)
+	oCfg.TracesEndpoint = ""
+	oCfg.MetricsEndpoint = ""
+	oCfg.LogsEndpoint = ""
+	disableResilienceWhenHealthChecked(cfg, &oCfg.QueueConfig, &oCfg.RetryConfig)
+
+	return oCfg
+}
+
+func buildArrowExporterConfig(cfg *Config, endpoint string) otelarrowexporter.Config {
+	oCfg := *cfg.Protocol.OTelArrow
+	oCfg.Endpoint = endpoint
+	disableResilienceWhenHealthChecked(cfg, &oCfg.QueueSettings, &oCfg.RetryConfig)
 
 	return oCfg
 }
 
+// disableResilienceWhenHealthChecked disables the sending queue and the retries of the exporter to an endpoint when the
+// health checking is enabled: the enqueued exports succeed right away, and the retried exports block until the retries
+// are exhausted, which would hide or delay the failures of the endpoint. The queue and the retries of the loadbalancing
+// exporter can be used instead, each of their attempts being recorded.
+func disableResilienceWhenHealthChecked(cfg *Config, queueCfg *exporterhelper.QueueConfig, retryCfg *configretry.BackOffConfig) {
+	if cfg.HealthCheck.ConsecutiveFailures > 0 {
+		queueCfg.Enabled = false
+		retryCfg.Enabled = false
+	}
+}
+
+// protocolResilience returns the sending queue and retry settings of the configured protocol.
+func protocolResilience(cfg *Config) (exporterhelper.QueueConfig, configretry.BackOffConfig) {
+	switch {
+	case cfg.Protocol.OTelArrow != nil:
+		return cfg.Protocol.OTelArrow.QueueSettings, cfg.Protocol.OTelArrow.RetryConfig
+	case cfg.Protocol.OTLPHTTP != nil:
+		return cfg.Protocol.OTLPHTTP.QueueConfig, cfg.Protocol.OTLPHTTP.RetryConfig
+	default:
+		return cfg.Protocol.OTLP.QueueConfig, cfg.Protocol.OTLP.RetryConfig
+	}
+}
+
+// buildExporterFactoryAndConfig returns the factory and the configuration of the exporter to the endpoint, according
+// to the configured protocol.
+func buildExporterFactoryAndConfig(cfg *Config, endpoint string) (exporter.Factory, component.Config) {
+	switch {
+	case cfg.Protocol.OTelArrow != nil:
+		oCfg := buildArrowExporterConfig(cfg, endpoint)
+		return otelarrowexporter.NewFactory(), &oCfg
+	case cfg.Protocol.OTLPHTTP != nil:
+		oCfg := buildHTTPExporterConfig(cfg, endpoint)
+		return otlphttpexporter.NewFactory(), &oCfg
+	default:
+		oCfg := buildExporterConfig(cfg, endpoint)
+		return otlpexporter.NewFactory(), &oCfg
+	}
+}
+
+// buildProbeClientConfig returns the gRPC client settings used to probe the endpoints, from the configured protocol.
+func buildProbeClientConfig(cfg *Config) configgrpc.ClientConfig {
+	switch {
+	case cfg.Protocol.OTelArrow != nil:
+		return cfg.Protocol.OTelArrow.ClientConfig
+	case cfg.Protocol.OTLPHTTP != nil:
+		return configgrpc.ClientConfig{
+			TLSSetting: cfg.Protocol.OTLPHTTP.ClientConfig.TLSSetting,
+			Headers:    cfg.Protocol.OTLPHTTP.ClientConfig.Headers,
+			Auth:       cfg.Protocol.OTLPHTTP.ClientConfig.Auth,
+		}
+	default:
+		return cfg.Protocol.OTLP.ClientConfig
+	}
+}
+
 func buildExporterSettings(params exporter.Settings, endpoint string) exporter.Settings {
 	// Override child exporter ID to segregate metrics from loadbalancing top level
 	childName := endpoint
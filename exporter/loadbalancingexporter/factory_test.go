@@ -12,15 +12,18 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configopaque"
 	"go.opentelemetry.io/collector/config/configretry"
 	"go.opentelemetry.io/collector/exporter/exporterhelper"
 	"go.opentelemetry.io/collector/exporter/exportertest"
 	"go.opentelemetry.io/collector/exporter/otlpexporter"
+	"go.opentelemetry.io/collector/exporter/otlphttpexporter"
 	"go.opentelemetry.io/collector/otelcol/otelcoltest"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zaptest/observer"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/loadbalancingexporter/internal/metadata"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/otelarrowexporter"
 )
 
 func TestTracesExporterGetsCreatedWithValidConfiguration(t *testing.T) {
@@ -100,6 +103,127 @@ func TestBuildExporterConfig(t *testing.T) {
 	assert.Equal(t, defaultCfg.RetryConfig, exporterCfg.RetryConfig)
 }
 
+func TestBuildExporterConfigWithHealthCheck(t *testing.T) {
+	// prepare
+	cfg := createDefaultConfig().(*Config)
+	cfg.HealthCheck.ConsecutiveFailures = 3
+	queueCfg, retryCfg := protocolResilience(cfg)
+	require.True(t, queueCfg.Enabled)
+	require.True(t, retryCfg.Enabled)
+
+	// test and verify
+	exporterCfg := buildExporterConfig(cfg, "the-endpoint")
+	assert.False(t, exporterCfg.QueueConfig.Enabled)
+	assert.False(t, exporterCfg.RetryConfig.Enabled)
+	assert.True(t, cfg.Protocol.OTLP.QueueConfig.Enabled, "the template is left untouched")
+	assert.True(t, cfg.Protocol.OTLP.RetryConfig.Enabled, "the template is left untouched")
+
+	cfg.Protocol.OTLPHTTP = otlphttpexporter.NewFactory().CreateDefaultConfig().(*otlphttpexporter.Config)
+	httpCfg := buildHTTPExporterConfig(cfg, "the-endpoint:4318")
+	assert.False(t, httpCfg.QueueConfig.Enabled)
+	assert.False(t, httpCfg.RetryConfig.Enabled)
+
+	cfg.Protocol.OTLPHTTP = nil
+	cfg.Protocol.OTelArrow = otelarrowexporter.NewFactory().CreateDefaultConfig().(*otelarrowexporter.Config)
+	arrowCfg := buildArrowExporterConfig(cfg, "the-endpoint")
+	assert.False(t, arrowCfg.QueueSettings.Enabled)
+	assert.False(t, arrowCfg.RetryConfig.Enabled)
+
+	// the queue and the retries are kept when the health checking is disabled
+	cfg.HealthCheck.ConsecutiveFailures = 0
+	arrowCfg = buildArrowExporterConfig(cfg, "the-endpoint")
+	assert.True(t, arrowCfg.QueueSettings.Enabled)
+	assert.True(t, arrowCfg.RetryConfig.Enabled)
+}
+
+func TestBuildHTTPExporterConfig(t *testing.T) {
+	// prepare
+	cfg := createDefaultConfig().(*Config)
+	cfg.Protocol.OTLPHTTP = otlphttpexporter.NewFactory().CreateDefaultConfig().(*otlphttpexporter.Config)
+	cfg.Protocol.OTLPHTTP.TracesEndpoint = "https://should-be-ignored:4318/v1/traces"
+
+	// test
+	exporterCfg := buildHTTPExporterConfig(cfg, "the-endpoint:4318")
+
+	// verify
+	assert.Equal(t, "https://the-endpoint:4318", exporterCfg.ClientConfig.Endpoint)
+	assert.Empty(t, exporterCfg.TracesEndpoint)
+	assert.Equal(t, "https://should-be-ignored:4318/v1/traces", cfg.Protocol.OTLPHTTP.TracesEndpoint, "the template is left untouched")
+
+	// test
+	cfg.Protocol.OTLPHTTP.ClientConfig.TLSSetting.Insecure = true
+	exporterCfg = buildHTTPExporterConfig(cfg, "the-endpoint:4318")
+
+	// verify
+	assert.Equal(t, "http://the-endpoint:4318", exporterCfg.ClientConfig.Endpoint)
+}
+
+func TestBuildExporterFactoryAndConfig(t *testing.T) {
+	for _, tt := range []struct {
+		desc     string
+		protocol func(*Protocol)
+		factory  component.Type
+		endpoint func(component.Config) string
+	}{
+		{
+			desc:     "otlp",
+			protocol: func(*Protocol) {},
+			factory:  otlpexporter.NewFactory().Type(),
+			endpoint: func(cfg component.Config) string {
+				return cfg.(*otlpexporter.Config).Endpoint
+			},
+		},
+		{
+			desc: "otlphttp",
+			protocol: func(p *Protocol) {
+				p.OTLPHTTP = otlphttpexporter.NewFactory().CreateDefaultConfig().(*otlphttpexporter.Config)
+			},
+			factory: otlphttpexporter.NewFactory().Type(),
+			endpoint: func(cfg component.Config) string {
+				return cfg.(*otlphttpexporter.Config).ClientConfig.Endpoint
+			},
+		},
+		{
+			desc: "otelarrow",
+			protocol: func(p *Protocol) {
+				p.OTelArrow = otelarrowexporter.NewFactory().CreateDefaultConfig().(*otelarrowexporter.Config)
+			},
+			factory: otelarrowexporter.NewFactory().Type(),
+			endpoint: func(cfg component.Config) string {
+				return cfg.(*otelarrowexporter.Config).Endpoint
+			},
+		},
+	} {
+		t.Run(tt.desc, func(t *testing.T) {
+			// prepare
+			cfg := createDefaultConfig().(*Config)
+			tt.protocol(&cfg.Protocol)
+
+			// test
+			factory, exporterCfg := buildExporterFactoryAndConfig(cfg, "the-endpoint:4317")
+
+			// verify
+			assert.Equal(t, tt.factory, factory.Type())
+			assert.Contains(t, tt.endpoint(exporterCfg), "the-endpoint:4317")
+		})
+	}
+}
+
+func TestBuildProbeClientConfig(t *testing.T) {
+	// prepare
+	cfg := createDefaultConfig().(*Config)
+	cfg.Protocol.OTLPHTTP = otlphttpexporter.NewFactory().CreateDefaultConfig().(*otlphttpexporter.Config)
+	cfg.Protocol.OTLPHTTP.ClientConfig.TLSSetting.Insecure = true
+	cfg.Protocol.OTLPHTTP.ClientConfig.Headers = map[string]configopaque.String{"x-tenant": "a"}
+
+	// test
+	clientCfg := buildProbeClientConfig(cfg)
+
+	// verify
+	assert.True(t, clientCfg.TLSSetting.Insecure)
+	assert.Equal(t, map[string]configopaque.String{"x-tenant": "a"}, clientCfg.Headers)
+}
+
 func TestBuildExporterSettings(t *testing.T) {
 	// prepare
 	creationParams := exportertest.NewNopSettings()
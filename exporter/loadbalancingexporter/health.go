@@ -0,0 +1,301 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package loadbalancingexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/loadbalancingexporter"
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/loadbalancingexporter/internal/metadata"
+)
+
+const (
+	defaultBaseEjectionTime   = 30 * time.Second
+	defaultMaxEjectionTime    = 5 * time.Minute
+	defaultMaxEjectionPercent = 50
+)
+
+var (
+	errInvalidConsecutiveFailures = errors.New("the number of consecutive failures can't be negative")
+	errInvalidEjectionTime        = errors.New("the base ejection time has to be positive, and at most the max ejection time")
+	errInvalidMaxEjectionPercent  = errors.New("the max ejection percent has to be between 0 and 100")
+	errProbeWithoutHealthCheck    = errors.New("the grpc probe requires the number of consecutive failures to be set")
+)
+
+// healthProber checks the health of the endpoints actively.
+type healthProber interface {
+	start(host component.Host)
+	// probe returns an error when the endpoint isn't healthy
+	probe(ctx context.Context, endpoint string) error
+	// forget releases the resources held for the endpoint, once it isn't resolved anymore
+	forget(endpoint string)
+	shutdown()
+}
+
+// healthTracker ejects the endpoints failing cfg.ConsecutiveFailures exports or probes in a row, and reinstates them
+// after an ejection time doubling with each consecutive ejection. The endpoints are tracked with their port.
+type healthTracker struct {
+	cfg       HealthCheck
+	logger    *zap.Logger
+	telemetry *metadata.TelemetryBuilder
+	prober    healthProber
+	// onChange is called when an endpoint is ejected or reinstated, without holding the lock of the tracker
+	onChange func()
+	now      func() time.Time
+
+	mu        sync.Mutex
+	endpoints map[string]*endpointHealth
+	stopped   bool
+	done      chan struct{}
+	probing   sync.WaitGroup
+}
+
+type endpointHealth struct {
+	failures  int
+	ejections int
+	ejected   bool
+	// reinstatedAt is used to forgive the past ejections of the endpoints staying healthy
+	reinstatedAt time.Time
+	timer        *time.Timer
+}
+
+func validateHealthCheck(cfg HealthCheck) error {
+	if cfg.ConsecutiveFailures < 0 {
+		return errInvalidConsecutiveFailures
+	}
+	if cfg.ConsecutiveFailures == 0 {
+		if cfg.GRPCProbe != nil {
+			return errProbeWithoutHealthCheck
+		}
+		return nil
+	}
+	if cfg.BaseEjectionTime <= 0 || cfg.MaxEjectionTime < cfg.BaseEjectionTime {
+		return errInvalidEjectionTime
+	}
+	if cfg.MaxEjectionPercent < 0 || cfg.MaxEjectionPercent > 100 {
+		return errInvalidMaxEjectionPercent
+	}
+	return nil
+}
+
+func newHealthTracker(cfg HealthCheck, logger *zap.Logger, telemetry *metadata.TelemetryBuilder, prober healthProber, onChange func()) *healthTracker {
+	return &healthTracker{
+		cfg:       cfg,
+		logger:    logger,
+		telemetry: telemetry,
+		prober:    prober,
+		onChange:  onChange,
+		now:       time.Now,
+		endpoints: map[string]*endpointHealth{},
+		done:      make(chan struct{}),
+	}
+}
+
+// start starts probing the endpoints, when a prober is set.
+func (h *healthTracker) start(host component.Host) {
+	if h.prober == nil {
+		return
+	}
+	h.prober.start(host)
+	interval := defaultProbeInterval
+	if h.cfg.GRPCProbe != nil && h.cfg.GRPCProbe.Interval > 0 {
+		interval = h.cfg.GRPCProbe.Interval
+	}
+	h.probing.Add(1)
+	go func() {
+		defer h.probing.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-h.done:
+				return
+			case <-ticker.C:
+				h.probeAll()
+			}
+		}
+	}()
+}
+
+// probeAll probes the endpoints in the ring, the ejected endpoints are probed when their ejection time elapses.
+func (h *healthTracker) probeAll() {
+	h.mu.Lock()
+	var endpoints []string
+	for endpoint, eh := range h.endpoints {
+		if !eh.ejected {
+			endpoints = append(endpoints, endpoint)
+		}
+	}
+	h.mu.Unlock()
+
+	var wg sync.WaitGroup
+	for _, endpoint := range endpoints {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := h.prober.probe(context.Background(), endpoint)
+			if err != nil {
+				h.logger.Debug("failed to probe the endpoint", zap.String("endpoint", endpoint), zap.Error(err))
+			}
+			h.record(endpoint, err == nil)
+		}()
+	}
+	wg.Wait()
+}
+
+// retain starts tracking the new endpoints, and forgets the endpoints not resolved anymore.
+func (h *healthTracker) retain(resolved []string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	current := make(map[string]bool, len(resolved))
+	for _, endpoint := range resolved {
+		endpoint = endpointWithPort(endpoint)
+		current[endpoint] = true
+		if _, ok := h.endpoints[endpoint]; !ok {
+			h.endpoints[endpoint] = &endpointHealth{}
+		}
+	}
+	for endpoint, eh := range h.endpoints {
+		if current[endpoint] {
+			continue
+		}
+		if eh.timer != nil {
+			eh.timer.Stop()
+		}
+		delete(h.endpoints, endpoint)
+		if h.prober != nil {
+			h.prober.forget(endpoint)
+		}
+	}
+}
+
+// isEjected returns whether the endpoint is currently ejected from the ring.
+func (h *healthTracker) isEjected(endpoint string) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	eh, ok := h.endpoints[endpointWithPort(endpoint)]
+	return ok && eh.ejected
+}
+
+// record records the outcome of an export or a probe of the endpoint, and ejects it when it failed too many times
+// in a row.
+func (h *healthTracker) record(endpoint string, healthy bool) {
+	h.mu.Lock()
+	eh, ok := h.endpoints[endpoint]
+	if !ok || eh.ejected || h.stopped {
+		h.mu.Unlock()
+		return
+	}
+	if healthy {
+		eh.failures = 0
+		h.mu.Unlock()
+		return
+	}
+	eh.failures++
+	if eh.failures < h.cfg.ConsecutiveFailures || !h.canEject() {
+		h.mu.Unlock()
+		return
+	}
+	// the past ejections are forgiven progressively, one per base ejection time spent in the ring
+	if eh.ejections > 0 {
+		eh.ejections = max(eh.ejections-int(h.now().Sub(eh.reinstatedAt)/h.cfg.BaseEjectionTime), 0)
+	}
+	h.eject(endpoint, eh)
+	h.mu.Unlock()
+
+	h.onChange()
+}
+
+// canEject returns whether one more endpoint can be ejected, it must be called with the lock held.
+func (h *healthTracker) canEject() bool {
+	ejected := 0
+	for _, eh := range h.endpoints {
+		if eh.ejected {
+			ejected++
+		}
+	}
+	if ejected+1 >= len(h.endpoints) {
+		return false
+	}
+	return ejected == 0 || (ejected+1)*100 <= h.cfg.MaxEjectionPercent*len(h.endpoints)
+}
+
+// eject ejects the endpoint and schedules its reinstatement, it must be called with the lock held.
+func (h *healthTracker) eject(endpoint string, eh *endpointHealth) {
+	eh.failures = 0
+	eh.ejections++
+	eh.ejected = true
+	ejectionTime := h.ejectionTime(eh.ejections)
+	eh.timer = time.AfterFunc(ejectionTime, func() {
+		h.reinstate(endpoint, eh)
+	})
+
+	h.logger.Warn("ejecting the unhealthy endpoint from the ring", zap.String("endpoint", endpoint), zap.Duration("ejection_time", ejectionTime))
+	h.telemetry.LoadbalancerBackendEjections.Add(context.Background(), 1, metric.WithAttributes(attribute.String("endpoint", endpoint)))
+}
+
+// ejectionTime returns the ejection time after the given number of consecutive ejections.
+func (h *healthTracker) ejectionTime(ejections int) time.Duration {
+	ejectionTime := h.cfg.BaseEjectionTime
+	for i := 1; i < ejections && ejectionTime < h.cfg.MaxEjectionTime; i++ {
+		ejectionTime *= 2
+	}
+	return min(ejectionTime, h.cfg.MaxEjectionTime)
+}
+
+// reinstate reinstates the endpoint once its ejection time elapsed. When a prober is set, the endpoint is ejected
+// again if it isn't healthy yet.
+func (h *healthTracker) reinstate(endpoint string, eh *endpointHealth) {
+	var probeErr error
+	if h.prober != nil {
+		probeErr = h.prober.probe(context.Background(), endpoint)
+	}
+
+	h.mu.Lock()
+	if h.stopped || h.endpoints[endpoint] != eh || !eh.ejected {
+		h.mu.Unlock()
+		return
+	}
+	if probeErr != nil {
+		h.logger.Debug("the ejected endpoint is still unhealthy", zap.String("endpoint", endpoint), zap.Error(probeErr))
+		h.eject(endpoint, eh)
+		h.mu.Unlock()
+		return
+	}
+	eh.ejected = false
+	eh.timer = nil
+	eh.reinstatedAt = h.now()
+	h.mu.Unlock()
+
+	h.logger.Info("reinstating the endpoint in the ring", zap.String("endpoint", endpoint))
+	h.onChange()
+}
+
+func (h *healthTracker) shutdown() {
+	h.mu.Lock()
+	if h.stopped {
+		h.mu.Unlock()
+		return
+	}
+	h.stopped = true
+	for _, eh := range h.endpoints {
+		if eh.timer != nil {
+			eh.timer.Stop()
+		}
+	}
+	h.mu.Unlock()
+
+	close(h.done)
+	h.probing.Wait()
+	if h.prober != nil {
+		h.prober.shutdown()
+	}
+}
@@ -0,0 +1,124 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package loadbalancingexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/loadbalancingexporter"
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configgrpc"
+	"google.golang.org/grpc"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	grpcmetadata "google.golang.org/grpc/metadata"
+)
+
+const (
+	defaultProbeInterval = 10 * time.Second
+	defaultProbeTimeout  = time.Second
+)
+
+var _ healthProber = (*grpcProber)(nil)
+
+// grpcProber probes the endpoints with the gRPC health checking protocol, reusing the client settings of the OTLP
+// exporters, like TLS, headers and authentication.
+type grpcProber struct {
+	cfg       GRPCProbe
+	clientCfg configgrpc.ClientConfig
+	settings  component.TelemetrySettings
+	host      component.Host
+
+	mu    sync.Mutex
+	conns map[string]*grpc.ClientConn
+}
+
+func newGRPCProber(cfg GRPCProbe, clientCfg configgrpc.ClientConfig, settings component.TelemetrySettings) *grpcProber {
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = defaultProbeTimeout
+	}
+	return &grpcProber{
+		cfg:       cfg,
+		clientCfg: clientCfg,
+		settings:  settings,
+		conns:     map[string]*grpc.ClientConn{},
+	}
+}
+
+func (p *grpcProber) start(host component.Host) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.host = host
+}
+
+func (p *grpcProber) probe(ctx context.Context, endpoint string) error {
+	conn, err := p.conn(ctx, endpoint)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, p.cfg.Timeout)
+	defer cancel()
+	if len(p.clientCfg.Headers) > 0 {
+		md := grpcmetadata.MD{}
+		for k, v := range p.clientCfg.Headers {
+			md.Set(k, string(v))
+		}
+		ctx = grpcmetadata.NewOutgoingContext(ctx, md)
+	}
+	resp, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{Service: p.cfg.Service})
+	if err != nil {
+		return err
+	}
+	if resp.GetStatus() != healthpb.HealthCheckResponse_SERVING {
+		return fmt.Errorf("the endpoint %q is %s", endpoint, resp.GetStatus())
+	}
+	return nil
+}
+
+// conn returns the connection to the health service of the endpoint, creating it if needed.
+func (p *grpcProber) conn(ctx context.Context, endpoint string) (*grpc.ClientConn, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if conn, ok := p.conns[endpoint]; ok {
+		return conn, nil
+	}
+
+	clientCfg := p.clientCfg
+	clientCfg.Endpoint = endpoint
+	if p.cfg.Port != 0 {
+		host, _, err := net.SplitHostPort(endpoint)
+		if err != nil {
+			return nil, err
+		}
+		clientCfg.Endpoint = net.JoinHostPort(host, strconv.Itoa(int(p.cfg.Port)))
+	}
+	conn, err := clientCfg.ToClientConn(ctx, p.host, p.settings)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't create the connection to probe the endpoint %q: %w", endpoint, err)
+	}
+	p.conns[endpoint] = conn
+	return conn, nil
+}
+
+func (p *grpcProber) forget(endpoint string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if conn, ok := p.conns[endpoint]; ok {
+		_ = conn.Close()
+		delete(p.conns, endpoint)
+	}
+}
+
+func (p *grpcProber) shutdown() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for endpoint, conn := range p.conns {
+		_ = conn.Close()
+		delete(p.conns, endpoint)
+	}
+}
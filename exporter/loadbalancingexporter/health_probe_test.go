@@ -0,0 +1,98 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package loadbalancingexporter
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/configgrpc"
+	"go.opentelemetry.io/collector/config/configtls"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func startHealthServer(t *testing.T) (*health.Server, string) {
+	ln, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+	srv := grpc.NewServer()
+	hs := health.NewServer()
+	healthpb.RegisterHealthServer(srv, hs)
+	go func() {
+		_ = srv.Serve(ln)
+	}()
+	t.Cleanup(srv.Stop)
+	return hs, ln.Addr().String()
+}
+
+func newTestGRPCProber(t *testing.T, cfg GRPCProbe) *grpcProber {
+	clientCfg := configgrpc.ClientConfig{TLSSetting: configtls.ClientConfig{Insecure: true}}
+	p := newGRPCProber(cfg, clientCfg, componenttest.NewNopTelemetrySettings())
+	p.start(componenttest.NewNopHost())
+	t.Cleanup(p.shutdown)
+	return p
+}
+
+func TestGRPCProber(t *testing.T) {
+	// prepare
+	hs, endpoint := startHealthServer(t)
+	p := newTestGRPCProber(t, GRPCProbe{Timeout: time.Second})
+
+	// test and verify
+	hs.SetServingStatus("", healthpb.HealthCheckResponse_SERVING)
+	assert.NoError(t, p.probe(context.Background(), endpoint))
+
+	hs.SetServingStatus("", healthpb.HealthCheckResponse_NOT_SERVING)
+	assert.ErrorContains(t, p.probe(context.Background(), endpoint), "NOT_SERVING")
+}
+
+func TestGRPCProberService(t *testing.T) {
+	// prepare
+	hs, endpoint := startHealthServer(t)
+	hs.SetServingStatus("opentelemetry.proto.collector.trace.v1.TraceService", healthpb.HealthCheckResponse_SERVING)
+	p := newTestGRPCProber(t, GRPCProbe{Service: "opentelemetry.proto.collector.logs.v1.LogsService"})
+
+	// test
+	err := p.probe(context.Background(), endpoint)
+
+	// verify
+	assert.Error(t, err, "the service is unknown to the health server")
+}
+
+func TestGRPCProberPort(t *testing.T) {
+	// prepare
+	_, healthEndpoint := startHealthServer(t)
+	_, port, err := net.SplitHostPort(healthEndpoint)
+	require.NoError(t, err)
+	portNumber, err := strconv.ParseUint(port, 10, 16)
+	require.NoError(t, err)
+	p := newTestGRPCProber(t, GRPCProbe{Port: uint16(portNumber)})
+
+	// test
+	err = p.probe(context.Background(), "localhost:4317")
+
+	// verify
+	assert.NoError(t, err)
+}
+
+func TestGRPCProberForget(t *testing.T) {
+	// prepare
+	_, endpoint := startHealthServer(t)
+	p := newTestGRPCProber(t, GRPCProbe{})
+	require.NoError(t, p.probe(context.Background(), endpoint))
+	require.Len(t, p.conns, 1)
+
+	// test
+	p.forget(endpoint)
+
+	// verify
+	assert.Empty(t, p.conns)
+}
@@ -0,0 +1,273 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package loadbalancingexporter
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.uber.org/zap"
+)
+
+type fakeProber struct {
+	mu        sync.Mutex
+	err       error
+	probed    map[string]int
+	forgotten []string
+}
+
+func (p *fakeProber) start(component.Host) {}
+
+func (p *fakeProber) probe(_ context.Context, endpoint string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.probed == nil {
+		p.probed = map[string]int{}
+	}
+	p.probed[endpoint]++
+	return p.err
+}
+
+func (p *fakeProber) forget(endpoint string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.forgotten = append(p.forgotten, endpoint)
+}
+
+func (p *fakeProber) shutdown() {}
+
+func newTestHealthTracker(t *testing.T, cfg HealthCheck, prober healthProber) (*healthTracker, *atomic.Int32) {
+	_, tb := getTelemetryAssets(t)
+	changes := &atomic.Int32{}
+	h := newHealthTracker(cfg, zap.NewNop(), tb, prober, func() { changes.Add(1) })
+	t.Cleanup(h.shutdown)
+	return h, changes
+}
+
+func TestValidateHealthCheck(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		cfg  HealthCheck
+		err  error
+	}{
+		{
+			name: "disabled",
+			cfg:  HealthCheck{},
+		},
+		{
+			name: "valid",
+			cfg:  HealthCheck{ConsecutiveFailures: 5, BaseEjectionTime: time.Second, MaxEjectionTime: time.Minute, MaxEjectionPercent: 50, GRPCProbe: &GRPCProbe{}},
+		},
+		{
+			name: "negative consecutive failures",
+			cfg:  HealthCheck{ConsecutiveFailures: -1},
+			err:  errInvalidConsecutiveFailures,
+		},
+		{
+			name: "probe without consecutive failures",
+			cfg:  HealthCheck{GRPCProbe: &GRPCProbe{}},
+			err:  errProbeWithoutHealthCheck,
+		},
+		{
+			name: "no base ejection time",
+			cfg:  HealthCheck{ConsecutiveFailures: 5, MaxEjectionTime: time.Minute},
+			err:  errInvalidEjectionTime,
+		},
+		{
+			name: "max ejection time lower than the base one",
+			cfg:  HealthCheck{ConsecutiveFailures: 5, BaseEjectionTime: time.Minute, MaxEjectionTime: time.Second},
+			err:  errInvalidEjectionTime,
+		},
+		{
+			name: "max ejection percent over 100",
+			cfg:  HealthCheck{ConsecutiveFailures: 5, BaseEjectionTime: time.Second, MaxEjectionTime: time.Minute, MaxEjectionPercent: 101},
+			err:  errInvalidMaxEjectionPercent,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.err, validateHealthCheck(tt.cfg))
+		})
+	}
+}
+
+func TestHealthTrackerEjectsAfterConsecutiveFailures(t *testing.T) {
+	// prepare
+	h, changes := newTestHealthTracker(t, HealthCheck{ConsecutiveFailures: 3, BaseEjectionTime: time.Hour, MaxEjectionTime: time.Hour, MaxEjectionPercent: 50}, nil)
+	h.retain([]string{"endpoint-1", "endpoint-2"})
+
+	// test
+	h.record("endpoint-1:4317", false)
+	h.record("endpoint-1:4317", false)
+	// a success resets the count of consecutive failures
+	h.record("endpoint-1:4317", true)
+	h.record("endpoint-1:4317", false)
+	h.record("endpoint-1:4317", false)
+	require.False(t, h.isEjected("endpoint-1"))
+	h.record("endpoint-1:4317", false)
+
+	// verify
+	assert.True(t, h.isEjected("endpoint-1"))
+	assert.True(t, h.isEjected("endpoint-1:4317"))
+	assert.False(t, h.isEjected("endpoint-2"))
+	assert.Equal(t, int32(1), changes.Load())
+}
+
+func TestHealthTrackerMaxEjectionPercent(t *testing.T) {
+	// prepare
+	h, _ := newTestHealthTracker(t, HealthCheck{ConsecutiveFailures: 1, BaseEjectionTime: time.Hour, MaxEjectionTime: time.Hour, MaxEjectionPercent: 50}, nil)
+	h.retain([]string{"endpoint-1", "endpoint-2", "endpoint-3", "endpoint-4"})
+
+	// test
+	for _, endpoint := range []string{"endpoint-1", "endpoint-2", "endpoint-3", "endpoint-4"} {
+		h.record(endpointWithPort(endpoint), false)
+	}
+
+	// verify
+	assert.True(t, h.isEjected("endpoint-1"))
+	assert.True(t, h.isEjected("endpoint-2"))
+	assert.False(t, h.isEjected("endpoint-3"))
+	assert.False(t, h.isEjected("endpoint-4"))
+}
+
+func TestHealthTrackerKeepsLastEndpoint(t *testing.T) {
+	// prepare
+	h, _ := newTestHealthTracker(t, HealthCheck{ConsecutiveFailures: 1, BaseEjectionTime: time.Hour, MaxEjectionTime: time.Hour, MaxEjectionPercent: 100}, nil)
+	h.retain([]string{"endpoint-1", "endpoint-2"})
+
+	// test
+	h.record("endpoint-1:4317", false)
+	h.record("endpoint-2:4317", false)
+
+	// verify
+	assert.True(t, h.isEjected("endpoint-1"))
+	assert.False(t, h.isEjected("endpoint-2"))
+}
+
+func TestHealthTrackerEjectionTime(t *testing.T) {
+	h, _ := newTestHealthTracker(t, HealthCheck{BaseEjectionTime: time.Second, MaxEjectionTime: 5 * time.Second}, nil)
+	assert.Equal(t, time.Second, h.ejectionTime(1))
+	assert.Equal(t, 2*time.Second, h.ejectionTime(2))
+	assert.Equal(t, 4*time.Second, h.ejectionTime(3))
+	assert.Equal(t, 5*time.Second, h.ejectionTime(4))
+	assert.Equal(t, 5*time.Second, h.ejectionTime(100))
+}
+
+func TestHealthTrackerReinstates(t *testing.T) {
+	// prepare
+	h, changes := newTestHealthTracker(t, HealthCheck{ConsecutiveFailures: 1, BaseEjectionTime: 10 * time.Millisecond, MaxEjectionTime: time.Second, MaxEjectionPercent: 50}, nil)
+	h.retain([]string{"endpoint-1", "endpoint-2"})
+
+	// test
+	h.record("endpoint-1:4317", false)
+	require.True(t, h.isEjected("endpoint-1"))
+
+	// verify
+	assert.Eventually(t, func() bool {
+		return !h.isEjected("endpoint-1")
+	}, time.Second, 5*time.Millisecond)
+	assert.Equal(t, int32(2), changes.Load())
+}
+
+func TestHealthTrackerBackoff(t *testing.T) {
+	// prepare
+	h, _ := newTestHealthTracker(t, HealthCheck{ConsecutiveFailures: 1, BaseEjectionTime: time.Hour, MaxEjectionTime: 10 * time.Hour, MaxEjectionPercent: 50}, nil)
+	now := time.Now()
+	h.now = func() time.Time { return now }
+	h.retain([]string{"endpoint-1", "endpoint-2"})
+	eh := h.endpoints["endpoint-1:4317"]
+
+	// test and verify
+	h.record("endpoint-1:4317", false)
+	h.reinstate("endpoint-1:4317", eh)
+	h.record("endpoint-1:4317", false)
+	// ejected again right after being reinstated
+	assert.Equal(t, 2, eh.ejections)
+
+	h.reinstate("endpoint-1:4317", eh)
+	now = now.Add(90 * time.Minute)
+	h.record("endpoint-1:4317", false)
+	// one ejection is forgiven per base ejection time spent in the ring
+	assert.Equal(t, 2, eh.ejections)
+
+	h.reinstate("endpoint-1:4317", eh)
+	now = now.Add(10 * time.Hour)
+	h.record("endpoint-1:4317", false)
+	assert.Equal(t, 1, eh.ejections)
+}
+
+func TestHealthTrackerReinstateProbesFirst(t *testing.T) {
+	// prepare
+	prober := &fakeProber{err: errors.New("not serving")}
+	h, changes := newTestHealthTracker(t, HealthCheck{ConsecutiveFailures: 1, BaseEjectionTime: time.Hour, MaxEjectionTime: 10 * time.Hour, MaxEjectionPercent: 50}, prober)
+	h.retain([]string{"endpoint-1", "endpoint-2"})
+	eh := h.endpoints["endpoint-1:4317"]
+	h.record("endpoint-1:4317", false)
+
+	// test
+	h.reinstate("endpoint-1:4317", eh)
+
+	// verify
+	assert.True(t, h.isEjected("endpoint-1"))
+	assert.Equal(t, 2, eh.ejections)
+	assert.Equal(t, int32(1), changes.Load())
+
+	// test
+	prober.err = nil
+	h.reinstate("endpoint-1:4317", eh)
+
+	// verify
+	assert.False(t, h.isEjected("endpoint-1"))
+	assert.Equal(t, int32(2), changes.Load())
+}
+
+func TestHealthTrackerProbes(t *testing.T) {
+	// prepare
+	prober := &fakeProber{err: errors.New("not serving")}
+	h, _ := newTestHealthTracker(t, HealthCheck{ConsecutiveFailures: 2, BaseEjectionTime: time.Hour, MaxEjectionTime: time.Hour, MaxEjectionPercent: 50, GRPCProbe: &GRPCProbe{Interval: 5 * time.Millisecond}}, prober)
+	h.retain([]string{"endpoint-1", "endpoint-2"})
+
+	// test
+	h.start(nil)
+
+	// verify
+	assert.Eventually(t, func() bool {
+		return h.isEjected("endpoint-1") != h.isEjected("endpoint-2")
+	}, time.Second, 5*time.Millisecond)
+}
+
+func TestHealthTrackerRetain(t *testing.T) {
+	// prepare
+	prober := &fakeProber{}
+	h, _ := newTestHealthTracker(t, HealthCheck{ConsecutiveFailures: 1, BaseEjectionTime: time.Hour, MaxEjectionTime: time.Hour, MaxEjectionPercent: 50}, prober)
+	h.retain([]string{"endpoint-1", "endpoint-2", "endpoint-3"})
+	h.record("endpoint-1:4317", false)
+
+	// test
+	h.retain([]string{"endpoint-2", "endpoint-3"})
+	h.retain([]string{"endpoint-1", "endpoint-2", "endpoint-3"})
+
+	// verify
+	assert.False(t, h.isEjected("endpoint-1"), "the health of the endpoints not resolved anymore is forgotten")
+	assert.Equal(t, []string{"endpoint-1:4317"}, prober.forgotten)
+}
+
+func TestHealthTrackerIgnoresUnknownEndpoints(t *testing.T) {
+	// prepare
+	h, changes := newTestHealthTracker(t, HealthCheck{ConsecutiveFailures: 1, BaseEjectionTime: time.Hour, MaxEjectionTime: time.Hour, MaxEjectionPercent: 50}, nil)
+	h.retain([]string{"endpoint-1", "endpoint-2"})
+
+	// test
+	h.record("endpoint-3:4317", false)
+
+	// verify
+	assert.False(t, h.isEjected("endpoint-3"))
+	assert.Equal(t, int32(0), changes.Load())
+}
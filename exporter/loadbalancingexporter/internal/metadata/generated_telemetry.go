@@ -24,12 +24,16 @@ func Tracer(settings component.TelemetrySettings) trace.Tracer {
 // TelemetryBuilder provides an interface for components to report telemetry
 // as defined in metadata and user config.
 type TelemetryBuilder struct {
-	meter                         metric.Meter
-	LoadbalancerBackendLatency    metric.Int64Histogram
-	LoadbalancerBackendOutcome    metric.Int64Counter
-	LoadbalancerNumBackendUpdates metric.Int64Counter
-	LoadbalancerNumBackends       metric.Int64Gauge
-	LoadbalancerNumResolutions    metric.Int64Counter
+	meter                            metric.Meter
+	LoadbalancerBackendEjections     metric.Int64Counter
+	LoadbalancerBackendLatency       metric.Int64Histogram
+	LoadbalancerBackendOutcome       metric.Int64Counter
+	LoadbalancerBackendRingShare     metric.Float64Gauge
+	LoadbalancerBackendRoutingKeys   metric.Int64Counter
+	LoadbalancerBoundedLoadOverflows metric.Int64Counter
+	LoadbalancerNumBackendUpdates    metric.Int64Counter
+	LoadbalancerNumBackends          metric.Int64Gauge
+	LoadbalancerNumResolutions       metric.Int64Counter
 }
 
 // TelemetryBuilderOption applies changes to default builder.
@@ -52,6 +56,12 @@ func NewTelemetryBuilder(settings component.TelemetrySettings, options ...Teleme
 	}
 	builder.meter = Meter(settings)
 	var err, errs error
+	builder.LoadbalancerBackendEjections, err = getLeveledMeter(builder.meter, configtelemetry.LevelBasic, settings.MetricsLevel).Int64Counter(
+		"otelcol_loadbalancer_backend_ejections",
+		metric.WithDescription("Number of times each endpoint was ejected from the ring after failing its health checks."),
+		metric.WithUnit("{ejections}"),
+	)
+	errs = errors.Join(errs, err)
 	builder.LoadbalancerBackendLatency, err = getLeveledMeter(builder.meter, configtelemetry.LevelBasic, settings.MetricsLevel).Int64Histogram(
 		"otelcol_loadbalancer_backend_latency",
 		metric.WithDescription("Response latency in ms for the backends."),
@@ -65,6 +75,24 @@ func NewTelemetryBuilder(settings component.TelemetrySettings, options ...Teleme
 		metric.WithUnit("{outcomes}"),
 	)
 	errs = errors.Join(errs, err)
+	builder.LoadbalancerBackendRingShare, err = getLeveledMeter(builder.meter, configtelemetry.LevelBasic, settings.MetricsLevel).Float64Gauge(
+		"otelcol_loadbalancer_backend_ring_share",
+		metric.WithDescription("Fraction of the hash ring owned by each endpoint, according to its weight."),
+		metric.WithUnit("1"),
+	)
+	errs = errors.Join(errs, err)
+	builder.LoadbalancerBackendRoutingKeys, err = getLeveledMeter(builder.meter, configtelemetry.LevelBasic, settings.MetricsLevel).Int64Counter(
+		"otelcol_loadbalancer_backend_routing_keys",
+		metric.WithDescription("Number of routing keys assigned to each endpoint."),
+		metric.WithUnit("{keys}"),
+	)
+	errs = errors.Join(errs, err)
+	builder.LoadbalancerBoundedLoadOverflows, err = getLeveledMeter(builder.meter, configtelemetry.LevelBasic, settings.MetricsLevel).Int64Counter(
+		"otelcol_loadbalancer_bounded_load_overflows",
+		metric.WithDescription("Number of routing keys moved away from each endpoint because it was at its load cap."),
+		metric.WithUnit("{keys}"),
+	)
+	errs = errors.Join(errs, err)
 	builder.LoadbalancerNumBackendUpdates, err = getLeveledMeter(builder.meter, configtelemetry.LevelBasic, settings.MetricsLevel).Int64Counter(
 		"otelcol_loadbalancer_num_backend_updates",
 		metric.WithDescription("Number of times the list of backends was updated."),
@@ -20,13 +20,29 @@ func TestSetupTelemetry(t *testing.T) {
 	)
 	require.NoError(t, err)
 	require.NotNil(t, tb)
+	tb.LoadbalancerBackendEjections.Add(context.Background(), 1)
 	tb.LoadbalancerBackendLatency.Record(context.Background(), 1)
 	tb.LoadbalancerBackendOutcome.Add(context.Background(), 1)
+	tb.LoadbalancerBackendRingShare.Record(context.Background(), 1)
+	tb.LoadbalancerBackendRoutingKeys.Add(context.Background(), 1)
+	tb.LoadbalancerBoundedLoadOverflows.Add(context.Background(), 1)
 	tb.LoadbalancerNumBackendUpdates.Add(context.Background(), 1)
 	tb.LoadbalancerNumBackends.Record(context.Background(), 1)
 	tb.LoadbalancerNumResolutions.Add(context.Background(), 1)
 
 	testTel.AssertMetrics(t, []metricdata.Metrics{
+		{
+			Name:        "otelcol_loadbalancer_backend_ejections",
+			Description: "Number of times each endpoint was ejected from the ring after failing its health checks.",
+			Unit:        "{ejections}",
+			Data: metricdata.Sum[int64]{
+				Temporality: metricdata.CumulativeTemporality,
+				IsMonotonic: true,
+				DataPoints: []metricdata.DataPoint[int64]{
+					{},
+				},
+			},
+		},
 		{
 			Name:        "otelcol_loadbalancer_backend_latency",
 			Description: "Response latency in ms for the backends.",
@@ -50,6 +66,40 @@ func TestSetupTelemetry(t *testing.T) {
 				},
 			},
 		},
+		{
+			Name:        "otelcol_loadbalancer_backend_ring_share",
+			Description: "Fraction of the hash ring owned by each endpoint, according to its weight.",
+			Unit:        "1",
+			Data: metricdata.Gauge[float64]{
+				DataPoints: []metricdata.DataPoint[float64]{
+					{},
+				},
+			},
+		},
+		{
+			Name:        "otelcol_loadbalancer_backend_routing_keys",
+			Description: "Number of routing keys assigned to each endpoint.",
+			Unit:        "{keys}",
+			Data: metricdata.Sum[int64]{
+				Temporality: metricdata.CumulativeTemporality,
+				IsMonotonic: true,
+				DataPoints: []metricdata.DataPoint[int64]{
+					{},
+				},
+			},
+		},
+		{
+			Name:        "otelcol_loadbalancer_bounded_load_overflows",
+			Description: "Number of routing keys moved away from each endpoint because it was at its load cap.",
+			Unit:        "{keys}",
+			Data: metricdata.Sum[int64]{
+				Temporality: metricdata.CumulativeTemporality,
+				IsMonotonic: true,
+				DataPoints: []metricdata.DataPoint[int64]{
+					{},
+				},
+			},
+		},
 		{
 			Name:        "otelcol_loadbalancer_num_backend_updates",
 			Description: "Number of times the list of backends was updated.",
@@ -7,10 +7,14 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net"
 	"strings"
 	"sync"
 
 	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
 	"go.uber.org/zap"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/loadbalancingexporter/internal/metadata"
@@ -23,6 +27,9 @@ const (
 var (
 	errNoResolver                = errors.New("no resolvers specified for the exporter")
 	errMultipleResolversProvided = errors.New("only one resolver should be specified")
+	errMultipleProtocolsProvided = errors.New("only one protocol should be specified")
+	errInvalidBalanceFactor      = errors.New("the balance factor has to be at least 1")
+	errInvalidWeight             = errors.New("the weights of the endpoints have to be positive")
 )
 
 type componentFactory func(ctx context.Context, endpoint string) (component.Component, error)
@@ -31,18 +38,28 @@ type loadBalancer struct {
 	logger *zap.Logger
 	host   component.Host
 
-	res  resolver
-	ring *hashRing
+	res resolver
+	// resolved are the endpoints returned by the resolver, the ring excludes the ejected ones
+	resolved []string
+	ring     *hashRing
+	// weights are the weights of the endpoints, from the resolver configuration
+	weights map[string]int
+	// loads caps the loads of the endpoints when the balance factor is set
+	loads *boundedLoads
+	// health ejects the unhealthy endpoints from the ring when the health checking is enabled
+	health *healthTracker
 
 	componentFactory componentFactory
 	exporters        map[string]*wrappedExporter
+	telemetry        *metadata.TelemetryBuilder
 
 	stopped    bool
 	updateLock sync.RWMutex
 }
 
 // Create new load balancer
-func newLoadBalancer(logger *zap.Logger, cfg component.Config, factory componentFactory, telemetry *metadata.TelemetryBuilder) (*loadBalancer, error) {
+func newLoadBalancer(settings component.TelemetrySettings, cfg component.Config, factory componentFactory, telemetry *metadata.TelemetryBuilder) (*loadBalancer, error) {
+	logger := settings.Logger
 	oCfg := cfg.(*Config)
 
 	count := 0
@@ -61,9 +78,17 @@ func newLoadBalancer(logger *zap.Logger, cfg component.Config, factory component
 	if count > 1 {
 		return nil, errMultipleResolversProvided
 	}
+	if oCfg.BalanceFactor != 0 && oCfg.BalanceFactor < 1 {
+		return nil, errInvalidBalanceFactor
+	}
+	if err := validateHealthCheck(oCfg.HealthCheck); err != nil {
+		return nil, err
+	}
 
 	var res resolver
+	var weights map[string]int
 	if oCfg.Resolver.Static != nil {
+		weights = oCfg.Resolver.Static.Weights
 		var err error
 		res, err = newStaticResolver(
 			oCfg.Resolver.Static.Hostnames,
@@ -74,6 +99,7 @@ func newLoadBalancer(logger *zap.Logger, cfg component.Config, factory component
 		}
 	}
 	if oCfg.Resolver.DNS != nil {
+		weights = oCfg.Resolver.DNS.Weights
 		dnsLogger := logger.With(zap.String("resolver", "dns"))
 
 		var err error
@@ -90,6 +116,7 @@ func newLoadBalancer(logger *zap.Logger, cfg component.Config, factory component
 		}
 	}
 	if oCfg.Resolver.K8sSvc != nil {
+		weights = oCfg.Resolver.K8sSvc.Weights
 		k8sLogger := logger.With(zap.String("resolver", "k8s service"))
 
 		clt, err := newInClusterClient()
@@ -131,36 +158,156 @@ func newLoadBalancer(logger *zap.Logger, cfg component.Config, factory component
 	if res == nil {
 		return nil, errNoResolver
 	}
+	for _, weight := range weights {
+		if weight <= 0 {
+			return nil, errInvalidWeight
+		}
+	}
 
-	return &loadBalancer{
+	lb := &loadBalancer{
 		logger:           logger,
 		res:              res,
+		weights:          weights,
 		componentFactory: factory,
 		exporters:        map[string]*wrappedExporter{},
-	}, nil
+		telemetry:        telemetry,
+	}
+	if oCfg.BalanceFactor != 0 {
+		lb.loads = newBoundedLoads(oCfg.BalanceFactor)
+	}
+	if oCfg.HealthCheck.ConsecutiveFailures > 0 {
+		if queueCfg, retryCfg := protocolResilience(oCfg); queueCfg.Enabled || retryCfg.Enabled {
+			logger.Warn("the sending_queue and retry_on_failure settings of the protocol are disabled since the health checking "+
+				"requires the outcome of each export, the settings of the loadbalancing exporter can be used instead",
+				zap.Bool("sending_queue", queueCfg.Enabled), zap.Bool("retry_on_failure", retryCfg.Enabled))
+		}
+		var prober healthProber
+		if oCfg.HealthCheck.GRPCProbe != nil {
+			prober = newGRPCProber(*oCfg.HealthCheck.GRPCProbe, buildProbeClientConfig(oCfg), settings)
+		}
+		lb.health = newHealthTracker(oCfg.HealthCheck, logger.With(zap.String("component", "health_check")), telemetry, prober, lb.onHealthChanges)
+	}
+	return lb, nil
 }
 
 func (lb *loadBalancer) Start(ctx context.Context, host component.Host) error {
 	lb.res.onChange(lb.onBackendChanges)
 	lb.host = host
-	return lb.res.start(ctx)
+	if err := lb.res.start(ctx); err != nil {
+		return err
+	}
+	if lb.health != nil {
+		lb.health.start(host)
+	}
+	return nil
 }
 
 func (lb *loadBalancer) onBackendChanges(resolved []string) {
-	newRing := newHashRing(resolved)
+	lb.updateLock.Lock()
+	defer lb.updateLock.Unlock()
 
-	if !newRing.equal(lb.ring) {
-		lb.updateLock.Lock()
-		defer lb.updateLock.Unlock()
+	lb.resolved = resolved
+	if lb.health != nil {
+		lb.health.retain(resolved)
+	}
 
-		lb.ring = newRing
+	// TODO: set a timeout?
+	ctx := context.Background()
 
-		// TODO: set a timeout?
-		ctx := context.Background()
+	// add the missing exporters first, the ejected endpoints keep their exporters to be reinstated later
+	lb.addMissingExporters(ctx, resolved)
+	lb.removeExtraExporters(ctx, resolved)
+	lb.updateRing(ctx)
+}
 
-		// add the missing exporters first
-		lb.addMissingExporters(ctx, resolved)
-		lb.removeExtraExporters(ctx, resolved)
+// onHealthChanges rebuilds the ring once an endpoint got ejected or reinstated.
+func (lb *loadBalancer) onHealthChanges() {
+	lb.updateLock.Lock()
+	defer lb.updateLock.Unlock()
+	if lb.stopped {
+		return
+	}
+	lb.updateRing(context.Background())
+}
+
+// updateRing rebuilds the ring from the resolved endpoints which aren't ejected, it must be called with the update
+// lock held.
+func (lb *loadBalancer) updateRing(ctx context.Context) {
+	endpoints := lb.healthyEndpoints()
+	newRing := newWeightedHashRing(endpoints, lb.endpointWeights(endpoints))
+	if newRing.equal(lb.ring) {
+		return
+	}
+
+	oldRing := lb.ring
+	lb.ring = newRing
+	if lb.loads != nil {
+		lb.loads.retain(newRing)
+	}
+	lb.recordRingShares(ctx, oldRing, newRing)
+}
+
+// healthyEndpoints returns the resolved endpoints which aren't ejected. All the resolved endpoints are returned when
+// all of them are ejected, as there would be nowhere to send the data otherwise.
+func (lb *loadBalancer) healthyEndpoints() []string {
+	if lb.health == nil {
+		return lb.resolved
+	}
+	healthy := make([]string, 0, len(lb.resolved))
+	for _, endpoint := range lb.resolved {
+		if !lb.health.isEjected(endpoint) {
+			healthy = append(healthy, endpoint)
+		}
+	}
+	if len(healthy) == 0 {
+		return lb.resolved
+	}
+	return healthy
+}
+
+// onExportResult records the outcome of an export for the health checking of the endpoint. The permanent errors
+// are caused by the data, not by the endpoint, and are ignored.
+func (lb *loadBalancer) onExportResult(exp *wrappedExporter, err error) {
+	if lb.health == nil || consumererror.IsPermanent(err) {
+		return
+	}
+	lb.health.record(exp.endpoint, err == nil)
+}
+
+// endpointWeights returns the weights of the resolved endpoints. The weight of an endpoint is looked up by the endpoint
+// as resolved, then with the default port, and then by its host only.
+func (lb *loadBalancer) endpointWeights(resolved []string) map[string]int {
+	if len(lb.weights) == 0 {
+		return nil
+	}
+	weights := make(map[string]int, len(resolved))
+	for _, endpoint := range resolved {
+		keys := []string{endpoint, endpointWithPort(endpoint)}
+		if host, _, err := net.SplitHostPort(endpoint); err == nil {
+			keys = append(keys, host)
+		}
+		for _, key := range keys {
+			if weight, ok := lb.weights[key]; ok {
+				weights[endpoint] = weight
+				break
+			}
+		}
+	}
+	return weights
+}
+
+// recordRingShares records the share of the ring of the endpoints, the endpoints removed from the ring get a share of 0.
+func (lb *loadBalancer) recordRingShares(ctx context.Context, oldRing, newRing *hashRing) {
+	shares := newRing.shares()
+	if oldRing != nil {
+		for endpoint := range oldRing.weights {
+			if _, ok := shares[endpoint]; !ok {
+				shares[endpoint] = 0
+			}
+		}
+	}
+	for endpoint, share := range shares {
+		lb.telemetry.LoadbalancerBackendRingShare.Record(ctx, share, metric.WithAttributes(attribute.String("endpoint", endpointWithPort(endpoint))))
 	}
 }
 
@@ -220,7 +367,12 @@ func endpointFound(endpoint string, endpoints []string) bool {
 
 func (lb *loadBalancer) Shutdown(ctx context.Context) error {
 	err := lb.res.shutdown(ctx)
+	if lb.health != nil {
+		lb.health.shutdown()
+	}
+	lb.updateLock.Lock()
 	lb.stopped = true
+	lb.updateLock.Unlock()
 
 	for _, e := range lb.exporters {
 		err = errors.Join(err, e.Shutdown(ctx))
@@ -228,19 +380,28 @@ func (lb *loadBalancer) Shutdown(ctx context.Context) error {
 	return err
 }
 
-// exporterAndEndpoint returns the exporter and the endpoint for the given identifier.
-func (lb *loadBalancer) exporterAndEndpoint(identifier []byte) (*wrappedExporter, string, error) {
+// exporterAndEndpoint returns the exporter and the endpoint for the given identifier, routing the given number of
+// items (spans, data points or log records).
+func (lb *loadBalancer) exporterAndEndpoint(identifier []byte, items int) (*wrappedExporter, string, error) {
 	// NOTE: make rolling updates of next tier of collectors work. currently, this may cause
 	// data loss because the latest batches sent to outdated backend will never find their way out.
 	// for details: https://github.com/open-telemetry/opentelemetry-collector-contrib/issues/1690
 	lb.updateLock.RLock()
 	defer lb.updateLock.RUnlock()
 	endpoint := lb.ring.endpointFor(identifier)
+	if lb.loads != nil {
+		owner := endpoint
+		var overflow bool
+		if endpoint, overflow = lb.loads.endpointFor(lb.ring, identifier, items); overflow {
+			lb.telemetry.LoadbalancerBoundedLoadOverflows.Add(context.Background(), 1, metric.WithAttributes(attribute.String("endpoint", endpointWithPort(owner))))
+		}
+	}
 	exp, found := lb.exporters[endpointWithPort(endpoint)]
 	if !found {
 		// something is really wrong... how come we couldn't find the exporter??
 		return nil, "", fmt.Errorf("couldn't find the exporter for the endpoint %q", endpoint)
 	}
+	lb.telemetry.LoadbalancerBackendRoutingKeys.Add(context.Background(), 1, metric.WithAttributeSet(exp.endpointAttr))
 
 	return exp, endpoint, nil
 }
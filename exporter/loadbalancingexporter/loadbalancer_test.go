@@ -6,16 +6,25 @@ package loadbalancingexporter
 import (
 	"context"
 	"errors"
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumererror"
 	"go.opentelemetry.io/collector/exporter"
 	"go.opentelemetry.io/collector/exporter/exportertest"
 	"go.opentelemetry.io/collector/exporter/otlpexporter"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
 	"k8s.io/client-go/tools/clientcmd"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/loadbalancingexporter/internal/metadata"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/loadbalancingexporter/internal/metadatatest"
 )
 
 func TestNewLoadBalancerNoResolver(t *testing.T) {
@@ -24,7 +33,7 @@ func TestNewLoadBalancerNoResolver(t *testing.T) {
 	cfg := &Config{}
 
 	// test
-	p, err := newLoadBalancer(ts.Logger, cfg, nil, tb)
+	p, err := newLoadBalancer(ts.TelemetrySettings, cfg, nil, tb)
 
 	// verify
 	require.Nil(t, p)
@@ -41,7 +50,7 @@ func TestNewLoadBalancerInvalidStaticResolver(t *testing.T) {
 	}
 
 	// test
-	p, err := newLoadBalancer(ts.Logger, cfg, nil, tb)
+	p, err := newLoadBalancer(ts.TelemetrySettings, cfg, nil, tb)
 
 	// verify
 	require.Nil(t, p)
@@ -60,7 +69,7 @@ func TestNewLoadBalancerInvalidDNSResolver(t *testing.T) {
 	}
 
 	// test
-	p, err := newLoadBalancer(ts.Logger, cfg, nil, tb)
+	p, err := newLoadBalancer(ts.TelemetrySettings, cfg, nil, tb)
 
 	// verify
 	require.Nil(t, p)
@@ -79,7 +88,7 @@ func TestNewLoadBalancerInvalidK8sResolver(t *testing.T) {
 	}
 
 	// test
-	p, err := newLoadBalancer(ts.Logger, cfg, nil, tb)
+	p, err := newLoadBalancer(ts.TelemetrySettings, cfg, nil, tb)
 
 	// verify
 	assert.Nil(t, p)
@@ -91,7 +100,7 @@ func TestLoadBalancerStart(t *testing.T) {
 	ts, tb := getTelemetryAssets(t)
 	cfg := simpleConfig()
 
-	p, err := newLoadBalancer(ts.Logger, cfg, nil, tb)
+	p, err := newLoadBalancer(ts.TelemetrySettings, cfg, nil, tb)
 	require.NotNil(t, p)
 	require.NoError(t, err)
 	p.res = &mockResolver{}
@@ -115,7 +124,7 @@ func TestWithDNSResolver(t *testing.T) {
 		},
 	}
 
-	p, err := newLoadBalancer(ts.Logger, cfg, nil, tb)
+	p, err := newLoadBalancer(ts.TelemetrySettings, cfg, nil, tb)
 	require.NotNil(t, p)
 	require.NoError(t, err)
 
@@ -138,7 +147,7 @@ func TestWithDNSResolverNoEndpoints(t *testing.T) {
 		},
 	}
 
-	p, err := newLoadBalancer(ts.Logger, cfg, nil, tb)
+	p, err := newLoadBalancer(ts.TelemetrySettings, cfg, nil, tb)
 	require.NotNil(t, p)
 	require.NoError(t, err)
 
@@ -147,7 +156,7 @@ func TestWithDNSResolverNoEndpoints(t *testing.T) {
 	defer func() { assert.NoError(t, p.Shutdown(context.Background())) }()
 
 	// test
-	_, e, _ := p.exporterAndEndpoint([]byte{128, 128, 0, 0})
+	_, e, _ := p.exporterAndEndpoint([]byte{128, 128, 0, 0}, 1)
 
 	// verify
 	assert.Equal(t, "", e)
@@ -167,7 +176,7 @@ func TestMultipleResolvers(t *testing.T) {
 	}
 
 	// test
-	p, err := newLoadBalancer(ts.Logger, cfg, nil, tb)
+	p, err := newLoadBalancer(ts.TelemetrySettings, cfg, nil, tb)
 
 	// verify
 	assert.Nil(t, p)
@@ -179,7 +188,7 @@ func TestStartFailureStaticResolver(t *testing.T) {
 	ts, tb := getTelemetryAssets(t)
 	cfg := simpleConfig()
 
-	p, err := newLoadBalancer(ts.Logger, cfg, nil, tb)
+	p, err := newLoadBalancer(ts.TelemetrySettings, cfg, nil, tb)
 	require.NotNil(t, p)
 	require.NoError(t, err)
 
@@ -219,7 +228,7 @@ func TestOnBackendChanges(t *testing.T) {
 		return newNopMockExporter(), nil
 	}
 
-	p, err := newLoadBalancer(ts.Logger, cfg, componentFactory, tb)
+	p, err := newLoadBalancer(ts.TelemetrySettings, cfg, componentFactory, tb)
 	require.NotNil(t, p)
 	require.NoError(t, err)
 
@@ -243,7 +252,7 @@ func TestRemoveExtraExporters(t *testing.T) {
 		return newNopMockExporter(), nil
 	}
 
-	p, err := newLoadBalancer(ts.Logger, cfg, componentFactory, tb)
+	p, err := newLoadBalancer(ts.TelemetrySettings, cfg, componentFactory, tb)
 	require.NotNil(t, p)
 	require.NoError(t, err)
 
@@ -277,7 +286,7 @@ func TestAddMissingExporters(t *testing.T) {
 		return exporterFactory.CreateTraces(ctx, exportertest.NewNopSettings(), &oCfg)
 	}
 
-	p, err := newLoadBalancer(ts.Logger, cfg, fn, tb)
+	p, err := newLoadBalancer(ts.TelemetrySettings, cfg, fn, tb)
 	require.NotNil(t, p)
 	require.NoError(t, err)
 
@@ -312,7 +321,7 @@ func TestFailedToAddMissingExporters(t *testing.T) {
 		return exporterFactory.CreateTraces(ctx, exportertest.NewNopSettings(), &oCfg)
 	}
 
-	p, err := newLoadBalancer(ts.Logger, cfg, fn, tb)
+	p, err := newLoadBalancer(ts.TelemetrySettings, cfg, fn, tb)
 	require.NotNil(t, p)
 	require.NoError(t, err)
 
@@ -378,7 +387,7 @@ func TestFailedExporterInRing(t *testing.T) {
 	componentFactory := func(_ context.Context, _ string) (component.Component, error) {
 		return newNopMockExporter(), nil
 	}
-	p, err := newLoadBalancer(ts.Logger, cfg, componentFactory, tb)
+	p, err := newLoadBalancer(ts.TelemetrySettings, cfg, componentFactory, tb)
 	require.NotNil(t, p)
 	require.NoError(t, err)
 
@@ -397,14 +406,14 @@ func TestFailedExporterInRing(t *testing.T) {
 
 	// test
 	// this trace ID will reach the endpoint-2 -- see the consistent hashing tests for more info
-	_, _, err = p.exporterAndEndpoint([]byte{128, 128, 0, 0})
+	_, _, err = p.exporterAndEndpoint([]byte{128, 128, 0, 0}, 1)
 
 	// verify
 	assert.Error(t, err)
 
 	// test
 	// this service name will reach the endpoint-2 -- see the consistent hashing tests for more info
-	_, _, err = p.exporterAndEndpoint([]byte("get-recommendations-1"))
+	_, _, err = p.exporterAndEndpoint([]byte("get-recommendations-1"), 1)
 
 	// verify
 	assert.Error(t, err)
@@ -422,7 +431,7 @@ func TestNewLoadBalancerInvalidNamespaceAwsResolver(t *testing.T) {
 	}
 
 	// test
-	p, err := newLoadBalancer(ts.Logger, cfg, nil, tb)
+	p, err := newLoadBalancer(ts.TelemetrySettings, cfg, nil, tb)
 
 	// verify
 	assert.Nil(t, p)
@@ -442,7 +451,7 @@ func TestNewLoadBalancerInvalidServiceAwsResolver(t *testing.T) {
 	}
 
 	// test
-	p, err := newLoadBalancer(ts.Logger, cfg, nil, tb)
+	p, err := newLoadBalancer(ts.TelemetrySettings, cfg, nil, tb)
 
 	// verify
 	assert.Nil(t, p)
@@ -452,3 +461,229 @@ func TestNewLoadBalancerInvalidServiceAwsResolver(t *testing.T) {
 func newNopMockExporter() *wrappedExporter {
 	return newWrappedExporter(mockComponent{}, "mock")
 }
+
+func TestNewLoadBalancerInvalidBalanceFactor(t *testing.T) {
+	// prepare
+	ts, tb := getTelemetryAssets(t)
+	cfg := simpleConfig()
+	cfg.BalanceFactor = 0.5
+
+	// test
+	p, err := newLoadBalancer(ts.TelemetrySettings, cfg, nil, tb)
+
+	// verify
+	require.Nil(t, p)
+	require.Equal(t, errInvalidBalanceFactor, err)
+}
+
+func TestNewLoadBalancerInvalidWeight(t *testing.T) {
+	// prepare
+	ts, tb := getTelemetryAssets(t)
+	cfg := simpleConfig()
+	cfg.Resolver.Static.Weights = map[string]int{"endpoint-1": 0}
+
+	// test
+	p, err := newLoadBalancer(ts.TelemetrySettings, cfg, nil, tb)
+
+	// verify
+	require.Nil(t, p)
+	require.Equal(t, errInvalidWeight, err)
+}
+
+func TestEndpointWeights(t *testing.T) {
+	// prepare
+	ts, tb := getTelemetryAssets(t)
+	cfg := &Config{
+		Resolver: ResolverSettings{
+			DNS: &DNSResolver{
+				Hostname: "service-1",
+				Weights: map[string]int{
+					"10.0.0.1:4317":   2,
+					"10.0.0.2":        3,
+					"endpoint-3:4317": 4,
+				},
+			},
+		},
+	}
+	p, err := newLoadBalancer(ts.TelemetrySettings, cfg, nil, tb)
+	require.NoError(t, err)
+
+	// test
+	weights := p.endpointWeights([]string{"10.0.0.1:4317", "10.0.0.2:4317", "endpoint-3", "10.0.0.4:4317"})
+
+	// verify
+	assert.Equal(t, map[string]int{"10.0.0.1:4317": 2, "10.0.0.2:4317": 3, "endpoint-3": 4}, weights)
+}
+
+func TestBoundedLoadsTelemetry(t *testing.T) {
+	// prepare
+	tt := metadatatest.SetupTelemetry()
+	defer func() {
+		require.NoError(t, tt.Shutdown(context.Background()))
+	}()
+	tb, err := metadata.NewTelemetryBuilder(tt.NewTelemetrySettings())
+	require.NoError(t, err)
+	cfg := &Config{
+		Resolver: ResolverSettings{
+			Static: &StaticResolver{
+				Hostnames: []string{"endpoint-1", "endpoint-2"},
+				Weights:   map[string]int{"endpoint-2": 3},
+			},
+		},
+		BalanceFactor: 1,
+	}
+	componentFactory := func(_ context.Context, _ string) (component.Component, error) {
+		return newNopMockExporter(), nil
+	}
+	p, err := newLoadBalancer(componenttest.NewNopTelemetrySettings(), cfg, componentFactory, tb)
+	require.NoError(t, err)
+	require.NoError(t, p.Start(context.Background(), componenttest.NewNopHost()))
+
+	// test
+	for i := 0; i < 100; i++ {
+		_, _, err = p.exporterAndEndpoint([]byte(fmt.Sprintf("service-%d", i)), 1)
+		require.NoError(t, err)
+	}
+	p.onBackendChanges([]string{"endpoint-2"})
+
+	// verify
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, tt.Reader.Collect(context.Background(), &rm))
+	values := map[string]map[string]float64{}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			values[m.Name] = map[string]float64{}
+			switch data := m.Data.(type) {
+			case metricdata.Sum[int64]:
+				for _, dp := range data.DataPoints {
+					endpoint, _ := dp.Attributes.Value("endpoint")
+					values[m.Name][endpoint.AsString()] = float64(dp.Value)
+				}
+			case metricdata.Gauge[float64]:
+				for _, dp := range data.DataPoints {
+					endpoint, _ := dp.Attributes.Value("endpoint")
+					values[m.Name][endpoint.AsString()] = dp.Value
+				}
+			}
+		}
+	}
+
+	routed := values["otelcol_loadbalancer_backend_routing_keys"]
+	assert.Equal(t, float64(100), routed["endpoint-1:4317"]+routed["endpoint-2:4317"])
+	assert.InDelta(t, 25, routed["endpoint-1:4317"], 1)
+	overflows := values["otelcol_loadbalancer_bounded_load_overflows"]
+	assert.Positive(t, overflows["endpoint-1:4317"]+overflows["endpoint-2:4317"])
+	// the removed endpoint doesn't own any part of the ring anymore
+	assert.Equal(t, map[string]float64{"endpoint-1:4317": 0, "endpoint-2:4317": 1}, values["otelcol_loadbalancer_backend_ring_share"])
+}
+
+func TestNewLoadBalancerInvalidHealthCheck(t *testing.T) {
+	// prepare
+	ts, tb := getTelemetryAssets(t)
+	cfg := simpleConfig()
+	cfg.HealthCheck = HealthCheck{ConsecutiveFailures: 5}
+
+	// test
+	p, err := newLoadBalancer(ts.TelemetrySettings, cfg, nil, tb)
+
+	// verify
+	require.Nil(t, p)
+	require.Equal(t, errInvalidEjectionTime, err)
+}
+
+func TestNewLoadBalancerHealthCheckOverridesProtocolResilience(t *testing.T) {
+	// prepare
+	ts, tb := getTelemetryAssets(t)
+	observedZapCore, observedLogs := observer.New(zap.WarnLevel)
+	ts.Logger = zap.New(observedZapCore)
+	cfg := createDefaultConfig().(*Config)
+	cfg.Resolver.Static = &StaticResolver{Hostnames: []string{"endpoint-1"}}
+	cfg.HealthCheck.ConsecutiveFailures = 3
+
+	// test
+	p, err := newLoadBalancer(ts.TelemetrySettings, cfg, nil, tb)
+
+	// verify
+	require.NotNil(t, p)
+	require.NoError(t, err)
+	require.Equal(t, 1, observedLogs.Len())
+	assert.Equal(t, map[string]any{"sending_queue": true, "retry_on_failure": true}, observedLogs.All()[0].ContextMap())
+
+	// test
+	cfg.Protocol.OTLP.QueueConfig.Enabled = false
+	cfg.Protocol.OTLP.RetryConfig.Enabled = false
+	_, err = newLoadBalancer(ts.TelemetrySettings, cfg, nil, tb)
+
+	// verify
+	require.NoError(t, err)
+	assert.Equal(t, 1, observedLogs.Len(), "no warning when the settings are disabled explicitly")
+}
+
+func TestLoadBalancerEjectsUnhealthyEndpoint(t *testing.T) {
+	// prepare
+	ts, tb := getTelemetryAssets(t)
+	cfg := &Config{
+		Resolver: ResolverSettings{
+			Static: &StaticResolver{Hostnames: []string{"endpoint-1", "endpoint-2"}},
+		},
+		HealthCheck: HealthCheck{
+			ConsecutiveFailures: 2,
+			BaseEjectionTime:    time.Hour,
+			MaxEjectionTime:     time.Hour,
+			MaxEjectionPercent:  50,
+		},
+	}
+	componentFactory := func(_ context.Context, _ string) (component.Component, error) {
+		return newNopMockExporter(), nil
+	}
+	p, err := newLoadBalancer(ts.TelemetrySettings, cfg, componentFactory, tb)
+	require.NoError(t, err)
+	require.NoError(t, p.Start(context.Background(), componenttest.NewNopHost()))
+	defer func() {
+		require.NoError(t, p.Shutdown(context.Background()))
+	}()
+	exp := p.exporters["endpoint-1:4317"]
+
+	// test
+	// the permanent errors are caused by the data, not by the endpoint
+	p.onExportResult(exp, consumererror.NewPermanent(errors.New("invalid data")))
+	p.onExportResult(exp, consumererror.NewPermanent(errors.New("invalid data")))
+	require.Len(t, p.ring.items, 2*defaultWeight)
+	p.onExportResult(exp, errors.New("deadline exceeded"))
+	p.onExportResult(exp, errors.New("deadline exceeded"))
+
+	// verify
+	assert.Len(t, p.ring.items, defaultWeight)
+	for i := 0; i < 100; i++ {
+		_, endpoint, err := p.exporterAndEndpoint([]byte(fmt.Sprintf("key-%d", i)), 1)
+		require.NoError(t, err)
+		assert.Equal(t, "endpoint-2", endpoint)
+	}
+	// the ejected endpoint keeps its exporter, to be reinstated later
+	assert.Contains(t, p.exporters, "endpoint-1:4317")
+
+	// test
+	p.onBackendChanges([]string{"endpoint-2"})
+
+	// verify
+	assert.NotContains(t, p.exporters, "endpoint-1:4317")
+}
+
+func TestHealthyEndpointsAllEjected(t *testing.T) {
+	// prepare
+	ts, tb := getTelemetryAssets(t)
+	cfg := serviceBasedRoutingConfig()
+	cfg.HealthCheck = HealthCheck{ConsecutiveFailures: 1, BaseEjectionTime: time.Hour, MaxEjectionTime: time.Hour}
+	p, err := newLoadBalancer(ts.TelemetrySettings, cfg, nil, tb)
+	require.NoError(t, err)
+	p.resolved = []string{"endpoint-1", "endpoint-2"}
+	p.health.retain(p.resolved)
+	p.health.endpoints["endpoint-1:4317"].ejected = true
+
+	// test and verify
+	assert.Equal(t, []string{"endpoint-2"}, p.healthyEndpoints())
+
+	// all the resolved endpoints are used when all of them are ejected, there would be nowhere to send the data otherwise
+	p.health.endpoints["endpoint-2:4317"].ejected = true
+	assert.Equal(t, []string{"endpoint-1", "endpoint-2"}, p.healthyEndpoints())
+}
@@ -72,7 +72,7 @@ func TestLogExporterStart(t *testing.T) {
 			"error",
 			func() *logExporterImp {
 				// prepare
-				lb, err := newLoadBalancer(ts.Logger, simpleConfig(), nil, tb)
+				lb, err := newLoadBalancer(ts.TelemetrySettings, simpleConfig(), nil, tb)
 				require.NoError(t, err)
 				p, _ := newLogsExporter(exportertest.NewNopSettings(), simpleConfig())
 
@@ -121,7 +121,7 @@ func TestConsumeLogs(t *testing.T) {
 		return newNopMockLogsExporter(), nil
 	}
 
-	lb, err := newLoadBalancer(ts.Logger, simpleConfig(), componentFactory, tb)
+	lb, err := newLoadBalancer(ts.TelemetrySettings, simpleConfig(), componentFactory, tb)
 	require.NotNil(t, lb)
 	require.NoError(t, err)
 
@@ -158,7 +158,7 @@ func TestConsumeLogsUnexpectedExporterType(t *testing.T) {
 	}
 	ts, tb := getTelemetryAssets(t)
 
-	lb, err := newLoadBalancer(ts.Logger, simpleConfig(), componentFactory, tb)
+	lb, err := newLoadBalancer(ts.TelemetrySettings, simpleConfig(), componentFactory, tb)
 	require.NotNil(t, lb)
 	require.NoError(t, err)
 
@@ -197,7 +197,7 @@ func TestLogBatchWithTwoTraces(t *testing.T) {
 		return newMockLogsExporter(sink.ConsumeLogs), nil
 	}
 
-	lb, err := newLoadBalancer(ts.Logger, simpleConfig(), componentFactory, tb)
+	lb, err := newLoadBalancer(ts.TelemetrySettings, simpleConfig(), componentFactory, tb)
 	require.NotNil(t, lb)
 	require.NoError(t, err)
 
@@ -270,7 +270,7 @@ func TestLogsWithoutTraceID(t *testing.T) {
 	componentFactory := func(_ context.Context, _ string) (component.Component, error) {
 		return newMockLogsExporter(sink.ConsumeLogs), nil
 	}
-	lb, err := newLoadBalancer(ts.Logger, simpleConfig(), componentFactory, tb)
+	lb, err := newLoadBalancer(ts.TelemetrySettings, simpleConfig(), componentFactory, tb)
 	require.NotNil(t, lb)
 	require.NoError(t, err)
 
@@ -312,7 +312,7 @@ func TestConsumeLogs_ConcurrentResolverChange(t *testing.T) {
 		}
 		return te, nil
 	}
-	lb, err := newLoadBalancer(ts.Logger, simpleConfig(), componentFactory, tb)
+	lb, err := newLoadBalancer(ts.TelemetrySettings, simpleConfig(), componentFactory, tb)
 	require.NotNil(t, lb)
 	require.NoError(t, err)
 
@@ -410,7 +410,7 @@ func TestRollingUpdatesWhenConsumeLogs(t *testing.T) {
 	componentFactory := func(_ context.Context, _ string) (component.Component, error) {
 		return newNopMockLogsExporter(), nil
 	}
-	lb, err := newLoadBalancer(ts.Logger, cfg, componentFactory, tb)
+	lb, err := newLoadBalancer(ts.TelemetrySettings, cfg, componentFactory, tb)
 	require.NotNil(t, lb)
 	require.NoError(t, err)
 
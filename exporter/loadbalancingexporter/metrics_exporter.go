@@ -13,7 +13,6 @@ import (
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/exporter"
-	"go.opentelemetry.io/collector/exporter/otlpexporter"
 	"go.opentelemetry.io/collector/pdata/pmetric"
 	conventions "go.opentelemetry.io/collector/semconv/v1.27.0"
 	"go.opentelemetry.io/otel/metric"
@@ -42,15 +41,14 @@ func newMetricsExporter(params exporter.Settings, cfg component.Config) (*metric
 	if err != nil {
 		return nil, err
 	}
-	exporterFactory := otlpexporter.NewFactory()
 	cfFunc := func(ctx context.Context, endpoint string) (component.Component, error) {
-		oCfg := buildExporterConfig(cfg.(*Config), endpoint)
+		exporterFactory, oCfg := buildExporterFactoryAndConfig(cfg.(*Config), endpoint)
 		oParams := buildExporterSettings(params, endpoint)
 
-		return exporterFactory.CreateMetrics(ctx, oParams, &oCfg)
+		return exporterFactory.CreateMetrics(ctx, oParams, oCfg)
 	}
 
-	lb, err := newLoadBalancer(params.Logger, cfg, cfFunc, telemetry)
+	lb, err := newLoadBalancer(params.TelemetrySettings, cfg, cfFunc, telemetry)
 	if err != nil {
 		return nil, err
 	}
@@ -116,7 +114,7 @@ func (e *metricExporterImp) ConsumeMetrics(ctx context.Context, md pmetric.Metri
 	exporterEndpoints := map[*wrappedExporter]string{}
 
 	for routingID, mds := range batches {
-		exp, endpoint, err := e.loadBalancer.exporterAndEndpoint([]byte(routingID))
+		exp, endpoint, err := e.loadBalancer.exporterAndEndpoint([]byte(routingID), mds.DataPointCount())
 		if err != nil {
 			return err
 		}
@@ -140,6 +138,7 @@ func (e *metricExporterImp) ConsumeMetrics(ctx context.Context, md pmetric.Metri
 
 		exp.consumeWG.Done()
 		errs = multierr.Append(errs, err)
+		e.loadBalancer.onExportResult(exp, err)
 		e.telemetry.LoadbalancerBackendLatency.Record(ctx, duration.Milliseconds(), metric.WithAttributeSet(exp.endpointAttr))
 		if err == nil {
 			e.telemetry.LoadbalancerBackendOutcome.Add(ctx, 1, metric.WithAttributeSet(exp.successAttr))
@@ -13,7 +13,6 @@ import (
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/exporter"
-	"go.opentelemetry.io/collector/exporter/otlpexporter"
 	"go.opentelemetry.io/collector/pdata/ptrace"
 	"go.opentelemetry.io/otel/metric"
 	"go.uber.org/multierr"
@@ -44,15 +43,14 @@ func newTracesExporter(params exporter.Settings, cfg component.Config) (*traceEx
 		return nil, err
 	}
 
-	exporterFactory := otlpexporter.NewFactory()
 	cfFunc := func(ctx context.Context, endpoint string) (component.Component, error) {
-		oCfg := buildExporterConfig(cfg.(*Config), endpoint)
+		exporterFactory, oCfg := buildExporterFactoryAndConfig(cfg.(*Config), endpoint)
 		oParams := buildExporterSettings(params, endpoint)
 
-		return exporterFactory.CreateTraces(ctx, oParams, &oCfg)
+		return exporterFactory.CreateTraces(ctx, oParams, oCfg)
 	}
 
-	lb, err := newLoadBalancer(params.Logger, cfg, cfFunc, telemetry)
+	lb, err := newLoadBalancer(params.TelemetrySettings, cfg, cfFunc, telemetry)
 	if err != nil {
 		return nil, err
 	}
@@ -101,7 +99,7 @@ func (e *traceExporterImp) ConsumeTraces(ctx context.Context, td ptrace.Traces)
 		}
 
 		for rid := range routingID {
-			exp, endpoint, err := e.loadBalancer.exporterAndEndpoint([]byte(rid))
+			exp, endpoint, err := e.loadBalancer.exporterAndEndpoint([]byte(rid), batch.SpanCount())
 			if err != nil {
 				return err
 			}
@@ -124,6 +122,7 @@ func (e *traceExporterImp) ConsumeTraces(ctx context.Context, td ptrace.Traces)
 		err := exp.ConsumeTraces(ctx, td)
 		exp.consumeWG.Done()
 		errs = multierr.Append(errs, err)
+		e.loadBalancer.onExportResult(exp, err)
 		duration := time.Since(start)
 		e.telemetry.LoadbalancerBackendLatency.Record(ctx, duration.Milliseconds(), metric.WithAttributeSet(exp.endpointAttr))
 		if err == nil {
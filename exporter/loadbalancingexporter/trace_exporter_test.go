@@ -22,9 +22,12 @@ import (
 	"go.opentelemetry.io/collector/consumer/consumertest"
 	"go.opentelemetry.io/collector/exporter"
 	"go.opentelemetry.io/collector/exporter/exportertest"
+	"go.opentelemetry.io/collector/exporter/otlphttpexporter"
 	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/ptrace"
 	conventions "go.opentelemetry.io/collector/semconv/v1.27.0"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/otelarrowexporter"
 )
 
 func TestNewTracesExporter(t *testing.T) {
@@ -72,7 +75,7 @@ func TestTracesExporterStart(t *testing.T) {
 			"error",
 			func() *traceExporterImp {
 				ts, tb := getTelemetryAssets(t)
-				lb, _ := newLoadBalancer(ts.Logger, simpleConfig(), nil, tb)
+				lb, _ := newLoadBalancer(ts.TelemetrySettings, simpleConfig(), nil, tb)
 				p, _ := newTracesExporter(ts, simpleConfig())
 
 				lb.res = &mockResolver{
@@ -102,6 +105,39 @@ func TestTracesExporterStart(t *testing.T) {
 	}
 }
 
+func TestTracesExporterProtocols(t *testing.T) {
+	for _, tt := range []struct {
+		desc     string
+		protocol Protocol
+	}{
+		{
+			desc:     "otlphttp",
+			protocol: Protocol{OTLPHTTP: otlphttpexporter.NewFactory().CreateDefaultConfig().(*otlphttpexporter.Config)},
+		},
+		{
+			desc:     "otelarrow",
+			protocol: Protocol{OTelArrow: otelarrowexporter.NewFactory().CreateDefaultConfig().(*otelarrowexporter.Config)},
+		},
+	} {
+		t.Run(tt.desc, func(t *testing.T) {
+			// prepare
+			cfg := simpleConfig()
+			cfg.Protocol = tt.protocol
+			p, err := newTracesExporter(exportertest.NewNopSettings(), cfg)
+			require.NoError(t, err)
+
+			// test
+			require.NoError(t, p.Start(context.Background(), componenttest.NewNopHost()))
+			defer func() {
+				require.NoError(t, p.Shutdown(context.Background()))
+			}()
+
+			// verify
+			assert.Contains(t, p.loadBalancer.exporters, "endpoint-1:4317")
+		})
+	}
+}
+
 func TestTracesExporterShutdown(t *testing.T) {
 	p, err := newTracesExporter(exportertest.NewNopSettings(), simpleConfig())
 	require.NotNil(t, p)
@@ -119,7 +155,7 @@ func TestConsumeTraces(t *testing.T) {
 	componentFactory := func(_ context.Context, _ string) (component.Component, error) {
 		return newNopMockTracesExporter(), nil
 	}
-	lb, err := newLoadBalancer(ts.Logger, simpleConfig(), componentFactory, tb)
+	lb, err := newLoadBalancer(ts.TelemetrySettings, simpleConfig(), componentFactory, tb)
 	require.NotNil(t, lb)
 	require.NoError(t, err)
 
@@ -167,7 +203,7 @@ func TestConsumeTraces_ConcurrentResolverChange(t *testing.T) {
 		}
 		return te, nil
 	}
-	lb, err := newLoadBalancer(ts.Logger, simpleConfig(), componentFactory, tb)
+	lb, err := newLoadBalancer(ts.TelemetrySettings, simpleConfig(), componentFactory, tb)
 	require.NotNil(t, lb)
 	require.NoError(t, err)
 
@@ -210,7 +246,7 @@ func TestConsumeTracesServiceBased(t *testing.T) {
 	componentFactory := func(_ context.Context, _ string) (component.Component, error) {
 		return newNopMockTracesExporter(), nil
 	}
-	lb, err := newLoadBalancer(ts.Logger, serviceBasedRoutingConfig(), componentFactory, tb)
+	lb, err := newLoadBalancer(ts.TelemetrySettings, serviceBasedRoutingConfig(), componentFactory, tb)
 	require.NotNil(t, lb)
 	require.NoError(t, err)
 
@@ -277,7 +313,7 @@ func TestConsumeTracesExporterNoEndpoint(t *testing.T) {
 	componentFactory := func(_ context.Context, _ string) (component.Component, error) {
 		return newNopMockTracesExporter(), nil
 	}
-	lb, err := newLoadBalancer(ts.Logger, simpleConfig(), componentFactory, tb)
+	lb, err := newLoadBalancer(ts.TelemetrySettings, simpleConfig(), componentFactory, tb)
 	require.NotNil(t, lb)
 	require.NoError(t, err)
 
@@ -312,7 +348,7 @@ func TestConsumeTracesUnexpectedExporterType(t *testing.T) {
 	componentFactory := func(_ context.Context, _ string) (component.Component, error) {
 		return newNopMockExporter(), nil
 	}
-	lb, err := newLoadBalancer(ts.Logger, simpleConfig(), componentFactory, tb)
+	lb, err := newLoadBalancer(ts.TelemetrySettings, simpleConfig(), componentFactory, tb)
 	require.NotNil(t, lb)
 	require.NoError(t, err)
 
@@ -350,7 +386,7 @@ func TestBatchWithTwoTraces(t *testing.T) {
 	componentFactory := func(_ context.Context, _ string) (component.Component, error) {
 		return newMockTracesExporter(sink.ConsumeTraces), nil
 	}
-	lb, err := newLoadBalancer(ts.Logger, simpleConfig(), componentFactory, tb)
+	lb, err := newLoadBalancer(ts.TelemetrySettings, simpleConfig(), componentFactory, tb)
 	require.NotNil(t, lb)
 	require.NoError(t, err)
 
@@ -479,7 +515,7 @@ func TestRollingUpdatesWhenConsumeTraces(t *testing.T) {
 	componentFactory := func(_ context.Context, _ string) (component.Component, error) {
 		return newNopMockTracesExporter(), nil
 	}
-	lb, err := newLoadBalancer(ts.Logger, cfg, componentFactory, tb)
+	lb, err := newLoadBalancer(ts.TelemetrySettings, cfg, componentFactory, tb)
 	require.NotNil(t, lb)
 	require.NoError(t, err)
 
@@ -579,7 +615,7 @@ func benchConsumeTraces(b *testing.B, endpointsCount int, tracesCount int) {
 		},
 	}
 
-	lb, err := newLoadBalancer(ts.Logger, config, componentFactory, tb)
+	lb, err := newLoadBalancer(ts.TelemetrySettings, config, componentFactory, tb)
 	require.NotNil(b, lb)
 	require.NoError(b, err)
 
@@ -21,6 +21,7 @@ import (
 type wrappedExporter struct {
 	component.Component
 	consumeWG sync.WaitGroup
+	endpoint  string
 
 	// we store the attributes here for both cases, to avoid new allocations on the hot path
 	endpointAttr attribute.Set
@@ -32,6 +33,7 @@ func newWrappedExporter(exp component.Component, identifier string) *wrappedExpo
 	ea := attribute.String("endpoint", identifier)
 	return &wrappedExporter{
 		Component:    exp,
+		endpoint:     identifier,
 		endpointAttr: attribute.NewSet(ea),
 		successAttr:  attribute.NewSet(ea, attribute.Bool("success", true)),
 		failureAttr:  attribute.NewSet(ea, attribute.Bool("success", false)),
@@ -85,6 +85,8 @@ func (a *lastValueAccumulator) addMetric(metric pmetric.Metric, il pcommon.Instr
 		return a.accumulateSum(metric, il, resourceAttrs, now)
 	case pmetric.MetricTypeHistogram:
 		return a.accumulateHistogram(metric, il, resourceAttrs, now)
+	case pmetric.MetricTypeExponentialHistogram:
+		return a.accumulateExponentialHistogram(metric, il, resourceAttrs, now)
 	case pmetric.MetricTypeSummary:
 		return a.accumulateSummary(metric, il, resourceAttrs, now)
 	default:
@@ -291,6 +293,66 @@ func (a *lastValueAccumulator) accumulateHistogram(metric pmetric.Metric, il pco
 	return
 }
 
+func (a *lastValueAccumulator) accumulateExponentialHistogram(metric pmetric.Metric, il pcommon.InstrumentationScope, resourceAttrs pcommon.Map, now time.Time) (n int) {
+	expHistogram := metric.ExponentialHistogram()
+	dps := expHistogram.DataPoints()
+
+	for i := 0; i < dps.Len(); i++ {
+		ip := dps.At(i)
+
+		signature := timeseriesSignature(il.Name(), metric, ip.Attributes(), resourceAttrs)
+		if ip.Flags().NoRecordedValue() {
+			a.registeredMetrics.Delete(signature)
+			return 0
+		}
+
+		v, ok := a.registeredMetrics.Load(signature)
+		if !ok {
+			m := copyMetricMetadata(metric)
+			ip.CopyTo(m.SetEmptyExponentialHistogram().DataPoints().AppendEmpty())
+			m.ExponentialHistogram().SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+			a.registeredMetrics.Store(signature, &accumulatedValue{value: m, resourceAttrs: resourceAttrs, scope: il, updated: now})
+			n++
+			continue
+		}
+		mv := v.(*accumulatedValue)
+
+		m := copyMetricMetadata(metric)
+		m.SetEmptyExponentialHistogram().SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+
+		switch expHistogram.AggregationTemporality() {
+		case pmetric.AggregationTemporalityDelta:
+			pp := mv.value.ExponentialHistogram().DataPoints().At(0)
+			if ip.StartTimestamp().AsTime() != pp.Timestamp().AsTime() {
+				// treat misalignment as restart and reset, or violation of single-writer principle and drop
+				if ip.StartTimestamp().AsTime().After(pp.Timestamp().AsTime()) {
+					ip.CopyTo(m.ExponentialHistogram().DataPoints().AppendEmpty())
+				} else {
+					a.logger.With(
+						zap.String("metric_name", metric.Name()),
+					).Warn("Dropped misaligned exponential histogram datapoint")
+					continue
+				}
+			} else {
+				accumulateExponentialHistogramValues(pp, ip, m.ExponentialHistogram().DataPoints().AppendEmpty())
+			}
+		case pmetric.AggregationTemporalityCumulative:
+			if ip.Timestamp().AsTime().Before(mv.value.ExponentialHistogram().DataPoints().At(0).Timestamp().AsTime()) {
+				// only keep datapoint with latest timestamp
+				continue
+			}
+
+			ip.CopyTo(m.ExponentialHistogram().DataPoints().AppendEmpty())
+		default:
+			// unsupported temporality
+			continue
+		}
+		a.registeredMetrics.Store(signature, &accumulatedValue{value: m, resourceAttrs: resourceAttrs, scope: il, updated: now})
+		n++
+	}
+	return
+}
+
 // Collect returns a slice with relevant aggregated metrics and their resource attributes.
 func (a *lastValueAccumulator) Collect() ([]pmetric.Metric, []pcommon.Map) {
 	a.logger.Debug("Accumulator collect called")
@@ -382,3 +444,54 @@ func accumulateHistogramValues(prev, current, dest pmetric.HistogramDataPoint) {
 
 	dest.ExplicitBounds().FromRaw(newer.ExplicitBounds().AsRaw())
 }
+
+func accumulateExponentialHistogramValues(prev, current, dest pmetric.ExponentialHistogramDataPoint) {
+	older := prev
+	newer := current
+	if current.Timestamp().AsTime().Before(prev.Timestamp().AsTime()) {
+		older = current
+		newer = prev
+	}
+
+	newer.CopyTo(dest)
+	dest.SetStartTimestamp(prev.StartTimestamp())
+
+	// use new value if the bucket layouts do not match
+	if older.Scale() != newer.Scale() || older.ZeroThreshold() != newer.ZeroThreshold() {
+		return
+	}
+
+	dest.SetCount(newer.Count() + older.Count())
+	dest.SetSum(newer.Sum() + older.Sum())
+	dest.SetZeroCount(newer.ZeroCount() + older.ZeroCount())
+	if older.HasMin() && newer.HasMin() {
+		dest.SetMin(min(older.Min(), newer.Min()))
+	}
+	if older.HasMax() && newer.HasMax() {
+		dest.SetMax(max(older.Max(), newer.Max()))
+	}
+	mergeExponentialHistogramBuckets(older.Positive(), newer.Positive(), dest.Positive())
+	mergeExponentialHistogramBuckets(older.Negative(), newer.Negative(), dest.Negative())
+}
+
+func mergeExponentialHistogramBuckets(older, newer, dest pmetric.ExponentialHistogramDataPointBuckets) {
+	if older.BucketCounts().Len() == 0 {
+		newer.CopyTo(dest)
+		return
+	}
+	if newer.BucketCounts().Len() == 0 {
+		older.CopyTo(dest)
+		return
+	}
+
+	offset := min(older.Offset(), newer.Offset())
+	end := max(older.Offset()+int32(older.BucketCounts().Len()), newer.Offset()+int32(newer.BucketCounts().Len()))
+	counts := make([]uint64, end-offset)
+	for _, b := range []pmetric.ExponentialHistogramDataPointBuckets{older, newer} {
+		for i := 0; i < b.BucketCounts().Len(); i++ {
+			counts[b.Offset()-offset+int32(i)] += b.BucketCounts().At(i)
+		}
+	}
+	dest.SetOffset(offset)
+	dest.BucketCounts().FromRaw(counts)
+}
@@ -9,6 +9,7 @@ import (
 	"sort"
 	"sync"
 	"time"
+	"unicode/utf8"
 
 	"github.com/prometheus/client_golang/prometheus"
 	dto "github.com/prometheus/client_model/go"
@@ -30,6 +31,7 @@ type collector struct {
 
 	sendTimestamps    bool
 	addMetricSuffixes bool
+	utf8Names         bool
 	namespace         string
 	constLabels       prometheus.Labels
 	metricFamilies    sync.Map
@@ -49,11 +51,30 @@ func newCollector(config *Config, logger *zap.Logger) *collector {
 		sendTimestamps:    config.SendTimestamps,
 		constLabels:       config.ConstLabels,
 		addMetricSuffixes: config.AddMetricSuffixes,
+		utf8Names:         config.MetricNameEscapingScheme != "" && config.MetricNameEscapingScheme != model.EscapeUnderscores,
 		metricExpiration:  config.MetricExpiration,
 	}
 }
 
-func convertExemplars(exemplars pmetric.ExemplarSlice) []prometheus.Exemplar {
+// metricName returns the exposed name of the metric, keeping UTF-8 characters unless legacy names are used.
+func (c *collector) metricName(metric pmetric.Metric) string {
+	if c.utf8Names {
+		return prometheustranslator.BuildMetricName(metric, c.namespace, c.addMetricSuffixes)
+	}
+	return prometheustranslator.BuildCompliantName(metric, c.namespace, c.addMetricSuffixes)
+}
+
+// labelName returns the exposed name of an attribute, keeping UTF-8 characters unless legacy names are used.
+func (c *collector) labelName(key string) string {
+	if c.utf8Names {
+		return key
+	}
+	return prometheustranslator.NormalizeLabel(key)
+}
+
+// convertExemplars converts exemplars, adding their filtered attributes as labels
+// for as long as the labels fit in the OpenMetrics exemplar size limit.
+func (c *collector) convertExemplars(exemplars pmetric.ExemplarSlice) []prometheus.Exemplar {
 	length := exemplars.Len()
 	result := make([]prometheus.Exemplar, length)
 
@@ -69,6 +90,8 @@ func convertExemplars(exemplars pmetric.ExemplarSlice) []prometheus.Exemplar {
 			exemplarLabels[prometheustranslator.ExemplarSpanIDKey] = hex.EncodeToString(spanID[:])
 		}
 
+		c.addExemplarAttributes(exemplarLabels, e.FilteredAttributes())
+
 		var value float64
 		switch e.ValueType() {
 		case pmetric.ExemplarValueTypeDouble:
@@ -86,6 +109,34 @@ func convertExemplars(exemplars pmetric.ExemplarSlice) []prometheus.Exemplar {
 	return result
 }
 
+func (c *collector) addExemplarAttributes(exemplarLabels prometheus.Labels, attributes pcommon.Map) {
+	runes := 0
+	for k, v := range exemplarLabels {
+		runes += utf8.RuneCountInString(k) + utf8.RuneCountInString(v)
+	}
+
+	keys := make([]string, 0, attributes.Len())
+	attributes.Range(func(k string, _ pcommon.Value) bool {
+		keys = append(keys, k)
+		return true
+	})
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		v, _ := attributes.Get(k)
+		name, value := c.labelName(k), v.AsString()
+		if _, exists := exemplarLabels[name]; exists {
+			continue
+		}
+		size := utf8.RuneCountInString(name) + utf8.RuneCountInString(value)
+		if runes+size > prometheus.ExemplarMaxRunes {
+			continue
+		}
+		exemplarLabels[name] = value
+		runes += size
+	}
+}
+
 // Describe is a no-op, because the collector dynamically allocates metrics.
 // https://github.com/prometheus/client_golang/blob/v1.9.0/prometheus/collector.go#L28-L40
 func (c *collector) Describe(_ chan<- *prometheus.Desc) {}
@@ -107,6 +158,8 @@ func (c *collector) convertMetric(metric pmetric.Metric, resourceAttrs pcommon.M
 		return c.convertSum(metric, resourceAttrs)
 	case pmetric.MetricTypeHistogram:
 		return c.convertDoubleHistogram(metric, resourceAttrs)
+	case pmetric.MetricTypeExponentialHistogram:
+		return c.convertExponentialHistogram(metric, resourceAttrs)
 	case pmetric.MetricTypeSummary:
 		return c.convertSummary(metric, resourceAttrs)
 	}
@@ -115,7 +168,7 @@ func (c *collector) convertMetric(metric pmetric.Metric, resourceAttrs pcommon.M
 }
 
 func (c *collector) getMetricMetadata(metric pmetric.Metric, mType *dto.MetricType, attributes pcommon.Map, resourceAttrs pcommon.Map) (*prometheus.Desc, []string, error) {
-	name := prometheustranslator.BuildCompliantName(metric, c.namespace, c.addMetricSuffixes)
+	name := c.metricName(metric)
 	help, err := c.validateMetrics(name, metric.Description(), mType)
 	if err != nil {
 		return nil, nil, err
@@ -125,7 +178,7 @@ func (c *collector) getMetricMetadata(metric pmetric.Metric, mType *dto.MetricTy
 	values := make([]string, 0, attributes.Len()+2)
 
 	attributes.Range(func(k string, v pcommon.Value) bool {
-		keys = append(keys, prometheustranslator.NormalizeLabel(k))
+		keys = append(keys, c.labelName(k))
 		values = append(values, v.AsString())
 		return true
 	})
@@ -198,7 +251,7 @@ func (c *collector) convertSum(metric pmetric.Metric, resourceAttrs pcommon.Map)
 	var exemplars []prometheus.Exemplar
 	// Prometheus currently only supports exporting counters
 	if metricType == prometheus.CounterValue {
-		exemplars = convertExemplars(ip.Exemplars())
+		exemplars = c.convertExemplars(ip.Exemplars())
 	}
 
 	var m prometheus.Metric
@@ -287,7 +340,7 @@ func (c *collector) convertDoubleHistogram(metric pmetric.Metric, resourceAttrs
 		points[bucket] = cumCount
 	}
 
-	exemplars := convertExemplars(ip.Exemplars())
+	exemplars := c.convertExemplars(ip.Exemplars())
 
 	var m prometheus.Metric
 	if ip.StartTimestamp().AsTime().Unix() > 0 {
@@ -312,6 +365,24 @@ func (c *collector) convertDoubleHistogram(metric pmetric.Metric, resourceAttrs
 	return m, nil
 }
 
+func (c *collector) convertExponentialHistogram(metric pmetric.Metric, resourceAttrs pcommon.Map) (prometheus.Metric, error) {
+	ip := metric.ExponentialHistogram().DataPoints().At(0)
+	desc, attributes, err := c.getMetricMetadata(metric, dto.MetricType_HISTOGRAM.Enum(), ip.Attributes(), resourceAttrs)
+	if err != nil {
+		return nil, err
+	}
+
+	m, err := newNativeHistogram(desc, ip, c.convertExemplars(ip.Exemplars()), attributes...)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.sendTimestamps {
+		return prometheus.NewMetricWithTimestamp(ip.Timestamp().AsTime(), m), nil
+	}
+	return m, nil
+}
+
 func (c *collector) createTargetInfoMetrics(resourceAttrs []pcommon.Map) ([]prometheus.Metric, error) {
 	var lastErr error
 
@@ -349,7 +420,7 @@ func (c *collector) createTargetInfoMetrics(resourceAttrs []pcommon.Map) ([]prom
 		})
 
 		attributes.Range(func(k string, v pcommon.Value) bool {
-			finalKey := prometheustranslator.NormalizeLabel(k)
+			finalKey := c.labelName(k)
 			if existingVal, ok := labels[finalKey]; ok {
 				labels[finalKey] = existingVal + ";" + v.AsString()
 			} else {
@@ -280,6 +280,181 @@ func TestConvertMonotonicSumExemplar(t *testing.T) {
 	exemplarsEqual(t, exemplar, promCounter.GetExemplar())
 }
 
+func TestConvertExemplarFilteredAttributes(t *testing.T) {
+	metric := pmetric.NewMetric()
+	metric.SetName("test_monotonic_sum")
+	sum := metric.SetEmptySum()
+	sum.SetIsMonotonic(true)
+	dataPoint := sum.DataPoints().AppendEmpty()
+	dataPoint.SetIntValue(1)
+
+	exemplar := dataPoint.Exemplars().AppendEmpty()
+	setTextExemplarWithIntValue(exemplar, 1)
+	exemplar.FilteredAttributes().PutStr("http.route", "/users")
+	exemplar.FilteredAttributes().PutStr("z.too.long", strings.Repeat("x", prometheus.ExemplarMaxRunes))
+
+	c := collector{logger: zap.NewNop()}
+	promMetric, err := c.convertSum(metric, pcommon.NewMap())
+	require.NoError(t, err)
+	outMetric := io_prometheus_client.Metric{}
+	require.NoError(t, promMetric.Write(&outMetric))
+
+	labels := map[string]string{}
+	for _, l := range outMetric.GetCounter().GetExemplar().GetLabel() {
+		labels[l.GetName()] = l.GetValue()
+	}
+	require.Len(t, labels, 3)
+	require.Equal(t, "/users", labels["http_route"])
+	require.Contains(t, labels, "trace_id")
+	require.Contains(t, labels, "span_id")
+}
+
+func TestConvertExponentialHistogram(t *testing.T) {
+	ts := time.Unix(1700000000, 0).UTC()
+
+	tests := []struct {
+		name           string
+		scale          int32
+		positiveOffset int32
+		positive       []uint64
+		negative       []uint64
+		wantSchema     int32
+		wantPosSpans   []*io_prometheus_client.BucketSpan
+		wantPosDeltas  []int64
+		wantNegSpans   []*io_prometheus_client.BucketSpan
+		wantNegDeltas  []int64
+	}{
+		{
+			name:           "contiguous and sparse buckets",
+			scale:          2,
+			positiveOffset: -1,
+			positive:       []uint64{1, 3, 0, 0, 2},
+			negative:       []uint64{4},
+			wantSchema:     2,
+			wantPosSpans: []*io_prometheus_client.BucketSpan{
+				{Offset: proto.Int32(0), Length: proto.Uint32(2)},
+				{Offset: proto.Int32(2), Length: proto.Uint32(1)},
+			},
+			wantPosDeltas: []int64{1, 2, -1},
+			wantNegSpans: []*io_prometheus_client.BucketSpan{
+				{Offset: proto.Int32(1), Length: proto.Uint32(1)},
+			},
+			wantNegDeltas: []int64{4},
+		},
+		{
+			name:           "scale above the maximum schema is downscaled",
+			scale:          10,
+			positiveOffset: 0,
+			positive:       []uint64{1, 1, 1, 1, 2},
+			wantSchema:     8,
+			wantPosSpans: []*io_prometheus_client.BucketSpan{
+				{Offset: proto.Int32(1), Length: proto.Uint32(2)},
+			},
+			wantPosDeltas: []int64{4, -2},
+		},
+		{
+			name:       "no populated bucket",
+			scale:      0,
+			wantSchema: 0,
+			wantPosSpans: []*io_prometheus_client.BucketSpan{
+				{Offset: proto.Int32(0), Length: proto.Uint32(0)},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			metric := pmetric.NewMetric()
+			metric.SetName("test_exponential_histogram")
+			dp := metric.SetEmptyExponentialHistogram().DataPoints().AppendEmpty()
+			dp.SetScale(tt.scale)
+			dp.SetCount(12)
+			dp.SetSum(42)
+			dp.SetZeroCount(1)
+			dp.SetZeroThreshold(0.001)
+			dp.Positive().SetOffset(tt.positiveOffset)
+			dp.Positive().BucketCounts().FromRaw(tt.positive)
+			dp.Negative().BucketCounts().FromRaw(tt.negative)
+			dp.SetStartTimestamp(pcommon.NewTimestampFromTime(ts))
+			dp.SetTimestamp(pcommon.NewTimestampFromTime(ts.Add(time.Minute)))
+			exemplar := dp.Exemplars().AppendEmpty()
+			setTestExemplarWithDoubleValue(exemplar, 3.0)
+
+			c := collector{logger: zap.NewNop()}
+			promMetric, err := c.convertMetric(metric, pcommon.NewMap())
+			require.NoError(t, err)
+			require.Contains(t, promMetric.Desc().String(), "fqName: \"test_exponential_histogram\"")
+
+			out := io_prometheus_client.Metric{}
+			require.NoError(t, promMetric.Write(&out))
+			h := out.GetHistogram()
+			require.Equal(t, uint64(12), h.GetSampleCount())
+			require.Equal(t, 42.0, h.GetSampleSum())
+			require.Equal(t, tt.wantSchema, h.GetSchema())
+			require.Equal(t, uint64(1), h.GetZeroCount())
+			require.Equal(t, 0.001, h.GetZeroThreshold())
+			require.Equal(t, tt.wantPosSpans, h.GetPositiveSpan())
+			require.Equal(t, tt.wantPosDeltas, h.GetPositiveDelta())
+			require.Equal(t, tt.wantNegSpans, h.GetNegativeSpan())
+			require.Equal(t, tt.wantNegDeltas, h.GetNegativeDelta())
+			require.Equal(t, ts, h.GetCreatedTimestamp().AsTime())
+			require.Empty(t, h.GetBucket())
+			require.Len(t, h.GetExemplars(), 1)
+			exemplarsEqual(t, exemplar, h.GetExemplars()[0])
+		})
+	}
+}
+
+func TestConvertExponentialHistogramUnsupportedScale(t *testing.T) {
+	metric := pmetric.NewMetric()
+	metric.SetName("test_exponential_histogram")
+	metric.SetEmptyExponentialHistogram().DataPoints().AppendEmpty().SetScale(-5)
+
+	c := collector{logger: zap.NewNop()}
+	_, err := c.convertMetric(metric, pcommon.NewMap())
+	require.ErrorContains(t, err, "minimum native histogram schema")
+}
+
+func TestCollectMetricsUTF8Names(t *testing.T) {
+	metric := pmetric.NewMetric()
+	metric.SetName("http.server.active_requests")
+	metric.SetUnit("{request}")
+	dp := metric.SetEmptySum().DataPoints().AppendEmpty()
+	metric.Sum().SetIsMonotonic(true)
+	metric.Sum().SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+	dp.SetIntValue(42)
+	dp.Attributes().PutStr("http.request.method", "GET")
+
+	loggerCore := errorCheckCore{}
+	c := collector{
+		accumulator: &mockAccumulator{
+			[]pmetric.Metric{metric},
+			pcommon.NewMap(),
+		},
+		addMetricSuffixes: true,
+		utf8Names:         true,
+		logger:            zap.New(&loggerCore),
+	}
+
+	ch := make(chan prometheus.Metric, 1)
+	go func() {
+		c.Collect(ch)
+		close(ch)
+	}()
+
+	for m := range ch {
+		require.Contains(t, m.Desc().String(), "fqName: \"http.server.active_requests_total\"")
+
+		pbMetric := io_prometheus_client.Metric{}
+		require.NoError(t, m.Write(&pbMetric))
+		require.Len(t, pbMetric.Label, 1)
+		require.Equal(t, "http.request.method", pbMetric.Label[0].GetName())
+		require.Equal(t, "GET", pbMetric.Label[0].GetValue())
+	}
+
+	require.Empty(t, loggerCore.errorMessages)
+}
+
 // errorCheckCore keeps track of logged errors
 type errorCheckCore struct {
 	errorMessages []string
@@ -4,9 +4,11 @@
 package prometheusexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/prometheusexporter"
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/model"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config/confighttp"
 
@@ -37,11 +39,20 @@ type Config struct {
 
 	// AddMetricSuffixes controls whether suffixes are added to metric names. Defaults to true.
 	AddMetricSuffixes bool `mapstructure:"add_metric_suffixes"`
+
+	// MetricNameEscapingScheme controls how metric and label names are exposed. "underscores" (the default)
+	// translates names to the legacy Prometheus character set. "allow-utf-8", "dots" and "values" keep
+	// UTF-8 names; scrapers that do not negotiate an escaping scheme receive names escaped with
+	// underscores for "allow-utf-8" and with the configured scheme otherwise.
+	MetricNameEscapingScheme string `mapstructure:"metric_name_escaping_scheme"`
 }
 
 var _ component.Config = (*Config)(nil)
 
 // Validate checks if the exporter configuration is valid
 func (cfg *Config) Validate() error {
+	if _, err := model.ToEscapingScheme(cfg.MetricNameEscapingScheme); err != nil {
+		return fmt.Errorf("invalid metric_name_escaping_scheme: %w", err)
+	}
 	return nil
 }
@@ -53,6 +53,8 @@ func TestLoadConfig(t *testing.T) {
 				SendTimestamps:    true,
 				MetricExpiration:  60 * time.Minute,
 				AddMetricSuffixes: false,
+
+				MetricNameEscapingScheme: "allow-utf-8",
 			},
 		},
 	}
@@ -71,3 +73,14 @@ func TestLoadConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateMetricNameEscapingScheme(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	for _, scheme := range []string{"underscores", "dots", "values", "allow-utf-8"} {
+		cfg.MetricNameEscapingScheme = scheme
+		assert.NoError(t, component.ValidateConfig(cfg), scheme)
+	}
+
+	cfg.MetricNameEscapingScheme = "unknown"
+	assert.ErrorContains(t, component.ValidateConfig(cfg), "invalid metric_name_escaping_scheme")
+}
@@ -7,6 +7,7 @@ import (
 	"context"
 	"time"
 
+	"github.com/prometheus/common/model"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/exporter"
@@ -31,6 +32,8 @@ func createDefaultConfig() component.Config {
 		MetricExpiration:  time.Minute * 5,
 		EnableOpenMetrics: false,
 		AddMetricSuffixes: true,
+
+		MetricNameEscapingScheme: model.EscapeUnderscores,
 	}
 }
 
@@ -0,0 +1,135 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package prometheusexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/prometheusexporter"
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	// Native histograms support schemas -4 to 8; exponential histograms with
+	// a larger scale are downscaled, smaller scales cannot be represented.
+	nativeHistogramMinSchema = -4
+	nativeHistogramMaxSchema = 8
+)
+
+// nativeHistogram is a constant prometheus.Metric holding a native histogram.
+// Native histograms are only part of the protobuf exposition format, text
+// formats only expose their count and sum.
+type nativeHistogram struct {
+	desc       *prometheus.Desc
+	labelPairs []*dto.LabelPair
+	histogram  *dto.Histogram
+}
+
+func (h *nativeHistogram) Desc() *prometheus.Desc {
+	return h.desc
+}
+
+func (h *nativeHistogram) Write(out *dto.Metric) error {
+	out.Label = h.labelPairs
+	out.Histogram = h.histogram
+	return nil
+}
+
+// newNativeHistogram converts an exponential histogram data point to a native histogram.
+func newNativeHistogram(desc *prometheus.Desc, dp pmetric.ExponentialHistogramDataPoint, exemplars []prometheus.Exemplar, labelValues ...string) (prometheus.Metric, error) {
+	// Validate the descriptor and label values the same way constant metrics do.
+	if _, err := prometheus.NewConstMetric(desc, prometheus.UntypedValue, 0, labelValues...); err != nil {
+		return nil, err
+	}
+
+	schema := dp.Scale()
+	if schema < nativeHistogramMinSchema {
+		return nil, fmt.Errorf("exponential histogram scale %d is lower than the minimum native histogram schema %d", schema, nativeHistogramMinSchema)
+	}
+	var scaleDown int32
+	if schema > nativeHistogramMaxSchema {
+		scaleDown = schema - nativeHistogramMaxSchema
+		schema = nativeHistogramMaxSchema
+	}
+
+	h := &dto.Histogram{
+		SampleCount:   proto.Uint64(dp.Count()),
+		Schema:        proto.Int32(schema),
+		ZeroThreshold: proto.Float64(dp.ZeroThreshold()),
+		ZeroCount:     proto.Uint64(dp.ZeroCount()),
+	}
+	if dp.HasSum() {
+		h.SampleSum = proto.Float64(dp.Sum())
+	}
+	h.PositiveSpan, h.PositiveDelta = convertNativeHistogramBuckets(dp.Positive(), scaleDown)
+	h.NegativeSpan, h.NegativeDelta = convertNativeHistogramBuckets(dp.Negative(), scaleDown)
+	if len(h.PositiveSpan) == 0 && len(h.NegativeSpan) == 0 {
+		// An empty span marks the histogram as native even without any populated bucket.
+		h.PositiveSpan = []*dto.BucketSpan{{Offset: proto.Int32(0), Length: proto.Uint32(0)}}
+	}
+	if start := dp.StartTimestamp().AsTime(); start.Unix() > 0 {
+		h.CreatedTimestamp = timestamppb.New(start)
+	}
+	for _, e := range exemplars {
+		h.Exemplars = append(h.Exemplars, toDtoExemplar(e))
+	}
+
+	return &nativeHistogram{
+		desc:       desc,
+		labelPairs: prometheus.MakeLabelPairs(desc, labelValues),
+		histogram:  h,
+	}, nil
+}
+
+// convertNativeHistogramBuckets returns the spans and delta encoded counts of the populated buckets,
+// merging buckets by 2^scaleDown. The OTLP bucket at index i covers (base^i, base^(i+1)], which is
+// the Prometheus bucket at index i+1.
+func convertNativeHistogramBuckets(buckets pmetric.ExponentialHistogramDataPointBuckets, scaleDown int32) ([]*dto.BucketSpan, []int64) {
+	counts := buckets.BucketCounts()
+	var (
+		spans     []*dto.BucketSpan
+		deltas    []int64
+		prevCount int64
+		nextIndex int32
+	)
+	for i := 0; i < counts.Len(); {
+		index := (buckets.Offset() + int32(i)) >> scaleDown
+		count := counts.At(i)
+		for i++; i < counts.Len() && (buckets.Offset()+int32(i))>>scaleDown == index; i++ {
+			count += counts.At(i)
+		}
+		if count == 0 {
+			continue
+		}
+
+		promIndex := index + 1
+		if len(spans) == 0 || promIndex != nextIndex {
+			spans = append(spans, &dto.BucketSpan{Offset: proto.Int32(promIndex - nextIndex), Length: proto.Uint32(0)})
+		}
+		span := spans[len(spans)-1]
+		span.Length = proto.Uint32(span.GetLength() + 1)
+		deltas = append(deltas, int64(count)-prevCount)
+		prevCount = int64(count)
+		nextIndex = promIndex + 1
+	}
+	return spans, deltas
+}
+
+func toDtoExemplar(e prometheus.Exemplar) *dto.Exemplar {
+	labels := make([]*dto.LabelPair, 0, len(e.Labels))
+	for name, value := range e.Labels {
+		labels = append(labels, &dto.LabelPair{Name: proto.String(name), Value: proto.String(value)})
+	}
+	sort.Slice(labels, func(i, j int) bool { return labels[i].GetName() < labels[j].GetName() })
+
+	return &dto.Exemplar{
+		Label:     labels,
+		Value:     proto.Float64(e.Value),
+		Timestamp: timestamppb.New(e.Timestamp),
+	}
+}
@@ -11,6 +11,7 @@ import (
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/prometheus/common/model"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/exporter"
 	"go.opentelemetry.io/collector/pdata/pmetric"
@@ -38,6 +39,17 @@ func newPrometheusExporter(config *Config, set exporter.Settings) (*prometheusEx
 	collector := newCollector(config, set.Logger)
 	registry := prometheus.NewRegistry()
 	_ = registry.Register(collector)
+	handler := promhttp.HandlerFor(
+		registry,
+		promhttp.HandlerOpts{
+			ErrorHandling:     promhttp.ContinueOnError,
+			ErrorLog:          newPromLogger(set.Logger),
+			EnableOpenMetrics: config.EnableOpenMetrics,
+		},
+	)
+	if scheme := config.MetricNameEscapingScheme; scheme == model.EscapeDots || scheme == model.EscapeValues {
+		handler = withDefaultEscaping(handler, scheme)
+	}
 	return &prometheusExporter{
 		config:       *config,
 		name:         set.ID.String(),
@@ -45,18 +57,32 @@ func newPrometheusExporter(config *Config, set exporter.Settings) (*prometheusEx
 		collector:    collector,
 		registry:     registry,
 		shutdownFunc: func(_ context.Context) error { return nil },
-		handler: promhttp.HandlerFor(
-			registry,
-			promhttp.HandlerOpts{
-				ErrorHandling:     promhttp.ContinueOnError,
-				ErrorLog:          newPromLogger(set.Logger),
-				EnableOpenMetrics: config.EnableOpenMetrics,
-			},
-		),
-		settings: set.TelemetrySettings,
+		handler:      handler,
+		settings:     set.TelemetrySettings,
 	}, nil
 }
 
+// withDefaultEscaping makes the handler escape metric and label names with the specified
+// scheme when the scraper does not negotiate one in its Accept header.
+func withDefaultEscaping(next http.Handler, scheme string) http.Handler {
+	param := ";" + model.EscapingKey + "=" + scheme
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		accept := r.Header.Get("Accept")
+		if !strings.Contains(accept, model.EscapingKey+"=") {
+			if strings.TrimSpace(accept) == "" {
+				accept = "text/plain"
+			}
+			ranges := strings.Split(accept, ",")
+			for i := range ranges {
+				ranges[i] = strings.TrimSpace(ranges[i]) + param
+			}
+			r = r.Clone(r.Context())
+			r.Header.Set("Accept", strings.Join(ranges, ","))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
 func (pe *prometheusExporter) Start(ctx context.Context, host component.Host) error {
 	ln, err := pe.config.ToListener(ctx)
 	if err != nil {
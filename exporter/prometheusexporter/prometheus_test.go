@@ -8,9 +8,12 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/component/componenttest"
@@ -428,6 +431,123 @@ func TestPrometheusExporter_endToEndWithResource(t *testing.T) {
 	}
 }
 
+func TestPrometheusExporter_metricNameEscaping(t *testing.T) {
+	tests := []struct {
+		scheme string
+		accept string
+		want   []string
+	}{
+		{
+			scheme: "underscores",
+			want:   []string{`http_server_active_requests_total{http_request_method="GET"} 42`},
+		},
+		{
+			scheme: "underscores",
+			accept: "text/plain;version=0.0.4;escaping=allow-utf-8",
+			want:   []string{`http_server_active_requests_total{http_request_method="GET"} 42`},
+		},
+		{
+			scheme: "allow-utf-8",
+			want:   []string{`http_server_active_requests_total{http_request_method="GET"} 42`},
+		},
+		{
+			scheme: "allow-utf-8",
+			accept: "text/plain;version=0.0.4;escaping=allow-utf-8",
+			want:   []string{`{"http.server.active_requests_total","http.request.method"="GET"} 42`},
+		},
+		{
+			scheme: "dots",
+			want:   []string{`http_dot_server_dot_active__requests__total{http_dot_request_dot_method="GET"} 42`},
+		},
+		{
+			scheme: "dots",
+			accept: "text/plain;version=0.0.4;q=0.5,*/*;q=0.1",
+			want:   []string{`http_dot_server_dot_active__requests__total{http_dot_request_dot_method="GET"} 42`},
+		},
+		{
+			scheme: "dots",
+			accept: "text/plain;version=0.0.4;escaping=allow-utf-8",
+			want:   []string{`{"http.server.active_requests_total","http.request.method"="GET"} 42`},
+		},
+		{
+			scheme: "values",
+			want:   []string{`U__http_2e_server_2e_active__requests__total{U__http_2e_request_2e_method="GET"} 42`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.scheme+"/"+tt.accept, func(t *testing.T) {
+			cfg := createDefaultConfig().(*Config)
+			cfg.Endpoint = "localhost:0"
+			cfg.MetricNameEscapingScheme = tt.scheme
+			pe, err := newPrometheusExporter(cfg, exportertest.NewNopSettings())
+			require.NoError(t, err)
+
+			md := pmetric.NewMetrics()
+			metric := md.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics().AppendEmpty()
+			metric.SetName("http.server.active_requests")
+			metric.SetUnit("{request}")
+			metric.SetEmptySum().SetIsMonotonic(true)
+			metric.Sum().SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+			dp := metric.Sum().DataPoints().AppendEmpty()
+			dp.SetIntValue(42)
+			dp.Attributes().PutStr("http.request.method", "GET")
+			require.NoError(t, pe.ConsumeMetrics(context.Background(), md))
+
+			req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+			if tt.accept != "" {
+				req.Header.Set("Accept", tt.accept)
+			}
+			rec := httptest.NewRecorder()
+			pe.handler.ServeHTTP(rec, req)
+
+			require.Equal(t, http.StatusOK, rec.Code)
+			for _, w := range tt.want {
+				assert.Contains(t, rec.Body.String(), w)
+			}
+		})
+	}
+}
+
+func TestPrometheusExporter_nativeHistogram(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Endpoint = "localhost:0"
+	pe, err := newPrometheusExporter(cfg, exportertest.NewNopSettings())
+	require.NoError(t, err)
+
+	md := pmetric.NewMetrics()
+	metric := md.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics().AppendEmpty()
+	metric.SetName("request.duration")
+	metric.SetUnit("s")
+	metric.SetEmptyExponentialHistogram().SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+	dp := metric.ExponentialHistogram().DataPoints().AppendEmpty()
+	dp.SetScale(3)
+	dp.SetCount(6)
+	dp.SetSum(12.5)
+	dp.Positive().SetOffset(2)
+	dp.Positive().BucketCounts().FromRaw([]uint64{1, 2, 3})
+	dp.SetTimestamp(pcommon.NewTimestampFromTime(time.Now()))
+	require.NoError(t, pe.ConsumeMetrics(context.Background(), md))
+
+	req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	req.Header.Set("Accept", string(expfmt.NewFormat(expfmt.TypeProtoDelim)))
+	rec := httptest.NewRecorder()
+	pe.handler.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	decoder := expfmt.NewDecoder(rec.Body, expfmt.ResponseFormat(rec.Header()))
+	mf := &dto.MetricFamily{}
+	require.NoError(t, decoder.Decode(mf))
+	require.Equal(t, "request_duration_seconds", mf.GetName())
+	require.Equal(t, dto.MetricType_HISTOGRAM, mf.GetType())
+	h := mf.GetMetric()[0].GetHistogram()
+	assert.Equal(t, int32(3), h.GetSchema())
+	assert.Equal(t, uint64(6), h.GetSampleCount())
+	assert.Equal(t, 12.5, h.GetSampleSum())
+	assert.Equal(t, []int64{1, 1, 1}, h.GetPositiveDelta())
+	assert.Equal(t, int32(3), h.GetPositiveSpan()[0].GetOffset())
+}
+
 func metricBuilder(delta int64, prefix, job, instance string) pmetric.Metrics {
 	md := pmetric.NewMetrics()
 	rms := md.ResourceMetrics().AppendEmpty()
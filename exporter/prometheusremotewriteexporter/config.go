@@ -57,8 +57,21 @@ type Config struct {
 
 	// SendMetadata controls whether prometheus metadata will be generated and sent
 	SendMetadata bool `mapstructure:"send_metadata"`
+
+	// Protocol is the version of the remote write protocol used to send the metrics, either v1 or v2.
+	// When the endpoint rejects remote write 2.0 requests, the exporter falls back to v1.
+	Protocol string `mapstructure:"protocol"`
+
+	// ConvertHistogramsToNHCB controls whether explicit bucket histograms are sent as native histograms
+	// with custom buckets instead of classic histogram series. Requires the v2 protocol.
+	ConvertHistogramsToNHCB bool `mapstructure:"convert_histograms_to_nhcb"`
 }
 
+const (
+	protocolV1 = "v1"
+	protocolV2 = "v2"
+)
+
 type CreatedMetric struct {
 	// Enabled if true the _created metrics could be exported
 	Enabled bool `mapstructure:"enabled"`
@@ -106,6 +119,23 @@ func (cfg *Config) Validate() error {
 		return fmt.Errorf("remote write consumer number can't be negative")
 	}
 
+	switch cfg.Protocol {
+	case "":
+		cfg.Protocol = protocolV1
+	case protocolV1, protocolV2:
+	default:
+		return fmt.Errorf("unsupported remote write protocol %q, must be %q or %q", cfg.Protocol, protocolV1, protocolV2)
+	}
+	if cfg.ConvertHistogramsToNHCB && cfg.Protocol != protocolV2 {
+		return fmt.Errorf("convert_histograms_to_nhcb requires the %q protocol", protocolV2)
+	}
+
+	if cfg.WAL != nil {
+		if err := cfg.WAL.validate(); err != nil {
+			return err
+		}
+	}
+
 	if cfg.TargetInfo == nil {
 		cfg.TargetInfo = &TargetInfo{
 			Enabled: true,
@@ -80,7 +80,9 @@ func TestLoadConfig(t *testing.T) {
 				TargetInfo: &TargetInfo{
 					Enabled: true,
 				},
-				CreatedMetric: &CreatedMetric{Enabled: true},
+				CreatedMetric:           &CreatedMetric{Enabled: true},
+				Protocol:                protocolV2,
+				ConvertHistogramsToNHCB: true,
 			},
 		},
 		{
@@ -95,6 +97,18 @@ func TestLoadConfig(t *testing.T) {
 			id:           component.NewIDWithName(metadata.Type, "less_than_1_max_batch_request_parallelism"),
 			errorMessage: "max_batch_request_parallelism can't be set to below 1",
 		},
+		{
+			id:           component.NewIDWithName(metadata.Type, "invalid_protocol"),
+			errorMessage: `unsupported remote write protocol "v3", must be "v1" or "v2"`,
+		},
+		{
+			id:           component.NewIDWithName(metadata.Type, "nhcb_without_v2"),
+			errorMessage: `convert_histograms_to_nhcb requires the "v2" protocol`,
+		},
+		{
+			id:           component.NewIDWithName(metadata.Type, "negative_wal_max_size"),
+			errorMessage: "wal max_size_bytes can't be negative",
+		},
 	}
 
 	for _, tt := range tests {
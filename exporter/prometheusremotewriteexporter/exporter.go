@@ -14,11 +14,13 @@ import (
 	"net/url"
 	"strings"
 	"sync"
+	"sync/atomic"
 
 	"github.com/cenkalti/backoff/v4"
 	"github.com/gogo/protobuf/proto"
 	"github.com/golang/snappy"
 	"github.com/prometheus/prometheus/prompb"
+	writev2 "github.com/prometheus/prometheus/prompb/io/prometheus/write/v2"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config/confighttp"
 	"go.opentelemetry.io/collector/config/configretry"
@@ -38,6 +40,10 @@ import (
 type prwTelemetry interface {
 	recordTranslationFailure(ctx context.Context)
 	recordTranslatedTimeSeries(ctx context.Context, numTS int)
+	recordWALBytes(ctx context.Context, size int64)
+	recordWALLag(ctx context.Context, lag int64)
+	recordWALReplayedSamples(ctx context.Context, numSamples int)
+	recordWALDroppedRequests(ctx context.Context, numRequests int, reason string)
 }
 
 type prwTelemetryOtel struct {
@@ -53,6 +59,23 @@ func (p *prwTelemetryOtel) recordTranslatedTimeSeries(ctx context.Context, numTS
 	p.telemetryBuilder.ExporterPrometheusremotewriteTranslatedTimeSeries.Add(ctx, int64(numTS), metric.WithAttributes(p.otelAttrs...))
 }
 
+func (p *prwTelemetryOtel) recordWALBytes(ctx context.Context, size int64) {
+	p.telemetryBuilder.ExporterPrometheusremotewriteWalBytes.Record(ctx, size, metric.WithAttributes(p.otelAttrs...))
+}
+
+func (p *prwTelemetryOtel) recordWALLag(ctx context.Context, lag int64) {
+	p.telemetryBuilder.ExporterPrometheusremotewriteWalLag.Record(ctx, lag, metric.WithAttributes(p.otelAttrs...))
+}
+
+func (p *prwTelemetryOtel) recordWALReplayedSamples(ctx context.Context, numSamples int) {
+	p.telemetryBuilder.ExporterPrometheusremotewriteWalReplayedSamples.Add(ctx, int64(numSamples), metric.WithAttributes(p.otelAttrs...))
+}
+
+func (p *prwTelemetryOtel) recordWALDroppedRequests(ctx context.Context, numRequests int, reason string) {
+	p.telemetryBuilder.ExporterPrometheusremotewriteWalDroppedRequests.Add(ctx, int64(numRequests),
+		metric.WithAttributes(p.otelAttrs...), metric.WithAttributes(attribute.String("reason", reason)))
+}
+
 type buffer struct {
 	protobuf *proto.Buffer
 	snappy   []byte
@@ -68,6 +91,14 @@ var bufferPool = sync.Pool{
 	},
 }
 
+// writeRequest is a remote write request, either a *prompb.WriteRequest or a *writev2.Request.
+type writeRequest interface {
+	proto.Message
+}
+
+// errUnsupportedProtocol is returned when the remote write endpoint rejects the protocol of a request.
+var errUnsupportedProtocol = errors.New("unsupported remote write protocol")
+
 // prwExporter converts OTLP metrics to Prometheus remote write TimeSeries and sends them to a remote endpoint.
 type prwExporter struct {
 	endpointURL       *url.URL
@@ -84,6 +115,11 @@ type prwExporter struct {
 	wal               *prweWAL
 	exporterSettings  prometheusremotewrite.Settings
 	telemetry         prwTelemetry
+	protocol          string
+
+	// fallbackToV1 is set once the endpoint rejected a remote write 2.0 request, the
+	// following requests are sent using remote write 1.0.
+	fallbackToV1 atomic.Bool
 
 	// When concurrency is enabled, concurrent goroutines would potentially
 	// fight over the same batchState object. To avoid this, we use a pool
@@ -150,8 +186,11 @@ func newPRWExporter(cfg *Config, set exporter.Settings) (*prwExporter, error) {
 			ExportCreatedMetric: cfg.CreatedMetric.Enabled,
 			AddMetricSuffixes:   cfg.AddMetricSuffixes,
 			SendMetadata:        cfg.SendMetadata,
+
+			ConvertHistogramsToNHCB: cfg.ConvertHistogramsToNHCB,
 		},
 		telemetry:      prwTelemetry,
+		protocol:       cfg.Protocol,
 		batchStatePool: sync.Pool{New: func() any { return newBatchTimeServicesState() }},
 	}
 
@@ -159,7 +198,7 @@ func newPRWExporter(cfg *Config, set exporter.Settings) (*prwExporter, error) {
 		prwe.settings.Logger.Warn("export_created_metric is deprecated and will be removed in a future release")
 	}
 
-	prwe.wal = newWAL(cfg.WAL, prwe.export)
+	prwe.wal = newWAL(cfg.WAL, prwTelemetry, prwe.export)
 	return prwe, nil
 }
 
@@ -203,6 +242,9 @@ func (prwe *prwExporter) PushMetrics(ctx context.Context, md pmetric.Metrics) er
 	case <-prwe.closeChan:
 		return errors.New("shutdown has been called")
 	default:
+		if prwe.protocol == protocolV2 && !prwe.fallbackToV1.Load() {
+			return prwe.pushMetricsV2(ctx, md)
+		}
 
 		tsMap, err := prometheusremotewrite.FromMetrics(md, prwe.exporterSettings)
 		if err != nil {
@@ -222,6 +264,20 @@ func (prwe *prwExporter) PushMetrics(ctx context.Context, md pmetric.Metrics) er
 	}
 }
 
+// pushMetricsV2 converts metrics to Prometheus remote write 2.0 TimeSeries and sends them to the remote endpoint.
+func (prwe *prwExporter) pushMetricsV2(ctx context.Context, md pmetric.Metrics) error {
+	tsMap, symbolsTable, err := prometheusremotewrite.FromMetricsV2(md, prwe.exporterSettings)
+	if err != nil {
+		prwe.telemetry.recordTranslationFailure(ctx)
+		prwe.settings.Logger.Debug("failed to translate metrics, exporting remaining metrics", zap.Error(err), zap.Int("translated", len(tsMap)))
+	}
+
+	prwe.telemetry.recordTranslatedTimeSeries(ctx, len(tsMap))
+
+	// Call export even if a conversion error, since there may be points that were successfully converted.
+	return prwe.handleExportV2(ctx, tsMap, symbolsTable.Symbols())
+}
+
 func validateAndSanitizeExternalLabels(cfg *Config) (map[string]string, error) {
 	sanitizedLabels := make(map[string]string)
 	for key, value := range cfg.ExternalLabels {
@@ -247,6 +303,20 @@ func (prwe *prwExporter) handleExport(ctx context.Context, tsMap map[string]*pro
 	if err != nil {
 		return err
 	}
+	return prwe.exportOrPersist(ctx, toWriteRequests(requests))
+}
+
+func (prwe *prwExporter) handleExportV2(ctx context.Context, tsMap map[string]*writev2.TimeSeries, symbols []string) error {
+	// There are no metrics to export, so return.
+	if len(tsMap) == 0 {
+		return nil
+	}
+
+	requests := batchTimeSeriesV2(tsMap, symbols, prwe.maxBatchSizeBytes)
+	return prwe.exportOrPersist(ctx, toWriteRequests(requests))
+}
+
+func (prwe *prwExporter) exportOrPersist(ctx context.Context, requests []writeRequest) error {
 	if !prwe.walEnabled() {
 		// Perform a direct export otherwise.
 		return prwe.export(ctx, requests)
@@ -254,15 +324,23 @@ func (prwe *prwExporter) handleExport(ctx context.Context, tsMap map[string]*pro
 
 	// Otherwise the WAL is enabled, and just persist the requests to the WAL
 	// and they'll be exported in another goroutine to the RemoteWrite endpoint.
-	if err = prwe.wal.persistToWAL(requests); err != nil {
+	if err := prwe.wal.persistToWAL(ctx, requests); err != nil {
 		return consumererror.NewPermanent(err)
 	}
 	return nil
 }
 
+func toWriteRequests[T writeRequest](requests []T) []writeRequest {
+	out := make([]writeRequest, len(requests))
+	for i, req := range requests {
+		out[i] = req
+	}
+	return out
+}
+
 // export sends a Snappy-compressed WriteRequest containing TimeSeries to a remote write endpoint in order
-func (prwe *prwExporter) export(ctx context.Context, requests []*prompb.WriteRequest) error {
-	input := make(chan *prompb.WriteRequest, len(requests))
+func (prwe *prwExporter) export(ctx context.Context, requests []writeRequest) error {
+	input := make(chan writeRequest, len(requests))
 	for _, request := range requests {
 		input <- request
 	}
@@ -303,7 +381,31 @@ func (prwe *prwExporter) export(ctx context.Context, requests []*prompb.WriteReq
 	return errs
 }
 
-func (prwe *prwExporter) execute(ctx context.Context, writeReq *prompb.WriteRequest) error {
+// execute sends the request to the remote write endpoint. Remote write 2.0 requests are converted to
+// remote write 1.0 and sent again if the endpoint doesn't support remote write 2.0, and all the
+// following remote write 2.0 requests are converted.
+func (prwe *prwExporter) execute(ctx context.Context, writeReq writeRequest) error {
+	reqV2, isV2 := writeReq.(*writev2.Request)
+	if isV2 && prwe.fallbackToV1.Load() {
+		return prwe.send(ctx, convertRequestToV1(reqV2, prwe.exporterSettings.SendMetadata))
+	}
+
+	err := prwe.send(ctx, writeReq)
+	if isV2 && errors.Is(err, errUnsupportedProtocol) {
+		if prwe.fallbackToV1.CompareAndSwap(false, true) {
+			prwe.settings.Logger.Warn("remote write endpoint doesn't support remote write 2.0, falling back to remote write 1.0", zap.Error(err))
+		}
+		return prwe.send(ctx, convertRequestToV1(reqV2, prwe.exporterSettings.SendMetadata))
+	}
+	return err
+}
+
+func (prwe *prwExporter) send(ctx context.Context, writeReq writeRequest) error {
+	contentType, version := "application/x-protobuf", "0.1.0"
+	if _, ok := writeReq.(*writev2.Request); ok {
+		contentType, version = "application/x-protobuf;proto=io.prometheus.write.v2.Request", "2.0.0"
+	}
+
 	buf := bufferPool.Get().(*buffer)
 	buf.protobuf.Reset()
 	defer bufferPool.Put(buf)
@@ -345,8 +447,8 @@ func (prwe *prwExporter) execute(ctx context.Context, writeReq *prompb.WriteRequ
 		// Add necessary headers specified by:
 		// https://cortexmetrics.io/docs/apis/#remote-api
 		req.Header.Add("Content-Encoding", "snappy")
-		req.Header.Set("Content-Type", "application/x-protobuf")
-		req.Header.Set("X-Prometheus-Remote-Write-Version", "0.1.0")
+		req.Header.Set("Content-Type", contentType)
+		req.Header.Set("X-Prometheus-Remote-Write-Version", version)
 		req.Header.Set("User-Agent", prwe.userAgentHeader)
 
 		resp, err := prwe.client.Do(req)
@@ -369,6 +471,12 @@ func (prwe *prwExporter) execute(ctx context.Context, writeReq *prompb.WriteRequ
 			return rerr
 		}
 
+		// 415 errors mean the endpoint doesn't support the protocol of the request
+		// Reference: https://prometheus.io/docs/specs/remote_write_spec_2_0/#protocol
+		if resp.StatusCode == http.StatusUnsupportedMediaType {
+			return backoff.Permanent(fmt.Errorf("%w: %w", errUnsupportedProtocol, rerr))
+		}
+
 		// 429 errors are recoverable and the exporter should retry if RetryOnHTTP429 enabled
 		// Reference: https://github.com/prometheus/prometheus/pull/12677
 		if prwe.retryOnHTTP429 && resp.StatusCode == http.StatusTooManyRequests {
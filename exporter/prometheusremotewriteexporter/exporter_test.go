@@ -21,6 +21,7 @@ import (
 	"github.com/golang/snappy"
 	"github.com/prometheus/prometheus/model/value"
 	"github.com/prometheus/prometheus/prompb"
+	writev2 "github.com/prometheus/prometheus/prompb/io/prometheus/write/v2"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/component"
@@ -1074,6 +1075,98 @@ func TestWALOnExporterRoundTrip(t *testing.T) {
 	assert.Equal(t, gotFromWAL, gotFromUpload)
 }
 
+func TestPushMetricsV2(t *testing.T) {
+	tests := []struct {
+		name string
+		// status of the responses to remote write 2.0 requests
+		v2Status        int
+		wantV2Requests  int
+		wantV1Requests  int
+		wantFallbackSet bool
+	}{
+		{
+			name:           "remote write 2.0",
+			v2Status:       http.StatusNoContent,
+			wantV2Requests: 2,
+		},
+		{
+			name:            "fallback to remote write 1.0",
+			v2Status:        http.StatusUnsupportedMediaType,
+			wantV2Requests:  1,
+			wantV1Requests:  2,
+			wantFallbackSet: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var mu sync.Mutex
+			var v1Requests []*prompb.WriteRequest
+			var v2Requests []*writev2.Request
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, err := io.ReadAll(r.Body)
+				assert.NoError(t, err)
+				data, err := snappy.Decode(nil, body)
+				assert.NoError(t, err)
+
+				mu.Lock()
+				defer mu.Unlock()
+				switch r.Header.Get("Content-Type") {
+				case "application/x-protobuf;proto=io.prometheus.write.v2.Request":
+					assert.Equal(t, "2.0.0", r.Header.Get("X-Prometheus-Remote-Write-Version"))
+					req := &writev2.Request{}
+					assert.NoError(t, proto.Unmarshal(data, req))
+					v2Requests = append(v2Requests, req)
+					w.WriteHeader(tt.v2Status)
+				case "application/x-protobuf":
+					assert.Equal(t, "0.1.0", r.Header.Get("X-Prometheus-Remote-Write-Version"))
+					req := &prompb.WriteRequest{}
+					assert.NoError(t, proto.Unmarshal(data, req))
+					v1Requests = append(v1Requests, req)
+					w.WriteHeader(http.StatusNoContent)
+				default:
+					w.WriteHeader(http.StatusUnsupportedMediaType)
+				}
+			}))
+			defer server.Close()
+
+			clientConfig := confighttp.NewDefaultClientConfig()
+			clientConfig.Endpoint = server.URL
+			cfg := &Config{
+				ClientConfig:      clientConfig,
+				MaxBatchSizeBytes: 3000000,
+				RemoteWriteQueue:  RemoteWriteQueue{NumConsumers: 1},
+				TargetInfo:        &TargetInfo{Enabled: true},
+				CreatedMetric:     &CreatedMetric{Enabled: false},
+				Protocol:          protocolV2,
+			}
+			prwe, err := newPRWExporter(cfg, exportertest.NewNopSettings())
+			require.NoError(t, err)
+			ctx := context.Background()
+			require.NoError(t, prwe.Start(ctx, componenttest.NewNopHost()))
+			defer func() {
+				require.NoError(t, prwe.Shutdown(ctx))
+			}()
+
+			md := getMetricsFromMetricList(validMetrics1[validSum], validMetrics1[validIntGauge])
+			require.NoError(t, prwe.PushMetrics(ctx, md))
+			require.NoError(t, prwe.PushMetrics(ctx, md))
+
+			mu.Lock()
+			defer mu.Unlock()
+			assert.Len(t, v2Requests, tt.wantV2Requests)
+			assert.Len(t, v1Requests, tt.wantV1Requests)
+			assert.Equal(t, tt.wantFallbackSet, prwe.fallbackToV1.Load())
+			for _, req := range v2Requests {
+				assert.Len(t, req.Timeseries, 2)
+			}
+			for _, req := range v1Requests {
+				assert.Len(t, req.Timeseries, 2)
+			}
+		})
+	}
+}
+
 func canceledContext() context.Context {
 	ctx, cancel := context.WithCancel(context.Background())
 	cancel()
@@ -0,0 +1,225 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package prometheusremotewriteexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/prometheusremotewriteexporter"
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/histogram"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/model/value"
+	"github.com/prometheus/prometheus/prompb"
+	writev2 "github.com/prometheus/prometheus/prompb/io/prometheus/write/v2"
+)
+
+// batchTimeSeriesV2 splits series into multiple remote write 2.0 requests. Each request only holds
+// the symbols referenced by its series.
+func batchTimeSeriesV2(tsMap map[string]*writev2.TimeSeries, symbols []string, maxBatchByteSize int) []*writev2.Request {
+	var requests []*writev2.Request
+
+	symbolsTable := writev2.NewSymbolTable()
+	var tsArray []writev2.TimeSeries
+	sizeOfCurrentBatch := 0
+	for _, v := range tsMap {
+		symbolsBefore := len(symbolsTable.Symbols())
+		ts := resymbolizeTimeSeries(v, symbols, &symbolsTable)
+		sizeOfSeries := ts.Size() + symbolsSize(symbolsTable.Symbols()[symbolsBefore:])
+
+		if len(tsArray) > 0 && sizeOfCurrentBatch+sizeOfSeries >= maxBatchByteSize {
+			// The symbols only referenced by the current series are moved to the next batch.
+			requests = append(requests, &writev2.Request{
+				Symbols:    symbolsTable.Symbols()[:symbolsBefore],
+				Timeseries: tsArray,
+			})
+
+			symbolsTable = writev2.NewSymbolTable()
+			ts = resymbolizeTimeSeries(v, symbols, &symbolsTable)
+			sizeOfSeries = ts.Size() + symbolsSize(symbolsTable.Symbols())
+			tsArray = nil
+			sizeOfCurrentBatch = 0
+		}
+
+		tsArray = append(tsArray, ts)
+		sizeOfCurrentBatch += sizeOfSeries
+	}
+
+	if len(tsArray) != 0 {
+		requests = append(requests, &writev2.Request{
+			Symbols:    symbolsTable.Symbols(),
+			Timeseries: tsArray,
+		})
+	}
+	return requests
+}
+
+// resymbolizeTimeSeries returns a copy of ts referencing symbolsTable instead of symbols.
+func resymbolizeTimeSeries(ts *writev2.TimeSeries, symbols []string, symbolsTable *writev2.SymbolsTable) writev2.TimeSeries {
+	out := *ts
+	out.LabelsRefs = resymbolizeRefs(ts.LabelsRefs, symbols, symbolsTable)
+	if len(ts.Exemplars) > 0 {
+		out.Exemplars = make([]writev2.Exemplar, len(ts.Exemplars))
+		for i, e := range ts.Exemplars {
+			e.LabelsRefs = resymbolizeRefs(e.LabelsRefs, symbols, symbolsTable)
+			out.Exemplars[i] = e
+		}
+	}
+	out.Metadata.HelpRef = symbolsTable.Symbolize(symbols[ts.Metadata.HelpRef])
+	out.Metadata.UnitRef = symbolsTable.Symbolize(symbols[ts.Metadata.UnitRef])
+	return out
+}
+
+func resymbolizeRefs(refs []uint32, symbols []string, symbolsTable *writev2.SymbolsTable) []uint32 {
+	out := make([]uint32, len(refs))
+	for i, ref := range refs {
+		out[i] = symbolsTable.Symbolize(symbols[ref])
+	}
+	return out
+}
+
+// symbolsSize returns the encoded size of the symbols, assuming their length fits in two bytes.
+func symbolsSize(symbols []string) int {
+	size := 0
+	for _, s := range symbols {
+		size += len(s) + 3
+	}
+	return size
+}
+
+// convertRequestToV1 converts a remote write 2.0 request to remote write 1.0. Native histograms
+// with custom buckets are converted to classic histogram series, and created timestamps are dropped.
+// The metadata is only converted if sendMetadata is set.
+func convertRequestToV1(req *writev2.Request, sendMetadata bool) *prompb.WriteRequest {
+	b := labels.NewScratchBuilder(0)
+	out := &prompb.WriteRequest{
+		Timeseries: make([]prompb.TimeSeries, 0, len(req.Timeseries)),
+	}
+	metadata := map[string]prompb.MetricMetadata{}
+
+	for _, ts := range req.Timeseries {
+		ls := ts.ToLabels(&b, req.Symbols)
+		if sendMetadata {
+			addMetadataV1(metadata, ls.Get(model.MetricNameLabel), ts, req.Symbols)
+		}
+
+		seriesV1 := prompb.TimeSeries{
+			Labels:  prompb.FromLabels(ls, nil),
+			Samples: make([]prompb.Sample, 0, len(ts.Samples)),
+		}
+		for _, s := range ts.Samples {
+			seriesV1.Samples = append(seriesV1.Samples, prompb.Sample{Value: s.Value, Timestamp: s.Timestamp})
+		}
+		for _, e := range ts.Exemplars {
+			ex := e.ToExemplar(&b, req.Symbols)
+			seriesV1.Exemplars = append(seriesV1.Exemplars, prompb.Exemplar{
+				Labels:    prompb.FromLabels(ex.Labels, nil),
+				Value:     ex.Value,
+				Timestamp: ex.Ts,
+			})
+		}
+		for _, h := range ts.Histograms {
+			switch {
+			case h.Schema == histogram.CustomBucketsSchema:
+				out.Timeseries = append(out.Timeseries, customBucketsToClassicSeries(ls, h)...)
+			case h.IsFloatHistogram():
+				seriesV1.Histograms = append(seriesV1.Histograms, prompb.FromFloatHistogram(h.Timestamp, h.ToFloatHistogram()))
+			default:
+				seriesV1.Histograms = append(seriesV1.Histograms, prompb.FromIntHistogram(h.Timestamp, h.ToIntHistogram()))
+			}
+		}
+		if len(seriesV1.Samples) > 0 || len(seriesV1.Histograms) > 0 {
+			out.Timeseries = append(out.Timeseries, seriesV1)
+		}
+	}
+
+	if len(metadata) > 0 {
+		out.Metadata = make([]prompb.MetricMetadata, 0, len(metadata))
+		for _, m := range metadata {
+			out.Metadata = append(out.Metadata, m)
+		}
+		sort.Slice(out.Metadata, func(i, j int) bool {
+			return out.Metadata[i].MetricFamilyName < out.Metadata[j].MetricFamilyName
+		})
+	}
+	return out
+}
+
+// addMetadataV1 adds the metadata of the metric family of the series to metadata.
+func addMetadataV1(metadata map[string]prompb.MetricMetadata, name string, ts writev2.TimeSeries, symbols []string) {
+	family := name
+	switch ts.Metadata.Type {
+	case writev2.Metadata_METRIC_TYPE_HISTOGRAM, writev2.Metadata_METRIC_TYPE_GAUGEHISTOGRAM:
+		if len(ts.Histograms) == 0 {
+			family = trimSuffixes(name, "_bucket", "_sum", "_count")
+		}
+	case writev2.Metadata_METRIC_TYPE_SUMMARY:
+		family = trimSuffixes(name, "_sum", "_count")
+	}
+	if _, ok := metadata[family]; ok {
+		return
+	}
+	metadata[family] = prompb.MetricMetadata{
+		Type:             prompb.MetricMetadata_MetricType(ts.Metadata.Type),
+		MetricFamilyName: family,
+		Help:             symbols[ts.Metadata.HelpRef],
+		Unit:             symbols[ts.Metadata.UnitRef],
+	}
+}
+
+func trimSuffixes(name string, suffixes ...string) string {
+	for _, suffix := range suffixes {
+		if trimmed, ok := strings.CutSuffix(name, suffix); ok {
+			return trimmed
+		}
+	}
+	return name
+}
+
+// customBucketsToClassicSeries converts a native histogram with custom buckets to the bucket, sum
+// and count series of a classic histogram.
+func customBucketsToClassicSeries(ls labels.Labels, h writev2.Histogram) []prompb.TimeSeries {
+	fh := h.ToFloatHistogram()
+	name := ls.Get(model.MetricNameLabel)
+	stale := value.IsStaleNaN(fh.Sum)
+
+	series := func(v float64, name string, extras ...string) prompb.TimeSeries {
+		lb := labels.NewBuilder(ls)
+		lb.Set(model.MetricNameLabel, name)
+		for i := 0; i+1 < len(extras); i += 2 {
+			lb.Set(extras[i], extras[i+1])
+		}
+		if stale {
+			v = math.Float64frombits(value.StaleNaN)
+		}
+		return prompb.TimeSeries{
+			Labels:  prompb.FromLabels(lb.Labels(), nil),
+			Samples: []prompb.Sample{{Value: v, Timestamp: h.Timestamp}},
+		}
+	}
+
+	counts := make([]float64, len(fh.CustomValues)+1)
+	it := fh.PositiveBucketIterator()
+	for it.Next() {
+		bucket := it.At()
+		if int(bucket.Index) < len(counts) {
+			counts[bucket.Index] += bucket.Count
+		}
+	}
+
+	out := make([]prompb.TimeSeries, 0, len(counts)+2)
+	out = append(out,
+		series(fh.Sum, name+"_sum"),
+		series(fh.Count, name+"_count"),
+	)
+	var cumulative float64
+	for i, bound := range fh.CustomValues {
+		cumulative += counts[i]
+		out = append(out, series(cumulative, name+"_bucket", model.BucketLabel, strconv.FormatFloat(bound, 'f', -1, 64)))
+	}
+	out = append(out, series(fh.Count, name+"_bucket", model.BucketLabel, "+Inf"))
+	return out
+}
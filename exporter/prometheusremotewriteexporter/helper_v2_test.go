@@ -0,0 +1,169 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package prometheusremotewriteexporter
+
+import (
+	"math"
+	"testing"
+
+	"github.com/prometheus/prometheus/model/histogram"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/model/value"
+	"github.com/prometheus/prometheus/prompb"
+	writev2 "github.com/prometheus/prometheus/prompb/io/prometheus/write/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_batchTimeSeriesV2(t *testing.T) {
+	symbols := []string{"", "__name__", "a", "b", "help", "unit", "trace_id", "1234"}
+	tsMap := map[string]*writev2.TimeSeries{
+		"0": {
+			LabelsRefs: []uint32{1, 2},
+			Samples:    []writev2.Sample{{Value: 1, Timestamp: 10}},
+			Exemplars:  []writev2.Exemplar{{LabelsRefs: []uint32{6, 7}, Value: 1, Timestamp: 10}},
+			Metadata:   writev2.Metadata{Type: writev2.Metadata_METRIC_TYPE_COUNTER, HelpRef: 4, UnitRef: 5},
+		},
+		"1": {
+			LabelsRefs: []uint32{1, 3},
+			Samples:    []writev2.Sample{{Value: 2, Timestamp: 20}},
+			Metadata:   writev2.Metadata{Type: writev2.Metadata_METRIC_TYPE_GAUGE},
+		},
+	}
+
+	// All the series fit in a single request.
+	requests := batchTimeSeriesV2(tsMap, symbols, 3000000)
+	require.Len(t, requests, 1)
+	assert.ElementsMatch(t, []string{
+		`{__name__="a"}`,
+		`{__name__="b"}`,
+	}, seriesLabels(requests[0]))
+
+	// Each series is sent in its own request, with only the symbols it references.
+	requests = batchTimeSeriesV2(tsMap, symbols, 10)
+	require.Len(t, requests, 2)
+	for _, req := range requests {
+		require.Len(t, req.Timeseries, 1)
+		ts := req.Timeseries[0]
+		b := labels.NewScratchBuilder(0)
+		switch ts.ToLabels(&b, req.Symbols).Get("__name__") {
+		case "a":
+			assert.ElementsMatch(t, []string{"", "__name__", "a", "help", "unit", "trace_id", "1234"}, req.Symbols)
+			assert.Equal(t, "help", req.Symbols[ts.Metadata.HelpRef])
+			assert.Equal(t, "unit", req.Symbols[ts.Metadata.UnitRef])
+			assert.Equal(t, labels.FromStrings("trace_id", "1234"), ts.Exemplars[0].ToExemplar(&b, req.Symbols).Labels)
+		case "b":
+			assert.ElementsMatch(t, []string{"", "__name__", "b"}, req.Symbols)
+			assert.Equal(t, uint32(0), ts.Metadata.HelpRef)
+		default:
+			assert.Fail(t, "unexpected series")
+		}
+	}
+}
+
+func seriesLabels(req *writev2.Request) []string {
+	b := labels.NewScratchBuilder(0)
+	out := make([]string, 0, len(req.Timeseries))
+	for _, ts := range req.Timeseries {
+		out = append(out, ts.ToLabels(&b, req.Symbols).String())
+	}
+	return out
+}
+
+func Test_convertRequestToV1(t *testing.T) {
+	symbols := writev2.NewSymbolTable()
+	ref := func(lbls ...string) []uint32 {
+		return symbols.SymbolizeLabels(labels.FromStrings(lbls...), nil)
+	}
+	nhcb := &histogram.Histogram{
+		Schema:          histogram.CustomBucketsSchema,
+		Count:           5,
+		Sum:             20,
+		PositiveSpans:   []histogram.Span{{Offset: 1, Length: 1}, {Offset: 1, Length: 1}},
+		PositiveBuckets: []int64{2, 1},
+		CustomValues:    []float64{1, 5, 10},
+	}
+	exponential := &histogram.Histogram{
+		Schema:          0,
+		Count:           3,
+		Sum:             5,
+		PositiveSpans:   []histogram.Span{{Offset: 1, Length: 2}},
+		PositiveBuckets: []int64{1, 1},
+	}
+
+	req := &writev2.Request{
+		Timeseries: []writev2.TimeSeries{
+			{
+				LabelsRefs:       ref("__name__", "requests_total", "job", "api"),
+				Samples:          []writev2.Sample{{Value: 10, Timestamp: 100}},
+				Exemplars:        []writev2.Exemplar{{LabelsRefs: ref("trace_id", "1234"), Value: 1, Timestamp: 90}},
+				Metadata:         writev2.Metadata{Type: writev2.Metadata_METRIC_TYPE_COUNTER, HelpRef: symbols.Symbolize("number of requests")},
+				CreatedTimestamp: 50,
+			},
+			{
+				LabelsRefs: ref("__name__", "duration_sum"),
+				Samples:    []writev2.Sample{{Value: 3, Timestamp: 100}},
+				Metadata:   writev2.Metadata{Type: writev2.Metadata_METRIC_TYPE_SUMMARY},
+			},
+			{
+				LabelsRefs: ref("__name__", "latency", "job", "api"),
+				Histograms: []writev2.Histogram{writev2.FromIntHistogram(100, nhcb)},
+				Metadata:   writev2.Metadata{Type: writev2.Metadata_METRIC_TYPE_HISTOGRAM},
+			},
+			{
+				LabelsRefs: ref("__name__", "size"),
+				Histograms: []writev2.Histogram{writev2.FromIntHistogram(100, exponential)},
+				Metadata:   writev2.Metadata{Type: writev2.Metadata_METRIC_TYPE_HISTOGRAM},
+			},
+		},
+	}
+	req.Symbols = symbols.Symbols()
+
+	got := convertRequestToV1(req, true)
+
+	sample := func(v float64) []prompb.Sample {
+		return []prompb.Sample{{Value: v, Timestamp: 100}}
+	}
+	assert.ElementsMatch(t, []prompb.TimeSeries{
+		{
+			Labels:    getPromLabels("__name__", "requests_total", "job", "api"),
+			Samples:   sample(10),
+			Exemplars: []prompb.Exemplar{{Labels: getPromLabels("trace_id", "1234"), Value: 1, Timestamp: 90}},
+		},
+		{Labels: getPromLabels("__name__", "duration_sum"), Samples: sample(3)},
+		{Labels: getPromLabels("__name__", "latency_sum", "job", "api"), Samples: sample(20)},
+		{Labels: getPromLabels("__name__", "latency_count", "job", "api"), Samples: sample(5)},
+		{Labels: getPromLabels("__name__", "latency_bucket", "job", "api", "le", "1"), Samples: sample(0)},
+		{Labels: getPromLabels("__name__", "latency_bucket", "job", "api", "le", "5"), Samples: sample(2)},
+		{Labels: getPromLabels("__name__", "latency_bucket", "job", "api", "le", "10"), Samples: sample(2)},
+		{Labels: getPromLabels("__name__", "latency_bucket", "job", "api", "le", "+Inf"), Samples: sample(5)},
+		{
+			Labels:     getPromLabels("__name__", "size"),
+			Samples:    []prompb.Sample{},
+			Histograms: []prompb.Histogram{prompb.FromIntHistogram(100, exponential)},
+		},
+	}, got.Timeseries)
+	assert.Equal(t, []prompb.MetricMetadata{
+		{Type: prompb.MetricMetadata_SUMMARY, MetricFamilyName: "duration"},
+		{Type: prompb.MetricMetadata_HISTOGRAM, MetricFamilyName: "latency"},
+		{Type: prompb.MetricMetadata_COUNTER, MetricFamilyName: "requests_total", Help: "number of requests"},
+		{Type: prompb.MetricMetadata_HISTOGRAM, MetricFamilyName: "size"},
+	}, got.Metadata)
+
+	assert.Empty(t, convertRequestToV1(req, false).Metadata)
+}
+
+func Test_customBucketsToClassicSeriesStale(t *testing.T) {
+	h := writev2.FromIntHistogram(100, &histogram.Histogram{
+		Schema:       histogram.CustomBucketsSchema,
+		Count:        value.StaleNaN,
+		Sum:          math.Float64frombits(value.StaleNaN),
+		CustomValues: []float64{1},
+	})
+	series := customBucketsToClassicSeries(labels.FromStrings("__name__", "latency"), h)
+	require.Len(t, series, 4)
+	for _, ts := range series {
+		assert.True(t, value.IsStaleNaN(ts.Samples[0].Value))
+	}
+}
@@ -27,6 +27,10 @@ type TelemetryBuilder struct {
 	meter                                             metric.Meter
 	ExporterPrometheusremotewriteFailedTranslations   metric.Int64Counter
 	ExporterPrometheusremotewriteTranslatedTimeSeries metric.Int64Counter
+	ExporterPrometheusremotewriteWalBytes             metric.Int64Gauge
+	ExporterPrometheusremotewriteWalDroppedRequests   metric.Int64Counter
+	ExporterPrometheusremotewriteWalLag               metric.Int64Gauge
+	ExporterPrometheusremotewriteWalReplayedSamples   metric.Int64Counter
 }
 
 // TelemetryBuilderOption applies changes to default builder.
@@ -61,6 +65,30 @@ func NewTelemetryBuilder(settings component.TelemetrySettings, options ...Teleme
 		metric.WithUnit("1"),
 	)
 	errs = errors.Join(errs, err)
+	builder.ExporterPrometheusremotewriteWalBytes, err = getLeveledMeter(builder.meter, configtelemetry.LevelBasic, settings.MetricsLevel).Int64Gauge(
+		"otelcol_exporter_prometheusremotewrite_wal_bytes",
+		metric.WithDescription("Size of the write-ahead log on disk"),
+		metric.WithUnit("By"),
+	)
+	errs = errors.Join(errs, err)
+	builder.ExporterPrometheusremotewriteWalDroppedRequests, err = getLeveledMeter(builder.meter, configtelemetry.LevelBasic, settings.MetricsLevel).Int64Counter(
+		"otelcol_exporter_prometheusremotewrite_wal_dropped_requests",
+		metric.WithDescription("Number of write requests dropped from the write-ahead log because it exceeded its maximum size or age"),
+		metric.WithUnit("1"),
+	)
+	errs = errors.Join(errs, err)
+	builder.ExporterPrometheusremotewriteWalLag, err = getLeveledMeter(builder.meter, configtelemetry.LevelBasic, settings.MetricsLevel).Int64Gauge(
+		"otelcol_exporter_prometheusremotewrite_wal_lag",
+		metric.WithDescription("Number of write requests in the write-ahead log which have not been read for export yet"),
+		metric.WithUnit("1"),
+	)
+	errs = errors.Join(errs, err)
+	builder.ExporterPrometheusremotewriteWalReplayedSamples, err = getLeveledMeter(builder.meter, configtelemetry.LevelBasic, settings.MetricsLevel).Int64Counter(
+		"otelcol_exporter_prometheusremotewrite_wal_replayed_samples",
+		metric.WithDescription("Number of samples and histograms read from the write-ahead log and exported"),
+		metric.WithUnit("1"),
+	)
+	errs = errors.Join(errs, err)
 	return &builder, errs
 }
 
@@ -22,6 +22,10 @@ func TestSetupTelemetry(t *testing.T) {
 	require.NotNil(t, tb)
 	tb.ExporterPrometheusremotewriteFailedTranslations.Add(context.Background(), 1)
 	tb.ExporterPrometheusremotewriteTranslatedTimeSeries.Add(context.Background(), 1)
+	tb.ExporterPrometheusremotewriteWalBytes.Record(context.Background(), 1)
+	tb.ExporterPrometheusremotewriteWalDroppedRequests.Add(context.Background(), 1)
+	tb.ExporterPrometheusremotewriteWalLag.Record(context.Background(), 1)
+	tb.ExporterPrometheusremotewriteWalReplayedSamples.Add(context.Background(), 1)
 
 	testTel.AssertMetrics(t, []metricdata.Metrics{
 		{
@@ -48,6 +52,50 @@ func TestSetupTelemetry(t *testing.T) {
 				},
 			},
 		},
+		{
+			Name:        "otelcol_exporter_prometheusremotewrite_wal_bytes",
+			Description: "Size of the write-ahead log on disk",
+			Unit:        "By",
+			Data: metricdata.Gauge[int64]{
+				DataPoints: []metricdata.DataPoint[int64]{
+					{},
+				},
+			},
+		},
+		{
+			Name:        "otelcol_exporter_prometheusremotewrite_wal_dropped_requests",
+			Description: "Number of write requests dropped from the write-ahead log because it exceeded its maximum size or age",
+			Unit:        "1",
+			Data: metricdata.Sum[int64]{
+				Temporality: metricdata.CumulativeTemporality,
+				IsMonotonic: true,
+				DataPoints: []metricdata.DataPoint[int64]{
+					{},
+				},
+			},
+		},
+		{
+			Name:        "otelcol_exporter_prometheusremotewrite_wal_lag",
+			Description: "Number of write requests in the write-ahead log which have not been read for export yet",
+			Unit:        "1",
+			Data: metricdata.Gauge[int64]{
+				DataPoints: []metricdata.DataPoint[int64]{
+					{},
+				},
+			},
+		},
+		{
+			Name:        "otelcol_exporter_prometheusremotewrite_wal_replayed_samples",
+			Description: "Number of samples and histograms read from the write-ahead log and exported",
+			Unit:        "1",
+			Data: metricdata.Sum[int64]{
+				Temporality: metricdata.CumulativeTemporality,
+				IsMonotonic: true,
+				DataPoints: []metricdata.DataPoint[int64]{
+					{},
+				},
+			},
+		},
 	}, metricdatatest.IgnoreTimestamp(), metricdatatest.IgnoreValue())
 	require.NoError(t, testTel.Shutdown(context.Background()))
 }
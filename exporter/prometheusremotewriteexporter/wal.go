@@ -7,6 +7,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"os"
 	"path/filepath"
 	"sync"
 	"sync/atomic"
@@ -15,9 +16,11 @@ import (
 	"github.com/fsnotify/fsnotify"
 	"github.com/gogo/protobuf/proto"
 	"github.com/prometheus/prometheus/prompb"
+	writev2 "github.com/prometheus/prometheus/prompb/io/prometheus/write/v2"
 	"github.com/tidwall/wal"
 	"go.uber.org/multierr"
 	"go.uber.org/zap"
+	"golang.org/x/time/rate"
 )
 
 type prweWAL struct {
@@ -26,7 +29,10 @@ type prweWAL struct {
 	walConfig *WALConfig
 	walPath   string
 
-	exportSink func(ctx context.Context, reqL []*prompb.WriteRequest) error
+	exportSink func(ctx context.Context, reqL []writeRequest) error
+	telemetry  prwTelemetry
+	// limiter paces the samples read from the WAL, it is nil if the replay isn't rate limited.
+	limiter *rate.Limiter
 
 	stopOnce  sync.Once
 	stopChan  chan struct{}
@@ -35,14 +41,44 @@ type prweWAL struct {
 }
 
 const (
+	// walEntryV2 prefixes the WAL entries holding remote write 2.0 requests. Remote write 1.0
+	// requests are stored as is for compatibility with existing WALs, a valid protobuf
+	// message cannot start with a zero byte.
+	walEntryV2 byte = 0x00
+
 	defaultWALBufferSize        = 300
 	defaultWALTruncateFrequency = 1 * time.Minute
+
+	walDropReasonSize = "size"
+	walDropReasonAge  = "age"
 )
 
 type WALConfig struct {
 	Directory         string        `mapstructure:"directory"`
 	BufferSize        int           `mapstructure:"buffer_size"`
 	TruncateFrequency time.Duration `mapstructure:"truncate_frequency"`
+	// MaxSizeBytes is the maximum size of the WAL on disk. When exceeded, the oldest requests
+	// are dropped. Zero means no limit.
+	MaxSizeBytes int64 `mapstructure:"max_size_bytes"`
+	// MaxAge is the maximum age of the newest sample of a request read from the WAL. Older
+	// requests are dropped instead of being exported. Zero means no limit.
+	MaxAge time.Duration `mapstructure:"max_age"`
+	// ReplaySamplesPerSecond limits the rate at which samples are read from the WAL and
+	// exported. Zero means no limit.
+	ReplaySamplesPerSecond int `mapstructure:"replay_samples_per_second"`
+}
+
+func (wc *WALConfig) validate() error {
+	if wc.MaxSizeBytes < 0 {
+		return fmt.Errorf("wal max_size_bytes can't be negative")
+	}
+	if wc.MaxAge < 0 {
+		return fmt.Errorf("wal max_age can't be negative")
+	}
+	if wc.ReplaySamplesPerSecond < 0 {
+		return fmt.Errorf("wal replay_samples_per_second can't be negative")
+	}
+	return nil
 }
 
 func (wc *WALConfig) bufferSize() int {
@@ -59,15 +95,22 @@ func (wc *WALConfig) truncateFrequency() time.Duration {
 	return defaultWALTruncateFrequency
 }
 
-func newWAL(walConfig *WALConfig, exportSink func(context.Context, []*prompb.WriteRequest) error) *prweWAL {
+func newWAL(walConfig *WALConfig, telemetry prwTelemetry, exportSink func(context.Context, []writeRequest) error) *prweWAL {
 	if walConfig == nil {
 		// There are cases for which the WAL can be disabled.
 		// TODO: Perhaps log that the WAL wasn't enabled.
 		return nil
 	}
 
+	var limiter *rate.Limiter
+	if walConfig.ReplaySamplesPerSecond > 0 {
+		limiter = rate.NewLimiter(rate.Limit(walConfig.ReplaySamplesPerSecond), walConfig.ReplaySamplesPerSecond)
+	}
+
 	return &prweWAL{
 		exportSink: exportSink,
+		telemetry:  telemetry,
+		limiter:    limiter,
 		walConfig:  walConfig,
 		stopChan:   make(chan struct{}),
 		rWALIndex:  &atomic.Uint64{},
@@ -114,7 +157,11 @@ func (prwe *prweWAL) retrieveWALIndices() (err error) {
 	if err != nil {
 		return fmt.Errorf("prometheusremotewriteexporter: failed to retrieve the first WAL index: %w", err)
 	}
-	prwe.rWALIndex.Store(rIndex)
+	// The newest entry can't be truncated from the WAL, the read index is kept when it is
+	// past the first index so that the entries already read aren't exported again.
+	if rIndex > prwe.rWALIndex.Load() {
+		prwe.rWALIndex.Store(rIndex)
+	}
 
 	wIndex, err := prwe.wal.LastIndex()
 	if err != nil {
@@ -181,17 +228,17 @@ func (prwe *prweWAL) run(ctx context.Context) (err error) {
 	return nil
 }
 
-// continuallyPopWALThenExport reads a prompb.WriteRequest proto encoded blob from the WAL, and moves
+// continuallyPopWALThenExport reads a write request proto encoded blob from the WAL, and moves
 // the WAL's front index forward until either the read buffer period expires or the maximum
 // buffer size is exceeded. When either of the two conditions are matched, it then exports
 // the requests to the Remote-Write endpoint, and then truncates the head of the WAL to where
 // it last read from.
 func (prwe *prweWAL) continuallyPopWALThenExport(ctx context.Context, signalStart func()) (err error) {
-	var reqL []*prompb.WriteRequest
+	var reqL []writeRequest
 	defer func() {
 		// Keeping it within a closure to ensure that the later
 		// updated value of reqL is always flushed to disk.
-		if errL := prwe.exportSink(ctx, reqL); errL != nil {
+		if errL := prwe.exportRequests(ctx, reqL); errL != nil {
 			err = multierr.Append(err, errL)
 		}
 	}()
@@ -219,12 +266,20 @@ func (prwe *prweWAL) continuallyPopWALThenExport(ctx context.Context, signalStar
 		default:
 		}
 
-		var req *prompb.WriteRequest
+		var req writeRequest
 		req, err = prwe.readPrompbFromWAL(ctx, prwe.rWALIndex.Load())
 		if err != nil {
 			return err
 		}
-		reqL = append(reqL, req)
+		samples, newest := requestSamples(req)
+		if prwe.tooOld(samples, newest) {
+			prwe.telemetry.recordWALDroppedRequests(ctx, 1, walDropReasonAge)
+		} else {
+			if err = prwe.waitForReplay(ctx, samples); err != nil {
+				return err
+			}
+			reqL = append(reqL, req)
+		}
 
 		var shouldExport bool
 		select {
@@ -279,7 +334,7 @@ func (prwe *prweWAL) syncAndTruncateFront() error {
 	return nil
 }
 
-func (prwe *prweWAL) exportThenFrontTruncateWAL(ctx context.Context, reqL []*prompb.WriteRequest) error {
+func (prwe *prweWAL) exportThenFrontTruncateWAL(ctx context.Context, reqL []writeRequest) error {
 	if len(reqL) == 0 {
 		return nil
 	}
@@ -287,27 +342,102 @@ func (prwe *prweWAL) exportThenFrontTruncateWAL(ctx context.Context, reqL []*pro
 		return nil
 	}
 
-	if errL := prwe.exportSink(ctx, reqL); errL != nil {
+	if errL := prwe.exportRequests(ctx, reqL); errL != nil {
 		return errL
 	}
 	if err := prwe.syncAndTruncateFront(); err != nil {
 		return err
 	}
 	// Reset by retrieving the respective read and write WAL indices.
-	return prwe.retrieveWALIndices()
+	if err := prwe.retrieveWALIndices(); err != nil {
+		return err
+	}
+
+	prwe.mu.Lock()
+	defer prwe.mu.Unlock()
+	return prwe.recordWALState(ctx)
+}
+
+// exportRequests exports the requests read from the WAL and records the number of replayed samples.
+func (prwe *prweWAL) exportRequests(ctx context.Context, reqL []writeRequest) error {
+	if err := prwe.exportSink(ctx, reqL); err != nil {
+		return err
+	}
+	var samples int
+	for _, req := range reqL {
+		n, _ := requestSamples(req)
+		samples += n
+	}
+	if samples > 0 {
+		prwe.telemetry.recordWALReplayedSamples(ctx, samples)
+	}
+	return nil
+}
+
+// tooOld reports whether a request whose newest sample has the timestamp newest is older than
+// the maximum age of the WAL. Requests without samples are never too old.
+func (prwe *prweWAL) tooOld(samples int, newest int64) bool {
+	if prwe.walConfig.MaxAge <= 0 || samples == 0 {
+		return false
+	}
+	return time.Since(time.UnixMilli(newest)) > prwe.walConfig.MaxAge
+}
+
+// waitForReplay blocks until the rate limiter allows the replay of the given number of samples.
+func (prwe *prweWAL) waitForReplay(ctx context.Context, samples int) error {
+	if prwe.limiter == nil {
+		return nil
+	}
+	// WaitN fails for more events than the burst, so large requests are waited for in chunks.
+	for samples > 0 {
+		n := min(samples, prwe.limiter.Burst())
+		if err := prwe.limiter.WaitN(ctx, n); err != nil {
+			return err
+		}
+		samples -= n
+	}
+	return nil
+}
+
+// requestSamples returns the number of samples and histograms of a request and the timestamp
+// of the newest one, in milliseconds.
+func requestSamples(req writeRequest) (count int, newest int64) {
+	switch req := req.(type) {
+	case *prompb.WriteRequest:
+		for _, ts := range req.Timeseries {
+			count += len(ts.Samples) + len(ts.Histograms)
+			for _, s := range ts.Samples {
+				newest = max(newest, s.Timestamp)
+			}
+			for _, h := range ts.Histograms {
+				newest = max(newest, h.Timestamp)
+			}
+		}
+	case *writev2.Request:
+		for _, ts := range req.Timeseries {
+			count += len(ts.Samples) + len(ts.Histograms)
+			for _, s := range ts.Samples {
+				newest = max(newest, s.Timestamp)
+			}
+			for _, h := range ts.Histograms {
+				newest = max(newest, h.Timestamp)
+			}
+		}
+	}
+	return count, newest
 }
 
 // persistToWAL is the routine that'll be hooked into the exporter's receiving side and it'll
 // write them to the Write-Ahead-Log so that shutdowns won't lose data, and that the routine that
 // reads from the WAL can then process the previously serialized requests.
-func (prwe *prweWAL) persistToWAL(requests []*prompb.WriteRequest) error {
+func (prwe *prweWAL) persistToWAL(ctx context.Context, requests []writeRequest) error {
 	prwe.mu.Lock()
 	defer prwe.mu.Unlock()
 
 	// Write all the requests to the WAL in a batch.
 	batch := new(wal.Batch)
 	for _, req := range requests {
-		protoBlob, err := proto.Marshal(req)
+		protoBlob, err := marshalWALEntry(req)
 		if err != nil {
 			return err
 		}
@@ -315,14 +445,130 @@ func (prwe *prweWAL) persistToWAL(requests []*prompb.WriteRequest) error {
 		batch.Write(wIndex, protoBlob)
 	}
 
-	return prwe.wal.WriteBatch(batch)
+	if err := prwe.wal.WriteBatch(batch); err != nil {
+		return err
+	}
+	if err := prwe.enforceMaxSize(ctx); err != nil {
+		return err
+	}
+	return prwe.recordWALState(ctx)
 }
 
-func (prwe *prweWAL) readPrompbFromWAL(ctx context.Context, index uint64) (wreq *prompb.WriteRequest, err error) {
-	prwe.mu.Lock()
-	defer prwe.mu.Unlock()
+// enforceMaxSize drops the oldest entries of the WAL until its size on disk is below the
+// configured maximum. The newest entry is always kept. It must be called with mu held.
+func (prwe *prweWAL) enforceMaxSize(ctx context.Context) error {
+	maxSize := prwe.walConfig.MaxSizeBytes
+	if maxSize <= 0 {
+		return nil
+	}
+
+	for {
+		size, err := prwe.sizeOnDisk()
+		if err != nil {
+			return err
+		}
+		if size <= maxSize {
+			return nil
+		}
+		first, err := prwe.wal.FirstIndex()
+		if err != nil {
+			return err
+		}
+		last, err := prwe.wal.LastIndex()
+		if err != nil {
+			return err
+		}
+		if first >= last {
+			return nil
+		}
+
+		// Estimate the number of entries to drop from their average size.
+		avgSize := max(size/int64(last-first+1), 1)
+		newFirst := min(first+uint64((size-maxSize)/avgSize)+1, last)
+		if err = prwe.wal.TruncateFront(newFirst); err != nil {
+			return err
+		}
 
-	var protoBlob []byte
+		// Only the entries which haven't been read yet are lost, the other ones are being exported.
+		if rIndex := max(prwe.rWALIndex.Load(), first); newFirst > rIndex {
+			prwe.telemetry.recordWALDroppedRequests(ctx, int(newFirst-rIndex), walDropReasonSize)
+			prwe.rWALIndex.Store(newFirst)
+		}
+	}
+}
+
+// sizeOnDisk returns the size of the WAL segments. It must be called with mu held.
+func (prwe *prweWAL) sizeOnDisk() (int64, error) {
+	entries, err := os.ReadDir(prwe.walPath)
+	if err != nil {
+		return 0, err
+	}
+	var size int64
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+			return 0, err
+		}
+		if info.Mode().IsRegular() {
+			size += info.Size()
+		}
+	}
+	return size, nil
+}
+
+// recordWALState records the size of the WAL and the number of entries which haven't been read
+// yet. It must be called with mu held.
+func (prwe *prweWAL) recordWALState(ctx context.Context) error {
+	if prwe.wal == nil {
+		return nil
+	}
+	size, err := prwe.sizeOnDisk()
+	if err != nil {
+		return err
+	}
+	last, err := prwe.wal.LastIndex()
+	if err != nil {
+		return err
+	}
+	var lag int64
+	if rIndex := max(prwe.rWALIndex.Load(), 1); last >= rIndex {
+		lag = int64(last - rIndex + 1)
+	}
+	prwe.telemetry.recordWALBytes(ctx, size)
+	prwe.telemetry.recordWALLag(ctx, lag)
+	return nil
+}
+
+func marshalWALEntry(req writeRequest) ([]byte, error) {
+	protoBlob, err := proto.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := req.(*writev2.Request); ok {
+		return append([]byte{walEntryV2}, protoBlob...), nil
+	}
+	return protoBlob, nil
+}
+
+func unmarshalWALEntry(protoBlob []byte) (writeRequest, error) {
+	if len(protoBlob) > 0 && protoBlob[0] == walEntryV2 {
+		req := new(writev2.Request)
+		if err := proto.Unmarshal(protoBlob[1:], req); err != nil {
+			return nil, err
+		}
+		return req, nil
+	}
+	req := new(prompb.WriteRequest)
+	if err := proto.Unmarshal(protoBlob, req); err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+func (prwe *prweWAL) readPrompbFromWAL(ctx context.Context, index uint64) (wreq writeRequest, err error) {
 	for i := 0; i < 12; i++ {
 		// Firstly check if we've been terminated, then exit if so.
 		select {
@@ -337,20 +583,10 @@ func (prwe *prweWAL) readPrompbFromWAL(ctx context.Context, index uint64) (wreq
 			index = 1
 		}
 
-		if prwe.wal == nil {
-			return nil, fmt.Errorf("attempt to read from closed WAL")
-		}
-
-		protoBlob, err = prwe.wal.Read(index)
+		var req writeRequest
+		var walPath string
+		req, walPath, err = prwe.readEntry(index)
 		if err == nil { // The read succeeded.
-			req := new(prompb.WriteRequest)
-			if err = proto.Unmarshal(protoBlob, req); err != nil {
-				return nil, err
-			}
-
-			// Now increment the WAL's read index.
-			prwe.rWALIndex.Add(1)
-
 			return req, nil
 		}
 
@@ -366,59 +602,89 @@ func (prwe *prweWAL) readPrompbFromWAL(ctx context.Context, index uint64) (wreq
 
 		// Otherwise, we couldn't find the record, let's try watching
 		// the WAL file until perhaps there is a write to it.
-		walWatcher, werr := fsnotify.NewWatcher()
-		if werr != nil {
-			return nil, werr
-		}
-		if werr = walWatcher.Add(prwe.walPath); werr != nil {
+		if werr := prwe.waitForWrite(ctx, walPath, err); werr != nil {
 			return nil, werr
 		}
 
-		// Watch until perhaps there is a write to the WAL file.
-		watchCh := make(chan error)
-		wErr := err
-		go func() {
-			defer func() {
-				watchCh <- wErr
-				close(watchCh)
-				// Close the file watcher.
-				walWatcher.Close()
-			}()
+		// Otherwise a write occurred might have occurred,
+		// and we can sleep for a little bit then try again.
+		time.Sleep(time.Duration(1<<i) * time.Millisecond)
+	}
+	return nil, err
+}
+
+// readEntry reads the entry at index and moves the read index past it. The mutex is only held
+// while reading, so that the requests can be persisted while waiting for a write.
+func (prwe *prweWAL) readEntry(index uint64) (writeRequest, string, error) {
+	prwe.mu.Lock()
+	defer prwe.mu.Unlock()
 
-			select {
-			case <-ctx.Done(): // If the context was cancelled, bail out ASAP.
-				wErr = ctx.Err()
-				return
+	if prwe.wal == nil {
+		return nil, "", fmt.Errorf("attempt to read from closed WAL")
+	}
 
-			case event, ok := <-walWatcher.Events:
-				if !ok {
-					return
-				}
-				switch event.Op {
-				case fsnotify.Remove:
-					// The file got deleted.
-					// TODO: Add capabilities to search for the updated file.
-				case fsnotify.Rename:
-					// Renamed, we don't have information about the renamed file's new name.
-				case fsnotify.Write:
-					// Finally a write, let's try reading again, but after some watch.
-					wErr = nil
-				}
+	// The oldest entries might have been dropped since the index was loaded.
+	if first, ferr := prwe.wal.FirstIndex(); ferr == nil && index < first {
+		index = first
+	}
 
-			case eerr, ok := <-walWatcher.Errors:
-				if ok {
-					wErr = eerr
-				}
+	protoBlob, err := prwe.wal.Read(index)
+	if err != nil {
+		return nil, prwe.walPath, err
+	}
+	req, err := unmarshalWALEntry(protoBlob)
+	if err != nil {
+		return nil, prwe.walPath, err
+	}
+
+	// Now move the WAL's read index past the entry.
+	prwe.rWALIndex.Store(index + 1)
+	return req, prwe.walPath, nil
+}
+
+// waitForWrite watches the WAL directory until there is a write to it. Writes happening
+// before the watch started are caught up with by giving up after the truncate frequency.
+func (prwe *prweWAL) waitForWrite(ctx context.Context, walPath string, readErr error) error {
+	walWatcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	defer walWatcher.Close()
+	if err = walWatcher.Add(walPath); err != nil {
+		return err
+	}
+
+	timer := time.NewTimer(prwe.walConfig.truncateFrequency())
+	defer timer.Stop()
+
+	// Watch until perhaps there is a write to the WAL file.
+	for {
+		select {
+		case <-ctx.Done(): // If the context was cancelled, bail out ASAP.
+			return ctx.Err()
+		case <-prwe.stopChan:
+			return fmt.Errorf("attempt to read from WAL after stopped")
+		case <-timer.C:
+			return nil
+
+		case event, ok := <-walWatcher.Events:
+			if !ok {
+				return readErr
+			}
+			switch {
+			case event.Has(fsnotify.Write), event.Has(fsnotify.Create):
+				// Finally a write, let's try reading again, but after some watch.
+				return nil
+			default:
+				// The file got deleted or renamed, we don't have information about the new file.
+				return readErr
 			}
-		}()
 
-		if gerr := <-watchCh; gerr != nil {
-			return nil, gerr
+		case werr, ok := <-walWatcher.Errors:
+			if ok {
+				return werr
+			}
+			return readErr
 		}
-
-		// Otherwise a write occurred might have occurred,
-		// and we can sleep for a little bit then try again.
-		time.Sleep(time.Duration(1<<i) * time.Millisecond)
 	}
-	return nil, err
 }
@@ -5,29 +5,79 @@ package prometheusremotewriteexporter
 
 import (
 	"context"
+	"fmt"
 	"sort"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/prometheus/prometheus/prompb"
+	writev2 "github.com/prometheus/prometheus/prompb/io/prometheus/write/v2"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
 )
 
-func doNothingExportSink(_ context.Context, reqL []*prompb.WriteRequest) error {
+func doNothingExportSink(_ context.Context, reqL []writeRequest) error {
 	_ = reqL
 	return nil
 }
 
+// mockPRWTelemetry records the WAL telemetry.
+type mockPRWTelemetry struct {
+	mu              sync.Mutex
+	walBytes        int64
+	walLag          int64
+	replayedSamples int
+	droppedRequests map[string]int
+}
+
+func (m *mockPRWTelemetry) recordTranslationFailure(_ context.Context) {}
+
+func (m *mockPRWTelemetry) recordTranslatedTimeSeries(_ context.Context, _ int) {}
+
+func (m *mockPRWTelemetry) recordWALBytes(_ context.Context, size int64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.walBytes = size
+}
+
+func (m *mockPRWTelemetry) recordWALLag(_ context.Context, lag int64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.walLag = lag
+}
+
+func (m *mockPRWTelemetry) recordWALReplayedSamples(_ context.Context, numSamples int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.replayedSamples += numSamples
+}
+
+func (m *mockPRWTelemetry) recordWALDroppedRequests(_ context.Context, numRequests int, reason string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.droppedRequests == nil {
+		m.droppedRequests = map[string]int{}
+	}
+	m.droppedRequests[reason] += numRequests
+}
+
+func (m *mockPRWTelemetry) dropped(reason string) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.droppedRequests[reason]
+}
+
 func TestWALCreation_nilConfig(t *testing.T) {
 	config := (*WALConfig)(nil)
-	pwal := newWAL(config, doNothingExportSink)
+	pwal := newWAL(config, &mockPRWTelemetry{}, doNothingExportSink)
 	require.Nil(t, pwal)
 }
 
 func TestWALCreation_nonNilConfig(t *testing.T) {
 	config := &WALConfig{Directory: t.TempDir()}
-	pwal := newWAL(config, doNothingExportSink)
+	pwal := newWAL(config, &mockPRWTelemetry{}, doNothingExportSink)
 	require.NotNil(t, pwal)
 	assert.NoError(t, pwal.stop())
 }
@@ -78,7 +128,7 @@ func TestWALStopManyTimes(t *testing.T) {
 		TruncateFrequency: 60 * time.Microsecond,
 		BufferSize:        1,
 	}
-	pwal := newWAL(config, doNothingExportSink)
+	pwal := newWAL(config, &mockPRWTelemetry{}, doNothingExportSink)
 	require.NotNil(t, pwal)
 
 	// Ensure that invoking .stop() multiple times doesn't cause a panic, but actually
@@ -94,7 +144,7 @@ func TestWAL_persist(t *testing.T) {
 	// Unit tests that requests written to the WAL persist.
 	config := &WALConfig{Directory: t.TempDir()}
 
-	pwal := newWAL(config, doNothingExportSink)
+	pwal := newWAL(config, &mockPRWTelemetry{}, doNothingExportSink)
 	require.NotNil(t, pwal)
 
 	// 1. Write out all the entries.
@@ -127,7 +177,7 @@ func TestWAL_persist(t *testing.T) {
 		assert.NoError(t, pwal.stop())
 	})
 
-	require.NoError(t, pwal.persistToWAL(reqL))
+	require.NoError(t, pwal.persistToWAL(ctx, toWriteRequests(reqL)))
 
 	// 2. Read all the entries from the WAL itself, guided by the indices available,
 	// and ensure that they are exactly in order as we'd expect them.
@@ -141,7 +191,7 @@ func TestWAL_persist(t *testing.T) {
 	for i := start; i <= end; i++ {
 		req, err := pwal.readPrompbFromWAL(ctx, i)
 		require.NoError(t, err)
-		reqLFromWAL = append(reqLFromWAL, req)
+		reqLFromWAL = append(reqLFromWAL, req.(*prompb.WriteRequest))
 	}
 
 	orderByLabelValueForEach(reqL)
@@ -149,3 +199,169 @@ func TestWAL_persist(t *testing.T) {
 	require.Equal(t, reqLFromWAL[0], reqL[0])
 	require.Equal(t, reqLFromWAL[1], reqL[1])
 }
+
+func TestWAL_persistMixedProtocols(t *testing.T) {
+	config := &WALConfig{Directory: t.TempDir()}
+
+	pwal := newWAL(config, &mockPRWTelemetry{}, doNothingExportSink)
+	require.NotNil(t, pwal)
+	require.NoError(t, pwal.retrieveWALIndices())
+	t.Cleanup(func() {
+		assert.NoError(t, pwal.stop())
+	})
+
+	reqL := []writeRequest{
+		&prompb.WriteRequest{
+			Timeseries: []prompb.TimeSeries{
+				{
+					Labels:  []prompb.Label{{Name: "ts1l1", Value: "ts1k1"}},
+					Samples: []prompb.Sample{{Value: 1, Timestamp: 100}},
+				},
+			},
+		},
+		&writev2.Request{
+			Symbols: []string{"", "ts2l1", "ts2k1"},
+			Timeseries: []writev2.TimeSeries{
+				{
+					LabelsRefs:       []uint32{1, 2},
+					Samples:          []writev2.Sample{{Value: 2, Timestamp: 200}},
+					Metadata:         writev2.Metadata{Type: writev2.Metadata_METRIC_TYPE_COUNTER},
+					CreatedTimestamp: 50,
+				},
+			},
+		},
+		// An empty remote write 1.0 request is encoded as an empty entry.
+		&prompb.WriteRequest{},
+	}
+	ctx := context.Background()
+	require.NoError(t, pwal.persistToWAL(ctx, reqL))
+
+	var reqLFromWAL []writeRequest
+	for i := uint64(1); i <= uint64(len(reqL)); i++ {
+		req, err := pwal.readPrompbFromWAL(ctx, i)
+		require.NoError(t, err)
+		reqLFromWAL = append(reqLFromWAL, req)
+	}
+	assert.Equal(t, reqL, reqLFromWAL)
+}
+
+func sampleRequest(name string, timestamp time.Time) *prompb.WriteRequest {
+	return &prompb.WriteRequest{
+		Timeseries: []prompb.TimeSeries{
+			{
+				Labels:  []prompb.Label{{Name: "__name__", Value: name}},
+				Samples: []prompb.Sample{{Value: 1, Timestamp: timestamp.UnixMilli()}},
+			},
+		},
+	}
+}
+
+func TestWAL_maxSizeDropsOldest(t *testing.T) {
+	telemetry := &mockPRWTelemetry{}
+	config := &WALConfig{Directory: t.TempDir(), MaxSizeBytes: 200}
+	pwal := newWAL(config, telemetry, doNothingExportSink)
+	require.NoError(t, pwal.retrieveWALIndices())
+	t.Cleanup(func() {
+		assert.NoError(t, pwal.stop())
+	})
+
+	ctx := context.Background()
+	const numRequests = 20
+	for i := 0; i < numRequests; i++ {
+		req := sampleRequest(fmt.Sprintf("metric_%d", i), time.Now())
+		require.NoError(t, pwal.persistToWAL(ctx, []writeRequest{req}))
+	}
+
+	first, err := pwal.wal.FirstIndex()
+	require.NoError(t, err)
+	last, err := pwal.wal.LastIndex()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(numRequests), last)
+	assert.Greater(t, first, uint64(1))
+
+	size, err := pwal.sizeOnDisk()
+	require.NoError(t, err)
+	assert.LessOrEqual(t, size, config.MaxSizeBytes)
+	assert.Equal(t, size, telemetry.walBytes)
+	assert.Equal(t, int(first-1), telemetry.dropped(walDropReasonSize))
+	assert.Equal(t, int64(last-first+1), telemetry.walLag)
+
+	// Reading from a dropped index returns the oldest remaining request.
+	req, err := pwal.readPrompbFromWAL(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, fmt.Sprintf("metric_%d", first-1), req.(*prompb.WriteRequest).Timeseries[0].Labels[0].Value)
+	assert.Equal(t, first+1, pwal.rWALIndex.Load())
+}
+
+func TestWAL_maxAgeDropsOldRequests(t *testing.T) {
+	telemetry := &mockPRWTelemetry{}
+	exported := make(chan writeRequest, 10)
+	exportSink := func(_ context.Context, reqL []writeRequest) error {
+		for _, req := range reqL {
+			exported <- req
+		}
+		return nil
+	}
+	config := &WALConfig{Directory: t.TempDir(), MaxAge: time.Hour, BufferSize: 1}
+	pwal := newWAL(config, telemetry, exportSink)
+
+	ctx := contextWithLogger(context.Background(), zap.NewNop())
+	require.NoError(t, pwal.run(ctx))
+	t.Cleanup(func() {
+		assert.NoError(t, pwal.stop())
+	})
+
+	fresh := sampleRequest("fresh", time.Now())
+	require.NoError(t, pwal.persistToWAL(ctx, []writeRequest{
+		sampleRequest("old", time.Now().Add(-2*time.Hour)),
+		fresh,
+	}))
+
+	select {
+	case req := <-exported:
+		assert.Equal(t, fresh, req)
+	case <-time.After(10 * time.Second):
+		require.Fail(t, "timed out waiting for the export")
+	}
+	assert.Equal(t, 1, telemetry.dropped(walDropReasonAge))
+	assert.Eventually(t, func() bool {
+		telemetry.mu.Lock()
+		defer telemetry.mu.Unlock()
+		return telemetry.replayedSamples == 1
+	}, 10*time.Second, 10*time.Millisecond)
+}
+
+func TestWAL_replayRateLimit(t *testing.T) {
+	config := &WALConfig{Directory: t.TempDir(), ReplaySamplesPerSecond: 100}
+	pwal := newWAL(config, &mockPRWTelemetry{}, doNothingExportSink)
+	require.NotNil(t, pwal.limiter)
+
+	// The first 100 samples are replayed right away, the following ones are paced.
+	start := time.Now()
+	require.NoError(t, pwal.waitForReplay(context.Background(), 150))
+	assert.GreaterOrEqual(t, time.Since(start), 400*time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.Error(t, pwal.waitForReplay(ctx, 150))
+}
+
+func Test_requestSamples(t *testing.T) {
+	samples, newest := requestSamples(&prompb.WriteRequest{
+		Timeseries: []prompb.TimeSeries{
+			{Samples: []prompb.Sample{{Timestamp: 10}, {Timestamp: 30}}},
+			{Histograms: []prompb.Histogram{{Timestamp: 20}}},
+		},
+	})
+	assert.Equal(t, 3, samples)
+	assert.Equal(t, int64(30), newest)
+
+	samples, newest = requestSamples(&writev2.Request{
+		Timeseries: []writev2.TimeSeries{
+			{Samples: []writev2.Sample{{Timestamp: 10}}},
+			{Histograms: []writev2.Histogram{{Timestamp: 40}}},
+		},
+	})
+	assert.Equal(t, 2, samples)
+	assert.Equal(t, int64(40), newest)
+}
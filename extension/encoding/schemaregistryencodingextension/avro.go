@@ -0,0 +1,57 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package schemaregistryencodingextension // import "github.com/open-telemetry/opentelemetry-collector-contrib/extension/encoding/schemaregistryencodingextension"
+
+import (
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/linkedin/goavro/v2"
+)
+
+type avroDecoder struct {
+	codec *goavro.Codec
+}
+
+func newAvroDecoder(schema string) (decoder, error) {
+	codec, err := goavro.NewCodec(schema)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create avro codec: %w", err)
+	}
+	return &avroDecoder{codec: codec}, nil
+}
+
+func (d *avroDecoder) decode(payload []byte) (any, error) {
+	native, _, err := d.codec.NativeFromBinary(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to deserialize avro record: %w", err)
+	}
+	return avroToRaw(native), nil
+}
+
+// avroToRaw replaces the values of the avro logical types, which FromRaw does
+// not support.
+func avroToRaw(value any) any {
+	switch v := value.(type) {
+	case time.Time:
+		return v.UnixNano()
+	case time.Duration:
+		return v.Nanoseconds()
+	case *big.Rat:
+		f, _ := v.Float64()
+		return f
+	case map[string]any:
+		for k, item := range v {
+			v[k] = avroToRaw(item)
+		}
+		return v
+	case []any:
+		for i, item := range v {
+			v[i] = avroToRaw(item)
+		}
+		return v
+	}
+	return value
+}
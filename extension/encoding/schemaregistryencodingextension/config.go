@@ -0,0 +1,26 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package schemaregistryencodingextension // import "github.com/open-telemetry/opentelemetry-collector-contrib/extension/encoding/schemaregistryencodingextension"
+
+import (
+	"errors"
+
+	"go.opentelemetry.io/collector/config/confighttp"
+)
+
+var errNoEndpoint = errors.New("no schema registry endpoint provided")
+
+type Config struct {
+	// ClientConfig configures the HTTP client of the Schema Registry API,
+	// its endpoint is the base URL of the registry.
+	confighttp.ClientConfig `mapstructure:",squash"`
+}
+
+func (c *Config) Validate() error {
+	if c.Endpoint == "" {
+		return errNoEndpoint
+	}
+
+	return nil
+}
@@ -0,0 +1,97 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package schemaregistryencodingextension // import "github.com/open-telemetry/opentelemetry-collector-contrib/extension/encoding/schemaregistryencodingextension"
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/extension"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/encoding"
+)
+
+// wireFormatHeaderSize is the size of the header of the Confluent wire
+// format: a magic byte followed by the 4 bytes big-endian schema ID.
+const wireFormatHeaderSize = 5
+
+// lookupTimeout bounds the lookup of a schema, including the fetches of its
+// references.
+const lookupTimeout = 30 * time.Second
+
+var (
+	errNotStarted        = errors.New("schema registry encoding extension not started")
+	errInvalidWireFormat = errors.New("payload is not in the schema registry wire format")
+)
+
+var _ encoding.LogsUnmarshalerExtension = (*schemaRegistryExtension)(nil)
+
+type schemaRegistryExtension struct {
+	config   *Config
+	settings extension.Settings
+	registry *registryClient
+
+	// ctx is the parent context of the lookups, canceled on shutdown.
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+func newExtension(config *Config, settings extension.Settings) *schemaRegistryExtension {
+	return &schemaRegistryExtension{config: config, settings: settings}
+}
+
+func (e *schemaRegistryExtension) UnmarshalLogs(buf []byte) (plog.Logs, error) {
+	p := plog.NewLogs()
+	if e.registry == nil {
+		return p, errNotStarted
+	}
+	if len(buf) < wireFormatHeaderSize || buf[0] != 0 {
+		return p, errInvalidWireFormat
+	}
+
+	schemaID := binary.BigEndian.Uint32(buf[1:wireFormatHeaderSize])
+	ctx, cancel := context.WithTimeout(e.ctx, lookupTimeout)
+	d, err := e.registry.decoder(ctx, schemaID)
+	cancel()
+	if err != nil {
+		return p, err
+	}
+	body, err := d.decode(buf[wireFormatHeaderSize:])
+	if err != nil {
+		return p, fmt.Errorf("failed to decode payload of schema %d: %w", schemaID, err)
+	}
+
+	logRecord := p.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords().AppendEmpty()
+	logRecord.SetObservedTimestamp(pcommon.NewTimestampFromTime(time.Now()))
+	if err := logRecord.Body().FromRaw(body); err != nil {
+		return p, err
+	}
+	return p, nil
+}
+
+func (e *schemaRegistryExtension) Start(ctx context.Context, host component.Host) error {
+	client, err := e.config.ToClient(ctx, host, e.settings.TelemetrySettings)
+	if err != nil {
+		return err
+	}
+	e.ctx, e.cancel = context.WithCancel(context.Background())
+	e.registry = newRegistryClient(e.config.Endpoint, client)
+	return nil
+}
+
+func (e *schemaRegistryExtension) Shutdown(_ context.Context) error {
+	if e.cancel != nil {
+		e.cancel()
+	}
+	if e.registry != nil {
+		e.registry.client.CloseIdleConnections()
+	}
+	return nil
+}
@@ -0,0 +1,371 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package schemaregistryencodingextension
+
+import (
+	"context"
+	"encoding/binary"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bufbuild/protocompile"
+	"github.com/linkedin/goavro/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/extension/extensiontest"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/dynamicpb"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/encoding/schemaregistryencodingextension/internal/registrytest"
+)
+
+const avroSchema = `{
+	"type": "record",
+	"name": "Order",
+	"namespace": "com.example",
+	"fields": [
+		{"name": "id", "type": "string"},
+		{"name": "amount", "type": "double"},
+		{"name": "quantity", "type": "int"},
+		{"name": "created", "type": {"type": "long", "logicalType": "timestamp-millis"}},
+		{"name": "tags", "type": {"type": "array", "items": "string"}}
+	]
+}`
+
+const commonProto = `syntax = "proto3";
+package com.example.common;
+
+enum Status {
+  STATUS_UNSPECIFIED = 0;
+  STATUS_SHIPPED = 1;
+}
+`
+
+const orderProto = `syntax = "proto3";
+package com.example;
+
+import "common.proto";
+import "google/protobuf/timestamp.proto";
+
+message Customer {
+  string name = 1;
+}
+
+message Order {
+  message Line {
+    string sku = 1;
+    uint64 quantity = 2;
+  }
+  string id = 1;
+  com.example.common.Status status = 2;
+  repeated Line lines = 3;
+  map<string, string> labels = 4;
+  google.protobuf.Timestamp created = 5;
+  bytes signature = 6;
+}
+`
+
+func startExtension(t *testing.T, registry *registrytest.Registry) *schemaRegistryExtension {
+	t.Helper()
+	config := createDefaultConfig().(*Config)
+	config.Endpoint = registry.URL()
+	e := newExtension(config, extensiontest.NewNopSettings())
+	require.NoError(t, e.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() {
+		require.NoError(t, e.Shutdown(context.Background()))
+	})
+	return e
+}
+
+// wireFormat prefixes the payload with the magic byte, the schema ID and, for
+// Protobuf, the message indexes.
+func wireFormat(id int, indexes []byte, payload []byte) []byte {
+	buf := []byte{0}
+	buf = binary.BigEndian.AppendUint32(buf, uint32(id))
+	buf = append(buf, indexes...)
+	return append(buf, payload...)
+}
+
+func TestUnmarshalLogs_avro(t *testing.T) {
+	registry := registrytest.NewRegistry()
+	defer registry.Close()
+	id := registry.Register("orders-value", "", avroSchema)
+	e := startExtension(t, registry)
+
+	codec, err := goavro.NewCodec(avroSchema)
+	require.NoError(t, err)
+	native, _, err := codec.NativeFromTextual([]byte(`{"id": "o-1", "amount": 12.5, "quantity": 3, "created": 1697187201488, "tags": ["a", "b"]}`))
+	require.NoError(t, err)
+	payload, err := codec.BinaryFromNative(nil, native)
+	require.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		logs, err := e.UnmarshalLogs(wireFormat(id, nil, payload))
+		require.NoError(t, err)
+		logRecord := logs.ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(0)
+		assert.JSONEq(t, `{"id":"o-1","amount":12.5,"quantity":3,"created":1697187201488000000,"tags":["a","b"]}`, logRecord.Body().AsString())
+		assert.NotZero(t, logRecord.ObservedTimestamp())
+	}
+	// The schema is fetched once.
+	assert.Equal(t, 1, registry.Requests())
+}
+
+func TestUnmarshalLogs_protobuf(t *testing.T) {
+	registry := registrytest.NewRegistry()
+	defer registry.Close()
+	registry.Register("common", schemaTypeProtobuf, commonProto)
+	id := registry.Register("orders-value", schemaTypeProtobuf, orderProto, registrytest.Reference{
+		Name:    "common.proto",
+		Subject: "common",
+		Version: 1,
+	})
+	e := startExtension(t, registry)
+
+	compiler := protocompile.Compiler{
+		Resolver: protocompile.WithStandardImports(&protocompile.SourceResolver{
+			Accessor: protocompile.SourceAccessorFromMap(map[string]string{
+				"order.proto":  orderProto,
+				"common.proto": commonProto,
+			}),
+		}),
+	}
+	files, err := compiler.Compile(context.Background(), "order.proto")
+	require.NoError(t, err)
+
+	order := dynamicpb.NewMessage(files[0].Messages().ByName("Order"))
+	require.NoError(t, protojson.Unmarshal([]byte(`{
+		"id": "o-1",
+		"status": "STATUS_SHIPPED",
+		"lines": [{"sku": "sku-1", "quantity": "18446744073709551615"}],
+		"labels": {"team": "a"},
+		"created": "2023-10-13T09:33:21Z",
+		"signature": "AQI="
+	}`), order))
+	payload, err := proto.Marshal(order)
+	require.NoError(t, err)
+
+	// Order is the second message of the schema, its indexes are [1].
+	logs, err := e.UnmarshalLogs(wireFormat(id, []byte{2, 2}, payload))
+	require.NoError(t, err)
+	body := logs.ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(0).Body()
+	assert.Equal(t, map[string]any{
+		"id":     "o-1",
+		"status": "STATUS_SHIPPED",
+		"lines": []any{
+			map[string]any{"sku": "sku-1", "quantity": "18446744073709551615"},
+		},
+		"labels":    map[string]any{"team": "a"},
+		"created":   map[string]any{"seconds": int64(1697189601)},
+		"signature": []byte{1, 2},
+	}, body.AsRaw())
+
+	customer := dynamicpb.NewMessage(files[0].Messages().ByName("Customer"))
+	require.NoError(t, protojson.Unmarshal([]byte(`{"name": "alice"}`), customer))
+	payload, err = proto.Marshal(customer)
+	require.NoError(t, err)
+
+	// The first message of the schema is encoded as a single 0.
+	logs, err = e.UnmarshalLogs(wireFormat(id, []byte{0}, payload))
+	require.NoError(t, err)
+	body = logs.ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(0).Body()
+	assert.Equal(t, map[string]any{"name": "alice"}, body.AsRaw())
+
+	// Line is nested in Order, its indexes are [1, 0].
+	_, err = e.UnmarshalLogs(wireFormat(id, []byte{4, 2, 0}, nil))
+	require.NoError(t, err)
+
+	_, err = e.UnmarshalLogs(wireFormat(id, []byte{2, 6}, payload))
+	assert.ErrorContains(t, err, "protobuf message index [3] not found in schema")
+}
+
+func TestUnmarshalLogs_json(t *testing.T) {
+	registry := registrytest.NewRegistry()
+	defer registry.Close()
+	id := registry.Register("events-value", schemaTypeJSON, `{"type": "object", "properties": {"name": {"type": "string"}}}`)
+	e := startExtension(t, registry)
+
+	logs, err := e.UnmarshalLogs(wireFormat(id, nil, []byte(`{"name": "signup", "count": 9007199254740993, "ratio": 0.5, "tags": [1]}`)))
+	require.NoError(t, err)
+	body := logs.ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(0).Body()
+	assert.Equal(t, map[string]any{
+		"name":  "signup",
+		"count": int64(9007199254740993),
+		"ratio": 0.5,
+		"tags":  []any{int64(1)},
+	}, body.AsRaw())
+
+	_, err = e.UnmarshalLogs(wireFormat(id, nil, []byte(`{"name": "signup"} {}`)))
+	assert.ErrorIs(t, err, errTrailingJSON)
+}
+
+func TestUnmarshalLogs_errors(t *testing.T) {
+	registry := registrytest.NewRegistry()
+	defer registry.Close()
+	avroID := registry.Register("orders-value", "", avroSchema)
+	invalidID := registry.Register("invalid-value", "", `{"type": "unknown"}`)
+	unsupportedID := registry.Register("xml-value", "XML", `<schema/>`)
+
+	_, err := newExtension(createDefaultConfig().(*Config), extensiontest.NewNopSettings()).UnmarshalLogs(wireFormat(avroID, nil, nil))
+	assert.ErrorIs(t, err, errNotStarted)
+
+	e := startExtension(t, registry)
+	tests := []struct {
+		name    string
+		buf     []byte
+		wantErr string
+	}{
+		{
+			name:    "too_short",
+			buf:     []byte{0, 0, 0},
+			wantErr: errInvalidWireFormat.Error(),
+		},
+		{
+			name:    "magic_byte",
+			buf:     []byte{1, 0, 0, 0, 1},
+			wantErr: errInvalidWireFormat.Error(),
+		},
+		{
+			name:    "unknown_schema",
+			buf:     wireFormat(42, nil, nil),
+			wantErr: "failed to fetch schema 42: schema registry responded with status 404: Schema 42 not found",
+		},
+		{
+			name:    "invalid_schema",
+			buf:     wireFormat(invalidID, nil, nil),
+			wantErr: "invalid schema 2: failed to create avro codec",
+		},
+		{
+			name:    "unsupported_schema_type",
+			buf:     wireFormat(unsupportedID, nil, nil),
+			wantErr: `invalid schema 3: unsupported schema type "XML"`,
+		},
+		{
+			name:    "invalid_payload",
+			buf:     wireFormat(avroID, nil, []byte{0xff}),
+			wantErr: "failed to decode payload of schema 1",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := e.UnmarshalLogs(tt.buf)
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestUnmarshalLogs_concurrentLookups(t *testing.T) {
+	registry := registrytest.NewRegistry()
+	defer registry.Close()
+	id := registry.Register("events-value", schemaTypeJSON, `{"type": "object"}`)
+	registry.SetLatency(100 * time.Millisecond)
+	e := startExtension(t, registry)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := e.UnmarshalLogs(wireFormat(id, nil, []byte(`{"id": 1}`)))
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+	// The concurrent lookups share a single fetch.
+	assert.Equal(t, 1, registry.Requests())
+}
+
+func TestUnmarshalLogs_failedLookups(t *testing.T) {
+	registry := registrytest.NewRegistry()
+	defer registry.Close()
+	e := startExtension(t, registry)
+	now := time.Now()
+	e.registry.now = func() time.Time { return now }
+
+	for i := 0; i < 2; i++ {
+		_, err := e.UnmarshalLogs(wireFormat(1, nil, []byte(`{}`)))
+		assert.ErrorContains(t, err, "failed to fetch schema 1")
+	}
+	// The failure is cached until it expires.
+	assert.Equal(t, 1, registry.Requests())
+
+	id := registry.Register("events-value", schemaTypeJSON, `{"type": "object"}`)
+	now = now.Add(failureTTL)
+	_, err := e.UnmarshalLogs(wireFormat(id, nil, []byte(`{}`)))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, registry.Requests())
+	assert.Empty(t, e.registry.failures)
+}
+
+func TestUnmarshalLogs_lookupCanceledOnShutdown(t *testing.T) {
+	registry := registrytest.NewRegistry()
+	defer registry.Close()
+	id := registry.Register("events-value", schemaTypeJSON, `{"type": "object"}`)
+	registry.SetLatency(time.Second)
+	config := createDefaultConfig().(*Config)
+	config.Endpoint = registry.URL()
+	e := newExtension(config, extensiontest.NewNopSettings())
+	require.NoError(t, e.Start(context.Background(), componenttest.NewNopHost()))
+
+	errs := make(chan error, 1)
+	go func() {
+		_, err := e.UnmarshalLogs(wireFormat(id, nil, []byte(`{}`)))
+		errs <- err
+	}()
+	require.Eventually(t, func() bool { return registry.Requests() == 1 }, time.Second, 10*time.Millisecond)
+	require.NoError(t, e.Shutdown(context.Background()))
+	assert.ErrorIs(t, <-errs, context.Canceled)
+}
+
+func TestReadMessageIndexes(t *testing.T) {
+	tests := []struct {
+		name        string
+		payload     []byte
+		wantIndexes []int
+		wantRest    []byte
+		wantErr     bool
+	}{
+		{
+			name:        "first_message",
+			payload:     []byte{0, 0xa},
+			wantIndexes: []int{0},
+			wantRest:    []byte{0xa},
+		},
+		{
+			name:        "nested_message",
+			payload:     []byte{4, 2, 6, 0xa},
+			wantIndexes: []int{1, 3},
+			wantRest:    []byte{0xa},
+		},
+		{
+			name:    "empty",
+			payload: []byte{},
+			wantErr: true,
+		},
+		{
+			name:    "negative_count",
+			payload: []byte{1},
+			wantErr: true,
+		},
+		{
+			name:    "truncated",
+			payload: []byte{4, 2},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			indexes, rest, err := readMessageIndexes(tt.payload)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, errInvalidMessageIndexes)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantIndexes, indexes)
+			assert.Equal(t, tt.wantRest, rest)
+		})
+	}
+}
@@ -0,0 +1,135 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package registrytest provides a local stand-in of the Schema Registry API
+// for tests.
+package registrytest // import "github.com/open-telemetry/opentelemetry-collector-contrib/extension/encoding/schemaregistryencodingextension/internal/registrytest"
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Reference is a reference from a schema to the version of a subject.
+type Reference struct {
+	Name    string `json:"name"`
+	Subject string `json:"subject"`
+	Version int    `json:"version"`
+}
+
+type schema struct {
+	Schema     string      `json:"schema"`
+	SchemaType string      `json:"schemaType,omitempty"`
+	References []Reference `json:"references,omitempty"`
+}
+
+// Registry serves the schemas registered in memory with the endpoints of the
+// Schema Registry API used to read them.
+type Registry struct {
+	server   *httptest.Server
+	requests atomic.Int64
+	latency  atomic.Int64
+
+	mu       sync.Mutex
+	schemas  []schema
+	subjects map[string][]int
+}
+
+// NewRegistry starts a registry, it must be closed by the caller.
+func NewRegistry() *Registry {
+	r := &Registry{subjects: map[string][]int{}}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /schemas/ids/{id}", r.handleSchemaByID)
+	mux.HandleFunc("GET /subjects/{subject}/versions/{version}", r.handleSubjectVersion)
+	r.server = httptest.NewServer(mux)
+	return r
+}
+
+// URL is the endpoint of the registry.
+func (r *Registry) URL() string {
+	return r.server.URL
+}
+
+// Close shuts down the registry.
+func (r *Registry) Close() {
+	r.server.Close()
+}
+
+// Requests returns the number of requests served.
+func (r *Registry) Requests() int {
+	return int(r.requests.Load())
+}
+
+// SetLatency delays the responses of the registry.
+func (r *Registry) SetLatency(latency time.Duration) {
+	r.latency.Store(int64(latency))
+}
+
+// Register adds a new version of the subject and returns the ID of the schema.
+// The schema type is empty for Avro schemas.
+func (r *Registry) Register(subject, schemaType, text string, refs ...Reference) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.schemas = append(r.schemas, schema{Schema: text, SchemaType: schemaType, References: refs})
+	id := len(r.schemas)
+	r.subjects[subject] = append(r.subjects[subject], id)
+	return id
+}
+
+func (r *Registry) handleSchemaByID(w http.ResponseWriter, req *http.Request) {
+	r.requests.Add(1)
+	time.Sleep(time.Duration(r.latency.Load()))
+	id, err := strconv.Atoi(req.PathValue("id"))
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err != nil || id < 1 || id > len(r.schemas) {
+		writeError(w, 40403, "Schema "+req.PathValue("id")+" not found")
+		return
+	}
+	writeJSON(w, http.StatusOK, r.schemas[id-1])
+}
+
+func (r *Registry) handleSubjectVersion(w http.ResponseWriter, req *http.Request) {
+	r.requests.Add(1)
+	time.Sleep(time.Duration(r.latency.Load()))
+	version, err := strconv.Atoi(req.PathValue("version"))
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	versions := r.subjects[req.PathValue("subject")]
+	if err != nil || version < 1 || version > len(versions) {
+		writeError(w, 40402, "Version "+req.PathValue("version")+" not found")
+		return
+	}
+	id := versions[version-1]
+	writeJSON(w, http.StatusOK, struct {
+		Subject string `json:"subject"`
+		ID      int    `json:"id"`
+		Version int    `json:"version"`
+		schema
+	}{
+		Subject: req.PathValue("subject"),
+		ID:      id,
+		Version: version,
+		schema:  r.schemas[id-1],
+	})
+}
+
+func writeError(w http.ResponseWriter, code int, message string) {
+	writeJSON(w, http.StatusNotFound, struct {
+		ErrorCode int    `json:"error_code"`
+		Message   string `json:"message"`
+	}{ErrorCode: code, Message: message})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/vnd.schemaregistry.v1+json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,53 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package schemaregistryencodingextension // import "github.com/open-telemetry/opentelemetry-collector-contrib/extension/encoding/schemaregistryencodingextension"
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+var errTrailingJSON = errors.New("failed to deserialize json document: unexpected data after the document")
+
+// jsonDecoder decodes the payloads of JSON Schema schemas, which are plain
+// JSON documents. The payloads are not validated against the schema.
+type jsonDecoder struct{}
+
+func (jsonDecoder) decode(payload []byte) (any, error) {
+	dec := json.NewDecoder(bytes.NewReader(payload))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, fmt.Errorf("failed to deserialize json document: %w", err)
+	}
+	if dec.More() {
+		return nil, errTrailingJSON
+	}
+	return jsonToRaw(v), nil
+}
+
+// jsonToRaw keeps the integers of the document as int64.
+func jsonToRaw(value any) any {
+	switch v := value.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		f, _ := v.Float64()
+		return f
+	case map[string]any:
+		for k, item := range v {
+			v[k] = jsonToRaw(item)
+		}
+		return v
+	case []any:
+		for i, item := range v {
+			v[i] = jsonToRaw(item)
+		}
+		return v
+	}
+	return value
+}
@@ -0,0 +1,135 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package schemaregistryencodingextension // import "github.com/open-telemetry/opentelemetry-collector-contrib/extension/encoding/schemaregistryencodingextension"
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/bufbuild/protocompile"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+var errInvalidMessageIndexes = errors.New("invalid protobuf message indexes")
+
+// protobufDecoder decodes the payloads of a Protobuf schema, which start with
+// the indexes of the message type within the schema.
+type protobufDecoder struct {
+	file protoreflect.FileDescriptor
+}
+
+func newProtobufDecoder(ctx context.Context, name string, sources map[string]string) (decoder, error) {
+	compiler := protocompile.Compiler{
+		Resolver: protocompile.WithStandardImports(&protocompile.SourceResolver{
+			Accessor: protocompile.SourceAccessorFromMap(sources),
+		}),
+	}
+	files, err := compiler.Compile(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compile protobuf schema: %w", err)
+	}
+	return &protobufDecoder{file: files[0]}, nil
+}
+
+func (d *protobufDecoder) decode(payload []byte) (any, error) {
+	indexes, payload, err := readMessageIndexes(payload)
+	if err != nil {
+		return nil, err
+	}
+	desc, err := d.messageDescriptor(indexes)
+	if err != nil {
+		return nil, err
+	}
+	msg := dynamicpb.NewMessage(desc)
+	if err := proto.Unmarshal(payload, msg); err != nil {
+		return nil, fmt.Errorf("failed to deserialize protobuf message %s: %w", desc.FullName(), err)
+	}
+	return protoMessageToRaw(msg), nil
+}
+
+// readMessageIndexes reads the path of the message type in the schema: the
+// zigzag encoded number of indexes followed by the indexes, a single 0 is the
+// first message of the schema.
+func readMessageIndexes(payload []byte) ([]int, []byte, error) {
+	count, n := binary.Varint(payload)
+	if n <= 0 || count < 0 || count > int64(len(payload)) {
+		return nil, nil, errInvalidMessageIndexes
+	}
+	payload = payload[n:]
+	if count == 0 {
+		return []int{0}, payload, nil
+	}
+	indexes := make([]int, count)
+	for i := range indexes {
+		index, n := binary.Varint(payload)
+		if n <= 0 || index < 0 {
+			return nil, nil, errInvalidMessageIndexes
+		}
+		indexes[i] = int(index)
+		payload = payload[n:]
+	}
+	return indexes, payload, nil
+}
+
+func (d *protobufDecoder) messageDescriptor(indexes []int) (protoreflect.MessageDescriptor, error) {
+	messages := d.file.Messages()
+	var desc protoreflect.MessageDescriptor
+	for _, index := range indexes {
+		if index >= messages.Len() {
+			return nil, fmt.Errorf("protobuf message index %v not found in schema", indexes)
+		}
+		desc = messages.Get(index)
+		messages = desc.Messages()
+	}
+	return desc, nil
+}
+
+// protoMessageToRaw converts the populated fields of the message to a map
+// keyed by field name.
+func protoMessageToRaw(msg protoreflect.Message) map[string]any {
+	raw := map[string]any{}
+	msg.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		switch {
+		case fd.IsList():
+			list := v.List()
+			items := make([]any, list.Len())
+			for i := range items {
+				items[i] = protoValueToRaw(fd, list.Get(i))
+			}
+			raw[string(fd.Name())] = items
+		case fd.IsMap():
+			entries := map[string]any{}
+			v.Map().Range(func(k protoreflect.MapKey, mv protoreflect.Value) bool {
+				entries[k.String()] = protoValueToRaw(fd.MapValue(), mv)
+				return true
+			})
+			raw[string(fd.Name())] = entries
+		default:
+			raw[string(fd.Name())] = protoValueToRaw(fd, v)
+		}
+		return true
+	})
+	return raw
+}
+
+func protoValueToRaw(fd protoreflect.FieldDescriptor, v protoreflect.Value) any {
+	switch fd.Kind() {
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		return protoMessageToRaw(v.Message())
+	case protoreflect.EnumKind:
+		if ev := fd.Enum().Values().ByNumber(v.Enum()); ev != nil {
+			return string(ev.Name())
+		}
+		return strconv.Itoa(int(v.Enum()))
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		// uint64 doesn't fit in the int64 of the log attributes.
+		return strconv.FormatUint(v.Uint(), 10)
+	}
+	return v.Interface()
+}
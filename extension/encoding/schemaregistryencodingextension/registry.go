@@ -0,0 +1,218 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package schemaregistryencodingextension // import "github.com/open-telemetry/opentelemetry-collector-contrib/extension/encoding/schemaregistryencodingextension"
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// Schema types of the registry, the type is omitted for Avro schemas.
+const (
+	schemaTypeAvro     = "AVRO"
+	schemaTypeProtobuf = "PROTOBUF"
+	schemaTypeJSON     = "JSON"
+)
+
+// maxReferenceDepth limits the nesting of the references of a schema.
+const maxReferenceDepth = 32
+
+// failureTTL is the time during which the error of a failed lookup is returned
+// for the same schema ID without querying the registry again.
+const failureTTL = 5 * time.Second
+
+// registrySchema is a schema as returned by the Schema Registry API.
+type registrySchema struct {
+	Schema     string            `json:"schema"`
+	SchemaType string            `json:"schemaType"`
+	References []schemaReference `json:"references"`
+}
+
+// schemaReference is a reference to another schema, its name is the name
+// used to import it, e.g. the path of a Protobuf import.
+type schemaReference struct {
+	Name    string `json:"name"`
+	Subject string `json:"subject"`
+	Version int    `json:"version"`
+}
+
+// decoder decodes the payloads written with a schema.
+type decoder interface {
+	decode(payload []byte) (any, error)
+}
+
+// registryClient fetches the schemas from the Schema Registry API. The schemas
+// are immutable once registered, so the decoders are cached by schema ID. The
+// concurrent lookups of a schema share a single fetch, and failed lookups are
+// cached for failureTTL so that payloads of unknown schemas don't flood the
+// registry.
+type registryClient struct {
+	endpoint string
+	client   *http.Client
+	now      func() time.Time
+
+	lookups singleflight.Group
+
+	mu       sync.Mutex
+	decoders map[uint32]decoder
+	failures map[uint32]failedLookup
+}
+
+// failedLookup is the error of the lookup of a schema, returned until it expires.
+type failedLookup struct {
+	err        error
+	expiration time.Time
+}
+
+func newRegistryClient(endpoint string, client *http.Client) *registryClient {
+	return &registryClient{
+		endpoint: strings.TrimSuffix(endpoint, "/"),
+		client:   client,
+		now:      time.Now,
+		decoders: map[uint32]decoder{},
+		failures: map[uint32]failedLookup{},
+	}
+}
+
+// decoder returns the decoder of the schema with the given ID.
+func (r *registryClient) decoder(ctx context.Context, id uint32) (decoder, error) {
+	r.mu.Lock()
+	d, ok := r.decoders[id]
+	failure, failed := r.failures[id]
+	r.mu.Unlock()
+	if ok {
+		return d, nil
+	}
+	if failed && r.now().Before(failure.expiration) {
+		return nil, failure.err
+	}
+
+	v, err, _ := r.lookups.Do(strconv.FormatUint(uint64(id), 10), func() (any, error) {
+		d, err := r.lookup(ctx, id)
+
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		if err != nil {
+			r.addFailure(id, err)
+			return nil, err
+		}
+		delete(r.failures, id)
+		r.decoders[id] = d
+		return d, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.(decoder), nil
+}
+
+// addFailure caches the error of the lookup of a schema and evicts the
+// expired ones. It must be called holding mu.
+func (r *registryClient) addFailure(id uint32, err error) {
+	now := r.now()
+	for failedID, failure := range r.failures {
+		if !now.Before(failure.expiration) {
+			delete(r.failures, failedID)
+		}
+	}
+	r.failures[id] = failedLookup{err: err, expiration: now.Add(failureTTL)}
+}
+
+// lookup fetches the schema with the given ID and creates its decoder.
+func (r *registryClient) lookup(ctx context.Context, id uint32) (decoder, error) {
+	schema, err := r.fetch(ctx, "/schemas/ids/"+strconv.FormatUint(uint64(id), 10))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch schema %d: %w", id, err)
+	}
+	d, err := r.newDecoder(ctx, id, schema)
+	if err != nil {
+		return nil, fmt.Errorf("invalid schema %d: %w", id, err)
+	}
+	return d, nil
+}
+
+func (r *registryClient) newDecoder(ctx context.Context, id uint32, schema *registrySchema) (decoder, error) {
+	switch schema.SchemaType {
+	case "", schemaTypeAvro:
+		if len(schema.References) > 0 {
+			return nil, fmt.Errorf("references of %s schemas are not supported", schemaTypeAvro)
+		}
+		return newAvroDecoder(schema.Schema)
+	case schemaTypeProtobuf:
+		name := fmt.Sprintf("schema_%d.proto", id)
+		sources := map[string]string{name: schema.Schema}
+		if err := r.resolveReferences(ctx, schema.References, sources, 0); err != nil {
+			return nil, err
+		}
+		return newProtobufDecoder(ctx, name, sources)
+	case schemaTypeJSON:
+		return jsonDecoder{}, nil
+	default:
+		return nil, fmt.Errorf("unsupported schema type %q", schema.SchemaType)
+	}
+}
+
+// resolveReferences fetches the referenced schemas, and their own references,
+// into sources keyed by reference name.
+func (r *registryClient) resolveReferences(ctx context.Context, refs []schemaReference, sources map[string]string, depth int) error {
+	if depth >= maxReferenceDepth {
+		return fmt.Errorf("references nested more than %d levels", maxReferenceDepth)
+	}
+	for _, ref := range refs {
+		if _, ok := sources[ref.Name]; ok {
+			continue
+		}
+		schema, err := r.fetch(ctx, fmt.Sprintf("/subjects/%s/versions/%d", url.PathEscape(ref.Subject), ref.Version))
+		if err != nil {
+			return fmt.Errorf("failed to fetch reference %q: %w", ref.Name, err)
+		}
+		sources[ref.Name] = schema.Schema
+		if err := r.resolveReferences(ctx, schema.References, sources, depth+1); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *registryClient) fetch(ctx context.Context, path string) (*registrySchema, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, r.endpoint+path, http.NoBody)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/vnd.schemaregistry.v1+json")
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		// The errors of the API are {"error_code": 40403, "message": "Schema not found"}.
+		var apiErr struct {
+			Message string `json:"message"`
+		}
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		if json.Unmarshal(body, &apiErr) != nil || apiErr.Message == "" {
+			apiErr.Message = http.StatusText(resp.StatusCode)
+		}
+		return nil, fmt.Errorf("schema registry responded with status %d: %s", resp.StatusCode, apiErr.Message)
+	}
+
+	var schema registrySchema
+	if err := json.NewDecoder(resp.Body).Decode(&schema); err != nil {
+		return nil, fmt.Errorf("failed to decode schema registry response: %w", err)
+	}
+	return &schema, nil
+}
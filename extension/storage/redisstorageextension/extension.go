@@ -77,20 +77,41 @@ func (rc redisClient) Delete(ctx context.Context, key string) error {
 	return err
 }
 
+// Batch runs the operations in a transaction, so that they are applied atomically, and sets the
+// values of the Get operations once it completes.
 func (rc redisClient) Batch(ctx context.Context, ops ...*storage.Operation) error {
-	p := rc.client.Pipeline()
-	for _, op := range ops {
+	p := rc.client.TxPipeline()
+	gets := make([]*redis.StringCmd, len(ops))
+	for i, op := range ops {
 		switch op.Type {
 		case storage.Delete:
-			p.Del(ctx, op.Key)
+			p.Del(ctx, rc.prefix+op.Key)
 		case storage.Get:
-			p.Get(ctx, op.Key)
+			gets[i] = p.Get(ctx, rc.prefix+op.Key)
 		case storage.Set:
-			p.Set(ctx, op.Key, op.Value, rc.expiration)
+			p.Set(ctx, rc.prefix+op.Key, op.Value, rc.expiration)
 		}
 	}
-	_, err := p.Exec(ctx)
-	return err
+	cmds, err := p.Exec(ctx)
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return err
+	}
+	// The missing keys of the Get operations fail with redis.Nil, which isn't an error here.
+	for _, cmd := range cmds {
+		if err := cmd.Err(); err != nil && !errors.Is(err, redis.Nil) {
+			return err
+		}
+	}
+	for i, op := range ops {
+		if gets[i] == nil {
+			continue
+		}
+		op.Value, err = gets[i].Bytes()
+		if errors.Is(err, redis.Nil) {
+			op.Value = nil
+		}
+	}
+	return nil
 }
 
 func (rc redisClient) Close(_ context.Context) error {
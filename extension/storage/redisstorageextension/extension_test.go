@@ -137,6 +137,42 @@ func TestClientHandlesSimpleCases(t *testing.T) {
 	require.Nil(t, data)
 }
 
+func TestClientBatch(t *testing.T) {
+	t.Skip("Requires a Redis cluster to be present at localhost:6379")
+	ctx := context.Background()
+	se := newTestExtension(t)
+
+	client, err := se.GetClient(
+		ctx,
+		component.KindReceiver,
+		newTestEntity("my_component"),
+		"",
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, client.Close(ctx))
+	})
+
+	myBytes := []byte("value")
+
+	// Set the data and get it in the same batch
+	get := storage.GetOperation("key")
+	require.NoError(t, client.Batch(ctx, storage.SetOperation("key", myBytes), get))
+	require.Equal(t, myBytes, get.Value)
+
+	// The batch is applied with the prefix of the client
+	data, err := client.Get(ctx, "key")
+	require.NoError(t, err)
+	require.Equal(t, myBytes, data)
+
+	// Get and delete the data, then get missing data
+	get = storage.GetOperation("key")
+	missing := storage.GetOperation("key")
+	require.NoError(t, client.Batch(ctx, get, storage.DeleteOperation("key"), missing))
+	require.Equal(t, myBytes, get.Value)
+	require.Nil(t, missing.Value)
+}
+
 func TestTwoClientsWithDifferentNames(t *testing.T) {
 	t.Skip("Requires a Redis cluster to be present at localhost:6379")
 	ctx := context.Background()
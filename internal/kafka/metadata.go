@@ -0,0 +1,30 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package kafka // import "github.com/open-telemetry/opentelemetry-collector-contrib/internal/kafka"
+
+import "time"
+
+// Metadata defines configuration for retrieving metadata from the broker.
+type Metadata struct {
+	// Whether to maintain a full set of metadata for all topics, or just
+	// the minimal set that has been necessary so far. The full set is simpler
+	// and usually more convenient, but can take up a substantial amount of
+	// memory if you have many topics and partitions. Defaults to true.
+	Full bool `mapstructure:"full"`
+
+	// Retry configuration for metadata.
+	// This configuration is useful to avoid race conditions when broker
+	// is starting at the same time as collector.
+	Retry MetadataRetry `mapstructure:"retry"`
+}
+
+// MetadataRetry defines retry configuration for Metadata.
+type MetadataRetry struct {
+	// The total number of times to retry a metadata request when the
+	// cluster is in the middle of a leader election or at startup (default 3).
+	Max int `mapstructure:"max"`
+	// How long to wait for leader election to occur before retrying
+	// (default 250ms). Similar to the JVM's `retry.backoff.ms`.
+	Backoff time.Duration `mapstructure:"backoff"`
+}
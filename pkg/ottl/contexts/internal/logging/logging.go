@@ -348,6 +348,16 @@ func (u UInt64Slice) MarshalLogArray(encoder zapcore.ArrayEncoder) error {
 	return nil
 }
 
+type Int64Slice pcommon.Int64Slice
+
+func (i Int64Slice) MarshalLogArray(encoder zapcore.ArrayEncoder) error {
+	is := pcommon.Int64Slice(i)
+	for j := 0; j < is.Len(); j++ {
+		encoder.AppendInt64(is.At(j))
+	}
+	return nil
+}
+
 type Float64Slice pcommon.Float64Slice
 
 func (f Float64Slice) MarshalLogArray(encoder zapcore.ArrayEncoder) error {
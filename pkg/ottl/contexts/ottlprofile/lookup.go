@@ -0,0 +1,94 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ottlprofile // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottlprofile"
+
+import (
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pprofile"
+)
+
+// SampleAttributes resolves the attribute indices of the sample against the
+// attribute table of the profile and returns them as a new map. Indices that
+// are out of range of the attribute table are ignored.
+func SampleAttributes(sample pprofile.Sample, profile pprofile.Profile) pcommon.Map {
+	attrs := pcommon.NewMap()
+	table := profile.AttributeTable()
+	indices := sample.AttributeIndices()
+	attrs.EnsureCapacity(indices.Len())
+	for i := 0; i < indices.Len(); i++ {
+		idx := int(indices.At(i))
+		if idx < 0 || idx >= table.Len() {
+			continue
+		}
+		attr := table.At(idx)
+		attr.Value().CopyTo(attrs.PutEmpty(attr.Key()))
+	}
+	return attrs
+}
+
+// DefaultSampleTypeIndex returns the index of the default sample type of the
+// profile. As per the pprof format, if no default sample type is set then the
+// last sample type is used. Returns -1 if the profile has no sample types.
+func DefaultSampleTypeIndex(profile pprofile.Profile) int {
+	sampleTypes := profile.SampleType()
+	if sampleTypes.Len() == 0 {
+		return -1
+	}
+	if defaultIdx := profile.DefaultSampleTypeStrindex(); defaultIdx != 0 {
+		for i := 0; i < sampleTypes.Len(); i++ {
+			if sampleTypes.At(i).TypeStrindex() == defaultIdx {
+				return i
+			}
+		}
+	}
+	return sampleTypes.Len() - 1
+}
+
+// forEachFunction calls fn for every function in the stack of the sample,
+// starting from the leaf, until fn returns false. Inlined functions are
+// reported before the function they were inlined into.
+func forEachFunction(sample pprofile.Sample, profile pprofile.Profile, fn func(pprofile.Function) bool) {
+	locationIndices := profile.LocationIndices()
+	locations := profile.LocationTable()
+	functions := profile.FunctionTable()
+	start := int(sample.LocationsStartIndex())
+	end := start + int(sample.LocationsLength())
+	for i := start; i < end && i < locationIndices.Len(); i++ {
+		locIdx := int(locationIndices.At(i))
+		if locIdx < 0 || locIdx >= locations.Len() {
+			continue
+		}
+		lines := locations.At(locIdx).Line()
+		for j := 0; j < lines.Len(); j++ {
+			fnIdx := int(lines.At(j).FunctionIndex())
+			if fnIdx < 0 || fnIdx >= functions.Len() {
+				continue
+			}
+			if !fn(functions.At(fnIdx)) {
+				return
+			}
+		}
+	}
+}
+
+// leafFunction returns the innermost function of the sample's stack.
+func leafFunction(sample pprofile.Sample, profile pprofile.Profile) (pprofile.Function, bool) {
+	var (
+		leaf  pprofile.Function
+		found bool
+	)
+	forEachFunction(sample, profile, func(f pprofile.Function) bool {
+		leaf, found = f, true
+		return false
+	})
+	return leaf, found
+}
+
+func stringAt(profile pprofile.Profile, idx int32) string {
+	table := profile.StringTable()
+	if idx < 0 || int(idx) >= table.Len() {
+		return ""
+	}
+	return table.At(int(idx))
+}
@@ -0,0 +1,396 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ottlprofile // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottlprofile"
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pprofile"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/internal"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/internal/logging"
+)
+
+const (
+	// Experimental: *NOTE* this constant is subject to change or removal in the future.
+	ContextName            = "profile"
+	contextNameDescription = "Profile Sample"
+)
+
+var (
+	_ internal.ResourceContext             = (*TransformContext)(nil)
+	_ internal.InstrumentationScopeContext = (*TransformContext)(nil)
+	_ zapcore.ObjectMarshaler              = (*TransformContext)(nil)
+)
+
+var errReadOnlyPath = errors.New("path is read-only in the profile context")
+
+// TransformContext is the transform context for a single sample of a profile.
+// Profiles store sample attributes, functions and strings in lookup tables
+// shared by all samples of a profile; the context resolves these lookups so
+// that samples can be accessed like any other OTLP record.
+type TransformContext struct {
+	sample               pprofile.Sample
+	profile              pprofile.Profile
+	instrumentationScope pcommon.InstrumentationScope
+	resource             pcommon.Resource
+	cache                pcommon.Map
+	scopeProfiles        pprofile.ScopeProfiles
+	resourceProfiles     pprofile.ResourceProfiles
+}
+
+func (tCtx TransformContext) MarshalLogObject(encoder zapcore.ObjectEncoder) error {
+	err := encoder.AddObject("resource", logging.Resource(tCtx.resource))
+	err = errors.Join(err, encoder.AddObject("scope", logging.InstrumentationScope(tCtx.instrumentationScope)))
+	err = errors.Join(err, encoder.AddObject("attributes", logging.Map(SampleAttributes(tCtx.sample, tCtx.profile))))
+	err = errors.Join(err, encoder.AddArray("values", logging.Int64Slice(tCtx.sample.Value())))
+	err = errors.Join(err, encoder.AddObject("cache", logging.Map(tCtx.cache)))
+	return err
+}
+
+type Option func(*ottl.Parser[TransformContext])
+
+type TransformContextOption func(*TransformContext)
+
+func NewTransformContext(sample pprofile.Sample, profile pprofile.Profile, instrumentationScope pcommon.InstrumentationScope, resource pcommon.Resource, scopeProfiles pprofile.ScopeProfiles, resourceProfiles pprofile.ResourceProfiles, options ...TransformContextOption) TransformContext {
+	tc := TransformContext{
+		sample:               sample,
+		profile:              profile,
+		instrumentationScope: instrumentationScope,
+		resource:             resource,
+		cache:                pcommon.NewMap(),
+		scopeProfiles:        scopeProfiles,
+		resourceProfiles:     resourceProfiles,
+	}
+	for _, opt := range options {
+		opt(&tc)
+	}
+	return tc
+}
+
+// Experimental: *NOTE* this option is subject to change or removal in the future.
+func WithCache(cache *pcommon.Map) TransformContextOption {
+	return func(p *TransformContext) {
+		if cache != nil {
+			p.cache = *cache
+		}
+	}
+}
+
+func (tCtx TransformContext) GetSample() pprofile.Sample {
+	return tCtx.sample
+}
+
+func (tCtx TransformContext) GetProfile() pprofile.Profile {
+	return tCtx.profile
+}
+
+func (tCtx TransformContext) GetInstrumentationScope() pcommon.InstrumentationScope {
+	return tCtx.instrumentationScope
+}
+
+func (tCtx TransformContext) GetResource() pcommon.Resource {
+	return tCtx.resource
+}
+
+func (tCtx TransformContext) getCache() pcommon.Map {
+	return tCtx.cache
+}
+
+func (tCtx TransformContext) GetScopeSchemaURLItem() internal.SchemaURLItem {
+	return tCtx.scopeProfiles
+}
+
+func (tCtx TransformContext) GetResourceSchemaURLItem() internal.SchemaURLItem {
+	return tCtx.resourceProfiles
+}
+
+func NewParser(functions map[string]ottl.Factory[TransformContext], telemetrySettings component.TelemetrySettings, options ...Option) (ottl.Parser[TransformContext], error) {
+	pep := pathExpressionParser{telemetrySettings}
+	p, err := ottl.NewParser[TransformContext](
+		functions,
+		pep.parsePath,
+		telemetrySettings,
+		ottl.WithEnumParser[TransformContext](parseEnum),
+	)
+	if err != nil {
+		return ottl.Parser[TransformContext]{}, err
+	}
+	for _, opt := range options {
+		opt(&p)
+	}
+	return p, nil
+}
+
+// EnablePathContextNames enables the support to path's context names on statements.
+// When this option is configured, all statement's paths must have a valid context prefix,
+// otherwise an error is reported.
+//
+// Experimental: *NOTE* this option is subject to change or removal in the future.
+func EnablePathContextNames() Option {
+	return func(p *ottl.Parser[TransformContext]) {
+		ottl.WithPathContextNames[TransformContext]([]string{
+			ContextName,
+			internal.ResourceContextName,
+			internal.InstrumentationScopeContextName,
+		})(p)
+	}
+}
+
+type StatementSequenceOption func(*ottl.StatementSequence[TransformContext])
+
+func WithStatementSequenceErrorMode(errorMode ottl.ErrorMode) StatementSequenceOption {
+	return func(s *ottl.StatementSequence[TransformContext]) {
+		ottl.WithStatementSequenceErrorMode[TransformContext](errorMode)(s)
+	}
+}
+
+func NewStatementSequence(statements []*ottl.Statement[TransformContext], telemetrySettings component.TelemetrySettings, options ...StatementSequenceOption) ottl.StatementSequence[TransformContext] {
+	s := ottl.NewStatementSequence(statements, telemetrySettings)
+	for _, op := range options {
+		op(&s)
+	}
+	return s
+}
+
+type ConditionSequenceOption func(*ottl.ConditionSequence[TransformContext])
+
+func WithConditionSequenceErrorMode(errorMode ottl.ErrorMode) ConditionSequenceOption {
+	return func(c *ottl.ConditionSequence[TransformContext]) {
+		ottl.WithConditionSequenceErrorMode[TransformContext](errorMode)(c)
+	}
+}
+
+func NewConditionSequence(conditions []*ottl.Condition[TransformContext], telemetrySettings component.TelemetrySettings, options ...ConditionSequenceOption) ottl.ConditionSequence[TransformContext] {
+	c := ottl.NewConditionSequence(conditions, telemetrySettings)
+	for _, op := range options {
+		op(&c)
+	}
+	return c
+}
+
+func parseEnum(val *ottl.EnumSymbol) (*ottl.Enum, error) {
+	if val != nil {
+		return nil, fmt.Errorf("enum symbol, %s, not found", *val)
+	}
+	return nil, fmt.Errorf("enum symbol not provided")
+}
+
+type pathExpressionParser struct {
+	telemetrySettings component.TelemetrySettings
+}
+
+func (pep *pathExpressionParser) parsePath(path ottl.Path[TransformContext]) (ottl.GetSetter[TransformContext], error) {
+	if path == nil {
+		return nil, fmt.Errorf("path cannot be nil")
+	}
+	// Higher contexts parsing
+	if path.Context() != "" && path.Context() != ContextName {
+		return pep.parseHigherContextPath(path.Context(), path)
+	}
+	// Backward compatibility with paths without context
+	if path.Context() == "" && (path.Name() == internal.ResourceContextName || path.Name() == internal.InstrumentationScopeContextName) {
+		return pep.parseHigherContextPath(path.Name(), path.Next())
+	}
+
+	switch path.Name() {
+	case "cache":
+		if path.Keys() == nil {
+			return accessCache(), nil
+		}
+		return accessCacheKey(path.Keys()), nil
+	case "attributes":
+		if path.Keys() == nil {
+			return accessAttributes(), nil
+		}
+		return accessAttributesKey(path.Keys()), nil
+	case "profile_id":
+		return accessProfileID(), nil
+	case "time_unix_nano":
+		return accessTimeUnixNano(), nil
+	case "time":
+		return accessTime(), nil
+	case "duration_unix_nano":
+		return accessDurationUnixNano(), nil
+	case "sample_type":
+		return accessSampleType(), nil
+	case "sample_unit":
+		return accessSampleUnit(), nil
+	case "value":
+		return accessValue(), nil
+	case "values":
+		return accessValues(), nil
+	case "function_name":
+		return accessFunctionName(), nil
+	case "function_names":
+		return accessFunctionNames(), nil
+	case "file_name":
+		return accessFileName(), nil
+	default:
+		return nil, internal.FormatDefaultErrorMessage(path.Name(), path.String(), contextNameDescription, internal.ProfileRef)
+	}
+}
+
+func (pep *pathExpressionParser) parseHigherContextPath(context string, path ottl.Path[TransformContext]) (ottl.GetSetter[TransformContext], error) {
+	switch context {
+	case internal.ResourceContextName:
+		return internal.ResourcePathGetSetter(ContextName, path)
+	case internal.InstrumentationScopeContextName:
+		return internal.ScopePathGetSetter(ContextName, path)
+	default:
+		var fullPath string
+		if path != nil {
+			fullPath = path.String()
+		}
+		return nil, internal.FormatDefaultErrorMessage(context, fullPath, contextNameDescription, internal.ProfileRef)
+	}
+}
+
+func accessCache() ottl.StandardGetSetter[TransformContext] {
+	return ottl.StandardGetSetter[TransformContext]{
+		Getter: func(_ context.Context, tCtx TransformContext) (any, error) {
+			return tCtx.getCache(), nil
+		},
+		Setter: func(_ context.Context, tCtx TransformContext, val any) error {
+			if m, ok := val.(pcommon.Map); ok {
+				m.CopyTo(tCtx.getCache())
+			}
+			return nil
+		},
+	}
+}
+
+func accessCacheKey(key []ottl.Key[TransformContext]) ottl.StandardGetSetter[TransformContext] {
+	return ottl.StandardGetSetter[TransformContext]{
+		Getter: func(ctx context.Context, tCtx TransformContext) (any, error) {
+			return internal.GetMapValue[TransformContext](ctx, tCtx, tCtx.getCache(), key)
+		},
+		Setter: func(ctx context.Context, tCtx TransformContext, val any) error {
+			return internal.SetMapValue[TransformContext](ctx, tCtx, tCtx.getCache(), key, val)
+		},
+	}
+}
+
+func accessAttributes() ottl.StandardGetSetter[TransformContext] {
+	return readOnly(func(_ context.Context, tCtx TransformContext) (any, error) {
+		return SampleAttributes(tCtx.GetSample(), tCtx.GetProfile()), nil
+	})
+}
+
+func accessAttributesKey(key []ottl.Key[TransformContext]) ottl.StandardGetSetter[TransformContext] {
+	return readOnly(func(ctx context.Context, tCtx TransformContext) (any, error) {
+		return internal.GetMapValue[TransformContext](ctx, tCtx, SampleAttributes(tCtx.GetSample(), tCtx.GetProfile()), key)
+	})
+}
+
+func accessProfileID() ottl.StandardGetSetter[TransformContext] {
+	return readOnly(func(_ context.Context, tCtx TransformContext) (any, error) {
+		id := tCtx.GetProfile().ProfileID()
+		return hex.EncodeToString(id[:]), nil
+	})
+}
+
+func accessTimeUnixNano() ottl.StandardGetSetter[TransformContext] {
+	return readOnly(func(_ context.Context, tCtx TransformContext) (any, error) {
+		return tCtx.GetProfile().Time().AsTime().UnixNano(), nil
+	})
+}
+
+func accessTime() ottl.StandardGetSetter[TransformContext] {
+	return readOnly(func(_ context.Context, tCtx TransformContext) (any, error) {
+		return tCtx.GetProfile().Time().AsTime(), nil
+	})
+}
+
+func accessDurationUnixNano() ottl.StandardGetSetter[TransformContext] {
+	return readOnly(func(_ context.Context, tCtx TransformContext) (any, error) {
+		return int64(tCtx.GetProfile().Duration()), nil
+	})
+}
+
+func accessSampleType() ottl.StandardGetSetter[TransformContext] {
+	return readOnly(func(_ context.Context, tCtx TransformContext) (any, error) {
+		idx := DefaultSampleTypeIndex(tCtx.GetProfile())
+		if idx < 0 {
+			return "", nil
+		}
+		return stringAt(tCtx.GetProfile(), tCtx.GetProfile().SampleType().At(idx).TypeStrindex()), nil
+	})
+}
+
+func accessSampleUnit() ottl.StandardGetSetter[TransformContext] {
+	return readOnly(func(_ context.Context, tCtx TransformContext) (any, error) {
+		idx := DefaultSampleTypeIndex(tCtx.GetProfile())
+		if idx < 0 {
+			return "", nil
+		}
+		return stringAt(tCtx.GetProfile(), tCtx.GetProfile().SampleType().At(idx).UnitStrindex()), nil
+	})
+}
+
+func accessValue() ottl.StandardGetSetter[TransformContext] {
+	return readOnly(func(_ context.Context, tCtx TransformContext) (any, error) {
+		idx := DefaultSampleTypeIndex(tCtx.GetProfile())
+		if idx < 0 || idx >= tCtx.GetSample().Value().Len() {
+			return nil, nil
+		}
+		return tCtx.GetSample().Value().At(idx), nil
+	})
+}
+
+func accessValues() ottl.StandardGetSetter[TransformContext] {
+	return readOnly(func(_ context.Context, tCtx TransformContext) (any, error) {
+		return tCtx.GetSample().Value().AsRaw(), nil
+	})
+}
+
+func accessFunctionName() ottl.StandardGetSetter[TransformContext] {
+	return readOnly(func(_ context.Context, tCtx TransformContext) (any, error) {
+		fn, ok := leafFunction(tCtx.GetSample(), tCtx.GetProfile())
+		if !ok {
+			return nil, nil
+		}
+		return stringAt(tCtx.GetProfile(), fn.NameStrindex()), nil
+	})
+}
+
+func accessFunctionNames() ottl.StandardGetSetter[TransformContext] {
+	return readOnly(func(_ context.Context, tCtx TransformContext) (any, error) {
+		names := pcommon.NewSlice()
+		profile := tCtx.GetProfile()
+		forEachFunction(tCtx.GetSample(), profile, func(fn pprofile.Function) bool {
+			names.AppendEmpty().SetStr(stringAt(profile, fn.NameStrindex()))
+			return true
+		})
+		return names, nil
+	})
+}
+
+func accessFileName() ottl.StandardGetSetter[TransformContext] {
+	return readOnly(func(_ context.Context, tCtx TransformContext) (any, error) {
+		fn, ok := leafFunction(tCtx.GetSample(), tCtx.GetProfile())
+		if !ok {
+			return nil, nil
+		}
+		return stringAt(tCtx.GetProfile(), fn.FilenameStrindex()), nil
+	})
+}
+
+// readOnly wraps a getter for a value that is derived from the profile lookup
+// tables and thus cannot be set in place.
+func readOnly(getter ottl.ExprFunc[TransformContext]) ottl.StandardGetSetter[TransformContext] {
+	return ottl.StandardGetSetter[TransformContext]{
+		Getter: getter,
+		Setter: func(context.Context, TransformContext, any) error {
+			return errReadOnlyPath
+		},
+	}
+}
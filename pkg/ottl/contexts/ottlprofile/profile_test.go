@@ -0,0 +1,265 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ottlprofile
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pprofile"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/internal"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/ottltest"
+)
+
+func Test_newPathGetSetter(t *testing.T) {
+	sample, profile, il, resource := createTelemetry()
+
+	functionNames := pcommon.NewSlice()
+	functionNames.AppendEmpty().SetStr("inlined")
+	functionNames.AppendEmpty().SetStr("leaf")
+	functionNames.AppendEmpty().SetStr("main")
+
+	attrs := pcommon.NewMap()
+	attrs.PutStr("thread.name", "worker")
+	attrs.PutInt("thread.id", 7)
+
+	tests := []struct {
+		name string
+		path ottl.Path[TransformContext]
+		want any
+	}{
+		{
+			name: "attributes",
+			path: &internal.TestPath[TransformContext]{N: "attributes"},
+			want: attrs,
+		},
+		{
+			name: "attributes string",
+			path: &internal.TestPath[TransformContext]{
+				N: "attributes",
+				KeySlice: []ottl.Key[TransformContext]{
+					&internal.TestKey[TransformContext]{S: ottltest.Strp("thread.name")},
+				},
+			},
+			want: "worker",
+		},
+		{
+			name: "profile_id",
+			path: &internal.TestPath[TransformContext]{N: "profile_id"},
+			want: "0102030405060708090a0b0c0d0e0f10",
+		},
+		{
+			name: "time_unix_nano",
+			path: &internal.TestPath[TransformContext]{N: "time_unix_nano"},
+			want: int64(100_000_000),
+		},
+		{
+			name: "time",
+			path: &internal.TestPath[TransformContext]{N: "time"},
+			want: time.Date(1970, 1, 1, 0, 0, 0, 100000000, time.UTC),
+		},
+		{
+			name: "duration_unix_nano",
+			path: &internal.TestPath[TransformContext]{N: "duration_unix_nano"},
+			want: int64(10_000_000_000),
+		},
+		{
+			name: "sample_type",
+			path: &internal.TestPath[TransformContext]{N: "sample_type"},
+			want: "cpu",
+		},
+		{
+			name: "sample_unit",
+			path: &internal.TestPath[TransformContext]{N: "sample_unit"},
+			want: "nanoseconds",
+		},
+		{
+			name: "value",
+			path: &internal.TestPath[TransformContext]{N: "value"},
+			want: int64(20_000_000),
+		},
+		{
+			name: "values",
+			path: &internal.TestPath[TransformContext]{N: "values"},
+			want: []int64{2, 20_000_000},
+		},
+		{
+			name: "function_name",
+			path: &internal.TestPath[TransformContext]{N: "function_name"},
+			want: "inlined",
+		},
+		{
+			name: "function_names",
+			path: &internal.TestPath[TransformContext]{N: "function_names"},
+			want: functionNames,
+		},
+		{
+			name: "file_name",
+			path: &internal.TestPath[TransformContext]{N: "file_name"},
+			want: "leaf.go",
+		},
+		{
+			name: "resource attributes",
+			path: &internal.TestPath[TransformContext]{
+				N:        "resource",
+				NextPath: &internal.TestPath[TransformContext]{N: "attributes"},
+			},
+			want: resource.Attributes(),
+		},
+		{
+			name: "instrumentation_scope name",
+			path: &internal.TestPath[TransformContext]{
+				N:        "instrumentation_scope",
+				NextPath: &internal.TestPath[TransformContext]{N: "name"},
+			},
+			want: "library",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pep := pathExpressionParser{}
+			accessor, err := pep.parsePath(tt.path)
+			require.NoError(t, err)
+
+			tCtx := NewTransformContext(sample, profile, il, resource, pprofile.NewScopeProfiles(), pprofile.NewResourceProfiles())
+			got, err := accessor.Get(context.Background(), tCtx)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_readOnlyPaths(t *testing.T) {
+	sample, profile, il, resource := createTelemetry()
+	pep := pathExpressionParser{}
+	accessor, err := pep.parsePath(&internal.TestPath[TransformContext]{N: "value"})
+	require.NoError(t, err)
+
+	tCtx := NewTransformContext(sample, profile, il, resource, pprofile.NewScopeProfiles(), pprofile.NewResourceProfiles())
+	assert.ErrorIs(t, accessor.Set(context.Background(), tCtx, int64(1)), errReadOnlyPath)
+}
+
+func Test_newPathGetSetter_Cache(t *testing.T) {
+	sample, profile, il, resource := createTelemetry()
+	pep := pathExpressionParser{}
+	accessor, err := pep.parsePath(&internal.TestPath[TransformContext]{
+		N: "cache",
+		KeySlice: []ottl.Key[TransformContext]{
+			&internal.TestKey[TransformContext]{S: ottltest.Strp("temp")},
+		},
+	})
+	require.NoError(t, err)
+
+	tCtx := NewTransformContext(sample, profile, il, resource, pprofile.NewScopeProfiles(), pprofile.NewResourceProfiles())
+	require.NoError(t, accessor.Set(context.Background(), tCtx, "new value"))
+	got, err := accessor.Get(context.Background(), tCtx)
+	require.NoError(t, err)
+	assert.Equal(t, "new value", got)
+}
+
+func Test_invalidPath(t *testing.T) {
+	pep := pathExpressionParser{}
+	_, err := pep.parsePath(&internal.TestPath[TransformContext]{N: "invalid"})
+	assert.ErrorContains(t, err, `segment "invalid" from path "invalid" is not a valid path`)
+}
+
+func Test_Parser(t *testing.T) {
+	sample, profile, il, resource := createTelemetry()
+	parser, err := NewParser(nil, componenttest.NewNopTelemetrySettings())
+	require.NoError(t, err)
+
+	cond, err := parser.ParseCondition(`sample_type == "cpu" and attributes["thread.name"] == "worker" and function_name == "inlined"`)
+	require.NoError(t, err)
+
+	tCtx := NewTransformContext(sample, profile, il, resource, pprofile.NewScopeProfiles(), pprofile.NewResourceProfiles())
+	match, err := cond.Eval(context.Background(), tCtx)
+	require.NoError(t, err)
+	assert.True(t, match)
+}
+
+func Test_DefaultSampleTypeIndex(t *testing.T) {
+	_, profile, _, _ := createTelemetry()
+	assert.Equal(t, 1, DefaultSampleTypeIndex(profile))
+
+	// Without an explicit default the last sample type is used.
+	profile.SetDefaultSampleTypeStrindex(0)
+	assert.Equal(t, 1, DefaultSampleTypeIndex(profile))
+	profile.SetDefaultSampleTypeStrindex(1)
+	assert.Equal(t, 0, DefaultSampleTypeIndex(profile))
+
+	assert.Equal(t, -1, DefaultSampleTypeIndex(pprofile.NewProfile()))
+}
+
+func createTelemetry() (pprofile.Sample, pprofile.Profile, pcommon.InstrumentationScope, pcommon.Resource) {
+	profile := pprofile.NewProfile()
+	profile.SetProfileID(pprofile.ProfileID([16]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}))
+	profile.SetTime(pcommon.NewTimestampFromTime(time.UnixMilli(100)))
+	profile.SetDuration(pcommon.Timestamp(10 * time.Second))
+	profile.StringTable().Append(
+		"",            // 0
+		"samples",     // 1
+		"count",       // 2
+		"cpu",         // 3
+		"nanoseconds", // 4
+		"main",        // 5
+		"main.go",     // 6
+		"leaf",        // 7
+		"leaf.go",     // 8
+		"inlined",     // 9
+	)
+
+	samplesType := profile.SampleType().AppendEmpty()
+	samplesType.SetTypeStrindex(1)
+	samplesType.SetUnitStrindex(2)
+	cpuType := profile.SampleType().AppendEmpty()
+	cpuType.SetTypeStrindex(3)
+	cpuType.SetUnitStrindex(4)
+	profile.SetDefaultSampleTypeStrindex(3)
+
+	mainFn := profile.FunctionTable().AppendEmpty()
+	mainFn.SetNameStrindex(5)
+	mainFn.SetFilenameStrindex(6)
+	leafFn := profile.FunctionTable().AppendEmpty()
+	leafFn.SetNameStrindex(7)
+	leafFn.SetFilenameStrindex(8)
+	inlinedFn := profile.FunctionTable().AppendEmpty()
+	inlinedFn.SetNameStrindex(9)
+	inlinedFn.SetFilenameStrindex(8)
+
+	// Location 0 is main, location 1 is leaf with an inlined function.
+	profile.LocationTable().AppendEmpty().Line().AppendEmpty().SetFunctionIndex(0)
+	leafLoc := profile.LocationTable().AppendEmpty()
+	leafLoc.Line().AppendEmpty().SetFunctionIndex(2)
+	leafLoc.Line().AppendEmpty().SetFunctionIndex(1)
+	profile.LocationIndices().Append(1, 0)
+
+	threadName := profile.AttributeTable().AppendEmpty()
+	threadName.SetKey("thread.name")
+	threadName.Value().SetStr("worker")
+	threadID := profile.AttributeTable().AppendEmpty()
+	threadID.SetKey("thread.id")
+	threadID.Value().SetInt(7)
+
+	sample := profile.Sample().AppendEmpty()
+	sample.SetLocationsStartIndex(0)
+	sample.SetLocationsLength(2)
+	sample.Value().Append(2, 20_000_000)
+	sample.AttributeIndices().Append(0, 1)
+
+	il := pcommon.NewInstrumentationScope()
+	il.SetName("library")
+	il.SetVersion("version")
+
+	resource := pcommon.NewResource()
+	resource.Attributes().PutStr("service.name", "profiled")
+
+	return sample, profile, il, resource
+}
@@ -18,6 +18,7 @@ require (
 	go.opentelemetry.io/collector/component v0.118.0
 	go.opentelemetry.io/collector/component/componenttest v0.118.0
 	go.opentelemetry.io/collector/pdata v1.24.0
+	go.opentelemetry.io/collector/pdata/pprofile v0.118.0
 	go.opentelemetry.io/collector/semconv v0.118.0
 	go.opentelemetry.io/otel/trace v1.32.0
 	go.uber.org/goleak v1.3.0
@@ -168,6 +168,58 @@ func normalizeName(metric pmetric.Metric, namespace string) string {
 	return normalizedName
 }
 
+// BuildMetricName builds a metric name for consumers that accept UTF-8 metric names.
+//
+// Unlike BuildCompliantName, no character is replaced: the metric name is only prefixed
+// with the specified namespace and underscore (if any) and, when addMetricSuffixes is set,
+// suffixed with the unit, "_total" for counters and "_ratio" for gauges with unit "1".
+// Suffixes already present at the end of the name are not repeated.
+func BuildMetricName(metric pmetric.Metric, namespace string, addMetricSuffixes bool) string {
+	metricName := metric.Name()
+	if namespace != "" {
+		metricName = namespace + "_" + metricName
+	}
+
+	if !addMetricSuffixes {
+		return metricName
+	}
+
+	isCounter := metric.Type() == pmetric.MetricTypeSum && metric.Sum().IsMonotonic()
+	isRatio := metric.Unit() == "1" && metric.Type() == pmetric.MetricTypeGauge
+	if isCounter {
+		metricName = strings.TrimSuffix(metricName, "_total")
+	}
+	if isRatio {
+		metricName = strings.TrimSuffix(metricName, "_ratio")
+	}
+
+	unitTokens := strings.SplitN(metric.Unit(), "/", 2)
+	if mainUnitOtel := strings.TrimSpace(unitTokens[0]); mainUnitOtel != "" && !strings.ContainsAny(mainUnitOtel, "{}") {
+		metricName = appendSuffix(metricName, CleanUpString(unitMapGetOrDefault(mainUnitOtel)))
+	}
+	if len(unitTokens) > 1 {
+		if perUnitOtel := strings.TrimSpace(unitTokens[1]); perUnitOtel != "" && !strings.ContainsAny(perUnitOtel, "{}") {
+			metricName = appendSuffix(metricName, "per_"+CleanUpString(perUnitMapGetOrDefault(perUnitOtel)))
+		}
+	}
+
+	if isCounter {
+		metricName += "_total"
+	}
+	if isRatio {
+		metricName += "_ratio"
+	}
+	return metricName
+}
+
+// Append the specified suffix, separated with an underscore, unless the name already ends with it
+func appendSuffix(name, suffix string) string {
+	if suffix == "" || suffix == "per_" || strings.HasSuffix(name, "_"+suffix) {
+		return name
+	}
+	return name + "_" + suffix
+}
+
 // TrimPromSuffixes trims type and unit prometheus suffixes from a metric name.
 // Following the [OpenTelemetry specs] for converting Prometheus Metric points to OTLP.
 //
@@ -202,3 +202,22 @@ func TestBuildCompliantNameWithoutSuffixes(t *testing.T) {
 	require.Equal(t, ":foo::bar", BuildCompliantName(createGauge(":foo::bar", ""), "", addUnitAndTypeSuffixes))
 	require.Equal(t, ":foo::bar", BuildCompliantName(createCounter(":foo::bar", ""), "", addUnitAndTypeSuffixes))
 }
+
+func TestBuildMetricName(t *testing.T) {
+	addUnitAndTypeSuffixes := true
+	require.Equal(t, "system.io_bytes_total", BuildMetricName(createCounter("system.io", "By"), "", addUnitAndTypeSuffixes))
+	require.Equal(t, "system_network.io_bytes_total", BuildMetricName(createCounter("network.io", "By"), "system", addUnitAndTypeSuffixes))
+	require.Equal(t, "http.server.request.duration_seconds", BuildMetricName(createGauge("http.server.request.duration", "s"), "", addUnitAndTypeSuffixes))
+	require.Equal(t, "network_transmitted_bytes_total", BuildMetricName(createCounter("network_transmitted_bytes_total", "By"), "", addUnitAndTypeSuffixes))
+	require.Equal(t, "system.network.dropped_total", BuildMetricName(createCounter("system.network.dropped", "{packets}"), "", addUnitAndTypeSuffixes))
+	require.Equal(t, "disk.io_bytes_per_second", BuildMetricName(createGauge("disk.io", "By/s"), "", addUnitAndTypeSuffixes))
+	require.Equal(t, "hw.cpu.utilization_ratio", BuildMetricName(createGauge("hw.cpu.utilization", "1"), "", addUnitAndTypeSuffixes))
+	require.Equal(t, "3.14 digits", BuildMetricName(createGauge("3.14 digits", ""), "", addUnitAndTypeSuffixes))
+}
+
+func TestBuildMetricNameWithoutSuffixes(t *testing.T) {
+	addUnitAndTypeSuffixes := false
+	require.Equal(t, "system.io", BuildMetricName(createCounter("system.io", "By"), "", addUnitAndTypeSuffixes))
+	require.Equal(t, "system_network.io", BuildMetricName(createCounter("network.io", "By"), "system", addUnitAndTypeSuffixes))
+	require.Equal(t, "hw.cpu.utilization", BuildMetricName(createGauge("hw.cpu.utilization", "1"), "", addUnitAndTypeSuffixes))
+}
@@ -526,6 +526,22 @@ func addResourceTargetInfo(resource pcommon.Resource, settings Settings, timesta
 		return
 	}
 
+	labels := targetInfoLabels(resource, settings)
+	if labels == nil {
+		return
+	}
+
+	sample := &prompb.Sample{
+		Value: float64(1),
+		// convert ns to ms
+		Timestamp: convertTimeStamp(timestamp),
+	}
+	converter.addSample(sample, labels)
+}
+
+// targetInfoLabels returns the labels of the target info metric for the resource, or nil if the
+// target info metric isn't useful for it.
+func targetInfoLabels(resource pcommon.Resource, settings Settings) []prompb.Label {
 	attributes := resource.Attributes()
 	identifyingAttrs := []string{
 		conventions.AttributeServiceNamespace,
@@ -541,7 +557,7 @@ func addResourceTargetInfo(resource pcommon.Resource, settings Settings, timesta
 	}
 	if nonIdentifyingAttrsCount == 0 {
 		// If we only have job + instance, then target_info isn't useful, so don't add it.
-		return
+		return nil
 	}
 
 	name := prometheustranslator.TargetInfoMetricName
@@ -550,25 +566,14 @@ func addResourceTargetInfo(resource pcommon.Resource, settings Settings, timesta
 	}
 
 	labels := createAttributes(resource, attributes, settings.ExternalLabels, identifyingAttrs, false, model.MetricNameLabel, name)
-	haveIdentifier := false
 	for _, l := range labels {
 		if l.Name == model.JobLabel || l.Name == model.InstanceLabel {
-			haveIdentifier = true
-			break
+			return labels
 		}
 	}
 
-	if !haveIdentifier {
-		// We need at least one identifying label to generate target_info.
-		return
-	}
-
-	sample := &prompb.Sample{
-		Value: float64(1),
-		// convert ns to ms
-		Timestamp: convertTimeStamp(timestamp),
-	}
-	converter.addSample(sample, labels)
+	// We need at least one identifying label to generate target_info.
+	return nil
 }
 
 // convertTimeStamp converts OTLP timestamp in ns to timestamp in ms
@@ -0,0 +1,124 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package prometheusremotewrite // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/translator/prometheusremotewrite"
+
+import (
+	"math"
+	"strconv"
+
+	"github.com/prometheus/prometheus/model/value"
+	writev2 "github.com/prometheus/prometheus/prompb/io/prometheus/write/v2"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+)
+
+// bucketBoundsDataV2 is the remote write 2.0 equivalent of bucketBoundsData.
+type bucketBoundsDataV2 struct {
+	ts    *writev2.TimeSeries
+	bound float64
+}
+
+func (c *prometheusConverterV2) addHistogramDataPoints(dataPoints pmetric.HistogramDataPointSlice,
+	resource pcommon.Resource, settings Settings, baseName string, metadata writev2.Metadata,
+) {
+	for x := 0; x < dataPoints.Len(); x++ {
+		pt := dataPoints.At(x)
+		timestamp := convertTimeStamp(pt.Timestamp())
+		createdTimestamp := convertTimeStamp(pt.StartTimestamp())
+		baseLabels := createAttributes(resource, pt.Attributes(), settings.ExternalLabels, nil, false)
+
+		addSample := func(v float64, name string, extras ...string) *writev2.TimeSeries {
+			sample := &writev2.Sample{Value: v, Timestamp: timestamp}
+			if pt.Flags().NoRecordedValue() {
+				sample.Value = math.Float64frombits(value.StaleNaN)
+			}
+			ts := c.addSample(sample, createLabels(name, baseLabels, extras...), metadata)
+			ts.CreatedTimestamp = createdTimestamp
+			return ts
+		}
+
+		// If the sum is unset, it indicates the _sum metric point should be
+		// omitted
+		if pt.HasSum() {
+			addSample(pt.Sum(), baseName+sumStr)
+		}
+		addSample(float64(pt.Count()), baseName+countStr)
+
+		// cumulative count for conversion to cumulative histogram
+		var cumulativeCount uint64
+		var bucketBounds []bucketBoundsDataV2
+
+		// process each bound, based on histograms proto definition, # of buckets = # of explicit bounds + 1
+		for i := 0; i < pt.ExplicitBounds().Len() && i < pt.BucketCounts().Len(); i++ {
+			bound := pt.ExplicitBounds().At(i)
+			cumulativeCount += pt.BucketCounts().At(i)
+			boundStr := strconv.FormatFloat(bound, 'f', -1, 64)
+			ts := addSample(float64(cumulativeCount), baseName+bucketStr, leStr, boundStr)
+			bucketBounds = append(bucketBounds, bucketBoundsDataV2{ts: ts, bound: bound})
+		}
+		// add le=+Inf bucket
+		ts := addSample(float64(pt.Count()), baseName+bucketStr, leStr, pInfStr)
+		bucketBounds = append(bucketBounds, bucketBoundsDataV2{ts: ts, bound: math.Inf(1)})
+
+		// Each exemplar is added to the first bucket which contains it, the explicit bounds are sorted.
+		for _, exemplar := range c.exemplars(getPromExemplars(pt)) {
+			for _, bound := range bucketBounds {
+				if exemplar.Value <= bound.bound {
+					bound.ts.Exemplars = append(bound.ts.Exemplars, exemplar)
+					break
+				}
+			}
+		}
+	}
+}
+
+func (c *prometheusConverterV2) addSummaryDataPoints(dataPoints pmetric.SummaryDataPointSlice, resource pcommon.Resource,
+	settings Settings, baseName string, metadata writev2.Metadata,
+) {
+	for x := 0; x < dataPoints.Len(); x++ {
+		pt := dataPoints.At(x)
+		timestamp := convertTimeStamp(pt.Timestamp())
+		createdTimestamp := convertTimeStamp(pt.StartTimestamp())
+		baseLabels := createAttributes(resource, pt.Attributes(), settings.ExternalLabels, nil, false)
+
+		addSample := func(v float64, name string, extras ...string) {
+			sample := &writev2.Sample{Value: v, Timestamp: timestamp}
+			if pt.Flags().NoRecordedValue() {
+				sample.Value = math.Float64frombits(value.StaleNaN)
+			}
+			ts := c.addSample(sample, createLabels(name, baseLabels, extras...), metadata)
+			ts.CreatedTimestamp = createdTimestamp
+		}
+
+		// sum and count of the summary should append suffix to baseName
+		addSample(pt.Sum(), baseName+sumStr)
+		addSample(float64(pt.Count()), baseName+countStr)
+
+		// process each percentile/quantile
+		for i := 0; i < pt.QuantileValues().Len(); i++ {
+			qt := pt.QuantileValues().At(i)
+			percentileStr := strconv.FormatFloat(qt.Quantile(), 'f', -1, 64)
+			addSample(qt.Value(), baseName, quantileStr, percentileStr)
+		}
+	}
+}
+
+// addResourceTargetInfoV2 converts the resource to the target info metric.
+func addResourceTargetInfoV2(resource pcommon.Resource, settings Settings, timestamp pcommon.Timestamp, converter *prometheusConverterV2) {
+	if settings.DisableTargetInfo || timestamp == 0 {
+		return
+	}
+
+	labels := targetInfoLabels(resource, settings)
+	if labels == nil {
+		return
+	}
+
+	sample := &writev2.Sample{
+		Value: float64(1),
+		// convert ns to ms
+		Timestamp: convertTimeStamp(timestamp),
+	}
+	converter.addSample(sample, labels, writev2.Metadata{Type: writev2.Metadata_METRIC_TYPE_GAUGE})
+}
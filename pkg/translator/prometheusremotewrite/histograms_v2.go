@@ -0,0 +1,114 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package prometheusremotewrite // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/translator/prometheusremotewrite"
+
+import (
+	"math"
+
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/histogram"
+	"github.com/prometheus/prometheus/model/value"
+	writev2 "github.com/prometheus/prometheus/prompb/io/prometheus/write/v2"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+)
+
+func (c *prometheusConverterV2) addExponentialHistogramDataPoints(dataPoints pmetric.ExponentialHistogramDataPointSlice,
+	resource pcommon.Resource, settings Settings, baseName string, metadata writev2.Metadata,
+) error {
+	for x := 0; x < dataPoints.Len(); x++ {
+		pt := dataPoints.At(x)
+		lbls := createAttributes(
+			resource,
+			pt.Attributes(),
+			settings.ExternalLabels,
+			nil,
+			true,
+			model.MetricNameLabel,
+			baseName,
+		)
+
+		h, err := exponentialToNativeHistogram(pt)
+		if err != nil {
+			return err
+		}
+
+		ts := c.createTimeSeries(lbls, metadata)
+		ts.Histograms = []writev2.Histogram{writev2.FromIntHistogram(h.Timestamp, h.ToIntHistogram())}
+		ts.Exemplars = c.exemplars(getPromExemplars[pmetric.ExponentialHistogramDataPoint](pt))
+		ts.CreatedTimestamp = convertTimeStamp(pt.StartTimestamp())
+	}
+
+	return nil
+}
+
+// addCustomBucketsHistogramDataPoints translates OTel explicit bucket histogram data points to
+// Prometheus native histograms with custom buckets (NHCB).
+func (c *prometheusConverterV2) addCustomBucketsHistogramDataPoints(dataPoints pmetric.HistogramDataPointSlice,
+	resource pcommon.Resource, settings Settings, baseName string, metadata writev2.Metadata,
+) {
+	for x := 0; x < dataPoints.Len(); x++ {
+		pt := dataPoints.At(x)
+		lbls := createAttributes(
+			resource,
+			pt.Attributes(),
+			settings.ExternalLabels,
+			nil,
+			true,
+			model.MetricNameLabel,
+			baseName,
+		)
+
+		spans, deltas := customBucketsLayout(pt.BucketCounts())
+		h := &histogram.Histogram{
+			// See exponentialToNativeHistogram for why the counter reset hint is unknown.
+			CounterResetHint: histogram.UnknownCounterReset,
+			Schema:           histogram.CustomBucketsSchema,
+			PositiveSpans:    spans,
+			PositiveBuckets:  deltas,
+			CustomValues:     pt.ExplicitBounds().AsRaw(),
+		}
+		if pt.Flags().NoRecordedValue() {
+			h.Sum = math.Float64frombits(value.StaleNaN)
+			h.Count = value.StaleNaN
+		} else {
+			if pt.HasSum() {
+				h.Sum = pt.Sum()
+			}
+			h.Count = pt.Count()
+		}
+
+		ts := c.createTimeSeries(lbls, metadata)
+		ts.Histograms = []writev2.Histogram{writev2.FromIntHistogram(convertTimeStamp(pt.Timestamp()), h)}
+		ts.Exemplars = c.exemplars(getPromExemplars(pt))
+		ts.CreatedTimestamp = convertTimeStamp(pt.StartTimestamp())
+	}
+}
+
+// customBucketsLayout translates OTel explicit bucket counts to the sparse bucket representation of
+// Prometheus native histograms with custom buckets, where bucket i is bounded by the explicit bound i.
+// Empty buckets are omitted.
+func customBucketsLayout(bucketCounts pcommon.UInt64Slice) ([]histogram.Span, []int64) {
+	var (
+		spans     []histogram.Span
+		deltas    []int64
+		prevCount int64
+		gap       uint32
+	)
+	for i := 0; i < bucketCounts.Len(); i++ {
+		count := int64(bucketCounts.At(i))
+		if count == 0 {
+			gap++
+			continue
+		}
+		if len(spans) == 0 || gap > 0 {
+			spans = append(spans, histogram.Span{Offset: int32(gap)})
+			gap = 0
+		}
+		spans[len(spans)-1].Length++
+		deltas = append(deltas, count-prevCount)
+		prevCount = count
+	}
+	return spans, deltas
+}
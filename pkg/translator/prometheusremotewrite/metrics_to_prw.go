@@ -24,6 +24,10 @@ type Settings struct {
 	ExportCreatedMetric bool
 	AddMetricSuffixes   bool
 	SendMetadata        bool
+
+	// ConvertHistogramsToNHCB converts explicit bucket histograms to native histograms with
+	// custom buckets instead of classic histogram series. Only used by FromMetricsV2.
+	ConvertHistogramsToNHCB bool
 }
 
 // FromMetrics converts pmetric.Metrics to Prometheus remote write format.
@@ -69,6 +69,8 @@ func (c *prometheusConverterV2) fromMetrics(md pmetric.Metrics, settings Setting
 
 				promName := prometheustranslator.BuildCompliantName(metric, settings.Namespace, settings.AddMetricSuffixes)
 
+				metadata := c.metadata(metric, settings)
+
 				// handle individual metrics based on type
 				//exhaustive:enforce
 				switch metric.Type() {
@@ -78,22 +80,51 @@ func (c *prometheusConverterV2) fromMetrics(md pmetric.Metrics, settings Setting
 						errs = multierr.Append(errs, fmt.Errorf("empty data points. %s is dropped", metric.Name()))
 						break
 					}
-					c.addGaugeNumberDataPoints(dataPoints, resource, settings, promName)
+					c.addGaugeNumberDataPoints(dataPoints, resource, settings, promName, metadata)
 				case pmetric.MetricTypeSum:
-					// TODO implement
+					dataPoints := metric.Sum().DataPoints()
+					if dataPoints.Len() == 0 {
+						errs = multierr.Append(errs, fmt.Errorf("empty data points. %s is dropped", metric.Name()))
+						break
+					}
+					c.addSumNumberDataPoints(dataPoints, resource, metric, settings, promName, metadata)
 				case pmetric.MetricTypeHistogram:
-					// TODO implement
+					dataPoints := metric.Histogram().DataPoints()
+					if dataPoints.Len() == 0 {
+						errs = multierr.Append(errs, fmt.Errorf("empty data points. %s is dropped", metric.Name()))
+						break
+					}
+					if settings.ConvertHistogramsToNHCB {
+						c.addCustomBucketsHistogramDataPoints(dataPoints, resource, settings, promName, metadata)
+						break
+					}
+					c.addHistogramDataPoints(dataPoints, resource, settings, promName, metadata)
 				case pmetric.MetricTypeExponentialHistogram:
-					// TODO implement
+					dataPoints := metric.ExponentialHistogram().DataPoints()
+					if dataPoints.Len() == 0 {
+						errs = multierr.Append(errs, fmt.Errorf("empty data points. %s is dropped", metric.Name()))
+						break
+					}
+					errs = multierr.Append(errs, c.addExponentialHistogramDataPoints(
+						dataPoints,
+						resource,
+						settings,
+						promName,
+						metadata,
+					))
 				case pmetric.MetricTypeSummary:
-					// TODO implement
+					dataPoints := metric.Summary().DataPoints()
+					if dataPoints.Len() == 0 {
+						errs = multierr.Append(errs, fmt.Errorf("empty data points. %s is dropped", metric.Name()))
+						break
+					}
+					c.addSummaryDataPoints(dataPoints, resource, settings, promName, metadata)
 				default:
 					errs = multierr.Append(errs, errors.New("unsupported metric type"))
 				}
 			}
 		}
-		// TODO implement
-		// addResourceTargetInfov2(resource, settings, mostRecentTimestamp, c)
+		addResourceTargetInfoV2(resource, settings, mostRecentTimestamp, c)
 	}
 
 	return
@@ -108,25 +139,62 @@ func (c *prometheusConverterV2) timeSeries() []writev2.TimeSeries {
 	return allTS
 }
 
-func (c *prometheusConverterV2) addSample(sample *writev2.Sample, lbls []prompb.Label) *writev2.TimeSeries {
+// metadata returns the metadata of the time series converted from metric. The help and unit
+// are only set if settings.SendMetadata is enabled.
+func (c *prometheusConverterV2) metadata(metric pmetric.Metric, settings Settings) writev2.Metadata {
+	metadata := writev2.Metadata{
+		Type: writev2.Metadata_MetricType(otelMetricTypeToPromMetricType(metric)),
+	}
+	if settings.SendMetadata {
+		metadata.HelpRef = c.symbolTable.Symbolize(metric.Description())
+		metadata.UnitRef = c.symbolTable.Symbolize(metric.Unit())
+	}
+	return metadata
+}
+
+// createTimeSeries creates the TimeSeries that corresponds to lbls, replacing any existing one.
+func (c *prometheusConverterV2) createTimeSeries(lbls []prompb.Label, metadata writev2.Metadata) *writev2.TimeSeries {
+	ts := &writev2.TimeSeries{
+		LabelsRefs: c.symbolizeLabels(lbls),
+		Metadata:   metadata,
+	}
+	c.unique[timeSeriesSignature(lbls)] = ts
+	return ts
+}
+
+// addSample creates the TimeSeries that corresponds to lbls with the given sample and returns it.
+// If either lbls is nil/empty or sample is nil, nothing is done.
+func (c *prometheusConverterV2) addSample(sample *writev2.Sample, lbls []prompb.Label, metadata writev2.Metadata) *writev2.TimeSeries {
 	if sample == nil || len(lbls) == 0 {
 		// This shouldn't happen
 		return nil
 	}
 
+	ts := c.createTimeSeries(lbls, metadata)
+	ts.Samples = []writev2.Sample{*sample}
+	return ts
+}
+
+func (c *prometheusConverterV2) symbolizeLabels(lbls []prompb.Label) []uint32 {
 	buf := make([]uint32, 0, len(lbls)*2)
-	var off uint32
 	for _, l := range lbls {
-		off = c.symbolTable.Symbolize(l.Name)
-		buf = append(buf, off)
-		off = c.symbolTable.Symbolize(l.Value)
-		buf = append(buf, off)
-	}
-	ts := writev2.TimeSeries{
-		LabelsRefs: buf,
-		Samples:    []writev2.Sample{*sample},
+		buf = append(buf, c.symbolTable.Symbolize(l.Name), c.symbolTable.Symbolize(l.Value))
 	}
-	c.unique[timeSeriesSignature(lbls)] = &ts
+	return buf
+}
 
-	return &ts
+// exemplars converts promExemplars to the Prometheus remote write 2.0 format.
+func (c *prometheusConverterV2) exemplars(promExemplars []prompb.Exemplar) []writev2.Exemplar {
+	if len(promExemplars) == 0 {
+		return nil
+	}
+	exemplars := make([]writev2.Exemplar, 0, len(promExemplars))
+	for _, e := range promExemplars {
+		exemplars = append(exemplars, writev2.Exemplar{
+			LabelsRefs: c.symbolizeLabels(e.Labels),
+			Value:      e.Value,
+			Timestamp:  e.Timestamp,
+		})
+	}
+	return exemplars
 }
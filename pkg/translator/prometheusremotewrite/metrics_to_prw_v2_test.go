@@ -7,9 +7,13 @@ import (
 	"testing"
 	"time"
 
+	"github.com/prometheus/prometheus/model/histogram"
+	"github.com/prometheus/prometheus/model/labels"
 	writev2 "github.com/prometheus/prometheus/prompb/io/prometheus/write/v2"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
 )
 
 func TestFromMetricsV2(t *testing.T) {
@@ -24,19 +28,304 @@ func TestFromMetricsV2(t *testing.T) {
 
 	ts := uint64(time.Now().UnixNano())
 	payload := createExportRequest(5, 0, 1, 3, 0, pcommon.Timestamp(ts))
-	want := func() map[string]*writev2.TimeSeries {
-		return map[string]*writev2.TimeSeries{
-			"0": {
-				LabelsRefs: []uint32{1, 2, 3, 4, 5, 6, 7, 8},
-				Samples: []writev2.Sample{
-					{Timestamp: convertTimeStamp(pcommon.Timestamp(ts)), Value: 1.23},
-				},
+	want := map[string]*writev2.TimeSeries{
+		`{__name__="sum_1", series_name_1="value-1", series_name_2="value-2", series_name_3="value-3"}`: {
+			Metadata: writev2.Metadata{Type: writev2.Metadata_METRIC_TYPE_GAUGE},
+			Samples: []writev2.Sample{
+				{Timestamp: convertTimeStamp(pcommon.Timestamp(ts)), Value: 1.23},
+			},
+		},
+		`{__name__="gauge_1", series_name_1="value-1", series_name_2="value-2", series_name_3="value-3"}`: {
+			Metadata: writev2.Metadata{Type: writev2.Metadata_METRIC_TYPE_GAUGE},
+			Samples: []writev2.Sample{
+				{Timestamp: convertTimeStamp(pcommon.Timestamp(ts)), Value: 1.23},
 			},
-		}
+		},
 	}
-	wantedSymbols := []string{"", "series_name_2", "value-2", "series_name_3", "value-3", "__name__", "gauge_1", "series_name_1", "value-1"}
+	wantedSymbols := []string{"", "series_name_2", "value-2", "series_name_3", "value-3", "__name__", "sum_1", "gauge_1", "series_name_1", "value-1"}
 	tsMap, symbolsTable, err := FromMetricsV2(payload.Metrics(), settings)
 	require.NoError(t, err)
-	require.Equal(t, want(), tsMap)
+	assert.Equal(t, want, seriesByLabels(tsMap, symbolsTable.Symbols()))
 	require.ElementsMatch(t, wantedSymbols, symbolsTable.Symbols())
 }
+
+func TestFromMetricsV2MetricTypes(t *testing.T) {
+	start := pcommon.Timestamp(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC).UnixNano())
+	ts := start + pcommon.Timestamp(time.Minute)
+	startMs := convertTimeStamp(start)
+	tsMs := convertTimeStamp(ts)
+
+	tests := []struct {
+		name     string
+		metric   func(pmetric.Metric)
+		settings Settings
+		want     map[string]*writev2.TimeSeries
+	}{
+		{
+			name: "counter",
+			metric: func(m pmetric.Metric) {
+				m.SetName("requests")
+				m.SetDescription("number of requests")
+				m.SetUnit("1")
+				sum := m.SetEmptySum()
+				sum.SetIsMonotonic(true)
+				sum.SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+				dp := sum.DataPoints().AppendEmpty()
+				dp.SetStartTimestamp(start)
+				dp.SetTimestamp(ts)
+				dp.SetIntValue(10)
+				e := dp.Exemplars().AppendEmpty()
+				e.SetTimestamp(ts)
+				e.SetDoubleValue(1)
+			},
+			settings: Settings{SendMetadata: true},
+			want: map[string]*writev2.TimeSeries{
+				`{__name__="requests"}`: {
+					Metadata:         writev2.Metadata{Type: writev2.Metadata_METRIC_TYPE_COUNTER, HelpRef: 1, UnitRef: 2},
+					Samples:          []writev2.Sample{{Value: 10, Timestamp: tsMs}},
+					Exemplars:        []writev2.Exemplar{{LabelsRefs: []uint32{}, Value: 1, Timestamp: tsMs}},
+					CreatedTimestamp: startMs,
+				},
+			},
+		},
+		{
+			name: "classic histogram",
+			metric: func(m pmetric.Metric) {
+				m.SetName("latency")
+				h := m.SetEmptyHistogram()
+				h.SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+				dp := h.DataPoints().AppendEmpty()
+				dp.SetStartTimestamp(start)
+				dp.SetTimestamp(ts)
+				dp.SetCount(3)
+				dp.SetSum(7)
+				dp.ExplicitBounds().FromRaw([]float64{1, 5})
+				dp.BucketCounts().FromRaw([]uint64{1, 1, 1})
+				e := dp.Exemplars().AppendEmpty()
+				e.SetTimestamp(ts)
+				e.SetDoubleValue(3)
+			},
+			want: map[string]*writev2.TimeSeries{
+				`{__name__="latency_sum"}`: {
+					Metadata:         writev2.Metadata{Type: writev2.Metadata_METRIC_TYPE_HISTOGRAM},
+					Samples:          []writev2.Sample{{Value: 7, Timestamp: tsMs}},
+					CreatedTimestamp: startMs,
+				},
+				`{__name__="latency_count"}`: {
+					Metadata:         writev2.Metadata{Type: writev2.Metadata_METRIC_TYPE_HISTOGRAM},
+					Samples:          []writev2.Sample{{Value: 3, Timestamp: tsMs}},
+					CreatedTimestamp: startMs,
+				},
+				`{__name__="latency_bucket", le="1"}`: {
+					Metadata:         writev2.Metadata{Type: writev2.Metadata_METRIC_TYPE_HISTOGRAM},
+					Samples:          []writev2.Sample{{Value: 1, Timestamp: tsMs}},
+					CreatedTimestamp: startMs,
+				},
+				`{__name__="latency_bucket", le="5"}`: {
+					Metadata:         writev2.Metadata{Type: writev2.Metadata_METRIC_TYPE_HISTOGRAM},
+					Samples:          []writev2.Sample{{Value: 2, Timestamp: tsMs}},
+					Exemplars:        []writev2.Exemplar{{LabelsRefs: []uint32{}, Value: 3, Timestamp: tsMs}},
+					CreatedTimestamp: startMs,
+				},
+				`{__name__="latency_bucket", le="+Inf"}`: {
+					Metadata:         writev2.Metadata{Type: writev2.Metadata_METRIC_TYPE_HISTOGRAM},
+					Samples:          []writev2.Sample{{Value: 3, Timestamp: tsMs}},
+					CreatedTimestamp: startMs,
+				},
+			},
+		},
+		{
+			name: "custom buckets histogram",
+			metric: func(m pmetric.Metric) {
+				m.SetName("latency")
+				h := m.SetEmptyHistogram()
+				h.SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+				dp := h.DataPoints().AppendEmpty()
+				dp.SetStartTimestamp(start)
+				dp.SetTimestamp(ts)
+				dp.SetCount(5)
+				dp.SetSum(20)
+				dp.ExplicitBounds().FromRaw([]float64{1, 5, 10})
+				dp.BucketCounts().FromRaw([]uint64{0, 2, 0, 3})
+			},
+			settings: Settings{ConvertHistogramsToNHCB: true},
+			want: map[string]*writev2.TimeSeries{
+				`{__name__="latency"}`: {
+					Metadata: writev2.Metadata{Type: writev2.Metadata_METRIC_TYPE_HISTOGRAM},
+					Histograms: []writev2.Histogram{writev2.FromIntHistogram(tsMs, &histogram.Histogram{
+						Schema:          histogram.CustomBucketsSchema,
+						Count:           5,
+						Sum:             20,
+						PositiveSpans:   []histogram.Span{{Offset: 1, Length: 1}, {Offset: 1, Length: 1}},
+						PositiveBuckets: []int64{2, 1},
+						CustomValues:    []float64{1, 5, 10},
+					})},
+					CreatedTimestamp: startMs,
+				},
+			},
+		},
+		{
+			name: "exponential histogram",
+			metric: func(m pmetric.Metric) {
+				m.SetName("latency")
+				h := m.SetEmptyExponentialHistogram()
+				h.SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+				dp := h.DataPoints().AppendEmpty()
+				dp.SetStartTimestamp(start)
+				dp.SetTimestamp(ts)
+				dp.SetScale(0)
+				dp.SetCount(4)
+				dp.SetSum(10)
+				dp.SetZeroCount(1)
+				dp.Positive().SetOffset(0)
+				dp.Positive().BucketCounts().FromRaw([]uint64{1, 2})
+			},
+			want: map[string]*writev2.TimeSeries{
+				`{__name__="latency"}`: {
+					Metadata: writev2.Metadata{Type: writev2.Metadata_METRIC_TYPE_HISTOGRAM},
+					Histograms: []writev2.Histogram{writev2.FromIntHistogram(tsMs, &histogram.Histogram{
+						Schema:          0,
+						Count:           4,
+						Sum:             10,
+						ZeroCount:       1,
+						ZeroThreshold:   defaultZeroThreshold,
+						PositiveSpans:   []histogram.Span{{Offset: 1, Length: 2}},
+						PositiveBuckets: []int64{1, 1},
+						NegativeSpans:   []histogram.Span{},
+					})},
+					CreatedTimestamp: startMs,
+				},
+			},
+		},
+		{
+			name: "summary",
+			metric: func(m pmetric.Metric) {
+				m.SetName("duration")
+				dp := m.SetEmptySummary().DataPoints().AppendEmpty()
+				dp.SetTimestamp(ts)
+				dp.SetCount(2)
+				dp.SetSum(3)
+				q := dp.QuantileValues().AppendEmpty()
+				q.SetQuantile(0.5)
+				q.SetValue(1.5)
+			},
+			want: map[string]*writev2.TimeSeries{
+				`{__name__="duration_sum"}`: {
+					Metadata: writev2.Metadata{Type: writev2.Metadata_METRIC_TYPE_SUMMARY},
+					Samples:  []writev2.Sample{{Value: 3, Timestamp: tsMs}},
+				},
+				`{__name__="duration_count"}`: {
+					Metadata: writev2.Metadata{Type: writev2.Metadata_METRIC_TYPE_SUMMARY},
+					Samples:  []writev2.Sample{{Value: 2, Timestamp: tsMs}},
+				},
+				`{__name__="duration", quantile="0.5"}`: {
+					Metadata: writev2.Metadata{Type: writev2.Metadata_METRIC_TYPE_SUMMARY},
+					Samples:  []writev2.Sample{{Value: 1.5, Timestamp: tsMs}},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			md := pmetric.NewMetrics()
+			tt.metric(md.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics().AppendEmpty())
+
+			tsMap, symbolsTable, err := FromMetricsV2(md, tt.settings)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, seriesByLabels(tsMap, symbolsTable.Symbols()))
+		})
+	}
+}
+
+func TestFromMetricsV2Metadata(t *testing.T) {
+	md := pmetric.NewMetrics()
+	m := md.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics().AppendEmpty()
+	m.SetName("temperature")
+	m.SetDescription("room temperature")
+	m.SetUnit("Cel")
+	m.SetEmptyGauge().DataPoints().AppendEmpty().SetDoubleValue(20)
+
+	tsMap, symbolsTable, err := FromMetricsV2(md, Settings{SendMetadata: true})
+	require.NoError(t, err)
+	require.Len(t, tsMap, 1)
+
+	metadata := tsMap["0"].ToMetadata(symbolsTable.Symbols())
+	assert.Equal(t, "gauge", string(metadata.Type))
+	assert.Equal(t, "room temperature", metadata.Help)
+	assert.Equal(t, "Cel", metadata.Unit)
+}
+
+func TestFromMetricsV2TargetInfo(t *testing.T) {
+	ts := pcommon.Timestamp(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC).UnixNano())
+
+	md := pmetric.NewMetrics()
+	rm := md.ResourceMetrics().AppendEmpty()
+	rm.Resource().Attributes().PutStr("service.name", "checkout")
+	rm.Resource().Attributes().PutStr("host.name", "node-1")
+	m := rm.ScopeMetrics().AppendEmpty().Metrics().AppendEmpty()
+	m.SetName("temperature")
+	dp := m.SetEmptyGauge().DataPoints().AppendEmpty()
+	dp.SetTimestamp(ts)
+	dp.SetDoubleValue(20)
+
+	tsMap, symbolsTable, err := FromMetricsV2(md, Settings{})
+	require.NoError(t, err)
+	series := seriesByLabels(tsMap, symbolsTable.Symbols())
+	require.Len(t, series, 2)
+	assert.Equal(t, &writev2.TimeSeries{
+		Metadata: writev2.Metadata{Type: writev2.Metadata_METRIC_TYPE_GAUGE},
+		Samples:  []writev2.Sample{{Value: 1, Timestamp: convertTimeStamp(ts)}},
+	}, series[`{__name__="target_info", host_name="node-1", job="checkout"}`])
+
+	tsMap, _, err = FromMetricsV2(md, Settings{DisableTargetInfo: true})
+	require.NoError(t, err)
+	assert.Len(t, tsMap, 1)
+}
+
+func TestCustomBucketsLayout(t *testing.T) {
+	tests := []struct {
+		name       string
+		counts     []uint64
+		wantSpans  []histogram.Span
+		wantDeltas []int64
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name:       "no empty buckets",
+			counts:     []uint64{1, 3, 2},
+			wantSpans:  []histogram.Span{{Offset: 0, Length: 3}},
+			wantDeltas: []int64{1, 2, -1},
+		},
+		{
+			name:       "leading, inner and trailing empty buckets",
+			counts:     []uint64{0, 0, 4, 0, 0, 1, 2, 0},
+			wantSpans:  []histogram.Span{{Offset: 2, Length: 1}, {Offset: 2, Length: 2}},
+			wantDeltas: []int64{4, -3, 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			counts := pcommon.NewUInt64Slice()
+			counts.FromRaw(tt.counts)
+			spans, deltas := customBucketsLayout(counts)
+			assert.Equal(t, tt.wantSpans, spans)
+			assert.Equal(t, tt.wantDeltas, deltas)
+		})
+	}
+}
+
+// seriesByLabels indexes the time series by their labels and clears their label references, so
+// that they can be compared independently of the order of the symbols.
+func seriesByLabels(tsMap map[string]*writev2.TimeSeries, symbols []string) map[string]*writev2.TimeSeries {
+	b := labels.NewScratchBuilder(0)
+	out := make(map[string]*writev2.TimeSeries, len(tsMap))
+	for _, ts := range tsMap {
+		ls := ts.ToLabels(&b, symbols)
+		ts.LabelsRefs = nil
+		out[ls.String()] = ts
+	}
+	return out
+}
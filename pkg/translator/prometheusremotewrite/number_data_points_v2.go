@@ -14,7 +14,7 @@ import (
 )
 
 func (c *prometheusConverterV2) addGaugeNumberDataPoints(dataPoints pmetric.NumberDataPointSlice,
-	resource pcommon.Resource, settings Settings, name string,
+	resource pcommon.Resource, settings Settings, name string, metadata writev2.Metadata,
 ) {
 	for x := 0; x < dataPoints.Len(); x++ {
 		pt := dataPoints.At(x)
@@ -29,19 +29,48 @@ func (c *prometheusConverterV2) addGaugeNumberDataPoints(dataPoints pmetric.Numb
 			name,
 		)
 
-		sample := &writev2.Sample{
-			// convert ns to ms
-			Timestamp: convertTimeStamp(pt.Timestamp()),
-		}
-		switch pt.ValueType() {
-		case pmetric.NumberDataPointValueTypeInt:
-			sample.Value = float64(pt.IntValue())
-		case pmetric.NumberDataPointValueTypeDouble:
-			sample.Value = pt.DoubleValue()
+		c.addSample(numberSampleV2(pt), labels, metadata)
+	}
+}
+
+func (c *prometheusConverterV2) addSumNumberDataPoints(dataPoints pmetric.NumberDataPointSlice,
+	resource pcommon.Resource, metric pmetric.Metric, settings Settings, name string, metadata writev2.Metadata,
+) {
+	for x := 0; x < dataPoints.Len(); x++ {
+		pt := dataPoints.At(x)
+		lbls := createAttributes(
+			resource,
+			pt.Attributes(),
+			settings.ExternalLabels,
+			nil,
+			true,
+			model.MetricNameLabel,
+			name,
+		)
+		ts := c.addSample(numberSampleV2(pt), lbls, metadata)
+		if ts == nil {
+			continue
 		}
-		if pt.Flags().NoRecordedValue() {
-			sample.Value = math.Float64frombits(value.StaleNaN)
+		ts.Exemplars = c.exemplars(getPromExemplars[pmetric.NumberDataPoint](pt))
+		if metric.Sum().IsMonotonic() && pt.StartTimestamp() != 0 {
+			ts.CreatedTimestamp = convertTimeStamp(pt.StartTimestamp())
 		}
-		c.addSample(sample, labels)
 	}
 }
+
+func numberSampleV2(pt pmetric.NumberDataPoint) *writev2.Sample {
+	sample := &writev2.Sample{
+		// convert ns to ms
+		Timestamp: convertTimeStamp(pt.Timestamp()),
+	}
+	switch pt.ValueType() {
+	case pmetric.NumberDataPointValueTypeInt:
+		sample.Value = float64(pt.IntValue())
+	case pmetric.NumberDataPointValueTypeDouble:
+		sample.Value = pt.DoubleValue()
+	}
+	if pt.Flags().NoRecordedValue() {
+		sample.Value = math.Float64frombits(value.StaleNaN)
+	}
+	return sample
+}
@@ -76,6 +76,37 @@ func TestLoadConfig(t *testing.T) {
 				},
 			},
 		},
+		{
+			id: component.NewIDWithName(metadata.Type, "template"),
+			expected: &Config{
+				AddrConfig: confignet.AddrConfig{
+					Endpoint:  "localhost:2004",
+					Transport: confignet.TransportTypeTCP,
+				},
+				TCPIdleTimeout: 30 * time.Second,
+				Protocol:       "pickle",
+				Parser: &protocol.Config{
+					Type: "template",
+					Config: &protocol.TemplateParserConfig{
+						Templates: []*protocol.TemplateRule{
+							{
+								Filter:             "servers.*",
+								Template:           ".host.measurement*",
+								ResourceAttributes: []string{"host"},
+								Labels: map[string]string{
+									"key": "value",
+								},
+								MetricType: "cumulative",
+							},
+							{
+								Template: "service.measurement*",
+							},
+						},
+						MetricNameSeparator: ".",
+					},
+				},
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -106,4 +137,14 @@ func TestConfigValidate(t *testing.T) {
 		},
 	}
 	assert.Error(t, cfg.Validate())
+
+	cfg.TCPIdleTimeout = time.Second
+	cfg.Protocol = "unknown"
+	assert.Error(t, cfg.Validate())
+
+	cfg.Protocol = protocolPickle
+	assert.NoError(t, cfg.Validate())
+
+	cfg.Transport = confignet.TransportTypeUDP
+	assert.Error(t, cfg.Validate())
 }
@@ -4,8 +4,11 @@
 package client // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/carbonreceiver/internal/client"
 
 import (
+	"bytes"
+	"encoding/binary"
 	"fmt"
 	"io"
+	"math"
 	"net"
 	"strconv"
 	"strings"
@@ -144,6 +147,30 @@ func (g *Graphite) SendMetrics(metrics []Metric) error {
 	return nil
 }
 
+// SendPickledMetrics method can be used to pass a set of metrics and have it
+// be sent to the Graphite host using the pickle protocol.
+func (g *Graphite) SendPickledMetrics(metrics []Metric) error {
+	// Pickle protocol 2 of [(name, (timestamp, value)), ...]
+	var buf bytes.Buffer
+	buf.Write([]byte{0x80, 0x02, ']', '('})
+	for _, metric := range metrics {
+		buf.WriteByte('X')
+		_ = binary.Write(&buf, binary.LittleEndian, uint32(len(metric.Name)))
+		buf.WriteString(metric.Name)
+		buf.WriteByte('J')
+		_ = binary.Write(&buf, binary.LittleEndian, int32(metric.Timestamp.Unix()))
+		buf.WriteByte('G')
+		_ = binary.Write(&buf, binary.BigEndian, math.Float64bits(metric.Value))
+		// TUPLE2 twice: (timestamp, value) then (name, (timestamp, value)).
+		buf.Write([]byte{0x86, 0x86})
+	}
+	buf.Write([]byte{'e', '.'})
+
+	header := binary.BigEndian.AppendUint32(nil, uint32(buf.Len()))
+	_, err := g.Conn.Write(append(header, buf.Bytes()...))
+	return err
+}
+
 // Metric contains the metric fields expected by Graphite.
 type Metric struct {
 	Name      string
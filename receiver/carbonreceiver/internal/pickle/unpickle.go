@@ -0,0 +1,671 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package pickle implements a decoder for the subset of the Python pickle
+// format used by the Carbon pickle protocol, see
+// https://graphite.readthedocs.io/en/latest/feeding-carbon.html#the-pickle-protocol.
+//
+// Only the opcodes needed to represent None, booleans, numbers, strings,
+// lists and tuples are supported, any opcode that could instantiate an
+// arbitrary object is rejected.
+package pickle // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/carbonreceiver/internal/pickle"
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Opcodes of the pickle format, see Lib/pickletools.py in the Python sources.
+const (
+	opMark           = '('
+	opStop           = '.'
+	opPop            = '0'
+	opPopMark        = '1'
+	opDup            = '2'
+	opFloat          = 'F'
+	opInt            = 'I'
+	opBinInt         = 'J'
+	opBinInt1        = 'K'
+	opLong           = 'L'
+	opBinInt2        = 'M'
+	opNone           = 'N'
+	opString         = 'S'
+	opBinString      = 'T'
+	opShortBinString = 'U'
+	opUnicode        = 'V'
+	opBinUnicode     = 'X'
+	opAppend         = 'a'
+	opGet            = 'g'
+	opBinGet         = 'h'
+	opLongBinGet     = 'j'
+	opList           = 'l'
+	opPut            = 'p'
+	opBinPut         = 'q'
+	opLongBinPut     = 'r'
+	opTuple          = 't'
+	opEmptyList      = ']'
+	opAppends        = 'e'
+	opEmptyTuple     = ')'
+	opBinFloat       = 'G'
+	opBinBytes       = 'B'
+	opShortBinBytes  = 'C'
+
+	opProto           = 0x80
+	opTuple1          = 0x85
+	opTuple2          = 0x86
+	opTuple3          = 0x87
+	opNewTrue         = 0x88
+	opNewFalse        = 0x89
+	opLong1           = 0x8a
+	opLong4           = 0x8b
+	opShortBinUnicode = 0x8c
+	opBinUnicode8     = 0x8d
+	opBinBytes8       = 0x8e
+	opMemoize         = 0x94
+	opFrame           = 0x95
+)
+
+// maxProtocol is the highest version of the pickle protocol supported.
+const maxProtocol = 5
+
+var (
+	errStackUnderflow = errors.New("pickle: stack underflow")
+	errNoMark         = errors.New("pickle: mark not found")
+	errTruncated      = errors.New("pickle: truncated data")
+)
+
+// mark is pushed to the stack by the MARK opcode.
+type mark struct{}
+
+// list is the mutable representation of a Python list while decoding, it is
+// converted to []any once the whole pickle is decoded.
+type list struct {
+	items []any
+}
+
+type decoder struct {
+	data  []byte
+	pos   int
+	stack []any
+	memo  map[int]any
+}
+
+// Unpickle decodes the pickled data. The decoded values are returned as:
+//
+//   - nil for None
+//   - bool for booleans
+//   - int64 for integers, or *big.Int if they don't fit in an int64
+//   - float64 for floats
+//   - string for both Python 2 and Python 3 strings
+//   - []byte for bytes
+//   - []any for both lists and tuples
+//
+// The lists and tuples referenced several times in the pickle are returned as
+// the same slice.
+func Unpickle(data []byte) (any, error) {
+	d := &decoder{
+		data: data,
+		memo: make(map[int]any),
+	}
+	v, err := d.decode()
+	if err != nil {
+		return nil, err
+	}
+	return newResolver().resolve(v)
+}
+
+func (d *decoder) decode() (any, error) {
+	for {
+		op, err := d.readByte()
+		if err != nil {
+			return nil, err
+		}
+
+		switch op {
+		case opStop:
+			return d.pop()
+		case opProto:
+			var version byte
+			if version, err = d.readByte(); err != nil {
+				return nil, err
+			}
+			if version > maxProtocol {
+				return nil, fmt.Errorf("pickle: unsupported protocol %d", version)
+			}
+		case opFrame:
+			// Frames are just a hint for buffering, the data is already in memory.
+			_, err = d.readN(8)
+		case opMark:
+			d.push(mark{})
+		case opPop:
+			_, err = d.pop()
+		case opPopMark:
+			_, err = d.popMark()
+		case opDup:
+			var v any
+			if v, err = d.top(); err == nil {
+				d.push(v)
+			}
+		case opNone:
+			d.push(nil)
+		case opNewTrue:
+			d.push(true)
+		case opNewFalse:
+			d.push(false)
+		case opInt:
+			err = d.loadInt()
+		case opBinInt:
+			err = d.loadBinInt(4)
+		case opBinInt1:
+			err = d.loadBinInt(1)
+		case opBinInt2:
+			err = d.loadBinInt(2)
+		case opLong:
+			err = d.loadLong()
+		case opLong1:
+			err = d.loadLongN(1)
+		case opLong4:
+			err = d.loadLongN(4)
+		case opFloat:
+			err = d.loadFloat()
+		case opBinFloat:
+			var b []byte
+			if b, err = d.readN(8); err == nil {
+				d.push(math.Float64frombits(binary.BigEndian.Uint64(b)))
+			}
+		case opString:
+			err = d.loadString()
+		case opUnicode:
+			err = d.loadUnicode()
+		case opShortBinString, opShortBinUnicode:
+			err = d.loadBinString(1, false)
+		case opBinString, opBinUnicode:
+			err = d.loadBinString(4, false)
+		case opBinUnicode8:
+			err = d.loadBinString(8, false)
+		case opShortBinBytes:
+			err = d.loadBinString(1, true)
+		case opBinBytes:
+			err = d.loadBinString(4, true)
+		case opBinBytes8:
+			err = d.loadBinString(8, true)
+		case opEmptyList:
+			d.push(&list{})
+		case opList:
+			var items []any
+			if items, err = d.popMark(); err == nil {
+				d.push(&list{items: items})
+			}
+		case opAppend:
+			err = d.loadAppend()
+		case opAppends:
+			err = d.loadAppends()
+		case opEmptyTuple:
+			d.push([]any{})
+		case opTuple:
+			var items []any
+			if items, err = d.popMark(); err == nil {
+				d.push(items)
+			}
+		case opTuple1, opTuple2, opTuple3:
+			err = d.loadTupleN(int(op-opTuple1) + 1)
+		case opPut:
+			err = d.loadPut()
+		case opBinPut:
+			err = d.loadBinPut(1)
+		case opLongBinPut:
+			err = d.loadBinPut(4)
+		case opMemoize:
+			var v any
+			if v, err = d.top(); err == nil {
+				d.memo[len(d.memo)] = v
+			}
+		case opGet:
+			err = d.loadGet()
+		case opBinGet:
+			err = d.loadBinGet(1)
+		case opLongBinGet:
+			err = d.loadBinGet(4)
+		default:
+			return nil, fmt.Errorf("pickle: unsupported opcode 0x%02x", op)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (d *decoder) push(v any) {
+	d.stack = append(d.stack, v)
+}
+
+func (d *decoder) top() (any, error) {
+	if len(d.stack) == 0 {
+		return nil, errStackUnderflow
+	}
+	return d.stack[len(d.stack)-1], nil
+}
+
+func (d *decoder) pop() (any, error) {
+	v, err := d.top()
+	if err != nil {
+		return nil, err
+	}
+	d.stack = d.stack[:len(d.stack)-1]
+	return v, nil
+}
+
+// popMark pops all the items pushed after the last mark, and the mark itself.
+func (d *decoder) popMark() ([]any, error) {
+	for i := len(d.stack) - 1; i >= 0; i-- {
+		if _, ok := d.stack[i].(mark); ok {
+			items := make([]any, len(d.stack)-i-1)
+			copy(items, d.stack[i+1:])
+			d.stack = d.stack[:i]
+			return items, nil
+		}
+	}
+	return nil, errNoMark
+}
+
+func (d *decoder) readByte() (byte, error) {
+	if d.pos >= len(d.data) {
+		return 0, errTruncated
+	}
+	b := d.data[d.pos]
+	d.pos++
+	return b, nil
+}
+
+func (d *decoder) readN(n uint64) ([]byte, error) {
+	if n > uint64(len(d.data)-d.pos) {
+		return nil, errTruncated
+	}
+	b := d.data[d.pos : d.pos+int(n)]
+	d.pos += int(n)
+	return b, nil
+}
+
+func (d *decoder) readUint(size int) (uint64, error) {
+	b, err := d.readN(uint64(size))
+	if err != nil {
+		return 0, err
+	}
+	var v uint64
+	for i := size - 1; i >= 0; i-- {
+		v = v<<8 | uint64(b[i])
+	}
+	return v, nil
+}
+
+// readLine reads the argument of the text opcodes of the protocol 0.
+func (d *decoder) readLine() (string, error) {
+	idx := bytes.IndexByte(d.data[d.pos:], '\n')
+	if idx < 0 {
+		return "", errTruncated
+	}
+	line := string(d.data[d.pos : d.pos+idx])
+	d.pos += idx + 1
+	return line, nil
+}
+
+func (d *decoder) loadInt() error {
+	line, err := d.readLine()
+	if err != nil {
+		return err
+	}
+	switch line {
+	case "00":
+		d.push(false)
+		return nil
+	case "01":
+		d.push(true)
+		return nil
+	}
+	return d.pushInt(line)
+}
+
+func (d *decoder) loadBinInt(size int) error {
+	v, err := d.readUint(size)
+	if err != nil {
+		return err
+	}
+	if size == 4 {
+		// BININT is the only signed one.
+		d.push(int64(int32(uint32(v))))
+		return nil
+	}
+	d.push(int64(v))
+	return nil
+}
+
+func (d *decoder) loadLong() error {
+	line, err := d.readLine()
+	if err != nil {
+		return err
+	}
+	return d.pushInt(strings.TrimSuffix(line, "L"))
+}
+
+func (d *decoder) pushInt(s string) error {
+	if v, err := strconv.ParseInt(s, 10, 64); err == nil {
+		d.push(v)
+		return nil
+	}
+	v, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		return fmt.Errorf("pickle: invalid integer %q", s)
+	}
+	d.push(v)
+	return nil
+}
+
+// loadLongN decodes a little-endian two's complement integer of the given
+// length.
+func (d *decoder) loadLongN(size int) error {
+	n, err := d.readUint(size)
+	if err != nil {
+		return err
+	}
+	b, err := d.readN(n)
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		d.push(int64(0))
+		return nil
+	}
+
+	be := make([]byte, n)
+	for i := range b {
+		be[len(b)-1-i] = b[i]
+	}
+	v := new(big.Int).SetBytes(be)
+	if be[0]&0x80 != 0 {
+		v.Sub(v, new(big.Int).Lsh(big.NewInt(1), uint(8*n)))
+	}
+	if v.IsInt64() {
+		d.push(v.Int64())
+		return nil
+	}
+	d.push(v)
+	return nil
+}
+
+func (d *decoder) loadFloat() error {
+	line, err := d.readLine()
+	if err != nil {
+		return err
+	}
+	v, err := strconv.ParseFloat(line, 64)
+	if err != nil {
+		return fmt.Errorf("pickle: invalid float %q", line)
+	}
+	d.push(v)
+	return nil
+}
+
+// loadString decodes the Python repr of a string, as written by protocol 0.
+func (d *decoder) loadString() error {
+	line, err := d.readLine()
+	if err != nil {
+		return err
+	}
+	if len(line) < 2 || line[0] != line[len(line)-1] || (line[0] != '\'' && line[0] != '"') {
+		return fmt.Errorf("pickle: invalid string %q", line)
+	}
+	s, err := unescape(line[1:len(line)-1], false)
+	if err != nil {
+		return err
+	}
+	d.push(s)
+	return nil
+}
+
+// loadUnicode decodes a raw-unicode-escape string, as written by protocol 0.
+func (d *decoder) loadUnicode() error {
+	line, err := d.readLine()
+	if err != nil {
+		return err
+	}
+	s, err := unescape(line, true)
+	if err != nil {
+		return err
+	}
+	d.push(s)
+	return nil
+}
+
+func (d *decoder) loadBinString(lenSize int, isBytes bool) error {
+	n, err := d.readUint(lenSize)
+	if err != nil {
+		return err
+	}
+	b, err := d.readN(n)
+	if err != nil {
+		return err
+	}
+	if isBytes {
+		d.push(bytes.Clone(b))
+		return nil
+	}
+	d.push(string(b))
+	return nil
+}
+
+func (d *decoder) loadAppend() error {
+	v, err := d.pop()
+	if err != nil {
+		return err
+	}
+	return d.appendToList([]any{v})
+}
+
+func (d *decoder) loadAppends() error {
+	items, err := d.popMark()
+	if err != nil {
+		return err
+	}
+	return d.appendToList(items)
+}
+
+func (d *decoder) appendToList(items []any) error {
+	v, err := d.top()
+	if err != nil {
+		return err
+	}
+	l, ok := v.(*list)
+	if !ok {
+		return fmt.Errorf("pickle: cannot append to %T", v)
+	}
+	l.items = append(l.items, items...)
+	return nil
+}
+
+func (d *decoder) loadTupleN(n int) error {
+	if len(d.stack) < n {
+		return errStackUnderflow
+	}
+	items := make([]any, n)
+	copy(items, d.stack[len(d.stack)-n:])
+	d.stack = d.stack[:len(d.stack)-n]
+	d.push(items)
+	return nil
+}
+
+func (d *decoder) loadPut() error {
+	line, err := d.readLine()
+	if err != nil {
+		return err
+	}
+	idx, err := strconv.Atoi(line)
+	if err != nil {
+		return fmt.Errorf("pickle: invalid memo index %q", line)
+	}
+	return d.put(idx)
+}
+
+func (d *decoder) loadBinPut(size int) error {
+	idx, err := d.readUint(size)
+	if err != nil {
+		return err
+	}
+	return d.put(int(idx))
+}
+
+func (d *decoder) put(idx int) error {
+	v, err := d.top()
+	if err != nil {
+		return err
+	}
+	d.memo[idx] = v
+	return nil
+}
+
+func (d *decoder) loadGet() error {
+	line, err := d.readLine()
+	if err != nil {
+		return err
+	}
+	idx, err := strconv.Atoi(line)
+	if err != nil {
+		return fmt.Errorf("pickle: invalid memo index %q", line)
+	}
+	return d.get(idx)
+}
+
+func (d *decoder) loadBinGet(size int) error {
+	idx, err := d.readUint(size)
+	if err != nil {
+		return err
+	}
+	return d.get(int(idx))
+}
+
+func (d *decoder) get(idx int) error {
+	v, ok := d.memo[idx]
+	if !ok {
+		return fmt.Errorf("pickle: memo index %d not found", idx)
+	}
+	d.push(v)
+	return nil
+}
+
+// resolver replaces the lists used while decoding by plain slices.
+//
+// The lists and tuples referenced several times through the memo are resolved
+// once and the result is shared, otherwise a small pickle referencing the same
+// value at every nesting level would take an exponential time to decode.
+type resolver struct {
+	visiting map[*list]struct{}
+	// resolved is keyed by the *list of the lists, and the address of the
+	// first item of the tuples.
+	resolved map[any][]any
+}
+
+func newResolver() *resolver {
+	return &resolver{
+		visiting: make(map[*list]struct{}),
+		resolved: make(map[any][]any),
+	}
+}
+
+func (r *resolver) resolve(v any) (any, error) {
+	var key any
+	var items []any
+	switch t := v.(type) {
+	case *list:
+		if _, ok := r.visiting[t]; ok {
+			return nil, errors.New("pickle: recursive list")
+		}
+		key, items = t, t.items
+	case []any:
+		if len(t) == 0 {
+			return t, nil
+		}
+		key, items = &t[0], t
+	case mark:
+		return nil, errors.New("pickle: unexpected mark")
+	default:
+		return v, nil
+	}
+	if resolved, ok := r.resolved[key]; ok {
+		return resolved, nil
+	}
+
+	if l, ok := v.(*list); ok {
+		r.visiting[l] = struct{}{}
+		defer delete(r.visiting, l)
+	}
+	resolved := make([]any, len(items))
+	for i, item := range items {
+		var err error
+		if resolved[i], err = r.resolve(item); err != nil {
+			return nil, err
+		}
+	}
+	r.resolved[key] = resolved
+	return resolved, nil
+}
+
+// unescape decodes the escape sequences of the Python string representations,
+// the raw-unicode-escape encoding only escapes the code points using \u and \U.
+func unescape(s string, raw bool) (string, error) {
+	if !raw && !strings.Contains(s, `\`) {
+		return s, nil
+	}
+
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if raw && (c != '\\' || i+1 == len(s) || (s[i+1] != 'u' && s[i+1] != 'U')) {
+			// The raw-unicode-escape encoding writes the code points below
+			// 256 as latin-1 bytes.
+			sb.WriteRune(rune(c))
+			continue
+		}
+		if c != '\\' || i+1 == len(s) {
+			sb.WriteByte(c)
+			continue
+		}
+
+		next := s[i+1]
+		i++
+		switch next {
+		case 'n':
+			sb.WriteByte('\n')
+		case 'r':
+			sb.WriteByte('\r')
+		case 't':
+			sb.WriteByte('\t')
+		case '\\', '\'', '"':
+			sb.WriteByte(next)
+		case 'x', 'u', 'U':
+			size := map[byte]int{'x': 2, 'u': 4, 'U': 8}[next]
+			if i+size >= len(s) {
+				return "", fmt.Errorf("pickle: invalid escape sequence in %q", s)
+			}
+			code, err := strconv.ParseUint(s[i+1:i+1+size], 16, 32)
+			if err != nil {
+				return "", fmt.Errorf("pickle: invalid escape sequence in %q", s)
+			}
+			if next == 'x' {
+				sb.WriteByte(byte(code))
+			} else {
+				sb.WriteRune(rune(code))
+			}
+			i += size
+		default:
+			sb.WriteByte(c)
+			sb.WriteByte(next)
+		}
+	}
+	return sb.String(), nil
+}
@@ -0,0 +1,134 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package pickle
+
+import (
+	"encoding/hex"
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnpickle(t *testing.T) {
+	bigInt, _ := new(big.Int).SetString("1180591620717411303424", 10)
+
+	// Generated with pickle.dumps(data, protocol=N) where data is
+	// [("servers.web01.cpu.user", (1700000000, 1.5)),
+	//  ("servers.web01.requests;dc=east", (1700000000.25, 42)),
+	//  ("big", (1700000000, 2**70)),
+	//  ("neg", (1700000000, -3))]
+	carbonData := []any{
+		[]any{"servers.web01.cpu.user", []any{int64(1700000000), 1.5}},
+		[]any{"servers.web01.requests;dc=east", []any{1700000000.25, int64(42)}},
+		[]any{"big", []any{int64(1700000000), bigInt}},
+		[]any{"neg", []any{int64(1700000000), int64(-3)}},
+	}
+
+	tests := []struct {
+		name    string
+		data    string
+		want    any
+		wantErr string
+	}{
+		{
+			name: "protocol_0",
+			data: "286c70300a2856736572766572732e77656230312e6370752e757365720a70310a2849313730303030303030300a46312e350a7470320a7470330a612856736572766572732e77656230312e72657175657374733b64633d656173740a70340a2846313730303030303030302e32350a4934320a7470350a7470360a6128566269670a70370a2849313730303030303030300a4c313138303539313632303731373431313330333432344c0a7470380a7470390a6128566e65670a7031300a2849313730303030303030300a492d330a747031310a747031320a612e",
+			want: carbonData,
+		},
+		{
+			name: "protocol_2",
+			data: "80025d7100285816000000736572766572732e77656230312e6370752e7573657271014a00f15365473ff8000000000000867102867103581e000000736572766572732e77656230312e72657175657374733b64633d6561737471044741d954fc401000004b2a867105867106580300000062696771074a00f153658a0900000000000000004086710886710958030000006e6567710a4a00f153654afdffffff86710b86710c652e",
+			want: carbonData,
+		},
+		{
+			name: "protocol_4",
+			data: "8004958e000000000000005d94288c16736572766572732e77656230312e6370752e75736572944a00f15365473ff8000000000000869486948c1e736572766572732e77656230312e72657175657374733b64633d65617374944741d954fc401000004b2a869486948c03626967944a00f153658a09000000000000000040869486948c036e6567944a00f153654afdffffff86948694652e",
+			want: carbonData,
+		},
+		{
+			// pickle.dumps([x, x], protocol=2) where x = [1]
+			name: "shared_reference",
+			data: "80025d7100285d71014b01616801652e",
+			want: []any{[]any{int64(1)}, []any{int64(1)}},
+		},
+		{
+			// pickle.dumps(["café 'q'\n", None, True, False, ()], protocol=0)
+			name: "protocol_0_values",
+			data: hex.EncodeToString([]byte("(lp0\nVcaf\xe9 'q'\\u000a\np1\naNaI01\naI00\na(ta.")),
+			want: []any{"café 'q'\n", nil, true, false, []any{}},
+		},
+		{
+			// Python 2 pickle.dumps(['caf\xc3\xa9\t"x"'], protocol=0)
+			name: "protocol_0_python2_string",
+			data: hex.EncodeToString([]byte("(lp0\nS'caf\\xc3\\xa9\\t\"x\"'\np1\na.")),
+			want: []any{"café\t\"x\""},
+		},
+		{
+			// pickle.dumps([b"raw"], protocol=0) uses a global to build the bytes.
+			name:    "global_not_supported",
+			data:    hex.EncodeToString([]byte("(lp0\nc_codecs\nencode\np1\n.")),
+			wantErr: "pickle: unsupported opcode 0x63",
+		},
+		{
+			name:    "truncated",
+			data:    "80025d7100285816000000736572",
+			wantErr: "pickle: truncated data",
+		},
+		{
+			name:    "stack_underflow",
+			data:    "2e",
+			wantErr: "pickle: stack underflow",
+		},
+		{
+			name:    "append_no_list",
+			data:    "80024b014b02612e",
+			wantErr: "pickle: cannot append to int64",
+		},
+		{
+			name:    "recursive_list",
+			data:    "80025d710068006168002e",
+			wantErr: "pickle: recursive list",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := hex.DecodeString(tt.data)
+			require.NoError(t, err)
+
+			got, err := Unpickle(data)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestUnpickleSharedReferences(t *testing.T) {
+	// Every level is a list holding twice the list of the previous level,
+	// fetched from the memo: x0 = []; x1 = [x0, x0]; ...; x50 = [x49, x49].
+	const levels = 50
+	data := []byte{0x80, 0x02, opEmptyList, opBinPut, 0}
+	for i := 1; i <= levels; i++ {
+		data = append(data, opEmptyList, opBinPut, byte(i), opMark, opBinGet, byte(i-1), opBinGet, byte(i-1), opAppends)
+	}
+	data = append(data, opStop)
+
+	got, err := Unpickle(data)
+	require.NoError(t, err)
+
+	for i := levels; i > 0; i-- {
+		items, ok := got.([]any)
+		require.True(t, ok)
+		require.Len(t, items, 2)
+		assert.Equal(t, reflect.ValueOf(items[0]).Pointer(), reflect.ValueOf(items[1]).Pointer(), "the shared list must be resolved once")
+		got = items[0]
+	}
+	assert.Equal(t, []any{}, got)
+}
@@ -0,0 +1,187 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package transport // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/carbonreceiver/internal/transport"
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net"
+	"strconv"
+	"time"
+
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/carbonreceiver/internal/pickle"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/carbonreceiver/protocol"
+)
+
+// maxPickleFrameSize is the largest pickle accepted, the same limit used by
+// the Graphite carbon daemons.
+const maxPickleFrameSize = 1 << 20
+
+// NewPickleTCPServer creates a transport.Server using TCP as its transport
+// and the Carbon pickle protocol, see
+// https://graphite.readthedocs.io/en/latest/feeding-carbon.html#the-pickle-protocol.
+func NewPickleTCPServer(
+	addr string,
+	idleTimeout time.Duration,
+) (Server, error) {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+
+	t := tcpServer{
+		ln:          ln,
+		idleTimeout: idleTimeout,
+		pickle:      true,
+	}
+	return &t, nil
+}
+
+// handlePickleConnection reads the frames sent by the client, each frame is
+// a 4 bytes big-endian length followed by a pickled list of
+// (path, (timestamp, value)) tuples.
+func (t *tcpServer) handlePickleConnection(
+	p protocol.Parser,
+	nextConsumer consumer.Metrics,
+	conn net.Conn,
+) {
+	defer conn.Close()
+	header := make([]byte, 4)
+	for {
+		if err := conn.SetDeadline(time.Now().Add(t.idleTimeout)); err != nil {
+			t.reporter.OnDebugf(
+				"TCP Transport (%s) - conn.SetDeadLine error: %v",
+				t.ln.Addr(),
+				err)
+			return
+		}
+
+		if _, err := io.ReadFull(conn, header); err != nil {
+			// Timeouts, used to purge the idle connections, and disconnections
+			// end up here.
+			t.reporter.OnDebugf("TCP Transport (%s) - error: %v", t.ln.Addr(), err)
+			return
+		}
+
+		size := binary.BigEndian.Uint32(header)
+		ctx := t.reporter.OnDataReceived(context.Background())
+		if size > maxPickleFrameSize {
+			err := fmt.Errorf("pickle of %d bytes exceeds the maximum of %d bytes", size, maxPickleFrameSize)
+			t.reporter.OnTranslationError(ctx, err)
+			t.reporter.OnMetricsProcessed(ctx, 0, err)
+			// The frames can't be resynchronized after skipping one.
+			return
+		}
+
+		data := make([]byte, size)
+		if _, err := io.ReadFull(conn, data); err != nil {
+			t.reporter.OnMetricsProcessed(ctx, 0, err)
+			return
+		}
+
+		metrics := pmetric.NewMetrics()
+		numReceivedMetricPoints, err := t.parsePickle(ctx, p, data, metrics)
+		if err != nil {
+			t.reporter.OnTranslationError(ctx, err)
+		}
+		if metrics.MetricCount() == 0 {
+			t.reporter.OnMetricsProcessed(ctx, numReceivedMetricPoints, nil)
+			continue
+		}
+
+		err = nextConsumer.ConsumeMetrics(ctx, metrics)
+		t.reporter.OnMetricsProcessed(ctx, numReceivedMetricPoints, err)
+		if err != nil {
+			// Same as the plaintext protocol, close the connection to report
+			// the error back to the client.
+			return
+		}
+	}
+}
+
+// parsePickle decodes the pickled metrics and converts them to plaintext
+// lines so the configured parser handles the metric paths. The errors of the
+// individual metrics are reported as translation errors.
+func (t *tcpServer) parsePickle(
+	ctx context.Context,
+	p protocol.Parser,
+	data []byte,
+	metrics pmetric.Metrics,
+) (int, error) {
+	v, err := pickle.Unpickle(data)
+	if err != nil {
+		return 0, err
+	}
+	entries, ok := v.([]any)
+	if !ok {
+		return 0, fmt.Errorf("pickle must be a list of metrics, got %T", v)
+	}
+
+	for _, entry := range entries {
+		line, err := pickleEntryToLine(entry)
+		if err == nil {
+			err = appendMetric(p, line, metrics)
+		}
+		if err != nil {
+			t.reporter.OnTranslationError(ctx, err)
+		}
+	}
+	return len(entries), nil
+}
+
+var errInvalidPickleEntry = errors.New("pickled metric must be a (path, (timestamp, value)) tuple")
+
+// pickleEntryToLine formats an unpickled (path, (timestamp, value)) tuple as
+// the line "path value timestamp" of the plaintext protocol.
+func pickleEntryToLine(entry any) (string, error) {
+	tuple, ok := entry.([]any)
+	if !ok || len(tuple) != 2 {
+		return "", errInvalidPickleEntry
+	}
+	datapoint, ok := tuple[1].([]any)
+	if !ok || len(datapoint) != 2 {
+		return "", errInvalidPickleEntry
+	}
+
+	var path string
+	switch v := tuple[0].(type) {
+	case string:
+		path = v
+	case []byte:
+		path = string(v)
+	default:
+		return "", errInvalidPickleEntry
+	}
+	timestamp, err := formatPickleNumber(datapoint[0])
+	if err != nil {
+		return "", err
+	}
+	value, err := formatPickleNumber(datapoint[1])
+	if err != nil {
+		return "", err
+	}
+	return path + " " + value + " " + timestamp, nil
+}
+
+func formatPickleNumber(v any) (string, error) {
+	switch n := v.(type) {
+	case int64:
+		return strconv.FormatInt(n, 10), nil
+	case float64:
+		return strconv.FormatFloat(n, 'f', -1, 64), nil
+	case *big.Int:
+		return n.String(), nil
+	case string:
+		// Some clients send the numbers as strings.
+		return n, nil
+	}
+	return "", fmt.Errorf("pickled metric has a non numeric timestamp or value: %v", v)
+}
@@ -0,0 +1,70 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package transport
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_pickleEntryToLine(t *testing.T) {
+	bigInt, _ := new(big.Int).SetString("1180591620717411303424", 10)
+
+	tests := []struct {
+		name    string
+		entry   any
+		want    string
+		wantErr bool
+	}{
+		{
+			name:  "int_value",
+			entry: []any{"servers.web01.cpu", []any{int64(1582230020), int64(42)}},
+			want:  "servers.web01.cpu 42 1582230020",
+		},
+		{
+			name:  "float_timestamp_and_value",
+			entry: []any{[]byte("servers.web01.cpu"), []any{1582230020.5, 0.25}},
+			want:  "servers.web01.cpu 0.25 1582230020.5",
+		},
+		{
+			name:  "big_value",
+			entry: []any{"servers.web01.bytes", []any{int64(1582230020), bigInt}},
+			want:  "servers.web01.bytes 1180591620717411303424 1582230020",
+		},
+		{
+			name:    "not_a_tuple",
+			entry:   "servers.web01.cpu",
+			wantErr: true,
+		},
+		{
+			name:    "missing_timestamp",
+			entry:   []any{"servers.web01.cpu", []any{int64(42)}},
+			wantErr: true,
+		},
+		{
+			name:    "invalid_path",
+			entry:   []any{int64(1), []any{int64(1582230020), int64(42)}},
+			wantErr: true,
+		},
+		{
+			name:    "invalid_value",
+			entry:   []any{"servers.web01.cpu", []any{int64(1582230020), nil}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := pickleEntryToLine(tt.entry)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -6,8 +6,11 @@ package transport // import "github.com/open-telemetry/opentelemetry-collector-c
 import (
 	"context"
 	"errors"
+	"reflect"
 
 	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/carbonreceiver/protocol"
 )
@@ -62,3 +65,36 @@ type Reporter interface {
 		template string,
 		args ...any)
 }
+
+// appendMetric parses a Carbon line and appends the resulting metric to the
+// given metrics. The metrics with the same resource attributes, if the parser
+// extracts any, share the same resource.
+func appendMetric(p protocol.Parser, line string, metrics pmetric.Metrics) error {
+	var metric pmetric.Metric
+	resourceAttributes := pcommon.NewMap()
+	var err error
+	if rp, ok := p.(protocol.ResourceParser); ok {
+		metric, resourceAttributes, err = rp.ParseResource(line)
+	} else {
+		metric, err = p.Parse(line)
+	}
+	if err != nil {
+		return err
+	}
+
+	metric.MoveTo(scopeMetricsFor(metrics, resourceAttributes).Metrics().AppendEmpty())
+	return nil
+}
+
+func scopeMetricsFor(metrics pmetric.Metrics, resourceAttributes pcommon.Map) pmetric.ScopeMetrics {
+	rms := metrics.ResourceMetrics()
+	for i := 0; i < rms.Len(); i++ {
+		attrs := rms.At(i).Resource().Attributes()
+		if attrs.Len() == resourceAttributes.Len() && reflect.DeepEqual(attrs.AsRaw(), resourceAttributes.AsRaw()) {
+			return rms.At(i).ScopeMetrics().At(0)
+		}
+	}
+	rm := rms.AppendEmpty()
+	resourceAttributes.MoveTo(rm.Resource().Attributes())
+	return rm.ScopeMetrics().AppendEmpty()
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/pdata/pmetric"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/common/testutil"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/carbonreceiver/internal/client"
@@ -24,6 +25,7 @@ func Test_Server_ListenAndServe(t *testing.T) {
 		name          string
 		buildServerFn func(addr string) (Server, error)
 		buildClientFn func(addr string) (*client.Graphite, error)
+		pickle        bool
 	}{
 		{
 			name: "tcp",
@@ -34,6 +36,16 @@ func Test_Server_ListenAndServe(t *testing.T) {
 				return client.NewGraphite(client.TCP, addr)
 			},
 		},
+		{
+			name: "pickle",
+			buildServerFn: func(addr string) (Server, error) {
+				return NewPickleTCPServer(addr, 1*time.Second)
+			},
+			buildClientFn: func(addr string) (*client.Graphite, error) {
+				return client.NewGraphite(client.TCP, addr)
+			},
+			pickle: true,
+		},
 		{
 			name:          "udp",
 			buildServerFn: NewUDPServer,
@@ -44,7 +56,11 @@ func Test_Server_ListenAndServe(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			addr := testutil.GetAvailableLocalNetworkAddress(t, tt.name)
+			network := tt.name
+			if tt.pickle {
+				network = "tcp"
+			}
+			addr := testutil.GetAvailableLocalNetworkAddress(t, network)
 
 			svr, err := tt.buildServerFn(addr)
 			require.NoError(t, err)
@@ -70,9 +86,14 @@ func Test_Server_ListenAndServe(t *testing.T) {
 			require.NotNil(t, gc)
 
 			ts := time.Date(2020, 2, 20, 20, 20, 20, 20, time.UTC)
-			err = gc.SendMetric(client.Metric{
+			metric := client.Metric{
 				Name: "test.metric", Value: 1, Timestamp: ts,
-			})
+			}
+			if tt.pickle {
+				err = gc.SendPickledMetrics([]client.Metric{metric})
+			} else {
+				err = gc.SendMetric(metric)
+			}
 			assert.NoError(t, err)
 			runtime.Gosched()
 
@@ -100,6 +121,28 @@ func Test_Server_ListenAndServe(t *testing.T) {
 	}
 }
 
+func Test_appendMetric_resourceAttributes(t *testing.T) {
+	p, err := (&protocol.TemplateParserConfig{
+		Templates: []*protocol.TemplateRule{
+			{Template: "host.measurement*", ResourceAttributes: []string{"host"}},
+		},
+	}).BuildParser()
+	require.NoError(t, err)
+
+	metrics := pmetric.NewMetrics()
+	require.NoError(t, appendMetric(p, "web01.cpu 1 1582230020", metrics))
+	require.NoError(t, appendMetric(p, "web02.cpu 2 1582230020", metrics))
+	require.NoError(t, appendMetric(p, "web01.mem 3 1582230020", metrics))
+	assert.Error(t, appendMetric(p, "web01.mem", metrics))
+
+	rms := metrics.ResourceMetrics()
+	require.Equal(t, 2, rms.Len())
+	assert.Equal(t, map[string]any{"host": "web01"}, rms.At(0).Resource().Attributes().AsRaw())
+	assert.Equal(t, 2, rms.At(0).ScopeMetrics().At(0).Metrics().Len())
+	assert.Equal(t, map[string]any{"host": "web02"}, rms.At(1).Resource().Attributes().AsRaw())
+	assert.Equal(t, 1, rms.At(1).ScopeMetrics().At(0).Metrics().Len())
+}
+
 // mockReporter provides a Reporter that provides some useful functionalities for
 // tests (eg.: wait for certain number of messages).
 type mockReporter struct {
@@ -24,6 +24,9 @@ type tcpServer struct {
 	wg          sync.WaitGroup
 	idleTimeout time.Duration
 	reporter    Reporter
+	// pickle is set if the connections use the pickle protocol instead of
+	// the plaintext one.
+	pickle bool
 }
 
 var _ Server = (*tcpServer)(nil)
@@ -67,7 +70,11 @@ func (t *tcpServer) ListenAndServe(
 			connMapMtx.Unlock()
 			t.wg.Add(1)
 			go func(c net.Conn) {
-				t.handleConnection(parser, nextConsumer, c)
+				if t.pickle {
+					t.handlePickleConnection(parser, nextConsumer, c)
+				} else {
+					t.handleConnection(parser, nextConsumer, c)
+				}
 				connMapMtx.Lock()
 				delete(acceptedConnMap, c)
 				connMapMtx.Unlock()
@@ -150,15 +157,11 @@ func (t *tcpServer) handleConnection(
 				reporterActive = true
 			}
 			numReceivedMetricPoints++
-			var metric pmetric.Metric
-			metric, err = p.Parse(line)
-			if err != nil {
+			metrics := pmetric.NewMetrics()
+			if err = appendMetric(p, line, metrics); err != nil {
 				t.reporter.OnTranslationError(ctx, err)
 				continue
 			}
-			metrics := pmetric.NewMetrics()
-			newMetric := metrics.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics().AppendEmpty()
-			metric.MoveTo(newMetric)
 			err = nextConsumer.ConsumeMetrics(ctx, metrics)
 			t.reporter.OnMetricsProcessed(ctx, numReceivedMetricPoints, err)
 			reporterActive = false
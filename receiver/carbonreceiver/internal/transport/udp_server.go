@@ -92,7 +92,6 @@ func (u *udpServer) handlePacket(
 	ctx := u.reporter.OnDataReceived(context.Background())
 	var numReceivedMetricPoints int
 	metrics := pmetric.NewMetrics()
-	sm := metrics.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty()
 
 	buf := bytes.NewBuffer(data)
 	for {
@@ -106,13 +105,10 @@ func (u *udpServer) handlePacket(
 		line := strings.TrimSpace(string(bytes))
 		if line != "" {
 			numReceivedMetricPoints++
-			metric, err := p.Parse(line)
-			if err != nil {
+			if err := appendMetric(p, line, metrics); err != nil {
 				u.reporter.OnTranslationError(ctx, err)
 				continue
 			}
-			newMetric := sm.Metrics().AppendEmpty()
-			metric.MoveTo(newMetric)
 		}
 	}
 
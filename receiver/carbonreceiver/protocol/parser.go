@@ -4,6 +4,7 @@
 package protocol // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/carbonreceiver/protocol"
 
 import (
+	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/pmetric"
 )
 
@@ -25,3 +26,13 @@ type Parser interface {
 	// made.
 	Parse(line string) (pmetric.Metric, error)
 }
+
+// ResourceParser is a Parser that can also extract the attributes of the
+// resource that emitted the metric, e.g. the "template" parser.
+type ResourceParser interface {
+	Parser
+
+	// ParseResource is like Parse but also returns the resource attributes
+	// extracted from the line, the returned map is empty if there are none.
+	ParseResource(line string) (pmetric.Metric, pcommon.Map, error)
+}
@@ -40,6 +40,9 @@ type ParsedPath struct {
 	MetricName string
 	// Attributes extracted/generated by the parser.
 	Attributes pcommon.Map
+	// ResourceAttributes extracted/generated by the parser, the zero value
+	// means that the parser does not extract any.
+	ResourceAttributes pcommon.Map
 	// MetricType instructs the helper to generate the metric as the specified
 	// TargetMetricType.
 	MetricType TargetMetricType
@@ -60,7 +63,7 @@ type PathParserHelper struct {
 	pathParser PathParser
 }
 
-var _ Parser = (*PathParserHelper)(nil)
+var _ ResourceParser = (*PathParserHelper)(nil)
 
 // NewParser creates a new Parser instance that receives plaintext
 // Carbon data.
@@ -90,9 +93,28 @@ func NewParser(pathParser PathParser) (Parser, error) {
 // The <metric_timestamp> is the Unix time text of when the measurement was
 // made.
 func (pph *PathParserHelper) Parse(line string) (pmetric.Metric, error) {
+	m, _, err := pph.ParseResource(line)
+	return m, err
+}
+
+// ParseResource is like Parse but also returns the resource attributes
+// extracted from the <metric_path>.
+func (pph *PathParserHelper) ParseResource(line string) (pmetric.Metric, pcommon.Map, error) {
+	m, parsedPath, err := pph.parse(line)
+	if err != nil {
+		return pmetric.Metric{}, pcommon.Map{}, err
+	}
+	resourceAttributes := pcommon.NewMap()
+	if parsedPath.ResourceAttributes != (pcommon.Map{}) {
+		parsedPath.ResourceAttributes.CopyTo(resourceAttributes)
+	}
+	return m, resourceAttributes, nil
+}
+
+func (pph *PathParserHelper) parse(line string) (pmetric.Metric, ParsedPath, error) {
 	parts := strings.SplitN(line, " ", 4)
 	if len(parts) != 3 {
-		return pmetric.Metric{}, fmt.Errorf("invalid carbon metric [%s]", line)
+		return pmetric.Metric{}, ParsedPath{}, fmt.Errorf("invalid carbon metric [%s]", line)
 	}
 
 	path := parts[0]
@@ -102,7 +124,7 @@ func (pph *PathParserHelper) Parse(line string) (pmetric.Metric, error) {
 	parsedPath := ParsedPath{}
 	err := pph.pathParser.ParsePath(path, &parsedPath)
 	if err != nil {
-		return pmetric.Metric{}, fmt.Errorf("invalid carbon metric [%s]: %w", line, err)
+		return pmetric.Metric{}, ParsedPath{}, fmt.Errorf("invalid carbon metric [%s]: %w", line, err)
 	}
 
 	var unixTimeNs int64
@@ -111,7 +133,7 @@ func (pph *PathParserHelper) Parse(line string) (pmetric.Metric, error) {
 	if errIsFloat != nil {
 		dblVal, err = strconv.ParseFloat(timestampStr, 64)
 		if err != nil {
-			return pmetric.Metric{}, fmt.Errorf("invalid carbon metric time [%s]: %w", line, err)
+			return pmetric.Metric{}, ParsedPath{}, fmt.Errorf("invalid carbon metric time [%s]: %w", line, err)
 		}
 		sec, frac := math.Modf(dblVal)
 		unixTime = int64(sec)
@@ -122,7 +144,7 @@ func (pph *PathParserHelper) Parse(line string) (pmetric.Metric, error) {
 	if errIsFloat != nil {
 		dblVal, err = strconv.ParseFloat(valueStr, 64)
 		if err != nil {
-			return pmetric.Metric{}, fmt.Errorf("invalid carbon metric value [%s]: %w", line, err)
+			return pmetric.Metric{}, ParsedPath{}, fmt.Errorf("invalid carbon metric value [%s]: %w", line, err)
 		}
 	}
 
@@ -143,5 +165,5 @@ func (pph *PathParserHelper) Parse(line string) (pmetric.Metric, error) {
 		dp.SetIntValue(intVal)
 	}
 	parsedPath.Attributes.CopyTo(dp.Attributes())
-	return m, nil
+	return m, parsedPath, nil
 }
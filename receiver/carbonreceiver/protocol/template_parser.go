@@ -0,0 +1,269 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package protocol // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/carbonreceiver/protocol"
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+)
+
+const (
+	templateMeasurement    = "measurement"
+	templateGreedySuffix   = "*"
+	defaultTemplateNameSep = "."
+)
+
+// TemplateParserConfig has the configuration for a parser that breaks down a
+// Carbon "metric path" according to Graphite-style dotted templates, mapping
+// each node of the path to either the metric name or an attribute.
+//
+// Tags of Graphite 1.1 tagged series (";tag=value") are kept as attributes and
+// are not considered when matching the templates.
+//
+// Examples:
+//
+// 1. Template:
+//   - template: "host.service.measurement*"
+//     resource_attributes: [host]
+//     Metric path: "web01.nginx.requests.count;dc=east"
+//     Resulting metric:
+//     name: requests.count
+//     resource attribute keys: {"host"}
+//     resource attribute values: {"web01"}
+//     attribute keys: {"service", "dc"}
+//     attribute values: {"nginx", "east"}
+//
+// 2. Template:
+//   - filter: "servers.*.cpu.*"
+//     template: ".host.measurement.cpu"
+//     Metric path: "servers.db01.cpu.3"
+//     Resulting metric:
+//     name: cpu
+//     attribute keys: {"host", "cpu"}
+//     attribute values: {"db01", "3"}
+type TemplateParserConfig struct {
+	// Templates to be applied to the received metrics. The first template with a
+	// filter matching the metric path is applied. If no template matches, the
+	// metric is processed by the "plaintext" parser.
+	Templates []*TemplateRule `mapstructure:"templates"`
+
+	// MetricNameSeparator is used when joining the nodes of the metric path
+	// that form the metric name, the default is ".".
+	MetricNameSeparator string `mapstructure:"name_separator"`
+}
+
+// TemplateRule describes how the nodes of a metric path are mapped to the
+// metric name and attributes.
+type TemplateRule struct {
+	// Filter restricts the template to the metric paths matching it. It is a
+	// dotted pattern where each node is matched by a glob, e.g. "servers.*.cpu".
+	// A filter with fewer nodes than the path matches its prefix. The template
+	// applies to all the metrics if the filter is empty.
+	Filter string `mapstructure:"filter"`
+
+	// Template is a dotted pattern naming each node of the metric path:
+	// "measurement" nodes form the metric name, empty nodes are dropped and any
+	// other name makes the node the value of the attribute with that key. The
+	// last node can end with "*" to consume all the remaining nodes of the path.
+	// The remaining nodes are dropped otherwise.
+	Template string `mapstructure:"template"`
+
+	// ResourceAttributes lists the template nodes that are added as resource
+	// attributes instead of datapoint attributes.
+	ResourceAttributes []string `mapstructure:"resource_attributes"`
+
+	// Labels are key-value pairs added as attributes to the metrics matching
+	// this template.
+	Labels map[string]string `mapstructure:"labels"`
+
+	// MetricType selects the type of metric to be generated, supported values are
+	// "gauge" (the default) and "cumulative".
+	MetricType string `mapstructure:"type"`
+
+	// Some fields cached after the compilation of the template.
+	filterNodes   []string
+	templateNodes []string
+	greedy        bool
+}
+
+var _ (ParserConfig) = (*TemplateParserConfig)(nil)
+
+// BuildParser builds the respective parser of the configuration instance.
+func (tpc *TemplateParserConfig) BuildParser() (Parser, error) {
+	if tpc == nil {
+		return nil, errors.New("nil receiver on TemplateParserConfig.BuildParser")
+	}
+
+	if err := compileTemplateRules(tpc.Templates); err != nil {
+		return nil, err
+	}
+
+	tpp := &templatePathParser{
+		rules:               tpc.Templates,
+		metricNameSeparator: tpc.MetricNameSeparator,
+	}
+
+	return NewParser(tpp)
+}
+
+func compileTemplateRules(rules []*TemplateRule) error {
+	if len(rules) == 0 {
+		return errors.New(`no template was specified`)
+	}
+
+	for i, r := range rules {
+		if r.Template == "" {
+			return fmt.Errorf("empty template on %d-th rule", i)
+		}
+
+		switch TargetMetricType(r.MetricType) {
+		case DefaultMetricType, GaugeMetricType, CumulativeMetricType:
+		default:
+			return fmt.Errorf(
+				`error on %d-th rule: unknown metric type %q valid choices are: %q or %q`,
+				i,
+				r.MetricType,
+				GaugeMetricType,
+				CumulativeMetricType)
+		}
+
+		if r.Filter != "" {
+			r.filterNodes = strings.Split(r.Filter, ".")
+			for _, node := range r.filterNodes {
+				if _, err := path.Match(node, ""); err != nil {
+					return fmt.Errorf("error compiling filter of %d-th rule: %w", i, err)
+				}
+			}
+		}
+
+		r.templateNodes = strings.Split(r.Template, ".")
+		for j, node := range r.templateNodes {
+			if !strings.HasSuffix(node, templateGreedySuffix) {
+				continue
+			}
+			if j != len(r.templateNodes)-1 {
+				return fmt.Errorf("only the last node of the %d-th template can end with %q", i, templateGreedySuffix)
+			}
+			r.templateNodes[j] = strings.TrimSuffix(node, templateGreedySuffix)
+			r.greedy = true
+		}
+
+		for _, key := range r.ResourceAttributes {
+			if key == templateMeasurement || key == "" || !slices.Contains(r.templateNodes, key) {
+				return fmt.Errorf("resource attribute %q of the %d-th rule is not an attribute of its template", key, i)
+			}
+		}
+	}
+
+	return nil
+}
+
+type templatePathParser struct {
+	rules []*TemplateRule
+
+	metricNameSeparator string
+
+	// plaintextParser is used if no template matches a given metric, and to
+	// extract the tags of the metric.
+	plaintextPathParser PlaintextPathParser
+}
+
+// ParsePath converts the <metric_path> of a Carbon line (see PathParserHelper
+// a full description of the line format) according to the TemplateParserConfig
+// settings.
+func (tpp *templatePathParser) ParsePath(path string, parsedPath *ParsedPath) error {
+	if err := tpp.plaintextPathParser.ParsePath(path, parsedPath); err != nil {
+		return err
+	}
+
+	nodes := strings.Split(parsedPath.MetricName, ".")
+	for _, rule := range tpp.rules {
+		if !rule.matches(nodes) {
+			continue
+		}
+
+		var nameParts []string
+		resourceAttributes := pcommon.NewMap()
+		values := map[string][]string{}
+		var keys []string
+		for i, node := range nodes {
+			key := ""
+			switch {
+			case i < len(rule.templateNodes):
+				key = rule.templateNodes[i]
+			case rule.greedy:
+				key = rule.templateNodes[len(rule.templateNodes)-1]
+			}
+
+			switch key {
+			case "":
+				// Node dropped.
+			case templateMeasurement:
+				nameParts = append(nameParts, node)
+			default:
+				if _, ok := values[key]; !ok {
+					keys = append(keys, key)
+				}
+				values[key] = append(values[key], node)
+			}
+		}
+
+		attributes := pcommon.NewMap()
+		for _, key := range keys {
+			value := strings.Join(values[key], ".")
+			if slices.Contains(rule.ResourceAttributes, key) {
+				resourceAttributes.PutStr(key, value)
+			} else {
+				attributes.PutStr(key, value)
+			}
+		}
+		for k, v := range rule.Labels {
+			attributes.PutStr(k, v)
+		}
+		// Tags of the series take precedence over the template.
+		parsedPath.Attributes.Range(func(k string, v pcommon.Value) bool {
+			v.CopyTo(attributes.PutEmpty(k))
+			return true
+		})
+
+		if len(nameParts) != 0 {
+			separator := tpp.metricNameSeparator
+			if separator == "" {
+				separator = defaultTemplateNameSep
+			}
+			parsedPath.MetricName = strings.Join(nameParts, separator)
+		}
+		parsedPath.Attributes = attributes
+		parsedPath.ResourceAttributes = resourceAttributes
+		parsedPath.MetricType = TargetMetricType(rule.MetricType)
+		return nil
+	}
+
+	return nil
+}
+
+// matches reports whether the nodes of the metric path match the filter of
+// the rule.
+func (r *TemplateRule) matches(nodes []string) bool {
+	if len(r.filterNodes) > len(nodes) {
+		return false
+	}
+	for i, pattern := range r.filterNodes {
+		if ok, _ := path.Match(pattern, nodes[i]); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func templateDefaultConfig() ParserConfig {
+	return &TemplateParserConfig{
+		MetricNameSeparator: defaultTemplateNameSep,
+	}
+}
@@ -0,0 +1,259 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package protocol
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+)
+
+func TestTemplateParserConfigBuildParser(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  ParserConfig
+		wantErr bool
+	}{
+		{
+			name:    "nil_method_receiver",
+			config:  (*TemplateParserConfig)(nil),
+			wantErr: true,
+		},
+		{
+			name:    "no_templates",
+			config:  &TemplateParserConfig{},
+			wantErr: true,
+		},
+		{
+			name: "empty_template",
+			config: &TemplateParserConfig{
+				Templates: []*TemplateRule{
+					{Filter: "servers.*"},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid_filter",
+			config: &TemplateParserConfig{
+				Templates: []*TemplateRule{
+					{Filter: "servers.[", Template: "measurement*"},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "greedy_node_not_last",
+			config: &TemplateParserConfig{
+				Templates: []*TemplateRule{
+					{Template: "measurement*.host"},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "unknown_resource_attribute",
+			config: &TemplateParserConfig{
+				Templates: []*TemplateRule{
+					{Template: "host.measurement*", ResourceAttributes: []string{"service"}},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "measurement_resource_attribute",
+			config: &TemplateParserConfig{
+				Templates: []*TemplateRule{
+					{Template: "host.measurement*", ResourceAttributes: []string{"measurement"}},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid_metric_type",
+			config: &TemplateParserConfig{
+				Templates: []*TemplateRule{
+					{Template: "host.measurement*", MetricType: "unknown"},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "valid_templates",
+			config: &TemplateParserConfig{
+				Templates: []*TemplateRule{
+					{Filter: "servers.*", Template: ".host.measurement*", ResourceAttributes: []string{"host"}},
+					{Template: "service.measurement*"},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.config.BuildParser()
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Nil(t, got)
+				return
+			}
+
+			assert.NoError(t, err)
+			require.NotNil(t, got)
+		})
+	}
+}
+
+func Test_templateParser_parsePath(t *testing.T) {
+	config := TemplateParserConfig{
+		Templates: []*TemplateRule{
+			{
+				Filter:   "servers.*.cpu",
+				Template: ".host.measurement.cpu",
+			},
+			{
+				Filter:     "stats.*.*.requests",
+				Template:   ".region.host.measurement*",
+				Labels:     map[string]string{"k": "v"},
+				MetricType: string(CumulativeMetricType),
+			},
+			{
+				Filter:             "app*",
+				Template:           "service.host.measurement*",
+				ResourceAttributes: []string{"service", "host"},
+				MetricType:         string(GaugeMetricType),
+			},
+			{
+				Filter:   "dc.*",
+				Template: "region.region.host",
+			},
+		},
+		MetricNameSeparator: "_",
+	}
+
+	require.NoError(t, compileTemplateRules(config.Templates))
+	tp := &templatePathParser{
+		rules:               config.Templates,
+		metricNameSeparator: config.MetricNameSeparator,
+	}
+
+	tests := []struct {
+		name                   string
+		path                   string
+		wantName               string
+		wantAttributes         pcommon.Map
+		wantResourceAttributes pcommon.Map
+		wantMetricType         TargetMetricType
+		wantErr                bool
+	}{
+		{
+			name:           "no_template_match",
+			path:           "other.host01.cpu;k=v",
+			wantName:       "other.host01.cpu",
+			wantAttributes: newAttributes("k", "v"),
+		},
+		{
+			name:                   "match_template0",
+			path:                   "servers.host00.cpu.3",
+			wantName:               "cpu",
+			wantAttributes:         newAttributes("host", "host00", "cpu", "3"),
+			wantResourceAttributes: pcommon.NewMap(),
+		},
+		{
+			name:                   "match_template1_greedy",
+			path:                   "stats.east.host01.requests.http.total",
+			wantName:               "requests_http_total",
+			wantAttributes:         newAttributes("region", "east", "host", "host01", "k", "v"),
+			wantResourceAttributes: pcommon.NewMap(),
+			wantMetricType:         CumulativeMetricType,
+		},
+		{
+			name:                   "match_template2_tags",
+			path:                   "app01.web02.requests;service=override;dc=east",
+			wantName:               "requests",
+			wantAttributes:         newAttributes("service", "override", "dc", "east"),
+			wantResourceAttributes: newAttributes("service", "app01", "host", "web02"),
+			wantMetricType:         GaugeMetricType,
+		},
+		{
+			name:                   "match_template3_no_measurement",
+			path:                   "dc.west.host03.dropped",
+			wantName:               "dc.west.host03.dropped",
+			wantAttributes:         newAttributes("region", "dc.west", "host", "host03"),
+			wantResourceAttributes: pcommon.NewMap(),
+		},
+		{
+			name:    "invalid_tags",
+			path:    "app01.web02.requests;service",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ParsedPath{}
+			err := tp.ParsePath(tt.path, &got)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.Equal(t, tt.wantName, got.MetricName)
+			assert.Equal(t, tt.wantAttributes, got.Attributes)
+			assert.Equal(t, tt.wantResourceAttributes, got.ResourceAttributes)
+			assert.Equal(t, tt.wantMetricType, got.MetricType)
+		})
+	}
+}
+
+func TestTemplateParser_ParseResource(t *testing.T) {
+	config := TemplateParserConfig{
+		Templates: []*TemplateRule{
+			{
+				Template:           "host.service.measurement*",
+				ResourceAttributes: []string{"host"},
+			},
+		},
+	}
+	p, err := config.BuildParser()
+	require.NoError(t, err)
+	rp, ok := p.(ResourceParser)
+	require.True(t, ok)
+
+	got, resourceAttributes, err := rp.ParseResource("web01.nginx.requests.count;dc=east 42 1582230020")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"host": "web01"}, resourceAttributes.AsRaw())
+
+	want := pmetric.NewMetric()
+	want.SetName("requests.count")
+	dp := want.SetEmptyGauge().DataPoints().AppendEmpty()
+	dp.SetTimestamp(pcommon.Timestamp(1582230020 * 1e9))
+	dp.SetIntValue(42)
+	dp.Attributes().PutStr("service", "nginx")
+	dp.Attributes().PutStr("dc", "east")
+	assert.Equal(t, want, got)
+
+	// Without any measurement node the whole path is the metric name.
+	got, resourceAttributes, err = rp.ParseResource("web01 42 1582230020")
+	require.NoError(t, err)
+	assert.Equal(t, "web01", got.Name())
+	assert.Equal(t, map[string]any{"host": "web01"}, resourceAttributes.AsRaw())
+
+	p, err = (&PlaintextConfig{}).BuildParser()
+	require.NoError(t, err)
+	_, resourceAttributes, err = p.(ResourceParser).ParseResource("web01 42 1582230020")
+	require.NoError(t, err)
+	assert.Equal(t, 0, resourceAttributes.Len())
+}
+
+// newAttributes creates a map with the given key-value pairs, in order.
+func newAttributes(kvs ...string) pcommon.Map {
+	m := pcommon.NewMap()
+	for i := 0; i+1 < len(kvs); i += 2 {
+		m.PutStr(kvs[i], kvs[i+1])
+	}
+	return m
+}
@@ -171,6 +171,21 @@ func Test_carbonreceiver_EndToEnd(t *testing.T) {
 				return c.SputterThenSendMetric
 			},
 		},
+		{
+			name: "pickle",
+			configFn: func() *Config {
+				cfg := createDefaultConfig().(*Config)
+				cfg.Protocol = protocolPickle
+				return cfg
+			},
+			clientFn: func(t *testing.T) func(client.Metric) error {
+				c, err := client.NewGraphite(client.TCP, addr)
+				require.NoError(t, err)
+				return func(m client.Metric) error {
+					return c.SendPickledMetrics([]client.Metric{m})
+				}
+			},
+		},
 		{
 			name: "default_config_udp",
 			configFn: func() *Config {
@@ -0,0 +1,212 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package influxdbreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/influxdbreceiver"
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/influxdata/line-protocol/v2/lineprotocol"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/common/sanitize"
+)
+
+// apiVersion identifies the InfluxDB write API being served, they differ on
+// their query parameters and on the format of their error responses.
+type apiVersion int
+
+const (
+	apiV1 apiVersion = iota + 1 // POST /write
+	apiV2                       // POST /api/v2/write
+	apiV3                       // POST /api/v3/write_lp
+)
+
+// Error codes of the InfluxDB 2.x API, see
+// https://docs.influxdata.com/influxdb/v2/api/#tag/Response-codes
+const (
+	errCodeInvalid       = "invalid"
+	errCodeUnauthorized  = "unauthorized"
+	errCodeInternalError = "internal error"
+)
+
+var precisions = map[string]lineprotocol.Precision{
+	"ns": lineprotocol.Nanosecond,
+	"n":  lineprotocol.Nanosecond,
+	"µs": lineprotocol.Microsecond,
+	"µ":  lineprotocol.Microsecond,
+	"us": lineprotocol.Microsecond,
+	"u":  lineprotocol.Microsecond,
+	"ms": lineprotocol.Millisecond,
+	"s":  lineprotocol.Second,
+}
+
+// v3Precisions are the precisions of the InfluxDB 3.x API, besides "auto".
+var v3Precisions = map[string]lineprotocol.Precision{
+	"nanosecond":  lineprotocol.Nanosecond,
+	"microsecond": lineprotocol.Microsecond,
+	"millisecond": lineprotocol.Millisecond,
+	"second":      lineprotocol.Second,
+}
+
+// writeParams are the parameters of a write request common to all the APIs.
+type writeParams struct {
+	bucket string
+	org    string
+
+	precision lineprotocol.Precision
+	// autoPrecision guesses the precision of each timestamp from its magnitude.
+	autoPrecision bool
+	// acceptPartial writes the valid lines of a request with invalid ones.
+	acceptPartial bool
+}
+
+// parseWriteParams extracts the write parameters from the query of the
+// request, according to the API version. acceptPartial is the default of the
+// InfluxDB 1.x and 2.x APIs, which have no parameter for it.
+func parseWriteParams(api apiVersion, req *http.Request, acceptPartial bool) (writeParams, error) {
+	query := req.URL.Query()
+	params := writeParams{
+		precision:     defaultPrecision,
+		acceptPartial: acceptPartial || api == apiV3,
+	}
+
+	switch api {
+	case apiV1:
+		// InfluxDB 2.x maps the database and retention policy to the bucket
+		// "db/rp", see https://docs.influxdata.com/influxdb/v2/reference/api/influxdb-1x/dbrp/
+		params.bucket = query.Get("db")
+		if rp := query.Get("rp"); rp != "" && params.bucket != "" {
+			params.bucket += "/" + rp
+		}
+	case apiV2:
+		params.bucket = query.Get("bucket")
+		if params.bucket == "" {
+			params.bucket = query.Get("bucketID")
+		}
+		params.org = query.Get("org")
+		if params.org == "" {
+			params.org = query.Get("orgID")
+		}
+	case apiV3:
+		params.bucket = query.Get("db")
+		if acceptPartial := query.Get("accept_partial"); acceptPartial != "" {
+			var err error
+			if params.acceptPartial, err = strconv.ParseBool(acceptPartial); err != nil {
+				return params, fmt.Errorf("invalid accept_partial '%s'", sanitize.String(acceptPartial))
+			}
+		}
+	}
+
+	precisionStr := query.Get("precision")
+	if precisionStr == "" {
+		if api == apiV3 {
+			params.autoPrecision = true
+		}
+		return params, nil
+	}
+
+	var ok bool
+	if api == apiV3 {
+		if precisionStr == "auto" {
+			params.autoPrecision = true
+			return params, nil
+		}
+		if params.precision, ok = v3Precisions[precisionStr]; ok {
+			return params, nil
+		}
+	}
+	if params.precision, ok = precisions[precisionStr]; !ok {
+		return params, fmt.Errorf("unrecognized precision '%s'", sanitize.String(precisionStr))
+	}
+	return params, nil
+}
+
+// maxReportedLineErrors limits the number of line errors in the responses.
+const maxReportedLineErrors = 100
+
+// lineError is the error found while decoding a line of the request.
+type lineError struct {
+	line int
+	err  error
+}
+
+// writeError writes the error response in the format expected by the clients
+// of the API version.
+func writeError(w http.ResponseWriter, api apiVersion, statusCode int, code, message string, lineErrs []lineError) {
+	var body any
+	switch api {
+	case apiV2:
+		body = struct {
+			Code    string `json:"code"`
+			Message string `json:"message"`
+		}{Code: code, Message: message}
+	case apiV3:
+		type lineErrorData struct {
+			LineNumber   int    `json:"line_number"`
+			ErrorMessage string `json:"error_message"`
+		}
+		data := make([]lineErrorData, 0, len(lineErrs))
+		for _, le := range lineErrs[:min(len(lineErrs), maxReportedLineErrors)] {
+			data = append(data, lineErrorData{LineNumber: le.line, ErrorMessage: le.err.Error()})
+		}
+		body = struct {
+			Error string          `json:"error"`
+			Data  []lineErrorData `json:"data,omitempty"`
+		}{Error: message, Data: data}
+	default:
+		body = struct {
+			Error string `json:"error"`
+		}{Error: message}
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(statusCode)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+// authenticate checks the token sent by the client in any of the ways
+// supported by the InfluxDB clients:
+//
+//   - the "Authorization: Token <token>" header of InfluxDB 2.x
+//   - the "Authorization: Bearer <token>" header of InfluxDB 3.x
+//   - the password of the basic authentication, or the "p" query parameter, of
+//     InfluxDB 1.x
+func authenticate(req *http.Request, token string) bool {
+	var got string
+	auth := req.Header.Get("Authorization")
+	switch {
+	case strings.HasPrefix(auth, "Token "):
+		got = strings.TrimPrefix(auth, "Token ")
+	case strings.HasPrefix(auth, "Bearer "):
+		got = strings.TrimPrefix(auth, "Bearer ")
+	default:
+		var ok bool
+		if _, got, ok = req.BasicAuth(); !ok {
+			got = req.URL.Query().Get("p")
+		}
+	}
+	return subtle.ConstantTimeCompare([]byte(got), []byte(token)) == 1
+}
+
+// guessPrecision returns the precision of a timestamp close to the current
+// time, as done by InfluxDB 3.x for the "auto" precision.
+func guessPrecision(ts int64) lineprotocol.Precision {
+	if ts < 0 {
+		ts = -ts
+	}
+	switch {
+	case ts < 5e9:
+		return lineprotocol.Second
+	case ts < 5e12:
+		return lineprotocol.Millisecond
+	case ts < 5e15:
+		return lineprotocol.Microsecond
+	default:
+		return lineprotocol.Nanosecond
+	}
+}
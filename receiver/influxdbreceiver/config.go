@@ -5,9 +5,29 @@ package influxdbreceiver // import "github.com/open-telemetry/opentelemetry-coll
 
 import (
 	"go.opentelemetry.io/collector/config/confighttp"
+	"go.opentelemetry.io/collector/config/configopaque"
 )
 
 // Config defines configuration for the InfluxDB receiver.
 type Config struct {
 	confighttp.ServerConfig `mapstructure:",squash"`
+
+	// Token, if set, is required from the clients writing data. It is accepted
+	// as sent by the clients of any InfluxDB version: as the "Token" or
+	// "Bearer" authorization header, or as the password of InfluxDB 1.x.
+	Token configopaque.String `mapstructure:"token"`
+
+	// BucketAttribute is the resource attribute set to the bucket written to,
+	// the "db/rp" of InfluxDB 1.x or the database of InfluxDB 3.x. Empty
+	// disables the attribute.
+	BucketAttribute string `mapstructure:"bucket_attribute"`
+
+	// OrgAttribute is the resource attribute set to the organization written
+	// to, InfluxDB 2.x only. Empty disables the attribute.
+	OrgAttribute string `mapstructure:"org_attribute"`
+
+	// AcceptPartialWrites writes the valid lines of the InfluxDB 1.x and 2.x
+	// requests with invalid lines, instead of rejecting the whole request.
+	// InfluxDB 3.x clients choose with the accept_partial query parameter.
+	AcceptPartialWrites bool `mapstructure:"accept_partial_writes"`
 }
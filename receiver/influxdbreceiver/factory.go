@@ -14,7 +14,11 @@ import (
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/influxdbreceiver/internal/metadata"
 )
 
-const defaultEndpoint = "localhost:8086"
+const (
+	defaultEndpoint        = "localhost:8086"
+	defaultBucketAttribute = "influxdb.bucket"
+	defaultOrgAttribute    = "influxdb.org"
+)
 
 func NewFactory() receiver.Factory {
 	return receiver.NewFactory(
@@ -29,6 +33,8 @@ func createDefaultConfig() component.Config {
 		ServerConfig: confighttp.ServerConfig{
 			Endpoint: defaultEndpoint,
 		},
+		BucketAttribute: defaultBucketAttribute,
+		OrgAttribute:    defaultOrgAttribute,
 	}
 }
 
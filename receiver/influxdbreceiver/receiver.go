@@ -4,10 +4,15 @@
 package influxdbreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/influxdbreceiver"
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -19,10 +24,9 @@ import (
 	"go.opentelemetry.io/collector/config/confighttp"
 	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/pdata/pmetric"
 	"go.opentelemetry.io/collector/receiver"
 	"go.opentelemetry.io/collector/receiver/receiverhelper"
-
-	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/common/sanitize"
 )
 
 type metricsReceiver struct {
@@ -37,6 +41,12 @@ type metricsReceiver struct {
 
 	obsrecv *receiverhelper.ObsReport
 
+	token           string
+	bucketAttribute string
+	orgAttribute    string
+	acceptPartial   bool
+	version         string
+
 	settings component.TelemetrySettings
 }
 
@@ -61,6 +71,11 @@ func newMetricsReceiver(config *Config, settings receiver.Settings, nextConsumer
 		converter:          converter,
 		logger:             influxLogger,
 		obsrecv:            obsrecv,
+		token:              string(config.Token),
+		bucketAttribute:    config.BucketAttribute,
+		orgAttribute:       config.OrgAttribute,
+		acceptPartial:      config.AcceptPartialWrites,
+		version:            settings.BuildInfo.Version,
 		settings:           settings.TelemetrySettings,
 	}, err
 }
@@ -72,9 +87,11 @@ func (r *metricsReceiver) Start(ctx context.Context, host component.Host) error
 	}
 
 	router := http.NewServeMux()
-	router.HandleFunc("/write", r.handleWrite)        // InfluxDB 1.x
-	router.HandleFunc("/api/v2/write", r.handleWrite) // InfluxDB 2.x
+	router.HandleFunc("/write", r.handleWrite(apiV1))           // InfluxDB 1.x
+	router.HandleFunc("/api/v2/write", r.handleWrite(apiV2))    // InfluxDB 2.x
+	router.HandleFunc("/api/v3/write_lp", r.handleWrite(apiV3)) // InfluxDB 3.x
 	router.HandleFunc("/ping", r.handlePing)
+	router.HandleFunc("/health", r.handleHealth)
 
 	r.wg.Add(1)
 	r.server, err = r.httpServerSettings.ToServer(ctx, host, r.settings, router)
@@ -107,103 +124,210 @@ const (
 	dataFormat       = "influxdb"
 )
 
-var precisions = map[string]lineprotocol.Precision{
-	"ns": lineprotocol.Nanosecond,
-	"n":  lineprotocol.Nanosecond,
-	"µs": lineprotocol.Microsecond,
-	"µ":  lineprotocol.Microsecond,
-	"us": lineprotocol.Microsecond,
-	"u":  lineprotocol.Microsecond,
-	"ms": lineprotocol.Millisecond,
-	"s":  lineprotocol.Second,
-}
-
-func (r *metricsReceiver) handleWrite(w http.ResponseWriter, req *http.Request) {
-	defer func() {
-		_ = req.Body.Close()
-	}()
+func (r *metricsReceiver) handleWrite(api apiVersion) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		defer func() {
+			_ = req.Body.Close()
+		}()
 
-	precision := defaultPrecision
-	if precisionStr := req.URL.Query().Get("precision"); precisionStr != "" {
-		var ok bool
-		if precision, ok = precisions[precisionStr]; !ok {
-			w.WriteHeader(http.StatusBadRequest)
-			_, _ = fmt.Fprintf(w, "unrecognized precision '%s'", sanitize.String(precisionStr))
+		if r.token != "" && !authenticate(req, r.token) {
+			writeError(w, api, http.StatusUnauthorized, errCodeUnauthorized, "unauthorized access", nil)
 			return
 		}
-	}
-
-	batch := r.converter.NewBatch()
-	lpDecoder := lineprotocol.NewDecoder(req.Body)
 
-	ctx := r.obsrecv.StartMetricsOp(req.Context())
-
-	var k, vTag []byte
-	var vField lineprotocol.Value
-	for line := 0; lpDecoder.Next(); line++ {
-		measurement, err := lpDecoder.Measurement()
+		params, err := parseWriteParams(api, req, r.acceptPartial)
 		if err != nil {
-			w.WriteHeader(http.StatusBadRequest)
-			_, _ = fmt.Fprintf(w, "failed to parse measurement on line %d", line)
+			writeError(w, api, http.StatusBadRequest, errCodeInvalid, err.Error(), nil)
 			return
 		}
 
-		tags := make(map[string]string)
-		for k, vTag, err = lpDecoder.NextTag(); k != nil && err == nil; k, vTag, err = lpDecoder.NextTag() {
-			tags[string(k)] = string(vTag)
-		}
+		ctx := r.obsrecv.StartMetricsOp(req.Context())
+
+		batch, numLines, lineErrs, err := r.decodeBatch(req, params)
 		if err != nil {
-			w.WriteHeader(http.StatusBadRequest)
-			_, _ = fmt.Fprintf(w, "failed to parse tag on line %d", line)
+			r.obsrecv.EndMetricsOp(ctx, dataFormat, 0, err)
+			writeError(w, api, http.StatusBadRequest, errCodeInvalid, fmt.Sprintf("failed to read the request: %s", err.Error()), nil)
 			return
 		}
-
-		fields := make(map[string]any)
-		for k, vField, err = lpDecoder.NextField(); k != nil && err == nil; k, vField, err = lpDecoder.NextField() {
-			fields[string(k)] = vField.Interface()
-		}
-		if err != nil {
-			w.WriteHeader(http.StatusBadRequest)
-			_, _ = fmt.Fprintf(w, "failed to parse field on line %d", line)
+		if len(lineErrs) > 0 && (!params.acceptPartial || len(lineErrs) == numLines) {
+			r.obsrecv.EndMetricsOp(ctx, dataFormat, 0, nil)
+			writeError(w, api, http.StatusBadRequest, errCodeInvalid, lineErrorsMessage(lineErrs, numLines, false), lineErrs)
 			return
 		}
 
-		ts, err := lpDecoder.Time(precision, time.Time{})
+		metrics := batch.GetMetrics()
+		r.setResourceAttributes(metrics, params)
+		err = r.nextConsumer.ConsumeMetrics(req.Context(), metrics)
+		r.obsrecv.EndMetricsOp(ctx, dataFormat, metrics.DataPointCount(), err)
 		if err != nil {
-			w.WriteHeader(http.StatusBadRequest)
-			_, _ = fmt.Fprintf(w, "failed to parse timestamp on line %d", line)
+			r.logger.Debug("failed to pass metrics to next consumer: %s", err)
+			if consumererror.IsPermanent(err) {
+				writeError(w, api, http.StatusBadRequest, errCodeInvalid, err.Error(), nil)
+			} else {
+				writeError(w, api, http.StatusInternalServerError, errCodeInternalError, err.Error(), nil)
+			}
 			return
 		}
 
-		if err = lpDecoder.Err(); err != nil {
-			w.WriteHeader(http.StatusBadRequest)
-			_, _ = fmt.Fprintf(w, "failed to parse line: %s", err.Error())
+		if len(lineErrs) > 0 {
+			// The valid lines were written, as done by InfluxDB on partial writes.
+			writeError(w, api, http.StatusBadRequest, errCodeInvalid, lineErrorsMessage(lineErrs, numLines, true), lineErrs)
 			return
 		}
 
-		err = batch.AddPoint(string(measurement), tags, fields, ts, common.InfluxMetricValueTypeUntyped)
-		if err != nil {
-			w.WriteHeader(http.StatusBadRequest)
-			_, _ = fmt.Fprintf(w, "failed to append to the batch")
-			return
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// decodeBatch converts the line protocol of the request body, the lines that
+// fail to be decoded are returned as line errors. It also returns the number
+// of points in the body. The returned error is only set if the body can't be
+// read.
+func (r *metricsReceiver) decodeBatch(req *http.Request, params writeParams) (*influx2otel.MetricsBatch, int, []lineError, error) {
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		return nil, 0, nil, err
+	}
+	batch := r.converter.NewBatch()
+	lpDecoder := lineprotocol.NewDecoderWithBytes(body)
+
+	// The line errors report the lines of the body as InfluxDB does, counting
+	// the empty and comment lines skipped by the decoder, and the lines of the
+	// multi-line string fields.
+	lines := bytes.Split(body, []byte("\n"))
+	next := 0 // index of the line following the last decoded point
+	var lineErrs []lineError
+	numPoints := 0
+	for ; lpDecoder.Next(); numPoints++ {
+		for next < len(lines) && isEmptyLine(lines[next]) {
+			next++
+		}
+		line := next + 1
+		newlines, err := decodeLine(lpDecoder, batch, params)
+		next = line + newlines
+		if err == nil {
+			continue
 		}
+		// The decoder skips the rest of the line of a syntax error.
+		var decodeErr *lineprotocol.DecodeError
+		if errors.As(err, &decodeErr) {
+			line = int(decodeErr.Line)
+			next = line
+		}
+		lineErrs = append(lineErrs, lineError{line: line, err: err})
 	}
+	return batch, numPoints, lineErrs, nil
+}
 
-	err := r.nextConsumer.ConsumeMetrics(req.Context(), batch.GetMetrics())
-	r.obsrecv.EndMetricsOp(ctx, dataFormat, batch.GetMetrics().DataPointCount(), err)
+// isEmptyLine reports whether the decoder skips a line, as empty or comment.
+func isEmptyLine(line []byte) bool {
+	line = bytes.TrimLeft(line, " ")
+	return len(line) == 0 || line[0] == '#' || string(line) == "\r"
+}
+
+// decodeLine adds the point of the current line to the batch. It returns the
+// number of newlines in the string fields of the point.
+func decodeLine(lpDecoder *lineprotocol.Decoder, batch *influx2otel.MetricsBatch, params writeParams) (int, error) {
+	measurement, err := lpDecoder.Measurement()
 	if err != nil {
-		if consumererror.IsPermanent(err) {
-			w.WriteHeader(http.StatusBadRequest)
-		} else {
-			w.WriteHeader(http.StatusInternalServerError)
+		return 0, fmt.Errorf("failed to parse measurement: %w", err)
+	}
+	// The measurement is only valid until the next call to the decoder.
+	measurementStr := string(measurement)
+
+	var k, vTag []byte
+	tags := make(map[string]string)
+	for k, vTag, err = lpDecoder.NextTag(); k != nil && err == nil; k, vTag, err = lpDecoder.NextTag() {
+		tags[string(k)] = string(vTag)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse tag: %w", err)
+	}
+
+	var vField lineprotocol.Value
+	fields := make(map[string]any)
+	newlines := 0
+	for k, vField, err = lpDecoder.NextField(); k != nil && err == nil; k, vField, err = lpDecoder.NextField() {
+		fields[string(k)] = vField.Interface()
+		if vField.Kind() == lineprotocol.String {
+			newlines += strings.Count(vField.StringV(), "\n")
 		}
-		r.logger.Debug("failed to pass metrics to next consumer: %s", err)
-		return
+	}
+	if err != nil {
+		return newlines, fmt.Errorf("failed to parse field: %w", err)
 	}
 
-	w.WriteHeader(http.StatusNoContent)
+	ts, err := decodeTime(lpDecoder, params)
+	if err != nil {
+		return newlines, fmt.Errorf("failed to parse timestamp: %w", err)
+	}
+
+	if err = batch.AddPoint(measurementStr, tags, fields, ts, common.InfluxMetricValueTypeUntyped); err != nil {
+		return newlines, fmt.Errorf("failed to append to the batch: %w", err)
+	}
+	return newlines, nil
+}
+
+func decodeTime(lpDecoder *lineprotocol.Decoder, params writeParams) (time.Time, error) {
+	if !params.autoPrecision {
+		return lpDecoder.Time(params.precision, time.Time{})
+	}
+
+	tsBytes, err := lpDecoder.TimeBytes()
+	if err != nil || tsBytes == nil {
+		return time.Time{}, err
+	}
+	ts, err := strconv.ParseInt(string(tsBytes), 10, 64)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Unix(0, ts*int64(guessPrecision(ts).Duration())), nil
+}
+
+func lineErrorsMessage(lineErrs []lineError, numLines int, partial bool) string {
+	first := fmt.Sprintf("line %d: %s", lineErrs[0].line, lineErrs[0].err.Error())
+	if partial {
+		return fmt.Sprintf("partial write: %d of %d lines dropped, %s", len(lineErrs), numLines, first)
+	}
+	return fmt.Sprintf("failed to parse %d of %d lines, %s", len(lineErrs), numLines, first)
+}
+
+// setResourceAttributes adds the bucket and org of the request to the
+// resources of the metrics.
+func (r *metricsReceiver) setResourceAttributes(metrics pmetric.Metrics, params writeParams) {
+	rms := metrics.ResourceMetrics()
+	for i := 0; i < rms.Len(); i++ {
+		attrs := rms.At(i).Resource().Attributes()
+		if r.bucketAttribute != "" && params.bucket != "" {
+			attrs.PutStr(r.bucketAttribute, params.bucket)
+		}
+		if r.orgAttribute != "" && params.org != "" {
+			attrs.PutStr(r.orgAttribute, params.org)
+		}
+	}
+}
+
+// handleHealth serves the health check of InfluxDB 2.x, used by the clients
+// to verify the server is up.
+func (r *metricsReceiver) handleHealth(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(struct {
+		Name    string   `json:"name"`
+		Message string   `json:"message"`
+		Status  string   `json:"status"`
+		Checks  []string `json:"checks"`
+		Version string   `json:"version"`
+	}{
+		Name:    "influxdb",
+		Message: "ready for queries and writes",
+		Status:  "pass",
+		Checks:  []string{},
+		Version: r.version,
+	})
 }
 
 func (r *metricsReceiver) handlePing(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("X-Influxdb-Version", r.version)
 	w.WriteHeader(http.StatusNoContent)
 }
@@ -4,7 +4,12 @@
 package influxdbreceiver
 
 import (
+	"bytes"
+	"compress/gzip"
 	"context"
+	"io"
+	"net/http"
+	"strings"
 	"testing"
 	"time"
 
@@ -88,6 +93,217 @@ func TestWriteLineProtocol_v2API(t *testing.T) {
 	})
 }
 
+func TestWriteLineProtocol_compatibility(t *testing.T) {
+	addr := testutil.GetAvailableLocalAddress(t)
+	config := createDefaultConfig().(*Config)
+	config.Endpoint = addr
+	config.Token = "my-token"
+	nextConsumer := new(mockConsumer)
+
+	receiver, err := NewFactory().CreateMetrics(context.Background(), receivertest.NewNopSettings(), config, nextConsumer)
+	require.NoError(t, err)
+	require.NoError(t, receiver.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() { require.NoError(t, receiver.Shutdown(context.Background())) })
+
+	gzipped := func(s string) []byte {
+		var buf bytes.Buffer
+		gw := gzip.NewWriter(&buf)
+		_, err := gw.Write([]byte(s))
+		require.NoError(t, err)
+		require.NoError(t, gw.Close())
+		return buf.Bytes()
+	}
+
+	tests := []struct {
+		name            string
+		path            string
+		header          http.Header
+		body            []byte
+		wantStatus      int
+		wantBody        string
+		wantDataPoints  int
+		wantResourceRaw map[string]any
+	}{
+		{
+			name:            "v2_bucket_and_org",
+			path:            "/api/v2/write?org=my-org&bucket=my-bucket&precision=s",
+			header:          http.Header{"Authorization": {"Token my-token"}},
+			body:            []byte("cpu_temp,foo=bar gauge=87.332 1700000000"),
+			wantStatus:      http.StatusNoContent,
+			wantDataPoints:  1,
+			wantResourceRaw: map[string]any{"influxdb.bucket": "my-bucket", "influxdb.org": "my-org"},
+		},
+		{
+			name: "v2_gzip",
+			path: "/api/v2/write?org=my-org&bucket=my-bucket",
+			header: http.Header{
+				"Authorization":    {"Token my-token"},
+				"Content-Encoding": {"gzip"},
+			},
+			body:            gzipped("cpu_temp,foo=bar gauge=87.332\ncpu_temp,foo=baz gauge=12.5"),
+			wantStatus:      http.StatusNoContent,
+			wantDataPoints:  2,
+			wantResourceRaw: map[string]any{"influxdb.bucket": "my-bucket", "influxdb.org": "my-org"},
+		},
+		{
+			name:       "v2_invalid_line",
+			path:       "/api/v2/write?org=my-org&bucket=my-bucket",
+			header:     http.Header{"Authorization": {"Token my-token"}},
+			body:       []byte("cpu_temp,foo=bar gauge=87.332\ncpu_temp,foo=baz gauge=\n"),
+			wantStatus: http.StatusBadRequest,
+			wantBody:   `{"code":"invalid","message":"failed to parse 1 of 2 lines, line 2: failed to parse field: at line 2:24: field value has unrecognized type"}`,
+		},
+		{
+			name:       "v2_unauthorized",
+			path:       "/api/v2/write?org=my-org&bucket=my-bucket",
+			header:     http.Header{"Authorization": {"Token other-token"}},
+			body:       []byte("cpu_temp,foo=bar gauge=87.332"),
+			wantStatus: http.StatusUnauthorized,
+			wantBody:   `{"code":"unauthorized","message":"unauthorized access"}`,
+		},
+		{
+			name:       "v2_invalid_precision",
+			path:       "/api/v2/write?bucket=my-bucket&precision=h",
+			header:     http.Header{"Authorization": {"Token my-token"}},
+			body:       []byte("cpu_temp,foo=bar gauge=87.332"),
+			wantStatus: http.StatusBadRequest,
+			wantBody:   `{"code":"invalid","message":"unrecognized precision 'h'"}`,
+		},
+		{
+			name:            "v1_basic_auth",
+			path:            "/write?db=telegraf&rp=autogen&u=user&p=my-token",
+			body:            []byte("cpu_temp,foo=bar gauge=87.332"),
+			wantStatus:      http.StatusNoContent,
+			wantDataPoints:  1,
+			wantResourceRaw: map[string]any{"influxdb.bucket": "telegraf/autogen"},
+		},
+		{
+			name:       "v1_all_lines_invalid",
+			path:       "/write?db=telegraf&p=my-token",
+			body:       []byte("cpu_temp,foo=bar"),
+			wantStatus: http.StatusBadRequest,
+			wantBody:   `{"error":"failed to parse 1 of 1 lines, line 1: failed to parse field: at line 1:17: expected field key but none found"}`,
+		},
+		{
+			name:            "v3_auto_precision",
+			path:            "/api/v3/write_lp?db=my-db",
+			header:          http.Header{"Authorization": {"Bearer my-token"}},
+			body:            []byte("cpu_temp,foo=bar gauge=87.332 1700000000000"),
+			wantStatus:      http.StatusNoContent,
+			wantDataPoints:  1,
+			wantResourceRaw: map[string]any{"influxdb.bucket": "my-db"},
+		},
+		{
+			name:       "v3_no_partial",
+			path:       "/api/v3/write_lp?db=my-db&accept_partial=false",
+			header:     http.Header{"Authorization": {"Bearer my-token"}},
+			body:       []byte("cpu_temp,foo=bar gauge=87.332\ncpu_temp gauge=\n"),
+			wantStatus: http.StatusBadRequest,
+			wantBody:   `{"error":"failed to parse 1 of 2 lines, line 2: failed to parse field: at line 2:16: field value has unrecognized type","data":[{"line_number":2,"error_message":"failed to parse field: at line 2:16: field value has unrecognized type"}]}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nextConsumer.lastMetricsConsumed = pmetric.NewMetrics()
+
+			req, err := http.NewRequest(http.MethodPost, "http://"+addr+tt.path, bytes.NewReader(tt.body))
+			require.NoError(t, err)
+			for k, v := range tt.header {
+				req.Header[k] = v
+			}
+			resp, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			body, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+			require.NoError(t, resp.Body.Close())
+
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+			if tt.wantBody != "" {
+				assert.JSONEq(t, tt.wantBody, string(body))
+			}
+			metrics := nextConsumer.lastMetricsConsumed
+			require.Equal(t, tt.wantDataPoints, metrics.DataPointCount())
+			if tt.wantDataPoints > 0 {
+				assert.Equal(t, tt.wantResourceRaw, metrics.ResourceMetrics().At(0).Resource().Attributes().AsRaw())
+			}
+		})
+	}
+
+	t.Run("v3_auto_precision_timestamp", func(t *testing.T) {
+		nextConsumer.lastMetricsConsumed = pmetric.NewMetrics()
+		resp, err := http.Post("http://"+addr+"/api/v3/write_lp?db=my-db&p=my-token", "text/plain", strings.NewReader("cpu_temp gauge=1 1700000000000"))
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+		require.Equal(t, http.StatusNoContent, resp.StatusCode)
+		dp := nextConsumer.lastMetricsConsumed.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0).Gauge().DataPoints().At(0)
+		assert.Equal(t, time.UnixMilli(1700000000000).UTC(), dp.Timestamp().AsTime())
+	})
+
+	t.Run("health", func(t *testing.T) {
+		resp, err := http.Get("http://" + addr + "/health")
+		require.NoError(t, err)
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.JSONEq(t, `{"name":"influxdb","message":"ready for queries and writes","status":"pass","checks":[],"version":"latest"}`, string(body))
+	})
+}
+
+func TestWriteLineProtocol_partialWrites(t *testing.T) {
+	addr := testutil.GetAvailableLocalAddress(t)
+	config := createDefaultConfig().(*Config)
+	config.Endpoint = addr
+	config.AcceptPartialWrites = true
+	nextConsumer := new(mockConsumer)
+
+	receiver, err := NewFactory().CreateMetrics(context.Background(), receivertest.NewNopSettings(), config, nextConsumer)
+	require.NoError(t, err)
+	require.NoError(t, receiver.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() { require.NoError(t, receiver.Shutdown(context.Background())) })
+
+	tests := []struct {
+		name           string
+		body           string
+		wantBody       string
+		wantDataPoints int
+	}{
+		{
+			name:           "invalid_line",
+			body:           "cpu_temp,foo=bar gauge=87.332\ncpu_temp,foo=baz gauge=\n",
+			wantBody:       `{"code":"invalid","message":"partial write: 1 of 2 lines dropped, line 2: failed to parse field: at line 2:24: field value has unrecognized type"}`,
+			wantDataPoints: 1,
+		},
+		{
+			name:           "empty_and_comment_lines",
+			body:           "# cpu\n\ncpu_temp,foo=bar gauge=87.332\n  \ncpu_temp,foo=baz gauge=\n",
+			wantBody:       `{"code":"invalid","message":"partial write: 1 of 2 lines dropped, line 5: failed to parse field: at line 5:24: field value has unrecognized type"}`,
+			wantDataPoints: 1,
+		},
+		{
+			name:           "multi_line_string_field",
+			body:           "cpu_temp,foo=bar gauge=87.332,log=\"a\nb\"\n\nprometheus,le=0.5 a_bucket=1,b_bucket=2\n",
+			wantBody:       `{"code":"invalid","message":"partial write: 1 of 2 lines dropped, line 4: failed to append to the batch: histogram metric 'le' tagged line should have 1 field, found 2"}`,
+			wantDataPoints: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nextConsumer.lastMetricsConsumed = pmetric.NewMetrics()
+
+			resp, err := http.Post("http://"+addr+"/api/v2/write?bucket=my-bucket", "text/plain", strings.NewReader(tt.body))
+			require.NoError(t, err)
+			body, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+			require.NoError(t, resp.Body.Close())
+
+			assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+			assert.JSONEq(t, tt.wantBody, string(body))
+			assert.Equal(t, tt.wantDataPoints, nextConsumer.lastMetricsConsumed.DataPointCount())
+		})
+	}
+}
+
 type mockConsumer struct {
 	lastMetricsConsumed pmetric.Metrics
 }
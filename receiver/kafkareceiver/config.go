@@ -4,11 +4,12 @@
 package kafkareceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/kafkareceiver"
 
 import (
+	"errors"
+	"fmt"
 	"time"
 
 	"go.opentelemetry.io/collector/component"
 
-	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/kafkaexporter"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/kafka"
 )
 
@@ -52,10 +53,21 @@ type Config struct {
 	SessionTimeout time.Duration `mapstructure:"session_timeout"`
 	// Heartbeat interval for the Kafka consumer
 	HeartbeatInterval time.Duration `mapstructure:"heartbeat_interval"`
-	// The name of the kafka topic to consume from (default "otlp_spans" for traces, "otlp_metrics" for metrics, "otlp_logs" for logs)
+	// The name of the kafka topic to consume from (default "otlp_spans" for traces, "otlp_metrics" for metrics, "otlp_logs" for logs,
+	// unless Topics or TopicRegex are set)
 	Topic string `mapstructure:"topic"`
+	// Topics are additional kafka topics to consume from
+	Topics []string `mapstructure:"topics"`
+	// TopicRegex subscribes to the topics matching the regular expression,
+	// the topics of the cluster are listed every TopicRefreshInterval.
+	TopicRegex string `mapstructure:"topic_regex"`
+	// How frequently the topics matching TopicRegex are refreshed (default 30s)
+	TopicRefreshInterval time.Duration `mapstructure:"topic_refresh_interval"`
 	// Encoding of the messages (default "otlp_proto")
 	Encoding string `mapstructure:"encoding"`
+	// TopicEncodings overrides the encoding of the messages of some topics,
+	// keyed by topic name.
+	TopicEncodings map[string]string `mapstructure:"topic_encodings"`
 	// The consumer group that receiver will be consuming messages from (default "otel-collector")
 	GroupID string `mapstructure:"group_id"`
 	// The consumer client ID that receiver will use (default "otel-collector")
@@ -66,7 +78,7 @@ type Config struct {
 
 	// Metadata is the namespace for metadata management properties used by the
 	// Client, and shared by the Producer/Consumer.
-	Metadata kafkaexporter.Metadata `mapstructure:"metadata"`
+	Metadata kafka.Metadata `mapstructure:"metadata"`
 
 	Authentication kafka.Authentication `mapstructure:"auth"`
 
@@ -79,6 +91,10 @@ type Config struct {
 	// Extract headers from kafka records
 	HeaderExtraction HeaderExtraction `mapstructure:"header_extraction"`
 
+	// Adds the source topic, partition and offset of the kafka records to
+	// the resource attributes
+	MessageAttributes bool `mapstructure:"message_attributes"`
+
 	// The minimum bytes per fetch from Kafka (default "1")
 	MinFetchSize int32 `mapstructure:"min_fetch_size"`
 	// The default bytes per fetch from Kafka (default "1048576")
@@ -96,5 +112,23 @@ var _ component.Config = (*Config)(nil)
 
 // Validate checks the receiver configuration is valid
 func (cfg *Config) Validate() error {
+	if cfg.TopicRegex != "" {
+		if _, err := compileTopicRegex(cfg.TopicRegex); err != nil {
+			return fmt.Errorf("invalid topic_regex: %w", err)
+		}
+		if cfg.TopicRefreshInterval <= 0 {
+			return errors.New("topic_refresh_interval must be positive")
+		}
+	}
+	for _, topic := range cfg.Topics {
+		if topic == "" {
+			return errors.New("topics must not contain empty topic names")
+		}
+	}
+	for topic, encoding := range cfg.TopicEncodings {
+		if encoding == "" {
+			return fmt.Errorf("topic_encodings: empty encoding for topic %q", topic)
+		}
+	}
 	return nil
 }
@@ -14,7 +14,6 @@ import (
 	"go.opentelemetry.io/collector/config/configtls"
 	"go.opentelemetry.io/collector/confmap/confmaptest"
 
-	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/kafkaexporter"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/kafka"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/kafkareceiver/internal/metadata"
 )
@@ -34,6 +33,7 @@ func TestLoadConfig(t *testing.T) {
 			id: component.NewIDWithName(metadata.Type, ""),
 			expected: &Config{
 				Topic:                                "spans",
+				TopicRefreshInterval:                 30 * time.Second,
 				Encoding:                             "otlp_proto",
 				Brokers:                              []string{"foo:123", "bar:456"},
 				ResolveCanonicalBootstrapServersOnly: true,
@@ -51,9 +51,9 @@ func TestLoadConfig(t *testing.T) {
 						},
 					},
 				},
-				Metadata: kafkaexporter.Metadata{
+				Metadata: kafka.Metadata{
 					Full: true,
-					Retry: kafkaexporter.MetadataRetry{
+					Retry: kafka.MetadataRetry{
 						Max:     10,
 						Backoff: time.Second * 5,
 					},
@@ -70,14 +70,15 @@ func TestLoadConfig(t *testing.T) {
 		{
 			id: component.NewIDWithName(metadata.Type, "logs"),
 			expected: &Config{
-				Topic:             "logs",
-				Encoding:          "direct",
-				Brokers:           []string{"coffee:123", "foobar:456"},
-				ClientID:          "otel-collector",
-				GroupID:           "otel-collector",
-				InitialOffset:     "earliest",
-				SessionTimeout:    45 * time.Second,
-				HeartbeatInterval: 15 * time.Second,
+				Topic:                "logs",
+				TopicRefreshInterval: 30 * time.Second,
+				Encoding:             "direct",
+				Brokers:              []string{"coffee:123", "foobar:456"},
+				ClientID:             "otel-collector",
+				GroupID:              "otel-collector",
+				InitialOffset:        "earliest",
+				SessionTimeout:       45 * time.Second,
+				HeartbeatInterval:    15 * time.Second,
 				Authentication: kafka.Authentication{
 					TLS: &configtls.ClientConfig{
 						Config: configtls.Config{
@@ -87,9 +88,9 @@ func TestLoadConfig(t *testing.T) {
 						},
 					},
 				},
-				Metadata: kafkaexporter.Metadata{
+				Metadata: kafka.Metadata{
 					Full: true,
-					Retry: kafkaexporter.MetadataRetry{
+					Retry: kafka.MetadataRetry{
 						Max:     10,
 						Backoff: time.Second * 5,
 					},
@@ -103,6 +104,39 @@ func TestLoadConfig(t *testing.T) {
 				MaxFetchSize:     0,
 			},
 		},
+		{
+			id: component.NewIDWithName(metadata.Type, "topics"),
+			expected: &Config{
+				Topics:               []string{"team_a", "team_b"},
+				TopicRegex:           "^team_.*_logs$",
+				TopicRefreshInterval: time.Minute,
+				Encoding:             "otlp_proto",
+				TopicEncodings: map[string]string{
+					"team_b": "otlp_json",
+				},
+				Brokers:           []string{"localhost:9092"},
+				ClientID:          "otel-collector",
+				GroupID:           "otel-collector",
+				InitialOffset:     "latest",
+				SessionTimeout:    10 * time.Second,
+				HeartbeatInterval: 3 * time.Second,
+				Metadata: kafka.Metadata{
+					Full: true,
+					Retry: kafka.MetadataRetry{
+						Max:     3,
+						Backoff: time.Millisecond * 250,
+					},
+				},
+				AutoCommit: AutoCommit{
+					Enable:   true,
+					Interval: 1 * time.Second,
+				},
+				MessageAttributes: true,
+				MinFetchSize:      1,
+				DefaultFetchSize:  1048576,
+				MaxFetchSize:      0,
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -119,3 +153,57 @@ func TestLoadConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestConfigValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(*Config)
+		wantErr string
+	}{
+		{
+			name:   "default",
+			modify: func(*Config) {},
+		},
+		{
+			name: "invalid_topic_regex",
+			modify: func(cfg *Config) {
+				cfg.TopicRegex = "team_("
+			},
+			wantErr: "invalid topic_regex",
+		},
+		{
+			name: "no_topic_refresh_interval",
+			modify: func(cfg *Config) {
+				cfg.TopicRegex = "team_.*"
+				cfg.TopicRefreshInterval = 0
+			},
+			wantErr: "topic_refresh_interval must be positive",
+		},
+		{
+			name: "empty_topic",
+			modify: func(cfg *Config) {
+				cfg.Topics = []string{"team_a", ""}
+			},
+			wantErr: "topics must not contain empty topic names",
+		},
+		{
+			name: "empty_topic_encoding",
+			modify: func(cfg *Config) {
+				cfg.TopicEncodings = map[string]string{"team_a": ""}
+			},
+			wantErr: `topic_encodings: empty encoding for topic "team_a"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := createDefaultConfig().(*Config)
+			tt.modify(cfg)
+			err := cfg.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
@@ -13,7 +13,7 @@ import (
 	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/receiver"
 
-	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/kafkaexporter"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/kafka"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/kafkareceiver/internal/metadata"
 )
 
@@ -28,6 +28,7 @@ const (
 	defaultInitialOffset     = offsetLatest
 	defaultSessionTimeout    = 10 * time.Second
 	defaultHeartbeatInterval = 3 * time.Second
+	defaultTopicRefresh      = 30 * time.Second
 
 	// default from sarama.NewConfig()
 	defaultMetadataRetryMax = 3
@@ -71,16 +72,17 @@ func NewFactory(options ...FactoryOption) receiver.Factory {
 
 func createDefaultConfig() component.Config {
 	return &Config{
-		Encoding:          defaultEncoding,
-		Brokers:           []string{defaultBroker},
-		ClientID:          defaultClientID,
-		GroupID:           defaultGroupID,
-		InitialOffset:     defaultInitialOffset,
-		SessionTimeout:    defaultSessionTimeout,
-		HeartbeatInterval: defaultHeartbeatInterval,
-		Metadata: kafkaexporter.Metadata{
+		Encoding:             defaultEncoding,
+		TopicRefreshInterval: defaultTopicRefresh,
+		Brokers:              []string{defaultBroker},
+		ClientID:             defaultClientID,
+		GroupID:              defaultGroupID,
+		InitialOffset:        defaultInitialOffset,
+		SessionTimeout:       defaultSessionTimeout,
+		HeartbeatInterval:    defaultHeartbeatInterval,
+		Metadata: kafka.Metadata{
 			Full: defaultMetadataFull,
-			Retry: kafkaexporter.MetadataRetry{
+			Retry: kafka.MetadataRetry{
 				Max:     defaultMetadataRetryMax,
 				Backoff: defaultMetadataRetryBackoff,
 			},
@@ -111,7 +113,7 @@ func (f *kafkaReceiverFactory) createTracesReceiver(
 	nextConsumer consumer.Traces,
 ) (receiver.Traces, error) {
 	oCfg := *(cfg.(*Config))
-	if oCfg.Topic == "" {
+	if oCfg.Topic == "" && len(oCfg.Topics) == 0 && oCfg.TopicRegex == "" {
 		oCfg.Topic = defaultTracesTopic
 	}
 
@@ -129,7 +131,7 @@ func (f *kafkaReceiverFactory) createMetricsReceiver(
 	nextConsumer consumer.Metrics,
 ) (receiver.Metrics, error) {
 	oCfg := *(cfg.(*Config))
-	if oCfg.Topic == "" {
+	if oCfg.Topic == "" && len(oCfg.Topics) == 0 && oCfg.TopicRegex == "" {
 		oCfg.Topic = defaultMetricsTopic
 	}
 
@@ -147,7 +149,7 @@ func (f *kafkaReceiverFactory) createLogsReceiver(
 	nextConsumer consumer.Logs,
 ) (receiver.Logs, error) {
 	oCfg := *(cfg.(*Config))
-	if oCfg.Topic == "" {
+	if oCfg.Topic == "" && len(oCfg.Topics) == 0 && oCfg.TopicRegex == "" {
 		oCfg.Topic = defaultLogsTopic
 	}
 
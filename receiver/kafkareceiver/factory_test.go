@@ -72,6 +72,18 @@ func TestWithTracesUnmarshalers(t *testing.T) {
 	})
 }
 
+func TestCreateTraces_topics(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Topics = []string{"team_a_spans", "team_b_spans"}
+	f := kafkaReceiverFactory{}
+	r, err := f.createTracesReceiver(context.Background(), receivertest.NewNopSettings(), cfg, nil)
+	require.NoError(t, err)
+	tracesConsumer, ok := r.(*kafkaTracesConsumer)
+	require.True(t, ok)
+	// the default topic is only used without other topics
+	assert.Equal(t, []string{"team_a_spans", "team_b_spans"}, tracesConsumer.topics)
+}
+
 func TestCreateMetrics(t *testing.T) {
 	cfg := createDefaultConfig().(*Config)
 	cfg.Brokers = []string{"invalid:9092"}
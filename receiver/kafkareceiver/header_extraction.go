@@ -4,13 +4,25 @@
 package kafkareceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/kafkareceiver"
 
 import (
+	"strconv"
+
 	"github.com/IBM/sarama"
+	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/plog"
 	"go.opentelemetry.io/collector/pdata/pmetric"
 	"go.opentelemetry.io/collector/pdata/ptrace"
+	conventions "go.opentelemetry.io/collector/semconv/v1.27.0"
 	"go.uber.org/zap"
 )
 
+// putMessageAttributes adds the source topic, partition and offset of the
+// message to the attributes.
+func putMessageAttributes(attrs pcommon.Map, message *sarama.ConsumerMessage) {
+	attrs.PutStr(conventions.AttributeMessagingDestinationName, message.Topic)
+	attrs.PutStr(conventions.AttributeMessagingDestinationPartitionID, strconv.Itoa(int(message.Partition)))
+	attrs.PutInt(conventions.AttributeMessagingKafkaOffset, message.Offset)
+}
+
 func getAttribute(key string) string {
 	return "kafka.header." + key
 }
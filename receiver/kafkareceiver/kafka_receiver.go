@@ -44,6 +44,7 @@ type kafkaTracesConsumer struct {
 	cancelConsumeLoop context.CancelFunc
 	unmarshaler       TracesUnmarshaler
 	consumeLoopWG     *sync.WaitGroup
+	subscription      *topicSubscription
 
 	settings         receiver.Settings
 	telemetryBuilder *metadata.TelemetryBuilder
@@ -66,6 +67,7 @@ type kafkaMetricsConsumer struct {
 	cancelConsumeLoop context.CancelFunc
 	unmarshaler       MetricsUnmarshaler
 	consumeLoopWG     *sync.WaitGroup
+	subscription      *topicSubscription
 
 	settings         receiver.Settings
 	telemetryBuilder *metadata.TelemetryBuilder
@@ -88,6 +90,7 @@ type kafkaLogsConsumer struct {
 	cancelConsumeLoop context.CancelFunc
 	unmarshaler       LogsUnmarshaler
 	consumeLoopWG     *sync.WaitGroup
+	subscription      *topicSubscription
 
 	settings         receiver.Settings
 	telemetryBuilder *metadata.TelemetryBuilder
@@ -115,7 +118,7 @@ func newTracesReceiver(config Config, set receiver.Settings, nextConsumer consum
 
 	return &kafkaTracesConsumer{
 		config:            config,
-		topics:            []string{config.Topic},
+		topics:            staticTopics(config),
 		nextConsumer:      nextConsumer,
 		consumeLoopWG:     &sync.WaitGroup{},
 		settings:          set,
@@ -131,6 +134,14 @@ func newTracesReceiver(config Config, set receiver.Settings, nextConsumer consum
 }
 
 func createKafkaClient(ctx context.Context, config Config) (sarama.ConsumerGroup, error) {
+	saramaConfig, err := newSaramaConfig(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+	return sarama.NewConsumerGroup(config.Brokers, config.GroupID, saramaConfig)
+}
+
+func newSaramaConfig(ctx context.Context, config Config) (*sarama.Config, error) {
 	saramaConfig := sarama.NewConfig()
 	saramaConfig.ClientID = config.ClientID
 	saramaConfig.Metadata.Full = config.Metadata.Full
@@ -159,7 +170,7 @@ func createKafkaClient(ctx context.Context, config Config) (sarama.ConsumerGroup
 	if err := kafka.ConfigureAuthentication(ctx, config.Authentication, saramaConfig); err != nil {
 		return nil, err
 	}
-	return sarama.NewConsumerGroup(config.Brokers, config.GroupID, saramaConfig)
+	return saramaConfig, nil
 }
 
 func (c *kafkaTracesConsumer) Start(_ context.Context, host component.Host) error {
@@ -173,21 +184,14 @@ func (c *kafkaTracesConsumer) Start(_ context.Context, host component.Host) erro
 	if err != nil {
 		return err
 	}
-	// extensions take precedence over internal encodings
-	if unmarshaler, errExt := loadEncodingExtension[ptrace.Unmarshaler](
-		host,
-		c.config.Encoding,
-	); errExt == nil {
-		c.unmarshaler = &tracesEncodingUnmarshaler{
-			unmarshaler: *unmarshaler,
-			encoding:    c.config.Encoding,
-		}
-	}
-	if unmarshaler, ok := defaultTracesUnmarshalers()[c.config.Encoding]; c.unmarshaler == nil && ok {
-		c.unmarshaler = unmarshaler
+	if c.unmarshaler, err = newTracesUnmarshaler(host, c.config.Encoding); err != nil {
+		return err
 	}
-	if c.unmarshaler == nil {
-		return errUnrecognizedEncoding
+	topicUnmarshalers := make(map[string]TracesUnmarshaler, len(c.config.TopicEncodings))
+	for topic, encoding := range c.config.TopicEncodings {
+		if topicUnmarshalers[topic], err = newTracesUnmarshaler(host, encoding); err != nil {
+			return fmt.Errorf("encoding of topic %q: %w", topic, err)
+		}
 	}
 	// consumerGroup may be set in tests to inject fake implementation.
 	if c.consumerGroup == nil {
@@ -195,15 +199,21 @@ func (c *kafkaTracesConsumer) Start(_ context.Context, host component.Host) erro
 			return err
 		}
 	}
+	// subscription may be set in tests to inject fake topics.
+	if c.subscription, err = startTopicSubscription(ctx, c.config, c.subscription, c.settings.Logger, c.consumeLoopWG); err != nil {
+		return err
+	}
 	consumerGroup := &tracesConsumerGroupHandler{
 		logger:            c.settings.Logger,
 		unmarshaler:       c.unmarshaler,
+		topicUnmarshalers: topicUnmarshalers,
 		nextConsumer:      c.nextConsumer,
 		ready:             make(chan bool),
 		obsrecv:           obsrecv,
 		autocommitEnabled: c.autocommitEnabled,
 		messageMarking:    c.messageMarking,
 		headerExtractor:   &nopHeaderExtractor{},
+		messageAttributes: c.config.MessageAttributes,
 		telemetryBuilder:  c.telemetryBuilder,
 	}
 	if c.headerExtraction {
@@ -214,25 +224,13 @@ func (c *kafkaTracesConsumer) Start(_ context.Context, host component.Host) erro
 	}
 	c.consumeLoopWG.Add(1)
 	go c.consumeLoop(ctx, consumerGroup)
-	<-consumerGroup.ready
+	waitReady(consumerGroup.ready, c.subscription)
 	return nil
 }
 
 func (c *kafkaTracesConsumer) consumeLoop(ctx context.Context, handler sarama.ConsumerGroupHandler) {
 	defer c.consumeLoopWG.Done()
-	for {
-		// `Consume` should be called inside an infinite loop, when a
-		// server-side rebalance happens, the consumer session will need to be
-		// recreated to get the new claims
-		if err := c.consumerGroup.Consume(ctx, c.topics, handler); err != nil {
-			c.settings.Logger.Error("Error from consumer", zap.Error(err))
-		}
-		// check if context was cancelled, signaling that the consumer should stop
-		if ctx.Err() != nil {
-			c.settings.Logger.Info("Consumer stopped", zap.Error(ctx.Err()))
-			return
-		}
-	}
+	consumeLoop(ctx, c.consumerGroup, c.topics, c.subscription, handler, c.settings.Logger)
 }
 
 func (c *kafkaTracesConsumer) Shutdown(context.Context) error {
@@ -241,10 +239,11 @@ func (c *kafkaTracesConsumer) Shutdown(context.Context) error {
 	}
 	c.cancelConsumeLoop()
 	c.consumeLoopWG.Wait()
+	err := c.subscription.shutdown()
 	if c.consumerGroup == nil {
-		return nil
+		return err
 	}
-	return c.consumerGroup.Close()
+	return errors.Join(err, c.consumerGroup.Close())
 }
 
 func newMetricsReceiver(config Config, set receiver.Settings, nextConsumer consumer.Metrics) (*kafkaMetricsConsumer, error) {
@@ -255,7 +254,7 @@ func newMetricsReceiver(config Config, set receiver.Settings, nextConsumer consu
 
 	return &kafkaMetricsConsumer{
 		config:            config,
-		topics:            []string{config.Topic},
+		topics:            staticTopics(config),
 		nextConsumer:      nextConsumer,
 		consumeLoopWG:     &sync.WaitGroup{},
 		settings:          set,
@@ -281,21 +280,14 @@ func (c *kafkaMetricsConsumer) Start(_ context.Context, host component.Host) err
 	if err != nil {
 		return err
 	}
-	// extensions take precedence over internal encodings
-	if unmarshaler, errExt := loadEncodingExtension[pmetric.Unmarshaler](
-		host,
-		c.config.Encoding,
-	); errExt == nil {
-		c.unmarshaler = &metricsEncodingUnmarshaler{
-			unmarshaler: *unmarshaler,
-			encoding:    c.config.Encoding,
-		}
-	}
-	if unmarshaler, ok := defaultMetricsUnmarshalers()[c.config.Encoding]; c.unmarshaler == nil && ok {
-		c.unmarshaler = unmarshaler
+	if c.unmarshaler, err = newMetricsUnmarshaler(host, c.config.Encoding); err != nil {
+		return err
 	}
-	if c.unmarshaler == nil {
-		return errUnrecognizedEncoding
+	topicUnmarshalers := make(map[string]MetricsUnmarshaler, len(c.config.TopicEncodings))
+	for topic, encoding := range c.config.TopicEncodings {
+		if topicUnmarshalers[topic], err = newMetricsUnmarshaler(host, encoding); err != nil {
+			return fmt.Errorf("encoding of topic %q: %w", topic, err)
+		}
 	}
 	// consumerGroup may be set in tests to inject fake implementation.
 	if c.consumerGroup == nil {
@@ -303,15 +295,21 @@ func (c *kafkaMetricsConsumer) Start(_ context.Context, host component.Host) err
 			return err
 		}
 	}
+	// subscription may be set in tests to inject fake topics.
+	if c.subscription, err = startTopicSubscription(ctx, c.config, c.subscription, c.settings.Logger, c.consumeLoopWG); err != nil {
+		return err
+	}
 	metricsConsumerGroup := &metricsConsumerGroupHandler{
 		logger:            c.settings.Logger,
 		unmarshaler:       c.unmarshaler,
+		topicUnmarshalers: topicUnmarshalers,
 		nextConsumer:      c.nextConsumer,
 		ready:             make(chan bool),
 		obsrecv:           obsrecv,
 		autocommitEnabled: c.autocommitEnabled,
 		messageMarking:    c.messageMarking,
 		headerExtractor:   &nopHeaderExtractor{},
+		messageAttributes: c.config.MessageAttributes,
 		telemetryBuilder:  c.telemetryBuilder,
 	}
 	if c.headerExtraction {
@@ -322,25 +320,13 @@ func (c *kafkaMetricsConsumer) Start(_ context.Context, host component.Host) err
 	}
 	c.consumeLoopWG.Add(1)
 	go c.consumeLoop(ctx, metricsConsumerGroup)
-	<-metricsConsumerGroup.ready
+	waitReady(metricsConsumerGroup.ready, c.subscription)
 	return nil
 }
 
 func (c *kafkaMetricsConsumer) consumeLoop(ctx context.Context, handler sarama.ConsumerGroupHandler) {
 	defer c.consumeLoopWG.Done()
-	for {
-		// `Consume` should be called inside an infinite loop, when a
-		// server-side rebalance happens, the consumer session will need to be
-		// recreated to get the new claims
-		if err := c.consumerGroup.Consume(ctx, c.topics, handler); err != nil {
-			c.settings.Logger.Error("Error from consumer", zap.Error(err))
-		}
-		// check if context was cancelled, signaling that the consumer should stop
-		if ctx.Err() != nil {
-			c.settings.Logger.Info("Consumer stopped", zap.Error(ctx.Err()))
-			return
-		}
-	}
+	consumeLoop(ctx, c.consumerGroup, c.topics, c.subscription, handler, c.settings.Logger)
 }
 
 func (c *kafkaMetricsConsumer) Shutdown(context.Context) error {
@@ -349,10 +335,11 @@ func (c *kafkaMetricsConsumer) Shutdown(context.Context) error {
 	}
 	c.cancelConsumeLoop()
 	c.consumeLoopWG.Wait()
+	err := c.subscription.shutdown()
 	if c.consumerGroup == nil {
-		return nil
+		return err
 	}
-	return c.consumerGroup.Close()
+	return errors.Join(err, c.consumerGroup.Close())
 }
 
 func newLogsReceiver(config Config, set receiver.Settings, nextConsumer consumer.Logs) (*kafkaLogsConsumer, error) {
@@ -363,7 +350,7 @@ func newLogsReceiver(config Config, set receiver.Settings, nextConsumer consumer
 
 	return &kafkaLogsConsumer{
 		config:            config,
-		topics:            []string{config.Topic},
+		topics:            staticTopics(config),
 		nextConsumer:      nextConsumer,
 		consumeLoopWG:     &sync.WaitGroup{},
 		settings:          set,
@@ -389,24 +376,14 @@ func (c *kafkaLogsConsumer) Start(_ context.Context, host component.Host) error
 	if err != nil {
 		return err
 	}
-	// extensions take precedence over internal encodings
-	if unmarshaler, errExt := loadEncodingExtension[plog.Unmarshaler](
-		host,
-		c.config.Encoding,
-	); errExt == nil {
-		c.unmarshaler = &logsEncodingUnmarshaler{
-			unmarshaler: *unmarshaler,
-			encoding:    c.config.Encoding,
-		}
-	}
-	if unmarshaler, errInt := getLogsUnmarshaler(
-		c.config.Encoding,
-		defaultLogsUnmarshalers(c.settings.BuildInfo.Version, c.settings.Logger),
-	); c.unmarshaler == nil && errInt == nil {
-		c.unmarshaler = unmarshaler
+	if c.unmarshaler, err = newLogsUnmarshaler(host, c.config.Encoding, c.settings); err != nil {
+		return err
 	}
-	if c.unmarshaler == nil {
-		return errUnrecognizedEncoding
+	topicUnmarshalers := make(map[string]LogsUnmarshaler, len(c.config.TopicEncodings))
+	for topic, encoding := range c.config.TopicEncodings {
+		if topicUnmarshalers[topic], err = newLogsUnmarshaler(host, encoding, c.settings); err != nil {
+			return fmt.Errorf("encoding of topic %q: %w", topic, err)
+		}
 	}
 	// consumerGroup may be set in tests to inject fake implementation.
 	if c.consumerGroup == nil {
@@ -414,15 +391,21 @@ func (c *kafkaLogsConsumer) Start(_ context.Context, host component.Host) error
 			return err
 		}
 	}
+	// subscription may be set in tests to inject fake topics.
+	if c.subscription, err = startTopicSubscription(ctx, c.config, c.subscription, c.settings.Logger, c.consumeLoopWG); err != nil {
+		return err
+	}
 	logsConsumerGroup := &logsConsumerGroupHandler{
 		logger:            c.settings.Logger,
 		unmarshaler:       c.unmarshaler,
+		topicUnmarshalers: topicUnmarshalers,
 		nextConsumer:      c.nextConsumer,
 		ready:             make(chan bool),
 		obsrecv:           obsrecv,
 		autocommitEnabled: c.autocommitEnabled,
 		messageMarking:    c.messageMarking,
 		headerExtractor:   &nopHeaderExtractor{},
+		messageAttributes: c.config.MessageAttributes,
 		telemetryBuilder:  c.telemetryBuilder,
 	}
 	if c.headerExtraction {
@@ -433,25 +416,13 @@ func (c *kafkaLogsConsumer) Start(_ context.Context, host component.Host) error
 	}
 	c.consumeLoopWG.Add(1)
 	go c.consumeLoop(ctx, logsConsumerGroup)
-	<-logsConsumerGroup.ready
+	waitReady(logsConsumerGroup.ready, c.subscription)
 	return nil
 }
 
 func (c *kafkaLogsConsumer) consumeLoop(ctx context.Context, handler sarama.ConsumerGroupHandler) {
 	defer c.consumeLoopWG.Done()
-	for {
-		// `Consume` should be called inside an infinite loop, when a
-		// server-side rebalance happens, the consumer session will need to be
-		// recreated to get the new claims
-		if err := c.consumerGroup.Consume(ctx, c.topics, handler); err != nil {
-			c.settings.Logger.Error("Error from consumer", zap.Error(err))
-		}
-		// check if context was cancelled, signaling that the consumer should stop
-		if ctx.Err() != nil {
-			c.settings.Logger.Info("Consumer stopped", zap.Error(ctx.Err()))
-			return
-		}
-	}
+	consumeLoop(ctx, c.consumerGroup, c.topics, c.subscription, handler, c.settings.Logger)
 }
 
 func (c *kafkaLogsConsumer) Shutdown(context.Context) error {
@@ -460,18 +431,20 @@ func (c *kafkaLogsConsumer) Shutdown(context.Context) error {
 	}
 	c.cancelConsumeLoop()
 	c.consumeLoopWG.Wait()
+	err := c.subscription.shutdown()
 	if c.consumerGroup == nil {
-		return nil
+		return err
 	}
-	return c.consumerGroup.Close()
+	return errors.Join(err, c.consumerGroup.Close())
 }
 
 type tracesConsumerGroupHandler struct {
-	id           component.ID
-	unmarshaler  TracesUnmarshaler
-	nextConsumer consumer.Traces
-	ready        chan bool
-	readyCloser  sync.Once
+	id                component.ID
+	unmarshaler       TracesUnmarshaler
+	topicUnmarshalers map[string]TracesUnmarshaler
+	nextConsumer      consumer.Traces
+	ready             chan bool
+	readyCloser       sync.Once
 
 	logger *zap.Logger
 
@@ -481,14 +454,16 @@ type tracesConsumerGroupHandler struct {
 	autocommitEnabled bool
 	messageMarking    MessageMarking
 	headerExtractor   HeaderExtractor
+	messageAttributes bool
 }
 
 type metricsConsumerGroupHandler struct {
-	id           component.ID
-	unmarshaler  MetricsUnmarshaler
-	nextConsumer consumer.Metrics
-	ready        chan bool
-	readyCloser  sync.Once
+	id                component.ID
+	unmarshaler       MetricsUnmarshaler
+	topicUnmarshalers map[string]MetricsUnmarshaler
+	nextConsumer      consumer.Metrics
+	ready             chan bool
+	readyCloser       sync.Once
 
 	logger *zap.Logger
 
@@ -498,14 +473,16 @@ type metricsConsumerGroupHandler struct {
 	autocommitEnabled bool
 	messageMarking    MessageMarking
 	headerExtractor   HeaderExtractor
+	messageAttributes bool
 }
 
 type logsConsumerGroupHandler struct {
-	id           component.ID
-	unmarshaler  LogsUnmarshaler
-	nextConsumer consumer.Logs
-	ready        chan bool
-	readyCloser  sync.Once
+	id                component.ID
+	unmarshaler       LogsUnmarshaler
+	topicUnmarshalers map[string]LogsUnmarshaler
+	nextConsumer      consumer.Logs
+	ready             chan bool
+	readyCloser       sync.Once
 
 	logger *zap.Logger
 
@@ -515,6 +492,7 @@ type logsConsumerGroupHandler struct {
 	autocommitEnabled bool
 	messageMarking    MessageMarking
 	headerExtractor   HeaderExtractor
+	messageAttributes bool
 }
 
 var (
@@ -523,6 +501,30 @@ var (
 	_ sarama.ConsumerGroupHandler = (*logsConsumerGroupHandler)(nil)
 )
 
+// unmarshalerFor returns the unmarshaler of the messages of the topic.
+func (c *tracesConsumerGroupHandler) unmarshalerFor(topic string) TracesUnmarshaler {
+	if unmarshaler, ok := c.topicUnmarshalers[topic]; ok {
+		return unmarshaler
+	}
+	return c.unmarshaler
+}
+
+// unmarshalerFor returns the unmarshaler of the messages of the topic.
+func (c *metricsConsumerGroupHandler) unmarshalerFor(topic string) MetricsUnmarshaler {
+	if unmarshaler, ok := c.topicUnmarshalers[topic]; ok {
+		return unmarshaler
+	}
+	return c.unmarshaler
+}
+
+// unmarshalerFor returns the unmarshaler of the messages of the topic.
+func (c *logsConsumerGroupHandler) unmarshalerFor(topic string) LogsUnmarshaler {
+	if unmarshaler, ok := c.topicUnmarshalers[topic]; ok {
+		return unmarshaler
+	}
+	return c.unmarshaler
+}
+
 func (c *tracesConsumerGroupHandler) Setup(session sarama.ConsumerGroupSession) error {
 	c.readyCloser.Do(func() {
 		close(c.ready)
@@ -564,7 +566,8 @@ func (c *tracesConsumerGroupHandler) ConsumeClaim(session sarama.ConsumerGroupSe
 			c.telemetryBuilder.KafkaReceiverCurrentOffset.Record(ctx, message.Offset, metric.WithAttributeSet(attrs))
 			c.telemetryBuilder.KafkaReceiverOffsetLag.Record(ctx, claim.HighWaterMarkOffset()-message.Offset-1, metric.WithAttributeSet(attrs))
 
-			traces, err := c.unmarshaler.Unmarshal(message.Value)
+			unmarshaler := c.unmarshalerFor(message.Topic)
+			traces, err := unmarshaler.Unmarshal(message.Value)
 			if err != nil {
 				c.logger.Error("failed to unmarshal message", zap.Error(err))
 				c.telemetryBuilder.KafkaReceiverUnmarshalFailedSpans.Add(session.Context(), 1, metric.WithAttributes(attribute.String(attrInstanceName, c.id.String())))
@@ -575,9 +578,14 @@ func (c *tracesConsumerGroupHandler) ConsumeClaim(session sarama.ConsumerGroupSe
 			}
 
 			c.headerExtractor.extractHeadersTraces(traces, message)
+			if c.messageAttributes {
+				for i := 0; i < traces.ResourceSpans().Len(); i++ {
+					putMessageAttributes(traces.ResourceSpans().At(i).Resource().Attributes(), message)
+				}
+			}
 			spanCount := traces.SpanCount()
 			err = c.nextConsumer.ConsumeTraces(session.Context(), traces)
-			c.obsrecv.EndTracesOp(ctx, c.unmarshaler.Encoding(), spanCount, err)
+			c.obsrecv.EndTracesOp(ctx, unmarshaler.Encoding(), spanCount, err)
 			if err != nil {
 				if c.messageMarking.After && c.messageMarking.OnError {
 					session.MarkMessage(message, "")
@@ -641,7 +649,8 @@ func (c *metricsConsumerGroupHandler) ConsumeClaim(session sarama.ConsumerGroupS
 			c.telemetryBuilder.KafkaReceiverCurrentOffset.Record(ctx, message.Offset, metric.WithAttributeSet(attrs))
 			c.telemetryBuilder.KafkaReceiverOffsetLag.Record(ctx, claim.HighWaterMarkOffset()-message.Offset-1, metric.WithAttributeSet(attrs))
 
-			metrics, err := c.unmarshaler.Unmarshal(message.Value)
+			unmarshaler := c.unmarshalerFor(message.Topic)
+			metrics, err := unmarshaler.Unmarshal(message.Value)
 			if err != nil {
 				c.logger.Error("failed to unmarshal message", zap.Error(err))
 				c.telemetryBuilder.KafkaReceiverUnmarshalFailedMetricPoints.Add(session.Context(), 1, metric.WithAttributes(attribute.String(attrInstanceName, c.id.String())))
@@ -651,10 +660,15 @@ func (c *metricsConsumerGroupHandler) ConsumeClaim(session sarama.ConsumerGroupS
 				return err
 			}
 			c.headerExtractor.extractHeadersMetrics(metrics, message)
+			if c.messageAttributes {
+				for i := 0; i < metrics.ResourceMetrics().Len(); i++ {
+					putMessageAttributes(metrics.ResourceMetrics().At(i).Resource().Attributes(), message)
+				}
+			}
 
 			dataPointCount := metrics.DataPointCount()
 			err = c.nextConsumer.ConsumeMetrics(session.Context(), metrics)
-			c.obsrecv.EndMetricsOp(ctx, c.unmarshaler.Encoding(), dataPointCount, err)
+			c.obsrecv.EndMetricsOp(ctx, unmarshaler.Encoding(), dataPointCount, err)
 			if err != nil {
 				if c.messageMarking.After && c.messageMarking.OnError {
 					session.MarkMessage(message, "")
@@ -718,7 +732,8 @@ func (c *logsConsumerGroupHandler) ConsumeClaim(session sarama.ConsumerGroupSess
 			c.telemetryBuilder.KafkaReceiverCurrentOffset.Record(ctx, message.Offset, metric.WithAttributeSet(attrs))
 			c.telemetryBuilder.KafkaReceiverOffsetLag.Record(ctx, claim.HighWaterMarkOffset()-message.Offset-1, metric.WithAttributeSet(attrs))
 
-			logs, err := c.unmarshaler.Unmarshal(message.Value)
+			unmarshaler := c.unmarshalerFor(message.Topic)
+			logs, err := unmarshaler.Unmarshal(message.Value)
 			if err != nil {
 				c.logger.Error("failed to unmarshal message", zap.Error(err))
 				c.telemetryBuilder.KafkaReceiverUnmarshalFailedLogRecords.Add(ctx, 1, metric.WithAttributes(attribute.String(attrInstanceName, c.id.String())))
@@ -728,9 +743,14 @@ func (c *logsConsumerGroupHandler) ConsumeClaim(session sarama.ConsumerGroupSess
 				return err
 			}
 			c.headerExtractor.extractHeadersLogs(logs, message)
+			if c.messageAttributes {
+				for i := 0; i < logs.ResourceLogs().Len(); i++ {
+					putMessageAttributes(logs.ResourceLogs().At(i).Resource().Attributes(), message)
+				}
+			}
 			logRecordCount := logs.LogRecordCount()
 			err = c.nextConsumer.ConsumeLogs(session.Context(), logs)
-			c.obsrecv.EndLogsOp(ctx, c.unmarshaler.Encoding(), logRecordCount, err)
+			c.obsrecv.EndLogsOp(ctx, unmarshaler.Encoding(), logRecordCount, err)
 			if err != nil {
 				if c.messageMarking.After && c.messageMarking.OnError {
 					session.MarkMessage(message, "")
@@ -766,6 +786,54 @@ func toSaramaInitialOffset(initialOffset string) (int64, error) {
 	}
 }
 
+// newTracesUnmarshaler returns the unmarshaler of the encoding, extensions
+// take precedence over internal encodings.
+func newTracesUnmarshaler(host component.Host, encoding string) (TracesUnmarshaler, error) {
+	if unmarshaler, errExt := loadEncodingExtension[ptrace.Unmarshaler](host, encoding); errExt == nil {
+		return &tracesEncodingUnmarshaler{
+			unmarshaler: *unmarshaler,
+			encoding:    encoding,
+		}, nil
+	}
+	if unmarshaler, ok := defaultTracesUnmarshalers()[encoding]; ok {
+		return unmarshaler, nil
+	}
+	return nil, errUnrecognizedEncoding
+}
+
+// newMetricsUnmarshaler returns the unmarshaler of the encoding, extensions
+// take precedence over internal encodings.
+func newMetricsUnmarshaler(host component.Host, encoding string) (MetricsUnmarshaler, error) {
+	if unmarshaler, errExt := loadEncodingExtension[pmetric.Unmarshaler](host, encoding); errExt == nil {
+		return &metricsEncodingUnmarshaler{
+			unmarshaler: *unmarshaler,
+			encoding:    encoding,
+		}, nil
+	}
+	if unmarshaler, ok := defaultMetricsUnmarshalers()[encoding]; ok {
+		return unmarshaler, nil
+	}
+	return nil, errUnrecognizedEncoding
+}
+
+// newLogsUnmarshaler returns the unmarshaler of the encoding, extensions
+// take precedence over internal encodings.
+func newLogsUnmarshaler(host component.Host, encoding string, set receiver.Settings) (LogsUnmarshaler, error) {
+	if unmarshaler, errExt := loadEncodingExtension[plog.Unmarshaler](host, encoding); errExt == nil {
+		return &logsEncodingUnmarshaler{
+			unmarshaler: *unmarshaler,
+			encoding:    encoding,
+		}, nil
+	}
+	if unmarshaler, errInt := getLogsUnmarshaler(
+		encoding,
+		defaultLogsUnmarshalers(set.BuildInfo.Version, set.Logger),
+	); errInt == nil {
+		return unmarshaler, nil
+	}
+	return nil, errUnrecognizedEncoding
+}
+
 // loadEncodingExtension tries to load an available extension for the given encoding.
 func loadEncodingExtension[T any](host component.Host, encoding string) (*T, error) {
 	extensionID, err := encodingToComponentID(encoding)
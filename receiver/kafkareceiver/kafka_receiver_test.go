@@ -6,6 +6,7 @@ package kafkareceiver
 import (
 	"context"
 	"errors"
+	"regexp"
 	"sync"
 	"testing"
 	"time"
@@ -30,7 +31,6 @@ import (
 	"go.uber.org/zap/zapcore"
 	"go.uber.org/zap/zaptest/observer"
 
-	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/kafkaexporter"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/coreinternal/textutils"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/kafka"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/kafkareceiver/internal/metadata"
@@ -70,7 +70,7 @@ func TestNewTracesReceiver_err_auth_type(t *testing.T) {
 			},
 		},
 		Encoding: defaultEncoding,
-		Metadata: kafkaexporter.Metadata{
+		Metadata: kafka.Metadata{
 			Full: false,
 		},
 	}
@@ -429,7 +429,7 @@ func TestNewMetricsExporter_err_auth_type(t *testing.T) {
 			},
 		},
 		Encoding: defaultEncoding,
-		Metadata: kafkaexporter.Metadata{
+		Metadata: kafka.Metadata{
 			Full: false,
 		},
 	}
@@ -774,7 +774,7 @@ func TestNewLogsExporter_err_auth_type(t *testing.T) {
 			},
 		},
 		Encoding: defaultEncoding,
-		Metadata: kafkaexporter.Metadata{
+		Metadata: kafka.Metadata{
 			Full: false,
 		},
 	}
@@ -1222,6 +1222,104 @@ func TestLogsReceiver_encoding_extension(t *testing.T) {
 	}, 10*time.Second, time.Millisecond*100)
 }
 
+func TestLogsConsumerGroupHandler_topics(t *testing.T) {
+	obsrecv, err := receiverhelper.NewObsReport(receiverhelper.ObsReportSettings{ReceiverCreateSettings: receivertest.NewNopSettings()})
+	require.NoError(t, err)
+	textUnmarshaler, err := newTextLogsUnmarshaler().WithEnc("utf8")
+	require.NoError(t, err)
+	sink := &consumertest.LogsSink{}
+	c := logsConsumerGroupHandler{
+		unmarshaler: newPdataLogsUnmarshaler(&plog.ProtoUnmarshaler{}, defaultEncoding),
+		topicUnmarshalers: map[string]LogsUnmarshaler{
+			"team_a_logs": textUnmarshaler,
+		},
+		logger:            zap.NewNop(),
+		ready:             make(chan bool),
+		nextConsumer:      sink,
+		obsrecv:           obsrecv,
+		headerExtractor:   &nopHeaderExtractor{},
+		messageAttributes: true,
+		telemetryBuilder:  nopTelemetryBuilder(t),
+	}
+
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+	groupClaim := &testConsumerGroupClaim{
+		messageChan: make(chan *sarama.ConsumerMessage),
+	}
+	go func() {
+		assert.NoError(t, c.ConsumeClaim(testConsumerGroupSession{ctx: context.Background()}, groupClaim))
+		wg.Done()
+	}()
+
+	logs := testdata.GenerateLogs(1)
+	bts, err := (&plog.ProtoMarshaler{}).MarshalLogs(logs)
+	require.NoError(t, err)
+	groupClaim.messageChan <- &sarama.ConsumerMessage{Topic: "otlp_logs", Partition: 1, Offset: 10, Value: bts}
+	groupClaim.messageChan <- &sarama.ConsumerMessage{Topic: "team_a_logs", Partition: 2, Offset: 20, Value: []byte("plain text")}
+	close(groupClaim.messageChan)
+	wg.Wait()
+
+	require.Len(t, sink.AllLogs(), 2)
+	protoLogs := sink.AllLogs()[0].ResourceLogs().At(0)
+	assert.Equal(t, logs.LogRecordCount(), sink.AllLogs()[0].LogRecordCount())
+	assert.Equal(t, map[string]any{
+		"resource-attr":                      "resource-attr-val-1",
+		"messaging.destination.name":         "otlp_logs",
+		"messaging.destination.partition.id": "1",
+		"messaging.kafka.offset":             int64(10),
+	}, protoLogs.Resource().Attributes().AsRaw())
+
+	textLogs := sink.AllLogs()[1].ResourceLogs().At(0)
+	assert.Equal(t, "plain text", textLogs.ScopeLogs().At(0).LogRecords().At(0).Body().Str())
+	assert.Equal(t, map[string]any{
+		"messaging.destination.name":         "team_a_logs",
+		"messaging.destination.partition.id": "2",
+		"messaging.kafka.offset":             int64(20),
+	}, textLogs.Resource().Attributes().AsRaw())
+}
+
+func TestLogsReceiver_topic_encoding_err(t *testing.T) {
+	c := kafkaLogsConsumer{
+		config: Config{
+			Encoding:       defaultEncoding,
+			TopicEncodings: map[string]string{"team_a_logs": "unknown"},
+		},
+		nextConsumer:     consumertest.NewNop(),
+		consumeLoopWG:    &sync.WaitGroup{},
+		settings:         receivertest.NewNopSettings(),
+		consumerGroup:    &testConsumerGroup{},
+		telemetryBuilder: nopTelemetryBuilder(t),
+	}
+	err := c.Start(context.Background(), componenttest.NewNopHost())
+	require.ErrorIs(t, err, errUnrecognizedEncoding)
+	assert.ErrorContains(t, err, `encoding of topic "team_a_logs"`)
+}
+
+func TestTracesReceiver_topic_regex(t *testing.T) {
+	c := kafkaTracesConsumer{
+		config:        Config{Encoding: defaultEncoding},
+		nextConsumer:  consumertest.NewNop(),
+		consumeLoopWG: &sync.WaitGroup{},
+		settings:      receivertest.NewNopSettings(),
+		consumerGroup: &testConsumerGroup{},
+		subscription: &topicSubscription{
+			regex:    regexp.MustCompile("^team_.*_spans$"),
+			interval: time.Hour,
+			listTopics: func() ([]string, error) {
+				return nil, nil
+			},
+			logger:  zap.NewNop(),
+			changed: make(chan struct{}),
+		},
+		telemetryBuilder: nopTelemetryBuilder(t),
+	}
+
+	// Start doesn't wait for a consumer group session while no topic matches.
+	require.NoError(t, c.Start(context.Background(), componenttest.NewNopHost()))
+	require.NoError(t, c.Shutdown(context.Background()))
+}
+
 func TestToSaramaInitialOffset_earliest(t *testing.T) {
 	saramaInitialOffset, err := toSaramaInitialOffset(offsetEarliest)
 
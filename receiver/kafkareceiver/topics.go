@@ -0,0 +1,228 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package kafkareceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/kafkareceiver"
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/IBM/sarama"
+	"go.uber.org/zap"
+)
+
+// staticTopics returns the topics listed in the configuration, without
+// duplicates.
+func staticTopics(config Config) []string {
+	var topics []string
+	for _, topic := range append([]string{config.Topic}, config.Topics...) {
+		if topic != "" && !slices.Contains(topics, topic) {
+			topics = append(topics, topic)
+		}
+	}
+	return topics
+}
+
+// compileTopicRegex compiles the topic regex, anchored so that it matches whole
+// topic names: "logs" matches the topic "logs" but not "app_logs".
+func compileTopicRegex(pattern string) (*regexp.Regexp, error) {
+	return regexp.Compile("^(?:" + pattern + ")$")
+}
+
+// topicSubscription keeps the topics consumed by the receiver: the static
+// topics of the configuration and the topics of the cluster matching the
+// topic regex, which are listed every refresh interval.
+type topicSubscription struct {
+	static     []string
+	regex      *regexp.Regexp
+	interval   time.Duration
+	listTopics func() ([]string, error)
+	close      func() error
+	logger     *zap.Logger
+
+	mu     sync.Mutex
+	topics []string
+	// changed is closed, and replaced, when the topics change.
+	changed chan struct{}
+}
+
+// newTopicSubscription creates a subscription listing the topics of the
+// cluster with a dedicated sarama client.
+func newTopicSubscription(ctx context.Context, config Config, logger *zap.Logger) (*topicSubscription, error) {
+	saramaConfig, err := newSaramaConfig(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+	client, err := sarama.NewClient(config.Brokers, saramaConfig)
+	if err != nil {
+		return nil, err
+	}
+	listTopics := func() ([]string, error) {
+		if err := client.RefreshMetadata(); err != nil {
+			return nil, err
+		}
+		return client.Topics()
+	}
+	// The regex is validated with the configuration.
+	regex, _ := compileTopicRegex(config.TopicRegex)
+	return &topicSubscription{
+		static:     staticTopics(config),
+		regex:      regex,
+		interval:   config.TopicRefreshInterval,
+		listTopics: listTopics,
+		close:      client.Close,
+		logger:     logger,
+		changed:    make(chan struct{}),
+	}, nil
+}
+
+// startTopicSubscription lists the topics matching the topic regex and starts
+// refreshing them in the background. The subscription is created, unless
+// given, when the topic regex is set, otherwise it returns nil.
+func startTopicSubscription(
+	ctx context.Context,
+	config Config,
+	subscription *topicSubscription,
+	logger *zap.Logger,
+	wg *sync.WaitGroup,
+) (*topicSubscription, error) {
+	if subscription == nil {
+		if config.TopicRegex == "" {
+			return nil, nil
+		}
+		var err error
+		if subscription, err = newTopicSubscription(ctx, config, logger); err != nil {
+			return nil, err
+		}
+	}
+	if err := subscription.refresh(); err != nil {
+		return nil, errors.Join(err, subscription.shutdown())
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		subscription.run(ctx)
+	}()
+	return subscription, nil
+}
+
+// refresh lists the topics of the cluster, and signals the consumers when the
+// topics matching the regex changed.
+func (s *topicSubscription) refresh() error {
+	clusterTopics, err := s.listTopics()
+	if err != nil {
+		return err
+	}
+	topics := slices.Clone(s.static)
+	for _, topic := range clusterTopics {
+		if s.regex.MatchString(topic) && !slices.Contains(topics, topic) {
+			topics = append(topics, topic)
+		}
+	}
+	slices.Sort(topics)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if slices.Equal(topics, s.topics) {
+		return nil
+	}
+	s.logger.Info("Subscribed topics changed", zap.Strings("topics", topics))
+	s.topics = topics
+	close(s.changed)
+	s.changed = make(chan struct{})
+	return nil
+}
+
+// run refreshes the topics every refresh interval until ctx is cancelled.
+func (s *topicSubscription) run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if err := s.refresh(); err != nil {
+				s.logger.Error("Failed to refresh the subscribed topics", zap.Error(err))
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// shutdown closes the client used to list the topics.
+func (s *topicSubscription) shutdown() error {
+	if s == nil || s.close == nil {
+		return nil
+	}
+	return s.close()
+}
+
+// session returns the topics to consume and a context, derived from ctx, that
+// is cancelled when the topics change so the consumer group session is
+// recreated with the new topics.
+func (s *topicSubscription) session(ctx context.Context) ([]string, context.Context, context.CancelFunc) {
+	s.mu.Lock()
+	topics, changed := s.topics, s.changed
+	s.mu.Unlock()
+
+	sessionCtx, cancel := context.WithCancel(ctx)
+	go func() {
+		select {
+		case <-changed:
+			cancel()
+		case <-sessionCtx.Done():
+		}
+	}()
+	return topics, sessionCtx, cancel
+}
+
+// waitReady blocks until the handler sets up its first consumer group session,
+// unless no topic matches the topic regex yet.
+func waitReady(ready <-chan bool, subscription *topicSubscription) {
+	if subscription != nil {
+		subscription.mu.Lock()
+		empty := len(subscription.topics) == 0
+		subscription.mu.Unlock()
+		if empty {
+			return
+		}
+	}
+	<-ready
+}
+
+// consumeLoop calls Consume of the consumer group until ctx is cancelled. The
+// topics come from the subscription when there is one.
+func consumeLoop(
+	ctx context.Context,
+	consumerGroup sarama.ConsumerGroup,
+	topics []string,
+	subscription *topicSubscription,
+	handler sarama.ConsumerGroupHandler,
+	logger *zap.Logger,
+) {
+	// `Consume` should be called inside an infinite loop, when a
+	// server-side rebalance happens, the consumer session will need to be
+	// recreated to get the new claims
+	for {
+		sessionCtx, cancelSession := ctx, context.CancelFunc(func() {})
+		if subscription != nil {
+			topics, sessionCtx, cancelSession = subscription.session(ctx)
+		}
+		if subscription != nil && len(topics) == 0 {
+			// Nothing matches the topic regex yet, wait for the next refresh.
+			<-sessionCtx.Done()
+		} else if err := consumerGroup.Consume(sessionCtx, topics, handler); err != nil {
+			logger.Error("Error from consumer", zap.Error(err))
+		}
+		cancelSession()
+		// check if context was cancelled, signaling that the consumer should stop
+		if ctx.Err() != nil {
+			logger.Info("Consumer stopped", zap.Error(ctx.Err()))
+			return
+		}
+	}
+}
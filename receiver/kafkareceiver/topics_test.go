@@ -0,0 +1,157 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package kafkareceiver
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestStaticTopics(t *testing.T) {
+	assert.Equal(t, []string{"spans"}, staticTopics(Config{Topic: "spans"}))
+	assert.Equal(t, []string{"spans", "team_a", "team_b"}, staticTopics(Config{
+		Topic:  "spans",
+		Topics: []string{"team_a", "spans", "team_b"},
+	}))
+	assert.Empty(t, staticTopics(Config{TopicRegex: "team_.*"}))
+}
+
+func TestCompileTopicRegex(t *testing.T) {
+	regex, err := compileTopicRegex("logs|team_.*_logs")
+	require.NoError(t, err)
+	assert.True(t, regex.MatchString("logs"))
+	assert.True(t, regex.MatchString("team_a_logs"))
+	assert.False(t, regex.MatchString("app_logs"))
+	assert.False(t, regex.MatchString("logs_archive"))
+	assert.False(t, regex.MatchString("team_a_logs_archive"))
+
+	_, err = compileTopicRegex("team_(")
+	assert.Error(t, err)
+}
+
+func TestTopicSubscription_refresh(t *testing.T) {
+	clusterTopics := []string{"team_b_logs", "team_a_logs", "other"}
+	var listErr error
+	s := &topicSubscription{
+		static: []string{"static"},
+		regex:  regexp.MustCompile("^team_.*_logs$"),
+		listTopics: func() ([]string, error) {
+			return clusterTopics, listErr
+		},
+		logger:  zap.NewNop(),
+		changed: make(chan struct{}),
+	}
+
+	topics, ctx, cancel := s.session(context.Background())
+	defer cancel()
+	assert.Empty(t, topics)
+
+	require.NoError(t, s.refresh())
+	<-ctx.Done()
+	topics, ctx, cancel = s.session(context.Background())
+	defer cancel()
+	assert.Equal(t, []string{"static", "team_a_logs", "team_b_logs"}, topics)
+
+	// The session isn't cancelled while the topics don't change.
+	clusterTopics = []string{"team_a_logs", "team_b_logs"}
+	require.NoError(t, s.refresh())
+	assert.NoError(t, ctx.Err())
+
+	listErr = errors.New("no broker")
+	assert.ErrorIs(t, s.refresh(), listErr)
+	assert.NoError(t, ctx.Err())
+
+	listErr = nil
+	clusterTopics = []string{"team_a_logs", "team_c_logs"}
+	require.NoError(t, s.refresh())
+	<-ctx.Done()
+	topics, _, cancel = s.session(context.Background())
+	defer cancel()
+	assert.Equal(t, []string{"static", "team_a_logs", "team_c_logs"}, topics)
+}
+
+func TestConsumeLoop_subscription(t *testing.T) {
+	var mu sync.Mutex
+	clusterTopics := []string{"team_a_logs"}
+	s := &topicSubscription{
+		regex:    regexp.MustCompile("^team_.*_logs$"),
+		interval: 10 * time.Millisecond,
+		listTopics: func() ([]string, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			return clusterTopics, nil
+		},
+		logger:  zap.NewNop(),
+		changed: make(chan struct{}),
+	}
+	wg := &sync.WaitGroup{}
+	ctx, cancel := context.WithCancel(context.Background())
+	subscription, err := startTopicSubscription(ctx, Config{}, s, zap.NewNop(), wg)
+	require.NoError(t, err)
+	require.Same(t, s, subscription)
+
+	consumerGroup := &recordingConsumerGroup{}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		consumeLoop(ctx, consumerGroup, nil, s, nil, zap.NewNop())
+	}()
+
+	assert.Eventually(t, func() bool {
+		return slices.Equal(consumerGroup.lastTopics(), []string{"team_a_logs"})
+	}, 10*time.Second, 5*time.Millisecond)
+
+	mu.Lock()
+	clusterTopics = []string{"team_a_logs", "team_b_logs"}
+	mu.Unlock()
+	assert.Eventually(t, func() bool {
+		return slices.Equal(consumerGroup.lastTopics(), []string{"team_a_logs", "team_b_logs"})
+	}, 10*time.Second, 5*time.Millisecond)
+
+	cancel()
+	wg.Wait()
+	require.NoError(t, s.shutdown())
+}
+
+func TestStartTopicSubscription_noRegex(t *testing.T) {
+	s, err := startTopicSubscription(context.Background(), Config{Topic: "spans"}, nil, zap.NewNop(), &sync.WaitGroup{})
+	require.NoError(t, err)
+	assert.Nil(t, s)
+	assert.NoError(t, s.shutdown())
+}
+
+// recordingConsumerGroup records the topics of the sessions, which last until
+// their context is cancelled.
+type recordingConsumerGroup struct {
+	testConsumerGroup
+	mu     sync.Mutex
+	topics [][]string
+}
+
+func (g *recordingConsumerGroup) Consume(ctx context.Context, topics []string, _ sarama.ConsumerGroupHandler) error {
+	g.mu.Lock()
+	g.topics = append(g.topics, topics)
+	g.mu.Unlock()
+	<-ctx.Done()
+	return nil
+}
+
+func (g *recordingConsumerGroup) lastTopics() []string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if len(g.topics) == 0 {
+		return nil
+	}
+	return g.topics[len(g.topics)-1]
+}
@@ -106,6 +106,47 @@ func TestLoadTargetAllocatorConfig(t *testing.T) {
 	assert.Equal(t, promModel.Duration(5*time.Second), r2.PrometheusConfig.ScrapeConfigs[0].ScrapeInterval)
 }
 
+func TestLoadShardingConfig(t *testing.T) {
+	cm, err := confmaptest.LoadConf(filepath.Join("testdata", "config_sharding.yaml"))
+	require.NoError(t, err)
+	factory := NewFactory()
+
+	cfg := factory.CreateDefaultConfig()
+	sub, err := cm.Sub(component.NewIDWithName(metadata.Type, "").String())
+	require.NoError(t, err)
+	require.NoError(t, sub.Unmarshal(cfg))
+	require.NoError(t, component.ValidateConfig(cfg))
+
+	r0 := cfg.(*Config)
+	assert.Equal(t, "10.0.0.1", r0.Sharding.Self)
+	assert.Equal(t, "otelcol-headless.observability.svc.cluster.local", r0.Sharding.Resolver.DNS.Hostname)
+	assert.Equal(t, 10*time.Second, r0.Sharding.Resolver.DNS.Interval)
+	assert.Equal(t, 2*time.Second, r0.Sharding.Resolver.DNS.Timeout)
+
+	cfg = factory.CreateDefaultConfig()
+	sub, err = cm.Sub(component.NewIDWithName(metadata.Type, "k8s").String())
+	require.NoError(t, err)
+	require.NoError(t, sub.Unmarshal(cfg))
+	require.NoError(t, component.ValidateConfig(cfg))
+
+	r1 := cfg.(*Config)
+	assert.Equal(t, "otelcol-0", r1.Sharding.Self)
+	assert.Equal(t, "otelcol-headless.observability", r1.Sharding.Resolver.K8s.Service)
+	assert.True(t, r1.Sharding.Resolver.K8s.ReturnHostnames)
+
+	cfg = factory.CreateDefaultConfig()
+	sub, err = cm.Sub(component.NewIDWithName(metadata.Type, "withTA").String())
+	require.NoError(t, err)
+	require.NoError(t, sub.Unmarshal(cfg))
+	require.ErrorContains(t, component.ValidateConfig(cfg), "sharding and target_allocator cannot be used together")
+
+	cfg = factory.CreateDefaultConfig()
+	sub, err = cm.Sub(component.NewIDWithName(metadata.Type, "withoutResolver").String())
+	require.NoError(t, err)
+	require.NoError(t, sub.Unmarshal(cfg))
+	require.ErrorContains(t, component.ValidateConfig(cfg), "no sharding resolver configured")
+}
+
 func TestValidateConfigWithScrapeConfigFiles(t *testing.T) {
 	cm, err := confmaptest.LoadConf(filepath.Join("testdata", "config_scrape_config_files.yaml"))
 	require.NoError(t, err)
@@ -44,11 +44,14 @@ type metricGroup struct {
 	sum          float64
 	hasSum       bool
 	created      float64
+	createdMs    int64
 	value        float64
 	hValue       *histogram.Histogram
 	fhValue      *histogram.FloatHistogram
 	complexValue []*dataPoint
 	exemplars    pmetric.ExemplarSlice
+	// customBuckets is set if the buckets were added from a native histogram with custom buckets.
+	customBuckets bool
 }
 
 func newMetricFamily(metricName string, mc scrape.MetricMetadataStore, logger *zap.Logger) *metricFamily {
@@ -84,6 +87,20 @@ func (mg *metricGroup) sortPoints() {
 	})
 }
 
+// startTimestamp returns the start time of the data point, from the _created series or the created
+// timestamp if there is one.
+func (mg *metricGroup) startTimestamp(tsNanos pcommon.Timestamp) pcommon.Timestamp {
+	switch {
+	case mg.created != 0:
+		return timestampFromFloat64(mg.created)
+	case mg.createdMs != 0:
+		return timestampFromMs(mg.createdMs)
+	default:
+		// metrics_adjuster adjusts the startTimestamp to the initial scrape timestamp
+		return tsNanos
+	}
+}
+
 func (mg *metricGroup) toDistributionPoint(dest pmetric.HistogramDataPointSlice) {
 	if !mg.hasCount {
 		return
@@ -143,12 +160,7 @@ func (mg *metricGroup) toDistributionPoint(dest pmetric.HistogramDataPointSlice)
 
 	// The timestamp MUST be in retrieved from milliseconds and converted to nanoseconds.
 	tsNanos := timestampFromMs(mg.ts)
-	if mg.created != 0 {
-		point.SetStartTimestamp(timestampFromFloat64(mg.created))
-	} else {
-		// metrics_adjuster adjusts the startTimestamp to the initial scrape timestamp
-		point.SetStartTimestamp(tsNanos)
-	}
+	point.SetStartTimestamp(mg.startTimestamp(tsNanos))
 	point.SetTimestamp(tsNanos)
 	populateAttributes(pmetric.MetricTypeHistogram, mg.ls, point.Attributes())
 	mg.setExemplars(point.Exemplars())
@@ -221,12 +233,7 @@ func (mg *metricGroup) toExponentialHistogramDataPoints(dest pmetric.Exponential
 	}
 
 	tsNanos := timestampFromMs(mg.ts)
-	if mg.created != 0 {
-		point.SetStartTimestamp(timestampFromFloat64(mg.created))
-	} else {
-		// metrics_adjuster adjusts the startTimestamp to the initial scrape timestamp
-		point.SetStartTimestamp(tsNanos)
-	}
+	point.SetStartTimestamp(mg.startTimestamp(tsNanos))
 	point.SetTimestamp(tsNanos)
 	populateAttributes(pmetric.MetricTypeHistogram, mg.ls, point.Attributes())
 	mg.setExemplars(point.Exemplars())
@@ -315,12 +322,7 @@ func (mg *metricGroup) toSummaryPoint(dest pmetric.SummaryDataPointSlice) {
 	// The timestamp MUST be in retrieved from milliseconds and converted to nanoseconds.
 	tsNanos := timestampFromMs(mg.ts)
 	point.SetTimestamp(tsNanos)
-	if mg.created != 0 {
-		point.SetStartTimestamp(timestampFromFloat64(mg.created))
-	} else {
-		// metrics_adjuster adjusts the startTimestamp to the initial scrape timestamp
-		point.SetStartTimestamp(tsNanos)
-	}
+	point.SetStartTimestamp(mg.startTimestamp(tsNanos))
 	populateAttributes(pmetric.MetricTypeSummary, mg.ls, point.Attributes())
 }
 
@@ -329,12 +331,7 @@ func (mg *metricGroup) toNumberDataPoint(dest pmetric.NumberDataPointSlice) {
 	point := dest.AppendEmpty()
 	// gauge/undefined types have no start time.
 	if mg.mtype == pmetric.MetricTypeSum {
-		if mg.created != 0 {
-			point.SetStartTimestamp(timestampFromFloat64(mg.created))
-		} else {
-			// metrics_adjuster adjusts the startTimestamp to the initial scrape timestamp
-			point.SetStartTimestamp(tsNanos)
-		}
+		point.SetStartTimestamp(mg.startTimestamp(tsNanos))
 	}
 	point.SetTimestamp(tsNanos)
 	if value.IsStaleNaN(mg.value) {
@@ -388,6 +385,14 @@ func (mf *metricFamily) addSeries(seriesRef uint64, metricName string, ls labels
 	}
 	switch mf.mtype {
 	case pmetric.MetricTypeHistogram, pmetric.MetricTypeSummary:
+		if mg.customBuckets {
+			// Getting a classic histogram series after the native histogram with custom buckets means
+			// that `scrape_classic_histograms` is set to true, the classic histogram is preferred.
+			mg.customBuckets = false
+			mg.complexValue = nil
+			mg.hasCount = false
+			mg.hasSum = false
+		}
 		switch {
 		case strings.HasSuffix(metricName, metricsSuffixSum):
 			mg.sum = v
@@ -456,6 +461,59 @@ func (mf *metricFamily) addExponentialHistogramSeries(seriesRef uint64, metricNa
 	return nil
 }
 
+// addCustomBucketsHistogramSeries adds a native histogram with custom buckets as the buckets,
+// count and sum of a classic histogram.
+func (mf *metricFamily) addCustomBucketsHistogramSeries(seriesRef uint64, metricName string, ls labels.Labels, t int64, h *histogram.Histogram, fh *histogram.FloatHistogram) error {
+	mg := mf.loadMetricGroupOrCreate(seriesRef, ls, t)
+	if mg.ts != t {
+		return fmt.Errorf("inconsistent timestamps on metric points for metric %v", metricName)
+	}
+	if mg.mtype != pmetric.MetricTypeHistogram {
+		return fmt.Errorf("metric type mismatch for custom buckets histogram metric %v type %s", metricName, mg.mtype.String())
+	}
+	if len(mg.complexValue) > 0 && !mg.customBuckets {
+		// The classic histogram series were already added.
+		return nil
+	}
+	if fh == nil {
+		fh = h.ToFloat(nil)
+	}
+
+	counts := make([]float64, len(fh.CustomValues)+1)
+	it := fh.PositiveBucketIterator()
+	for it.Next() {
+		bucket := it.At()
+		if int(bucket.Index) < len(counts) {
+			counts[bucket.Index] += bucket.Count
+		}
+	}
+
+	// The bucket values are cumulative, as for classic histograms.
+	mg.complexValue = make([]*dataPoint, 0, len(fh.CustomValues))
+	var cumulative float64
+	for i, bound := range fh.CustomValues {
+		cumulative += counts[i]
+		mg.complexValue = append(mg.complexValue, &dataPoint{value: cumulative, boundary: bound})
+	}
+	mg.count = fh.Count
+	mg.sum = fh.Sum
+	mg.hasCount = true
+	mg.hasSum = true
+	mg.customBuckets = true
+	return nil
+}
+
+// addCreatedTimestamp sets the start time of a series from its created timestamp in milliseconds,
+// unless it was already set from a _created series.
+func (mf *metricFamily) addCreatedTimestamp(seriesRef uint64, ctMs int64) {
+	if ctMs == 0 {
+		return
+	}
+	if mg, ok := mf.groups[seriesRef]; ok {
+		mg.createdMs = ctMs
+	}
+}
+
 func (mf *metricFamily) appendMetric(metrics pmetric.MetricSlice, trimSuffixes bool) {
 	metric := pmetric.NewMetric()
 	// Trims type and unit suffixes from metric name
@@ -7,6 +7,7 @@ import (
 	"errors"
 	"regexp"
 
+	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/pmetric"
 	"go.uber.org/zap"
 )
@@ -20,13 +21,16 @@ var (
 type startTimeMetricAdjuster struct {
 	startTimeMetricRegex *regexp.Regexp
 	logger               *zap.Logger
+	useCreatedMetric     bool
 }
 
 // NewStartTimeMetricAdjuster returns a new MetricsAdjuster that adjust metrics' start times based on a start time metric.
-func NewStartTimeMetricAdjuster(logger *zap.Logger, startTimeMetricRegex *regexp.Regexp) MetricsAdjuster {
+// If useCreatedMetric is set, the start times set from the _created series or created timestamps are kept.
+func NewStartTimeMetricAdjuster(logger *zap.Logger, startTimeMetricRegex *regexp.Regexp, useCreatedMetric bool) MetricsAdjuster {
 	return &startTimeMetricAdjuster{
 		startTimeMetricRegex: startTimeMetricRegex,
 		logger:               logger,
+		useCreatedMetric:     useCreatedMetric,
 	}
 }
 
@@ -51,6 +55,9 @@ func (stma *startTimeMetricAdjuster) AdjustMetrics(metrics pmetric.Metrics) erro
 					dataPoints := metric.Sum().DataPoints()
 					for l := 0; l < dataPoints.Len(); l++ {
 						dp := dataPoints.At(l)
+						if stma.hasCreatedStartTime(dp.StartTimestamp(), dp.Timestamp(), dp.Flags()) {
+							continue
+						}
 						dp.SetStartTimestamp(startTimeTs)
 					}
 
@@ -58,6 +65,9 @@ func (stma *startTimeMetricAdjuster) AdjustMetrics(metrics pmetric.Metrics) erro
 					dataPoints := metric.Summary().DataPoints()
 					for l := 0; l < dataPoints.Len(); l++ {
 						dp := dataPoints.At(l)
+						if stma.hasCreatedStartTime(dp.StartTimestamp(), dp.Timestamp(), dp.Flags()) {
+							continue
+						}
 						dp.SetStartTimestamp(startTimeTs)
 					}
 
@@ -65,6 +75,9 @@ func (stma *startTimeMetricAdjuster) AdjustMetrics(metrics pmetric.Metrics) erro
 					dataPoints := metric.Histogram().DataPoints()
 					for l := 0; l < dataPoints.Len(); l++ {
 						dp := dataPoints.At(l)
+						if stma.hasCreatedStartTime(dp.StartTimestamp(), dp.Timestamp(), dp.Flags()) {
+							continue
+						}
 						dp.SetStartTimestamp(startTimeTs)
 					}
 
@@ -72,6 +85,9 @@ func (stma *startTimeMetricAdjuster) AdjustMetrics(metrics pmetric.Metrics) erro
 					dataPoints := metric.ExponentialHistogram().DataPoints()
 					for l := 0; l < dataPoints.Len(); l++ {
 						dp := dataPoints.At(l)
+						if stma.hasCreatedStartTime(dp.StartTimestamp(), dp.Timestamp(), dp.Flags()) {
+							continue
+						}
 						dp.SetStartTimestamp(startTimeTs)
 					}
 
@@ -88,6 +104,12 @@ func (stma *startTimeMetricAdjuster) AdjustMetrics(metrics pmetric.Metrics) erro
 	return nil
 }
 
+// hasCreatedStartTime returns true if the start time of a point was set from its created timestamp,
+// which is preferred over the start time metric.
+func (stma *startTimeMetricAdjuster) hasCreatedStartTime(start, ts pcommon.Timestamp, flags pmetric.DataPointFlags) bool {
+	return stma.useCreatedMetric && !flags.NoRecordedValue() && start < ts
+}
+
 func (stma *startTimeMetricAdjuster) getStartTime(metrics pmetric.Metrics) (float64, error) {
 	for i := 0; i < metrics.ResourceMetrics().Len(); i++ {
 		rm := metrics.ResourceMetrics().At(i)
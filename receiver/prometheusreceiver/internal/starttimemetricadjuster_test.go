@@ -4,10 +4,12 @@
 package internal
 
 import (
+	"fmt"
 	"regexp"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/pmetric"
 	"go.uber.org/zap"
@@ -113,7 +115,7 @@ func TestStartTimeMetricMatch(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			stma := NewStartTimeMetricAdjuster(zap.NewNop(), tt.startTimeMetricRegex)
+			stma := NewStartTimeMetricAdjuster(zap.NewNop(), tt.startTimeMetricRegex, false)
 			if tt.expectedErr != nil {
 				assert.ErrorIs(t, stma.AdjustMetrics(tt.inputs), tt.expectedErr)
 				return
@@ -154,3 +156,30 @@ func TestStartTimeMetricMatch(t *testing.T) {
 		})
 	}
 }
+
+func TestStartTimeMetricPrefersCreatedTimestamp(t *testing.T) {
+	const createdTime = pcommon.Timestamp(120 * 1e9)
+	const currentTime = pcommon.Timestamp(126 * 1e9)
+	const startTimeMetricValue = 124
+
+	for _, useCreatedMetric := range []bool{false, true} {
+		t.Run(fmt.Sprintf("useCreatedMetric=%v", useCreatedMetric), func(t *testing.T) {
+			inputs := metrics(
+				// The start time of the first point was set from its created timestamp.
+				sumMetric("test_sum_metric", doublePoint(nil, createdTime, currentTime, 16), doublePoint(nil, currentTime, currentTime, 10)),
+				gaugeMetric("process_start_time_seconds", doublePoint(nil, currentTime, currentTime, startTimeMetricValue)),
+			)
+
+			stma := NewStartTimeMetricAdjuster(zap.NewNop(), nil, useCreatedMetric)
+			require.NoError(t, stma.AdjustMetrics(inputs))
+
+			dps := inputs.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0).Sum().DataPoints()
+			if useCreatedMetric {
+				assert.Equal(t, createdTime, dps.At(0).StartTimestamp())
+			} else {
+				assert.Equal(t, timestampFromFloat64(startTimeMetricValue), dps.At(0).StartTimestamp())
+			}
+			assert.Equal(t, timestampFromFloat64(startTimeMetricValue), dps.At(1).StartTimestamp())
+		})
+	}
+}
@@ -50,6 +50,9 @@ type transaction struct {
 	obsrecv                *receiverhelper.ObsReport
 	// Used as buffer to calculate series ref hash.
 	bufBytes []byte
+	// ctLabels and ctMs hold the created timestamp appended for the next sample, see AppendCTZeroSample.
+	ctLabels labels.Labels
+	ctMs     int64
 }
 
 var emptyScopeID scopeID
@@ -89,6 +92,8 @@ func newTransaction(
 
 // Append always returns 0 to disable label caching.
 func (t *transaction) Append(_ storage.SeriesRef, ls labels.Labels, atMs int64, val float64) (storage.SeriesRef, error) {
+	ctMs := t.takeCreatedTimestamp(ls)
+
 	select {
 	case <-t.ctx.Done():
 		return 0, errTransactionAborted
@@ -160,7 +165,9 @@ func (t *transaction) Append(_ storage.SeriesRef, ls labels.Labels, atMs int64,
 
 	seriesRef := t.getSeriesRef(ls, curMF.mtype)
 	err = curMF.addSeries(seriesRef, metricName, ls, atMs, val)
-	if err != nil {
+	if err == nil {
+		curMF.addCreatedTimestamp(seriesRef, ctMs)
+	} else {
 		// Handle special case of float sample indicating staleness of native
 		// histogram. This is similar to how Prometheus handles it, but we
 		// don't have access to the previous value so we're applying some
@@ -239,6 +246,7 @@ func (t *transaction) AppendExemplar(_ storage.SeriesRef, l labels.Labels, e exe
 }
 
 func (t *transaction) AppendHistogram(_ storage.SeriesRef, ls labels.Labels, atMs int64, h *histogram.Histogram, fh *histogram.FloatHistogram) (storage.SeriesRef, error) {
+	ctMs := t.takeCreatedTimestamp(ls)
 	if !t.enableNativeHistograms {
 		return 0, nil
 	}
@@ -278,10 +286,18 @@ func (t *transaction) AppendHistogram(_ storage.SeriesRef, ls labels.Labels, atM
 	// The `up`, `target_info`, `otel_scope_info` metrics should never generate native histograms,
 	// thus we don't check for them here as opposed to the Append function.
 
+	// Native histograms with custom buckets have explicit bounds, they are converted to
+	// OTEL histograms instead of exponential histograms.
+	customBuckets := h != nil && h.UsesCustomBuckets() || fh != nil && fh.UsesCustomBuckets()
+	mtype := pmetric.MetricTypeExponentialHistogram
+	if customBuckets {
+		mtype = pmetric.MetricTypeHistogram
+	}
+
 	curMF, existing := t.getOrCreateMetricFamily(*rKey, getScopeID(ls), metricName)
 	if !existing {
-		curMF.mtype = pmetric.MetricTypeExponentialHistogram
-	} else if curMF.mtype != pmetric.MetricTypeExponentialHistogram {
+		curMF.mtype = mtype
+	} else if curMF.mtype != mtype {
 		// Already scraped as classic histogram.
 		return 0, nil
 	}
@@ -290,19 +306,44 @@ func (t *transaction) AppendHistogram(_ storage.SeriesRef, ls labels.Labels, atM
 		t.logger.Warn("dropping unsupported gauge histogram datapoint", zap.String("metric_name", metricName), zap.Any("labels", ls))
 	}
 
-	err = curMF.addExponentialHistogramSeries(t.getSeriesRef(ls, curMF.mtype), metricName, ls, atMs, h, fh)
+	seriesRef := t.getSeriesRef(ls, curMF.mtype)
+	if customBuckets {
+		err = curMF.addCustomBucketsHistogramSeries(seriesRef, metricName, ls, atMs, h, fh)
+	} else {
+		err = curMF.addExponentialHistogramSeries(seriesRef, metricName, ls, atMs, h, fh)
+	}
 	if err != nil {
 		t.logger.Warn("failed to add histogram datapoint", zap.Error(err), zap.String("metric_name", metricName), zap.Any("labels", ls))
+	} else {
+		curMF.addCreatedTimestamp(seriesRef, ctMs)
 	}
 
 	return 0, nil // never return errors, as that fails the whole scrape
 }
 
-func (t *transaction) AppendCTZeroSample(_ storage.SeriesRef, _ labels.Labels, _, _ int64) (storage.SeriesRef, error) {
-	// TODO: implement this func
+// AppendCTZeroSample records the created timestamp of the series of the sample appended next. It
+// is used as the start time of the data point instead of adding a zero sample.
+func (t *transaction) AppendCTZeroSample(_ storage.SeriesRef, ls labels.Labels, atMs, ctMs int64) (storage.SeriesRef, error) {
+	if ctMs >= atMs {
+		return 0, storage.ErrOutOfOrderCT
+	}
+	t.ctLabels = ls
+	t.ctMs = ctMs
 	return 0, nil
 }
 
+// takeCreatedTimestamp returns the created timestamp recorded for the series, or 0 if there is none.
+// The recorded created timestamp is reset as it only applies to a single sample.
+func (t *transaction) takeCreatedTimestamp(ls labels.Labels) int64 {
+	ctMs := t.ctMs
+	if ctMs != 0 && !labels.Equal(t.ctLabels, ls) {
+		ctMs = 0
+	}
+	t.ctLabels = labels.EmptyLabels()
+	t.ctMs = 0
+	return ctMs
+}
+
 func (t *transaction) getSeriesRef(ls labels.Labels, mtype pmetric.MetricType) uint64 {
 	var hash uint64
 	hash, t.bufBytes = getSeriesRef(t.bufBytes, ls, mtype)
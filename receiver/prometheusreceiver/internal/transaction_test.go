@@ -16,6 +16,7 @@ import (
 	"github.com/prometheus/prometheus/model/labels"
 	"github.com/prometheus/prometheus/model/metadata"
 	"github.com/prometheus/prometheus/scrape"
+	"github.com/prometheus/prometheus/storage"
 	"github.com/prometheus/prometheus/tsdb/tsdbutil"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -1754,6 +1755,14 @@ func TestMetricBuilderNativeHistogram(t *testing.T) {
 			ZeroCount:     0,
 		}
 		h0 := tsdbutil.GenerateTestHistogram(0)
+		nhcb := &histogram.Histogram{
+			Schema:          histogram.CustomBucketsSchema,
+			Count:           5,
+			Sum:             20,
+			PositiveSpans:   []histogram.Span{{Offset: 1, Length: 1}, {Offset: 1, Length: 1}},
+			PositiveBuckets: []int64{2, 1},
+			CustomValues:    []float64{1, 5, 10},
+		}
 
 		tests := []buildTestData{
 			{
@@ -1830,6 +1839,71 @@ func TestMetricBuilderNativeHistogram(t *testing.T) {
 					pt0.Negative().BucketCounts().Append(1)
 					pt0.Negative().BucketCounts().Append(1)
 
+					return []pmetric.Metrics{md0}
+				},
+			},
+			{
+				name: "custom buckets histogram",
+				inputs: []*testScrapedPage{
+					{
+						pts: []*testDataPoint{
+							createHistogramDataPoint("hist_test", nhcb, nil, nil, "foo", "bar"),
+						},
+					},
+				},
+				wants: func() []pmetric.Metrics {
+					md0 := pmetric.NewMetrics()
+					if !enableNativeHistograms {
+						return []pmetric.Metrics{md0}
+					}
+					mL0 := md0.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics()
+					m0 := mL0.AppendEmpty()
+					m0.SetName("hist_test")
+					m0.Metadata().PutStr("prometheus.type", "histogram")
+					m0.SetEmptyHistogram()
+					m0.Histogram().SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+					pt0 := m0.Histogram().DataPoints().AppendEmpty()
+					pt0.Attributes().PutStr("foo", "bar")
+					pt0.SetStartTimestamp(startTimestamp)
+					pt0.SetTimestamp(tsNanos)
+					pt0.SetCount(5)
+					pt0.SetSum(20)
+					pt0.ExplicitBounds().FromRaw([]float64{1, 5, 10})
+					pt0.BucketCounts().FromRaw([]uint64{0, 2, 0, 3})
+
+					return []pmetric.Metrics{md0}
+				},
+			},
+			{
+				name: "custom buckets histogram with classic histogram",
+				inputs: []*testScrapedPage{
+					{
+						pts: []*testDataPoint{
+							createHistogramDataPoint("hist_test", nhcb, nil, nil, "foo", "bar"),
+							createDataPoint("hist_test_bucket", 1, nil, "foo", "bar", "le", "1"),
+							createDataPoint("hist_test_bucket", 3, nil, "foo", "bar", "le", "+Inf"),
+							createDataPoint("hist_test_count", 3, nil, "foo", "bar"),
+							createDataPoint("hist_test_sum", 7, nil, "foo", "bar"),
+						},
+					},
+				},
+				wants: func() []pmetric.Metrics {
+					md0 := pmetric.NewMetrics()
+					mL0 := md0.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics()
+					m0 := mL0.AppendEmpty()
+					m0.SetName("hist_test")
+					m0.Metadata().PutStr("prometheus.type", "histogram")
+					m0.SetEmptyHistogram()
+					m0.Histogram().SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+					pt0 := m0.Histogram().DataPoints().AppendEmpty()
+					pt0.Attributes().PutStr("foo", "bar")
+					pt0.SetStartTimestamp(startTimestamp)
+					pt0.SetTimestamp(tsNanos)
+					pt0.SetCount(3)
+					pt0.SetSum(7)
+					pt0.ExplicitBounds().FromRaw([]float64{1})
+					pt0.BucketCounts().FromRaw([]uint64{1, 2})
+
 					return []pmetric.Metrics{md0}
 				},
 			},
@@ -1843,6 +1917,56 @@ func TestMetricBuilderNativeHistogram(t *testing.T) {
 	}
 }
 
+func TestTransactionAppendCTZeroSample(t *testing.T) {
+	const ctMs = ts - 60*1000
+	sink := new(consumertest.MetricsSink)
+	tr := newTransaction(scrapeCtx, NewInitialPointAdjuster(zap.NewNop(), time.Minute, true), sink, labels.EmptyLabels(), receivertest.NewNopSettings(), nopObsRecv(t), false, true)
+
+	counter := createDataPoint("counter_test", 100, nil, "foo", "bar")
+	_, err := tr.AppendCTZeroSample(0, counter.lb, ts, ctMs)
+	require.NoError(t, err)
+	_, err = tr.Append(0, counter.lb, ts, counter.v)
+	require.NoError(t, err)
+
+	hist := createHistogramDataPoint("hist_test", tsdbutil.GenerateTestHistogram(0), nil, nil, "foo", "bar")
+	_, err = tr.AppendCTZeroSample(0, hist.lb, ts, ctMs)
+	require.NoError(t, err)
+	_, err = tr.AppendHistogram(0, hist.lb, ts, hist.h, nil)
+	require.NoError(t, err)
+
+	// The created timestamp only applies to the sample appended next.
+	_, err = tr.AppendCTZeroSample(0, counter.lb, ts, ctMs)
+	require.NoError(t, err)
+	counter2 := createDataPoint("counter_test2", 1, nil, "foo", "bar")
+	_, err = tr.Append(0, counter2.lb, ts, counter2.v)
+	require.NoError(t, err)
+
+	// Created timestamps which aren't before the sample are rejected.
+	_, err = tr.AppendCTZeroSample(0, counter.lb, ts, ts)
+	require.ErrorIs(t, err, storage.ErrOutOfOrderCT)
+
+	require.NoError(t, tr.Commit())
+	mds := sink.AllMetrics()
+	require.Len(t, mds, 1)
+
+	startTimes := map[string]pcommon.Timestamp{}
+	metrics := mds[0].ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics()
+	for i := 0; i < metrics.Len(); i++ {
+		m := metrics.At(i)
+		switch m.Type() {
+		case pmetric.MetricTypeSum:
+			startTimes[m.Name()] = m.Sum().DataPoints().At(0).StartTimestamp()
+		case pmetric.MetricTypeExponentialHistogram:
+			startTimes[m.Name()] = m.ExponentialHistogram().DataPoints().At(0).StartTimestamp()
+		}
+	}
+	assert.Equal(t, map[string]pcommon.Timestamp{
+		"counter_test":  timestampFromMs(ctMs),
+		"hist_test":     timestampFromMs(ctMs),
+		"counter_test2": tsNanos,
+	}, startTimes)
+}
+
 type buildTestData struct {
 	name   string
 	inputs []*testScrapedPage
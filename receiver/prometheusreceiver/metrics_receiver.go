@@ -26,6 +26,7 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/prometheusreceiver/internal"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/prometheusreceiver/sharding"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/prometheusreceiver/targetallocator"
 )
 
@@ -46,6 +47,7 @@ type pReceiver struct {
 	scrapeManager          *scrape.Manager
 	discoveryManager       *discovery.Manager
 	targetAllocatorManager *targetallocator.Manager
+	shardingManager        *sharding.Manager
 	registerer             prometheus.Registerer
 	unregisterMetrics      func()
 	skipOffsetting         bool // for testing only
@@ -68,6 +70,7 @@ func newPrometheusReceiver(set receiver.Settings, cfg *Config, next consumer.Met
 			&baseCfg,
 			enableNativeHistogramsGate.IsEnabled(),
 		),
+		shardingManager: sharding.NewManager(set, cfg.Sharding),
 	}
 	return pr
 }
@@ -157,6 +160,11 @@ func (r *pReceiver) initPrometheusComponents(ctx context.Context, logger log.Log
 		opts.EnableNativeHistogramsIngestion = true
 	}
 
+	if useCreatedMetricGate.IsEnabled() {
+		// The created timestamps of the series scraped with the protobuf format are used as start times.
+		opts.EnableCreatedTimestampZeroIngestion = true
+	}
+
 	// for testing only
 	if r.skipOffsetting {
 		optsValue := reflect.ValueOf(opts).Elem()
@@ -172,6 +180,12 @@ func (r *pReceiver) initPrometheusComponents(ctx context.Context, logger log.Log
 	}
 	r.scrapeManager = scrapeManager
 
+	// Only the targets owned by this collector are scraped when sharding is enabled.
+	syncCh, err := r.shardingManager.Start(ctx, r.discoveryManager.SyncCh())
+	if err != nil {
+		return err
+	}
+
 	r.unregisterMetrics = func() {
 		refreshSdMetrics.Unregister()
 		for _, sdMetric := range sdMetrics {
@@ -185,7 +199,7 @@ func (r *pReceiver) initPrometheusComponents(ctx context.Context, logger log.Log
 		// The scrape manager needs to wait for the configuration to be loaded before beginning
 		<-r.configLoaded
 		r.settings.Logger.Info("Starting scrape manager")
-		if err := r.scrapeManager.Run(r.discoveryManager.SyncCh()); err != nil {
+		if err := r.scrapeManager.Run(syncCh); err != nil {
 			r.settings.Logger.Error("Scrape manager failed", zap.Error(err))
 			componentstatus.ReportStatus(host, componentstatus.NewFatalErrorEvent(err))
 		}
@@ -220,6 +234,9 @@ func (r *pReceiver) Shutdown(context.Context) error {
 	if r.targetAllocatorManager != nil {
 		r.targetAllocatorManager.Shutdown()
 	}
+	if r.shardingManager != nil {
+		r.shardingManager.Shutdown()
+	}
 	if r.unregisterMetrics != nil {
 		r.unregisterMetrics()
 	}
@@ -608,6 +608,12 @@ func compareHistogramStartTimestamp(timeStamp pcommon.Timestamp) histogramPointC
 	}
 }
 
+func compareExponentialHistogramStartTimestamp(timeStamp pcommon.Timestamp) exponentialHistogramComparator {
+	return func(t *testing.T, histogramDataPoint pmetric.ExponentialHistogramDataPoint) {
+		assert.Equal(t, timeStamp.String(), histogramDataPoint.StartTimestamp().String(), "Exponential Histogram Start-Timestamp does not match")
+	}
+}
+
 func compareSummaryTimestamp(timeStamp pcommon.Timestamp) summaryPointComparator {
 	return func(t *testing.T, summaryDataPoint pmetric.SummaryDataPoint) {
 		assert.Equal(t, timeStamp.String(), summaryDataPoint.Timestamp().String(), "Summary Timestamp does not match")
@@ -6,11 +6,14 @@ package prometheusreceiver
 import (
 	"math"
 	"testing"
+	"time"
 
+	"github.com/gogo/protobuf/types"
 	"github.com/prometheus/prometheus/config"
 	dto "github.com/prometheus/prometheus/prompb/io/prometheus/client"
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/featuregate"
+	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/pmetric"
 )
 
@@ -611,3 +614,89 @@ func TestFloatCounterHistogram(t *testing.T) {
 		c.PrometheusConfig.GlobalConfig.ScrapeProtocols = []config.ScrapeProtocol{config.PrometheusProto}
 	})
 }
+
+func TestCreatedTimestampViaProtobuf(t *testing.T) {
+	created := time.Date(2024, 1, 1, 0, 0, 0, 500*int(time.Millisecond), time.UTC)
+	ct, err := types.TimestampProto(created)
+	require.NoError(t, err)
+
+	mf := &dto.MetricFamily{
+		Name: "test_counter",
+		Type: dto.MetricType_COUNTER,
+		Metric: []dto.Metric{
+			{
+				Counter: &dto.Counter{
+					Value:            1234,
+					CreatedTimestamp: ct,
+				},
+			},
+		},
+	}
+	buffer := prometheusMetricFamilyToProtoBuf(t, nil, mf)
+
+	mf = &dto.MetricFamily{
+		Name: "test_native_histogram",
+		Type: dto.MetricType_HISTOGRAM,
+		Metric: []dto.Metric{
+			{
+				Histogram: &dto.Histogram{
+					SampleCount:      3,
+					SampleSum:        5,
+					Schema:           0,
+					ZeroThreshold:    0.001,
+					PositiveSpan:     []dto.BucketSpan{{Offset: 1, Length: 2}},
+					PositiveDelta:    []int64{1, 1},
+					CreatedTimestamp: ct,
+				},
+			},
+		},
+	}
+	prometheusMetricFamilyToProtoBuf(t, buffer, mf)
+
+	startTimestamp := pcommon.NewTimestampFromTime(created)
+	expectations := []testExpectation{
+		assertMetricPresent(
+			"test_counter",
+			compareMetricType(pmetric.MetricTypeSum),
+			compareMetricUnit(""),
+			[]dataPointExpectation{{
+				numberPointComparator: []numberPointComparator{
+					compareStartTimestamp(startTimestamp),
+					compareDoubleValue(1234),
+				},
+			}},
+		),
+		assertMetricPresent(
+			"test_native_histogram",
+			compareMetricType(pmetric.MetricTypeExponentialHistogram),
+			compareMetricUnit(""),
+			[]dataPointExpectation{{
+				exponentialHistogramComparator: []exponentialHistogramComparator{
+					compareExponentialHistogramStartTimestamp(startTimestamp),
+					compareExponentialHistogram(0, 3, 5, 0, 0, nil, 0, []uint64{1, 2}),
+				},
+			}},
+		),
+	}
+
+	targets := []*testData{
+		{
+			name: "target1",
+			pages: []mockPrometheusResponse{
+				{code: 200, useProtoBuf: true, buf: buffer.Bytes()},
+			},
+			validateFunc: func(t *testing.T, td *testData, result []pmetric.ResourceMetrics) {
+				verifyNumValidScrapeResults(t, td, result)
+				doCompare(t, "target1", td.attributes, result[0], expectations)
+			},
+		},
+	}
+
+	for _, gate := range []string{useCreatedMetricGate.ID(), enableNativeHistogramsGate.ID()} {
+		require.NoError(t, featuregate.GlobalRegistry().Set(gate, true))
+		defer func() {
+			_ = featuregate.GlobalRegistry().Set(gate, false)
+		}()
+	}
+	testComponent(t, targets, nil)
+}
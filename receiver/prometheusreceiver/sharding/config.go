@@ -0,0 +1,92 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package sharding // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/prometheusreceiver/sharding"
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+// Config defines how the discovered targets are sharded between the collector replicas.
+type Config struct {
+	// Self identifies this collector among the peers returned by the resolver,
+	// e.g. its pod IP for the dns and k8s resolvers.
+	Self     string         `mapstructure:"self"`
+	Resolver ResolverConfig `mapstructure:"resolver"`
+}
+
+// ResolverConfig defines how the collector replicas are discovered. Exactly one resolver must be set.
+type ResolverConfig struct {
+	Static *StaticResolverConfig `mapstructure:"static"`
+	DNS    *DNSResolverConfig    `mapstructure:"dns"`
+	K8s    *K8sResolverConfig    `mapstructure:"k8s"`
+}
+
+// StaticResolverConfig defines a fixed list of collector replicas.
+type StaticResolverConfig struct {
+	Hostnames []string `mapstructure:"hostnames"`
+}
+
+// DNSResolverConfig defines a hostname resolving to the IP addresses of the collector replicas,
+// typically a Kubernetes headless service.
+type DNSResolverConfig struct {
+	Hostname string        `mapstructure:"hostname"`
+	Interval time.Duration `mapstructure:"interval"`
+	Timeout  time.Duration `mapstructure:"timeout"`
+}
+
+// K8sResolverConfig defines a Kubernetes service whose endpoints are the collector replicas.
+type K8sResolverConfig struct {
+	// Service is the name of the service, optionally followed by a dot and its namespace.
+	Service string        `mapstructure:"service"`
+	Timeout time.Duration `mapstructure:"timeout"`
+	// ReturnHostnames identifies the replicas by their hostname instead of their IP, e.g. for stateful sets.
+	ReturnHostnames bool `mapstructure:"return_hostnames"`
+}
+
+var (
+	errNoSelf            = errors.New("sharding self is not a valid identifier")
+	errNoResolver        = errors.New("no sharding resolver configured")
+	errMultipleResolvers = errors.New("only one sharding resolver can be configured")
+	errNoHostnames       = errors.New("no hostnames specified for the static sharding resolver")
+	errNoHostname        = errors.New("no hostname specified for the dns sharding resolver")
+	errNoService         = errors.New("no service specified for the k8s sharding resolver")
+)
+
+func (cfg *Config) Validate() error {
+	// ensure valid self without variables
+	if cfg.Self == "" || strings.Contains(cfg.Self, "${") {
+		return errNoSelf
+	}
+
+	count := 0
+	if cfg.Resolver.Static != nil {
+		count++
+		if len(cfg.Resolver.Static.Hostnames) == 0 {
+			return errNoHostnames
+		}
+	}
+	if cfg.Resolver.DNS != nil {
+		count++
+		if cfg.Resolver.DNS.Hostname == "" {
+			return errNoHostname
+		}
+	}
+	if cfg.Resolver.K8s != nil {
+		count++
+		if cfg.Resolver.K8s.Service == "" {
+			return errNoService
+		}
+	}
+
+	switch count {
+	case 0:
+		return errNoResolver
+	case 1:
+		return nil
+	default:
+		return errMultipleResolvers
+	}
+}
@@ -0,0 +1,75 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package sharding
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     Config
+		wantErr error
+	}{
+		{
+			name: "static",
+			cfg:  Config{Self: "a", Resolver: ResolverConfig{Static: &StaticResolverConfig{Hostnames: []string{"a", "b"}}}},
+		},
+		{
+			name: "dns",
+			cfg:  Config{Self: "10.0.0.1", Resolver: ResolverConfig{DNS: &DNSResolverConfig{Hostname: "otelcol-headless"}}},
+		},
+		{
+			name: "k8s",
+			cfg:  Config{Self: "10.0.0.1", Resolver: ResolverConfig{K8s: &K8sResolverConfig{Service: "otelcol-headless.observability"}}},
+		},
+		{
+			name:    "no self",
+			cfg:     Config{Resolver: ResolverConfig{DNS: &DNSResolverConfig{Hostname: "otelcol-headless"}}},
+			wantErr: errNoSelf,
+		},
+		{
+			name:    "unresolved self",
+			cfg:     Config{Self: "${POD_IP}", Resolver: ResolverConfig{DNS: &DNSResolverConfig{Hostname: "otelcol-headless"}}},
+			wantErr: errNoSelf,
+		},
+		{
+			name:    "no resolver",
+			cfg:     Config{Self: "a"},
+			wantErr: errNoResolver,
+		},
+		{
+			name: "multiple resolvers",
+			cfg: Config{Self: "a", Resolver: ResolverConfig{
+				Static: &StaticResolverConfig{Hostnames: []string{"a"}},
+				DNS:    &DNSResolverConfig{Hostname: "otelcol-headless"},
+			}},
+			wantErr: errMultipleResolvers,
+		},
+		{
+			name:    "static without hostnames",
+			cfg:     Config{Self: "a", Resolver: ResolverConfig{Static: &StaticResolverConfig{}}},
+			wantErr: errNoHostnames,
+		},
+		{
+			name:    "dns without hostname",
+			cfg:     Config{Self: "a", Resolver: ResolverConfig{DNS: &DNSResolverConfig{}}},
+			wantErr: errNoHostname,
+		},
+		{
+			name:    "k8s without service",
+			cfg:     Config{Self: "a", Resolver: ResolverConfig{K8s: &K8sResolverConfig{}}},
+			wantErr: errNoService,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.wantErr, tt.cfg.Validate())
+		})
+	}
+}
@@ -0,0 +1,66 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package sharding // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/prometheusreceiver/sharding"
+
+import (
+	"hash/crc32"
+	"sort"
+)
+
+const (
+	maxPositions uint32 = 36000 // 360 degrees with two decimal places
+	peerWeight   int    = 100   // the number of points in the ring for each peer
+)
+
+// ringItem connects a specific angle in the ring with a specific peer.
+type ringItem struct {
+	pos  uint32
+	peer string
+}
+
+// hashRing is an immutable consistent hash ring following Karger et al.,
+// the same as the one used by the loadbalancing exporter.
+type hashRing struct {
+	items []ringItem
+}
+
+// newHashRing builds a consistent hash ring for the given peers.
+func newHashRing(peers []string) *hashRing {
+	var items []ringItem
+	positions := map[uint32]bool{} // tracking the used positions
+	for _, peer := range peers {
+		for i := 0; i < peerWeight; i++ {
+			h := crc32.NewIEEE()
+			h.Write([]byte(peer))
+			h.Write([]byte{byte(i)})
+			pos := h.Sum32() % maxPositions
+			// if this position is occupied already, skip this item
+			if positions[pos] {
+				continue
+			}
+			positions[pos] = true
+			items = append(items, ringItem{pos: pos, peer: peer})
+		}
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].pos < items[j].pos
+	})
+	return &hashRing{items: items}
+}
+
+// peerFor returns the peer responsible for the given identifier, or an empty string if the ring is empty.
+func (h *hashRing) peerFor(identifier []byte) string {
+	if h == nil || len(h.items) == 0 {
+		return ""
+	}
+	pos := crc32.ChecksumIEEE(identifier) % maxPositions
+
+	// the closest "next" item, wrapping around the ring
+	i := sort.Search(len(h.items), func(i int) bool { return h.items[i].pos >= pos })
+	if i == len(h.items) {
+		i = 0
+	}
+	return h.items[i].peer
+}
@@ -0,0 +1,45 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package sharding
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHashRingEmpty(t *testing.T) {
+	var nilRing *hashRing
+	assert.Empty(t, nilRing.peerFor([]byte("target")))
+	assert.Empty(t, newHashRing(nil).peerFor([]byte("target")))
+}
+
+func TestHashRingDistribution(t *testing.T) {
+	peers := []string{"10.0.0.1", "10.0.0.2", "10.0.0.3"}
+	ring := newHashRing(peers)
+
+	counts := map[string]int{}
+	for i := 0; i < 3000; i++ {
+		counts[ring.peerFor([]byte(fmt.Sprintf("target-%d", i)))]++
+	}
+	require.Len(t, counts, len(peers))
+	for _, peer := range peers {
+		assert.Greater(t, counts[peer], 500, "peer %s owns too few targets", peer)
+	}
+}
+
+func TestHashRingMinimalRebalance(t *testing.T) {
+	before := newHashRing([]string{"10.0.0.1", "10.0.0.2", "10.0.0.3"})
+	after := newHashRing([]string{"10.0.0.1", "10.0.0.2", "10.0.0.3", "10.0.0.4"})
+
+	for i := 0; i < 1000; i++ {
+		key := []byte(fmt.Sprintf("target-%d", i))
+		// a target only moves to the new peer, never between the existing ones
+		if owner := after.peerFor(key); owner != "10.0.0.4" {
+			assert.Equal(t, before.peerFor(key), owner)
+		}
+	}
+}
@@ -0,0 +1,182 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package sharding // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/prometheusreceiver/sharding"
+
+import (
+	"context"
+	"encoding/binary"
+	"slices"
+	"sync"
+
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/discovery/targetgroup"
+	"go.opentelemetry.io/collector/receiver"
+	"go.uber.org/zap"
+)
+
+// Manager sits between the discovery and scrape managers and only forwards the discovered
+// targets this collector is responsible for, according to a consistent hash ring of all
+// collector replicas. The targets are redistributed whenever the replicas change.
+type Manager struct {
+	settings receiver.Settings
+	cfg      *Config
+	shutdown chan struct{}
+
+	// newResolver can be replaced for testing.
+	newResolver func() (resolver, error)
+	resolver    resolver
+
+	syncCh    chan map[string][]*targetgroup.Group
+	rebalance chan struct{}
+
+	mu      sync.Mutex // mu protects the fields below.
+	ring    *hashRing
+	targets map[string][]*targetgroup.Group
+}
+
+func NewManager(set receiver.Settings, cfg *Config) *Manager {
+	m := &Manager{
+		settings:  set,
+		cfg:       cfg,
+		shutdown:  make(chan struct{}),
+		syncCh:    make(chan map[string][]*targetgroup.Group),
+		rebalance: make(chan struct{}, 1),
+	}
+	m.newResolver = m.createResolver
+	return m
+}
+
+// Start starts discovering the collector replicas and returns the channel of the targets to scrape,
+// which is the given discovery channel itself when sharding is disabled.
+func (m *Manager) Start(ctx context.Context, discovered <-chan map[string][]*targetgroup.Group) (<-chan map[string][]*targetgroup.Group, error) {
+	if m.cfg == nil {
+		// sharding is disabled
+		return discovered, nil
+	}
+
+	res, err := m.newResolver()
+	if err != nil {
+		return nil, err
+	}
+	m.settings.Logger.Info("Starting sharding peers discovery", zap.String("self", m.cfg.Self))
+	if err = res.start(ctx, m.onPeersChange); err != nil {
+		return nil, err
+	}
+	m.resolver = res
+
+	go m.run(ctx, discovered)
+	return m.syncCh, nil
+}
+
+func (m *Manager) Shutdown() {
+	close(m.shutdown)
+	if m.resolver != nil {
+		if err := m.resolver.shutdown(context.Background()); err != nil {
+			m.settings.Logger.Warn("Failed to shutdown the sharding resolver", zap.Error(err))
+		}
+	}
+}
+
+func (m *Manager) createResolver() (resolver, error) {
+	switch {
+	case m.cfg.Resolver.Static != nil:
+		return newStaticResolver(m.cfg.Resolver.Static.Hostnames), nil
+	case m.cfg.Resolver.DNS != nil:
+		return newDNSResolver(m.settings.Logger, m.cfg.Resolver.DNS), nil
+	case m.cfg.Resolver.K8s != nil:
+		clt, err := newInClusterClient()
+		if err != nil {
+			return nil, err
+		}
+		return newK8sResolver(clt, m.settings.Logger, m.cfg.Resolver.K8s), nil
+	default:
+		return nil, errNoResolver
+	}
+}
+
+func (m *Manager) onPeersChange(peers []string) {
+	m.settings.Logger.Info("Sharding peers changed", zap.Strings("peers", peers))
+	if !slices.Contains(peers, m.cfg.Self) {
+		m.settings.Logger.Warn("This collector is not part of the sharding peers and will not scrape any target", zap.String("self", m.cfg.Self))
+	}
+
+	m.mu.Lock()
+	m.ring = newHashRing(peers)
+	m.mu.Unlock()
+
+	select {
+	case m.rebalance <- struct{}{}:
+	default:
+		// a rebalance is already pending
+	}
+}
+
+func (m *Manager) run(ctx context.Context, discovered <-chan map[string][]*targetgroup.Group) {
+	for {
+		select {
+		case tsets, ok := <-discovered:
+			if !ok {
+				return
+			}
+			m.mu.Lock()
+			m.targets = tsets
+			m.mu.Unlock()
+		case <-m.rebalance:
+		case <-ctx.Done():
+			return
+		case <-m.shutdown:
+			return
+		}
+
+		m.mu.Lock()
+		if m.targets == nil {
+			// nothing discovered yet
+			m.mu.Unlock()
+			continue
+		}
+		owned := m.filter(m.targets, m.ring)
+		m.mu.Unlock()
+
+		select {
+		case m.syncCh <- owned:
+		case <-ctx.Done():
+			return
+		case <-m.shutdown:
+			return
+		}
+	}
+}
+
+// filter returns the target groups with only the targets owned by this collector. Groups are
+// kept even when empty so the scrape manager stops scraping the targets it no longer owns.
+func (m *Manager) filter(tsets map[string][]*targetgroup.Group, ring *hashRing) map[string][]*targetgroup.Group {
+	total, owned := 0, 0
+	res := make(map[string][]*targetgroup.Group, len(tsets))
+	for job, groups := range tsets {
+		filtered := make([]*targetgroup.Group, 0, len(groups))
+		for _, group := range groups {
+			if group == nil {
+				continue
+			}
+			ownedGroup := &targetgroup.Group{Source: group.Source, Labels: group.Labels}
+			for _, target := range group.Targets {
+				total++
+				if ring.peerFor(targetKey(job, group, target)) == m.cfg.Self {
+					ownedGroup.Targets = append(ownedGroup.Targets, target)
+					owned++
+				}
+			}
+			filtered = append(filtered, ownedGroup)
+		}
+		res[job] = filtered
+	}
+	m.settings.Logger.Debug("Sharded discovered targets", zap.Int("discovered", total), zap.Int("owned", owned))
+	return res
+}
+
+// targetKey identifies a target by its job and its labels before relabeling.
+func targetKey(job string, group *targetgroup.Group, target model.LabelSet) []byte {
+	fingerprint := group.Labels.Merge(target).Fingerprint()
+	return binary.BigEndian.AppendUint64([]byte(job), uint64(fingerprint))
+}
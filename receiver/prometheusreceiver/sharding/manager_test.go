@@ -0,0 +1,125 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package sharding
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/discovery/targetgroup"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/receiver/receivertest"
+)
+
+type fakeResolver struct {
+	peers
+}
+
+func (r *fakeResolver) start(_ context.Context, onChange func([]string)) error {
+	r.setCallback(onChange)
+	return nil
+}
+
+func (r *fakeResolver) shutdown(context.Context) error {
+	r.setCallback(nil)
+	return nil
+}
+
+func discoveredTargets(n int) map[string][]*targetgroup.Group {
+	group := &targetgroup.Group{Source: "static/0", Labels: model.LabelSet{"env": "test"}}
+	for i := 0; i < n; i++ {
+		group.Targets = append(group.Targets, model.LabelSet{model.AddressLabel: model.LabelValue(fmt.Sprintf("10.1.0.%d:8080", i))})
+	}
+	return map[string][]*targetgroup.Group{"job": {group}}
+}
+
+func receive(t *testing.T, ch <-chan map[string][]*targetgroup.Group) map[string][]*targetgroup.Group {
+	select {
+	case tsets := <-ch:
+		return tsets
+	case <-time.After(5 * time.Second):
+		t.Fatal("no targets received")
+		return nil
+	}
+}
+
+func addresses(tsets map[string][]*targetgroup.Group) []string {
+	var res []string
+	for _, groups := range tsets {
+		for _, group := range groups {
+			for _, target := range group.Targets {
+				res = append(res, string(target[model.AddressLabel]))
+			}
+		}
+	}
+	return res
+}
+
+func TestManagerDisabled(t *testing.T) {
+	m := NewManager(receivertest.NewNopSettings(), nil)
+	discovered := make(chan map[string][]*targetgroup.Group)
+	syncCh, err := m.Start(context.Background(), discovered)
+	require.NoError(t, err)
+	assert.Equal(t, (<-chan map[string][]*targetgroup.Group)(discovered), syncCh)
+	m.Shutdown()
+}
+
+func TestManagerPartitionsTargets(t *testing.T) {
+	peers := []string{"10.0.0.1", "10.0.0.2"}
+	var owned []string
+	for _, self := range peers {
+		m := NewManager(receivertest.NewNopSettings(), &Config{
+			Self:     self,
+			Resolver: ResolverConfig{Static: &StaticResolverConfig{Hostnames: peers}},
+		})
+		discovered := make(chan map[string][]*targetgroup.Group, 1)
+		syncCh, err := m.Start(context.Background(), discovered)
+		require.NoError(t, err)
+
+		discovered <- discoveredTargets(50)
+		tsets := receive(t, syncCh)
+		require.Len(t, tsets["job"], 1)
+		assert.Equal(t, model.LabelSet{"env": "test"}, tsets["job"][0].Labels)
+		assert.NotEmpty(t, addresses(tsets))
+		owned = append(owned, addresses(tsets)...)
+		m.Shutdown()
+	}
+	assert.ElementsMatch(t, addresses(discoveredTargets(50)), owned)
+}
+
+func TestManagerRebalance(t *testing.T) {
+	res := &fakeResolver{}
+	m := NewManager(receivertest.NewNopSettings(), &Config{
+		Self:     "10.0.0.1",
+		Resolver: ResolverConfig{Static: &StaticResolverConfig{Hostnames: []string{"10.0.0.1"}}},
+	})
+	m.newResolver = func() (resolver, error) { return res, nil }
+
+	discovered := make(chan map[string][]*targetgroup.Group, 1)
+	syncCh, err := m.Start(context.Background(), discovered)
+	require.NoError(t, err)
+	defer m.Shutdown()
+
+	res.update([]string{"10.0.0.1"})
+	// wait for the initial rebalance to be consumed, nothing has been discovered yet
+	require.Eventually(t, func() bool { return len(m.rebalance) == 0 }, 5*time.Second, 10*time.Millisecond)
+	discovered <- discoveredTargets(20)
+	assert.Len(t, addresses(receive(t, syncCh)), 20)
+
+	// a new peer takes over part of the targets without a new discovery
+	res.update([]string{"10.0.0.1", "10.0.0.2"})
+	shared := addresses(receive(t, syncCh))
+	assert.NotEmpty(t, shared)
+	assert.Less(t, len(shared), 20)
+
+	// once removed from the peers, the groups are kept empty to stop scraping
+	res.update([]string{"10.0.0.2"})
+	tsets := receive(t, syncCh)
+	require.Len(t, tsets["job"], 1)
+	assert.Empty(t, tsets["job"][0].Targets)
+}
@@ -0,0 +1,71 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package sharding // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/prometheusreceiver/sharding"
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"sync"
+)
+
+// resolver determines the contract for sources of collector replicas.
+type resolver interface {
+	// start signals the resolver to start its work, the callback is invoked with the sorted
+	// list of peers every time it changes.
+	start(ctx context.Context, onChange func([]string)) error
+
+	// shutdown signals the resolver to finish its work. The callback is not invoked anymore once it returns.
+	shutdown(ctx context.Context) error
+}
+
+// peers keeps the last resolved list of peers and notifies its changes.
+type peers struct {
+	mu       sync.Mutex
+	current  []string
+	onChange func([]string)
+}
+
+func (p *peers) setCallback(onChange func([]string)) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.onChange = onChange
+}
+
+func (p *peers) update(candidate []string) {
+	sort.Strings(candidate)
+	candidate = slices.Compact(candidate)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.current != nil && slices.Equal(p.current, candidate) {
+		return
+	}
+	p.current = candidate
+	if p.onChange != nil {
+		p.onChange(candidate)
+	}
+}
+
+var _ resolver = (*staticResolver)(nil)
+
+type staticResolver struct {
+	peers
+	hostnames []string
+}
+
+func newStaticResolver(hostnames []string) *staticResolver {
+	return &staticResolver{hostnames: slices.Clone(hostnames)}
+}
+
+func (r *staticResolver) start(_ context.Context, onChange func([]string)) error {
+	r.setCallback(onChange)
+	r.update(slices.Clone(r.hostnames))
+	return nil
+}
+
+func (r *staticResolver) shutdown(context.Context) error {
+	r.setCallback(nil)
+	return nil
+}
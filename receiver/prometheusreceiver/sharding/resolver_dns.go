@@ -0,0 +1,111 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package sharding // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/prometheusreceiver/sharding"
+
+import (
+	"context"
+	"net"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+var _ resolver = (*dnsResolver)(nil)
+
+const (
+	defaultDNSInterval = 5 * time.Second
+	defaultDNSTimeout  = time.Second
+)
+
+type netResolver interface {
+	LookupIPAddr(ctx context.Context, host string) ([]net.IPAddr, error)
+}
+
+type dnsResolver struct {
+	peers
+	logger *zap.Logger
+
+	hostname string
+	resolver netResolver
+	interval time.Duration
+	timeout  time.Duration
+
+	stopCh     chan struct{}
+	shutdownWg sync.WaitGroup
+}
+
+func newDNSResolver(logger *zap.Logger, cfg *DNSResolverConfig) *dnsResolver {
+	interval := cfg.Interval
+	if interval == 0 {
+		interval = defaultDNSInterval
+	}
+	timeout := cfg.Timeout
+	if timeout == 0 {
+		timeout = defaultDNSTimeout
+	}
+
+	return &dnsResolver{
+		logger:   logger,
+		hostname: cfg.Hostname,
+		resolver: &net.Resolver{},
+		interval: interval,
+		timeout:  timeout,
+		stopCh:   make(chan struct{}),
+	}
+}
+
+func (r *dnsResolver) start(ctx context.Context, onChange func([]string)) error {
+	r.setCallback(onChange)
+
+	resolveCtx, cancel := context.WithTimeout(ctx, r.timeout)
+	if err := r.resolve(resolveCtx); err != nil {
+		r.logger.Warn("failed to resolve the sharding peers", zap.Error(err))
+	}
+	cancel()
+
+	r.shutdownWg.Add(1)
+	go r.periodicallyResolve()
+	return nil
+}
+
+func (r *dnsResolver) shutdown(context.Context) error {
+	r.setCallback(nil)
+	close(r.stopCh)
+	r.shutdownWg.Wait()
+	return nil
+}
+
+func (r *dnsResolver) periodicallyResolve() {
+	defer r.shutdownWg.Done()
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			ctx, cancel := context.WithTimeout(context.Background(), r.timeout)
+			if err := r.resolve(ctx); err != nil {
+				r.logger.Warn("failed to resolve the sharding peers", zap.Error(err))
+			}
+			cancel()
+		case <-r.stopCh:
+			return
+		}
+	}
+}
+
+func (r *dnsResolver) resolve(ctx context.Context) error {
+	addrs, err := r.resolver.LookupIPAddr(ctx, r.hostname)
+	if err != nil {
+		return err
+	}
+
+	resolved := make([]string, len(addrs))
+	for i, addr := range addrs {
+		resolved[i] = addr.IP.String()
+	}
+	r.update(resolved)
+	return nil
+}
@@ -0,0 +1,68 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package sharding
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type mockDNSResolver struct {
+	mu    sync.Mutex
+	addrs []net.IPAddr
+	err   error
+}
+
+func (m *mockDNSResolver) set(addrs []net.IPAddr, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.addrs, m.err = addrs, err
+}
+
+func (m *mockDNSResolver) LookupIPAddr(context.Context, string) ([]net.IPAddr, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.addrs, m.err
+}
+
+func TestDNSResolver(t *testing.T) {
+	mock := &mockDNSResolver{}
+	mock.set([]net.IPAddr{{IP: net.IPv4(10, 0, 0, 2)}, {IP: net.IPv4(10, 0, 0, 1)}}, nil)
+
+	res := newDNSResolver(zap.NewNop(), &DNSResolverConfig{Hostname: "otelcol-headless", Interval: 10 * time.Millisecond})
+	res.resolver = mock
+
+	changes := make(chan []string, 10)
+	require.NoError(t, res.start(context.Background(), func(peers []string) { changes <- peers }))
+	defer func() { assert.NoError(t, res.shutdown(context.Background())) }()
+
+	assert.Equal(t, []string{"10.0.0.1", "10.0.0.2"}, <-changes)
+
+	// failures keep the last known peers
+	mock.set(nil, errors.New("lookup failed"))
+	time.Sleep(50 * time.Millisecond)
+	assert.Empty(t, changes)
+
+	mock.set([]net.IPAddr{{IP: net.IPv4(10, 0, 0, 3)}}, nil)
+	select {
+	case peers := <-changes:
+		assert.Equal(t, []string{"10.0.0.3"}, peers)
+	case <-time.After(5 * time.Second):
+		t.Fatal("peers change not notified")
+	}
+}
+
+func TestDNSResolverDefaults(t *testing.T) {
+	res := newDNSResolver(zap.NewNop(), &DNSResolverConfig{Hostname: "otelcol-headless"})
+	assert.Equal(t, defaultDNSInterval, res.interval)
+	assert.Equal(t, defaultDNSTimeout, res.timeout)
+}
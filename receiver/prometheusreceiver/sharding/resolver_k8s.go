@@ -0,0 +1,151 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package sharding // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/prometheusreceiver/sharding"
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+)
+
+var _ resolver = (*k8sResolver)(nil)
+
+const (
+	defaultListWatchTimeout = time.Second
+	inClusterNamespacePath  = "/var/run/secrets/kubernetes.io/serviceaccount/namespace"
+)
+
+type k8sResolver struct {
+	peers
+	logger *zap.Logger
+
+	svcName     string
+	svcNs       string
+	returnNames bool
+	listWatcher cache.ListerWatcher
+	informer    cache.SharedInformer
+
+	stopCh chan struct{}
+}
+
+func newInClusterClient() (kubernetes.Interface, error) {
+	cfg, err := rest.InClusterConfig()
+	if err != nil {
+		return nil, err
+	}
+	return kubernetes.NewForConfig(cfg)
+}
+
+func newK8sResolver(clt kubernetes.Interface, logger *zap.Logger, cfg *K8sResolverConfig) *k8sResolver {
+	timeout := cfg.Timeout
+	if timeout == 0 {
+		timeout = defaultListWatchTimeout
+	}
+
+	name, namespace, found := strings.Cut(cfg.Service, ".")
+	if !found {
+		namespace = "default"
+		if ns, err := getInClusterNamespace(); err == nil {
+			namespace = ns
+		} else {
+			logger.Warn(`could not determine the namespace of the sharding service, will use "default" as the namespace`, zap.Error(err))
+		}
+	}
+
+	selector := fmt.Sprintf("metadata.name=%s", name)
+	timeoutSeconds := int64(timeout.Seconds())
+	listWatcher := &cache.ListWatch{
+		ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+			options.FieldSelector = selector
+			options.TimeoutSeconds = &timeoutSeconds
+			return clt.CoreV1().Endpoints(namespace).List(context.Background(), options)
+		},
+		WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+			options.FieldSelector = selector
+			options.TimeoutSeconds = &timeoutSeconds
+			return clt.CoreV1().Endpoints(namespace).Watch(context.Background(), options)
+		},
+	}
+
+	return &k8sResolver{
+		logger:      logger,
+		svcName:     name,
+		svcNs:       namespace,
+		returnNames: cfg.ReturnHostnames,
+		listWatcher: listWatcher,
+		stopCh:      make(chan struct{}),
+	}
+}
+
+func (r *k8sResolver) start(_ context.Context, onChange func([]string)) error {
+	r.setCallback(onChange)
+
+	r.informer = cache.NewSharedInformer(r.listWatcher, &corev1.Endpoints{}, 0)
+	if _, err := r.informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(any) { r.resolve() },
+		UpdateFunc: func(any, any) { r.resolve() },
+		DeleteFunc: func(any) { r.resolve() },
+	}); err != nil {
+		return err
+	}
+	go r.informer.Run(r.stopCh)
+	if !cache.WaitForCacheSync(r.stopCh, r.informer.HasSynced) {
+		return errors.New("sharding endpoints informer not synced")
+	}
+	// the service may not have any endpoints yet
+	r.resolve()
+
+	r.logger.Debug("K8s sharding resolver started", zap.String("service", r.svcName), zap.String("namespace", r.svcNs))
+	return nil
+}
+
+func (r *k8sResolver) shutdown(context.Context) error {
+	r.setCallback(nil)
+	close(r.stopCh)
+	return nil
+}
+
+func (r *k8sResolver) resolve() {
+	resolved := []string{}
+	for _, obj := range r.informer.GetStore().List() {
+		eps, ok := obj.(*corev1.Endpoints)
+		if !ok {
+			continue
+		}
+		for _, subset := range eps.Subsets {
+			for _, addr := range subset.Addresses {
+				if !r.returnNames {
+					resolved = append(resolved, addr.IP)
+					continue
+				}
+				if addr.Hostname == "" {
+					r.logger.Warn("Endpoints object missing hostnames", zap.String("ip", addr.IP))
+					continue
+				}
+				resolved = append(resolved, addr.Hostname)
+			}
+		}
+	}
+	r.update(resolved)
+}
+
+func getInClusterNamespace() (string, error) {
+	namespace, err := os.ReadFile(inClusterNamespacePath)
+	if err != nil {
+		return "", fmt.Errorf("error reading namespace file: %w", err)
+	}
+	return string(namespace), nil
+}
@@ -0,0 +1,76 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package sharding
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func newEndpoints(addrs ...corev1.EndpointAddress) *corev1.Endpoints {
+	return &corev1.Endpoints{
+		ObjectMeta: metav1.ObjectMeta{Name: "otelcol-headless", Namespace: "observability"},
+		Subsets:    []corev1.EndpointSubset{{Addresses: addrs}},
+	}
+}
+
+func TestK8sResolver(t *testing.T) {
+	tests := []struct {
+		name            string
+		returnHostnames bool
+		initial         []string
+		updated         []string
+	}{
+		{
+			name:    "ips",
+			initial: []string{"10.0.0.1", "10.0.0.2"},
+			updated: []string{"10.0.0.1", "10.0.0.3"},
+		},
+		{
+			name:            "hostnames",
+			returnHostnames: true,
+			initial:         []string{"otelcol-0", "otelcol-1"},
+			updated:         []string{"otelcol-0", "otelcol-2"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clt := fake.NewSimpleClientset(newEndpoints(
+				corev1.EndpointAddress{IP: "10.0.0.1", Hostname: "otelcol-0"},
+				corev1.EndpointAddress{IP: "10.0.0.2", Hostname: "otelcol-1"},
+			))
+			res := newK8sResolver(clt, zap.NewNop(), &K8sResolverConfig{
+				Service:         "otelcol-headless.observability",
+				ReturnHostnames: tt.returnHostnames,
+			})
+
+			changes := make(chan []string, 10)
+			require.NoError(t, res.start(context.Background(), func(peers []string) { changes <- peers }))
+			defer func() { assert.NoError(t, res.shutdown(context.Background())) }()
+			assert.Equal(t, tt.initial, <-changes)
+
+			_, err := clt.CoreV1().Endpoints("observability").Update(context.Background(), newEndpoints(
+				corev1.EndpointAddress{IP: "10.0.0.1", Hostname: "otelcol-0"},
+				corev1.EndpointAddress{IP: "10.0.0.3", Hostname: "otelcol-2"},
+			), metav1.UpdateOptions{})
+			require.NoError(t, err)
+
+			select {
+			case peers := <-changes:
+				assert.Equal(t, tt.updated, peers)
+			case <-time.After(5 * time.Second):
+				t.Fatal("peers change not notified")
+			}
+		})
+	}
+}
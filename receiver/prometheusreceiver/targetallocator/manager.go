@@ -12,6 +12,7 @@ import (
 	"net/http"
 	"net/url"
 	"os"
+	"slices"
 	"sort"
 	"time"
 
@@ -173,6 +174,15 @@ func (m *Manager) applyCfg() error {
 			scrapeConfig.ScrapeClassicHistograms = true
 		}
 	}
+	if m.enableNativeHistograms {
+		// Native histograms can only be scraped with the protobuf format, prefer it unless the
+		// scrape protocols were configured.
+		for _, scrapeConfig := range m.promCfg.ScrapeConfigs {
+			if slices.Equal(scrapeConfig.ScrapeProtocols, promconfig.DefaultScrapeProtocols) {
+				scrapeConfig.ScrapeProtocols = promconfig.DefaultProtoFirstScrapeProtocols
+			}
+		}
+	}
 
 	if err := m.scrapeManager.ApplyConfig(m.promCfg); err != nil {
 		return err
@@ -11,6 +11,10 @@ import (
 // Config holds common fields and embedded protocol-specific configurations
 type Config struct {
 	confighttp.ServerConfig `mapstructure:",squash"`
+
+	// SenderHeader is the header identifying the sender of the Remote Write 1.0 requests, whose metadata is
+	// kept for each sender. The senders are identified by their host when it isn't set or missing.
+	SenderHeader string `mapstructure:"sender_header"`
 }
 
 var _ component.Config = (*Config)(nil)
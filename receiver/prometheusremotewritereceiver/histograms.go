@@ -0,0 +1,371 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package prometheusremotewritereceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/prometheusremotewritereceiver"
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/cespare/xxhash/v2"
+	"github.com/prometheus/prometheus/model/histogram"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/model/value"
+	writev2 "github.com/prometheus/prometheus/prompb/io/prometheus/write/v2"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+)
+
+const (
+	bucketSuffix = "_bucket"
+	sumSuffix    = "_sum"
+	countSuffix  = "_count"
+
+	quantileLabel = "quantile"
+
+	traceIDKey = "trace_id"
+	spanIDKey  = "span_id"
+)
+
+// classicHistogram is a histogram datapoint being assembled from the _bucket, _sum and _count series
+// of a classic Prometheus histogram.
+type classicHistogram struct {
+	dp pmetric.HistogramDataPoint
+	// buckets holds the cumulative count of each bucket by upper bound.
+	buckets  map[float64]float64
+	count    float64
+	hasCount bool
+}
+
+// classicSummary is a summary datapoint being assembled from the quantile, _sum and _count series
+// of a Prometheus summary.
+type classicSummary struct {
+	dp        pmetric.SummaryDataPoint
+	quantiles map[float64]float64
+}
+
+// addHistogramDatapoints adds the samples of a series of a classic histogram to the datapoints of
+// its histogram, identified by the labels of the series other than le and the timestamp.
+func (t *v2Translation) addHistogramDatapoints(ls labels.Labels, ts writev2.TimeSeries) error {
+	name := ls.Get(labels.MetricName)
+
+	var (
+		family string
+		le     float64
+	)
+	switch {
+	case strings.HasSuffix(name, bucketSuffix):
+		family = strings.TrimSuffix(name, bucketSuffix)
+		var err error
+		if le, err = parseFloatLabel(ls, labels.BucketLabel); err != nil {
+			return err
+		}
+	case strings.HasSuffix(name, sumSuffix):
+		family = strings.TrimSuffix(name, sumSuffix)
+	case strings.HasSuffix(name, countSuffix):
+		family = strings.TrimSuffix(name, countSuffix)
+	default:
+		return fmt.Errorf("classic histogram series must have one of the %q, %q or %q suffixes", bucketSuffix, sumSuffix, countSuffix)
+	}
+
+	m, metricKey := t.metric(ls, ts, family, pmetric.MetricTypeHistogram)
+	var labelsHash uint64
+	labelsHash, t.hashBuf = ls.HashWithoutLabels(t.hashBuf, labels.BucketLabel)
+
+	var latest *classicHistogram
+	var latestTimestamp int64
+	for i, sample := range ts.Samples {
+		key := datapointKey(metricKey, labelsHash, sample.Timestamp)
+		h, ok := t.histograms[key]
+		if !ok {
+			h = &classicHistogram{dp: m.Histogram().DataPoints().AppendEmpty(), buckets: make(map[float64]float64)}
+			putAttributes(h.dp.Attributes(), ls, labels.BucketLabel)
+			h.dp.SetTimestamp(timestampFromMs(sample.Timestamp))
+			t.histograms[key] = h
+		}
+		if ts.CreatedTimestamp != 0 {
+			h.dp.SetStartTimestamp(timestampFromMs(ts.CreatedTimestamp))
+		}
+		if value.IsStaleNaN(sample.Value) {
+			h.dp.SetFlags(pmetric.DefaultDataPointFlags.WithNoRecordedValue(true))
+		}
+
+		switch {
+		case strings.HasSuffix(name, bucketSuffix):
+			h.buckets[le] = sample.Value
+		case strings.HasSuffix(name, sumSuffix):
+			h.dp.SetSum(sample.Value)
+		default:
+			h.count, h.hasCount = sample.Value, true
+		}
+
+		if i == 0 || sample.Timestamp >= latestTimestamp {
+			latest, latestTimestamp = h, sample.Timestamp
+		}
+	}
+	t.stats.Samples += len(ts.Samples)
+
+	if latest != nil {
+		t.addExemplars(latest.dp.Exemplars(), ts.Exemplars)
+	}
+	return nil
+}
+
+// finish converts the cumulative bucket counts to the bucket counts of the datapoint.
+func (h *classicHistogram) finish() {
+	if len(h.buckets) == 0 {
+		h.dp.SetCount(roundCount(h.count))
+		return
+	}
+
+	bounds := make([]float64, 0, len(h.buckets))
+	for le := range h.buckets {
+		if !math.IsInf(le, 1) {
+			bounds = append(bounds, le)
+		}
+	}
+	sort.Float64s(bounds)
+
+	counts := make([]uint64, len(bounds)+1)
+	var cumulative float64
+	for i, bound := range bounds {
+		count := h.buckets[bound]
+		counts[i] = roundCount(count - cumulative)
+		cumulative = math.Max(cumulative, count)
+	}
+
+	total := cumulative
+	if inf, ok := h.buckets[math.Inf(1)]; ok {
+		total = inf
+	}
+	if h.hasCount {
+		total = h.count
+	}
+	counts[len(bounds)] = roundCount(total - cumulative)
+
+	h.dp.ExplicitBounds().FromRaw(bounds)
+	h.dp.BucketCounts().FromRaw(counts)
+	h.dp.SetCount(roundCount(total))
+}
+
+// addSummaryDatapoints adds the samples of a series of a summary to the datapoints of its summary,
+// identified by the labels of the series other than quantile and the timestamp.
+func (t *v2Translation) addSummaryDatapoints(ls labels.Labels, ts writev2.TimeSeries) error {
+	name := ls.Get(labels.MetricName)
+
+	var (
+		family   string
+		quantile float64
+	)
+	switch {
+	case ls.Has(quantileLabel):
+		family = name
+		var err error
+		if quantile, err = parseFloatLabel(ls, quantileLabel); err != nil {
+			return err
+		}
+	case strings.HasSuffix(name, sumSuffix):
+		family = strings.TrimSuffix(name, sumSuffix)
+	case strings.HasSuffix(name, countSuffix):
+		family = strings.TrimSuffix(name, countSuffix)
+	default:
+		return fmt.Errorf("summary series must have a %q label or one of the %q or %q suffixes", quantileLabel, sumSuffix, countSuffix)
+	}
+
+	m, metricKey := t.metric(ls, ts, family, pmetric.MetricTypeSummary)
+	var labelsHash uint64
+	labelsHash, t.hashBuf = ls.HashWithoutLabels(t.hashBuf, quantileLabel)
+
+	for _, sample := range ts.Samples {
+		key := datapointKey(metricKey, labelsHash, sample.Timestamp)
+		s, ok := t.summaries[key]
+		if !ok {
+			s = &classicSummary{dp: m.Summary().DataPoints().AppendEmpty(), quantiles: make(map[float64]float64)}
+			putAttributes(s.dp.Attributes(), ls, quantileLabel)
+			s.dp.SetTimestamp(timestampFromMs(sample.Timestamp))
+			t.summaries[key] = s
+		}
+		if ts.CreatedTimestamp != 0 {
+			s.dp.SetStartTimestamp(timestampFromMs(ts.CreatedTimestamp))
+		}
+		if value.IsStaleNaN(sample.Value) {
+			s.dp.SetFlags(pmetric.DefaultDataPointFlags.WithNoRecordedValue(true))
+		}
+
+		switch {
+		case ls.Has(quantileLabel):
+			s.quantiles[quantile] = sample.Value
+		case strings.HasSuffix(name, sumSuffix):
+			s.dp.SetSum(sample.Value)
+		default:
+			s.dp.SetCount(roundCount(sample.Value))
+		}
+	}
+	t.stats.Samples += len(ts.Samples)
+	// OTLP summaries have no exemplars.
+	return nil
+}
+
+// finish adds the quantiles to the datapoint in increasing order.
+func (s *classicSummary) finish() {
+	quantiles := make([]float64, 0, len(s.quantiles))
+	for q := range s.quantiles {
+		quantiles = append(quantiles, q)
+	}
+	sort.Float64s(quantiles)
+
+	for _, q := range quantiles {
+		qv := s.dp.QuantileValues().AppendEmpty()
+		qv.SetQuantile(q)
+		qv.SetValue(s.quantiles[q])
+	}
+}
+
+// addNativeHistogramDatapoints translates native histograms to exponential histograms. Native
+// histograms with custom buckets are translated to explicit bucket histograms.
+func (t *v2Translation) addNativeHistogramDatapoints(ls labels.Labels, ts writev2.TimeSeries) error {
+	name := ls.Get(labels.MetricName)
+
+	var (
+		latest          pmetric.ExemplarSlice
+		latestTimestamp int64
+	)
+	for i, h := range ts.Histograms {
+		fh := h.ToFloatHistogram()
+		if err := fh.Validate(); err != nil {
+			return fmt.Errorf("invalid native histogram: %w", err)
+		}
+
+		var exemplars pmetric.ExemplarSlice
+		if fh.UsesCustomBuckets() {
+			m, _ := t.metric(ls, ts, name, pmetric.MetricTypeHistogram)
+			dp := m.Histogram().DataPoints().AppendEmpty()
+			putAttributes(dp.Attributes(), ls)
+			dp.SetTimestamp(timestampFromMs(h.Timestamp))
+			if ts.CreatedTimestamp != 0 {
+				dp.SetStartTimestamp(timestampFromMs(ts.CreatedTimestamp))
+			}
+			if value.IsStaleNaN(fh.Sum) {
+				dp.SetFlags(pmetric.DefaultDataPointFlags.WithNoRecordedValue(true))
+			}
+			dp.SetCount(roundCount(fh.Count))
+			dp.SetSum(fh.Sum)
+			dp.ExplicitBounds().FromRaw(fh.CustomValues)
+			dp.BucketCounts().FromRaw(customBucketCounts(fh))
+			exemplars = dp.Exemplars()
+		} else {
+			if fh.Schema < histogram.ExponentialSchemaMin || fh.Schema > histogram.ExponentialSchemaMax {
+				return fmt.Errorf("unsupported native histogram schema %d", fh.Schema)
+			}
+			m, _ := t.metric(ls, ts, name, pmetric.MetricTypeExponentialHistogram)
+			dp := m.ExponentialHistogram().DataPoints().AppendEmpty()
+			putAttributes(dp.Attributes(), ls)
+			dp.SetTimestamp(timestampFromMs(h.Timestamp))
+			if ts.CreatedTimestamp != 0 {
+				dp.SetStartTimestamp(timestampFromMs(ts.CreatedTimestamp))
+			}
+			if value.IsStaleNaN(fh.Sum) {
+				dp.SetFlags(pmetric.DefaultDataPointFlags.WithNoRecordedValue(true))
+			}
+			dp.SetScale(fh.Schema)
+			dp.SetCount(roundCount(fh.Count))
+			dp.SetSum(fh.Sum)
+			dp.SetZeroThreshold(fh.ZeroThreshold)
+			dp.SetZeroCount(roundCount(fh.ZeroCount))
+			convertBuckets(fh.PositiveSpans, fh.PositiveBuckets, dp.Positive())
+			convertBuckets(fh.NegativeSpans, fh.NegativeBuckets, dp.Negative())
+			exemplars = dp.Exemplars()
+		}
+
+		if i == 0 || h.Timestamp >= latestTimestamp {
+			latest, latestTimestamp = exemplars, h.Timestamp
+		}
+	}
+	t.stats.Histograms += len(ts.Histograms)
+
+	if len(ts.Histograms) > 0 {
+		t.addExemplars(latest, ts.Exemplars)
+	}
+	return nil
+}
+
+// convertBuckets converts the sparse buckets of a native histogram to the dense buckets of an
+// exponential histogram.
+func convertBuckets(spans []histogram.Span, counts []float64, dest pmetric.ExponentialHistogramDataPointBuckets) {
+	if len(spans) == 0 {
+		return
+	}
+
+	// The bucket with index i of a native histogram covers the range (base^(i-1), base^i] while
+	// the one of an exponential histogram covers (base^i, base^(i+1)].
+	dest.SetOffset(spans[0].Offset - 1)
+
+	var c int
+	for i, span := range spans {
+		if i > 0 {
+			for j := int32(0); j < span.Offset; j++ {
+				dest.BucketCounts().Append(0)
+			}
+		}
+		for j := uint32(0); j < span.Length; j++ {
+			dest.BucketCounts().Append(roundCount(counts[c]))
+			c++
+		}
+	}
+}
+
+// customBucketCounts returns the bucket counts of a native histogram with custom buckets, the
+// bucket with index i has the custom value i as upper bound and the last one is the +Inf bucket.
+func customBucketCounts(fh *histogram.FloatHistogram) []uint64 {
+	counts := make([]uint64, len(fh.CustomValues)+1)
+
+	var idx int32
+	var c int
+	for i, span := range fh.PositiveSpans {
+		if i == 0 {
+			idx = span.Offset
+		} else {
+			idx += span.Offset
+		}
+		for j := uint32(0); j < span.Length; j++ {
+			if idx >= 0 && int(idx) < len(counts) {
+				counts[idx] = roundCount(fh.PositiveBuckets[c])
+			}
+			idx++
+			c++
+		}
+	}
+	return counts
+}
+
+// datapointKey identifies the datapoint of a metric with the given labels and timestamp.
+func datapointKey(metricKey, labelsHash uint64, timestamp int64) uint64 {
+	var b [24]byte
+	binary.LittleEndian.PutUint64(b[0:], metricKey)
+	binary.LittleEndian.PutUint64(b[8:], labelsHash)
+	binary.LittleEndian.PutUint64(b[16:], uint64(timestamp))
+	return xxhash.Sum64(b[:])
+}
+
+func parseFloatLabel(ls labels.Labels, name string) (float64, error) {
+	v := ls.Get(name)
+	if v == "" {
+		return 0, fmt.Errorf("missing %q label", name)
+	}
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %q label %q: %w", name, v, err)
+	}
+	return f, nil
+}
+
+// roundCount converts a count to an integer, negative and NaN counts are converted to 0.
+func roundCount(count float64) uint64 {
+	if !(count > 0) {
+		return 0
+	}
+	return uint64(math.Round(count))
+}
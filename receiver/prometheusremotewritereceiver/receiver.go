@@ -5,17 +5,24 @@ package prometheusremotewritereceiver // import "github.com/open-telemetry/opent
 
 import (
 	"context"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
+	"slices"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/cespare/xxhash/v2"
 	"github.com/gogo/protobuf/proto"
+	"github.com/hashicorp/golang-lru/v2/simplelru"
 	promconfig "github.com/prometheus/prometheus/config"
 	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/model/value"
+	"github.com/prometheus/prometheus/prompb"
 	writev2 "github.com/prometheus/prometheus/prompb/io/prometheus/write/v2"
 	promremote "github.com/prometheus/prometheus/storage/remote"
 	"go.opentelemetry.io/collector/component"
@@ -24,14 +31,20 @@ import (
 	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/pmetric"
 	"go.opentelemetry.io/collector/receiver"
+	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 )
 
 func newRemoteWriteReceiver(settings receiver.Settings, cfg *Config, nextConsumer consumer.Metrics) (receiver.Metrics, error) {
+	metadataCache, err := simplelru.NewLRU[metadataKey, cachedMetadata](metadataCacheSize, nil)
+	if err != nil {
+		return nil, err
+	}
 	return &prometheusRemoteWriteReceiver{
-		settings:     settings,
-		nextConsumer: nextConsumer,
-		config:       cfg,
+		settings:      settings,
+		nextConsumer:  nextConsumer,
+		config:        cfg,
+		metadataCache: metadataCache,
 		server: &http.Server{
 			ReadTimeout: 60 * time.Second,
 		},
@@ -44,6 +57,31 @@ type prometheusRemoteWriteReceiver struct {
 
 	config *Config
 	server *http.Server
+	wg     sync.WaitGroup
+
+	// metadataCache holds the metadata of the metric families sent by v1 senders.
+	metadataMtx   sync.Mutex
+	metadataCache *simplelru.LRU[metadataKey, cachedMetadata]
+}
+
+const (
+	// metadataCacheSize is the maximum number of metric families whose metadata is cached.
+	metadataCacheSize = 10000
+	// metadataCacheTTL is the time after which the metadata of a metric family is evicted if it was
+	// not sent again. Prometheus sends the metadata every minute by default.
+	metadataCacheTTL = 10 * time.Minute
+)
+
+// metadataKey identifies the metadata of a metric family sent by a v1 sender, as the families of
+// different senders may have different metadata.
+type metadataKey struct {
+	sender string
+	family string
+}
+
+type cachedMetadata struct {
+	prompb.MetricMetadata
+	updated time.Time
 }
 
 func (prw *prometheusRemoteWriteReceiver) Start(ctx context.Context, host component.Host) error {
@@ -60,7 +98,9 @@ func (prw *prometheusRemoteWriteReceiver) Start(ctx context.Context, host compon
 		return fmt.Errorf("failed to create prometheus remote-write listener: %w", err)
 	}
 
+	prw.wg.Add(1)
 	go func() {
+		defer prw.wg.Done()
 		if err := prw.server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
 			componentstatus.ReportStatus(host, componentstatus.NewFatalErrorEvent(fmt.Errorf("error starting prometheus remote-write receiver: %w", err)))
 		}
@@ -72,7 +112,10 @@ func (prw *prometheusRemoteWriteReceiver) Shutdown(ctx context.Context) error {
 	if prw.server == nil {
 		return nil
 	}
-	return prw.server.Shutdown(ctx)
+	err := prw.server.Shutdown(ctx)
+	// Wait for Serve to return, it closes the listener.
+	prw.wg.Wait()
+	return err
 }
 
 func (prw *prometheusRemoteWriteReceiver) handlePRW(w http.ResponseWriter, req *http.Request) {
@@ -89,12 +132,6 @@ func (prw *prometheusRemoteWriteReceiver) handlePRW(w http.ResponseWriter, req *
 		http.Error(w, err.Error(), http.StatusUnsupportedMediaType)
 		return
 	}
-	if msgType != promconfig.RemoteWriteProtoMsgV2 {
-		prw.settings.Logger.Warn("message received with unsupported proto version, rejecting")
-		http.Error(w, "Unsupported proto version", http.StatusUnsupportedMediaType)
-		return
-	}
-
 	// After parsing the content-type header, the next step would be to handle content-encoding.
 	// Luckly confighttp's Server has middleware that already decompress the request body for us.
 
@@ -105,15 +142,42 @@ func (prw *prometheusRemoteWriteReceiver) handlePRW(w http.ResponseWriter, req *
 		return
 	}
 
-	var prw2Req writev2.Request
-	if err = proto.Unmarshal(body, &prw2Req); err != nil {
-		prw.settings.Logger.Warn("Error decoding remote write request", zapcore.Field{Key: "error", Type: zapcore.ErrorType, Interface: err})
-		http.Error(w, err.Error(), http.StatusBadRequest)
-		return
+	var (
+		m     pmetric.Metrics
+		stats promremote.WriteResponseStats
+	)
+	switch msgType {
+	case promconfig.RemoteWriteProtoMsgV1:
+		var prw1Req prompb.WriteRequest
+		if err = proto.Unmarshal(body, &prw1Req); err != nil {
+			prw.settings.Logger.Warn("Error decoding remote write request", zapcore.Field{Key: "error", Type: zapcore.ErrorType, Interface: err})
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		m, err = prw.translateV1(req.Context(), prw.sender(req), &prw1Req)
+	default:
+		var prw2Req writev2.Request
+		if err = proto.Unmarshal(body, &prw2Req); err != nil {
+			prw.settings.Logger.Warn("Error decoding remote write request", zapcore.Field{Key: "error", Type: zapcore.ErrorType, Interface: err})
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		m, stats, err = prw.translateV2(req.Context(), &prw2Req)
+	}
+
+	if m.DataPointCount() > 0 {
+		if consumeErr := prw.nextConsumer.ConsumeMetrics(req.Context(), m); consumeErr != nil {
+			prw.settings.Logger.Warn("Error consuming remote write request", zapcore.Field{Key: "error", Type: zapcore.ErrorType, Interface: consumeErr})
+			http.Error(w, consumeErr.Error(), http.StatusInternalServerError)
+			return
+		}
 	}
 
-	_, stats, err := prw.translateV2(req.Context(), &prw2Req)
-	stats.SetHeaders(w)
+	// The valid samples have been written even if some were invalid, the stats tell the sender
+	// how many of them. They are only part of the v2 protocol.
+	if msgType == promconfig.RemoteWriteProtoMsgV2 {
+		stats.SetHeaders(w)
+	}
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest) // Following instructions at https://prometheus.io/docs/specs/remote_write_spec_2_0/#invalid-samples
 		return
@@ -152,24 +216,166 @@ func (prw *prometheusRemoteWriteReceiver) parseProto(contentType string) (promco
 	return promconfig.RemoteWriteProtoMsgV1, nil
 }
 
+// sender identifies the sender of the request by the configured header, or by its host when the
+// header isn't configured or missing.
+func (prw *prometheusRemoteWriteReceiver) sender(req *http.Request) string {
+	if prw.config.SenderHeader != "" {
+		if sender := req.Header.Get(prw.config.SenderHeader); sender != "" {
+			return sender
+		}
+	}
+	return remoteHost(req)
+}
+
+// remoteHost returns the host of the sender of the request, without its port.
+func remoteHost(req *http.Request) string {
+	host, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		return req.RemoteAddr
+	}
+	return host
+}
+
+// translateV1 translates a v1 remote-write request of the given sender into OTLP metrics.
+//
+// v1 time series don't carry their metadata, Prometheus sends the metadata of the metric families
+// periodically instead, in the same or separate requests. The type of the time series is inferred from
+// the metadata received so far from the sender, time series of unknown type are translated to gauges.
+// The request is then converted to a v2 request to be translated.
+func (prw *prometheusRemoteWriteReceiver) translateV1(ctx context.Context, sender string, req *prompb.WriteRequest) (pmetric.Metrics, error) {
+	prw.updateMetadata(sender, req.Metadata)
+
+	var (
+		symbols       = writev2.NewSymbolTable()
+		labelsBuilder = labels.NewScratchBuilder(0)
+		v2Req         = &writev2.Request{Timeseries: make([]writev2.TimeSeries, 0, len(req.Timeseries))}
+	)
+	for _, ts := range req.Timeseries {
+		ls := ts.ToLabels(&labelsBuilder, nil)
+		md := prw.lookupMetadata(sender, ls.Get(labels.MetricName))
+		if len(ts.Histograms) > 0 {
+			md.Type = prompb.MetricMetadata_HISTOGRAM
+		}
+
+		v2ts := writev2.TimeSeries{
+			LabelsRefs: symbols.SymbolizeLabels(ls, nil),
+			Metadata: writev2.Metadata{
+				// Both enums have the same values.
+				Type:    writev2.Metadata_MetricType(md.Type),
+				HelpRef: symbols.Symbolize(md.Help),
+				UnitRef: symbols.Symbolize(md.Unit),
+			},
+			Samples:    make([]writev2.Sample, 0, len(ts.Samples)),
+			Histograms: make([]writev2.Histogram, 0, len(ts.Histograms)),
+			Exemplars:  make([]writev2.Exemplar, 0, len(ts.Exemplars)),
+		}
+		for _, s := range ts.Samples {
+			v2ts.Samples = append(v2ts.Samples, writev2.Sample{Value: s.Value, Timestamp: s.Timestamp})
+		}
+		for _, h := range ts.Histograms {
+			if h.IsFloatHistogram() {
+				v2ts.Histograms = append(v2ts.Histograms, writev2.FromFloatHistogram(h.Timestamp, h.ToFloatHistogram()))
+			} else {
+				v2ts.Histograms = append(v2ts.Histograms, writev2.FromIntHistogram(h.Timestamp, h.ToIntHistogram()))
+			}
+		}
+		for _, e := range ts.Exemplars {
+			labelsBuilder.Reset()
+			for _, l := range e.Labels {
+				labelsBuilder.Add(l.Name, l.Value)
+			}
+			labelsBuilder.Sort()
+			v2ts.Exemplars = append(v2ts.Exemplars, writev2.Exemplar{
+				LabelsRefs: symbols.SymbolizeLabels(labelsBuilder.Labels(), nil),
+				Value:      e.Value,
+				Timestamp:  e.Timestamp,
+			})
+		}
+		v2Req.Timeseries = append(v2Req.Timeseries, v2ts)
+	}
+	v2Req.Symbols = symbols.Symbols()
+
+	m, _, err := prw.translateV2(ctx, v2Req)
+	return m, err
+}
+
+// familySuffixes are the suffixes of the series of metric families, with the types of the families
+// which have series with them.
+var familySuffixes = []struct {
+	suffix string
+	types  []prompb.MetricMetadata_MetricType
+}{
+	{bucketSuffix, []prompb.MetricMetadata_MetricType{prompb.MetricMetadata_HISTOGRAM, prompb.MetricMetadata_GAUGEHISTOGRAM}},
+	{sumSuffix, []prompb.MetricMetadata_MetricType{prompb.MetricMetadata_HISTOGRAM, prompb.MetricMetadata_SUMMARY}},
+	{countSuffix, []prompb.MetricMetadata_MetricType{prompb.MetricMetadata_HISTOGRAM, prompb.MetricMetadata_SUMMARY}},
+	{"_total", []prompb.MetricMetadata_MetricType{prompb.MetricMetadata_COUNTER}},
+}
+
+func (prw *prometheusRemoteWriteReceiver) updateMetadata(sender string, metadata []prompb.MetricMetadata) {
+	if len(metadata) == 0 {
+		return
+	}
+
+	now := time.Now()
+	prw.metadataMtx.Lock()
+	defer prw.metadataMtx.Unlock()
+	for _, md := range metadata {
+		prw.metadataCache.Add(metadataKey{sender: sender, family: md.MetricFamilyName}, cachedMetadata{MetricMetadata: md, updated: now})
+	}
+}
+
+// lookupMetadata returns the metadata of the family of the series with the given name, sent by
+// the given sender.
+func (prw *prometheusRemoteWriteReceiver) lookupMetadata(sender, name string) prompb.MetricMetadata {
+	prw.metadataMtx.Lock()
+	defer prw.metadataMtx.Unlock()
+
+	if md, ok := prw.getMetadata(metadataKey{sender: sender, family: name}); ok {
+		return md
+	}
+	for _, fs := range familySuffixes {
+		if !strings.HasSuffix(name, fs.suffix) {
+			continue
+		}
+		if md, ok := prw.getMetadata(metadataKey{sender: sender, family: strings.TrimSuffix(name, fs.suffix)}); ok && slices.Contains(fs.types, md.Type) {
+			return md
+		}
+	}
+	return prompb.MetricMetadata{Type: prompb.MetricMetadata_UNKNOWN}
+}
+
+// getMetadata returns the cached metadata with the given key, evicting it if it expired. It must
+// be called holding metadataMtx.
+func (prw *prometheusRemoteWriteReceiver) getMetadata(key metadataKey) (prompb.MetricMetadata, bool) {
+	md, ok := prw.metadataCache.Get(key)
+	if !ok {
+		return prompb.MetricMetadata{}, false
+	}
+	if time.Since(md.updated) > metadataCacheTTL {
+		prw.metadataCache.Remove(key)
+		return prompb.MetricMetadata{}, false
+	}
+	return md.MetricMetadata, true
+}
+
 // translateV2 translates a v2 remote-write request into OTLP metrics.
-// translate is not feature complete.
-// nolint
+//
+// Series are grouped into resources by their job and instance labels, into scopes by their
+// otel_scope_name and otel_scope_version labels and into metrics by their name, type and unit.
+// Invalid series are skipped and reported in the returned error, the other series are translated.
 func (prw *prometheusRemoteWriteReceiver) translateV2(_ context.Context, req *writev2.Request) (pmetric.Metrics, promremote.WriteResponseStats, error) {
 	var (
 		badRequestErrors error
-		otelMetrics      = pmetric.NewMetrics()
-		labelsBuilder    = labels.NewScratchBuilder(0)
-		stats            = promremote.WriteResponseStats{}
-		// Prometheus Remote-Write can send multiple time series with the same labels in the same request.
-		// Instead of creating a whole new OTLP metric, we just append the new sample to the existing OTLP metric.
-		// This cache is called "intra" because in the future we'll have a "interRequestCache" to cache resourceAttributes
-		// between requests based on the metric "target_info".
-		intraRequestCache = make(map[uint64]pmetric.ResourceMetrics)
+		t                = newV2Translation(req.Symbols)
 	)
 
 	for _, ts := range req.Timeseries {
-		ls := ts.ToLabels(&labelsBuilder, req.Symbols)
+		if err := validateRefs(ts, len(req.Symbols)); err != nil {
+			badRequestErrors = errors.Join(badRequestErrors, err)
+			continue
+		}
+
+		ls := ts.ToLabels(&t.labelsBuilder, req.Symbols)
 
 		if !ls.Has(labels.MetricName) {
 			badRequestErrors = errors.Join(badRequestErrors, fmt.Errorf("missing metric name in labels"))
@@ -179,104 +385,294 @@ func (prw *prometheusRemoteWriteReceiver) translateV2(_ context.Context, req *wr
 			continue
 		}
 
-		var rm pmetric.ResourceMetrics
-		hashedLabels := xxhash.Sum64String(ls.Get("job") + string([]byte{'\xff'}) + ls.Get("instance"))
-		intraCacheEntry, ok := intraRequestCache[hashedLabels]
-		if ok {
-			// We found the same time series in the same request, so we should append to the same OTLP metric.
-			rm = intraCacheEntry
-		} else {
-			rm = otelMetrics.ResourceMetrics().AppendEmpty()
-			parseJobAndInstance(rm.Resource().Attributes(), ls.Get("job"), ls.Get("instance"))
-			intraRequestCache[hashedLabels] = rm
-		}
-
+		var err error
 		switch ts.Metadata.Type {
 		case writev2.Metadata_METRIC_TYPE_COUNTER:
-			addCounterDatapoints(rm, ls, ts)
-		case writev2.Metadata_METRIC_TYPE_GAUGE:
-			addGaugeDatapoints(rm, ls, ts)
+			err = checkNoHistograms(ts)
+			t.addCounterDatapoints(ls, ts)
+		case writev2.Metadata_METRIC_TYPE_GAUGE, writev2.Metadata_METRIC_TYPE_INFO, writev2.Metadata_METRIC_TYPE_STATESET:
+			// Info and stateset metrics are series of gauges whose labels carry the information or state.
+			err = checkNoHistograms(ts)
+			t.addGaugeDatapoints(ls, ts)
+		case writev2.Metadata_METRIC_TYPE_UNSPECIFIED:
+			// As per the specification, metrics of unknown type are translated to gauges. Native
+			// histograms can't be gauges, they are translated as the ones of histogram series.
+			if len(ts.Histograms) > 0 {
+				err = t.addNativeHistogramDatapoints(ls, ts)
+			}
+			if len(ts.Samples) > 0 {
+				t.addGaugeDatapoints(ls, ts)
+			}
 		case writev2.Metadata_METRIC_TYPE_SUMMARY:
-			addSummaryDatapoints(rm, ls, ts)
+			err = errors.Join(checkNoHistograms(ts), t.addSummaryDatapoints(ls, ts))
 		case writev2.Metadata_METRIC_TYPE_HISTOGRAM:
-			addHistogramDatapoints(rm, ls, ts)
+			if len(ts.Histograms) > 0 {
+				err = t.addNativeHistogramDatapoints(ls, ts)
+			} else {
+				err = t.addHistogramDatapoints(ls, ts)
+			}
+		case writev2.Metadata_METRIC_TYPE_GAUGEHISTOGRAM:
+			// Gauge histograms have no OTLP equivalent yet, they are dropped as by the Prometheus
+			// receiver. They aren't counted in the stats, which tell the sender they weren't written.
+			prw.settings.Logger.Debug("Dropping gauge histogram series", zap.String("metric", ls.Get(labels.MetricName)))
 		default:
-			badRequestErrors = errors.Join(badRequestErrors, fmt.Errorf("unsupported metric type %q for metric %q", ts.Metadata.Type, ls.Get(labels.MetricName)))
+			err = fmt.Errorf("unsupported metric type %q", ts.Metadata.Type)
+		}
+		if err != nil {
+			badRequestErrors = errors.Join(badRequestErrors, fmt.Errorf("metric %q: %w", ls.Get(labels.MetricName), err))
 		}
 	}
 
-	return otelMetrics, stats, badRequestErrors
+	t.finish()
+	return t.metrics, t.stats, badRequestErrors
 }
 
-// parseJobAndInstance turns the job and instance labels service resource attributes.
-// Following the specification at https://opentelemetry.io/docs/specs/otel/compatibility/prometheus_and_openmetrics/
-func parseJobAndInstance(dest pcommon.Map, job, instance string) {
-	if instance != "" {
-		dest.PutStr("service.instance.id", instance)
+// checkNoHistograms checks that a time series of a type without native histograms has none.
+func checkNoHistograms(ts writev2.TimeSeries) error {
+	if len(ts.Histograms) > 0 {
+		return fmt.Errorf("unexpected native histograms in series of type %q", ts.Metadata.Type)
 	}
-	if job != "" {
-		parts := strings.Split(job, "/")
-		if len(parts) == 2 {
-			dest.PutStr("service.namespace", parts[0])
-			dest.PutStr("service.name", parts[1])
-			return
+	return nil
+}
+
+// validateRefs checks that all the symbol references of the time series are within the symbols table.
+func validateRefs(ts writev2.TimeSeries, numSymbols int) error {
+	if len(ts.LabelsRefs)%2 != 0 {
+		return fmt.Errorf("odd number of label references: %d", len(ts.LabelsRefs))
+	}
+	refs := append([]uint32{ts.Metadata.HelpRef, ts.Metadata.UnitRef}, ts.LabelsRefs...)
+	for _, e := range ts.Exemplars {
+		if len(e.LabelsRefs)%2 != 0 {
+			return fmt.Errorf("odd number of exemplar label references: %d", len(e.LabelsRefs))
 		}
-		dest.PutStr("service.name", job)
+		refs = append(refs, e.LabelsRefs...)
 	}
+	for _, ref := range refs {
+		if int(ref) >= numSymbols {
+			return fmt.Errorf("symbol reference %d out of range, the request has %d symbols", ref, numSymbols)
+		}
+	}
+	return nil
+}
+
+// v2Translation holds the state of the translation of a single remote-write v2 request.
+type v2Translation struct {
+	symbols       []string
+	labelsBuilder labels.ScratchBuilder
+	hashBuf       []byte
+
+	metrics pmetric.Metrics
+	stats   promremote.WriteResponseStats
+
+	// Prometheus Remote-Write can send multiple time series with the same labels in the same request.
+	// Instead of creating a whole new OTLP metric, we just append the new sample to the existing OTLP metric.
+	// This cache is called "intra" because in the future we'll have a "interRequestCache" to cache resourceAttributes
+	// between requests based on the metric "target_info".
+	intraRequestCache map[uint64]pmetric.ResourceMetrics
+	// metricCache holds the OTLP metrics by resource, scope, name, type and unit, which together
+	// identify a metric in OTel.
+	metricCache map[uint64]pmetric.Metric
+	// Classic histograms and summaries are sent as one series per bucket, quantile, sum and count.
+	// Their datapoints are assembled from all the series before being finished.
+	histograms map[uint64]*classicHistogram
+	summaries  map[uint64]*classicSummary
 }
 
-func addCounterDatapoints(_ pmetric.ResourceMetrics, _ labels.Labels, _ writev2.TimeSeries) {
-	// TODO: Implement this function
+func newV2Translation(symbols []string) *v2Translation {
+	return &v2Translation{
+		symbols:           symbols,
+		labelsBuilder:     labels.NewScratchBuilder(0),
+		metrics:           pmetric.NewMetrics(),
+		intraRequestCache: make(map[uint64]pmetric.ResourceMetrics),
+		metricCache:       make(map[uint64]pmetric.Metric),
+		histograms:        make(map[uint64]*classicHistogram),
+		summaries:         make(map[uint64]*classicSummary),
+	}
 }
 
-func addGaugeDatapoints(rm pmetric.ResourceMetrics, ls labels.Labels, ts writev2.TimeSeries) {
-	// TODO: Cache metric name+type+unit and look up cache before creating new empty metric.
-	// In OTel name+type+unit is the unique identifier of a metric and we should not create
-	// a new metric if it already exists.
+// metric returns the OTLP metric the series with the given labels belongs to, creating it and
+// its resource and scope if needed. The returned key identifies the metric within the request.
+func (t *v2Translation) metric(ls labels.Labels, ts writev2.TimeSeries, name string, metricType pmetric.MetricType) (pmetric.Metric, uint64) {
+	job, instance := ls.Get("job"), ls.Get("instance")
+	hashedLabels := xxhash.Sum64String(job + string([]byte{'\xff'}) + instance)
+	rm, ok := t.intraRequestCache[hashedLabels]
+	if !ok {
+		rm = t.metrics.ResourceMetrics().AppendEmpty()
+		parseJobAndInstance(rm.Resource().Attributes(), job, instance)
+		t.intraRequestCache[hashedLabels] = rm
+	}
 
 	scopeName := ls.Get("otel_scope_name")
 	scopeVersion := ls.Get("otel_scope_version")
+	unit := t.symbols[ts.Metadata.UnitRef]
+
+	d := xxhash.New()
+	for _, s := range []string{job, instance, scopeName, scopeVersion, name, metricType.String(), unit} {
+		_, _ = d.WriteString(s)
+		_, _ = d.Write([]byte{'\xff'})
+	}
+	key := d.Sum64()
+	if m, ok := t.metricCache[key]; ok {
+		return m, key
+	}
+
+	m := scopeMetrics(rm, scopeName, scopeVersion).Metrics().AppendEmpty()
+	m.SetName(name)
+	m.SetUnit(unit)
+	m.SetDescription(t.symbols[ts.Metadata.HelpRef])
+	switch metricType {
+	case pmetric.MetricTypeGauge:
+		m.SetEmptyGauge()
+	case pmetric.MetricTypeSum:
+		sum := m.SetEmptySum()
+		sum.SetIsMonotonic(true)
+		sum.SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+	case pmetric.MetricTypeHistogram:
+		m.SetEmptyHistogram().SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+	case pmetric.MetricTypeExponentialHistogram:
+		m.SetEmptyExponentialHistogram().SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+	case pmetric.MetricTypeSummary:
+		m.SetEmptySummary()
+	}
+	t.metricCache[key] = m
+	return m, key
+}
+
+// scopeMetrics returns the ScopeMetrics of the ResourceMetrics with the given name and version,
+// creating it if it's not present yet.
+func scopeMetrics(rm pmetric.ResourceMetrics, scopeName, scopeVersion string) pmetric.ScopeMetrics {
 	// TODO: If the scope version or scope name is empty, get the information from the collector build tags.
 	// More: https://opentelemetry.io/docs/specs/otel/compatibility/prometheus_and_openmetrics/#:~:text=Metrics%20which%20do%20not%20have%20an%20otel_scope_name%20or%20otel_scope_version%20label%20MUST%20be%20assigned%20an%20instrumentation%20scope%20identifying%20the%20entity%20performing%20the%20translation%20from%20Prometheus%20to%20OpenTelemetry%20(e.g.%20the%20collector%E2%80%99s%20prometheus%20receiver)
-
-	// Check if the name and version present in the labels are already present in the ResourceMetrics.
-	// If it is not present, we should create a new ScopeMetrics.
-	// Otherwise, we should append to the existing ScopeMetrics.
 	for j := 0; j < rm.ScopeMetrics().Len(); j++ {
 		scope := rm.ScopeMetrics().At(j)
 		if scopeName == scope.Scope().Name() && scopeVersion == scope.Scope().Version() {
-			addDatapoints(scope.Metrics().AppendEmpty().SetEmptyGauge().DataPoints(), ls, ts)
-			return
+			return scope
 		}
 	}
 
 	scope := rm.ScopeMetrics().AppendEmpty()
 	scope.Scope().SetName(scopeName)
 	scope.Scope().SetVersion(scopeVersion)
-	m := scope.Metrics().AppendEmpty().SetEmptyGauge()
-	addDatapoints(m.DataPoints(), ls, ts)
+	return scope
 }
 
-func addSummaryDatapoints(_ pmetric.ResourceMetrics, _ labels.Labels, _ writev2.TimeSeries) {
-	// TODO: Implement this function
+// parseJobAndInstance turns the job and instance labels service resource attributes.
+// Following the specification at https://opentelemetry.io/docs/specs/otel/compatibility/prometheus_and_openmetrics/
+func parseJobAndInstance(dest pcommon.Map, job, instance string) {
+	if instance != "" {
+		dest.PutStr("service.instance.id", instance)
+	}
+	if job != "" {
+		parts := strings.Split(job, "/")
+		if len(parts) == 2 {
+			dest.PutStr("service.namespace", parts[0])
+			dest.PutStr("service.name", parts[1])
+			return
+		}
+		dest.PutStr("service.name", job)
+	}
 }
 
-func addHistogramDatapoints(_ pmetric.ResourceMetrics, _ labels.Labels, _ writev2.TimeSeries) {
-	// TODO: Implement this function
+func (t *v2Translation) addCounterDatapoints(ls labels.Labels, ts writev2.TimeSeries) {
+	m, _ := t.metric(ls, ts, ls.Get(labels.MetricName), pmetric.MetricTypeSum)
+	t.addNumberDatapoints(m.Sum().DataPoints(), ls, ts)
 }
 
-// addDatapoints adds the labels to the datapoints attributes.
-// TODO: We're still not handling several fields that make a datapoint complete, e.g. StartTimestamp,
-// Timestamp, Value, etc.
-func addDatapoints(datapoints pmetric.NumberDataPointSlice, ls labels.Labels, _ writev2.TimeSeries) {
-	attributes := datapoints.AppendEmpty().Attributes()
+func (t *v2Translation) addGaugeDatapoints(ls labels.Labels, ts writev2.TimeSeries) {
+	m, _ := t.metric(ls, ts, ls.Get(labels.MetricName), pmetric.MetricTypeGauge)
+	t.addNumberDatapoints(m.Gauge().DataPoints(), ls, ts)
+}
 
-	for _, l := range ls {
+// addNumberDatapoints adds a datapoint for each sample of the time series. The exemplars of the
+// time series are added to the datapoint of its most recent sample.
+func (t *v2Translation) addNumberDatapoints(datapoints pmetric.NumberDataPointSlice, ls labels.Labels, ts writev2.TimeSeries) {
+	var (
+		latest          pmetric.NumberDataPoint
+		latestTimestamp int64
+	)
+	for i, sample := range ts.Samples {
+		dp := datapoints.AppendEmpty()
+		putAttributes(dp.Attributes(), ls)
+		dp.SetTimestamp(timestampFromMs(sample.Timestamp))
+		if ts.CreatedTimestamp != 0 {
+			dp.SetStartTimestamp(timestampFromMs(ts.CreatedTimestamp))
+		}
+		dp.SetDoubleValue(sample.Value)
+		if value.IsStaleNaN(sample.Value) {
+			dp.SetFlags(pmetric.DefaultDataPointFlags.WithNoRecordedValue(true))
+		}
+		if i == 0 || sample.Timestamp >= latestTimestamp {
+			latest, latestTimestamp = dp, sample.Timestamp
+		}
+	}
+	t.stats.Samples += len(ts.Samples)
+
+	if len(ts.Samples) > 0 {
+		t.addExemplars(latest.Exemplars(), ts.Exemplars)
+	}
+}
+
+// addExemplars translates the exemplars of a time series, the trace_id and span_id labels become
+// the trace and span IDs of the exemplars.
+func (t *v2Translation) addExemplars(dest pmetric.ExemplarSlice, exemplars []writev2.Exemplar) {
+	for _, e := range exemplars {
+		ex := e.ToExemplar(&t.labelsBuilder, t.symbols)
+		exemplar := dest.AppendEmpty()
+		exemplar.SetDoubleValue(ex.Value)
+		if ex.HasTs {
+			exemplar.SetTimestamp(timestampFromMs(ex.Ts))
+		}
+		ex.Labels.Range(func(l labels.Label) {
+			switch l.Name {
+			case traceIDKey:
+				var traceID pcommon.TraceID
+				if b, err := hex.DecodeString(l.Value); err == nil && len(b) == len(traceID) {
+					copy(traceID[:], b)
+					exemplar.SetTraceID(traceID)
+					return
+				}
+			case spanIDKey:
+				var spanID pcommon.SpanID
+				if b, err := hex.DecodeString(l.Value); err == nil && len(b) == len(spanID) {
+					copy(spanID[:], b)
+					exemplar.SetSpanID(spanID)
+					return
+				}
+			}
+			exemplar.FilteredAttributes().PutStr(l.Name, l.Value)
+		})
+	}
+	t.stats.Exemplars += len(exemplars)
+}
+
+// putAttributes adds the labels to the datapoint attributes, except the ones which are translated to
+// the resource, scope and metric and the given ones.
+func putAttributes(dest pcommon.Map, ls labels.Labels, exclude ...string) {
+	ls.Range(func(l labels.Label) {
 		if l.Name == "instance" || l.Name == "job" || // Become resource attributes "service.name", "service.instance.id" and "service.namespace"
 			l.Name == labels.MetricName || // Becomes metric name
 			l.Name == "otel_scope_name" || l.Name == "otel_scope_version" { // Becomes scope name and version
-			continue
+			return
 		}
-		attributes.PutStr(l.Name, l.Value)
+		for _, name := range exclude {
+			if l.Name == name {
+				return
+			}
+		}
+		dest.PutStr(l.Name, l.Value)
+	})
+}
+
+func timestampFromMs(ms int64) pcommon.Timestamp {
+	return pcommon.Timestamp(ms * int64(time.Millisecond))
+}
+
+// finish completes the datapoints of the classic histograms and summaries.
+func (t *v2Translation) finish() {
+	for _, h := range t.histograms {
+		h.finish()
+	}
+	for _, s := range t.summaries {
+		s.finish()
 	}
 }
@@ -8,16 +8,21 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/gogo/protobuf/proto"
 	"github.com/golang/snappy"
 	promconfig "github.com/prometheus/prometheus/config"
+	"github.com/prometheus/prometheus/prompb"
 	writev2 "github.com/prometheus/prometheus/prompb/io/prometheus/write/v2"
 	"github.com/prometheus/prometheus/storage/remote"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/pmetric"
 	"go.opentelemetry.io/collector/receiver/receivertest"
 
@@ -73,11 +78,13 @@ func setupServer(t *testing.T) {
 
 func TestHandlePRWContentTypeNegotiation(t *testing.T) {
 	setupServer(t)
+	t.Cleanup(http.DefaultClient.CloseIdleConnections)
 
 	for _, tc := range []struct {
 		name         string
 		contentType  string
 		extectedCode int
+		expectStats  bool
 	}{
 		{
 			name:         "no content type",
@@ -92,17 +99,23 @@ func TestHandlePRWContentTypeNegotiation(t *testing.T) {
 		{
 			name:         "x-protobuf/no proto parameter",
 			contentType:  "application/x-protobuf",
-			extectedCode: http.StatusUnsupportedMediaType,
+			extectedCode: http.StatusNoContent,
 		},
 		{
 			name:         "x-protobuf/v1 proto parameter",
 			contentType:  fmt.Sprintf("application/x-protobuf;proto=%s", promconfig.RemoteWriteProtoMsgV1),
-			extectedCode: http.StatusUnsupportedMediaType,
+			extectedCode: http.StatusNoContent,
 		},
 		{
 			name:         "x-protobuf/v2 proto parameter",
 			contentType:  fmt.Sprintf("application/x-protobuf;proto=%s", promconfig.RemoteWriteProtoMsgV2),
 			extectedCode: http.StatusNoContent,
+			expectStats:  true,
+		},
+		{
+			name:         "x-protobuf/unknown proto parameter",
+			contentType:  "application/x-protobuf;proto=io.prometheus.write.v3.Request",
+			extectedCode: http.StatusUnsupportedMediaType,
 		},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
@@ -119,10 +132,11 @@ func TestHandlePRWContentTypeNegotiation(t *testing.T) {
 			req.Header.Set("Content-Type", tc.contentType)
 			req.Header.Set("Content-Encoding", "snappy")
 			resp, err := http.DefaultClient.Do(req)
-			assert.NoError(t, err)
+			require.NoError(t, err)
+			defer resp.Body.Close()
 
 			assert.Equal(t, tc.extectedCode, resp.StatusCode)
-			if tc.extectedCode == http.StatusNoContent { // We went until the end
+			if tc.expectStats { // We went until the end of a v2 request
 				assert.NotEmpty(t, resp.Header.Get("X-Prometheus-Remote-Write-Samples-Written"))
 				assert.NotEmpty(t, resp.Header.Get("X-Prometheus-Remote-Write-Histograms-Written"))
 				assert.NotEmpty(t, resp.Header.Get("X-Prometheus-Remote-Write-Exemplars-Written"))
@@ -186,19 +200,30 @@ func TestTranslateV2(t *testing.T) {
 				sm1 := rm1.ScopeMetrics().AppendEmpty()
 				sm1.Scope().SetName("scope1")
 				sm1.Scope().SetVersion("v1")
-				sm1Attributes := sm1.Metrics().AppendEmpty().SetEmptyGauge().DataPoints().AppendEmpty().Attributes()
-				sm1Attributes.PutStr("d", "e")
-				sm2Attributes := sm1.Metrics().AppendEmpty().SetEmptyGauge().DataPoints().AppendEmpty().Attributes()
-				sm2Attributes.PutStr("d", "e")
+				m1 := sm1.Metrics().AppendEmpty()
+				m1.SetName("test_metric")
+				dps1 := m1.SetEmptyGauge().DataPoints()
+				dp1 := dps1.AppendEmpty()
+				dp1.SetTimestamp(pcommon.Timestamp(1 * int64(time.Millisecond)))
+				dp1.SetDoubleValue(1)
+				dp1.Attributes().PutStr("d", "e")
+				dp2 := dps1.AppendEmpty()
+				dp2.SetTimestamp(pcommon.Timestamp(2 * int64(time.Millisecond)))
+				dp2.SetDoubleValue(2)
+				dp2.Attributes().PutStr("d", "e")
 
 				sm2 := rm1.ScopeMetrics().AppendEmpty()
 				sm2.Scope().SetName("scope2")
 				sm2.Scope().SetVersion("v2")
-				sm3Attributes := sm2.Metrics().AppendEmpty().SetEmptyGauge().DataPoints().AppendEmpty().Attributes()
-				sm3Attributes.PutStr("foo", "bar")
+				m2 := sm2.Metrics().AppendEmpty()
+				m2.SetName("test_metric")
+				dp3 := m2.SetEmptyGauge().DataPoints().AppendEmpty()
+				dp3.SetTimestamp(pcommon.Timestamp(3 * int64(time.Millisecond)))
+				dp3.SetDoubleValue(3)
+				dp3.Attributes().PutStr("foo", "bar")
 				return expected
 			}(),
-			expectedStats: remote.WriteResponseStats{},
+			expectedStats: remote.WriteResponseStats{Samples: 3},
 		},
 		{
 			name: "missing metric name",
@@ -236,27 +261,413 @@ func TestTranslateV2(t *testing.T) {
 				rmAttributes1.PutStr("service.namespace", "service-x")
 				rmAttributes1.PutStr("service.name", "test")
 				rmAttributes1.PutStr("service.instance.id", "107cn001")
-				sm1 := rm1.ScopeMetrics().AppendEmpty()
-				sm1Attributes := sm1.Metrics().AppendEmpty().SetEmptyGauge().DataPoints().AppendEmpty().Attributes()
-				sm1Attributes.PutStr("d", "e")
-				sm1Attributes.PutStr("foo", "bar")
-				// Since we don't check "scope_name" and "scope_version", we end up with duplicated scope metrics for repeated series.
-				// TODO: Properly handle scope metrics.
-				sm2Attributes := sm1.Metrics().AppendEmpty().SetEmptyGauge().DataPoints().AppendEmpty().Attributes()
-				sm2Attributes.PutStr("d", "e")
-				sm2Attributes.PutStr("foo", "bar")
+				m1 := rm1.ScopeMetrics().AppendEmpty().Metrics().AppendEmpty()
+				m1.SetName("test_metric1")
+				dps1 := m1.SetEmptyGauge().DataPoints()
+				dp1 := dps1.AppendEmpty()
+				dp1.SetTimestamp(pcommon.Timestamp(1 * int64(time.Millisecond)))
+				dp1.SetDoubleValue(1)
+				dp1.Attributes().PutStr("d", "e")
+				dp1.Attributes().PutStr("foo", "bar")
+				// Both series have the same labels, their samples are datapoints of the same metric.
+				dp2 := dps1.AppendEmpty()
+				dp2.SetTimestamp(pcommon.Timestamp(2 * int64(time.Millisecond)))
+				dp2.SetDoubleValue(2)
+				dp2.Attributes().PutStr("d", "e")
+				dp2.Attributes().PutStr("foo", "bar")
 
 				rm2 := expected.ResourceMetrics().AppendEmpty()
 				rmAttributes2 := rm2.Resource().Attributes()
 				rmAttributes2.PutStr("service.name", "foo")
 				rmAttributes2.PutStr("service.instance.id", "bar")
-				mAttributes2 := rm2.ScopeMetrics().AppendEmpty().Metrics().AppendEmpty().SetEmptyGauge().DataPoints().AppendEmpty().Attributes()
-				mAttributes2.PutStr("d", "e")
-				mAttributes2.PutStr("foo", "bar")
+				m2 := rm2.ScopeMetrics().AppendEmpty().Metrics().AppendEmpty()
+				m2.SetName("test_metric1")
+				dp3 := m2.SetEmptyGauge().DataPoints().AppendEmpty()
+				dp3.SetTimestamp(pcommon.Timestamp(2 * int64(time.Millisecond)))
+				dp3.SetDoubleValue(2)
+				dp3.Attributes().PutStr("d", "e")
+				dp3.Attributes().PutStr("foo", "bar")
+
+				return expected
+			}(),
+			expectedStats: remote.WriteResponseStats{Samples: 3},
+		},
+		{
+			name: "counter with created timestamp and exemplar",
+			request: &writev2.Request{
+				Symbols: []string{
+					"",
+					"__name__", "http_requests_total",
+					"job", "svc",
+					"instance", "host:80",
+					"code", "200",
+					"Total number of requests",
+					"trace_id", "0102030405060708090a0b0c0d0e0f10",
+					"span_id", "0102030405060708",
+					"client", "a",
+					"{request}",
+				},
+				Timeseries: []writev2.TimeSeries{
+					{
+						Metadata:         writev2.Metadata{Type: writev2.Metadata_METRIC_TYPE_COUNTER, HelpRef: 9, UnitRef: 16},
+						LabelsRefs:       []uint32{1, 2, 3, 4, 5, 6, 7, 8},
+						Samples:          []writev2.Sample{{Value: 10, Timestamp: 1000}, {Value: 15, Timestamp: 2000}},
+						Exemplars:        []writev2.Exemplar{{LabelsRefs: []uint32{10, 11, 12, 13, 14, 15}, Value: 1, Timestamp: 1500}},
+						CreatedTimestamp: 500,
+					},
+				},
+			},
+			expectedMetrics: func() pmetric.Metrics {
+				expected := pmetric.NewMetrics()
+				rm := expected.ResourceMetrics().AppendEmpty()
+				rm.Resource().Attributes().PutStr("service.name", "svc")
+				rm.Resource().Attributes().PutStr("service.instance.id", "host:80")
+				m := rm.ScopeMetrics().AppendEmpty().Metrics().AppendEmpty()
+				m.SetName("http_requests_total")
+				m.SetUnit("{request}")
+				m.SetDescription("Total number of requests")
+				sum := m.SetEmptySum()
+				sum.SetIsMonotonic(true)
+				sum.SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+				dp1 := sum.DataPoints().AppendEmpty()
+				dp1.SetStartTimestamp(pcommon.Timestamp(500 * int64(time.Millisecond)))
+				dp1.SetTimestamp(pcommon.Timestamp(1000 * int64(time.Millisecond)))
+				dp1.SetDoubleValue(10)
+				dp1.Attributes().PutStr("code", "200")
+				dp2 := sum.DataPoints().AppendEmpty()
+				dp2.SetStartTimestamp(pcommon.Timestamp(500 * int64(time.Millisecond)))
+				dp2.SetTimestamp(pcommon.Timestamp(2000 * int64(time.Millisecond)))
+				dp2.SetDoubleValue(15)
+				dp2.Attributes().PutStr("code", "200")
+				exemplar := dp2.Exemplars().AppendEmpty()
+				exemplar.SetTimestamp(pcommon.Timestamp(1500 * int64(time.Millisecond)))
+				exemplar.SetDoubleValue(1)
+				exemplar.SetTraceID(pcommon.TraceID([16]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}))
+				exemplar.SetSpanID(pcommon.SpanID([8]byte{1, 2, 3, 4, 5, 6, 7, 8}))
+				exemplar.FilteredAttributes().PutStr("client", "a")
+				return expected
+			}(),
+			expectedStats: remote.WriteResponseStats{Samples: 2, Exemplars: 1},
+		},
+		{
+			name: "classic histogram",
+			request: &writev2.Request{
+				Symbols: []string{
+					"",
+					"__name__", "latency_seconds_bucket", "latency_seconds_sum", "latency_seconds_count",
+					"le", "0.1", "1", "+Inf",
+					"job", "svc",
+					"s",
+				},
+				Timeseries: []writev2.TimeSeries{
+					{
+						Metadata:         writev2.Metadata{Type: writev2.Metadata_METRIC_TYPE_HISTOGRAM, UnitRef: 11},
+						LabelsRefs:       []uint32{1, 2, 9, 10, 5, 8},
+						Samples:          []writev2.Sample{{Value: 6, Timestamp: 1000}},
+						CreatedTimestamp: 500,
+					},
+					{
+						Metadata:         writev2.Metadata{Type: writev2.Metadata_METRIC_TYPE_HISTOGRAM, UnitRef: 11},
+						LabelsRefs:       []uint32{1, 2, 9, 10, 5, 7},
+						Samples:          []writev2.Sample{{Value: 5, Timestamp: 1000}},
+						CreatedTimestamp: 500,
+					},
+					{
+						Metadata:         writev2.Metadata{Type: writev2.Metadata_METRIC_TYPE_HISTOGRAM, UnitRef: 11},
+						LabelsRefs:       []uint32{1, 2, 9, 10, 5, 6},
+						Samples:          []writev2.Sample{{Value: 2, Timestamp: 1000}},
+						CreatedTimestamp: 500,
+					},
+					{
+						Metadata:         writev2.Metadata{Type: writev2.Metadata_METRIC_TYPE_HISTOGRAM, UnitRef: 11},
+						LabelsRefs:       []uint32{1, 3, 9, 10},
+						Samples:          []writev2.Sample{{Value: 4.5, Timestamp: 1000}},
+						CreatedTimestamp: 500,
+					},
+					{
+						Metadata:         writev2.Metadata{Type: writev2.Metadata_METRIC_TYPE_HISTOGRAM, UnitRef: 11},
+						LabelsRefs:       []uint32{1, 4, 9, 10},
+						Samples:          []writev2.Sample{{Value: 6, Timestamp: 1000}},
+						CreatedTimestamp: 500,
+					},
+				},
+			},
+			expectedMetrics: func() pmetric.Metrics {
+				expected := pmetric.NewMetrics()
+				rm := expected.ResourceMetrics().AppendEmpty()
+				rm.Resource().Attributes().PutStr("service.name", "svc")
+				m := rm.ScopeMetrics().AppendEmpty().Metrics().AppendEmpty()
+				m.SetName("latency_seconds")
+				m.SetUnit("s")
+				histogram := m.SetEmptyHistogram()
+				histogram.SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+				dp := histogram.DataPoints().AppendEmpty()
+				dp.SetStartTimestamp(pcommon.Timestamp(500 * int64(time.Millisecond)))
+				dp.SetTimestamp(pcommon.Timestamp(1000 * int64(time.Millisecond)))
+				dp.SetCount(6)
+				dp.SetSum(4.5)
+				dp.ExplicitBounds().FromRaw([]float64{0.1, 1})
+				dp.BucketCounts().FromRaw([]uint64{2, 3, 1})
+				return expected
+			}(),
+			expectedStats: remote.WriteResponseStats{Samples: 5},
+		},
+		{
+			name: "summary",
+			request: &writev2.Request{
+				Symbols: []string{
+					"",
+					"__name__", "rpc_duration_seconds", "rpc_duration_seconds_sum", "rpc_duration_seconds_count",
+					"quantile", "0.5", "0.99",
+					"job", "svc",
+				},
+				Timeseries: []writev2.TimeSeries{
+					{
+						Metadata:   writev2.Metadata{Type: writev2.Metadata_METRIC_TYPE_SUMMARY},
+						LabelsRefs: []uint32{1, 2, 8, 9, 5, 7},
+						Samples:    []writev2.Sample{{Value: 3, Timestamp: 1000}},
+					},
+					{
+						Metadata:   writev2.Metadata{Type: writev2.Metadata_METRIC_TYPE_SUMMARY},
+						LabelsRefs: []uint32{1, 2, 8, 9, 5, 6},
+						Samples:    []writev2.Sample{{Value: 1, Timestamp: 1000}},
+					},
+					{
+						Metadata:   writev2.Metadata{Type: writev2.Metadata_METRIC_TYPE_SUMMARY},
+						LabelsRefs: []uint32{1, 3, 8, 9},
+						Samples:    []writev2.Sample{{Value: 20, Timestamp: 1000}},
+					},
+					{
+						Metadata:   writev2.Metadata{Type: writev2.Metadata_METRIC_TYPE_SUMMARY},
+						LabelsRefs: []uint32{1, 4, 8, 9},
+						Samples:    []writev2.Sample{{Value: 10, Timestamp: 1000}},
+					},
+				},
+			},
+			expectedMetrics: func() pmetric.Metrics {
+				expected := pmetric.NewMetrics()
+				rm := expected.ResourceMetrics().AppendEmpty()
+				rm.Resource().Attributes().PutStr("service.name", "svc")
+				m := rm.ScopeMetrics().AppendEmpty().Metrics().AppendEmpty()
+				m.SetName("rpc_duration_seconds")
+				dp := m.SetEmptySummary().DataPoints().AppendEmpty()
+				dp.SetTimestamp(pcommon.Timestamp(1000 * int64(time.Millisecond)))
+				dp.SetCount(10)
+				dp.SetSum(20)
+				q1 := dp.QuantileValues().AppendEmpty()
+				q1.SetQuantile(0.5)
+				q1.SetValue(1)
+				q2 := dp.QuantileValues().AppendEmpty()
+				q2.SetQuantile(0.99)
+				q2.SetValue(3)
+				return expected
+			}(),
+			expectedStats: remote.WriteResponseStats{Samples: 4},
+		},
+		{
+			name: "native histograms",
+			request: &writev2.Request{
+				Symbols: []string{
+					"",
+					"__name__", "request_size_bytes", "nhcb_seconds",
+					"job", "svc",
+				},
+				Timeseries: []writev2.TimeSeries{
+					{
+						Metadata:   writev2.Metadata{Type: writev2.Metadata_METRIC_TYPE_HISTOGRAM},
+						LabelsRefs: []uint32{1, 2, 4, 5},
+						Histograms: []writev2.Histogram{
+							{
+								Count:          &writev2.Histogram_CountInt{CountInt: 6},
+								Sum:            30,
+								Schema:         0,
+								ZeroThreshold:  0.001,
+								ZeroCount:      &writev2.Histogram_ZeroCountInt{ZeroCountInt: 1},
+								PositiveSpans:  []writev2.BucketSpan{{Offset: 1, Length: 2}, {Offset: 1, Length: 1}},
+								PositiveDeltas: []int64{1, 1, -1},
+								NegativeSpans:  []writev2.BucketSpan{{Offset: 0, Length: 1}},
+								NegativeDeltas: []int64{1},
+								Timestamp:      1000,
+							},
+						},
+					},
+					{
+						Metadata:   writev2.Metadata{Type: writev2.Metadata_METRIC_TYPE_HISTOGRAM},
+						LabelsRefs: []uint32{1, 3, 4, 5},
+						Histograms: []writev2.Histogram{
+							{
+								Count:          &writev2.Histogram_CountInt{CountInt: 6},
+								Sum:            4.5,
+								Schema:         -53,
+								PositiveSpans:  []writev2.BucketSpan{{Offset: 0, Length: 3}},
+								PositiveDeltas: []int64{2, 1, -2},
+								CustomValues:   []float64{0.1, 1},
+								Timestamp:      1000,
+							},
+						},
+					},
+				},
+			},
+			expectedMetrics: func() pmetric.Metrics {
+				expected := pmetric.NewMetrics()
+				rm := expected.ResourceMetrics().AppendEmpty()
+				rm.Resource().Attributes().PutStr("service.name", "svc")
+				sm := rm.ScopeMetrics().AppendEmpty()
+
+				m1 := sm.Metrics().AppendEmpty()
+				m1.SetName("request_size_bytes")
+				expHistogram := m1.SetEmptyExponentialHistogram()
+				expHistogram.SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+				dp1 := expHistogram.DataPoints().AppendEmpty()
+				dp1.SetTimestamp(pcommon.Timestamp(1000 * int64(time.Millisecond)))
+				dp1.SetScale(0)
+				dp1.SetCount(6)
+				dp1.SetSum(30)
+				dp1.SetZeroThreshold(0.001)
+				dp1.SetZeroCount(1)
+				dp1.Positive().SetOffset(0)
+				dp1.Positive().BucketCounts().FromRaw([]uint64{1, 2, 0, 1})
+				dp1.Negative().SetOffset(-1)
+				dp1.Negative().BucketCounts().FromRaw([]uint64{1})
 
+				m2 := sm.Metrics().AppendEmpty()
+				m2.SetName("nhcb_seconds")
+				histogram := m2.SetEmptyHistogram()
+				histogram.SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+				dp2 := histogram.DataPoints().AppendEmpty()
+				dp2.SetTimestamp(pcommon.Timestamp(1000 * int64(time.Millisecond)))
+				dp2.SetCount(6)
+				dp2.SetSum(4.5)
+				dp2.ExplicitBounds().FromRaw([]float64{0.1, 1})
+				dp2.BucketCounts().FromRaw([]uint64{2, 3, 1})
+				return expected
+			}(),
+			expectedStats: remote.WriteResponseStats{Histograms: 2},
+		},
+		{
+			name: "symbol reference out of range",
+			request: &writev2.Request{
+				Symbols: []string{"", "__name__", "test"},
+				Timeseries: []writev2.TimeSeries{
+					{
+						LabelsRefs: []uint32{1, 5},
+						Samples:    []writev2.Sample{{Value: 1, Timestamp: 1}},
+					},
+				},
+			},
+			expectError: "symbol reference 5 out of range",
+		},
+		{
+			name: "info and stateset metrics",
+			request: &writev2.Request{
+				Symbols: []string{"", "__name__", "build_info", "version", "1.0", "feature_state", "feature", "on"},
+				Timeseries: []writev2.TimeSeries{
+					{
+						Metadata:   writev2.Metadata{Type: writev2.Metadata_METRIC_TYPE_INFO},
+						LabelsRefs: []uint32{1, 2, 3, 4},
+						Samples:    []writev2.Sample{{Value: 1, Timestamp: 1}},
+					},
+					{
+						Metadata:   writev2.Metadata{Type: writev2.Metadata_METRIC_TYPE_STATESET},
+						LabelsRefs: []uint32{1, 5, 6, 7},
+						Samples:    []writev2.Sample{{Value: 1, Timestamp: 1}},
+					},
+				},
+			},
+			expectedMetrics: func() pmetric.Metrics {
+				expected := pmetric.NewMetrics()
+				sm := expected.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty()
+				m1 := sm.Metrics().AppendEmpty()
+				m1.SetName("build_info")
+				dp1 := m1.SetEmptyGauge().DataPoints().AppendEmpty()
+				dp1.SetTimestamp(pcommon.Timestamp(1 * int64(time.Millisecond)))
+				dp1.SetDoubleValue(1)
+				dp1.Attributes().PutStr("version", "1.0")
+				m2 := sm.Metrics().AppendEmpty()
+				m2.SetName("feature_state")
+				dp2 := m2.SetEmptyGauge().DataPoints().AppendEmpty()
+				dp2.SetTimestamp(pcommon.Timestamp(1 * int64(time.Millisecond)))
+				dp2.SetDoubleValue(1)
+				dp2.Attributes().PutStr("feature", "on")
+				return expected
+			}(),
+			expectedStats: remote.WriteResponseStats{Samples: 2},
+		},
+		{
+			name: "gauge histograms are dropped",
+			request: &writev2.Request{
+				Symbols: []string{"", "__name__", "queue_size_bucket", "le", "+Inf"},
+				Timeseries: []writev2.TimeSeries{
+					{
+						Metadata:   writev2.Metadata{Type: writev2.Metadata_METRIC_TYPE_GAUGEHISTOGRAM},
+						LabelsRefs: []uint32{1, 2, 3, 4},
+						Samples:    []writev2.Sample{{Value: 1, Timestamp: 1}},
+					},
+				},
+			},
+			expectedMetrics: pmetric.NewMetrics(),
+		},
+		{
+			name: "native histograms of unknown type",
+			request: &writev2.Request{
+				Symbols: []string{"", "__name__", "request_size_bytes"},
+				Timeseries: []writev2.TimeSeries{
+					{
+						LabelsRefs: []uint32{1, 2},
+						Histograms: []writev2.Histogram{
+							{
+								Count:          &writev2.Histogram_CountInt{CountInt: 2},
+								Sum:            3,
+								PositiveSpans:  []writev2.BucketSpan{{Offset: 1, Length: 1}},
+								PositiveDeltas: []int64{2},
+								Timestamp:      1000,
+							},
+						},
+					},
+				},
+			},
+			expectedMetrics: func() pmetric.Metrics {
+				expected := pmetric.NewMetrics()
+				m := expected.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics().AppendEmpty()
+				m.SetName("request_size_bytes")
+				expHistogram := m.SetEmptyExponentialHistogram()
+				expHistogram.SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+				dp := expHistogram.DataPoints().AppendEmpty()
+				dp.SetTimestamp(pcommon.Timestamp(1000 * int64(time.Millisecond)))
+				dp.SetCount(2)
+				dp.SetSum(3)
+				dp.Positive().BucketCounts().FromRaw([]uint64{2})
 				return expected
 			}(),
-			expectedStats: remote.WriteResponseStats{},
+			expectedStats: remote.WriteResponseStats{Histograms: 1},
+		},
+		{
+			name: "native histograms in gauge series",
+			request: &writev2.Request{
+				Symbols: []string{"", "__name__", "test"},
+				Timeseries: []writev2.TimeSeries{
+					{
+						Metadata:   writev2.Metadata{Type: writev2.Metadata_METRIC_TYPE_GAUGE},
+						LabelsRefs: []uint32{1, 2},
+						Histograms: []writev2.Histogram{{Count: &writev2.Histogram_CountInt{CountInt: 1}, Timestamp: 1}},
+					},
+				},
+			},
+			expectError: `metric "test": unexpected native histograms in series of type "METRIC_TYPE_GAUGE"`,
+		},
+		{
+			name: "unsupported metric type",
+			request: &writev2.Request{
+				Symbols: []string{"", "__name__", "test"},
+				Timeseries: []writev2.TimeSeries{
+					{
+						Metadata:   writev2.Metadata{Type: writev2.Metadata_MetricType(42)},
+						LabelsRefs: []uint32{1, 2},
+						Samples:    []writev2.Sample{{Value: 1, Timestamp: 1}},
+					},
+				},
+			},
+			expectError: `metric "test": unsupported metric type "42"`,
 		},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
@@ -272,3 +683,149 @@ func TestTranslateV2(t *testing.T) {
 		})
 	}
 }
+
+func TestHandlePRWConsumesMetrics(t *testing.T) {
+	sink := new(consumertest.MetricsSink)
+	factory := NewFactory()
+	prwReceiver, err := factory.CreateMetrics(context.Background(), receivertest.NewNopSettings(), factory.CreateDefaultConfig(), sink)
+	require.NoError(t, err)
+
+	body, err := proto.Marshal(writeV2RequestFixture)
+	require.NoError(t, err)
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/write", bytes.NewReader(body))
+	req.Header.Set("Content-Type", fmt.Sprintf("application/x-protobuf;proto=%s", promconfig.RemoteWriteProtoMsgV2))
+	w := httptest.NewRecorder()
+
+	prwReceiver.(*prometheusRemoteWriteReceiver).handlePRW(w, req)
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, "3", w.Header().Get("X-Prometheus-Remote-Write-Samples-Written"))
+	require.Len(t, sink.AllMetrics(), 1)
+	assert.Equal(t, 3, sink.DataPointCount())
+}
+
+func TestSender(t *testing.T) {
+	prwReceiver := setupMetricsReceiver(t)
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/write", nil)
+	req.RemoteAddr = "10.0.0.1:51234"
+	req.Header.Set("X-Prometheus-Replica", "prometheus-0")
+
+	// The senders are identified by their host, whatever their port.
+	assert.Equal(t, "10.0.0.1", prwReceiver.sender(req))
+
+	// The configured header takes precedence over the host.
+	prwReceiver.config.SenderHeader = "X-Prometheus-Replica"
+	assert.Equal(t, "prometheus-0", prwReceiver.sender(req))
+
+	req.Header.Del("X-Prometheus-Replica")
+	assert.Equal(t, "10.0.0.1", prwReceiver.sender(req))
+}
+
+func TestTranslateV1(t *testing.T) {
+	prwReceiver := setupMetricsReceiver(t)
+	ctx := context.Background()
+
+	request := &prompb.WriteRequest{
+		Metadata: []prompb.MetricMetadata{
+			{Type: prompb.MetricMetadata_COUNTER, MetricFamilyName: "http_requests_total", Help: "Total number of requests"},
+			{Type: prompb.MetricMetadata_HISTOGRAM, MetricFamilyName: "latency_seconds", Unit: "s"},
+		},
+		Timeseries: []prompb.TimeSeries{
+			{
+				Labels:    []prompb.Label{{Name: "__name__", Value: "http_requests_total"}, {Name: "job", Value: "service-x/test"}, {Name: "instance", Value: "107cn001"}, {Name: "code", Value: "200"}},
+				Samples:   []prompb.Sample{{Value: 10, Timestamp: 1000}},
+				Exemplars: []prompb.Exemplar{{Labels: []prompb.Label{{Name: "span_id", Value: "0102030405060708"}}, Value: 1, Timestamp: 900}},
+			},
+			{
+				Labels:  []prompb.Label{{Name: "__name__", Value: "latency_seconds_bucket"}, {Name: "job", Value: "service-x/test"}, {Name: "instance", Value: "107cn001"}, {Name: "le", Value: "+Inf"}},
+				Samples: []prompb.Sample{{Value: 3, Timestamp: 1000}},
+			},
+			{
+				Labels:  []prompb.Label{{Name: "__name__", Value: "latency_seconds_sum"}, {Name: "job", Value: "service-x/test"}, {Name: "instance", Value: "107cn001"}},
+				Samples: []prompb.Sample{{Value: 1.5, Timestamp: 1000}},
+			},
+			{
+				Labels:  []prompb.Label{{Name: "__name__", Value: "latency_seconds_count"}, {Name: "job", Value: "service-x/test"}, {Name: "instance", Value: "107cn001"}},
+				Samples: []prompb.Sample{{Value: 3, Timestamp: 1000}},
+			},
+			{
+				// No metadata, translated to a gauge.
+				Labels:  []prompb.Label{{Name: "__name__", Value: "up"}, {Name: "job", Value: "service-x/test"}, {Name: "instance", Value: "107cn001"}},
+				Samples: []prompb.Sample{{Value: 1, Timestamp: 1000}},
+			},
+		},
+	}
+
+	expected := pmetric.NewMetrics()
+	rm := expected.ResourceMetrics().AppendEmpty()
+	rm.Resource().Attributes().PutStr("service.namespace", "service-x")
+	rm.Resource().Attributes().PutStr("service.name", "test")
+	rm.Resource().Attributes().PutStr("service.instance.id", "107cn001")
+	sm := rm.ScopeMetrics().AppendEmpty()
+
+	counter := sm.Metrics().AppendEmpty()
+	counter.SetName("http_requests_total")
+	counter.SetDescription("Total number of requests")
+	sum := counter.SetEmptySum()
+	sum.SetIsMonotonic(true)
+	sum.SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+	sumDp := sum.DataPoints().AppendEmpty()
+	sumDp.SetTimestamp(pcommon.Timestamp(1000 * int64(time.Millisecond)))
+	sumDp.SetDoubleValue(10)
+	sumDp.Attributes().PutStr("code", "200")
+	exemplar := sumDp.Exemplars().AppendEmpty()
+	exemplar.SetTimestamp(pcommon.Timestamp(900 * int64(time.Millisecond)))
+	exemplar.SetDoubleValue(1)
+	exemplar.SetSpanID(pcommon.SpanID([8]byte{1, 2, 3, 4, 5, 6, 7, 8}))
+
+	histogram := sm.Metrics().AppendEmpty()
+	histogram.SetName("latency_seconds")
+	histogram.SetUnit("s")
+	histogram.SetEmptyHistogram().SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+	histogramDp := histogram.Histogram().DataPoints().AppendEmpty()
+	histogramDp.SetTimestamp(pcommon.Timestamp(1000 * int64(time.Millisecond)))
+	histogramDp.SetCount(3)
+	histogramDp.SetSum(1.5)
+	histogramDp.BucketCounts().FromRaw([]uint64{3})
+
+	gauge := sm.Metrics().AppendEmpty()
+	gauge.SetName("up")
+	gaugeDp := gauge.SetEmptyGauge().DataPoints().AppendEmpty()
+	gaugeDp.SetTimestamp(pcommon.Timestamp(1000 * int64(time.Millisecond)))
+	gaugeDp.SetDoubleValue(1)
+
+	metrics, err := prwReceiver.translateV1(ctx, "sender-a", request)
+	require.NoError(t, err)
+	assert.NoError(t, pmetrictest.CompareMetrics(expected, metrics))
+
+	// The metadata received by previous requests of the same sender is used to infer the type of the series.
+	counterRequest := &prompb.WriteRequest{
+		Timeseries: []prompb.TimeSeries{
+			{
+				Labels:  []prompb.Label{{Name: "__name__", Value: "http_requests_total"}},
+				Samples: []prompb.Sample{{Value: 12, Timestamp: 2000}},
+			},
+		},
+	}
+	metrics, err = prwReceiver.translateV1(ctx, "sender-a", counterRequest)
+	require.NoError(t, err)
+	require.Equal(t, 1, metrics.MetricCount())
+	assert.Equal(t, pmetric.MetricTypeSum, metrics.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0).Type())
+
+	metrics, err = prwReceiver.translateV1(ctx, "sender-b", counterRequest)
+	require.NoError(t, err)
+	require.Equal(t, 1, metrics.MetricCount())
+	assert.Equal(t, pmetric.MetricTypeGauge, metrics.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0).Type())
+
+	// Metadata which was not sent again for metadataCacheTTL is evicted.
+	key := metadataKey{sender: "sender-a", family: "http_requests_total"}
+	md, ok := prwReceiver.metadataCache.Get(key)
+	require.True(t, ok)
+	md.updated = md.updated.Add(-metadataCacheTTL - time.Second)
+	prwReceiver.metadataCache.Add(key, md)
+	metrics, err = prwReceiver.translateV1(ctx, "sender-a", counterRequest)
+	require.NoError(t, err)
+	require.Equal(t, 1, metrics.MetricCount())
+	assert.Equal(t, pmetric.MetricTypeGauge, metrics.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0).Type())
+	assert.False(t, prwReceiver.metadataCache.Contains(key))
+}
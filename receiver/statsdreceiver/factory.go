@@ -12,6 +12,7 @@ import (
 	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/receiver"
 
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/sharedcomponent"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/statsdreceiver/internal/metadata"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/statsdreceiver/internal/protocol"
 )
@@ -25,12 +26,22 @@ const (
 
 var defaultTimerHistogramMapping = []protocol.TimerHistogramMapping{{StatsdType: "timer", ObserverType: "gauge"}, {StatsdType: "histogram", ObserverType: "gauge"}, {StatsdType: "distribution", ObserverType: "gauge"}}
 
+// timerHistogramMapping returns the default mapping, which aggregates distributions
+// into exponential histograms when protocol.SeparateDistributionMappingGate is enabled.
+func timerHistogramMapping() []protocol.TimerHistogramMapping {
+	if !protocol.SeparateDistributionMappingGate.IsEnabled() {
+		return defaultTimerHistogramMapping
+	}
+	return []protocol.TimerHistogramMapping{{StatsdType: "timer", ObserverType: "gauge"}, {StatsdType: "histogram", ObserverType: "gauge"}, {StatsdType: "distribution", ObserverType: "histogram"}}
+}
+
 // NewFactory creates a factory for the StatsD receiver.
 func NewFactory() receiver.Factory {
 	return receiver.NewFactory(
 		metadata.Type,
 		createDefaultConfig,
 		receiver.WithMetrics(createMetricsReceiver, metadata.MetricsStability),
+		receiver.WithLogs(createLogsReceiver, metadata.LogsStability),
 	)
 }
 
@@ -43,7 +54,7 @@ func createDefaultConfig() component.Config {
 		AggregationInterval:   defaultAggregationInterval,
 		EnableMetricType:      defaultEnableMetricType,
 		IsMonotonicCounter:    defaultIsMonotonicCounter,
-		TimerHistogramMapping: defaultTimerHistogramMapping,
+		TimerHistogramMapping: timerHistogramMapping(),
 	}
 }
 
@@ -53,6 +64,40 @@ func createMetricsReceiver(
 	cfg component.Config,
 	consumer consumer.Metrics,
 ) (receiver.Metrics, error) {
+	var err error
+	c := cfg.(*Config)
+	r := receivers.GetOrAdd(c, func() (rcv component.Component) {
+		rcv, err = newReceiver(params, *c, consumer)
+		return rcv
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	r.Unwrap().(*statsdReceiver).nextConsumer = consumer
+	return r, nil
+}
+
+func createLogsReceiver(
+	_ context.Context,
+	params receiver.Settings,
+	cfg component.Config,
+	consumer consumer.Logs,
+) (receiver.Logs, error) {
+	var err error
 	c := cfg.(*Config)
-	return newReceiver(params, *c, consumer)
+	r := receivers.GetOrAdd(c, func() (rcv component.Component) {
+		rcv, err = newReceiver(params, *c, nil)
+		return rcv
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	r.Unwrap().(*statsdReceiver).nextLogsConsumer = consumer
+	return r, nil
 }
+
+// The metrics and logs of a receiver are received on the same endpoint, hence
+// the receiver is shared between the pipelines.
+var receivers = sharedcomponent.NewSharedComponents()
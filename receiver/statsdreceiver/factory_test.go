@@ -11,6 +11,9 @@ import (
 	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/consumer/consumertest"
 	"go.opentelemetry.io/collector/receiver/receivertest"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/common/testutil"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/statsdreceiver/internal/protocol"
 )
 
 func TestCreateDefaultConfig(t *testing.T) {
@@ -20,6 +23,15 @@ func TestCreateDefaultConfig(t *testing.T) {
 	assert.NoError(t, componenttest.CheckConfigStruct(cfg))
 }
 
+func TestCreateDefaultConfigDistributionMapping(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	assert.Contains(t, cfg.TimerHistogramMapping, protocol.TimerHistogramMapping{StatsdType: "distribution", ObserverType: "gauge"})
+
+	defer testutil.SetFeatureGateForTest(t, protocol.SeparateDistributionMappingGate, true)()
+	cfg = createDefaultConfig().(*Config)
+	assert.Contains(t, cfg.TimerHistogramMapping, protocol.TimerHistogramMapping{StatsdType: "distribution", ObserverType: "histogram"})
+}
+
 func TestCreateReceiver(t *testing.T) {
 	cfg := createDefaultConfig().(*Config)
 	cfg.NetAddr.Endpoint = "localhost:0" // Endpoint is required, not going to be used here.
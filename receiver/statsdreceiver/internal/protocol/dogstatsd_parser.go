@@ -0,0 +1,217 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package protocol // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/statsdreceiver/internal/protocol"
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	semconv "go.opentelemetry.io/collector/semconv/v1.22.0"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// DogStatsD events and service checks are converted to log records, see
+// https://docs.datadoghq.com/developers/dogstatsd/datagram_shell/?tab=events
+// https://docs.datadoghq.com/developers/dogstatsd/datagram_shell/?tab=servicechecks
+const (
+	eventPrefix        = "_e{"
+	serviceCheckPrefix = "_sc|"
+
+	eventName        = "dogstatsd.event"
+	serviceCheckName = "dogstatsd.service_check"
+
+	attrEventTitle          = "dogstatsd.event.title"
+	attrEventAggregationKey = "dogstatsd.event.aggregation_key"
+	attrEventPriority       = "dogstatsd.event.priority"
+	attrEventSourceTypeName = "dogstatsd.event.source_type_name"
+	attrEventAlertType      = "dogstatsd.event.alert_type"
+	attrServiceCheckName    = "dogstatsd.service_check.name"
+	attrServiceCheckStatus  = "dogstatsd.service_check.status"
+)
+
+var (
+	errEmptyEventTitle       = errors.New("empty event title")
+	errEmptyServiceCheckName = errors.New("empty service check name")
+)
+
+var (
+	eventSeverities = map[string]plog.SeverityNumber{
+		"success": plog.SeverityNumberInfo,
+		"info":    plog.SeverityNumberInfo,
+		"warning": plog.SeverityNumberWarn,
+		"error":   plog.SeverityNumberError,
+	}
+
+	serviceCheckStatuses = []struct {
+		text     string
+		severity plog.SeverityNumber
+	}{
+		{"OK", plog.SeverityNumberInfo},
+		{"WARNING", plog.SeverityNumberWarn},
+		{"CRITICAL", plog.SeverityNumberError},
+		{"UNKNOWN", plog.SeverityNumberUnspecified},
+	}
+)
+
+// parseEventMessage parses an event of the form
+// _e{<title length>,<text length>}:<title>|<text>|d:<timestamp>|h:<hostname>|k:<aggregation key>|p:<priority>|s:<source type>|t:<alert type>|#<tags>
+func parseEventMessage(line string, enableSimpleTags bool, lr plog.LogRecord) error {
+	lengths, rest, found := strings.Cut(strings.TrimPrefix(line, eventPrefix), "}:")
+	if !found {
+		return fmt.Errorf("invalid event format: %s", line)
+	}
+	titleLenStr, textLenStr, found := strings.Cut(lengths, ",")
+	if !found {
+		return fmt.Errorf("invalid event lengths: %s", lengths)
+	}
+	titleLen, err := strconv.Atoi(titleLenStr)
+	if err != nil || titleLen < 0 {
+		return fmt.Errorf("invalid event title length: %s", titleLenStr)
+	}
+	textLen, err := strconv.Atoi(textLenStr)
+	if err != nil || textLen < 0 {
+		return fmt.Errorf("invalid event text length: %s", textLenStr)
+	}
+	// the lengths are in bytes of the UTF-8 encoded title and text
+	if len(rest) < titleLen+1+textLen || rest[titleLen] != '|' {
+		return fmt.Errorf("event title and text do not match their lengths: %s", line)
+	}
+
+	title := rest[:titleLen]
+	if title == "" {
+		return errEmptyEventTitle
+	}
+	text := rest[titleLen+1 : titleLen+1+textLen]
+	rest = rest[titleLen+1+textLen:]
+	if rest != "" && !strings.HasPrefix(rest, "|") {
+		return fmt.Errorf("invalid event format: %s", line)
+	}
+
+	priority := "normal"
+	alertType := "info"
+	var kvs []attribute.KeyValue
+
+	part, additionalParts, _ := strings.Cut(strings.TrimPrefix(rest, "|"), "|")
+	for ; len(part) > 0; part, additionalParts, _ = strings.Cut(additionalParts, "|") {
+		switch {
+		case strings.HasPrefix(part, "d:"):
+			if err = setTimestamp(lr, strings.TrimPrefix(part, "d:")); err != nil {
+				return err
+			}
+		case strings.HasPrefix(part, "h:"):
+			kvs = append(kvs, attribute.String(semconv.AttributeHostName, strings.TrimPrefix(part, "h:")))
+		case strings.HasPrefix(part, "k:"):
+			kvs = append(kvs, attribute.String(attrEventAggregationKey, strings.TrimPrefix(part, "k:")))
+		case strings.HasPrefix(part, "p:"):
+			priority = strings.TrimPrefix(part, "p:")
+			if priority != "normal" && priority != "low" {
+				return fmt.Errorf("invalid event priority: %s", priority)
+			}
+		case strings.HasPrefix(part, "s:"):
+			kvs = append(kvs, attribute.String(attrEventSourceTypeName, strings.TrimPrefix(part, "s:")))
+		case strings.HasPrefix(part, "t:"):
+			alertType = strings.TrimPrefix(part, "t:")
+			if _, ok := eventSeverities[alertType]; !ok {
+				return fmt.Errorf("invalid event alert type: %s", alertType)
+			}
+		case strings.HasPrefix(part, "#"):
+			if kvs, err = appendTags(kvs, strings.TrimPrefix(part, "#"), enableSimpleTags); err != nil {
+				return err
+			}
+		case isOriginField(part):
+			kvs = appendOrigin(kvs, part)
+		default:
+			return fmt.Errorf("unrecognized event part: %s", part)
+		}
+	}
+
+	lr.SetEventName(eventName)
+	lr.SetObservedTimestamp(pcommon.NewTimestampFromTime(timeNowFunc()))
+	lr.SetSeverityNumber(eventSeverities[alertType])
+	lr.SetSeverityText(alertType)
+	lr.Body().SetStr(unescapeNewlines(text))
+	lr.Attributes().PutStr(attrEventTitle, unescapeNewlines(title))
+	lr.Attributes().PutStr(attrEventPriority, priority)
+	lr.Attributes().PutStr(attrEventAlertType, alertType)
+	putAttributes(lr.Attributes(), kvs)
+	return nil
+}
+
+// parseServiceCheckMessage parses a service check of the form
+// _sc|<name>|<status>|d:<timestamp>|h:<hostname>|#<tags>|m:<message>
+func parseServiceCheckMessage(line string, enableSimpleTags bool, lr plog.LogRecord) error {
+	name, rest, _ := strings.Cut(strings.TrimPrefix(line, serviceCheckPrefix), "|")
+	if name == "" {
+		return errEmptyServiceCheckName
+	}
+	statusStr, rest, _ := strings.Cut(rest, "|")
+	status, err := strconv.Atoi(statusStr)
+	if err != nil || status < 0 || status >= len(serviceCheckStatuses) {
+		return fmt.Errorf("invalid service check status: %s", statusStr)
+	}
+
+	var message string
+	var kvs []attribute.KeyValue
+
+	part, additionalParts, _ := strings.Cut(rest, "|")
+	for ; len(part) > 0; part, additionalParts, _ = strings.Cut(additionalParts, "|") {
+		switch {
+		case strings.HasPrefix(part, "d:"):
+			if err = setTimestamp(lr, strings.TrimPrefix(part, "d:")); err != nil {
+				return err
+			}
+		case strings.HasPrefix(part, "h:"):
+			kvs = append(kvs, attribute.String(semconv.AttributeHostName, strings.TrimPrefix(part, "h:")))
+		case strings.HasPrefix(part, "#"):
+			if kvs, err = appendTags(kvs, strings.TrimPrefix(part, "#"), enableSimpleTags); err != nil {
+				return err
+			}
+		case isOriginField(part):
+			kvs = appendOrigin(kvs, part)
+		case strings.HasPrefix(part, "m:"):
+			// the message is the last field and may contain the separator
+			message = strings.TrimPrefix(part, "m:")
+			if additionalParts != "" {
+				message += "|" + additionalParts
+				additionalParts = ""
+			}
+		default:
+			return fmt.Errorf("unrecognized service check part: %s", part)
+		}
+	}
+
+	lr.SetEventName(serviceCheckName)
+	lr.SetObservedTimestamp(pcommon.NewTimestampFromTime(timeNowFunc()))
+	lr.SetSeverityNumber(serviceCheckStatuses[status].severity)
+	lr.SetSeverityText(serviceCheckStatuses[status].text)
+	lr.Body().SetStr(unescapeNewlines(message))
+	lr.Attributes().PutStr(attrServiceCheckName, name)
+	lr.Attributes().PutInt(attrServiceCheckStatus, int64(status))
+	putAttributes(lr.Attributes(), kvs)
+	return nil
+}
+
+func setTimestamp(lr plog.LogRecord, timestampStr string) error {
+	timestampSeconds, err := strconv.ParseUint(timestampStr, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid timestamp: %s", timestampStr)
+	}
+	lr.SetTimestamp(pcommon.Timestamp(timestampSeconds * 1e9))
+	return nil
+}
+
+func putAttributes(attrs pcommon.Map, kvs []attribute.KeyValue) {
+	for _, kv := range kvs {
+		attrs.PutStr(string(kv.Key), kv.Value.AsString())
+	}
+}
+
+// unescapeNewlines restores the new lines escaped by the clients.
+func unescapeNewlines(s string) string {
+	return strings.ReplaceAll(s, `\n`, "\n")
+}
@@ -0,0 +1,237 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package protocol
+
+import (
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+)
+
+func Test_ParseEventMessage(t *testing.T) {
+	timeNowFunc = func() time.Time {
+		return time.Unix(711, 0)
+	}
+
+	tests := []struct {
+		name    string
+		input   string
+		wantLog func(lr plog.LogRecord)
+		err     error
+	}{
+		{
+			name:  "minimal event",
+			input: "_e{5,4}:title|text",
+			wantLog: func(lr plog.LogRecord) {
+				lr.SetEventName("dogstatsd.event")
+				lr.SetSeverityNumber(plog.SeverityNumberInfo)
+				lr.SetSeverityText("info")
+				lr.Body().SetStr("text")
+				lr.Attributes().PutStr("dogstatsd.event.title", "title")
+				lr.Attributes().PutStr("dogstatsd.event.priority", "normal")
+				lr.Attributes().PutStr("dogstatsd.event.alert_type", "info")
+			},
+		},
+		{
+			name:  "event with all fields",
+			input: `_e{10,20}:Deployment|shipped\nversion 1.2|d:1656581400|h:web-01|k:deploy|p:low|s:jenkins|t:error|#env:prod,team:web|c:ci-abc123`,
+			wantLog: func(lr plog.LogRecord) {
+				lr.SetEventName("dogstatsd.event")
+				lr.SetTimestamp(pcommon.Timestamp(1656581400 * 1e9))
+				lr.SetSeverityNumber(plog.SeverityNumberError)
+				lr.SetSeverityText("error")
+				lr.Body().SetStr("shipped\nversion 1.2")
+				lr.Attributes().PutStr("dogstatsd.event.title", "Deployment")
+				lr.Attributes().PutStr("dogstatsd.event.priority", "low")
+				lr.Attributes().PutStr("dogstatsd.event.alert_type", "error")
+				lr.Attributes().PutStr("host.name", "web-01")
+				lr.Attributes().PutStr("dogstatsd.event.aggregation_key", "deploy")
+				lr.Attributes().PutStr("dogstatsd.event.source_type_name", "jenkins")
+				lr.Attributes().PutStr("env", "prod")
+				lr.Attributes().PutStr("team", "web")
+				lr.Attributes().PutStr("container.id", "abc123")
+			},
+		},
+		{
+			name:  "title and text containing the separator",
+			input: "_e{3,3}:a|b|c|d|#k:v",
+			wantLog: func(lr plog.LogRecord) {
+				lr.SetEventName("dogstatsd.event")
+				lr.SetSeverityNumber(plog.SeverityNumberInfo)
+				lr.SetSeverityText("info")
+				lr.Body().SetStr("c|d")
+				lr.Attributes().PutStr("dogstatsd.event.title", "a|b")
+				lr.Attributes().PutStr("dogstatsd.event.priority", "normal")
+				lr.Attributes().PutStr("dogstatsd.event.alert_type", "info")
+				lr.Attributes().PutStr("k", "v")
+			},
+		},
+		{
+			name:  "invalid lengths",
+			input: "_e{5}:title|text",
+			err:   errors.New("invalid event lengths: 5"),
+		},
+		{
+			name:  "lengths not matching",
+			input: "_e{5,10}:title|text",
+			err:   errors.New("event title and text do not match their lengths: _e{5,10}:title|text"),
+		},
+		{
+			name:  "empty title",
+			input: "_e{0,4}:|text",
+			err:   errEmptyEventTitle,
+		},
+		{
+			name:  "invalid alert type",
+			input: "_e{5,4}:title|text|t:fatal",
+			err:   errors.New("invalid event alert type: fatal"),
+		},
+		{
+			name:  "invalid priority",
+			input: "_e{5,4}:title|text|p:high",
+			err:   errors.New("invalid event priority: high"),
+		},
+		{
+			name:  "unrecognized part",
+			input: "_e{5,4}:title|text|x:y",
+			err:   errors.New("unrecognized event part: x:y"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := plog.NewLogRecord()
+			err := parseEventMessage(tt.input, false, got)
+
+			if tt.err != nil {
+				assert.Equal(t, tt.err, err)
+				return
+			}
+			require.NoError(t, err)
+			want := plog.NewLogRecord()
+			want.SetObservedTimestamp(pcommon.NewTimestampFromTime(time.Unix(711, 0)))
+			tt.wantLog(want)
+			assert.Equal(t, want, got)
+		})
+	}
+}
+
+func Test_ParseServiceCheckMessage(t *testing.T) {
+	timeNowFunc = func() time.Time {
+		return time.Unix(711, 0)
+	}
+
+	tests := []struct {
+		name    string
+		input   string
+		wantLog func(lr plog.LogRecord)
+		err     error
+	}{
+		{
+			name:  "minimal service check",
+			input: "_sc|redis.can_connect|0",
+			wantLog: func(lr plog.LogRecord) {
+				lr.SetEventName("dogstatsd.service_check")
+				lr.SetSeverityNumber(plog.SeverityNumberInfo)
+				lr.SetSeverityText("OK")
+				lr.Body().SetStr("")
+				lr.Attributes().PutStr("dogstatsd.service_check.name", "redis.can_connect")
+				lr.Attributes().PutInt("dogstatsd.service_check.status", 0)
+			},
+		},
+		{
+			name:  "service check with all fields",
+			input: `_sc|redis.can_connect|2|d:1656581400|h:cache-01|#env:prod|e:pu-8e1f3a2b|m:connection refused|retrying\nin 5s`,
+			wantLog: func(lr plog.LogRecord) {
+				lr.SetEventName("dogstatsd.service_check")
+				lr.SetTimestamp(pcommon.Timestamp(1656581400 * 1e9))
+				lr.SetSeverityNumber(plog.SeverityNumberError)
+				lr.SetSeverityText("CRITICAL")
+				lr.Body().SetStr("connection refused|retrying\nin 5s")
+				lr.Attributes().PutStr("dogstatsd.service_check.name", "redis.can_connect")
+				lr.Attributes().PutInt("dogstatsd.service_check.status", 2)
+				lr.Attributes().PutStr("host.name", "cache-01")
+				lr.Attributes().PutStr("env", "prod")
+				lr.Attributes().PutStr("k8s.pod.uid", "8e1f3a2b")
+			},
+		},
+		{
+			name:  "empty name",
+			input: "_sc||0",
+			err:   errEmptyServiceCheckName,
+		},
+		{
+			name:  "invalid status",
+			input: "_sc|redis.can_connect|4",
+			err:   errors.New("invalid service check status: 4"),
+		},
+		{
+			name:  "invalid timestamp",
+			input: "_sc|redis.can_connect|1|d:yesterday",
+			err:   errors.New("invalid timestamp: yesterday"),
+		},
+		{
+			name:  "unrecognized part",
+			input: "_sc|redis.can_connect|1|x:y",
+			err:   errors.New("unrecognized service check part: x:y"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := plog.NewLogRecord()
+			err := parseServiceCheckMessage(tt.input, false, got)
+
+			if tt.err != nil {
+				assert.Equal(t, tt.err, err)
+				return
+			}
+			require.NoError(t, err)
+			want := plog.NewLogRecord()
+			want.SetObservedTimestamp(pcommon.NewTimestampFromTime(time.Unix(711, 0)))
+			tt.wantLog(want)
+			assert.Equal(t, want, got)
+		})
+	}
+}
+
+func TestStatsDParser_GetLogs(t *testing.T) {
+	const devVersion = "dev-0.0.1"
+	p := &StatsDParser{
+		BuildInfo: component.BuildInfo{
+			Version: devVersion,
+		},
+	}
+	require.NoError(t, p.Initialize(false, false, false, false, nil))
+
+	addr01, _ := net.ResolveUDPAddr("udp", "1.2.3.4:5678")
+	addr02, _ := net.ResolveUDPAddr("udp", "1.2.3.4:8765")
+	require.NoError(t, p.Aggregate("_e{5,4}:title|text", addr01))
+	require.NoError(t, p.Aggregate("_sc|redis.can_connect|1", addr01))
+	require.NoError(t, p.Aggregate("_sc|redis.can_connect|0", addr02))
+	require.NoError(t, p.Aggregate("test.metric:1|c", addr01))
+	assert.Error(t, p.Aggregate("_sc|redis.can_connect", addr01))
+
+	batches := p.GetLogs()
+	require.Len(t, batches, 2)
+	counts := map[string]int{}
+	for _, batch := range batches {
+		counts[batch.Info.Addr.String()] = batch.Logs.LogRecordCount()
+		scope := batch.Logs.ResourceLogs().At(0).ScopeLogs().At(0).Scope()
+		assert.Equal(t, receiverName, scope.Name())
+		assert.Equal(t, devVersion, scope.Version())
+	}
+	assert.Equal(t, map[string]int{"1.2.3.4:5678": 2, "1.2.3.4:8765": 1}, counts)
+
+	// the events and service checks are not reported as metrics, nor twice
+	assert.Len(t, p.GetMetrics(), 1)
+	assert.Empty(t, p.GetLogs())
+}
@@ -7,6 +7,7 @@ import (
 	"net"
 
 	"go.opentelemetry.io/collector/client"
+	"go.opentelemetry.io/collector/pdata/plog"
 	"go.opentelemetry.io/collector/pdata/pmetric"
 )
 
@@ -14,6 +15,7 @@ import (
 type Parser interface {
 	Initialize(enableMetricType bool, enableSimpleTags bool, isMonotonicCounter bool, enableIPOnlyAggregation bool, sendTimerHistogram []TimerHistogramMapping) error
 	GetMetrics() []BatchMetrics
+	GetLogs() []BatchLogs
 	Aggregate(line string, addr net.Addr) error
 }
 
@@ -21,3 +23,8 @@ type BatchMetrics struct {
 	Info    client.Info
 	Metrics pmetric.Metrics
 }
+
+type BatchLogs struct {
+	Info client.Info
+	Logs plog.Logs
+}
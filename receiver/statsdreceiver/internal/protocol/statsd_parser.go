@@ -14,12 +14,23 @@ import (
 	"github.com/lightstep/go-expohisto/structure"
 	"go.opentelemetry.io/collector/client"
 	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/featuregate"
 	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
 	"go.opentelemetry.io/collector/pdata/pmetric"
 	semconv "go.opentelemetry.io/collector/semconv/v1.22.0"
 	"go.opentelemetry.io/otel/attribute"
 )
 
+// SeparateDistributionMappingGate lets distributions be mapped on their own instead
+// of sharing the histogram mapping, and aggregates them into exponential histograms by default.
+var SeparateDistributionMappingGate = featuregate.GlobalRegistry().MustRegister(
+	"receiver.statsdreceiver.SeparateDistributionMapping",
+	featuregate.StageAlpha,
+	featuregate.WithRegisterDescription("When enabled, the StatsD receiver maps distributions separately"+
+		" from histograms and aggregates them into exponential histograms unless configured otherwise"),
+)
+
 var (
 	errEmptyMetricName  = errors.New("empty metric name")
 	errEmptyMetricValue = errors.New("empty metric value")
@@ -91,7 +102,9 @@ type StatsDParser struct {
 	enableIPOnlyAggregation bool
 	timerEvents             ObserverCategory
 	histogramEvents         ObserverCategory
+	distributionEvents      *ObserverCategory
 	lastIntervalTime        time.Time
+	logsByAddress           map[netAddr]BatchLogs
 	BuildInfo               component.BuildInfo
 }
 
@@ -170,8 +183,10 @@ func (p *StatsDParser) resetState(when time.Time) {
 func (p *StatsDParser) Initialize(enableMetricType bool, enableSimpleTags bool, isMonotonicCounter bool, enableIPOnlyAggregation bool, sendTimerHistogram []TimerHistogramMapping) error {
 	p.resetState(timeNowFunc())
 
+	p.logsByAddress = make(map[netAddr]BatchLogs)
 	p.histogramEvents = defaultObserverCategory
 	p.timerEvents = defaultObserverCategory
+	p.distributionEvents = nil
 	p.enableMetricType = enableMetricType
 	p.enableSimpleTags = enableSimpleTags
 	p.isMonotonicCounter = isMonotonicCounter
@@ -180,20 +195,31 @@ func (p *StatsDParser) Initialize(enableMetricType bool, enableSimpleTags bool,
 	// Note: validation occurs in ("../".Config).validate()
 	for _, eachMap := range sendTimerHistogram {
 		switch eachMap.StatsdType {
-		case HistogramTypeName, DistributionTypeName:
-			p.histogramEvents.method = eachMap.ObserverType
-			p.histogramEvents.histogramConfig = expoHistogramConfig(eachMap.Histogram)
-			p.histogramEvents.summaryPercentiles = eachMap.Summary.Percentiles
+		case HistogramTypeName:
+			p.histogramEvents = newObserverCategory(eachMap)
+		case DistributionTypeName:
+			if !SeparateDistributionMappingGate.IsEnabled() {
+				p.histogramEvents = newObserverCategory(eachMap)
+				continue
+			}
+			category := newObserverCategory(eachMap)
+			p.distributionEvents = &category
 		case TimingTypeName, TimingAltTypeName:
-			p.timerEvents.method = eachMap.ObserverType
-			p.timerEvents.histogramConfig = expoHistogramConfig(eachMap.Histogram)
-			p.timerEvents.summaryPercentiles = eachMap.Summary.Percentiles
+			p.timerEvents = newObserverCategory(eachMap)
 		case CounterTypeName, GaugeTypeName:
 		}
 	}
 	return nil
 }
 
+func newObserverCategory(mapping TimerHistogramMapping) ObserverCategory {
+	return ObserverCategory{
+		method:             mapping.ObserverType,
+		histogramConfig:    expoHistogramConfig(mapping.Histogram),
+		summaryPercentiles: mapping.Summary.Percentiles,
+	}
+}
+
 func expoHistogramConfig(opts HistogramConfig) structure.Config {
 	var r []structure.Option
 	if opts.MaxSize >= structure.MinSize {
@@ -263,6 +289,16 @@ func (p *StatsDParser) GetMetrics() []BatchMetrics {
 	return batchMetrics
 }
 
+// GetLogs gets the events and service checks received since the last call and reset them.
+func (p *StatsDParser) GetLogs() []BatchLogs {
+	batchLogs := make([]BatchLogs, 0, len(p.logsByAddress))
+	for _, batch := range p.logsByAddress {
+		batchLogs = append(batchLogs, batch)
+	}
+	p.logsByAddress = make(map[netAddr]BatchLogs)
+	return batchLogs
+}
+
 func (p *StatsDParser) copyMetricAndScope(rm pmetric.ResourceMetrics, metric pmetric.ScopeMetrics) {
 	ilm := rm.ScopeMetrics().AppendEmpty()
 	metric.CopyTo(ilm)
@@ -278,7 +314,14 @@ var timeNowFunc = time.Now
 
 func (p *StatsDParser) observerCategoryFor(t MetricType) ObserverCategory {
 	switch t {
-	case HistogramType, DistributionType:
+	case HistogramType:
+		return p.histogramEvents
+	case DistributionType:
+		// distributions are aggregated like histograms unless mapped on their own,
+		// which requires SeparateDistributionMappingGate
+		if p.distributionEvents != nil {
+			return *p.distributionEvents
+		}
 		return p.histogramEvents
 	case TimingType:
 		return p.timerEvents
@@ -289,16 +332,27 @@ func (p *StatsDParser) observerCategoryFor(t MetricType) ObserverCategory {
 
 // Aggregate for each metric line.
 func (p *StatsDParser) Aggregate(line string, addr net.Addr) error {
-	parsedMetric, err := parseMessageToMetric(line, p.enableMetricType, p.enableSimpleTags)
-	if err != nil {
-		return err
-	}
-
 	addrKey := newNetAddr(addr)
 	if p.enableIPOnlyAggregation {
 		addrKey = newIPOnlyNetAddr(addr)
 	}
 
+	switch {
+	case strings.HasPrefix(line, eventPrefix):
+		return p.appendLogRecord(addrKey, addr, func(lr plog.LogRecord) error {
+			return parseEventMessage(line, p.enableSimpleTags, lr)
+		})
+	case strings.HasPrefix(line, serviceCheckPrefix):
+		return p.appendLogRecord(addrKey, addr, func(lr plog.LogRecord) error {
+			return parseServiceCheckMessage(line, p.enableSimpleTags, lr)
+		})
+	}
+
+	parsedMetric, err := parseMessageToMetric(line, p.enableMetricType, p.enableSimpleTags)
+	if err != nil {
+		return err
+	}
+
 	instrument, ok := p.instrumentsByAddress[addrKey]
 	if !ok {
 		instrument = newInstruments(addr)
@@ -374,6 +428,28 @@ func (p *StatsDParser) Aggregate(line string, addr net.Addr) error {
 	return nil
 }
 
+func (p *StatsDParser) appendLogRecord(addrKey netAddr, addr net.Addr, parse func(plog.LogRecord) error) error {
+	lr := plog.NewLogRecord()
+	if err := parse(lr); err != nil {
+		return err
+	}
+
+	batch, ok := p.logsByAddress[addrKey]
+	if !ok {
+		batch = BatchLogs{
+			Info: client.Info{
+				Addr: addr,
+			},
+			Logs: plog.NewLogs(),
+		}
+		sl := batch.Logs.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty()
+		p.setVersionAndNameScope(sl.Scope())
+		p.logsByAddress[addrKey] = batch
+	}
+	lr.MoveTo(batch.Logs.ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().AppendEmpty())
+	return nil
+}
+
 func parseMessageToMetric(line string, enableMetricType bool, enableSimpleTags bool) (statsDMetric, error) {
 	result := statsDMetric{}
 
@@ -423,38 +499,13 @@ func parseMessageToMetric(line string, enableMetricType bool, enableSimpleTags b
 
 			result.sampleRate = f
 		case strings.HasPrefix(part, "#"):
-			tagsStr := strings.TrimPrefix(part, "#")
-
-			// handle an empty tag set
-			// where the tags part was still sent (some clients do this)
-			if len(tagsStr) == 0 {
-				continue
-			}
-
-			var tagSet string
-			tagSet, tagsStr, _ = strings.Cut(tagsStr, ",")
-			for ; len(tagSet) > 0; tagSet, tagsStr, _ = strings.Cut(tagsStr, ",") {
-				k, v, _ := strings.Cut(tagSet, ":")
-				if k == "" {
-					return result, fmt.Errorf("invalid tag format: %q", tagSet)
-				}
-
-				// support both simple tags (w/o value) and dimension tags (w/ value).
-				// dogstatsd notably allows simple tags.
-				if v == "" && !enableSimpleTags {
-					return result, fmt.Errorf("invalid tag format: %q", tagSet)
-				}
-
-				kvs = append(kvs, attribute.String(k, v))
-			}
-		case strings.HasPrefix(part, "c:"):
-			// As per DogStatD protocol v1.2:
-			// https://docs.datadoghq.com/developers/dogstatsd/datagram_shell/?tab=metrics#dogstatsd-protocol-v12
-			containerID := strings.TrimPrefix(part, "c:")
-
-			if containerID != "" {
-				kvs = append(kvs, attribute.String(semconv.AttributeContainerID, containerID))
+			var err error
+			kvs, err = appendTags(kvs, strings.TrimPrefix(part, "#"), enableSimpleTags)
+			if err != nil {
+				return result, err
 			}
+		case isOriginField(part):
+			kvs = appendOrigin(kvs, part)
 		case strings.HasPrefix(part, "T"):
 			// As per DogStatD protocol v1.3:
 			// https://docs.datadoghq.com/developers/dogstatsd/datagram_shell/?tab=metrics#dogstatsd-protocol-v13
@@ -493,6 +544,68 @@ func parseMessageToMetric(line string, enableMetricType bool, enableSimpleTags b
 	return result, nil
 }
 
+// appendTags appends the comma separated tags of a message.
+func appendTags(kvs []attribute.KeyValue, tagsStr string, enableSimpleTags bool) ([]attribute.KeyValue, error) {
+	// an empty tag set where the tags part was still sent (some clients do this)
+	// yields no tags
+	var tagSet string
+	tagSet, tagsStr, _ = strings.Cut(tagsStr, ",")
+	for ; len(tagSet) > 0; tagSet, tagsStr, _ = strings.Cut(tagsStr, ",") {
+		k, v, _ := strings.Cut(tagSet, ":")
+		if k == "" {
+			return kvs, fmt.Errorf("invalid tag format: %q", tagSet)
+		}
+
+		// support both simple tags (w/o value) and dimension tags (w/ value).
+		// dogstatsd notably allows simple tags.
+		if v == "" && !enableSimpleTags {
+			return kvs, fmt.Errorf("invalid tag format: %q", tagSet)
+		}
+
+		kvs = append(kvs, attribute.String(k, v))
+	}
+	return kvs, nil
+}
+
+// isOriginField reports whether the message part is one of the DogStatsD origin detection fields.
+func isOriginField(part string) bool {
+	return strings.HasPrefix(part, "c:") || strings.HasPrefix(part, "e:") || strings.HasPrefix(part, "card:")
+}
+
+// appendOrigin appends the container attributes of a DogStatsD origin detection field.
+func appendOrigin(kvs []attribute.KeyValue, part string) []attribute.KeyValue {
+	switch {
+	case strings.HasPrefix(part, "c:"):
+		// As per DogStatD protocol v1.2:
+		// https://docs.datadoghq.com/developers/dogstatsd/datagram_shell/?tab=metrics#dogstatsd-protocol-v12
+		// newer clients prefix the container ID with "ci-", or send the cgroup inode with "in-" which
+		// can only be resolved on the host of the sender.
+		containerID := strings.TrimPrefix(part, "c:")
+		if strings.HasPrefix(containerID, "in-") {
+			return kvs
+		}
+		containerID = strings.TrimPrefix(containerID, "ci-")
+
+		if containerID != "" {
+			kvs = append(kvs, attribute.String(semconv.AttributeContainerID, containerID))
+		}
+	case strings.HasPrefix(part, "e:"):
+		// As per DogStatD protocol v1.5, the external data injected by the Datadog admission controller, e.g.
+		// e:it-false,cn-nginx,pu-9a3b1c0e-2f4d-4b5a-8c6e-7d8f9a0b1c2d
+		item, externalData, _ := strings.Cut(strings.TrimPrefix(part, "e:"), ",")
+		for ; len(item) > 0; item, externalData, _ = strings.Cut(externalData, ",") {
+			switch {
+			case strings.HasPrefix(item, "cn-"):
+				kvs = append(kvs, attribute.String(semconv.AttributeK8SContainerName, strings.TrimPrefix(item, "cn-")))
+			case strings.HasPrefix(item, "pu-"):
+				kvs = append(kvs, attribute.String(semconv.AttributeK8SPodUID, strings.TrimPrefix(item, "pu-")))
+			}
+		}
+	}
+	// the tags cardinality requested from the Datadog agent does not apply
+	return kvs
+}
+
 type netAddr struct {
 	Network string
 	String  string
@@ -17,6 +17,7 @@ import (
 	semconv "go.opentelemetry.io/collector/semconv/v1.22.0"
 	"go.opentelemetry.io/otel/attribute"
 
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/common/testutil"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/coreinternal/metricstestutil"
 )
 
@@ -127,6 +128,48 @@ func Test_ParseMessageToMetric(t *testing.T) {
 				0,
 			),
 		},
+		{
+			name:  "counter metric with prefixed container ID",
+			input: "test.metric:42|c|#key:value|c:ci-abc123",
+			wantMetric: testStatsDMetric(
+				"test.metric",
+				42,
+				false,
+				"c",
+				0,
+				[]string{"key", semconv.AttributeContainerID},
+				[]string{"value", "abc123"},
+				0,
+			),
+		},
+		{
+			name:  "counter metric with cgroup inode and cardinality",
+			input: "test.metric:42|c|#key:value|c:in-12345|card:low",
+			wantMetric: testStatsDMetric(
+				"test.metric",
+				42,
+				false,
+				"c",
+				0,
+				[]string{"key"},
+				[]string{"value"},
+				0,
+			),
+		},
+		{
+			name:  "counter metric with external data",
+			input: "test.metric:42|c|e:it-false,cn-nginx,pu-8e1f3a2b",
+			wantMetric: testStatsDMetric(
+				"test.metric",
+				42,
+				false,
+				"c",
+				0,
+				[]string{semconv.AttributeK8SContainerName, semconv.AttributeK8SPodUID},
+				[]string{"nginx", "8e1f3a2b"},
+				0,
+			),
+		},
 		{
 			name:  "counter metric with timestamp",
 			input: "test.metric:42|c|T1656581400",
@@ -1635,6 +1678,81 @@ func TestStatsDParser_Mappings(t *testing.T) {
 	}
 }
 
+func TestStatsDParser_DistributionMapping(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		separate bool
+		mapping  []TimerHistogramMapping
+		expect   map[string]string
+	}{
+		{
+			name: "histogram-mapping-applies",
+			mapping: []TimerHistogramMapping{
+				{StatsdType: "histogram", ObserverType: "summary"},
+			},
+			expect: map[string]string{
+				"H": "Summary",
+				"D": "Summary",
+			},
+		},
+		{
+			name: "distribution-mapping-shared-with-histograms",
+			mapping: []TimerHistogramMapping{
+				{StatsdType: "histogram", ObserverType: "gauge"},
+				{StatsdType: "distribution", ObserverType: "histogram"},
+			},
+			expect: map[string]string{
+				"H": "ExponentialHistogram",
+				"D": "ExponentialHistogram",
+			},
+		},
+		{
+			name:     "histogram-mapping-applies-with-gate",
+			separate: true,
+			mapping: []TimerHistogramMapping{
+				{StatsdType: "histogram", ObserverType: "summary"},
+			},
+			expect: map[string]string{
+				"H": "Summary",
+				"D": "Summary",
+			},
+		},
+		{
+			name:     "distribution-mapped-on-its-own-with-gate",
+			separate: true,
+			mapping: []TimerHistogramMapping{
+				{StatsdType: "histogram", ObserverType: "gauge"},
+				{StatsdType: "distribution", ObserverType: "histogram"},
+			},
+			expect: map[string]string{
+				"H": "Gauge",
+				"D": "ExponentialHistogram",
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			defer testutil.SetFeatureGateForTest(t, SeparateDistributionMappingGate, tc.separate)()
+
+			p := &StatsDParser{}
+			assert.NoError(t, p.Initialize(false, false, false, false, tc.mapping))
+
+			addr, _ := net.ResolveUDPAddr("udp", "1.2.3.4:5678")
+			assert.NoError(t, p.Aggregate("H:10|h", addr))
+			assert.NoError(t, p.Aggregate("D:10|d", addr))
+
+			typeNames := map[string]string{}
+			ilm := p.GetMetrics()[0].Metrics.ResourceMetrics().At(0).ScopeMetrics()
+			for i := 0; i < ilm.Len(); i++ {
+				ilms := ilm.At(i).Metrics()
+				for j := 0; j < ilms.Len(); j++ {
+					typeNames[ilms.At(j).Name()] = ilms.At(j).Type().String()
+				}
+			}
+			assert.Equal(t, tc.expect, typeNames)
+		})
+	}
+}
+
 func TestStatsDParser_ScopeIsIncluded(t *testing.T) {
 	const devVersion = "dev-0.0.1"
 
@@ -4,6 +4,7 @@
 package client // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/statsdreceiver/internal/transport/client"
 
 import (
+	"encoding/binary"
 	"fmt"
 	"io"
 	"net"
@@ -45,7 +46,7 @@ func (s *StatsD) connect() error {
 		if err != nil {
 			return err
 		}
-	case "tcp":
+	case "tcp", "unix":
 		var err error
 		s.conn, err = net.Dial(s.transport, s.address)
 		if err != nil {
@@ -79,7 +80,22 @@ func (s *StatsD) Disconnect() error {
 
 // SendMetric sends the input metric to the StatsD connection.
 func (s *StatsD) SendMetric(metric Metric) error {
-	_, err := io.Copy(s.conn, strings.NewReader(metric.String()))
+	return s.send(metric.String())
+}
+
+// SendRaw sends the input message as is to the StatsD connection.
+func (s *StatsD) SendRaw(message string) error {
+	return s.send(message)
+}
+
+func (s *StatsD) send(message string) error {
+	if s.transport == "unix" {
+		// DogStatsD stream mode prefixes each datagram with its length
+		if err := binary.Write(s.conn, binary.LittleEndian, uint32(len(message))); err != nil {
+			return fmt.Errorf("send metric on test client: %w", err)
+		}
+	}
+	_, err := io.Copy(s.conn, strings.NewReader(message))
 	if err != nil {
 		return fmt.Errorf("send metric on test client: %w", err)
 	}
@@ -6,6 +6,7 @@ package transport
 import (
 	"io"
 	"net"
+	"path/filepath"
 	"runtime"
 	"sync"
 	"testing"
@@ -13,7 +14,6 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
-	"go.opentelemetry.io/collector/consumer/consumertest"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/common/testutil"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/statsdreceiver/internal/transport/client"
@@ -41,10 +41,22 @@ func Test_Server_ListenAndServe(t *testing.T) {
 			buildServerFn:     NewTCPServer,
 			buildClientFn:     client.NewStatsD,
 		},
+		{
+			name:      "unix",
+			transport: UDSStream,
+			getFreeEndpointFn: func(tb testing.TB, _ string) string {
+				return filepath.Join(tb.TempDir(), "statsd.sock")
+			},
+			buildServerFn: NewTCPServer,
+			buildClientFn: client.NewStatsD,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
+			if runtime.GOOS == "windows" && tt.transport == UDSStream {
+				t.Skip("skipping UDS test on windows")
+			}
 			addr := tt.getFreeEndpointFn(t, tt.name)
 			testFreeEndpoint(t, tt.name, addr)
 
@@ -52,8 +64,6 @@ func Test_Server_ListenAndServe(t *testing.T) {
 			require.NoError(t, err)
 			require.NotNil(t, srv)
 
-			mc := new(consumertest.MetricsSink)
-			require.NoError(t, err)
 			mr := NewMockReporter(1)
 			transferChan := make(chan Metric, 10)
 
@@ -61,7 +71,7 @@ func Test_Server_ListenAndServe(t *testing.T) {
 			wgListenAndServe.Add(1)
 			go func() {
 				defer wgListenAndServe.Done()
-				assert.Error(t, srv.ListenAndServe(mc, mr, transferChan))
+				assert.Error(t, srv.ListenAndServe(mr, transferChan))
 			}()
 
 			runtime.Gosched()
@@ -4,15 +4,15 @@
 package transport // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/statsdreceiver/internal/transport"
 
 import (
+	"bufio"
 	"bytes"
+	"encoding/binary"
 	"errors"
 	"fmt"
 	"io"
 	"net"
 	"strings"
 	"sync"
-
-	"go.opentelemetry.io/collector/consumer"
 )
 
 var errTCPServerDone = errors.New("server stopped")
@@ -28,7 +28,7 @@ type tcpServer struct {
 // Ensure that Server is implemented on TCP Server.
 var _ Server = (*tcpServer)(nil)
 
-// NewTCPServer creates a transport.Server using TCP as its transport.
+// NewTCPServer creates a transport.Server using TCP, or Unix domain sockets in stream mode, as its transport.
 func NewTCPServer(transport Transport, address string) (Server, error) {
 	var tsrv tcpServer
 	var err error
@@ -48,8 +48,8 @@ func NewTCPServer(transport Transport, address string) (Server, error) {
 }
 
 // ListenAndServe starts the server ready to receive metrics.
-func (t *tcpServer) ListenAndServe(nextConsumer consumer.Metrics, reporter Reporter, transferChan chan<- Metric) error {
-	if nextConsumer == nil || reporter == nil {
+func (t *tcpServer) ListenAndServe(reporter Reporter, transferChan chan<- Metric) error {
+	if reporter == nil {
 		return errNilListenAndServeParameters
 	}
 
@@ -70,7 +70,11 @@ LOOP:
 		select {
 		case conn := <-connChan:
 			t.wg.Add(1)
-			go t.handleConn(conn, transferChan)
+			if t.transport == UDSStream {
+				go t.handleLengthPrefixedConn(conn, transferChan)
+			} else {
+				go t.handleConn(conn, transferChan)
+			}
 		case <-t.stopChan:
 			break LOOP
 		}
@@ -108,6 +112,39 @@ func (t *tcpServer) handleConn(c net.Conn, transferChan chan<- Metric) {
 	}
 }
 
+// handleLengthPrefixedConn is helper that reads the DogStatsD stream framing, where each datagram is
+// prefixed by its length as a little endian uint32, and split the datagrams line by line to be parsed upstream.
+func (t *tcpServer) handleLengthPrefixedConn(c net.Conn, transferChan chan<- Metric) {
+	defer t.wg.Done()
+	reader := bufio.NewReader(c)
+	var payload []byte
+	for {
+		var length uint32
+		err := binary.Read(reader, binary.LittleEndian, &length)
+		if err == nil {
+			if int(length) > cap(payload) {
+				payload = make([]byte, length)
+			}
+			payload = payload[:length]
+			_, err = io.ReadFull(reader, payload)
+		}
+		if err != nil {
+			if !errors.Is(err, io.EOF) {
+				t.reporter.OnDebugf("%s transport (%s) Error reading payload: %v", t.transport, c.LocalAddr(), err)
+			}
+			return
+		}
+
+		splitPayload := NewSplitBytes(payload, '\n')
+		for splitPayload.Next() {
+			line := strings.TrimSpace(string(splitPayload.Chunk()))
+			if line != "" {
+				transferChan <- Metric{line, c.LocalAddr()}
+			}
+		}
+	}
+}
+
 // Close closes the server.
 func (t *tcpServer) Close() error {
 	close(t.stopChan)
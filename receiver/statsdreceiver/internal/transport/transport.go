@@ -22,6 +22,8 @@ const (
 	TCP4 Transport = "tcp4"
 	TCP6 Transport = "tcp6"
 	UDS  Transport = "unixgram"
+	// UDSStream is the stream mode of DogStatsD over Unix domain sockets.
+	UDSStream Transport = "unix"
 )
 
 // NewTransport creates a Transport based on the transport string or returns an empty Transport.
@@ -32,7 +34,7 @@ func NewTransport(ts string) Transport {
 		return trans
 	case TCP, TCP4, TCP6:
 		return trans
-	case UDS:
+	case UDS, UDSStream:
 		return trans
 	}
 	return Transport("")
@@ -41,7 +43,7 @@ func NewTransport(ts string) Transport {
 // String casts the transport to a String if the Transport is supported. Return an empty Transport overwise.
 func (trans Transport) String() string {
 	switch trans {
-	case UDP, UDP4, UDP6, TCP, TCP4, TCP6, UDS:
+	case UDP, UDP4, UDP6, TCP, TCP4, TCP6, UDS, UDSStream:
 		return string(trans)
 	}
 	return ""
@@ -59,7 +61,7 @@ func (trans Transport) IsPacketTransport() bool {
 // IsStreamTransport returns true if the transport is stream based.
 func (trans Transport) IsStreamTransport() bool {
 	switch trans {
-	case TCP, TCP4, TCP6:
+	case TCP, TCP4, TCP6, UDSStream:
 		return true
 	}
 	return false
@@ -25,19 +25,24 @@ import (
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/statsdreceiver/internal/transport"
 )
 
-var _ receiver.Metrics = (*statsdReceiver)(nil)
+var (
+	_ receiver.Metrics = (*statsdReceiver)(nil)
+	_ receiver.Logs    = (*statsdReceiver)(nil)
+)
 
-// statsdReceiver implements the receiver.Metrics for StatsD protocol.
+// statsdReceiver implements the receiver.Metrics for StatsD protocol, and the
+// receiver.Logs for the DogStatsD events and service checks.
 type statsdReceiver struct {
 	settings receiver.Settings
 	config   *Config
 
-	server       transport.Server
-	reporter     *reporter
-	obsrecv      *receiverhelper.ObsReport
-	parser       protocol.Parser
-	nextConsumer consumer.Metrics
-	cancel       context.CancelFunc
+	server           transport.Server
+	reporter         *reporter
+	obsrecv          *receiverhelper.ObsReport
+	parser           protocol.Parser
+	nextConsumer     consumer.Metrics
+	nextLogsConsumer consumer.Logs
+	cancel           context.CancelFunc
 }
 
 // newReceiver creates the StatsD receiver with the given parameters.
@@ -49,7 +54,7 @@ func newReceiver(
 	trans := transport.NewTransport(strings.ToLower(string(config.NetAddr.Transport)))
 
 	if config.NetAddr.Endpoint == "" {
-		if trans == transport.UDS {
+		if trans == transport.UDS || trans == transport.UDSStream {
 			config.NetAddr.Endpoint = "/var/run/statsd-receiver.sock"
 		} else {
 			config.NetAddr.Endpoint = "localhost:8125"
@@ -89,7 +94,7 @@ func buildTransportServer(config Config) (transport.Server, error) {
 	switch trans {
 	case transport.UDP, transport.UDP4, transport.UDP6:
 		return transport.NewUDPServer(trans, config.NetAddr.Endpoint)
-	case transport.TCP, transport.TCP4, transport.TCP6:
+	case transport.TCP, transport.TCP4, transport.TCP6, transport.UDSStream:
 		return transport.NewTCPServer(trans, config.NetAddr.Endpoint)
 	case transport.UDS:
 		return transport.NewUDSServer(trans, config.NetAddr.Endpoint)
@@ -119,7 +124,7 @@ func (r *statsdReceiver) Start(ctx context.Context, host component.Host) error {
 		return err
 	}
 	go func() {
-		if err := r.server.ListenAndServe(r.nextConsumer, r.reporter, transferChan); err != nil {
+		if err := r.server.ListenAndServe(r.reporter, transferChan); err != nil {
 			if !errors.Is(err, net.ErrClosed) {
 				componentstatus.ReportStatus(host, componentstatus.NewFatalErrorEvent(err))
 			}
@@ -130,7 +135,12 @@ func (r *statsdReceiver) Start(ctx context.Context, host component.Host) error {
 		for {
 			select {
 			case <-ticker.C:
+				r.flushLogs(ctx)
 				batchMetrics := r.parser.GetMetrics()
+				if r.nextConsumer == nil {
+					// only the events and service checks are consumed
+					continue
+				}
 				for _, batch := range batchMetrics {
 					batchCtx := client.NewContext(ctx, batch.Info)
 					numPoints := batch.Metrics.DataPointCount()
@@ -181,3 +191,21 @@ func (r *statsdReceiver) Shutdown(context.Context) error {
 func (r *statsdReceiver) Flush(ctx context.Context, metrics pmetric.Metrics, nextConsumer consumer.Metrics) error {
 	return nextConsumer.ConsumeMetrics(ctx, metrics)
 }
+
+// flushLogs sends the DogStatsD events and service checks received during the aggregation interval.
+func (r *statsdReceiver) flushLogs(ctx context.Context) {
+	batchLogs := r.parser.GetLogs()
+	if r.nextLogsConsumer == nil {
+		return
+	}
+	for _, batch := range batchLogs {
+		batchCtx := client.NewContext(ctx, batch.Info)
+		numRecords := batch.Logs.LogRecordCount()
+		flushCtx := r.obsrecv.StartLogsOp(batchCtx)
+		err := r.nextLogsConsumer.ConsumeLogs(flushCtx, batch.Logs)
+		if err != nil {
+			r.reporter.OnDebugf("Error flushing logs", zap.Error(err))
+		}
+		r.obsrecv.EndLogsOp(flushCtx, metadata.Type.String(), numRecords, err)
+	}
+}
@@ -181,3 +181,35 @@ func Test_statsdreceiver_EndToEnd(t *testing.T) {
 		})
 	}
 }
+
+func Test_statsdreceiver_SharedMetricsAndLogs(t *testing.T) {
+	addr := testutil.GetAvailableLocalNetworkAddress(t, "udp")
+	cfg := createDefaultConfig().(*Config)
+	cfg.NetAddr.Endpoint = addr
+	cfg.AggregationInterval = time.Second
+
+	factory := NewFactory()
+	metricsSink := new(consumertest.MetricsSink)
+	logsSink := new(consumertest.LogsSink)
+	mr, err := factory.CreateMetrics(context.Background(), receivertest.NewNopSettings(), cfg, metricsSink)
+	require.NoError(t, err)
+	lr, err := factory.CreateLogs(context.Background(), receivertest.NewNopSettings(), cfg, logsSink)
+	require.NoError(t, err)
+	assert.Same(t, mr, lr)
+
+	require.NoError(t, mr.Start(context.Background(), componenttest.NewNopHost()))
+	require.NoError(t, lr.Start(context.Background(), componenttest.NewNopHost()))
+	defer func() {
+		assert.NoError(t, mr.Shutdown(context.Background()))
+		assert.NoError(t, lr.Shutdown(context.Background()))
+	}()
+
+	statsdClient, err := client.NewStatsD("udp", addr)
+	require.NoError(t, err)
+	require.NoError(t, statsdClient.SendMetric(client.Metric{Name: "test.metric", Value: "42", Type: "c"}))
+	require.NoError(t, statsdClient.SendRaw("_e{6,7}:deploy|shipped|t:success\n_sc|redis.can_connect|0\n"))
+
+	require.Eventually(t, func() bool {
+		return logsSink.LogRecordCount() == 2 && metricsSink.DataPointCount() == 1
+	}, 10*time.Second, 100*time.Millisecond)
+}